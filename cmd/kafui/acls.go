@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared/aclcsv"
+	"github.com/spf13/cobra"
+)
+
+// aclDriftExitCode is the exit code of `kafui acls plan` when the cluster
+// differs from the CSV (1 is reserved for errors).
+const aclDriftExitCode = 2
+
+// newACLsCommand adds `kafui acls export|plan|apply`: the TUI's ACL CSV
+// export and declarative sync (pkg/ui/shared/aclcsv) for scripts and CI.
+func newACLsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "acls",
+		Short: "Export ACLs as CSV and sync the cluster from a CSV file",
+	}
+	cmd.AddCommand(newACLsExportCommand(), newACLsPlanCommand(), newACLsApplyCommand())
+	return cmd
+}
+
+func newACLsExportCommand() *cobra.Command {
+	var (
+		useMock bool
+		output  string
+	)
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the cluster's ACL bindings as CSV (stdout or --output)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			w := io.Writer(os.Stdout)
+			if output != "" && output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			return runACLsExport(w, ds)
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write (default stdout)")
+	return cmd
+}
+
+func newACLsPlanCommand() *cobra.Command {
+	var useMock, deleteExtra bool
+	cmd := &cobra.Command{
+		Use:   "plan <file>",
+		Short: "Show the ACL changes needed to match a CSV file; exit 2 on drift",
+		Long: "Compare the cluster's ACL bindings with a CSV file and print the difference.\n" +
+			"Exits 0 when in sync, 2 when there is drift and 1 on error. Bindings missing from\n" +
+			"the file only count as drift with --delete-extra (matching `acls apply`).",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			drift, err := runACLsPlan(os.Stdout, ds, args[0], deleteExtra)
+			if err != nil {
+				return err
+			}
+			if drift {
+				os.Exit(aclDriftExitCode)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	cmd.Flags().BoolVar(&deleteExtra, "delete-extra", false, "treat bindings missing from the file as drift to delete")
+	return cmd
+}
+
+func newACLsApplyCommand() *cobra.Command {
+	var useMock, deleteExtra bool
+	cmd := &cobra.Command{
+		Use:   "apply <file>",
+		Short: "Create the ACL bindings of a CSV file missing on the cluster",
+		Long: "Create every binding of the CSV file that the cluster lacks. With --delete-extra,\n" +
+			"bindings on the cluster that the file does not list are deleted as well.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runACLsApply(os.Stdout, ds, args[0], deleteExtra)
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	cmd.Flags().BoolVar(&deleteExtra, "delete-extra", false, "also delete bindings that the file does not list")
+	return cmd
+}
+
+func runACLsExport(w io.Writer, ds api.KafkaDataSource) error {
+	entries, err := ds.GetACLs()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, aclcsv.Marshal(entries))
+	return err
+}
+
+// planACLs parses the CSV at path and computes the sync plan. Without
+// deleteExtra the extra bindings are moved out of the plan and returned
+// separately so they are reported but never deleted.
+func planACLs(ds api.KafkaDataSource, path string, deleteExtra bool) (plan aclcsv.SyncPlan, kept []api.ACLEntry, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return plan, nil, err
+	}
+	desired, err := aclcsv.Parse(string(data))
+	if err != nil {
+		return plan, nil, err
+	}
+	if plan, err = aclcsv.SyncACLs(ds, desired); err != nil {
+		return plan, nil, err
+	}
+	if !deleteExtra {
+		kept, plan.ToDelete = plan.ToDelete, nil
+	}
+	return plan, kept, nil
+}
+
+// runACLsPlan prints the plan and reports whether the cluster drifted.
+func runACLsPlan(w io.Writer, ds api.KafkaDataSource, path string, deleteExtra bool) (bool, error) {
+	plan, kept, err := planACLs(ds, path, deleteExtra)
+	if err != nil {
+		return false, err
+	}
+	writeACLPlan(w, plan, kept)
+	return !plan.Empty(), nil
+}
+
+// runACLsApply prints the plan and applies it.
+func runACLsApply(w io.Writer, ds api.KafkaDataSource, path string, deleteExtra bool) error {
+	plan, kept, err := planACLs(ds, path, deleteExtra)
+	if err != nil {
+		return err
+	}
+	writeACLPlan(w, plan, kept)
+	if plan.Empty() {
+		return nil
+	}
+	if err := plan.Apply(ds); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Apply complete: %d created, %d deleted.\n", len(plan.ToCreate), len(plan.ToDelete))
+	return nil
+}
+
+// writeACLPlan renders one binding per line: "+" create, "-" delete and
+// "=" extra binding kept on the cluster.
+func writeACLPlan(w io.Writer, plan aclcsv.SyncPlan, kept []api.ACLEntry) {
+	for _, e := range plan.ToCreate {
+		fmt.Fprintf(w, "+ %s\n", formatACLBinding(e))
+	}
+	for _, e := range plan.ToDelete {
+		fmt.Fprintf(w, "- %s\n", formatACLBinding(e))
+	}
+	for _, e := range kept {
+		fmt.Fprintf(w, "= %s (not in file, kept; use --delete-extra to delete)\n", formatACLBinding(e))
+	}
+	if plan.Empty() {
+		fmt.Fprintln(w, "No changes. ACLs match the file.")
+		return
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to delete.\n", len(plan.ToCreate), len(plan.ToDelete))
+}
+
+// formatACLBinding is the plan line of a binding, as in the TUI sync preview.
+func formatACLBinding(e api.ACLEntry) string {
+	host := e.Host
+	if host == "" {
+		host = "*"
+	}
+	return fmt.Sprintf("%s %s:%s [%s] %s %s from %s", e.Principal, e.ResourceType, e.ResourceName, e.PatternType, e.Operation, e.Permission, host)
+}
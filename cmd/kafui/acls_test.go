@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestACLsExportPlanApply_Mock(t *testing.T) {
+	ds := newGetMockDS()
+	var exported bytes.Buffer
+	if err := runACLsExport(&exported, ds); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(exported.String()), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("expected header and at least two bindings, got:\n%s", exported.String())
+	}
+
+	// Drop the first binding and add a new one.
+	desired := append([]string{lines[0]}, lines[2:]...)
+	desired = append(desired, "User:ci,Topic,Literal,deployments,Write,Allow,*")
+	path := filepath.Join(t.TempDir(), "acls.csv")
+	if err := os.WriteFile(path, []byte(strings.Join(desired, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	drift, err := runACLsPlan(&out, ds, path, false)
+	if err != nil || !drift {
+		t.Fatalf("plan = %v, %v; want drift", drift, err)
+	}
+	if !strings.Contains(out.String(), "+ User:ci Topic:deployments [Literal] Write Allow from *") ||
+		!strings.Contains(out.String(), "= ") || !strings.Contains(out.String(), "Plan: 1 to create, 0 to delete.") {
+		t.Errorf("plan output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runACLsApply(&out, ds, path, false); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	out.Reset()
+	if drift, err := runACLsPlan(&out, ds, path, false); err != nil || drift {
+		t.Fatalf("plan after additive apply = %v, %v:\n%s", drift, err, out.String())
+	}
+
+	out.Reset()
+	if drift, err := runACLsPlan(&out, ds, path, true); err != nil || !drift || !strings.Contains(out.String(), "Plan: 0 to create, 1 to delete.") {
+		t.Fatalf("plan --delete-extra = %v, %v:\n%s", drift, err, out.String())
+	}
+	if err := runACLsApply(&out, ds, path, true); err != nil {
+		t.Fatalf("apply --delete-extra: %v", err)
+	}
+	out.Reset()
+	if drift, err := runACLsPlan(&out, ds, path, true); err != nil || drift {
+		t.Fatalf("plan after full apply = %v, %v:\n%s", drift, err, out.String())
+	}
+}
+
+func TestACLsPlan_MalformedCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.csv")
+	if err := os.WriteFile(path, []byte("User:a,Topic,Literal\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runACLsPlan(&bytes.Buffer{}, newGetMockDS(), path, false); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("err = %v, want a line-numbered parse error", err)
+	}
+}
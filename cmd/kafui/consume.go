@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/appconfig"
+	"github.com/Benny93/kafui/pkg/masking"
+	"github.com/Benny93/kafui/pkg/messagefilter"
+	"github.com/Benny93/kafui/pkg/serde"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+	"github.com/spf13/cobra"
+)
+
+// consumeFormats lists the values accepted by `kafui consume --format`.
+var consumeFormats = []string{"jsonl", "csv", "raw"}
+
+// consumeOptions carries the `kafui consume` flags.
+type consumeOptions struct {
+	seek       string
+	offset     string
+	timestamp  string
+	partitions []int32
+	limit      int
+	filter     string
+	keySerde   string
+	valueSerde string
+	format     string
+}
+
+// consumeDisplay is the display pipeline applied to every browsed message:
+// serde selection, then masking — the same order as the topic page.
+type consumeDisplay struct {
+	reg        *serde.Registry
+	masker     *masking.Masker
+	keySerde   string
+	valueSerde string
+}
+
+// newConsumeCommand adds `kafui consume <topic>`: a non-interactive browse with
+// the topic page's seek model, smart filter, serdes and masking, printing
+// JSONL, CSV or raw values to stdout.
+func newConsumeCommand() *cobra.Command {
+	var useMock bool
+	opts := consumeOptions{}
+	cmd := &cobra.Command{
+		Use:   "consume <topic>",
+		Short: "Browse a topic's messages to stdout (JSONL/CSV/raw)",
+		Long: "Browse a topic with the same seek modes, filters, serdes and masking as the topic page.\n" +
+			"--limit caps the number of messages read (before --filter), like the topic page's page size;\n" +
+			"0 reads to the end of the log, or until interrupted for --seek live.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newHealthDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			display, err := newConsumeDisplay(ds.GetContext(), args[0], opts.keySerde, opts.valueSerde)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			return runConsume(ctx, os.Stdout, ds, args[0], opts, display)
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&useMock, "mock", false, "use the mock datasource")
+	f.StringVar(&opts.seek, "seek", string(api.SeekNewest), "seek mode (newest|oldest|live|from-offset|to-offset|from-timestamp|to-timestamp)")
+	f.StringVar(&opts.offset, "offset", "", "offset for --seek from-offset/to-offset")
+	f.StringVar(&opts.timestamp, "timestamp", "", "timestamp for --seek from-timestamp/to-timestamp (RFC3339 or relative like -1h)")
+	f.Int32SliceVar(&opts.partitions, "partitions", nil, "comma-separated partitions to read (default all)")
+	f.IntVar(&opts.limit, "limit", api.DefaultPageSize, "maximum number of messages to read (0 = no limit)")
+	f.StringVar(&opts.filter, "filter", "", `smart-filter expression, e.g. 'value contains "error" && partition == 3'`)
+	f.StringVar(&opts.keySerde, "key-serde", serde.Auto, "serde used to render keys (auto = detect / configured binding)")
+	f.StringVar(&opts.valueSerde, "value-serde", serde.Auto, "serde used to render values (auto = detect / configured binding)")
+	f.StringVar(&opts.format, "format", "jsonl", "output format ("+strings.Join(consumeFormats, "|")+")")
+	return cmd
+}
+
+// newConsumeDisplay builds the serde registry and masker from the kafui config
+// of the given cluster. An explicit --key-serde/--value-serde wins over a
+// topic-bound serde from the config, which wins over auto-detection.
+func newConsumeDisplay(cluster, topic, keySerde, valueSerde string) (consumeDisplay, error) {
+	appCfg, err := appconfig.Load(appconfig.DefaultPath())
+	if err != nil {
+		appCfg = appconfig.Default()
+	}
+	ext := appCfg.Clusters[cluster]
+
+	d := consumeDisplay{keySerde: keySerde, valueSerde: valueSerde}
+	if d.reg, err = serde.BuildRegistry(nil, ext.Serdes); err != nil {
+		return d, fmt.Errorf("serdes: %w", err)
+	}
+	if d.keySerde == "" || d.keySerde == serde.Auto {
+		if name := serde.SelectSerde(ext.Serdes, topic, true); name != "" {
+			d.keySerde = name
+		}
+	}
+	if d.valueSerde == "" || d.valueSerde == serde.Auto {
+		if name := serde.SelectSerde(ext.Serdes, topic, false); name != "" {
+			d.valueSerde = name
+		}
+	}
+	for _, name := range []string{d.keySerde, d.valueSerde} {
+		if name != "" && name != serde.Auto {
+			if _, ok := d.reg.Get(name); !ok {
+				return d, serde.UnknownSerdeError{Name: name}
+			}
+		}
+	}
+	if len(ext.Masking) > 0 {
+		rules, err := masking.ParseRules(ext.Masking)
+		if err != nil {
+			return d, fmt.Errorf("masking rules: %w", err)
+		}
+		if d.masker, err = masking.New(rules); err != nil {
+			return d, fmt.Errorf("masking rules: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// seekValue picks the offset or timestamp flag required by the seek mode.
+func (o consumeOptions) seekValue() (string, error) {
+	switch api.SeekMode(o.seek) {
+	case api.SeekFromOffset, api.SeekToOffset:
+		if o.offset == "" {
+			return "", fmt.Errorf("--seek %s requires --offset", o.seek)
+		}
+		return o.offset, nil
+	case api.SeekFromTimestamp, api.SeekToTimestamp:
+		if o.timestamp == "" {
+			return "", fmt.Errorf("--seek %s requires --timestamp", o.seek)
+		}
+		return o.timestamp, nil
+	}
+	return "", nil
+}
+
+// runConsume browses topic and writes the processed messages to w. Bounded
+// modes collect the page and print it in the topic page's display order;
+// live mode streams each message as it arrives until ctx is cancelled.
+func runConsume(ctx context.Context, w io.Writer, ds api.KafkaDataSource, topic string, opts consumeOptions, display consumeDisplay) error {
+	out, err := newMessageWriter(w, opts.format)
+	if err != nil {
+		return err
+	}
+	value, err := opts.seekValue()
+	if err != nil {
+		return err
+	}
+	mode := api.SeekMode(opts.seek)
+	if opts.limit <= 0 && mode.Backward() {
+		return fmt.Errorf("--seek %s reads a window backwards and needs a positive --limit", opts.seek)
+	}
+	flags, err := api.BuildSeekFlags(opts.seek, value, opts.limit, opts.partitions)
+	if err != nil {
+		return err
+	}
+	var filter *messagefilter.Filter
+	if strings.TrimSpace(opts.filter) != "" {
+		if filter, err = messagefilter.Compile(opts.filter); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		read     int
+		page     []api.Message
+		firstErr error
+	)
+	live := mode == api.SeekLive
+	handle := func(msg api.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() != nil || (opts.limit > 0 && read >= opts.limit) {
+			return
+		}
+		read++
+		if opts.limit > 0 && read >= opts.limit {
+			cancel()
+		}
+		if !live {
+			page = append(page, msg)
+			return
+		}
+		if m, ok := display.process(ctx, ds, msg, filter); ok {
+			if err := out.write(m); err != nil && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		}
+	}
+	onError := func(e any) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			if err, ok := e.(error); ok {
+				firstErr = err
+			} else {
+				firstErr = fmt.Errorf("%v", e)
+			}
+		}
+		cancel()
+	}
+
+	err = ds.ConsumeTopic(ctx, topic, flags, handle, onError)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if firstErr != nil {
+		return firstErr
+	}
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+	if !live {
+		api.SortMessages(page, mode.Backward())
+		for _, msg := range page {
+			if m, ok := display.process(context.Background(), ds, msg, filter); ok {
+				if err := out.write(m); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return out.flush()
+}
+
+// process decodes a message, applies the chosen serdes, evaluates the filter on
+// the decoded (unmasked) message, and finally masks it for output. Messages
+// that do not match — or fail to evaluate — are dropped.
+func (d consumeDisplay) process(ctx context.Context, ds api.KafkaDataSource, msg api.Message, filter *messagefilter.Filter) (api.Message, bool) {
+	if len(msg.RawKey) > 0 || len(msg.RawValue) > 0 {
+		if decoded, err := ds.DecodeMessage(ctx, msg); err == nil {
+			msg = decoded
+		}
+	}
+	msg.Key = d.applySerde(msg.Key, msg.RawKey, d.keySerde)
+	msg.Value = d.applySerde(msg.Value, msg.RawValue, d.valueSerde)
+	if filter != nil {
+		if ok, err := filter.Eval(msg); err != nil || !ok {
+			return msg, false
+		}
+	}
+	if d.masker != nil {
+		msg.Key = d.masker.Apply(msg.Key, masking.Key)
+		msg.Value = d.masker.Apply(msg.Value, masking.Value)
+	}
+	return msg, true
+}
+
+// applySerde mirrors the topic page: "auto" keeps the datasource's decoding;
+// an explicit serde decodes the raw bytes through the registry.
+func (d consumeDisplay) applySerde(text string, raw []byte, pref string) string {
+	if pref == "" || pref == serde.Auto || d.reg == nil {
+		return text
+	}
+	data := raw
+	if len(data) == 0 {
+		data = []byte(text)
+	}
+	out, _, _ := serde.Decode(d.reg, pref, data)
+	return out
+}
+
+// messageWriter streams browsed messages in one of the consume formats.
+type messageWriter interface {
+	write(m api.Message) error
+	flush() error
+}
+
+func newMessageWriter(w io.Writer, format string) (messageWriter, error) {
+	switch format {
+	case "jsonl":
+		return jsonlWriter{w: w}, nil
+	case "csv":
+		return &csvMessageWriter{cw: csv.NewWriter(w)}, nil
+	case "raw":
+		return rawWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q (one of %s)", format, strings.Join(consumeFormats, "|"))
+}
+
+// jsonlWriter writes one message object per line, in the in-app export shape.
+type jsonlWriter struct{ w io.Writer }
+
+func (j jsonlWriter) write(m api.Message) error {
+	data, err := shared.MarshalMessageJSON(m)
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(append(data, '\n'))
+	return err
+}
+
+func (jsonlWriter) flush() error { return nil }
+
+// csvMessageWriter writes the shared message CSV layout, header first. Rows are
+// flushed per message so live tails show up immediately.
+type csvMessageWriter struct {
+	cw     *csv.Writer
+	header bool
+}
+
+func (c *csvMessageWriter) write(m api.Message) error {
+	if !c.header {
+		c.header = true
+		if err := c.cw.Write(shared.MessageCSVHeader); err != nil {
+			return err
+		}
+	}
+	if err := c.cw.Write(shared.MessageCSVRow(m)); err != nil {
+		return err
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+func (c *csvMessageWriter) flush() error {
+	if !c.header {
+		c.header = true
+		if err := c.cw.Write(shared.MessageCSVHeader); err != nil {
+			return err
+		}
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+// rawWriter writes only the rendered value, one per line (kcat's default).
+type rawWriter struct{ w io.Writer }
+
+func (r rawWriter) write(m api.Message) error {
+	_, err := io.WriteString(r.w, m.Value+"\n")
+	return err
+}
+
+func (rawWriter) flush() error { return nil }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/masking"
+	"github.com/Benny93/kafui/pkg/serde"
+)
+
+// seedTopic produces n records with keys k0..k(n-1) into partition 0 of topic.
+func seedTopic(t *testing.T, ds api.KafkaDataSource, topic string, n int) {
+	t.Helper()
+	p := int32(0)
+	for i := 0; i < n; i++ {
+		rec := api.ProduceRecord{
+			Key:       []byte("k" + string(rune('0'+i))),
+			Value:     []byte(`{"n":` + string(rune('0'+i)) + `,"secret":"s"}`),
+			Partition: &p,
+		}
+		if err := ds.ProduceMessage(context.Background(), topic, rec); err != nil {
+			t.Fatalf("produce: %v", err)
+		}
+	}
+}
+
+func autoDisplay(t *testing.T) consumeDisplay {
+	t.Helper()
+	reg, err := serde.BuildRegistry(nil, nil)
+	if err != nil {
+		t.Fatalf("registry: %v", err)
+	}
+	return consumeDisplay{reg: reg, keySerde: serde.Auto, valueSerde: serde.Auto}
+}
+
+func firstTopic(t *testing.T, ds api.KafkaDataSource) string {
+	t.Helper()
+	names, err := ds.GetTopicNames()
+	if err != nil || len(names) == 0 {
+		t.Fatalf("no topics: %v", err)
+	}
+	return names[0]
+}
+
+func TestRunConsume_OldestJSONLWithFilter(t *testing.T) {
+	ds := newGetMockDS()
+	topic := firstTopic(t, ds)
+	seedTopic(t, ds, topic, 5)
+
+	opts := consumeOptions{seek: "oldest", limit: 5, partitions: []int32{0}, format: "jsonl", filter: `key == "k3"`}
+	var buf bytes.Buffer
+	if err := runConsume(context.Background(), &buf, ds, topic, opts, autoDisplay(t)); err != nil {
+		t.Fatalf("runConsume: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("lines = %d, want 1:\n%s", len(lines), buf.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got["key"] != "k3" {
+		t.Errorf("key = %v, want k3", got["key"])
+	}
+}
+
+func TestRunConsume_CSVMasked(t *testing.T) {
+	ds := newGetMockDS()
+	topic := firstTopic(t, ds)
+	seedTopic(t, ds, topic, 2)
+
+	d := autoDisplay(t)
+	m, err := masking.New([]masking.Rule{{Action: masking.ActionReplace, Values: true, Fields: []string{"secret"}}})
+	if err != nil {
+		t.Fatalf("masker: %v", err)
+	}
+	d.masker = m
+
+	opts := consumeOptions{seek: "oldest", limit: 2, partitions: []int32{0}, format: "csv"}
+	var buf bytes.Buffer
+	if err := runConsume(context.Background(), &buf, ds, topic, opts, d); err != nil {
+		t.Fatalf("runConsume: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("records = %d, want header + 2", len(records))
+	}
+	for _, r := range records[1:] {
+		if strings.Contains(r[4], `"s"`) || !strings.Contains(r[4], "***DATA_MASKED***") {
+			t.Errorf("value not masked: %q", r[4])
+		}
+	}
+}
+
+func TestRunConsume_SeekValidation(t *testing.T) {
+	ds := newGetMockDS()
+	var buf bytes.Buffer
+	cases := []consumeOptions{
+		{seek: "from-offset", limit: 10, format: "jsonl"},
+		{seek: "newest", limit: 0, format: "jsonl"},
+		{seek: "oldest", limit: 10, format: "xml"},
+		{seek: "bogus", limit: 10, format: "jsonl"},
+		{seek: "oldest", limit: 10, format: "jsonl", filter: "value ~~ x"},
+	}
+	for _, opts := range cases {
+		if err := runConsume(context.Background(), &buf, ds, "t", opts, autoDisplay(t)); err == nil {
+			t.Errorf("%+v: expected error", opts)
+		}
+	}
+}
@@ -2,58 +2,378 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/ui/shared"
+	"github.com/Benny93/kafui/pkg/ui/shared/aclcsv"
 	"github.com/spf13/cobra"
 )
 
-// newGetCommand adds `kafui get …`: non-interactive, machine-readable listings.
-// Currently only `get brokers --format csv` is implemented (the CLI adaptation
-// of the brokers machine-readable endpoint).
+// newGetCommand adds `kafui get …`: non-interactive, machine-readable listings
+// backed by the same KafkaDataSource calls the TUI uses. Every resource accepts
+// --format table|json|yaml|csv, --columns and a --filter on the resource name.
 func newGetCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "Non-interactive resource listings (machine-readable)",
 	}
-	cmd.AddCommand(newGetBrokersCommand())
+	cmd.AddCommand(
+		newGetBrokersCommand(),
+		newGetResourceCommand("topics", nil, "List topics", "table", buildTopicsListing),
+		newGetResourceCommand("consumer-groups", []string{"groups"}, "List consumer groups", "table", buildGroupsListing),
+		newGetResourceCommand("schemas", []string{"subjects"}, "List schema registry subjects", "table", buildSchemasListing),
+		newGetResourceCommand("acls", nil, "List ACL bindings", "table", buildACLsListing),
+		newGetResourceCommand("quotas", nil, "List client quotas", "table", buildQuotasListing),
+		newGetResourceCommand("connectors", nil, "List Kafka Connect connectors", "table", buildConnectorsListing),
+		newGetResourceCommand("connect-clusters", nil, "List Kafka Connect clusters", "table", buildConnectClustersListing),
+		newGetResourceCommand("ksql-streams", nil, "List ksqlDB streams", "table", buildKsqlStreamsListing),
+	)
 	return cmd
 }
 
-func newGetBrokersCommand() *cobra.Command {
+// listingBuilder fetches a resource from ds, keeps the entries whose name
+// matches filter, and returns them as a listing.
+type listingBuilder func(ds api.KafkaDataSource, filter string) (listing, error)
+
+// newGetResourceCommand wires the shared --mock/--format/--columns/--filter
+// flags around a listing builder.
+func newGetResourceCommand(use string, aliases []string, short, defaultFormat string, build listingBuilder) *cobra.Command {
 	var useMock bool
-	var format string
+	var format, filter string
+	var columns []string
 	cmd := &cobra.Command{
-		Use:   "brokers",
-		Short: "List brokers (with stats) to stdout",
+		Use:     use,
+		Aliases: aliases,
+		Short:   short + " to stdout",
+		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if format != "csv" {
-				return fmt.Errorf("unsupported format %q (only csv)", format)
+			if err := validateFormat(format); err != nil {
+				return err
 			}
 			ds, err := newHealthDataSource(useMock)
 			if err != nil {
 				return err
 			}
-			return runGetBrokersCSV(ds)
+			return runGet(os.Stdout, ds, build, filter, format, columns)
 		},
 	}
 	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
-	cmd.Flags().StringVar(&format, "format", "csv", "output format (csv)")
+	cmd.Flags().StringVar(&format, "format", defaultFormat, "output format ("+strings.Join(outputFormats, "|")+")")
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, "comma-separated columns to print, by key or title (default all)")
+	cmd.Flags().StringVar(&filter, "filter", "", "only list resources whose name contains this text (case-insensitive)")
 	return cmd
 }
 
-// runGetBrokersCSV writes the broker list, enriched with stats, as CSV to stdout
-// using the same writer the in-app export uses so both agree.
-func runGetBrokersCSV(ds api.KafkaDataSource) error {
-	brokers, err := ds.GetBrokers()
+// runGet builds a listing and renders it to w.
+func runGet(w io.Writer, ds api.KafkaDataSource, build listingBuilder, filter, format string, columns []string) error {
+	l, err := build(ds, filter)
+	if err != nil {
+		return err
+	}
+	return writeListing(w, l, format, columns)
+}
+
+// newGetBrokersCommand keeps csv as the default format for compatibility with
+// scripts written against the original csv-only subcommand.
+func newGetBrokersCommand() *cobra.Command {
+	return newGetResourceCommand("brokers", nil, "List brokers (with stats)", "csv", buildBrokersListing)
+}
+
+var brokerColumns = []column{
+	{"id", "ID"}, {"host", "Host"}, {"port", "Port"}, {"rack", "Rack"},
+	{"diskUsage", "Disk Usage"}, {"leaders", "Leaders"}, {"replicas", "Replicas"},
+	{"isr", "ISR"}, {"leaderSkew", "Leader Skew"}, {"replicaSkew", "Replica Skew"},
+}
+
+func buildBrokersListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetBrokers()
 	if err != nil {
-		return fmt.Errorf("listing brokers: %w", err)
+		return listing{}, fmt.Errorf("listing brokers: %w", err)
 	}
 	stats, _, err := ds.GetBrokerStats()
 	if err != nil {
 		// Stats are best-effort; still emit the broker list.
 		stats = map[int32]api.BrokerStats{}
 	}
-	return shared.WriteBrokerCSV(os.Stdout, brokers, stats)
+	var brokers []api.BrokerInfo
+	for _, b := range all {
+		if matchName(filter, b.Host) || matchName(filter, fmt.Sprint(b.ID)) {
+			brokers = append(brokers, b)
+		}
+	}
+	sort.Slice(brokers, func(i, j int) bool { return brokers[i].ID < brokers[j].ID })
+
+	l := listing{
+		columns: brokerColumns,
+		csv:     func(w io.Writer) error { return shared.WriteBrokerCSV(w, brokers, stats) },
+	}
+	for _, b := range brokers {
+		row := []any{b.ID, b.Host, b.Port, b.Rack, nil, nil, nil, nil, nil, nil}
+		if s, ok := stats[b.ID]; ok {
+			row[4], row[5], row[6], row[7] = s.SegmentSize, s.LeaderCount, s.ReplicaCount, s.InSyncReplicaCount
+			row[8], row[9] = s.LeaderSkew, s.ReplicaSkew
+		}
+		l.rows = append(l.rows, row)
+	}
+	return l, nil
+}
+
+var topicColumns = []column{
+	{"name", "Name"}, {"partitions", "Partitions"}, {"replicationFactor", "Replication Factor"},
+	{"messageCount", "Message Count"}, {"outOfSync", "Out Of Sync"}, {"size", "Size"}, {"internal", "Internal"},
+}
+
+func buildTopicsListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetTopicNames()
+	if err != nil {
+		return listing{}, fmt.Errorf("listing topics: %w", err)
+	}
+	var names []string
+	for _, n := range all {
+		if matchName(filter, n) {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	rows := shared.CollectTopicCSVRows(ds, names)
+
+	l := listing{
+		columns: topicColumns,
+		csv:     func(w io.Writer) error { return shared.WriteTopicCSV(w, rows) },
+	}
+	for _, t := range rows {
+		var count, size any
+		if t.MessageCount >= 0 {
+			count = t.MessageCount
+		}
+		if t.Size >= 0 {
+			size = t.Size
+		}
+		l.rows = append(l.rows, []any{t.Name, t.Partitions, t.ReplicationFactor, count, t.OutOfSync, size, t.Internal})
+	}
+	return l, nil
+}
+
+var groupColumns = []column{
+	{"group", "Group ID"}, {"members", "Members"}, {"topics", "Topics"},
+	{"lag", "Lag"}, {"coordinator", "Coordinator"}, {"state", "State"},
+}
+
+func buildGroupsListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetConsumerGroups()
+	if err != nil {
+		return listing{}, fmt.Errorf("listing consumer groups: %w", err)
+	}
+	var names []string
+	for _, g := range all {
+		if matchName(filter, g.Name) {
+			names = append(names, g.Name)
+		}
+	}
+	var groups []api.ConsumerGroup
+	if len(names) > 0 {
+		if groups, err = ds.GetConsumerGroupDetails(names); err != nil {
+			return listing{}, fmt.Errorf("describing consumer groups: %w", err)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	l := listing{
+		columns: groupColumns,
+		csv:     func(w io.Writer) error { return shared.WriteConsumerGroupCSV(w, groups) },
+	}
+	for _, g := range groups {
+		var coord any
+		if g.CoordinatorID >= 0 {
+			coord = g.CoordinatorID
+		}
+		l.rows = append(l.rows, []any{g.Name, g.MemberCount, g.TopicCount, g.Lag, coord, g.State})
+	}
+	return l, nil
+}
+
+var schemaColumns = []column{
+	{"subject", "Subject"}, {"version", "Version"}, {"id", "ID"},
+	{"type", "Type"}, {"compatibility", "Compatibility"},
+}
+
+func buildSchemasListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetSchemas()
+	if err != nil {
+		return listing{}, fmt.Errorf("listing schemas: %w", err)
+	}
+	var subjects []string
+	for _, s := range all {
+		if matchName(filter, s.Subject) {
+			subjects = append(subjects, s.Subject)
+		}
+	}
+	var schemas []api.Schema
+	if len(subjects) > 0 {
+		if schemas, err = ds.GetSchemaDetails(subjects); err != nil {
+			return listing{}, fmt.Errorf("describing schemas: %w", err)
+		}
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Subject < schemas[j].Subject })
+
+	l := listing{columns: schemaColumns}
+	for _, s := range schemas {
+		schemaType := s.SchemaType
+		if schemaType == "" {
+			schemaType = "AVRO"
+		}
+		l.rows = append(l.rows, []any{s.Subject, s.Version, s.ID, schemaType, s.Compatibility})
+	}
+	return l, nil
+}
+
+var aclColumns = []column{
+	{"principal", "Principal"}, {"resourceType", "ResourceType"}, {"patternType", "PatternType"},
+	{"resourceName", "ResourceName"}, {"operation", "Operation"}, {"permission", "PermissionType"}, {"host", "Host"},
+}
+
+// buildACLsListing filters on the resource name or the principal, the two
+// identities ACL bindings are usually searched by.
+func buildACLsListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetACLs()
+	if err != nil {
+		return listing{}, fmt.Errorf("listing ACLs: %w", err)
+	}
+	var entries []api.ACLEntry
+	for _, e := range all {
+		if matchName(filter, e.ResourceName) || matchName(filter, e.Principal) {
+			entries = append(entries, e)
+		}
+	}
+
+	l := listing{
+		columns: aclColumns,
+		csv: func(w io.Writer) error {
+			_, err := io.WriteString(w, aclcsv.Marshal(entries))
+			return err
+		},
+	}
+	for _, e := range entries {
+		pattern := e.PatternType
+		if pattern == "" {
+			pattern = "Literal"
+		}
+		host := e.Host
+		if host == "" {
+			host = "*"
+		}
+		l.rows = append(l.rows, []any{e.Principal, e.ResourceType, pattern, e.ResourceName, e.Operation, e.Permission, host})
+	}
+	return l, nil
+}
+
+var quotaColumns = []column{
+	{"user", "User"}, {"clientId", "Client ID"}, {"ip", "IP"}, {"quotas", "Quotas"},
+}
+
+// quotaEntityValue renders an entity identifier: nil (absent) stays nil and
+// the empty string is the <default> entity, matching kafka-configs.sh.
+func quotaEntityValue(p *string) any {
+	if p == nil {
+		return nil
+	}
+	if *p == "" {
+		return "<default>"
+	}
+	return *p
+}
+
+func buildQuotasListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetClientQuotas()
+	if err != nil {
+		return listing{}, fmt.Errorf("listing client quotas: %w", err)
+	}
+	l := listing{columns: quotaColumns}
+	for _, q := range all {
+		user, client, ip := quotaEntityValue(q.Entity.User), quotaEntityValue(q.Entity.ClientID), quotaEntityValue(q.Entity.IP)
+		if !matchName(filter, formatCell(user)+" "+formatCell(client)+" "+formatCell(ip)) {
+			continue
+		}
+		l.rows = append(l.rows, []any{user, client, ip, q.Quotas})
+	}
+	return l, nil
+}
+
+var connectorColumns = []column{
+	{"connect", "Connect"}, {"name", "Name"}, {"type", "Type"}, {"state", "State"},
+	{"tasks", "Tasks"}, {"failedTasks", "Failed Tasks"}, {"topics", "Topics"}, {"class", "Class"},
+}
+
+func buildConnectorsListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetConnectors()
+	if err != nil {
+		return listing{}, fmt.Errorf("listing connectors: %w", err)
+	}
+	var connectors []api.Connector
+	for _, c := range all {
+		if matchName(filter, c.Name) {
+			connectors = append(connectors, c)
+		}
+	}
+	sort.Slice(connectors, func(i, j int) bool {
+		if connectors[i].ConnectCluster != connectors[j].ConnectCluster {
+			return connectors[i].ConnectCluster < connectors[j].ConnectCluster
+		}
+		return connectors[i].Name < connectors[j].Name
+	})
+
+	l := listing{columns: connectorColumns}
+	for _, c := range connectors {
+		topics := c.Topics
+		if topics == nil {
+			topics = []string{}
+		}
+		l.rows = append(l.rows, []any{c.ConnectCluster, c.Name, string(c.Type), c.State, c.TaskCount, c.FailedTaskCount, topics, c.Class})
+	}
+	return l, nil
+}
+
+var connectClusterColumns = []column{
+	{"name", "Name"}, {"address", "Address"}, {"version", "Version"}, {"reachable", "Reachable"},
+	{"connectors", "Connectors"}, {"failedConnectors", "Failed Connectors"},
+	{"tasks", "Tasks"}, {"failedTasks", "Failed Tasks"},
+}
+
+func buildConnectClustersListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.GetConnectClusters(true)
+	if err != nil {
+		return listing{}, fmt.Errorf("listing connect clusters: %w", err)
+	}
+	l := listing{columns: connectClusterColumns}
+	for _, c := range all {
+		if !matchName(filter, c.Name) {
+			continue
+		}
+		l.rows = append(l.rows, []any{c.Name, c.Address, c.Version, c.Reachable,
+			c.ConnectorCount, c.FailedConnectorCount, c.TaskCount, c.FailedTaskCount})
+	}
+	return l, nil
+}
+
+var ksqlStreamColumns = []column{
+	{"name", "Name"}, {"topic", "Topic"}, {"keyFormat", "Key Format"}, {"valueFormat", "Value Format"},
+}
+
+func buildKsqlStreamsListing(ds api.KafkaDataSource, filter string) (listing, error) {
+	all, err := ds.ListKsqlStreams()
+	if err != nil {
+		return listing{}, fmt.Errorf("listing ksqlDB streams: %w", err)
+	}
+	l := listing{columns: ksqlStreamColumns}
+	for _, s := range all {
+		if !matchName(filter, s.Name) {
+			continue
+		}
+		l.rows = append(l.rows, []any{s.Name, s.Topic, s.KeyFormat, s.ValueFormat})
+	}
+	return l, nil
 }
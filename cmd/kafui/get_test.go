@@ -1,42 +1,30 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/csv"
-	"io"
-	"os"
+	"encoding/json"
 	"strings"
 	"testing"
 
 	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"gopkg.in/yaml.v3"
 )
 
-// captureStdout runs fn while capturing anything written to os.Stdout.
-func captureStdout(t *testing.T, fn func()) string {
-	t.Helper()
-	orig := os.Stdout
-	r, w, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("pipe: %v", err)
-	}
-	os.Stdout = w
-	fn()
-	w.Close()
-	os.Stdout = orig
-	out, _ := io.ReadAll(r)
-	return string(out)
-}
-
-func TestRunGetBrokersCSV(t *testing.T) {
+func TestWriteListing_BrokersCSV(t *testing.T) {
 	ds := &mock.KafkaDataSourceMock{}
 	ds.Init("")
 
-	out := captureStdout(t, func() {
-		if err := runGetBrokersCSV(ds); err != nil {
-			t.Fatalf("runGetBrokersCSV: %v", err)
-		}
-	})
+	l, err := buildBrokersListing(ds, "")
+	if err != nil {
+		t.Fatalf("buildBrokersListing: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeListing(&buf, l, "csv", nil); err != nil {
+		t.Fatalf("writeListing: %v", err)
+	}
 
-	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	records, err := csv.NewReader(&buf).ReadAll()
 	if err != nil {
 		t.Fatalf("parse csv: %v", err)
 	}
@@ -57,8 +45,109 @@ func TestRunGetBrokersCSV(t *testing.T) {
 
 func TestGetBrokersCommand_UnsupportedFormat(t *testing.T) {
 	cmd := newGetBrokersCommand()
-	cmd.SetArgs([]string{"--mock", "--format", "json"})
+	cmd.SetArgs([]string{"--mock", "--format", "xml"})
 	if err := cmd.Execute(); err == nil {
 		t.Error("expected error for unsupported format")
 	}
 }
+
+func newGetMockDS() *mock.KafkaDataSourceMock {
+	ds := &mock.KafkaDataSourceMock{}
+	ds.Init("")
+	return ds
+}
+
+func TestRunGet_AllResourcesAllFormats(t *testing.T) {
+	ds := newGetMockDS()
+	builders := map[string]listingBuilder{
+		"brokers":          buildBrokersListing,
+		"topics":           buildTopicsListing,
+		"consumer-groups":  buildGroupsListing,
+		"schemas":          buildSchemasListing,
+		"acls":             buildACLsListing,
+		"quotas":           buildQuotasListing,
+		"connectors":       buildConnectorsListing,
+		"connect-clusters": buildConnectClustersListing,
+		"ksql-streams":     buildKsqlStreamsListing,
+	}
+	for name, build := range builders {
+		for _, format := range outputFormats {
+			var buf bytes.Buffer
+			if err := runGet(&buf, ds, build, "", format, nil); err != nil {
+				t.Errorf("%s/%s: %v", name, format, err)
+				continue
+			}
+			if buf.Len() == 0 {
+				t.Errorf("%s/%s: empty output", name, format)
+			}
+		}
+	}
+}
+
+func TestRunGetTopics_JSONFilterAndColumns(t *testing.T) {
+	ds := newGetMockDS()
+	names, _ := ds.GetTopicNames()
+	if len(names) == 0 {
+		t.Fatal("mock has no topics")
+	}
+	want := names[0]
+
+	var buf bytes.Buffer
+	if err := runGet(&buf, ds, buildTopicsListing, want, "json", []string{"name", "Partitions"}); err != nil {
+		t.Fatalf("runGet: %v", err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("parse json: %v\n%s", err, buf.String())
+	}
+	if len(rows) == 0 {
+		t.Fatalf("no rows for filter %q", want)
+	}
+	for _, r := range rows {
+		if len(r) != 2 {
+			t.Errorf("row has %d fields, want 2: %v", len(r), r)
+		}
+		if !strings.Contains(strings.ToLower(r["name"].(string)), strings.ToLower(want)) {
+			t.Errorf("row %v does not match filter %q", r, want)
+		}
+	}
+}
+
+func TestRunGetGroups_CSVMatchesSharedWriterWithColumns(t *testing.T) {
+	ds := newGetMockDS()
+	var buf bytes.Buffer
+	if err := runGet(&buf, ds, buildGroupsListing, "", "csv", []string{"state", "group"}); err != nil {
+		t.Fatalf("runGet: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if got := records[0]; len(got) != 2 || got[0] != "State" || got[1] != "Group ID" {
+		t.Errorf("header = %v, want [State Group ID]", got)
+	}
+}
+
+func TestRunGetACLs_YAML(t *testing.T) {
+	ds := newGetMockDS()
+	var buf bytes.Buffer
+	if err := runGet(&buf, ds, buildACLsListing, "", "yaml", nil); err != nil {
+		t.Fatalf("runGet: %v", err)
+	}
+	var rows []map[string]string
+	if err := yaml.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("parse yaml: %v", err)
+	}
+	acls, _ := ds.GetACLs()
+	if len(rows) != len(acls) {
+		t.Errorf("rows = %d, want %d", len(rows), len(acls))
+	}
+}
+
+func TestRunGet_UnknownColumn(t *testing.T) {
+	var buf bytes.Buffer
+	err := runGet(&buf, newGetMockDS(), buildTopicsListing, "", "table", []string{"nope"})
+	if err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("err = %v, want unknown column error", err)
+	}
+}
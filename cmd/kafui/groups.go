@@ -0,0 +1,403 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared/groupoffsets"
+	"github.com/spf13/cobra"
+)
+
+// resetOffsetsOptions carries the `kafui groups reset-offsets` flags.
+type resetOffsetsOptions struct {
+	group     string
+	topics    []string
+	allTopics bool
+
+	toEarliest bool
+	toLatest   bool
+	toDatetime string
+	toOffset   int64
+	shiftBy    int64
+	byDuration time.Duration
+	toCurrent  bool
+	fromGroup  string
+
+	execute bool
+}
+
+// newGroupsCommand adds `kafui groups`, the consumer-group operations.
+func newGroupsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "groups",
+		Aliases: []string{"consumer-groups"},
+		Short:   "Consumer group operations",
+	}
+	cmd.AddCommand(newResetOffsetsCommand(), newExportOffsetsCommand(), newImportOffsetsCommand())
+	return cmd
+}
+
+func newResetOffsetsCommand() *cobra.Command {
+	var (
+		useMock bool
+		dryRun  bool
+	)
+	opts := resetOffsetsOptions{}
+	cmd := &cobra.Command{
+		Use:   "reset-offsets",
+		Short: "Plan (--dry-run, default) or commit (--execute) a consumer group offset reset",
+		Long: "Reset a consumer group's committed offsets for one or more topics.\n" +
+			"Choose exactly one of --to-earliest, --to-latest, --to-datetime, --to-offset,\n" +
+			"--shift-by, --by-duration, --to-current or --from-group. Without --execute only the per-partition plan is\n" +
+			"printed; with --execute the planned targets are committed (the group must be inactive).",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun && opts.execute {
+				return fmt.Errorf("--dry-run and --execute are mutually exclusive")
+			}
+			f := cmd.Flags()
+			req, err := opts.request(f.Changed("to-offset"), f.Changed("shift-by"), f.Changed("by-duration"))
+			if err != nil {
+				return err
+			}
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runResetOffsets(cmd.Context(), os.Stdout, ds, opts, req)
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&useMock, "mock", false, "use the mock datasource")
+	f.StringVar(&opts.group, "group", "", "consumer group id (required)")
+	f.StringArrayVar(&opts.topics, "topic", nil, "topic to reset (repeatable)")
+	f.BoolVar(&opts.allTopics, "all-topics", false, "reset every topic the group has committed offsets for")
+	f.BoolVar(&opts.toEarliest, "to-earliest", false, "reset to the log start offset")
+	f.BoolVar(&opts.toLatest, "to-latest", false, "reset to the log end offset")
+	f.StringVar(&opts.toDatetime, "to-datetime", "", "reset to the first offset at/after a time (RFC3339 or relative like -2h)")
+	f.Int64Var(&opts.toOffset, "to-offset", 0, "reset every partition to this offset (clamped into the partition's range)")
+	f.Int64Var(&opts.shiftBy, "shift-by", 0, "move the committed offset by N records (negative rewinds)")
+	f.DurationVar(&opts.byDuration, "by-duration", 0, "reset to the first offset at/after now minus this duration (e.g. 2h)")
+	f.BoolVar(&opts.toCurrent, "to-current", false, "re-commit the current offsets, clamped into the partition's range")
+	f.StringVar(&opts.fromGroup, "from-group", "", "copy the committed offsets of another group")
+	f.BoolVar(&dryRun, "dry-run", false, "only print the plan (default)")
+	f.BoolVar(&opts.execute, "execute", false, "commit the planned offsets")
+	_ = cmd.MarkFlagRequired("group")
+	return cmd
+}
+
+// request validates the scope and mode flags and builds the reset request
+// template (GroupID and mode fields; the topic is filled in per topic). The
+// changed flags tell an explicit zero apart from an unset flag. --to-offset
+// becomes an explicit reset whose offsets are filled in per topic by
+// uniformOffsets.
+func (o resetOffsetsOptions) request(toOffsetSet, shiftBySet, byDurationSet bool) (api.OffsetResetRequest, error) {
+	req := api.OffsetResetRequest{GroupID: o.group}
+	if o.group == "" {
+		return req, fmt.Errorf("--group is required")
+	}
+	if o.allTopics == (len(o.topics) > 0) {
+		return req, fmt.Errorf("specify either --topic or --all-topics")
+	}
+	modes := 0
+	if o.toEarliest {
+		modes++
+		req.Mode = api.OffsetResetEarliest
+	}
+	if o.toLatest {
+		modes++
+		req.Mode = api.OffsetResetLatest
+	}
+	if o.toDatetime != "" {
+		modes++
+		ts, err := api.ParseSeekTime(o.toDatetime)
+		if err != nil {
+			return req, fmt.Errorf("--to-datetime: %w", err)
+		}
+		req.Mode = api.OffsetResetTimestamp
+		req.Timestamp = &ts
+	}
+	if toOffsetSet {
+		modes++
+		if o.toOffset < 0 {
+			return req, fmt.Errorf("--to-offset must not be negative")
+		}
+		req.Mode = api.OffsetResetExplicit
+	}
+	if shiftBySet {
+		modes++
+		if o.shiftBy == 0 {
+			return req, fmt.Errorf("--shift-by must not be zero")
+		}
+		req.Mode = api.OffsetResetShiftBy
+		req.ShiftBy = o.shiftBy
+	}
+	if byDurationSet {
+		modes++
+		if o.byDuration <= 0 {
+			return req, fmt.Errorf("--by-duration must be positive")
+		}
+		req.Mode = api.OffsetResetDuration
+		req.Duration = o.byDuration
+	}
+	if o.toCurrent {
+		modes++
+		req.Mode = api.OffsetResetCurrent
+	}
+	if o.fromGroup != "" {
+		modes++
+		if o.fromGroup == o.group {
+			return req, fmt.Errorf("--from-group must differ from --group")
+		}
+		req.Mode = api.OffsetResetFromGroup
+		req.SourceGroupID = o.fromGroup
+	}
+	if modes != 1 {
+		return req, fmt.Errorf("specify exactly one of --to-earliest, --to-latest, --to-datetime, --to-offset, --shift-by, --by-duration, --to-current, --from-group")
+	}
+	return req, nil
+}
+
+// runResetOffsets plans the reset for every topic in scope, prints the
+// per-partition table and, with --execute, commits exactly the planned targets.
+func runResetOffsets(ctx context.Context, w io.Writer, ds api.KafkaDataSource, opts resetOffsetsOptions, tmpl api.OffsetResetRequest) error {
+	topics := opts.topics
+	if opts.allTopics {
+		detail, err := ds.GetConsumerGroupDetail(opts.group)
+		if err != nil {
+			return err
+		}
+		topics = groupTopics(detail)
+		if len(topics) == 0 {
+			return fmt.Errorf("group %q has no committed offsets", opts.group)
+		}
+	}
+
+	var (
+		plan []api.PartitionResetPlan
+		reqs []api.OffsetResetRequest
+	)
+	for _, topic := range topics {
+		req := tmpl
+		req.Topic = topic
+		if req.Mode == api.OffsetResetExplicit {
+			offsets, err := uniformOffsets(ctx, ds, req, opts.toOffset)
+			if err != nil {
+				return err
+			}
+			req.PartitionOffsets = offsets
+		}
+		entries, err := ds.PlanConsumerGroupOffsetReset(ctx, req)
+		if err != nil {
+			return fmt.Errorf("planning reset for topic %q: %w", topic, err)
+		}
+		plan = append(plan, entries...)
+		reqs = append(reqs, req)
+	}
+	if err := writeResetPlan(w, plan); err != nil {
+		return err
+	}
+	if !opts.execute {
+		fmt.Fprintln(w, "Dry run: nothing committed. Re-run with --execute to apply.")
+		return nil
+	}
+
+	for _, req := range reqs {
+		req.Partitions, req.Planned = nil, map[int32]int64{}
+		for _, p := range plan {
+			if p.Topic == req.Topic {
+				req.Partitions = append(req.Partitions, p.Partition)
+				req.Planned[p.Partition] = p.Target
+			}
+		}
+		if len(req.Partitions) == 0 {
+			continue
+		}
+		if err := ds.ResetConsumerGroupOffsets(ctx, req); err != nil {
+			return fmt.Errorf("resetting topic %q: %w", req.Topic, err)
+		}
+	}
+	fmt.Fprintf(w, "Committed new offsets for %d partition(s) of group %s.\n", len(plan), opts.group)
+	return nil
+}
+
+// uniformOffsets maps every partition of the request's topic to offset, using
+// a to-latest plan to enumerate the partitions.
+func uniformOffsets(ctx context.Context, ds api.KafkaDataSource, req api.OffsetResetRequest, offset int64) (map[int32]int64, error) {
+	probe := req
+	probe.Mode = api.OffsetResetLatest
+	entries, err := ds.PlanConsumerGroupOffsetReset(ctx, probe)
+	if err != nil {
+		return nil, fmt.Errorf("listing partitions of topic %q: %w", req.Topic, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("topic %q has no partitions", req.Topic)
+	}
+	out := make(map[int32]int64, len(entries))
+	for _, e := range entries {
+		out[e.Partition] = offset
+	}
+	return out, nil
+}
+
+// groupTopics returns the sorted topics the group has committed offsets for.
+func groupTopics(d api.ConsumerGroupDetail) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, po := range d.TopicOffsets {
+		if po.CommittedOffset != nil && !seen[po.Topic] {
+			seen[po.Topic] = true
+			out = append(out, po.Topic)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// writeResetPlan prints the per-partition plan table.
+func writeResetPlan(w io.Writer, plan []api.PartitionResetPlan) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOPIC\tPARTITION\tCURRENT-OFFSET\tTARGET-OFFSET\tLOG-START\tLOG-END\tCURRENT-LAG\tNEW-LAG")
+	for _, p := range plan {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\t%d\t%s\t%d\n",
+			p.Topic, p.Partition, optionalOffset(p.Current), p.Target, p.LogStart, p.LogEnd, optionalOffset(p.CurrentLag()), p.TargetLag())
+	}
+	return tw.Flush()
+}
+
+func optionalOffset(v *int64) string {
+	if v == nil {
+		return "-"
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+func newExportOffsetsCommand() *cobra.Command {
+	var (
+		useMock bool
+		group   string
+		format  string
+		output  string
+	)
+	cmd := &cobra.Command{
+		Use:   "export-offsets",
+		Short: "Write a group's committed offsets and their record timestamps as JSON or CSV",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "json" && format != "csv" {
+				return fmt.Errorf("--format must be json or csv")
+			}
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			w := io.Writer(os.Stdout)
+			if output != "" && output != "-" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			return runExportOffsets(cmd.Context(), w, ds, group, format)
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&useMock, "mock", false, "use the mock datasource")
+	f.StringVar(&group, "group", "", "consumer group id (required)")
+	f.StringVar(&format, "format", "json", "output format: json or csv")
+	f.StringVarP(&output, "output", "o", "", "file to write (default stdout)")
+	_ = cmd.MarkFlagRequired("group")
+	return cmd
+}
+
+func newImportOffsetsCommand() *cobra.Command {
+	var (
+		useMock bool
+		dryRun  bool
+		execute bool
+		opts    groupoffsets.ImportOptions
+	)
+	cmd := &cobra.Command{
+		Use:   "import-offsets <file>",
+		Short: "Plan (--dry-run, default) or commit (--execute) offsets from an export-offsets file",
+		Long: "Commit the offsets of an export-offsets file (JSON or CSV) to a group, by default the\n" +
+			"exported one. With --translate-by-timestamp each partition resumes at the first record\n" +
+			"at/after the exported record's timestamp, for clusters whose offsets differ.\n" +
+			"Without --execute only the per-partition plan is printed.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun && execute {
+				return fmt.Errorf("--dry-run and --execute are mutually exclusive")
+			}
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runImportOffsets(cmd.Context(), os.Stdout, ds, args[0], opts, execute)
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&useMock, "mock", false, "use the mock datasource")
+	f.StringVar(&opts.Group, "group", "", "group to commit to (default: the exported group)")
+	f.BoolVar(&opts.TranslateByTimestamp, "translate-by-timestamp", false, "resolve offsets by the exported record timestamps")
+	f.BoolVar(&dryRun, "dry-run", false, "only print the plan (default)")
+	f.BoolVar(&execute, "execute", false, "commit the planned offsets")
+	return cmd
+}
+
+func runExportOffsets(ctx context.Context, w io.Writer, ds api.KafkaDataSource, group, format string) error {
+	exp, err := groupoffsets.Collect(ctx, ds, group)
+	if err != nil {
+		return err
+	}
+	if format == "csv" {
+		_, err = io.WriteString(w, exp.CSV())
+		return err
+	}
+	data, err := exp.JSON()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// runImportOffsets prints the import plan and, with execute, commits it.
+func runImportOffsets(ctx context.Context, w io.Writer, ds api.KafkaDataSource, path string, opts groupoffsets.ImportOptions, execute bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	exp, err := groupoffsets.Parse(data)
+	if err != nil {
+		return err
+	}
+	if opts.Group == "" {
+		opts.Group = exp.Group
+	}
+	plan, err := groupoffsets.PlanImport(ctx, ds, exp, opts)
+	if err != nil {
+		return err
+	}
+	if err := writeResetPlan(w, plan); err != nil {
+		return err
+	}
+	if !execute {
+		fmt.Fprintln(w, "Dry run: nothing committed. Re-run with --execute to apply.")
+		return nil
+	}
+	if err := groupoffsets.Apply(ctx, ds, opts.Group, plan); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Committed new offsets for %d partition(s) of group %s.\n", len(plan), opts.Group)
+	return nil
+}
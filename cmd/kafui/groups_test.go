@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"github.com/Benny93/kafui/pkg/ui/shared/groupoffsets"
+)
+
+func newGroupsMockDS() *mock.KafkaDataSourceMock {
+	ds := &mock.KafkaDataSourceMock{}
+	ds.Init("")
+	return ds
+}
+
+func committedOf(t *testing.T, ds api.KafkaDataSource, group, topic string) map[int32]int64 {
+	t.Helper()
+	d, err := ds.GetConsumerGroupDetail(group)
+	if err != nil {
+		t.Fatalf("detail: %v", err)
+	}
+	out := map[int32]int64{}
+	for _, po := range d.TopicOffsets {
+		if po.Topic == topic && po.CommittedOffset != nil {
+			out[po.Partition] = *po.CommittedOffset
+		}
+	}
+	return out
+}
+
+func TestResetOffsetsOptions_Request(t *testing.T) {
+	base := resetOffsetsOptions{group: "g", topics: []string{"t"}}
+
+	o := base
+	o.byDuration = 2 * time.Hour
+	req, err := o.request(false, false, true)
+	if err != nil || req.Mode != api.OffsetResetDuration || req.Duration != 2*time.Hour {
+		t.Errorf("by-duration request = %+v, %v", req, err)
+	}
+
+	o = base
+	req, err = o.request(true, false, false)
+	if err != nil || req.Mode != api.OffsetResetExplicit {
+		t.Errorf("--to-offset 0 must count as set: %+v, %v", req, err)
+	}
+
+	o = base
+	o.fromGroup = "other"
+	req, err = o.request(false, false, false)
+	if err != nil || req.Mode != api.OffsetResetFromGroup || req.SourceGroupID != "other" {
+		t.Errorf("from-group request = %+v, %v", req, err)
+	}
+
+	cases := map[string]struct {
+		opts                      resetOffsetsOptions
+		toOffset, shift, duration bool
+	}{
+		"no mode":          {opts: base},
+		"two modes":        {opts: resetOffsetsOptions{group: "g", topics: []string{"t"}, toEarliest: true, toLatest: true}},
+		"current and copy": {opts: resetOffsetsOptions{group: "g", topics: []string{"t"}, toCurrent: true, fromGroup: "x"}},
+		"copy from itself": {opts: resetOffsetsOptions{group: "g", topics: []string{"t"}, fromGroup: "g"}},
+		"no scope":         {opts: resetOffsetsOptions{group: "g", toEarliest: true}},
+		"both scopes":      {opts: resetOffsetsOptions{group: "g", topics: []string{"t"}, allTopics: true, toEarliest: true}},
+		"bad time":         {opts: resetOffsetsOptions{group: "g", topics: []string{"t"}, toDatetime: "yesterday"}},
+		"zero shift":       {opts: base, shift: true},
+	}
+	for name, c := range cases {
+		if _, err := c.opts.request(c.toOffset, c.shift, c.duration); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestRunResetOffsets_DryRunThenExecute(t *testing.T) {
+	ds := newGroupsMockDS()
+	opts := resetOffsetsOptions{group: "inventory-sync", topics: []string{"inventory-events"}, shiftBy: -5}
+	req, err := opts.request(false, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runResetOffsets(context.Background(), &buf, ds, opts, req); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "CURRENT-OFFSET") || !strings.Contains(out, "Dry run") {
+		t.Errorf("dry-run output:\n%s", out)
+	}
+	// p0: current 10 -> 5 (end 60, new lag 55)
+	if fields := strings.Fields(lineWith(out, "inventory-events  0")); len(fields) != 8 || fields[2] != "10" || fields[3] != "5" || fields[7] != "55" {
+		t.Errorf("partition 0 row = %v", fields)
+	}
+	if got := committedOf(t, ds, "inventory-sync", "inventory-events"); got[0] != 10 || got[1] != 20 {
+		t.Fatalf("dry run committed offsets: %v", got)
+	}
+
+	opts.execute = true
+	buf.Reset()
+	if err := runResetOffsets(context.Background(), &buf, ds, opts, req); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := committedOf(t, ds, "inventory-sync", "inventory-events"); got[0] != 5 || got[1] != 15 {
+		t.Errorf("executed offsets = %v, want 0:5 1:15", got)
+	}
+}
+
+func TestRunResetOffsets_AllTopicsActiveGroup(t *testing.T) {
+	ds := newGroupsMockDS()
+	opts := resetOffsetsOptions{group: "order-processor", allTopics: true, toOffset: 120}
+	req, err := opts.request(true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := runResetOffsets(context.Background(), &buf, ds, opts, req); err != nil {
+		t.Fatalf("dry run on an active group: %v", err)
+	}
+	// Every committed partition is planned to 120 (inside each end offset).
+	for _, p := range []string{"0", "1", "2"} {
+		if fields := strings.Fields(lineWith(buf.String(), "order-events  "+p)); len(fields) != 8 || fields[3] != "120" {
+			t.Errorf("partition %s row = %v\n%s", p, fields, buf.String())
+		}
+	}
+
+	opts.execute = true
+	err = runResetOffsets(context.Background(), &bytes.Buffer{}, ds, opts, req)
+	var notEmpty api.GroupNotEmptyError
+	if !errors.As(err, &notEmpty) {
+		t.Errorf("execute on an active group: err = %v, want GroupNotEmptyError", err)
+	}
+}
+
+// resetRecorder records the reset requests that reach the datasource.
+type resetRecorder struct {
+	*mock.KafkaDataSourceMock
+	resets []api.OffsetResetRequest
+}
+
+func (r *resetRecorder) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
+	r.resets = append(r.resets, req)
+	return r.KafkaDataSourceMock.ResetConsumerGroupOffsets(ctx, req)
+}
+
+func TestRunResetOffsets_FromGroup(t *testing.T) {
+	ds := &resetRecorder{KafkaDataSourceMock: newGroupsMockDS()}
+	opts := resetOffsetsOptions{group: "load-test-processor", topics: []string{"inventory-events"}, fromGroup: "inventory-sync", execute: true}
+	req, err := opts.request(false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runResetOffsets(context.Background(), &bytes.Buffer{}, ds, opts, req); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := committedOf(t, ds, "load-test-processor", "inventory-events"); got[0] != 10 || got[1] != 20 {
+		t.Errorf("copied offsets = %v, want 0:10 1:20", got)
+	}
+	// The commit keeps the copy mode and source so both are authorized and
+	// audited, and carries the planned targets.
+	if len(ds.resets) != 1 {
+		t.Fatalf("resets = %d, want 1", len(ds.resets))
+	}
+	commit := ds.resets[0]
+	if commit.Mode != api.OffsetResetFromGroup || commit.SourceGroupID != "inventory-sync" {
+		t.Errorf("commit mode = %q source = %q, want copy from inventory-sync", commit.Mode, commit.SourceGroupID)
+	}
+	if commit.Planned[0] != 10 || commit.Planned[1] != 20 {
+		t.Errorf("commit planned = %v, want 0:10 1:20", commit.Planned)
+	}
+}
+
+func lineWith(out, prefix string) string {
+	for _, l := range strings.Split(out, "\n") {
+		if strings.HasPrefix(strings.Join(strings.Fields(l), "  "), prefix) {
+			return l
+		}
+	}
+	return ""
+}
+
+func TestExportThenImportOffsets(t *testing.T) {
+	src := newGroupsMockDS()
+	var exported bytes.Buffer
+	if err := runExportOffsets(context.Background(), &exported, src, "inventory-sync", "csv"); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !strings.HasPrefix(exported.String(), "Group,Topic,Partition,Offset,Timestamp\n") {
+		t.Fatalf("csv export:\n%s", exported.String())
+	}
+	path := filepath.Join(t.TempDir(), "offsets.csv")
+	if err := os.WriteFile(path, exported.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := newGroupsMockDS()
+	if err := dst.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{GroupID: "inventory-sync", Topic: "inventory-events", Mode: api.OffsetResetLatest}); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := runImportOffsets(context.Background(), &buf, dst, path, groupoffsets.ImportOptions{}, false); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if got := committedOf(t, dst, "inventory-sync", "inventory-events"); got[0] != 60 {
+		t.Fatalf("dry run committed: %v", got)
+	}
+	if err := runImportOffsets(context.Background(), &buf, dst, path, groupoffsets.ImportOptions{}, true); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got := committedOf(t, dst, "inventory-sync", "inventory-events"); got[0] != 10 || got[1] != 20 {
+		t.Errorf("imported offsets = %v, want 0:10 1:20", got)
+	}
+}
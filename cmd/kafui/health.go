@@ -5,8 +5,10 @@ import (
 	"os"
 
 	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/appconfig"
 	"github.com/Benny93/kafui/pkg/datasource/kafds"
 	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"github.com/Benny93/kafui/pkg/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -40,8 +42,13 @@ func newHealthDataSource(useMock bool) (api.KafkaDataSource, error) {
 		ds.Init("")
 		return ds, nil
 	}
-	kafds.SetOverrides(brokersFlag, schemaRegistryURL, clusterFlag, verboseFlag)
 	ds := kafds.NewKafkaDataSourceKaf()
+	ds.SetOverrides(kafds.Overrides{
+		Brokers:        brokersFlag,
+		SchemaRegistry: schemaRegistryURL,
+		Cluster:        clusterFlag,
+		Verbose:        verboseFlag,
+	})
 	ds.Init(cfgFile)
 	if err := api.ValidateClusterOverride(ds, clusterFlag); err != nil {
 		return nil, err
@@ -49,6 +56,25 @@ func newHealthDataSource(useMock bool) (api.KafkaDataSource, error) {
 	return ds, nil
 }
 
+// newGuardedDataSource builds a one-shot datasource wrapped with the same
+// authorization gate and audit service as the TUI, for CLI commands that
+// mutate the cluster. --read-only and per-cluster readOnly settings apply.
+func newGuardedDataSource(useMock bool) (api.KafkaDataSource, error) {
+	ds, err := newHealthDataSource(useMock)
+	if err != nil {
+		return nil, err
+	}
+	appCfg, err := appconfig.Load(appconfig.DefaultPath())
+	if err != nil {
+		appCfg = appconfig.Default()
+	}
+	guard, _, err := ui.NewGuardedDataSource(ds, appCfg, readOnlyFlag)
+	if err != nil {
+		return nil, err
+	}
+	return guard, nil
+}
+
 // runHealth probes broker (via a metadata listing) and, when configured, the
 // schema registry. It prints one OK/FAIL line per service and returns an exit code.
 func runHealth(ds api.KafkaDataSource) int {
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormats lists the values accepted by --format on listing commands.
+var outputFormats = []string{"table", "json", "yaml", "csv"}
+
+// column is one output column. Key names the json/yaml field and is the
+// preferred --columns identifier; Title is the csv/table header.
+type column struct {
+	Key   string
+	Title string
+}
+
+// listing is a resource listing that writeListing renders in any --format.
+// Rows hold typed cell values (nil = unknown) aligned with columns.
+type listing struct {
+	columns []column
+	rows    [][]any
+	// csv, when set, writes the same rows with the shared in-app CSV writer so
+	// CLI and TUI exports agree. Its header must match the column titles.
+	csv func(w io.Writer) error
+}
+
+// matchName reports whether name passes the --filter substring (case-insensitive).
+// An empty filter matches everything.
+func matchName(filter, name string) bool {
+	return filter == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+// selectColumns resolves --columns against the listing's columns by key or
+// title (case-insensitive, spaces ignored) and returns their indexes in the
+// requested order. An empty selection keeps every column.
+func selectColumns(cols []column, selection []string) ([]int, error) {
+	if len(selection) == 0 {
+		idx := make([]int, len(cols))
+		for i := range cols {
+			idx[i] = i
+		}
+		return idx, nil
+	}
+	norm := func(s string) string { return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", "")) }
+	var idx []int
+	for _, sel := range selection {
+		found := -1
+		for i, c := range cols {
+			if norm(sel) == norm(c.Key) || norm(sel) == norm(c.Title) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			keys := make([]string, len(cols))
+			for i, c := range cols {
+				keys[i] = c.Key
+			}
+			return nil, fmt.Errorf("unknown column %q (available: %s)", sel, strings.Join(keys, ", "))
+		}
+		idx = append(idx, found)
+	}
+	return idx, nil
+}
+
+// validateFormat rejects anything outside outputFormats.
+func validateFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format %q (one of %s)", format, strings.Join(outputFormats, "|"))
+}
+
+// writeListing renders l to w in the given format, restricted to the selected
+// columns.
+func writeListing(w io.Writer, l listing, format string, selection []string) error {
+	if err := validateFormat(format); err != nil {
+		return err
+	}
+	idx, err := selectColumns(l.columns, selection)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "json":
+		return writeJSON(w, l, idx)
+	case "yaml":
+		return writeYAML(w, l, idx)
+	case "csv":
+		if l.csv != nil {
+			return writeSharedCSV(w, l, idx)
+		}
+		return writeCSV(w, l, idx)
+	default:
+		return writeTable(w, l, idx)
+	}
+}
+
+func writeTable(w io.Writer, l listing, idx []int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(idx))
+	for i, c := range idx {
+		header[i] = strings.ToUpper(l.columns[c].Title)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range l.rows {
+		cells := make([]string, len(idx))
+		for i, c := range idx {
+			cells[i] = formatCell(row[c])
+			if cells[i] == "" {
+				cells[i] = "-"
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeCSV(w io.Writer, l listing, idx []int) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(idx))
+	for i, c := range idx {
+		header[i] = l.columns[c].Title
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range l.rows {
+		cells := make([]string, len(idx))
+		for i, c := range idx {
+			cells[i] = formatCell(row[c])
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeSharedCSV runs the shared writer and, when a column subset was
+// requested, projects its output onto those columns by header title.
+func writeSharedCSV(w io.Writer, l listing, idx []int) error {
+	if len(idx) == len(l.columns) && isIdentity(idx) {
+		return l.csv(w)
+	}
+	var buf bytes.Buffer
+	if err := l.csv(&buf); err != nil {
+		return err
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	pos := make(map[string]int, len(records[0]))
+	for i, h := range records[0] {
+		pos[h] = i
+	}
+	cw := csv.NewWriter(w)
+	for _, rec := range records {
+		out := make([]string, len(idx))
+		for i, c := range idx {
+			if p, ok := pos[l.columns[c].Title]; ok && p < len(rec) {
+				out[i] = rec[p]
+			}
+		}
+		if err := cw.Write(out); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func isIdentity(idx []int) bool {
+	for i, c := range idx {
+		if i != c {
+			return false
+		}
+	}
+	return true
+}
+
+// writeJSON writes rows as an array of objects whose fields keep column order
+// (encoding/json would sort map keys).
+func writeJSON(w io.Writer, l listing, idx []int) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for r, row := range l.rows {
+		if r > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for i, c := range idx {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			k, _ := json.Marshal(l.columns[c].Key)
+			v, err := json.Marshal(row[c])
+			if err != nil {
+				return err
+			}
+			buf.Write(k)
+			buf.WriteByte(':')
+			buf.Write(v)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// writeYAML writes rows as a sequence of mappings in column order.
+func writeYAML(w io.Writer, l listing, idx []int) error {
+	seq := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, row := range l.rows {
+		m := &yaml.Node{Kind: yaml.MappingNode}
+		for _, c := range idx {
+			var v yaml.Node
+			if err := v.Encode(row[c]); err != nil {
+				return err
+			}
+			m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: l.columns[c].Key}, &v)
+		}
+		seq.Content = append(seq.Content, m)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(seq); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// formatCell renders a typed cell for csv/table output. nil renders empty.
+func formatCell(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case *string:
+		if x == nil {
+			return ""
+		}
+		return *x
+	case *int64:
+		if x == nil {
+			return ""
+		}
+		return strconv.FormatInt(*x, 10)
+	case *float64:
+		if x == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*x, 'f', -1, 64)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case []string:
+		return strings.Join(x, ",")
+	case map[string]float64:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, k+"="+strconv.FormatFloat(x[k], 'f', -1, 64))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(x)
+	}
+}
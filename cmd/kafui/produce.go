@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/serde"
+	"github.com/spf13/cobra"
+)
+
+// produceInputs lists the values accepted by `kafui produce --input`.
+var produceInputs = []string{"jsonl", "lines"}
+
+// produceOptions carries the `kafui produce` flags.
+type produceOptions struct {
+	file         string
+	input        string
+	keySeparator string
+	keySerde     string
+	valueSerde   string
+	partition    int32
+	headers      []string
+}
+
+// newProduceCommand adds `kafui produce <topic>`: scripted produce from stdin or
+// a file. Every record goes through the guarded datasource, so the authz gate
+// (including read-only clusters) and audit log apply exactly as for the
+// in-app produce form.
+func newProduceCommand() *cobra.Command {
+	var useMock bool
+	opts := produceOptions{}
+	cmd := &cobra.Command{
+		Use:   "produce <topic>",
+		Short: "Produce records from stdin or a file (JSONL or line-delimited values)",
+		Long: "Produce records to a topic.\n" +
+			"--input jsonl: one object per line: {\"key\":…, \"value\":…, \"headers\":{\"k\":\"v\"}, \"partition\":N};\n" +
+			"  key/value may be strings, JSON values (encoded as JSON text) or null.\n" +
+			"--input lines: one value per line; with --key-separator each line is <key><sep><value>.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in := io.Reader(os.Stdin)
+			if opts.file != "" && opts.file != "-" {
+				f, err := os.Open(opts.file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			n, err := runProduce(cmd.Context(), in, ds, args[0], opts)
+			fmt.Fprintf(os.Stderr, "produced %d record(s) to %s\n", n, args[0])
+			return err
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&useMock, "mock", false, "use the mock datasource")
+	f.StringVarP(&opts.file, "file", "f", "-", "file to read records from (- = stdin)")
+	f.StringVar(&opts.input, "input", "jsonl", "input format ("+strings.Join(produceInputs, "|")+")")
+	f.StringVar(&opts.keySeparator, "key-separator", "", "for --input lines: split each line into key and value at the first separator")
+	f.StringVar(&opts.keySerde, "key-serde", serde.NameString, "serde used to encode keys (string|json|hex|int|long|msgpack)")
+	f.StringVar(&opts.valueSerde, "value-serde", serde.NameString, "serde used to encode values (string|json|hex|int|long|msgpack)")
+	f.Int32Var(&opts.partition, "partition", -1, "partition for records that do not set one (-1 = let the partitioner choose)")
+	f.StringArrayVar(&opts.headers, "header", nil, "header k=v added to every record (repeatable)")
+	return cmd
+}
+
+// produceLine is one JSONL input record. Key and Value stay raw so both JSON
+// strings and structured JSON values (for the json serde) are accepted.
+type produceLine struct {
+	Key       json.RawMessage `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Headers   json.RawMessage `json:"headers"`
+	Partition *int32          `json:"partition"`
+}
+
+// runProduce reads records from in and produces them one by one, stopping at
+// the first failure. It returns the number of records produced.
+func runProduce(ctx context.Context, in io.Reader, ds api.KafkaDataSource, topic string, opts produceOptions) (int, error) {
+	reg, err := serde.BuildRegistry(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	keySer, err := lookupSerializer(reg, opts.keySerde)
+	if err != nil {
+		return 0, err
+	}
+	valueSer, err := lookupSerializer(reg, opts.valueSerde)
+	if err != nil {
+		return 0, err
+	}
+	var common []api.MessageHeader
+	for _, h := range opts.headers {
+		kv := strings.SplitN(h, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return 0, fmt.Errorf("invalid --header %q (want k=v)", h)
+		}
+		common = append(common, api.MessageHeader{Key: kv[0], Value: kv[1]})
+	}
+
+	var parse func(line string) (api.ProduceRecord, error)
+	switch opts.input {
+	case "jsonl":
+		parse = func(line string) (api.ProduceRecord, error) { return parseJSONLRecord(line, keySer, valueSer) }
+	case "lines":
+		parse = func(line string) (api.ProduceRecord, error) {
+			return parseValueLine(line, opts.keySeparator, keySer, valueSer)
+		}
+	default:
+		return 0, fmt.Errorf("unsupported input %q (one of %s)", opts.input, strings.Join(produceInputs, "|"))
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	produced, lineNo := 0, 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		rec, err := parse(line)
+		if err != nil {
+			return produced, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		rec.Headers = append(append([]api.MessageHeader(nil), common...), rec.Headers...)
+		if rec.Partition == nil && opts.partition >= 0 {
+			p := opts.partition
+			rec.Partition = &p
+		}
+		if err := ds.ProduceMessage(ctx, topic, rec); err != nil {
+			return produced, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		produced++
+	}
+	if err := scanner.Err(); err != nil {
+		return produced, err
+	}
+	return produced, nil
+}
+
+// lookupSerializer resolves a serde that can encode (implements Serializer).
+func lookupSerializer(reg *serde.Registry, name string) (serde.Serializer, error) {
+	s, ok := reg.Get(name)
+	if !ok {
+		return nil, serde.UnknownSerdeError{Name: name}
+	}
+	ser, ok := s.(serde.Serializer)
+	if !ok {
+		return nil, fmt.Errorf("serde %q cannot encode (decode only)", name)
+	}
+	return ser, nil
+}
+
+// parseJSONLRecord decodes one JSONL record. An absent or null key/value
+// produces a null record part (nil, not empty), as in the produce form.
+func parseJSONLRecord(line string, keySer, valueSer serde.Serializer) (api.ProduceRecord, error) {
+	var pl produceLine
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&pl); err != nil {
+		return api.ProduceRecord{}, fmt.Errorf("invalid record: %w", err)
+	}
+	rec := api.ProduceRecord{Partition: pl.Partition}
+	var err error
+	if rec.Key, err = encodeJSONField(pl.Key, keySer); err != nil {
+		return rec, fmt.Errorf("key: %w", err)
+	}
+	if rec.Value, err = encodeJSONField(pl.Value, valueSer); err != nil {
+		return rec, fmt.Errorf("value: %w", err)
+	}
+	if rec.Headers, err = parseJSONHeaders(pl.Headers); err != nil {
+		return rec, fmt.Errorf("headers: %w", err)
+	}
+	return rec, nil
+}
+
+// encodeJSONField serializes a key/value field: a JSON string is encoded as its
+// text, any other JSON value as its compact JSON text, and null as nil.
+func encodeJSONField(raw json.RawMessage, ser serde.Serializer) ([]byte, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var text string
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+	} else {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			return nil, err
+		}
+		text = buf.String()
+	}
+	return ser.Serialize(text)
+}
+
+// parseJSONHeaders accepts headers as an object {"k":"v"} (applied in key
+// order) or as a list [{"key":"k","value":"v"}] preserving order and duplicates.
+func parseJSONHeaders(raw json.RawMessage) ([]api.MessageHeader, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '[' {
+		var list []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		out := make([]api.MessageHeader, 0, len(list))
+		for _, h := range list {
+			out = append(out, api.MessageHeader{Key: h.Key, Value: h.Value})
+		}
+		return out, nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]api.MessageHeader, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, api.MessageHeader{Key: k, Value: m[k]})
+	}
+	return out, nil
+}
+
+// parseValueLine turns a plain line into a record. Without a separator the
+// whole line is the value and the key is null; a line lacking the separator
+// is rejected so keys are never silently dropped.
+func parseValueLine(line, sep string, keySer, valueSer serde.Serializer) (api.ProduceRecord, error) {
+	rec := api.ProduceRecord{}
+	value := line
+	if sep != "" {
+		idx := strings.Index(line, sep)
+		if idx < 0 {
+			return rec, fmt.Errorf("missing key separator %q", sep)
+		}
+		key, err := keySer.Serialize(line[:idx])
+		if err != nil {
+			return rec, fmt.Errorf("key: %w", err)
+		}
+		rec.Key = key
+		value = line[idx+len(sep):]
+	}
+	v, err := valueSer.Serialize(value)
+	if err != nil {
+		return rec, fmt.Errorf("value: %w", err)
+	}
+	rec.Value = v
+	return rec, nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/appconfig"
+	"github.com/Benny93/kafui/pkg/authz"
+	"github.com/Benny93/kafui/pkg/datasource"
+	"github.com/Benny93/kafui/pkg/serde"
+)
+
+// recordingDS captures produced records instead of sending them.
+type recordingDS struct {
+	api.KafkaDataSource
+	records []api.ProduceRecord
+}
+
+func (r *recordingDS) ProduceMessage(_ context.Context, _ string, rec api.ProduceRecord) error {
+	r.records = append(r.records, rec)
+	return nil
+}
+
+func TestRunProduce_JSONL(t *testing.T) {
+	ds := &recordingDS{KafkaDataSource: newGetMockDS()}
+	in := strings.NewReader(`{"key":"k1","value":{"a": 1},"headers":{"b":"2","a":"1"},"partition":2}
+
+{"key":null,"value":"plain","headers":[{"key":"x","value":"y"},{"key":"x","value":"z"}]}
+`)
+	n, err := runProduce(context.Background(), in, ds, "orders", produceOptions{
+		input: "jsonl", keySerde: serde.NameString, valueSerde: serde.NameString, partition: -1, headers: []string{"src=cli"},
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("runProduce = %d, %v", n, err)
+	}
+	first, second := ds.records[0], ds.records[1]
+	if string(first.Key) != "k1" || string(first.Value) != `{"a":1}` {
+		t.Errorf("first record = %q/%q", first.Key, first.Value)
+	}
+	if first.Partition == nil || *first.Partition != 2 {
+		t.Errorf("first partition = %v", first.Partition)
+	}
+	wantHeaders := []api.MessageHeader{{Key: "src", Value: "cli"}, {Key: "a", Value: "1"}, {Key: "b", Value: "2"}}
+	if len(first.Headers) != 3 || first.Headers[0] != wantHeaders[0] || first.Headers[1] != wantHeaders[1] || first.Headers[2] != wantHeaders[2] {
+		t.Errorf("first headers = %v", first.Headers)
+	}
+	if second.Key != nil || string(second.Value) != "plain" || second.Partition != nil {
+		t.Errorf("second record = %+v", second)
+	}
+	if len(second.Headers) != 3 || second.Headers[2].Value != "z" {
+		t.Errorf("second headers = %v", second.Headers)
+	}
+}
+
+func TestRunProduce_LinesWithSeparatorAndSerdes(t *testing.T) {
+	ds := &recordingDS{KafkaDataSource: newGetMockDS()}
+	in := strings.NewReader("7:a:b\n8:c\n")
+	n, err := runProduce(context.Background(), in, ds, "orders", produceOptions{
+		input: "lines", keySeparator: ":", keySerde: serde.NameLong, valueSerde: serde.NameString, partition: 1,
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("runProduce = %d, %v", n, err)
+	}
+	if got := int64(binary.BigEndian.Uint64(ds.records[0].Key)); got != 7 {
+		t.Errorf("key = %d, want 7", got)
+	}
+	if string(ds.records[0].Value) != "a:b" {
+		t.Errorf("value = %q, want split at the first separator", ds.records[0].Value)
+	}
+	if p := ds.records[1].Partition; p == nil || *p != 1 {
+		t.Errorf("partition = %v, want --partition default", p)
+	}
+}
+
+func TestRunProduce_Errors(t *testing.T) {
+	ds := &recordingDS{KafkaDataSource: newGetMockDS()}
+	cases := []struct {
+		name, input string
+		opts        produceOptions
+		want        string
+	}{
+		{"missing separator", "a:1\nnosep\n", produceOptions{input: "lines", keySeparator: ":"}, "line 2"},
+		{"bad json", "{not json}\n", produceOptions{input: "jsonl"}, "line 1"},
+		{"unknown field", `{"val":"x"}` + "\n", produceOptions{input: "jsonl"}, "invalid record"},
+		{"bad int", `{"value":"abc"}` + "\n", produceOptions{input: "jsonl", valueSerde: serde.NameInt}, "value"},
+		{"unknown serde", "", produceOptions{input: "jsonl", valueSerde: "nope"}, "nope"},
+		{"bad header", "", produceOptions{input: "jsonl", headers: []string{"novalue"}}, "--header"},
+		{"bad input", "", produceOptions{input: "xml"}, "unsupported input"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.opts.keySerde == "" {
+				c.opts.keySerde = serde.NameString
+			}
+			if c.opts.valueSerde == "" {
+				c.opts.valueSerde = serde.NameString
+			}
+			_, err := runProduce(context.Background(), strings.NewReader(c.input), ds, "orders", c.opts)
+			if err == nil || !strings.Contains(err.Error(), c.want) {
+				t.Errorf("err = %v, want containing %q", err, c.want)
+			}
+		})
+	}
+}
+
+func TestRunProduce_ReadOnlyDenied(t *testing.T) {
+	gate, err := authz.NewGate(appconfig.AuthzSettings{}, nil, true)
+	if err != nil {
+		t.Fatalf("gate: %v", err)
+	}
+	inner := &recordingDS{KafkaDataSource: newGetMockDS()}
+	ds := datasource.NewGuard(inner, gate, nil)
+	n, err := runProduce(context.Background(), bytes.NewBufferString(`{"value":"v"}`+"\n"), ds, "orders", produceOptions{
+		input: "jsonl", keySerde: serde.NameString, valueSerde: serde.NameString, partition: -1,
+	})
+	var ro api.ClusterReadOnlyError
+	if n != 0 || !errors.As(err, &ro) {
+		t.Fatalf("runProduce = %d, %v; want ClusterReadOnlyError", n, err)
+	}
+	if len(inner.records) != 0 {
+		t.Errorf("records reached the datasource despite read-only: %d", len(inner.records))
+	}
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// newReassignCommand adds `kafui reassign`, partition reassignment across
+// brokers: planning a decommission or rebalance, submitting it with an
+// optional replication throttle, and following or cancelling it.
+func newReassignCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reassign",
+		Short: "Partition reassignment: decommission brokers, rebalance, track and cancel moves",
+	}
+	cmd.AddCommand(
+		newReassignPlanCommand(api.ReassignDecommission),
+		newReassignPlanCommand(api.ReassignRebalance),
+		newReassignListCommand(),
+		newReassignCancelCommand(),
+		newReassignClearThrottlesCommand(),
+	)
+	return cmd
+}
+
+// newReassignPlanCommand builds `kafui reassign decommission|rebalance`.
+func newReassignPlanCommand(goal api.ReassignmentGoal) *cobra.Command {
+	var (
+		useMock bool
+		dryRun  bool
+		execute bool
+		req     = api.ReassignmentRequest{Goal: goal}
+		opts    api.ReassignmentOptions
+	)
+	short := "Plan (--dry-run, default) or submit (--execute) moving every replica off the given brokers"
+	if goal == api.ReassignRebalance {
+		short = "Plan (--dry-run, default) or submit (--execute) evening out replicas and preferred leaders"
+	}
+	cmd := &cobra.Command{
+		Use:   string(goal),
+		Short: short,
+		Long: short + ".\n" +
+			"Without --execute only the per-partition moves are printed. --throttle caps the\n" +
+			"replication traffic of the moved replicas; it stays in place until\n" +
+			"`kafui reassign clear-throttles` once the moves have finished.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun && execute {
+				return fmt.Errorf("--dry-run and --execute are mutually exclusive")
+			}
+			if goal == api.ReassignDecommission && len(req.Brokers) == 0 {
+				return fmt.Errorf("--broker is required")
+			}
+			if opts.ThrottleBytesPerSec < 0 {
+				return fmt.Errorf("--throttle must not be negative")
+			}
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runReassign(cmd.Context(), os.Stdout, ds, req, opts, execute)
+		},
+	}
+	f := cmd.Flags()
+	f.BoolVar(&useMock, "mock", false, "use the mock datasource")
+	if goal == api.ReassignDecommission {
+		f.Int32SliceVar(&req.Brokers, "broker", nil, "broker id to drain (repeatable)")
+	}
+	f.StringArrayVar(&req.Topics, "topic", nil, "limit the plan to this topic (repeatable; default every topic, internal ones only when decommissioning)")
+	f.Int64Var(&opts.ThrottleBytesPerSec, "throttle", 0, "replication throttle in bytes/sec for the moved replicas (0 = unthrottled)")
+	f.BoolVar(&dryRun, "dry-run", false, "only print the plan (default)")
+	f.BoolVar(&execute, "execute", false, "submit the planned moves")
+	return cmd
+}
+
+// runReassign plans req, prints the moves and, with execute, submits them.
+func runReassign(ctx context.Context, w io.Writer, ds api.KafkaDataSource, req api.ReassignmentRequest, opts api.ReassignmentOptions, execute bool) error {
+	plan, err := ds.PlanReassignment(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(plan.Moves) == 0 {
+		fmt.Fprintln(w, "Nothing to move.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOPIC\tPARTITION\tCURRENT\tTARGET\tADDING\tREMOVING")
+	for _, m := range plan.Moves {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			m.Topic, m.Partition, brokerList(m.Current), brokerList(m.Target), brokerList(m.Adding()), brokerList(m.Removing()))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !execute {
+		fmt.Fprintln(w, "Dry run: nothing submitted. Re-run with --execute to apply.")
+		return nil
+	}
+
+	if err := ds.ExecuteReassignment(ctx, plan, opts); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Submitted %d partition move(s). Follow them with `kafui reassign list`.\n", len(plan.Moves))
+	if opts.ThrottleBytesPerSec > 0 {
+		fmt.Fprintln(w, "Replication is throttled until `kafui reassign clear-throttles` is run.")
+	}
+	return nil
+}
+
+func newReassignListCommand() *cobra.Command {
+	var useMock bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the in-flight partition reassignments and their progress",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runReassignList(cmd.Context(), os.Stdout, ds)
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	return cmd
+}
+
+func runReassignList(ctx context.Context, w io.Writer, ds api.KafkaDataSource) error {
+	inFlight, err := ds.ListReassignments(ctx)
+	if err != nil {
+		return err
+	}
+	if len(inFlight) == 0 {
+		fmt.Fprintln(w, "No reassignments in progress.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOPIC\tPARTITION\tREPLICAS\tADDING\tREMOVING\tPROGRESS")
+	for _, r := range inFlight {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%.0f%%\n",
+			r.Topic, r.Partition, brokerList(r.Replicas), brokerList(r.Adding), brokerList(r.Removing), r.Progress*100)
+	}
+	return tw.Flush()
+}
+
+func newReassignCancelCommand() *cobra.Command {
+	var (
+		useMock bool
+		all     bool
+	)
+	cmd := &cobra.Command{
+		Use:   "cancel [<topic>:<partition>...]",
+		Short: "Cancel in-flight reassignments; the partitions keep their original replicas",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all == (len(args) > 0) {
+				return fmt.Errorf("specify either partitions or --all")
+			}
+			partitions, err := parseTopicPartitions(args)
+			if err != nil {
+				return err
+			}
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runReassignCancel(cmd.Context(), os.Stdout, ds, partitions)
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	cmd.Flags().BoolVar(&all, "all", false, "cancel every in-flight reassignment")
+	return cmd
+}
+
+// runReassignCancel cancels the given partitions, or every in-flight
+// reassignment when partitions is empty.
+func runReassignCancel(ctx context.Context, w io.Writer, ds api.KafkaDataSource, partitions []api.TopicPartition) error {
+	if len(partitions) == 0 {
+		inFlight, err := ds.ListReassignments(ctx)
+		if err != nil {
+			return err
+		}
+		for _, r := range inFlight {
+			partitions = append(partitions, api.TopicPartition{Topic: r.Topic, Partition: r.Partition})
+		}
+		if len(partitions) == 0 {
+			fmt.Fprintln(w, "No reassignments in progress.")
+			return nil
+		}
+	}
+	if err := ds.CancelReassignments(ctx, partitions); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Cancelled %d reassignment(s).\n", len(partitions))
+	return nil
+}
+
+// parseTopicPartitions parses <topic>:<partition> arguments.
+func parseTopicPartitions(args []string) ([]api.TopicPartition, error) {
+	out := make([]api.TopicPartition, 0, len(args))
+	for _, arg := range args {
+		i := strings.LastIndex(arg, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("%q: want <topic>:<partition>", arg)
+		}
+		p, err := strconv.ParseInt(arg[i+1:], 10, 32)
+		if err != nil || p < 0 {
+			return nil, fmt.Errorf("%q: invalid partition", arg)
+		}
+		out = append(out, api.TopicPartition{Topic: arg[:i], Partition: int32(p)})
+	}
+	return out, nil
+}
+
+func newReassignClearThrottlesCommand() *cobra.Command {
+	var useMock bool
+	cmd := &cobra.Command{
+		Use:   "clear-throttles [<topic>...]",
+		Short: "Remove the replication throttle rates from every broker and the throttled replicas from the topics",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			if err := ds.ClearReplicationThrottles(cmd.Context(), args); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout, "Replication throttles cleared.")
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	return cmd
+}
+
+// brokerList formats broker ids as a comma-separated list, "-" when empty.
+func brokerList(ids []int32) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(int(id))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+)
+
+func TestRunReassign_DryRunThenExecute(t *testing.T) {
+	ds := newGroupsMockDS()
+	ctx := context.Background()
+	req := api.ReassignmentRequest{Goal: api.ReassignRebalance}
+
+	var buf bytes.Buffer
+	if err := runReassign(ctx, &buf, ds, req, api.ReassignmentOptions{}, false); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Dry run") {
+		t.Errorf("dry run output missing notice:\n%s", buf.String())
+	}
+	if inFlight, _ := ds.ListReassignments(ctx); len(inFlight) != 0 {
+		t.Fatalf("dry run submitted %d move(s)", len(inFlight))
+	}
+
+	plan, err := ds.PlanReassignment(ctx, req)
+	if err != nil || len(plan.Moves) == 0 {
+		t.Fatalf("mock rebalance plan = %+v, %v; want moves", plan, err)
+	}
+	buf.Reset()
+	if err := runReassign(ctx, &buf, ds, req, api.ReassignmentOptions{ThrottleBytesPerSec: 1 << 20}, true); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if !strings.Contains(buf.String(), "clear-throttles") {
+		t.Errorf("execute output missing throttle hint:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := runReassignList(ctx, &buf, ds); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(buf.String(), plan.Moves[0].Topic) {
+		t.Errorf("list output missing %s:\n%s", plan.Moves[0].Topic, buf.String())
+	}
+
+	buf.Reset()
+	if err := runReassignCancel(ctx, &buf, ds, nil); err != nil {
+		t.Fatalf("cancel all: %v", err)
+	}
+	if inFlight, _ := ds.ListReassignments(ctx); len(inFlight) != 0 {
+		t.Errorf("%d reassignment(s) left after cancel", len(inFlight))
+	}
+}
+
+func TestRunReassign_DecommissionUnknownBroker(t *testing.T) {
+	var buf bytes.Buffer
+	req := api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{99}}
+	err := runReassign(context.Background(), &buf, newGroupsMockDS(), req, api.ReassignmentOptions{}, false)
+	var nf api.BrokerNotFoundError
+	if !errors.As(err, &nf) {
+		t.Errorf("err = %v, want BrokerNotFoundError", err)
+	}
+}
+
+func TestParseTopicPartitions(t *testing.T) {
+	got, err := parseTopicPartitions([]string{"orders:3", "a:b:0"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []api.TopicPartition{{Topic: "orders", Partition: 3}, {Topic: "a:b", Partition: 0}}
+	if len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for _, bad := range []string{"orders", ":1", "orders:x", "orders:-1"} {
+		if _, err := parseTopicPartitions([]string{bad}); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestReassignCommand_FlagValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  func() error
+	}{
+		{"decommission without broker", func() error {
+			c := newReassignPlanCommand(api.ReassignDecommission)
+			c.SetArgs([]string{"--mock"})
+			return c.Execute()
+		}},
+		{"dry-run and execute", func() error {
+			c := newReassignPlanCommand(api.ReassignRebalance)
+			c.SetArgs([]string{"--mock", "--dry-run", "--execute"})
+			return c.Execute()
+		}},
+		{"cancel without scope", func() error {
+			c := newReassignCancelCommand()
+			c.SetArgs([]string{"--mock"})
+			return c.Execute()
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.cmd(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -80,6 +80,13 @@ func CreateRootCommand(initFunc KafuiInitFunc) *cobra.Command {
 	rootCmd.AddCommand(newVersionCommand())
 	rootCmd.AddCommand(newHealthCommand())
 	rootCmd.AddCommand(newGetCommand())
+	rootCmd.AddCommand(newConsumeCommand())
+	rootCmd.AddCommand(newProduceCommand())
+	rootCmd.AddCommand(newTopicsCommand())
+	rootCmd.AddCommand(newACLsCommand())
+	rootCmd.AddCommand(newSchemasCommand())
+	rootCmd.AddCommand(newGroupsCommand())
+	rootCmd.AddCommand(newReassignCommand())
 
 	// Errors are reported by DoExecute (once, without a stack trace or usage
 	// dump); cobra's own printing is silenced to avoid a duplicate message.
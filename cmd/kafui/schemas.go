@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared/schemabackup"
+	"github.com/spf13/cobra"
+)
+
+// newSchemasCommand adds `kafui schemas export|plan|import`: schema registry
+// backup and migration between registries (see schemabackup).
+func newSchemasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schemas",
+		Short: "Back up a schema registry and import the backup into another registry",
+	}
+	cmd.AddCommand(newSchemasExportCommand(), newSchemasPlanCommand(), newSchemasImportCommand())
+	return cmd
+}
+
+func newSchemasExportCommand() *cobra.Command {
+	var useMock bool
+	cmd := &cobra.Command{
+		Use:   "export <dir|file.tar.gz>",
+		Short: "Write every subject with all versions, references, IDs and compatibility levels",
+		Long: "Export the active cluster's schema registry. A path ending in .tar.gz or .tgz\n" +
+			"is written as one archive; any other path as a new (or empty) directory.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runSchemasExport(os.Stdout, ds, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	return cmd
+}
+
+func newSchemasPlanCommand() *cobra.Command {
+	var useMock bool
+	cmd := &cobra.Command{
+		Use:   "plan <dir|file.tar.gz>",
+		Short: "Show what importing a schema backup would register and change",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			_, err = runSchemasPlan(os.Stdout, ds, args[0])
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	return cmd
+}
+
+func newSchemasImportCommand() *cobra.Command {
+	var useMock bool
+	cmd := &cobra.Command{
+		Use:   "import <dir|file.tar.gz>",
+		Short: "Import a schema backup into the active cluster's registry",
+		Long: "Register every backed-up version the target lacks, each subject's versions in\n" +
+			"order and referenced versions first, then set the compatibility levels.\n" +
+			"Subjects whose target mode is IMPORT keep their version numbers and schema IDs\n" +
+			"(put an empty registry in IMPORT mode first); otherwise the target assigns new\n" +
+			"ones and references are rewritten to match.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runSchemasImport(os.Stdout, ds, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	return cmd
+}
+
+func runSchemasExport(w io.Writer, ds api.KafkaDataSource, path string) error {
+	b, err := schemabackup.Export(ds)
+	if err != nil {
+		return err
+	}
+	if err := schemabackup.Write(path, b); err != nil {
+		return err
+	}
+	versions := 0
+	for _, s := range b.Subjects {
+		versions += len(s.Versions)
+	}
+	fmt.Fprintf(w, "Exported %d subjects (%d versions) to %s.\n", len(b.Subjects), versions, path)
+	return nil
+}
+
+// runSchemasPlan prints the import plan for the backup at path and returns it.
+func runSchemasPlan(w io.Writer, ds api.KafkaDataSource, path string) (schemabackup.Plan, error) {
+	b, err := schemabackup.Read(path)
+	if err != nil {
+		return schemabackup.Plan{}, err
+	}
+	plan, err := schemabackup.PlanImport(ds, b)
+	if err != nil {
+		return plan, err
+	}
+	writeSchemaPlan(w, plan)
+	return plan, nil
+}
+
+// runSchemasImport prints the plan and applies it.
+func runSchemasImport(w io.Writer, ds api.KafkaDataSource, path string) error {
+	plan, err := runSchemasPlan(w, ds, path)
+	if err != nil || plan.Empty() {
+		return err
+	}
+	if err := plan.Apply(ds); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "Import complete.")
+	return nil
+}
+
+// writeSchemaPlan renders the plan one change per line: "+" register,
+// "=" already present, "~" compatibility change.
+func writeSchemaPlan(w io.Writer, p schemabackup.Plan) {
+	for _, s := range p.Registers {
+		how := "new id and version"
+		if s.PreserveID {
+			how = "keeps id and version"
+		}
+		fmt.Fprintf(w, "+ %s v%d (id %d, %s)\n", s.Subject, s.Version.Version, s.Version.ID, how)
+	}
+	for _, s := range p.Skipped {
+		fmt.Fprintf(w, "= %s v%d (already present as v%d)\n", s.Subject, s.Version.Version, s.Existing)
+	}
+	for _, c := range p.CompatChanges {
+		target := "global"
+		if c.Subject != "" {
+			target = c.Subject
+		}
+		from := string(c.From)
+		if from == "" {
+			from = "(inherited)"
+		}
+		fmt.Fprintf(w, "~ %s: compatibility %s -> %s\n", target, from, c.To)
+	}
+	if p.Empty() {
+		fmt.Fprintln(w, "No changes. The registry matches the backup.")
+		return
+	}
+	fmt.Fprintf(w, "Plan: %d to register, %d already present, %d compatibility changes.\n", len(p.Registers), len(p.Skipped), len(p.CompatChanges))
+	if len(p.Registers) > 0 && !p.PreservesIDs() {
+		fmt.Fprintln(w, "Note: subjects not in IMPORT mode get new schema IDs and version numbers.")
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemasExportPlanImport_Mock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.tar.gz")
+	var out bytes.Buffer
+	if err := runSchemasExport(&out, newGetMockDS(), path); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "Exported ") {
+		t.Errorf("export output: %q", out.String())
+	}
+
+	// The same registry already holds every exported version.
+	out.Reset()
+	plan, err := runSchemasPlan(&out, newGetMockDS(), path)
+	if err != nil || !plan.Empty() || !strings.Contains(out.String(), "No changes.") {
+		t.Fatalf("plan against the source = %v:\n%s", err, out.String())
+	}
+
+	// A registry without orders-value needs it registered again.
+	ds := newGetMockDS()
+	if _, err := ds.DeleteSubject("orders-value", true); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runSchemasImport(&out, ds, path); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	for _, want := range []string{
+		"+ orders-value v1 (id 101, new id and version)",
+		"Plan: 3 to register,",
+		"Note: subjects not in IMPORT mode",
+		"Import complete.",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("import output missing %q:\n%s", want, out.String())
+		}
+	}
+	if versions, err := ds.GetSchemaVersions("orders-value"); err != nil || len(versions) != 3 {
+		t.Errorf("orders-value after import = %d versions, %v", len(versions), err)
+	}
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared/topicspec"
+	"github.com/spf13/cobra"
+)
+
+// newTopicsCommand adds `kafui topics plan|apply <file>`: declarative topic
+// management from a YAML spec (see topicspec.Spec).
+func newTopicsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "topics",
+		Short: "Declarative topic management from a YAML spec",
+	}
+	cmd.AddCommand(newTopicsPlanCommand(), newTopicsApplyCommand())
+	return cmd
+}
+
+func newTopicsPlanCommand() *cobra.Command {
+	var useMock bool
+	cmd := &cobra.Command{
+		Use:   "plan <file>",
+		Short: "Show the changes needed to reconcile the cluster with a topic spec",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			_, err = runTopicsPlan(os.Stdout, ds, args[0])
+			return err
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	return cmd
+}
+
+func newTopicsApplyCommand() *cobra.Command {
+	var (
+		useMock bool
+		opts    topicspec.ApplyOptions
+	)
+	cmd := &cobra.Command{
+		Use:   "apply <file>",
+		Short: "Reconcile the cluster with a topic spec",
+		Long: "Apply creates, config changes, partition increases and replication-factor changes.\n" +
+			"Unsafe changes (partition decreases, which re-create the topic, and deletions of\n" +
+			"topics missing from a pruning spec) are reported and skipped unless --allow-unsafe.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := newGuardedDataSource(useMock)
+			if err != nil {
+				return err
+			}
+			return runTopicsApply(os.Stdout, ds, args[0], opts)
+		},
+	}
+	cmd.Flags().BoolVar(&useMock, "mock", false, "use the mock datasource")
+	cmd.Flags().BoolVar(&opts.AllowUnsafe, "allow-unsafe", false, "apply partition decreases (re-creating the topic) and deletions; both lose data")
+	return cmd
+}
+
+// loadTopicSpec reads and validates the spec file.
+func loadTopicSpec(path string) (topicspec.Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return topicspec.Spec{}, err
+	}
+	return topicspec.Parse(data)
+}
+
+// runTopicsPlan prints the plan for the spec at path and returns it.
+func runTopicsPlan(w io.Writer, ds api.KafkaDataSource, path string) (topicspec.Plan, error) {
+	spec, err := loadTopicSpec(path)
+	if err != nil {
+		return topicspec.Plan{}, err
+	}
+	plan, err := topicspec.PlanTopics(ds, spec)
+	if err != nil {
+		return plan, err
+	}
+	writeTopicPlan(w, plan)
+	return plan, nil
+}
+
+// runTopicsApply prints the plan, applies it and reports the outcome. Skipped
+// unsafe changes make it fail so scripted runs notice the remaining drift.
+func runTopicsApply(w io.Writer, ds api.KafkaDataSource, path string, opts topicspec.ApplyOptions) error {
+	plan, err := runTopicsPlan(w, ds, path)
+	if err != nil || plan.Empty() {
+		return err
+	}
+	if err := plan.Apply(ds, opts); err != nil {
+		if !opts.AllowUnsafe && plan.HasUnsafeChanges() {
+			return fmt.Errorf("unsafe changes skipped (re-run with --allow-unsafe to apply them):\n%w", err)
+		}
+		return err
+	}
+	fmt.Fprintln(w, "Apply complete.")
+	return nil
+}
+
+// writeTopicPlan renders the plan one change per line: "+" create, "~" change,
+// "-" config reset, "!" unsafe.
+func writeTopicPlan(w io.Writer, p topicspec.Plan) {
+	if p.Empty() {
+		fmt.Fprintln(w, "No changes. Topics match the spec.")
+		return
+	}
+	for _, t := range p.Creates {
+		rf := "default"
+		if t.ReplicationFactor > 0 {
+			rf = fmt.Sprint(t.ReplicationFactor)
+		}
+		fmt.Fprintf(w, "+ create topic %s (partitions=%d, replication=%s%s)\n", t.Name, t.Partitions, rf, formatSpecConfigs(t.Configs))
+	}
+	for _, c := range p.ConfigChanges {
+		if c.To == nil {
+			fmt.Fprintf(w, "- %s: config %s reset to default (was %s)\n", c.Topic, c.Key, planValue(c.From, c.Sensitive))
+			continue
+		}
+		fmt.Fprintf(w, "~ %s: config %s %s -> %s\n", c.Topic, c.Key, planValue(c.From, c.Sensitive), planValue(c.To, c.Sensitive))
+	}
+	for _, c := range p.PartitionIncreases {
+		fmt.Fprintf(w, "~ %s: partitions %d -> %d\n", c.Topic, c.From, c.To)
+	}
+	for _, c := range p.ReplicationChanges {
+		fmt.Fprintf(w, "~ %s: replication factor %d -> %d\n", c.Topic, c.From, c.To)
+	}
+	for _, c := range p.PartitionDecreases {
+		fmt.Fprintf(w, "! %s: partitions %d -> %d (unsafe: re-creates the topic, deleting its data)\n", c.Topic, c.From, c.To)
+	}
+	for _, name := range p.Deletes {
+		fmt.Fprintf(w, "! delete topic %s (unsafe: not in the spec)\n", name)
+	}
+	changes := len(p.ConfigChanges) + len(p.PartitionIncreases) + len(p.ReplicationChanges)
+	fmt.Fprintf(w, "Plan: %d to create, %d to change, %d unsafe.\n", len(p.Creates), changes, len(p.PartitionDecreases)+len(p.Deletes))
+}
+
+// planValue renders a config value for the plan, hiding sensitive values.
+func planValue(v *string, sensitive bool) string {
+	switch {
+	case v == nil:
+		return "(unset)"
+	case sensitive:
+		return "(sensitive)"
+	}
+	return fmt.Sprintf("%q", *v)
+}
+
+func formatSpecConfigs(configs map[string]string) string {
+	if len(configs) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(configs))
+	for k := range configs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + configs[k]
+	}
+	return ", " + strings.Join(parts, ", ")
+}
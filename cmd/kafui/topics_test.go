@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared/topicspec"
+)
+
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "topics.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTopicsPlanApply_Mock(t *testing.T) {
+	ds := newGetMockDS()
+	if err := ds.CreateTopic("spec-shrink", 4, 1, nil); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	defer ds.DeleteTopic("spec-shrink")
+	defer ds.DeleteTopic("spec-new")
+	path := writeSpec(t, `
+topics:
+  - name: spec-new
+    partitions: 3
+    configs:
+      retention.ms: "1000"
+  - name: spec-shrink
+    partitions: 2
+    configs:
+      retention.ms: "5000"
+`)
+
+	var buf bytes.Buffer
+	if _, err := runTopicsPlan(&buf, ds, path); err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"+ create topic spec-new (partitions=3, replication=default, retention.ms=1000)",
+		`~ spec-shrink: config retention.ms "604800000" -> "5000"`,
+		"! spec-shrink: partitions 4 -> 2 (unsafe",
+		"Plan: 1 to create, 1 to change, 1 unsafe.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plan output missing %q:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	err := runTopicsApply(&buf, ds, path, topicspec.ApplyOptions{})
+	var decrease api.PartitionDecreaseError
+	if !errors.As(err, &decrease) || decrease.TopicName != "spec-shrink" {
+		t.Fatalf("apply err = %v, want PartitionDecreaseError for spec-shrink", err)
+	}
+	topics, _ := ds.GetTopics()
+	if topics["spec-new"].NumPartitions != 3 || topics["spec-shrink"].NumPartitions != 4 {
+		t.Errorf("after safe apply: new=%d shrink=%d", topics["spec-new"].NumPartitions, topics["spec-shrink"].NumPartitions)
+	}
+
+	buf.Reset()
+	if err := runTopicsApply(&buf, ds, path, topicspec.ApplyOptions{AllowUnsafe: true}); err != nil {
+		t.Fatalf("unsafe apply: %v", err)
+	}
+	topics, _ = ds.GetTopics()
+	if got := topics["spec-shrink"]; got.NumPartitions != 2 || got.ConfigEntries["retention.ms"] == nil || *got.ConfigEntries["retention.ms"] != "5000" {
+		t.Errorf("re-created topic = %+v", got)
+	}
+
+	buf.Reset()
+	if _, err := runTopicsPlan(&buf, ds, path); err != nil || !strings.Contains(buf.String(), "No changes") {
+		t.Errorf("plan after apply = %q, %v", buf.String(), err)
+	}
+}
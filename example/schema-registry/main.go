@@ -20,15 +20,14 @@ import (
 
 func main() {
 	// Load ~/.kaf/config and set the active cluster (same as the CLI does
-	// via cobra.OnInitialize).
-	if err := kafds.InitFromConfig(""); err != nil {
+	// via Init, but failing on a missing config).
+	ds := kafds.NewKafkaDataSourceKaf()
+	if err := ds.InitFromConfig(""); err != nil {
 		fmt.Fprintf(os.Stderr, "❌  Failed to load kaf config: %v\n", err)
 		fmt.Fprintf(os.Stderr, "    Make sure ~/.kaf/config exists and is readable.\n")
 		os.Exit(1)
 	}
 
-	ds := kafds.NewKafkaDataSourceKaf()
-
 	// Show active cluster name and its schema registry URL.
 	activeCtx := ds.GetContext()
 	fmt.Printf("Active cluster: %s\n", activeCtx)
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -104,10 +105,36 @@ type ConsumeFlags struct {
 	SeekOffset    *int64     // required for SeekFromOffset / SeekToOffset
 	SeekTimestamp *time.Time // required for SeekFromTimestamp / SeekToTimestamp
 	Partitions    []int32    // empty = all partitions
+
+	// Isolation selects whether records of aborted (and still open)
+	// transactions are returned. Empty means read_uncommitted, Kafka's default.
+	Isolation IsolationLevel
+}
+
+// IsolationLevel mirrors the consumer isolation.level setting.
+type IsolationLevel string
+
+const (
+	// IsolationReadUncommitted returns every record, including those written
+	// by transactions that were later aborted.
+	IsolationReadUncommitted IsolationLevel = "read_uncommitted"
+	// IsolationReadCommitted returns only records of committed transactions
+	// and stops at the last stable offset.
+	IsolationReadCommitted IsolationLevel = "read_committed"
+)
+
+// ReadCommitted reports whether the flags request read_committed isolation.
+func (f ConsumeFlags) ReadCommitted() bool {
+	return f.Isolation == IsolationReadCommitted
 }
 
 // Validate checks that offset/timestamp seek modes carry their required value.
 func (f ConsumeFlags) Validate() error {
+	switch f.Isolation {
+	case "", IsolationReadUncommitted, IsolationReadCommitted:
+	default:
+		return InvalidSeekError{Mode: string(f.Seek), Reason: fmt.Sprintf("unknown isolation level %q", f.Isolation)}
+	}
 	switch f.Seek {
 	case "", SeekNewest, SeekOldest, SeekLive:
 		return nil
@@ -143,6 +170,81 @@ type ProduceRecord struct {
 	Partition *int32 // nil = let the partitioner choose
 }
 
+// ProducerAcks selects how many replica acknowledgements a produce waits for.
+type ProducerAcks string
+
+const (
+	AcksAll    ProducerAcks = "all"    // every in-sync replica (default)
+	AcksLeader ProducerAcks = "leader" // the partition leader only
+	AcksNone   ProducerAcks = "none"   // fire and forget; offsets are unknown
+)
+
+// CompressionCodec names a producer compression codec.
+type CompressionCodec string
+
+const (
+	CompressionNone   CompressionCodec = "none"
+	CompressionGzip   CompressionCodec = "gzip"
+	CompressionSnappy CompressionCodec = "snappy"
+	CompressionLZ4    CompressionCodec = "lz4"
+	CompressionZstd   CompressionCodec = "zstd"
+)
+
+// CompressionCodecs lists the supported codecs in display order.
+var CompressionCodecs = []CompressionCodec{CompressionNone, CompressionGzip, CompressionSnappy, CompressionLZ4, CompressionZstd}
+
+// ProducerOptions tunes the producer used by ProduceBatch. The zero value
+// matches ProduceMessage: acks=all, no compression, no idempotence.
+type ProducerOptions struct {
+	Acks        ProducerAcks     // "" = all
+	Compression CompressionCodec // "" = none
+	// Idempotent enables the idempotent producer (requires acks=all).
+	Idempotent bool
+	// TransactionalID, when set, sends the whole batch in one transaction:
+	// either every record is committed or none is. Implies Idempotent.
+	TransactionalID string
+	// Linger is how long the producer waits to fill a batch (0 = send at once).
+	Linger time.Duration
+	// BatchSize is the byte size that triggers a send (0 = producer default).
+	BatchSize int
+}
+
+// Transactional reports whether the batch is sent in a transaction.
+func (o ProducerOptions) Transactional() bool { return o.TransactionalID != "" }
+
+// Validate rejects unknown enum values and contradictory settings.
+func (o ProducerOptions) Validate() error {
+	switch o.Acks {
+	case "", AcksAll, AcksLeader, AcksNone:
+	default:
+		return InvalidConfigError{Key: "acks", Reason: fmt.Sprintf("unknown acks %q (all|leader|none)", o.Acks)}
+	}
+	switch o.Compression {
+	case "", CompressionNone, CompressionGzip, CompressionSnappy, CompressionLZ4, CompressionZstd:
+	default:
+		return InvalidConfigError{Key: "compression", Reason: fmt.Sprintf("unknown codec %q", o.Compression)}
+	}
+	if (o.Idempotent || o.Transactional()) && o.Acks != "" && o.Acks != AcksAll {
+		return InvalidConfigError{Key: "acks", Reason: "idempotent and transactional producers require acks=all"}
+	}
+	if o.Linger < 0 {
+		return InvalidConfigError{Key: "linger", Reason: "must not be negative"}
+	}
+	if o.BatchSize < 0 {
+		return InvalidConfigError{Key: "batch.size", Reason: "must not be negative"}
+	}
+	return nil
+}
+
+// ProduceResult reports where one record of a ProduceBatch landed. Err is set
+// (and Partition/Offset are meaningless) when the record was not written.
+// Offset is -1 when acks=none.
+type ProduceResult struct {
+	Partition int32
+	Offset    int64
+	Err       error
+}
+
 type ConsumerGroup struct {
 	Name      string
 	State     string
@@ -247,6 +349,9 @@ type Schema struct {
 	// to the global level when the subject has no own setting). Empty when not
 	// resolved. (SR-6)
 	Compatibility string `json:"compatibility,omitempty"`
+	// References lists the subjects this version imports; only filled where the
+	// full version record was fetched (e.g. by RegisterSchema).
+	References []SchemaReference `json:"references,omitempty"`
 }
 
 type SchemaInfo struct {
@@ -315,11 +420,26 @@ type KafkaDataSource interface {
 	// The group must be inactive (Empty or Dead); otherwise a GroupNotEmptyError
 	// is returned. Invalid requests yield an InvalidOffsetResetError.
 	ResetConsumerGroupOffsets(ctx context.Context, req OffsetResetRequest) error
+	// PlanConsumerGroupOffsetReset resolves the per-partition targets of a reset
+	// exactly as ResetConsumerGroupOffsets would, without committing anything.
+	// Unlike the reset it does not require the group to be inactive.
+	PlanConsumerGroupOffsetReset(ctx context.Context, req OffsetResetRequest) ([]PartitionResetPlan, error)
+	// GetRecordTimestamps returns the timestamp of the record at each given
+	// partition offset of the topic (the next record when the offset itself was
+	// compacted away). Offsets at or past the log end have no record and are
+	// absent from the result.
+	GetRecordTimestamps(ctx context.Context, topic string, offsets map[int32]int64) (map[int32]time.Time, error)
 	ConsumeTopic(ctx context.Context, topicName string, flags ConsumeFlags, handleMessage MessageHandlerFunc, onError func(err any)) error
 	// ProduceMessage produces a single record to the topic. It validates that
 	// the topic exists and that any explicit partition is in range, returning a
 	// TopicNotFoundError, PartitionError, or ProduceError respectively.
 	ProduceMessage(ctx context.Context, topic string, rec ProduceRecord) error
+	// ProduceBatch produces several records with the given producer options and
+	// returns one result per record, in input order. It validates the topic and
+	// partitions like ProduceMessage before sending anything. When any record
+	// fails it also returns a ProduceError; in a transactional batch a failure
+	// aborts the transaction and every result carries the error.
+	ProduceBatch(ctx context.Context, topic string, recs []ProduceRecord, opts ProducerOptions) ([]ProduceResult, error)
 	// GetTopicMessageCounts fetches approximate message counts for a set of topics.
 	// It accepts a map of topicName → numPartitions (already known from GetTopics).
 	// Counts are computed as sum(newestOffset - oldestOffset) across all partitions.
@@ -342,6 +462,12 @@ type KafkaDataSource interface {
 	// SubjectNotFoundError for an unknown subject and a
 	// SchemaRegistryNotConfiguredError when no registry is configured. (SR-4)
 	GetSchemaVersions(subject string) ([]SchemaVersion, error)
+	// GetSchemaVersion fetches one version of a subject with its text and
+	// references. Pass version=0 to retrieve the latest version.
+	GetSchemaVersion(subject string, version int) (SchemaVersion, error)
+	// GetSchemaReferencedBy lists the subject versions whose schemas reference
+	// the given version (0 = latest).
+	GetSchemaReferencedBy(subject string, version int) ([]SubjectVersion, error)
 	// GetGlobalCompatibility returns the registry's global compatibility level. (SR-5)
 	GetGlobalCompatibility() (CompatibilityLevel, error)
 	// GetSubjectCompatibility returns a subject's effective compatibility level.
@@ -350,11 +476,23 @@ type KafkaDataSource interface {
 	GetSubjectCompatibility(subject string) (level CompatibilityLevel, isSubjectSpecific bool, err error)
 	// RegisterSchema registers a new schema under the subject, creating the subject
 	// if new or a new version otherwise. schemaType may be empty for AVRO. It maps
-	// a 409 to SchemaIncompatibleError and a 422 to SchemaValidationError. (SR-7)
-	RegisterSchema(subject, schemaText, schemaType string) (Schema, error)
-	// CheckSchemaCompatibility tests a candidate schema against the subject's latest
-	// version without registering it, returning the verbose messages on failure. (SR-8)
-	CheckSchemaCompatibility(subject, schemaText, schemaType string) (compatible bool, messages []string, err error)
+	// a 409 to SchemaIncompatibleError and a 422 to SchemaValidationError.
+	// references may be nil; each must resolve to an existing subject version.
+	// (SR-7)
+	RegisterSchema(subject, schemaText, schemaType string, references []SchemaReference) (Schema, error)
+	// ImportSchema registers version.Schema under the subject with the given
+	// version number and schema ID, as a registry migration does. The registry
+	// or subject must be in IMPORT mode; otherwise it fails with
+	// SchemaModeError.
+	ImportSchema(subject string, version SchemaVersion) (Schema, error)
+	// CheckSchemaCompatibility tests a candidate schema (with its references)
+	// against the subject's latest version without registering it, returning the
+	// verbose messages on failure. (SR-8)
+	CheckSchemaCompatibility(subject, schemaText, schemaType string, references []SchemaReference) (compatible bool, messages []string, err error)
+	// CheckSchemaCompatibilityWithVersion tests a candidate schema against one
+	// version of the subject under the subject's compatibility level. Checking
+	// each prior version in turn is how a *_TRANSITIVE level is evaluated.
+	CheckSchemaCompatibilityWithVersion(subject string, version int, schemaText, schemaType string, references []SchemaReference) (compatible bool, messages []string, err error)
 	// DeleteSubject deletes all versions of a subject. permanent=true performs a
 	// hard delete (requires a prior soft delete). It returns the deleted version
 	// numbers. (SR-9)
@@ -368,6 +506,26 @@ type KafkaDataSource interface {
 	// SetSubjectCompatibility sets a subject's compatibility level. It validates
 	// the level before making any HTTP call. (SR-10)
 	SetSubjectCompatibility(subject string, level CompatibilityLevel) error
+	// GetDeletedSubjects lists subjects whose versions are all soft-deleted
+	// (visible only via the registry's deleted=true listing).
+	GetDeletedSubjects() ([]string, error)
+	// GetDeletedSchemaVersions lists the soft-deleted versions of a subject,
+	// ascending, with text and references. Active versions are not included.
+	GetDeletedSchemaVersions(subject string) ([]SchemaVersion, error)
+	// RestoreSchemaVersion re-registers a soft-deleted version's text, type and
+	// references under its subject. The registry assigns the next version
+	// number; a SchemaVersionNotFoundError means the version is not
+	// soft-deleted.
+	RestoreSchemaVersion(subject string, version int) (Schema, error)
+	// GetGlobalMode returns the registry's mode.
+	GetGlobalMode() (SchemaMode, error)
+	// GetSubjectMode returns a subject's effective mode, falling back to the
+	// global mode with isSubjectSpecific=false.
+	GetSubjectMode(subject string) (mode SchemaMode, isSubjectSpecific bool, err error)
+	// SetGlobalMode sets the registry's mode.
+	SetGlobalMode(mode SchemaMode) error
+	// SetSubjectMode sets a subject's mode.
+	SetSubjectMode(subject string, mode SchemaMode) error
 	// GetACLs returns all ACL bindings for the current cluster.
 	// Returns an empty slice (not an error) when the broker returns no ACLs
 	// or the connected user lacks the DESCRIBE ACL on cluster resources.
@@ -390,6 +548,31 @@ type KafkaDataSource interface {
 	// (absent properties are cleared); an empty/nil map deletes the entity.
 	// A QuotaValidationError is returned when no entity identifier is set.
 	AlterClientQuotas(entity ClientQuotaEntity, quotas map[string]float64) error
+	// DescribeUserScramCredentials returns the SCRAM users and their configured
+	// mechanisms, ordered by name. A nil/empty users slice describes every user.
+	DescribeUserScramCredentials(users []string) ([]ScramUser, error)
+	// UpsertUserScramCredentials creates or rotates SCRAM credentials. Every
+	// upsert is validated first; an invalid one yields a ScramValidationError
+	// and nothing is sent.
+	UpsertUserScramCredentials(upserts []ScramCredentialUpsert) error
+	// DeleteUserScramCredentials removes SCRAM credentials, one mechanism per
+	// deletion.
+	DeleteUserScramCredentials(deletions []ScramCredentialDeletion) error
+	// DescribeDelegationTokens returns the delegation tokens owned by the given
+	// principals (every token the caller may describe when owners is empty),
+	// ordered by expiry.
+	DescribeDelegationTokens(owners []string) ([]DelegationToken, error)
+	// CreateDelegationToken creates a token for the connected principal. The
+	// returned token carries its HMAC. Tokens cannot be created over a
+	// connection that is itself authenticated with a delegation token.
+	CreateDelegationToken(req CreateDelegationTokenRequest) (DelegationToken, error)
+	// RenewDelegationToken moves a token's expiry to period from now (bounded
+	// by its max time; period <= 0 uses the broker's renew interval) and
+	// returns the new expiry.
+	RenewDelegationToken(token DelegationToken, period time.Duration) (time.Time, error)
+	// ExpireDelegationToken moves a token's expiry to period from now; a
+	// period <= 0 expires it immediately. It returns the new expiry.
+	ExpireDelegationToken(token DelegationToken, period time.Duration) (time.Time, error)
 	// GetMessageSchemaInfo retrieves schema information for a message's key and value
 	// Returns nil for non-Avro messages or when schema information is not available
 	GetMessageSchemaInfo(keySchemaID, valueSchemaID string) (*MessageSchemaInfo, error)
@@ -434,6 +617,32 @@ type KafkaDataSource interface {
 	// GetBrokerMetrics returns a JSON metrics snapshot for a broker, or a
 	// MetricsNotAvailableError when metrics collection is not available.
 	GetBrokerMetrics(brokerID int32) (string, error)
+	// DescribeQuorum returns the KRaft controller quorum of the active cluster.
+	// ZooKeeper-based clusters (and KRaft clusters older than 3.3, whose brokers
+	// do not forward DescribeQuorum) yield a NotSupportedError.
+	DescribeQuorum(ctx context.Context) (QuorumInfo, error)
+	// PlanReassignment computes, without submitting, a balanced partition
+	// reassignment: draining brokers (ReassignDecommission) or evening out
+	// replicas and preferred leaders (ReassignRebalance).
+	PlanReassignment(ctx context.Context, req ReassignmentRequest) (ReassignmentPlan, error)
+	// ExecuteReassignment submits a plan's moves, applying the replication
+	// throttle first when opts asks for one.
+	ExecuteReassignment(ctx context.Context, plan ReassignmentPlan, opts ReassignmentOptions) error
+	// ListReassignments returns the in-flight partition reassignments of the
+	// active cluster with their catch-up progress.
+	ListReassignments(ctx context.Context) ([]PartitionReassignment, error)
+	// CancelReassignments reverts the in-flight reassignments of the given
+	// partitions to their original replicas.
+	CancelReassignments(ctx context.Context, partitions []TopicPartition) error
+	// ClearReplicationThrottles removes the replication throttle rates from
+	// every broker and the throttled-replica lists from the given topics.
+	ClearReplicationThrottles(ctx context.Context, topics []string) error
+	// DescribeLeadership returns the leader, replicas and ISR of every partition
+	// of the given topics (every topic when empty), sorted.
+	DescribeLeadership(ctx context.Context, topics []string) ([]PartitionLeadership, error)
+	// ElectLeaders triggers a preferred or unclean leader election and returns
+	// each partition's outcome; partitions that needed no election succeed.
+	ElectLeaders(ctx context.Context, req ElectLeadersRequest) ([]ElectionResult, error)
 
 	// --- Topic administration (TP-2..TP-11) ---
 
@@ -466,6 +675,13 @@ type KafkaDataSource interface {
 	// -1 purges all partitions. It returns a CleanupPolicyError for compact-only
 	// topics.
 	PurgeTopicMessages(name string, partition int32) error
+	// DeleteRecordsBefore deletes the records below per-partition offsets, or
+	// older than a timestamp (resolved per partition to the first offset at or
+	// after it). Partitions with nothing to delete are reported with Deleted 0.
+	// It returns an InvalidDeleteRecordsError for a malformed request or an
+	// offset past the end of its partition, and a CleanupPolicyError for
+	// compact-only topics.
+	DeleteRecordsBefore(ctx context.Context, req DeleteRecordsRequest) ([]RecordDeletion, error)
 	// RecreateTopic deletes and re-creates a topic preserving its partition
 	// count, replication factor, and non-default configs.
 	RecreateTopic(name string) error
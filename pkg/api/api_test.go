@@ -1,8 +1,10 @@
 package api
 
 import (
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // TestMessageHeader tests the MessageHeader struct
@@ -294,4 +296,33 @@ func BenchmarkMessageCreation(b *testing.B) {
 			},
 		}
 	}
-}
\ No newline at end of file
+}
+func TestProducerOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ProducerOptions
+		wantErr bool
+	}{
+		{"zero value", ProducerOptions{}, false},
+		{"leader acks with gzip", ProducerOptions{Acks: AcksLeader, Compression: CompressionGzip}, false},
+		{"transactional", ProducerOptions{TransactionalID: "tx"}, false},
+		{"unknown acks", ProducerOptions{Acks: "most"}, true},
+		{"unknown codec", ProducerOptions{Compression: "brotli"}, true},
+		{"idempotent without acks=all", ProducerOptions{Idempotent: true, Acks: AcksNone}, true},
+		{"transactional without acks=all", ProducerOptions{TransactionalID: "tx", Acks: AcksLeader}, true},
+		{"negative linger", ProducerOptions{Linger: -time.Millisecond}, true},
+		{"negative batch size", ProducerOptions{BatchSize: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			var ce InvalidConfigError
+			if tt.wantErr && !errors.As(err, &ce) {
+				t.Errorf("Validate() = %v, want InvalidConfigError", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -34,6 +37,74 @@ func SortMessages(msgs []Message, descending bool) {
 	})
 }
 
+// ---- MSG-21: seek flags from user input ------------------------------------
+
+// ParseSeekTime parses an absolute RFC3339 timestamp or a relative duration such
+// as "-1h" / "-30m" (relative to now).
+func ParseSeekTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("timestamp is required")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q (use RFC3339 like 2006-01-02T15:04:05Z or a relative offset like -1h)", s)
+}
+
+// BuildSeekFlags builds ConsumeFlags for the chosen seek mode and value.
+// value carries the offset (integer) or timestamp (RFC3339/relative) for the
+// modes that need one; count is the page size. It returns a validated set of
+// flags (MSG-21). The topic page's seek dialog and `kafui consume` share it so
+// both browse with identical semantics.
+func BuildSeekFlags(mode, value string, count int, partitions []int32) (ConsumeFlags, error) {
+	f := ConsumeFlags{
+		LimitMessages: int64(count),
+		Partitions:    partitions,
+	}
+	switch SeekMode(mode) {
+	case SeekNewest, "":
+		f.Seek = SeekNewest
+		f.Follow = false
+		f.Tail = int32(count)
+		f.OffsetFlag = "latest"
+	case SeekOldest:
+		f.Seek = SeekOldest
+		f.Follow = false
+		f.OffsetFlag = "oldest"
+	case SeekLive:
+		f.Seek = SeekLive
+		f.Follow = true
+		f.OffsetFlag = "latest"
+		f.LimitMessages = 0 // tailing applies no page limit
+	case SeekFromOffset, SeekToOffset:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return f, fmt.Errorf("offset must be an integer: %q", value)
+		}
+		f.Seek = SeekMode(mode)
+		f.SeekOffset = &n
+		f.OffsetFlag = strconv.FormatInt(n, 10) // legacy numeric offset path
+	case SeekFromTimestamp, SeekToTimestamp:
+		t, err := ParseSeekTime(value)
+		if err != nil {
+			return f, err
+		}
+		f.Seek = SeekMode(mode)
+		f.SeekTimestamp = &t
+		f.OffsetFlag = "latest"
+	default:
+		return f, fmt.Errorf("unknown seek mode %q", mode)
+	}
+	if err := f.Validate(); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
 // ---- MSG-8: page size clamping ---------------------------------------------
 
 const (
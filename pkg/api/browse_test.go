@@ -31,6 +31,9 @@ func TestConsumeFlagsValidate(t *testing.T) {
 		{"from-timestamp missing", ConsumeFlags{Seek: SeekFromTimestamp}, true},
 		{"to-timestamp missing", ConsumeFlags{Seek: SeekToTimestamp}, true},
 		{"unknown mode", ConsumeFlags{Seek: SeekMode("bogus")}, true},
+		{"read_committed", ConsumeFlags{Seek: SeekNewest, Isolation: IsolationReadCommitted}, false},
+		{"read_uncommitted", ConsumeFlags{Isolation: IsolationReadUncommitted}, false},
+		{"unknown isolation", ConsumeFlags{Isolation: IsolationLevel("serializable")}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
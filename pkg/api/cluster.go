@@ -1,6 +1,9 @@
 package api
 
-import "slices"
+import (
+	"slices"
+	"time"
+)
 
 // ClusterStatus is the health status of a cluster as tracked by the background collector.
 type ClusterStatus string
@@ -38,6 +41,7 @@ type ClusterOverview struct {
 	MessagesInPerSec     float64
 	ReadOnly             bool
 	Version              string
+	CoordinationType     string // "kraft", "zookeeper", or "unknown"; "" until collected
 	Capabilities         []Capability
 }
 
@@ -60,6 +64,49 @@ type ClusterStatistics struct {
 	DiskUsage                 []BrokerDiskUsage
 	Version                   string
 	CoordinationType          string // "kraft", "zookeeper", or "unknown"
+	// Quorum is the KRaft controller quorum; nil unless CoordinationType is
+	// "kraft" and the cluster answered DescribeQuorum.
+	Quorum *QuorumInfo
+}
+
+// QuorumInfo describes the KRaft metadata quorum (__cluster_metadata-0) as
+// reported by DescribeQuorum.
+type QuorumInfo struct {
+	LeaderID      int32
+	LeaderEpoch   int32
+	HighWatermark int64
+	Voters        []QuorumReplica
+	Observers     []QuorumReplica // brokers replicating the metadata log
+}
+
+// QuorumReplica is one voter's or observer's replication state. Lag is the
+// distance in records behind the leader's log end offset. The timestamps are
+// zero when the cluster predates DescribeQuorum v1 (Kafka 3.3).
+type QuorumReplica struct {
+	ReplicaID    int32
+	LogEndOffset int64
+	Lag          int64
+	LastFetch    time.Time
+	LastCaughtUp time.Time
+}
+
+// Role returns "leader", "voter" or "observer" for replicaID, or "" when the
+// replica is not part of the quorum.
+func (q QuorumInfo) Role(replicaID int32) string {
+	if replicaID == q.LeaderID {
+		return "leader"
+	}
+	for _, v := range q.Voters {
+		if v.ReplicaID == replicaID {
+			return "voter"
+		}
+	}
+	for _, o := range q.Observers {
+		if o.ReplicaID == replicaID {
+			return "observer"
+		}
+	}
+	return ""
 }
 
 // ValidationResult is the outcome of probing one component of a cluster connection.
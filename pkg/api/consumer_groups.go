@@ -72,6 +72,13 @@ const (
 	OffsetResetLatest    OffsetResetMode = "latest"
 	OffsetResetTimestamp OffsetResetMode = "timestamp"
 	OffsetResetExplicit  OffsetResetMode = "explicit"
+	OffsetResetShiftBy   OffsetResetMode = "shift-by"
+	OffsetResetDuration  OffsetResetMode = "by-duration"
+	// OffsetResetCurrent re-commits the current position clamped into the
+	// partition's offset range (repairing commits that fell off the log start);
+	// uncommitted partitions get the log end.
+	OffsetResetCurrent   OffsetResetMode = "to-current"
+	OffsetResetFromGroup OffsetResetMode = "copy-from-group"
 )
 
 // OffsetResetRequest describes a consumer-group offset reset. An empty
@@ -83,4 +90,64 @@ type OffsetResetRequest struct {
 	Partitions       []int32
 	Timestamp        *time.Time      // required for OffsetResetTimestamp
 	PartitionOffsets map[int32]int64 // required for OffsetResetExplicit
+	// ShiftBy moves the committed offset by N records (negative rewinds) for
+	// OffsetResetShiftBy. Partitions without a committed offset shift from the
+	// log end. The result is clamped into the partition's offset range.
+	ShiftBy int64
+	// Duration rewinds to the first offset at or after now-Duration for
+	// OffsetResetDuration (resolved like OffsetResetTimestamp). Must be > 0.
+	Duration time.Duration
+	// SourceGroupID is the group whose committed offsets OffsetResetFromGroup
+	// copies. Partitions the source group has not committed are left out.
+	SourceGroupID string
+	// Planned holds targets already resolved by PlanConsumerGroupOffsetReset.
+	// When set, ResetConsumerGroupOffsets commits exactly these instead of
+	// resolving Mode again, so a previewed reset cannot drift; Mode and
+	// SourceGroupID still describe the reset for authorization and audit.
+	// Every entry must be a partition of the reset within its current offset
+	// range, otherwise the reset fails with InvalidOffsetResetError.
+	Planned map[int32]int64
+}
+
+// PartitionResetPlan is the dry-run outcome of an offset reset for one
+// partition: where the group is, where it would be, and the partition's
+// offset range the target was clamped into.
+type PartitionResetPlan struct {
+	Topic     string
+	Partition int32
+	// Current is the committed offset, nil when the group has none.
+	Current  *int64
+	Target   int64
+	LogStart int64
+	LogEnd   int64
+}
+
+// CurrentLag is LogEnd - Current, nil when there is no committed offset.
+func (p PartitionResetPlan) CurrentLag() *int64 {
+	if p.Current == nil {
+		return nil
+	}
+	lag := p.LogEnd - *p.Current
+	if lag < 0 {
+		lag = 0
+	}
+	return &lag
+}
+
+// TargetLag is the lag the group has right after the reset.
+func (p PartitionResetPlan) TargetLag() int64 {
+	if p.LogEnd < p.Target {
+		return 0
+	}
+	return p.LogEnd - p.Target
+}
+
+// LagDelta is TargetLag - CurrentLag: positive when the reset makes the group
+// re-consume records, negative when it skips them. Without a committed offset
+// the whole target lag is new.
+func (p PartitionResetPlan) LagDelta() int64 {
+	if cur := p.CurrentLag(); cur != nil {
+		return p.TargetLag() - *cur
+	}
+	return p.TargetLag()
 }
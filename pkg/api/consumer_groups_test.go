@@ -79,3 +79,22 @@ func TestOffsetResetRequestZeroValues(t *testing.T) {
 		t.Errorf("CommittedOffset/Lag should be nil by default")
 	}
 }
+
+func TestPartitionResetPlanLag(t *testing.T) {
+	cur := int64(90)
+	p := PartitionResetPlan{Current: &cur, Target: 10, LogStart: 0, LogEnd: 100}
+	if got := *p.CurrentLag(); got != 10 {
+		t.Errorf("CurrentLag() = %d, want 10", got)
+	}
+	if got := p.LagDelta(); got != 80 {
+		t.Errorf("LagDelta() = %d, want 80", got)
+	}
+	p.Target = 100
+	if got := p.LagDelta(); got != -10 {
+		t.Errorf("LagDelta() skipping to the end = %d, want -10", got)
+	}
+	p.Current = nil
+	if p.CurrentLag() != nil || p.LagDelta() != 0 {
+		t.Errorf("uncommitted: CurrentLag() = %v, LagDelta() = %d", p.CurrentLag(), p.LagDelta())
+	}
+}
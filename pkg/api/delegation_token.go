@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/base64"
+	"time"
+)
+
+// DelegationToken is a Kafka delegation token. Clients authenticate with it
+// over SASL/SCRAM using TokenID as the username and the base64 HMAC as the
+// password.
+type DelegationToken struct {
+	TokenID string
+	// Owner is the principal the token acts as (e.g. "User:alice").
+	Owner string
+	// Renewers are the principals, besides the owner, allowed to renew it.
+	Renewers   []string
+	IssueTime  time.Time
+	ExpiryTime time.Time
+	// MaxTime bounds every renewal; the token cannot live past it.
+	MaxTime time.Time
+	// HMAC is the token's secret. It is needed to renew or expire the token
+	// and must not be displayed except right after creation.
+	HMAC []byte `json:"-"`
+}
+
+// HMACBase64 returns the HMAC in the form clients use as the SCRAM password.
+func (t DelegationToken) HMACBase64() string {
+	return base64.StdEncoding.EncodeToString(t.HMAC)
+}
+
+// Expired reports whether the token has expired at now.
+func (t DelegationToken) Expired(now time.Time) bool {
+	return !t.ExpiryTime.IsZero() && !now.Before(t.ExpiryTime)
+}
+
+// CreateDelegationTokenRequest describes a token to create for the connected
+// principal.
+type CreateDelegationTokenRequest struct {
+	// Renewers are "<type>:<name>" principals allowed to renew the token.
+	Renewers []string
+	// MaxLifetime caps the token's lifetime; 0 uses the broker's
+	// delegation.token.max.lifetime.ms.
+	MaxLifetime time.Duration
+}
+
+// ValidateCreateDelegationToken checks a create request before any broker
+// call.
+func ValidateCreateDelegationToken(req CreateDelegationTokenRequest) error {
+	for _, r := range req.Renewers {
+		if err := ValidatePrincipal(r); err != nil {
+			return InvalidDelegationTokenError{Reason: "renewer " + r + ": must be in the form <type>:<name>"}
+		}
+	}
+	if req.MaxLifetime < 0 {
+		return InvalidDelegationTokenError{Reason: "max lifetime must not be negative"}
+	}
+	return nil
+}
+
+// ValidateDelegationTokenTarget checks that a token can be renewed or expired,
+// which needs its HMAC.
+func ValidateDelegationTokenTarget(t DelegationToken) error {
+	if len(t.HMAC) == 0 {
+		return InvalidDelegationTokenError{Reason: "token HMAC is required"}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package api
+
+// ElectionType selects how ElectLeaders picks the new leaders.
+type ElectionType string
+
+const (
+	// ElectPreferred moves leadership back to each partition's preferred
+	// replica (the first in its replica list) if that replica is in the ISR.
+	ElectPreferred ElectionType = "preferred"
+	// ElectUnclean elects any live replica as leader of a leaderless
+	// partition, even one outside the ISR. Records may be lost.
+	ElectUnclean ElectionType = "unclean"
+)
+
+// ElectLeadersRequest describes a leader election.
+type ElectLeadersRequest struct {
+	Type ElectionType
+	// Partitions to elect; empty means every partition of the cluster.
+	Partitions []TopicPartition
+}
+
+// ElectionResult is one partition's election outcome. Err is nil both when
+// the leader changed and when no election was needed.
+type ElectionResult struct {
+	Topic     string
+	Partition int32
+	Err       error
+}
+
+// PartitionLeadership is a partition's current leader against its replicas.
+type PartitionLeadership struct {
+	Topic     string
+	Partition int32
+	// Leader is -1 when the partition has no leader.
+	Leader   int32
+	Replicas []int32
+	ISR      []int32
+}
+
+// Preferred returns the preferred leader, or -1 for a partition without
+// replicas.
+func (p PartitionLeadership) Preferred() int32 {
+	if len(p.Replicas) == 0 {
+		return -1
+	}
+	return p.Replicas[0]
+}
+
+// NonPreferred reports whether a leader other than the preferred replica
+// leads the partition.
+func (p PartitionLeadership) NonPreferred() bool {
+	return p.Leader >= 0 && p.Leader != p.Preferred()
+}
+
+// Leaderless reports whether the partition has no leader.
+func (p PartitionLeadership) Leaderless() bool { return p.Leader < 0 }
+
+// PreferredInSync reports whether a preferred election can succeed, that is
+// whether the preferred replica is in the ISR.
+func (p PartitionLeadership) PreferredInSync() bool {
+	for _, b := range p.ISR {
+		if b == p.Preferred() {
+			return true
+		}
+	}
+	return false
+}
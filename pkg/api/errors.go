@@ -392,6 +392,16 @@ func (e CleanupPolicyError) Error() string {
 	return fmt.Sprintf("cannot purge messages for %q: cleanup.policy is %q (must include \"delete\")", e.TopicName, e.Policy)
 }
 
+// InvalidDeleteRecordsError is returned when a DeleteRecordsBefore request
+// fails validation (no target, both targets, an offset past the end, etc.).
+type InvalidDeleteRecordsError struct {
+	Reason string
+}
+
+func (e InvalidDeleteRecordsError) Error() string {
+	return fmt.Sprintf("invalid record deletion: %s", e.Reason)
+}
+
 // RecreateTimeoutError is returned when a recreated topic's prior instance does
 // not finish deleting before the bounded retry window expires. (TP-10)
 type RecreateTimeoutError struct {
@@ -416,6 +426,38 @@ func (e InvalidReplicationFactorError) Error() string {
 	return fmt.Sprintf("invalid replication factor for %q: %s", e.TopicName, e.Reason)
 }
 
+// ReassignmentError is returned when a reassignment cannot be planned or
+// submitted (unknown broker, too few brokers left, controller rejection).
+type ReassignmentError struct {
+	Reason string
+	Cause  error
+}
+
+func (e ReassignmentError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("reassignment: %s: %v", e.Reason, e.Cause)
+	}
+	return "reassignment: " + e.Reason
+}
+
+func (e ReassignmentError) Unwrap() error { return e.Cause }
+
+// ElectionError is returned when a leader election cannot be requested (unknown
+// election type) or the controller rejects it as a whole.
+type ElectionError struct {
+	Reason string
+	Cause  error
+}
+
+func (e ElectionError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("leader election: %s: %v", e.Reason, e.Cause)
+	}
+	return "leader election: " + e.Reason
+}
+
+func (e ElectionError) Unwrap() error { return e.Cause }
+
 // --- ACL & quota errors (AQ-3, AQ-10) ---
 
 // ACLValidationError is returned when an ACL binding fails validation before
@@ -459,6 +501,29 @@ func (e QuotaValidationError) Error() string {
 
 func (e QuotaValidationError) Unwrap() error { return e.Cause }
 
+// ScramValidationError is returned when a SCRAM credential request fails
+// validation before any broker call (empty user, unknown mechanism, iterations
+// out of bounds, empty password).
+type ScramValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e ScramValidationError) Error() string {
+	return fmt.Sprintf("invalid SCRAM credential %s: %s", e.Field, e.Reason)
+}
+
+// InvalidDelegationTokenError is returned when a delegation-token request
+// fails validation before any broker call (malformed renewer, negative
+// lifetime or period, missing HMAC).
+type InvalidDelegationTokenError struct {
+	Reason string
+}
+
+func (e InvalidDelegationTokenError) Error() string {
+	return fmt.Sprintf("invalid delegation token request: %s", e.Reason)
+}
+
 // InvalidSeekError is returned when ConsumeFlags carry an invalid seek model
 // (unknown mode, or an offset/timestamp mode missing its value). (MSG-1)
 type InvalidSeekError struct {
@@ -578,6 +643,28 @@ func (e SchemaValidationError) Error() string {
 
 func (e SchemaValidationError) Unwrap() error { return e.Cause }
 
+// SchemaModeError is returned when the registry refuses an operation because
+// the registry or subject mode does not permit it, e.g. registering while
+// READONLY (registry error code 42205).
+type SchemaModeError struct {
+	Subject string
+	Message string
+	Cause   error
+}
+
+func (e SchemaModeError) Error() string {
+	target := "schema registry"
+	if e.Subject != "" {
+		target = "subject " + e.Subject
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("operation not permitted by the mode of %s: %s", target, e.Message)
+	}
+	return fmt.Sprintf("operation not permitted by the mode of %s", target)
+}
+
+func (e SchemaModeError) Unwrap() error { return e.Cause }
+
 // --- Kafka Connect errors (KC-2) ---
 
 // ConnectClusterNotFoundError is returned when an operation references a Connect
@@ -0,0 +1,84 @@
+package api
+
+// ReassignmentGoal selects what a reassignment plan optimizes for.
+type ReassignmentGoal string
+
+const (
+	// ReassignDecommission moves every replica off ReassignmentRequest.Brokers
+	// onto the least-loaded remaining brokers.
+	ReassignDecommission ReassignmentGoal = "decommission"
+	// ReassignRebalance spreads replicas, then preferred leaders, evenly across
+	// all brokers.
+	ReassignRebalance ReassignmentGoal = "rebalance"
+)
+
+// ReassignmentRequest describes the plan to compute.
+type ReassignmentRequest struct {
+	Goal ReassignmentGoal
+	// Brokers are the brokers to drain (decommission only).
+	Brokers []int32
+	// Topics limits the plan to these topics; empty means every topic, minus
+	// the internal ones for a rebalance. A decommission always includes them
+	// so no replica is left on a drained broker.
+	Topics []string
+}
+
+// PartitionMove is one partition's replica change. Target[0] becomes the
+// preferred leader.
+type PartitionMove struct {
+	Topic     string
+	Partition int32
+	Current   []int32
+	Target    []int32
+}
+
+// Adding returns the target replicas not in the current set.
+func (m PartitionMove) Adding() []int32 { return replicaDiff(m.Target, m.Current) }
+
+// Removing returns the current replicas not in the target set.
+func (m PartitionMove) Removing() []int32 { return replicaDiff(m.Current, m.Target) }
+
+func replicaDiff(a, b []int32) []int32 {
+	var out []int32
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// ReassignmentPlan is a computed, not yet submitted, set of partition moves.
+// Moves only lists partitions whose replica list changes.
+type ReassignmentPlan struct {
+	Goal  ReassignmentGoal
+	Moves []PartitionMove
+}
+
+// ReassignmentOptions tune how a plan is executed.
+type ReassignmentOptions struct {
+	// ThrottleBytesPerSec caps replication traffic of the moved replicas on
+	// every involved broker; 0 leaves replication unthrottled. Throttles stay
+	// in place until ClearReplicationThrottles is called.
+	ThrottleBytesPerSec int64
+}
+
+// PartitionReassignment is an in-flight reassignment as reported by the
+// controller, with the catch-up progress of the replicas being added.
+type PartitionReassignment struct {
+	Topic     string
+	Partition int32
+	Replicas  []int32
+	Adding    []int32
+	Removing  []int32
+	// Progress is the adding replicas' average catch-up in [0,1]: 1 for a
+	// replica already in the ISR, otherwise its log size over the leader's.
+	Progress float64
+}
@@ -1,5 +1,7 @@
 package api
 
+import "fmt"
+
 // SchemaVersion is the metadata for a single registered version of a subject.
 // Schema holds the full definition text; it may be empty when only metadata was
 // requested (fetch the text lazily via GetSchemaContent).
@@ -8,6 +10,45 @@ type SchemaVersion struct {
 	ID         int    `json:"id"`
 	SchemaType string `json:"schemaType"` // AVRO, PROTOBUF, JSON — empty means AVRO
 	Schema     string `json:"schema"`
+	// References lists the other subjects this version imports.
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+// SchemaReference points from a schema to another registered schema it depends
+// on. Name is how the referencing schema refers to it: the fully-qualified
+// record name for AVRO, the import path for PROTOBUF, the $ref URL for JSON.
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// SubjectVersion identifies one registered version of a subject, e.g. a schema
+// that references another one.
+type SubjectVersion struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// ValidateSchemaReferences checks that every reference names a subject and a
+// concrete version and that no name is used twice. Violations yield a
+// SchemaValidationError.
+func ValidateSchemaReferences(refs []SchemaReference) error {
+	seen := make(map[string]bool, len(refs))
+	for _, r := range refs {
+		switch {
+		case r.Name == "":
+			return SchemaValidationError{Message: "reference name is required"}
+		case r.Subject == "":
+			return SchemaValidationError{Message: fmt.Sprintf("reference %q: subject is required", r.Name)}
+		case r.Version < 1:
+			return SchemaValidationError{Message: fmt.Sprintf("reference %q: version must be 1 or greater", r.Name)}
+		case seen[r.Name]:
+			return SchemaValidationError{Message: fmt.Sprintf("reference %q is listed twice", r.Name)}
+		}
+		seen[r.Name] = true
+	}
+	return nil
 }
 
 // CompatibilityLevel is a schema-registry compatibility setting. The zero value
@@ -49,3 +90,30 @@ func (l CompatibilityLevel) Valid() bool {
 		return false
 	}
 }
+
+// SchemaMode is a registry or subject mode. READONLY rejects registrations and
+// deletes; IMPORT accepts registrations that carry explicit IDs and versions
+// (used to migrate between registries).
+type SchemaMode string
+
+const (
+	SchemaModeReadWrite SchemaMode = "READWRITE"
+	SchemaModeReadOnly  SchemaMode = "READONLY"
+	SchemaModeImport    SchemaMode = "IMPORT"
+)
+
+// SchemaModes lists the settable modes in the order a UI selector should
+// present them.
+func SchemaModes() []SchemaMode {
+	return []SchemaMode{SchemaModeReadWrite, SchemaModeReadOnly, SchemaModeImport}
+}
+
+// Valid reports whether m is one of the settable modes.
+func (m SchemaMode) Valid() bool {
+	switch m {
+	case SchemaModeReadWrite, SchemaModeReadOnly, SchemaModeImport:
+		return true
+	default:
+		return false
+	}
+}
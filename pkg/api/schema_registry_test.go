@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchemaReferences(t *testing.T) {
+	addr := SchemaReference{Name: "com.example.Address", Subject: "address", Version: 1}
+	tests := []struct {
+		name    string
+		refs    []SchemaReference
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []SchemaReference{addr, {Name: "common.proto", Subject: "common", Version: 3}}, false},
+		{"missing name", []SchemaReference{{Subject: "address", Version: 1}}, true},
+		{"missing subject", []SchemaReference{{Name: "a", Version: 1}}, true},
+		{"latest is not a version", []SchemaReference{{Name: "a", Subject: "address", Version: -1}}, true},
+		{"duplicate name", []SchemaReference{addr, addr}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchemaReferences(tt.refs)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			var ve SchemaValidationError
+			assert.True(t, errors.As(err, &ve))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import "strings"
+
+// ScramMechanism names a SASL/SCRAM hash mechanism as Kafka spells it.
+type ScramMechanism string
+
+const (
+	ScramSHA256 ScramMechanism = "SCRAM-SHA-256"
+	ScramSHA512 ScramMechanism = "SCRAM-SHA-512"
+)
+
+// ScramMechanisms lists the mechanisms Kafka supports, in display order.
+var ScramMechanisms = []ScramMechanism{ScramSHA256, ScramSHA512}
+
+// Iteration bounds the broker enforces for SCRAM credentials. The default
+// matches kafka-configs.sh.
+const (
+	ScramMinIterations     int32 = 4096
+	ScramMaxIterations     int32 = 16384
+	ScramDefaultIterations int32 = 4096
+)
+
+// ScramCredentialInfo is one mechanism configured for a user. The broker never
+// returns the salted password, only the mechanism and its iteration count.
+type ScramCredentialInfo struct {
+	Mechanism  ScramMechanism
+	Iterations int32
+}
+
+// ScramUser is a SCRAM user with the credentials configured for it.
+type ScramUser struct {
+	Name        string
+	Credentials []ScramCredentialInfo
+}
+
+// ScramCredentialUpsert creates or replaces (rotates) a user's credential for
+// one mechanism. The password is salted client-side and never leaves the
+// process in clear text.
+type ScramCredentialUpsert struct {
+	User       string
+	Mechanism  ScramMechanism
+	Iterations int32
+	Password   string
+}
+
+// ScramCredentialDeletion removes a user's credential for one mechanism.
+type ScramCredentialDeletion struct {
+	User      string
+	Mechanism ScramMechanism
+}
+
+// ParseScramMechanism accepts a mechanism name case-insensitively, with or
+// without the "SCRAM-" prefix (e.g. "sha-512").
+func ParseScramMechanism(s string) (ScramMechanism, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	if !strings.HasPrefix(name, "SCRAM-") {
+		name = "SCRAM-" + name
+	}
+	for _, m := range ScramMechanisms {
+		if string(m) == name {
+			return m, nil
+		}
+	}
+	return "", ScramValidationError{Field: "mechanism", Reason: "must be SCRAM-SHA-256 or SCRAM-SHA-512"}
+}
+
+// ValidateScramUpsert checks a credential upsert before any broker call: a
+// user name, a known mechanism, iterations within the broker's bounds and a
+// non-empty password.
+func ValidateScramUpsert(u ScramCredentialUpsert) error {
+	if err := validateScramTarget(u.User, u.Mechanism); err != nil {
+		return err
+	}
+	if u.Iterations < ScramMinIterations || u.Iterations > ScramMaxIterations {
+		return ScramValidationError{Field: "iterations", Reason: "must be between 4096 and 16384"}
+	}
+	if u.Password == "" {
+		return ScramValidationError{Field: "password", Reason: "must not be empty"}
+	}
+	return nil
+}
+
+// ValidateScramDeletion checks a credential deletion before any broker call.
+func ValidateScramDeletion(d ScramCredentialDeletion) error {
+	return validateScramTarget(d.User, d.Mechanism)
+}
+
+func validateScramTarget(user string, mechanism ScramMechanism) error {
+	if strings.TrimSpace(user) == "" {
+		return ScramValidationError{Field: "user", Reason: "must not be empty"}
+	}
+	if mechanism != ScramSHA256 && mechanism != ScramSHA512 {
+		return ScramValidationError{Field: "mechanism", Reason: "must be SCRAM-SHA-256 or SCRAM-SHA-512"}
+	}
+	return nil
+}
@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 // TopicConfigEntry describes a single topic configuration key with the metadata
 // needed to render it: its effective value, the derived default, the config
 // source, and whether it is sensitive (masked) or read-only. (TP-2)
@@ -56,3 +58,24 @@ func (d TopicDetails) MessageCount() int64 {
 	}
 	return total
 }
+
+// DeleteRecordsRequest describes a truncation of a topic: every record below a
+// per-partition offset, or every record older than a point in time. Exactly one
+// of Offsets and Before is set.
+type DeleteRecordsRequest struct {
+	Topic string
+	// Offsets deletes, per partition, the records below the given offset.
+	Offsets map[int32]int64
+	// Before deletes the records with a timestamp earlier than this time on
+	// Partitions (every partition when empty).
+	Before     *time.Time
+	Partitions []int32
+}
+
+// RecordDeletion is one partition's outcome of DeleteRecordsBefore: the
+// partition now starts at Offset, Deleted records earlier than before.
+type RecordDeletion struct {
+	Partition int32
+	Offset    int64
+	Deleted   int64
+}
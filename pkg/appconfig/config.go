@@ -138,6 +138,9 @@ type SASLConfig struct {
 	// ClientID and TokenURL but no ClientSecret or static Token), kafui runs the
 	// interactive device-code grant for OAUTHBEARER (AA-13).
 	DeviceAuthURL string `yaml:"deviceAuthURL"`
+	// TokenAuth logs in with a delegation token over SCRAM: Username is the
+	// token ID and Password the base64 HMAC.
+	TokenAuth bool `yaml:"tokenAuth"`
 }
 
 // TLSConfig is the broker TLS material (PEM file paths) for a fully-kafui-defined cluster.
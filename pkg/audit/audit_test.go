@@ -23,6 +23,9 @@ func TestClassify(t *testing.T) {
 		{"read-only is denied", api.ClusterReadOnlyError{Cluster: "prod"}, ResultAccessDenied},
 		{"acl validation", api.ACLValidationError{Field: "principal", Reason: "x"}, ResultValidationError},
 		{"topic validation", api.TopicValidationError{TopicName: "t", Reason: "bad"}, ResultValidationError},
+		{"record deletion validation", api.InvalidDeleteRecordsError{Reason: "past the end"}, ResultValidationError},
+		{"scram validation", api.ScramValidationError{Field: "iterations", Reason: "out of range"}, ResultValidationError},
+		{"delegation token validation", api.InvalidDelegationTokenError{Reason: "token HMAC is required"}, ResultValidationError},
 		{"generic is execution error", api.TopicNotFoundError{TopicName: "t"}, ResultExecutionError},
 	}
 	for _, tt := range tests {
@@ -80,10 +80,14 @@ func isValidation(err error) bool {
 		offV   api.InvalidOffsetResetError
 		seekV  api.InvalidSeekError
 		rfV    api.InvalidReplicationFactorError
+		delV   api.InvalidDeleteRecordsError
+		scramV api.ScramValidationError
+		tokenV api.InvalidDelegationTokenError
 	)
 	return errors.As(err, &aclV) || errors.As(err, &quotaV) || errors.As(err, &topicV) ||
 		errors.As(err, &schemaV) || errors.As(err, &cfgV) || errors.As(err, &offV) ||
-		errors.As(err, &seekV) || errors.As(err, &rfV)
+		errors.As(err, &seekV) || errors.As(err, &rfV) || errors.As(err, &delV) ||
+		errors.As(err, &scramV) || errors.As(err, &tokenV)
 }
 
 // ResolveUser returns the acting local identity: the OS user, falling back to
@@ -13,17 +13,19 @@ package authz
 type ResourceType string
 
 const (
-	ResourceTopic          ResourceType = "topic"
-	ResourceConsumerGroup  ResourceType = "consumer-group"
-	ResourceSchema         ResourceType = "schema"
-	ResourceConnectCluster ResourceType = "connect-cluster"
-	ResourceConnector      ResourceType = "connector"
-	ResourceSQLEngine      ResourceType = "sql-engine"
-	ResourceACL            ResourceType = "acl"
-	ResourceAudit          ResourceType = "audit"
-	ResourceClientQuota    ResourceType = "client-quotas"
-	ResourceClusterConfig  ResourceType = "cluster-configuration"
-	ResourceAppConfig      ResourceType = "application-configuration"
+	ResourceTopic           ResourceType = "topic"
+	ResourceConsumerGroup   ResourceType = "consumer-group"
+	ResourceSchema          ResourceType = "schema"
+	ResourceConnectCluster  ResourceType = "connect-cluster"
+	ResourceConnector       ResourceType = "connector"
+	ResourceSQLEngine       ResourceType = "sql-engine"
+	ResourceACL             ResourceType = "acl"
+	ResourceAudit           ResourceType = "audit"
+	ResourceClientQuota     ResourceType = "client-quotas"
+	ResourceScramUser       ResourceType = "scram-users"
+	ResourceDelegationToken ResourceType = "delegation-tokens"
+	ResourceClusterConfig   ResourceType = "cluster-configuration"
+	ResourceAppConfig       ResourceType = "application-configuration"
 )
 
 // Action names an operation class. Read actions leave the cluster unchanged;
@@ -45,9 +47,11 @@ const (
 	ActionResetOffsets    Action = "reset offsets"
 	ActionExecute         Action = "execute"
 	ActionModifyCompat    Action = "modify compatibility"
+	ActionModifyMode      Action = "modify mode"
 	ActionPause           Action = "pause"
 	ActionResume          Action = "resume"
 	ActionRestart         Action = "restart"
+	ActionElectLeaders    Action = "elect leaders"
 )
 
 // registry maps each resource type to its valid actions, each flagged altering
@@ -63,6 +67,7 @@ var registry = map[ResourceType]map[Action]bool{
 		ActionCreate:          true,
 		ActionEdit:            true,
 		ActionDelete:          true,
+		ActionElectLeaders:    true,
 	},
 	ResourceConsumerGroup: {
 		ActionView:         false,
@@ -75,6 +80,7 @@ var registry = map[ResourceType]map[Action]bool{
 		ActionEdit:         true,
 		ActionDelete:       true,
 		ActionModifyCompat: true,
+		ActionModifyMode:   true,
 	},
 	ResourceConnectCluster: {
 		ActionView: false,
@@ -105,9 +111,21 @@ var registry = map[ResourceType]map[Action]bool{
 		ActionView: false,
 		ActionEdit: true,
 	},
+	ResourceScramUser: {
+		ActionView:   false,
+		ActionEdit:   true,
+		ActionDelete: true,
+	},
+	ResourceDelegationToken: {
+		ActionView:   false,
+		ActionCreate: true,
+		ActionEdit:   true,
+		ActionDelete: true,
+	},
 	ResourceClusterConfig: {
-		ActionView: false,
-		ActionEdit: true,
+		ActionView:         false,
+		ActionEdit:         true,
+		ActionElectLeaders: true,
 	},
 	ResourceAppConfig: {
 		ActionView: false,
@@ -20,8 +20,13 @@ func TestIsAltering(t *testing.T) {
 		{"topic delete is altering", ResourceTopic, ActionDelete, true},
 		{"group reset offsets is altering", ResourceConsumerGroup, ActionResetOffsets, true},
 		{"schema modify compat is altering", ResourceSchema, ActionModifyCompat, true},
+		{"schema modify mode is altering", ResourceSchema, ActionModifyMode, true},
 		{"acl view is read", ResourceACL, ActionView, false},
 		{"sql execute is altering", ResourceSQLEngine, ActionExecute, true},
+		{"topic elect leaders is altering", ResourceTopic, ActionElectLeaders, true},
+		{"cluster elect leaders is altering", ResourceClusterConfig, ActionElectLeaders, true},
+		{"scram user edit is altering", ResourceScramUser, ActionEdit, true},
+		{"delegation token view is read", ResourceDelegationToken, ActionView, false},
 		{"unknown resource is altering (fail safe)", ResourceType("nope"), ActionView, true},
 		{"unknown action is altering (fail safe)", ResourceTopic, Action("frobnicate"), true},
 	}
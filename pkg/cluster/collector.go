@@ -143,6 +143,7 @@ func (c *Collector) collectOne(ctx context.Context, name string) {
 			ov.BrokerCount = prev.stats.BrokerCount
 			ov.OnlinePartitionCount = prev.stats.OnlinePartitions
 			ov.Version = prev.stats.Version
+			ov.CoordinationType = prev.stats.CoordinationType
 			c.cache[name] = cached{overview: ov, stats: prev.stats, hasStats: true}
 			return
 		}
@@ -153,6 +154,7 @@ func (c *Collector) collectOne(ctx context.Context, name string) {
 	ov.BrokerCount = stats.BrokerCount
 	ov.OnlinePartitionCount = stats.OnlinePartitions
 	ov.Version = stats.Version
+	ov.CoordinationType = stats.CoordinationType
 	if names, err := c.ds.GetTopicNames(); err == nil {
 		ov.TopicCount = len(names)
 	} else if prev.hasStats {
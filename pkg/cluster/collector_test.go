@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/stretchr/testify/assert"
@@ -43,6 +44,9 @@ func (f *fakeDS) ConsumeTopic(context.Context, string, api.ConsumeFlags, api.Mes
 	return nil
 }
 func (f *fakeDS) ProduceMessage(context.Context, string, api.ProduceRecord) error  { return nil }
+func (f *fakeDS) ProduceBatch(context.Context, string, []api.ProduceRecord, api.ProducerOptions) ([]api.ProduceResult, error) {
+	return nil, nil
+}
 func (f *fakeDS) GetTopicMessageCounts(map[string]int32) (map[string]int64, error) { return nil, nil }
 func (f *fakeDS) GetSchemas() ([]api.Schema, error)                                { return nil, nil }
 func (f *fakeDS) GetSchemaDetails([]string) ([]api.Schema, error)                  { return nil, nil }
@@ -52,8 +56,8 @@ func (f *fakeDS) GetGlobalCompatibility() (api.CompatibilityLevel, error)
 func (f *fakeDS) GetSubjectCompatibility(string) (api.CompatibilityLevel, bool, error) {
 	return "", false, nil
 }
-func (f *fakeDS) RegisterSchema(string, string, string) (api.Schema, error) { return api.Schema{}, nil }
-func (f *fakeDS) CheckSchemaCompatibility(string, string, string) (bool, []string, error) {
+func (f *fakeDS) RegisterSchema(string, string, string, []api.SchemaReference) (api.Schema, error) { return api.Schema{}, nil }
+func (f *fakeDS) CheckSchemaCompatibility(string, string, string, []api.SchemaReference) (bool, []string, error) {
 	return true, nil, nil
 }
 func (f *fakeDS) DeleteSubject(string, bool) ([]int, error)                    { return nil, nil }
@@ -89,6 +93,96 @@ func (f *fakeDS) AlterReplicaLogDir(brokerID int32, topic string, partition int3
 	return nil
 }
 func (f *fakeDS) GetBrokerMetrics(brokerID int32) (string, error) { return "", nil }
+func (f *fakeDS) DescribeQuorum(ctx context.Context) (api.QuorumInfo, error) { return api.QuorumInfo{}, nil }
+func (f *fakeDS) PlanReassignment(ctx context.Context, req api.ReassignmentRequest) (api.ReassignmentPlan, error) {
+	return api.ReassignmentPlan{}, nil
+}
+func (f *fakeDS) ExecuteReassignment(ctx context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	return nil
+}
+func (f *fakeDS) ListReassignments(ctx context.Context) ([]api.PartitionReassignment, error) { return nil, nil }
+func (f *fakeDS) CancelReassignments(ctx context.Context, partitions []api.TopicPartition) error { return nil }
+func (f *fakeDS) ClearReplicationThrottles(ctx context.Context, topics []string) error { return nil }
+func (f *fakeDS) DescribeLeadership(ctx context.Context, topics []string) ([]api.PartitionLeadership, error) {
+	return nil, nil
+}
+func (f *fakeDS) ElectLeaders(ctx context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	return nil, nil
+}
+
+func (f *fakeDS) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	return nil, nil
+}
+
+func (f *fakeDS) DescribeUserScramCredentials(users []string) ([]api.ScramUser, error) {
+	return nil, nil
+}
+
+func (f *fakeDS) UpsertUserScramCredentials(upserts []api.ScramCredentialUpsert) error {
+	return nil
+}
+
+func (f *fakeDS) DeleteUserScramCredentials(deletions []api.ScramCredentialDeletion) error {
+	return nil
+}
+
+func (f *fakeDS) DescribeDelegationTokens(owners []string) ([]api.DelegationToken, error) {
+	return nil, nil
+}
+
+func (f *fakeDS) CreateDelegationToken(req api.CreateDelegationTokenRequest) (api.DelegationToken, error) {
+	return api.DelegationToken{}, nil
+}
+
+func (f *fakeDS) RenewDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (f *fakeDS) ExpireDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+func (f *fakeDS) GetSchemaVersion(subject string, version int) (api.SchemaVersion, error) {
+	return api.SchemaVersion{}, nil
+}
+func (f *fakeDS) GetSchemaReferencedBy(subject string, version int) ([]api.SubjectVersion, error) {
+	return nil, nil
+}
+
+func (f *fakeDS) GetDeletedSubjects() ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeDS) GetDeletedSchemaVersions(subject string) ([]api.SchemaVersion, error) {
+	return nil, nil
+}
+
+func (f *fakeDS) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (f *fakeDS) GetGlobalMode() (api.SchemaMode, error) {
+	return "", nil
+}
+
+func (f *fakeDS) GetSubjectMode(subject string) (api.SchemaMode, bool, error) {
+	return "", false, nil
+}
+
+func (f *fakeDS) SetGlobalMode(mode api.SchemaMode) error {
+	return nil
+}
+
+func (f *fakeDS) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	return nil
+}
+
+func (f *fakeDS) ImportSchema(subject string, version api.SchemaVersion) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (f *fakeDS) CheckSchemaCompatibilityWithVersion(subject string, version int, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
+	return true, nil, nil
+}
 
 // Topic-administration + analysis stubs (TP-1..TP-11, TP-29/TP-30).
 func (f *fakeDS) GetTopicConfig(topicName string) ([]api.TopicConfigEntry, error) { return nil, nil }
@@ -154,6 +248,12 @@ func (f *fakeDS) DeleteConsumerGroupOffsets(groupID, topic string) error { retur
 func (f *fakeDS) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
 	return nil
 }
+func (f *fakeDS) PlanConsumerGroupOffsetReset(ctx context.Context, req api.OffsetResetRequest) ([]api.PartitionResetPlan, error) {
+	return nil, nil
+}
+func (f *fakeDS) GetRecordTimestamps(ctx context.Context, topic string, offsets map[int32]int64) (map[int32]time.Time, error) {
+	return nil, nil
+}
 
 func newFake() *fakeDS {
 	return &fakeDS{
@@ -226,6 +326,20 @@ func TestTopicCountPopulated(t *testing.T) {
 	assert.Equal(t, 3, ov.TopicCount)
 }
 
+func TestCoordinationTypeRetainedWhenOffline(t *testing.T) {
+	f := newFake()
+	f.stats["a"] = api.ClusterStatistics{BrokerCount: 3, Version: "3.7", CoordinationType: "kraft"}
+	c := New(f, 0, nil)
+	c.CollectAll(context.Background())
+	assert.Equal(t, "kraft", mustOverview(t, c, "a").CoordinationType)
+
+	f.statsErr["a"] = errors.New("down")
+	ov, err := c.RefreshCluster(context.Background(), "a")
+	require.NoError(t, err)
+	assert.Equal(t, api.ClusterOffline, ov.Status)
+	assert.Equal(t, "kraft", ov.CoordinationType)
+}
+
 func TestRefreshUnknownCluster(t *testing.T) {
 	c := New(newFake(), 0, nil)
 	_, err := c.RefreshCluster(context.Background(), "nope")
@@ -11,6 +11,7 @@ package datasource
 
 import (
 	"context"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/audit"
@@ -144,6 +145,23 @@ func (g *Guard) PurgeTopicMessages(name string, partition int32) error {
 	})
 }
 
+func (g *Guard) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	var out []api.RecordDeletion
+	params := map[string]any{"topic": req.Topic}
+	if req.Before != nil {
+		params["before"] = *req.Before
+		params["partitions"] = req.Partitions
+	} else {
+		params["offsets"] = req.Offsets
+	}
+	err := g.do("DeleteRecordsBefore", params, []ref{{authz.ResourceTopic, req.Topic, authz.ActionDeleteMessages}}, func() error {
+		var e error
+		out, e = g.KafkaDataSource.DeleteRecordsBefore(ctx, req)
+		return e
+	})
+	return out, err
+}
+
 func (g *Guard) RecreateTopic(name string) error {
 	// Recreate deletes then re-creates: requires both delete and create.
 	refs := []ref{{authz.ResourceTopic, name, authz.ActionDelete}, {authz.ResourceTopic, "", authz.ActionCreate}}
@@ -158,6 +176,20 @@ func (g *Guard) ProduceMessage(ctx context.Context, topic string, rec api.Produc
 	})
 }
 
+func (g *Guard) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	var out []api.ProduceResult
+	params := map[string]any{"topic": topic, "records": len(recs)}
+	if opts.Transactional() {
+		params["transactional_id"] = opts.TransactionalID
+	}
+	err := g.do("ProduceBatch", params, []ref{{authz.ResourceTopic, topic, authz.ActionProduceMessages}}, func() error {
+		var e error
+		out, e = g.KafkaDataSource.ProduceBatch(ctx, topic, recs, opts)
+		return e
+	})
+	return out, err
+}
+
 // --- Consumer groups ---
 
 func (g *Guard) DeleteConsumerGroup(groupID string) error {
@@ -173,18 +205,48 @@ func (g *Guard) DeleteConsumerGroupOffsets(groupID string, topic string) error {
 }
 
 func (g *Guard) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
-	return g.do("ResetConsumerGroupOffsets", map[string]any{"group": req.GroupID, "topic": req.Topic}, []ref{{authz.ResourceConsumerGroup, req.GroupID, authz.ActionResetOffsets}}, func() error {
+	params, refs := offsetResetRefs(req)
+	return g.do("ResetConsumerGroupOffsets", params, refs, func() error {
 		return g.KafkaDataSource.ResetConsumerGroupOffsets(ctx, req)
 	})
 }
 
+// PlanConsumerGroupOffsetReset needs the same rights as the reset it previews:
+// the plan of a copy reveals the source group's positions.
+func (g *Guard) PlanConsumerGroupOffsetReset(ctx context.Context, req api.OffsetResetRequest) ([]api.PartitionResetPlan, error) {
+	var out []api.PartitionResetPlan
+	params, refs := offsetResetRefs(req)
+	err := g.do("PlanConsumerGroupOffsetReset", params, refs, func() error {
+		var e error
+		out, e = g.KafkaDataSource.PlanConsumerGroupOffsetReset(ctx, req)
+		return e
+	})
+	return out, err
+}
+
+func offsetResetRefs(req api.OffsetResetRequest) (map[string]any, []ref) {
+	params := map[string]any{"group": req.GroupID, "topic": req.Topic, "mode": string(req.Mode)}
+	refs := []ref{{authz.ResourceConsumerGroup, req.GroupID, authz.ActionResetOffsets}}
+	if req.Mode == api.OffsetResetFromGroup {
+		// Copying exposes the source group's positions.
+		params["sourceGroup"] = req.SourceGroupID
+		refs = append(refs, ref{authz.ResourceConsumerGroup, req.SourceGroupID, authz.ActionView})
+	}
+	if req.Planned != nil {
+		params["planned"] = req.Planned
+	}
+	return params, refs
+}
+
 // --- Schema registry ---
 
-func (g *Guard) RegisterSchema(subject, schemaText, schemaType string) (api.Schema, error) {
+// RegisterSchema needs create on the subject, like ImportSchema and
+// RestoreSchemaVersion.
+func (g *Guard) RegisterSchema(subject, schemaText, schemaType string, references []api.SchemaReference) (api.Schema, error) {
 	var out api.Schema
-	err := g.do("RegisterSchema", map[string]any{"subject": subject}, []ref{{authz.ResourceSchema, "", authz.ActionCreate}}, func() error {
+	err := g.do("RegisterSchema", map[string]any{"subject": subject}, []ref{{authz.ResourceSchema, subject, authz.ActionCreate}}, func() error {
 		var e error
-		out, e = g.KafkaDataSource.RegisterSchema(subject, schemaText, schemaType)
+		out, e = g.KafkaDataSource.RegisterSchema(subject, schemaText, schemaType, references)
 		return e
 	})
 	return out, err
@@ -200,6 +262,40 @@ func (g *Guard) DeleteSubject(subject string, permanent bool) ([]int, error) {
 	return out, err
 }
 
+func (g *Guard) ImportSchema(subject string, version api.SchemaVersion) (api.Schema, error) {
+	var out api.Schema
+	err := g.do("ImportSchema", map[string]any{"subject": subject, "version": version.Version, "id": version.ID}, []ref{{authz.ResourceSchema, subject, authz.ActionCreate}}, func() error {
+		var e error
+		out, e = g.KafkaDataSource.ImportSchema(subject, version)
+		return e
+	})
+	return out, err
+}
+
+// RestoreSchemaVersion is a registration, so it is checked like RegisterSchema:
+// create on the subject.
+func (g *Guard) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	var out api.Schema
+	err := g.do("RestoreSchemaVersion", map[string]any{"subject": subject, "version": version}, []ref{{authz.ResourceSchema, subject, authz.ActionCreate}}, func() error {
+		var e error
+		out, e = g.KafkaDataSource.RestoreSchemaVersion(subject, version)
+		return e
+	})
+	return out, err
+}
+
+func (g *Guard) SetGlobalMode(mode api.SchemaMode) error {
+	return g.do("SetGlobalMode", map[string]any{"mode": string(mode)}, []ref{{authz.ResourceSchema, "", authz.ActionModifyMode}}, func() error {
+		return g.KafkaDataSource.SetGlobalMode(mode)
+	})
+}
+
+func (g *Guard) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	return g.do("SetSubjectMode", map[string]any{"subject": subject, "mode": string(mode)}, []ref{{authz.ResourceSchema, subject, authz.ActionModifyMode}}, func() error {
+		return g.KafkaDataSource.SetSubjectMode(subject, mode)
+	})
+}
+
 func (g *Guard) DeleteSchemaVersion(subject string, version int, permanent bool) error {
 	return g.do("DeleteSchemaVersion", map[string]any{"subject": subject, "version": version}, []ref{{authz.ResourceSchema, subject, authz.ActionDelete}}, func() error {
 		return g.KafkaDataSource.DeleteSchemaVersion(subject, version, permanent)
@@ -238,6 +334,81 @@ func (g *Guard) AlterClientQuotas(entity api.ClientQuotaEntity, quotas map[strin
 	})
 }
 
+// SCRAM upserts both create and rotate credentials, so they are authorized as
+// edit on each user. Passwords never reach the audit params.
+func (g *Guard) UpsertUserScramCredentials(upserts []api.ScramCredentialUpsert) error {
+	refs := make([]ref, 0, len(upserts))
+	for _, u := range upserts {
+		refs = append(refs, ref{authz.ResourceScramUser, u.User, authz.ActionEdit})
+	}
+	return g.do("UpsertUserScramCredentials", scramAuditParams(len(upserts), func(i int) (string, api.ScramMechanism) {
+		return upserts[i].User, upserts[i].Mechanism
+	}), refs, func() error {
+		return g.KafkaDataSource.UpsertUserScramCredentials(upserts)
+	})
+}
+
+func (g *Guard) DeleteUserScramCredentials(deletions []api.ScramCredentialDeletion) error {
+	refs := make([]ref, 0, len(deletions))
+	for _, d := range deletions {
+		refs = append(refs, ref{authz.ResourceScramUser, d.User, authz.ActionDelete})
+	}
+	return g.do("DeleteUserScramCredentials", scramAuditParams(len(deletions), func(i int) (string, api.ScramMechanism) {
+		return deletions[i].User, deletions[i].Mechanism
+	}), refs, func() error {
+		return g.KafkaDataSource.DeleteUserScramCredentials(deletions)
+	})
+}
+
+// scramAuditParams lists the "user/mechanism" pairs touched by a SCRAM
+// operation.
+func scramAuditParams(n int, at func(int) (string, api.ScramMechanism)) map[string]any {
+	creds := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		user, mech := at(i)
+		creds = append(creds, user+"/"+string(mech))
+	}
+	return map[string]any{"credentials": creds}
+}
+
+// Delegation-token audit params name the token, never its HMAC.
+func (g *Guard) CreateDelegationToken(req api.CreateDelegationTokenRequest) (api.DelegationToken, error) {
+	var out api.DelegationToken
+	params := map[string]any{"renewers": req.Renewers}
+	if req.MaxLifetime > 0 {
+		params["maxLifetime"] = req.MaxLifetime.String()
+	}
+	err := g.do("CreateDelegationToken", params, []ref{{authz.ResourceDelegationToken, "", authz.ActionCreate}}, func() error {
+		var e error
+		out, e = g.KafkaDataSource.CreateDelegationToken(req)
+		if e == nil {
+			params["token"] = out.TokenID
+		}
+		return e
+	})
+	return out, err
+}
+
+func (g *Guard) RenewDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	var out time.Time
+	err := g.do("RenewDelegationToken", map[string]any{"token": token.TokenID, "period": period.String()}, []ref{{authz.ResourceDelegationToken, token.TokenID, authz.ActionEdit}}, func() error {
+		var e error
+		out, e = g.KafkaDataSource.RenewDelegationToken(token, period)
+		return e
+	})
+	return out, err
+}
+
+func (g *Guard) ExpireDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	var out time.Time
+	err := g.do("ExpireDelegationToken", map[string]any{"token": token.TokenID, "period": period.String()}, []ref{{authz.ResourceDelegationToken, token.TokenID, authz.ActionDelete}}, func() error {
+		var e error
+		out, e = g.KafkaDataSource.ExpireDelegationToken(token, period)
+		return e
+	})
+	return out, err
+}
+
 // --- Broker / cluster configuration ---
 
 func (g *Guard) AlterBrokerConfig(brokerID int32, key, value string) error {
@@ -252,6 +423,52 @@ func (g *Guard) AlterReplicaLogDir(brokerID int32, topic string, partition int32
 	})
 }
 
+func (g *Guard) ExecuteReassignment(ctx context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	params := map[string]any{"goal": string(plan.Goal), "moves": len(plan.Moves)}
+	if opts.ThrottleBytesPerSec > 0 {
+		params["throttle"] = opts.ThrottleBytesPerSec
+	}
+	return g.do("ExecuteReassignment", params, []ref{{authz.ResourceClusterConfig, "", authz.ActionEdit}}, func() error {
+		return g.KafkaDataSource.ExecuteReassignment(ctx, plan, opts)
+	})
+}
+
+func (g *Guard) CancelReassignments(ctx context.Context, partitions []api.TopicPartition) error {
+	return g.do("CancelReassignments", map[string]any{"partitions": len(partitions)}, []ref{{authz.ResourceClusterConfig, "", authz.ActionEdit}}, func() error {
+		return g.KafkaDataSource.CancelReassignments(ctx, partitions)
+	})
+}
+
+func (g *Guard) ClearReplicationThrottles(ctx context.Context, topics []string) error {
+	return g.do("ClearReplicationThrottles", map[string]any{"topics": topics}, []ref{{authz.ResourceClusterConfig, "", authz.ActionEdit}}, func() error {
+		return g.KafkaDataSource.ClearReplicationThrottles(ctx, topics)
+	})
+}
+
+// ElectLeaders checks elect-leaders on every topic involved, or on the cluster
+// configuration for a cluster-wide election.
+func (g *Guard) ElectLeaders(ctx context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	params := map[string]any{"type": string(req.Type), "partitions": len(req.Partitions)}
+	refs := []ref{{authz.ResourceClusterConfig, "", authz.ActionElectLeaders}}
+	if len(req.Partitions) > 0 {
+		refs = nil
+		seen := map[string]bool{}
+		for _, tp := range req.Partitions {
+			if !seen[tp.Topic] {
+				seen[tp.Topic] = true
+				refs = append(refs, ref{authz.ResourceTopic, tp.Topic, authz.ActionElectLeaders})
+			}
+		}
+	}
+	var out []api.ElectionResult
+	err := g.do("ElectLeaders", params, refs, func() error {
+		var e error
+		out, e = g.KafkaDataSource.ElectLeaders(ctx, req)
+		return e
+	})
+	return out, err
+}
+
 // --- Kafka Connect (connector name is the composite "<connect>/<name>") ---
 
 func connectorName(connect, name string) string { return connect + "/" + name }
@@ -389,6 +606,30 @@ func (g *Guard) GetSchemaDetails(subjects []string) ([]api.Schema, error) {
 	return g.filterSchemas(g.KafkaDataSource.GetSchemaDetails(subjects))
 }
 
+// GetDeletedSubjects hides soft-deleted subjects the user may not view.
+func (g *Guard) GetDeletedSubjects() ([]string, error) {
+	names, err := g.KafkaDataSource.GetDeletedSubjects()
+	if err != nil {
+		return names, err
+	}
+	return g.filterNames(authz.ResourceSchema, names), nil
+}
+
+// GetSchemaReferencedBy hides referencing subjects the user may not view.
+func (g *Guard) GetSchemaReferencedBy(subject string, version int) ([]api.SubjectVersion, error) {
+	refs, err := g.KafkaDataSource.GetSchemaReferencedBy(subject, version)
+	if err != nil || g.gate == nil || !g.gate.Enabled() {
+		return refs, err
+	}
+	out := refs[:0]
+	for _, r := range refs {
+		if g.gate.Allowed(authz.ActionView, authz.ResourceSchema, r.Subject) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
 func (g *Guard) filterSchemas(schemas []api.Schema, err error) ([]api.Schema, error) {
 	if err != nil || g.gate == nil || !g.gate.Enabled() {
 		return schemas, err
@@ -2,7 +2,9 @@ package datasource
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/audit"
@@ -20,6 +22,9 @@ type spyDS struct {
 	ctx           string
 	deleteCalled  bool
 	produceCalled bool
+	moveCalled    bool
+	electCalled   bool
+	truncated     bool
 	topicNames    []string
 }
 
@@ -37,6 +42,22 @@ func (s *spyDS) ProduceMessage(ctx context.Context, topic string, rec api.Produc
 	s.produceCalled = true
 	return nil
 }
+func (s *spyDS) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	s.produceCalled = true
+	return make([]api.ProduceResult, len(recs)), nil
+}
+func (s *spyDS) ExecuteReassignment(ctx context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	s.moveCalled = true
+	return nil
+}
+func (s *spyDS) ElectLeaders(ctx context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	s.electCalled = true
+	return nil, nil
+}
+func (s *spyDS) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	s.truncated = true
+	return nil, nil
+}
 func (s *spyDS) GetTopicNames() ([]string, error) { return s.topicNames, nil }
 
 // recWriter captures audit records.
@@ -113,6 +134,196 @@ func TestGuardReadOnlyBlocksWriteWithClusterReadOnlyError(t *testing.T) {
 	assert.Equal(t, audit.ResultAccessDenied, w.records[0].Result)
 }
 
+func TestGuardProduceBatchAudited(t *testing.T) {
+	spy := newSpy()
+	w := &recWriter{}
+	svc := audit.NewService(true, audit.LevelAll, w, nil)
+	g := NewGuard(spy, adminGate(t, false), svc)
+
+	res, err := g.ProduceBatch(context.Background(), "orders-eu", []api.ProduceRecord{{}, {}}, api.ProducerOptions{TransactionalID: "tx-1"})
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.True(t, spy.produceCalled)
+	require.Len(t, w.records, 1)
+	assert.Equal(t, "ProduceBatch", w.records[0].Operation)
+	assert.Equal(t, 2, w.records[0].Params["records"])
+	assert.Equal(t, "tx-1", w.records[0].Params["transactional_id"])
+
+	spy.produceCalled = false
+	ro := NewGuard(spy, adminGate(t, true), nil)
+	_, err = ro.ProduceBatch(context.Background(), "orders-eu", []api.ProduceRecord{{}}, api.ProducerOptions{})
+	var roErr api.ClusterReadOnlyError
+	assert.ErrorAs(t, err, &roErr)
+	assert.False(t, spy.produceCalled)
+}
+
+func TestGuardReassignmentNeedsClusterConfigEdit(t *testing.T) {
+	w := &recWriter{}
+	svc := audit.NewService(true, audit.LevelAll, w, nil)
+	plan := api.ReassignmentPlan{Goal: api.ReassignDecommission, Moves: []api.PartitionMove{{Topic: "orders-eu", Current: []int32{1}, Target: []int32{2}}}}
+
+	// Topic rights alone do not allow moving replicas.
+	spy := newSpy()
+	g := NewGuard(spy, adminGate(t, false), svc)
+	err := g.ExecuteReassignment(context.Background(), plan, api.ReassignmentOptions{})
+	var denied api.AccessDeniedError
+	assert.ErrorAs(t, err, &denied)
+	assert.False(t, spy.moveCalled)
+
+	cfg := appconfig.AuthzSettings{Profiles: []appconfig.Profile{{
+		Name: "ops", Clusters: []string{"prod"},
+		Permissions: []appconfig.Permission{{Resource: "cluster-configuration", Actions: []string{"all"}}},
+	}}}
+	ops, err := authz.NewGate(cfg, nil, false)
+	require.NoError(t, err)
+	ops.SetCluster("prod")
+	g = NewGuard(spy, ops, svc)
+
+	w.records = nil
+	require.NoError(t, g.ExecuteReassignment(context.Background(), plan, api.ReassignmentOptions{ThrottleBytesPerSec: 1024}))
+	assert.True(t, spy.moveCalled)
+	require.Len(t, w.records, 1)
+	assert.Equal(t, "ExecuteReassignment", w.records[0].Operation)
+	assert.Equal(t, "decommission", w.records[0].Params["goal"])
+	assert.Equal(t, 1, w.records[0].Params["moves"])
+	assert.Equal(t, int64(1024), w.records[0].Params["throttle"])
+}
+
+func TestGuardElectLeadersScope(t *testing.T) {
+	spy := newSpy()
+	g := NewGuard(spy, adminGate(t, false), nil)
+
+	// Topic rights cover elections on that topic's partitions...
+	_, err := g.ElectLeaders(context.Background(), api.ElectLeadersRequest{
+		Type: api.ElectPreferred, Partitions: []api.TopicPartition{{Topic: "orders-eu", Partition: 2}},
+	})
+	require.NoError(t, err)
+	assert.True(t, spy.electCalled)
+
+	// ...but not a cluster-wide election.
+	spy.electCalled = false
+	_, err = g.ElectLeaders(context.Background(), api.ElectLeadersRequest{Type: api.ElectPreferred})
+	var denied api.AccessDeniedError
+	assert.ErrorAs(t, err, &denied)
+	assert.False(t, spy.electCalled)
+}
+
+func TestGuardOffsetResetCopyNeedsSourceView(t *testing.T) {
+	w := &recWriter{}
+	cfg := appconfig.AuthzSettings{Profiles: []appconfig.Profile{{
+		Name: "inventory-team", Clusters: []string{"prod"},
+		Permissions: []appconfig.Permission{{Resource: "consumer-group", Name: "inventory-.*|load-test-.*", Actions: []string{"view", "reset offsets"}}},
+	}}}
+	gate, err := authz.NewGate(cfg, nil, false)
+	require.NoError(t, err)
+	gate.SetCluster("prod")
+	g := NewGuard(newSpy(), gate, audit.NewService(true, audit.LevelAll, w, nil))
+
+	copyFrom := func(source string) api.OffsetResetRequest {
+		return api.OffsetResetRequest{GroupID: "load-test-processor", Topic: "inventory-events", Mode: api.OffsetResetFromGroup, SourceGroupID: source}
+	}
+	plan, err := g.PlanConsumerGroupOffsetReset(context.Background(), copyFrom("inventory-sync"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, plan)
+
+	// Neither the preview nor the commit of planned targets may copy from a
+	// group the profile cannot view.
+	var denied api.AccessDeniedError
+	_, err = g.PlanConsumerGroupOffsetReset(context.Background(), copyFrom("order-processor"))
+	assert.ErrorAs(t, err, &denied)
+	commit := copyFrom("order-processor")
+	commit.Planned = map[int32]int64{0: 1}
+	err = g.ResetConsumerGroupOffsets(context.Background(), commit)
+	assert.ErrorAs(t, err, &denied)
+
+	last := w.records[len(w.records)-1]
+	assert.Equal(t, "ResetConsumerGroupOffsets", last.Operation)
+	assert.Equal(t, "copy-from-group", last.Params["mode"])
+	assert.Equal(t, "order-processor", last.Params["sourceGroup"])
+}
+
+func TestGuardDeleteRecordsBeforeAudited(t *testing.T) {
+	w := &recWriter{}
+	spy := newSpy()
+	g := NewGuard(spy, adminGate(t, false), audit.NewService(true, audit.LevelAll, w, nil))
+	_, err := g.DeleteRecordsBefore(context.Background(), api.DeleteRecordsRequest{Topic: "orders-eu", Offsets: map[int32]int64{3: 100}})
+	require.NoError(t, err)
+	assert.True(t, spy.truncated)
+	require.Len(t, w.records, 1)
+	assert.Equal(t, "DeleteRecordsBefore", w.records[0].Operation)
+	assert.Equal(t, map[int32]int64{3: 100}, w.records[0].Params["offsets"])
+
+	spy.truncated = false
+	_, err = NewGuard(spy, viewerGate(t), nil).DeleteRecordsBefore(context.Background(), api.DeleteRecordsRequest{Topic: "orders-eu", Offsets: map[int32]int64{3: 100}})
+	var denied api.AccessDeniedError
+	assert.ErrorAs(t, err, &denied)
+	assert.False(t, spy.truncated)
+}
+
+func TestGuardScramCredentialsScopedByUser(t *testing.T) {
+	w := &recWriter{}
+	cfg := appconfig.AuthzSettings{Profiles: []appconfig.Profile{{
+		Name: "iam", Clusters: []string{"prod"},
+		Permissions: []appconfig.Permission{{Resource: "scram-users", Name: "svc-.*", Actions: []string{"edit"}}},
+	}}}
+	gate, err := authz.NewGate(cfg, nil, false)
+	require.NoError(t, err)
+	gate.SetCluster("prod")
+	spy := newSpy()
+	g := NewGuard(spy, gate, audit.NewService(true, audit.LevelAll, w, nil))
+
+	upsert := api.ScramCredentialUpsert{User: "svc-a", Mechanism: api.ScramSHA256, Iterations: 4096, Password: "hunter2"}
+	require.NoError(t, g.UpsertUserScramCredentials([]api.ScramCredentialUpsert{upsert}))
+	users, _ := spy.DescribeUserScramCredentials([]string{"svc-a"})
+	assert.Len(t, users, 1)
+	require.Len(t, w.records, 1)
+	assert.Equal(t, "UpsertUserScramCredentials", w.records[0].Operation)
+	assert.Equal(t, map[string]any{"credentials": []string{"svc-a/SCRAM-SHA-256"}}, w.records[0].Params, "the password is never audited")
+
+	// Any user outside the permitted names denies the whole batch.
+	other := upsert
+	other.User = "alice"
+	err = g.UpsertUserScramCredentials([]api.ScramCredentialUpsert{upsert, other})
+	var denied api.AccessDeniedError
+	assert.ErrorAs(t, err, &denied)
+
+	// Edit does not imply delete.
+	err = g.DeleteUserScramCredentials([]api.ScramCredentialDeletion{{User: "svc-a", Mechanism: api.ScramSHA256}})
+	assert.ErrorAs(t, err, &denied)
+	users, _ = spy.DescribeUserScramCredentials([]string{"svc-a"})
+	assert.Len(t, users, 1)
+}
+
+func TestGuardDelegationTokensNeverAuditHMAC(t *testing.T) {
+	w := &recWriter{}
+	cfg := appconfig.AuthzSettings{Profiles: []appconfig.Profile{{
+		Name: "batch-admin", Clusters: []string{"prod"},
+		Permissions: []appconfig.Permission{{Resource: "delegation-tokens", Actions: []string{"create", "edit"}}},
+	}}}
+	gate, err := authz.NewGate(cfg, nil, false)
+	require.NoError(t, err)
+	gate.SetCluster("prod")
+	spy := newSpy()
+	g := NewGuard(spy, gate, audit.NewService(true, audit.LevelAll, w, nil))
+
+	tok, err := g.CreateDelegationToken(api.CreateDelegationTokenRequest{Renewers: []string{"User:scheduler"}, MaxLifetime: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, w.records, 1)
+	assert.Equal(t, "CreateDelegationToken", w.records[0].Operation)
+	assert.Equal(t, map[string]any{"renewers": []string{"User:scheduler"}, "maxLifetime": "1h0m0s", "token": tok.TokenID}, w.records[0].Params, "the HMAC is never audited")
+
+	_, err = g.RenewDelegationToken(tok, time.Hour)
+	require.NoError(t, err)
+
+	// Create and edit do not imply delete (expire).
+	_, err = g.ExpireDelegationToken(tok, 0)
+	var denied api.AccessDeniedError
+	assert.ErrorAs(t, err, &denied)
+	for _, r := range w.records {
+		assert.NotContains(t, fmt.Sprint(r.Params), tok.HMACBase64())
+	}
+}
+
 func TestGuardListingFilteredByViewPermission(t *testing.T) {
 	spy := newSpy() // returns orders-eu, payments
 	g := NewGuard(spy, viewerGate(t), nil)
@@ -122,6 +333,88 @@ func TestGuardListingFilteredByViewPermission(t *testing.T) {
 	assert.Equal(t, []string{"orders-eu"}, names, "only view-permitted topics survive")
 }
 
+func TestGuardSchemaReferencedByFilteredByViewPermission(t *testing.T) {
+	spy := newSpy()
+	refs := []api.SchemaReference{{Name: "com.example.common.Address", Subject: "common-address", Version: 1}}
+	_, err := spy.RegisterSchema("internal-audit-value", `{"type":"string"}`, "", refs)
+	require.NoError(t, err)
+
+	cfg := appconfig.AuthzSettings{Profiles: []appconfig.Profile{{
+		Name: "customers", Clusters: []string{"prod"},
+		Permissions: []appconfig.Permission{{Resource: "schema", Name: "customers-.*|common-.*", Actions: []string{"view"}}},
+	}}}
+	gate, err := authz.NewGate(cfg, nil, false)
+	require.NoError(t, err)
+	gate.SetCluster("prod")
+
+	by, err := NewGuard(spy, gate, nil).GetSchemaReferencedBy("common-address", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []api.SubjectVersion{{Subject: "customers-value", Version: 1}}, by)
+}
+
+func TestGuardSchemaModeNeedsModifyMode(t *testing.T) {
+	w := &recWriter{}
+	cfg := appconfig.AuthzSettings{Profiles: []appconfig.Profile{{
+		Name: "schema-owner", Clusters: []string{"prod"},
+		Permissions: []appconfig.Permission{
+			{Resource: "schema", Name: "orders-.*", Actions: []string{"create", "modify mode"}},
+			{Resource: "schema", Name: "legacy-.*", Actions: []string{"view"}},
+		},
+	}}}
+	gate, err := authz.NewGate(cfg, nil, false)
+	require.NoError(t, err)
+	gate.SetCluster("prod")
+	spy := newSpy()
+	_, err = spy.DeleteSubject("inventory-value", false)
+	require.NoError(t, err)
+	g := NewGuard(spy, gate, audit.NewService(true, audit.LevelAll, w, nil))
+
+	require.NoError(t, g.SetSubjectMode("orders-value", api.SchemaModeReadOnly))
+	require.Len(t, w.records, 1)
+	assert.Equal(t, "SetSubjectMode", w.records[0].Operation)
+	assert.Equal(t, map[string]any{"subject": "orders-value", "mode": "READONLY"}, w.records[0].Params)
+
+	// A subject-scoped grant does not cover the global mode.
+	var denied api.AccessDeniedError
+	assert.ErrorAs(t, g.SetGlobalMode(api.SchemaModeReadOnly), &denied)
+	// Restoring registers a version, so it needs create on the subject.
+	_, err = g.RestoreSchemaVersion("legacy-orders-value", 1)
+	assert.ErrorAs(t, err, &denied)
+	require.Len(t, w.records, 3)
+	assert.Equal(t, audit.ResultAccessDenied, w.records[2].Result)
+
+	deleted, err := g.GetDeletedSubjects()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy-orders-value"}, deleted, "only view-permitted deleted subjects survive")
+}
+
+func TestGuardSchemaRegisterScopedToSubject(t *testing.T) {
+	cfg := appconfig.AuthzSettings{Profiles: []appconfig.Profile{{
+		Name: "schema-owner", Clusters: []string{"prod"},
+		Permissions: []appconfig.Permission{{Resource: "schema", Name: "inventory-.*", Actions: []string{"create"}}},
+	}}}
+	gate, err := authz.NewGate(cfg, nil, false)
+	require.NoError(t, err)
+	gate.SetCluster("prod")
+	spy := newSpy()
+	_, err = spy.DeleteSubject("inventory-value", false)
+	require.NoError(t, err)
+	g := NewGuard(spy, gate, nil)
+
+	// A subject-scoped create grant covers registering and restoring within
+	// its subjects, and neither outside them.
+	_, err = g.RegisterSchema("inventory-key", `{"type":"string"}`, "", nil)
+	require.NoError(t, err)
+	_, err = g.RestoreSchemaVersion("inventory-value", 1)
+	require.NoError(t, err)
+
+	var denied api.AccessDeniedError
+	_, err = g.RegisterSchema("orders-value", `{"type":"string"}`, "", nil)
+	assert.ErrorAs(t, err, &denied)
+	_, err = g.RestoreSchemaVersion("legacy-orders-value", 1)
+	assert.ErrorAs(t, err, &denied)
+}
+
 func TestGuardDisabledGateNoFiltering(t *testing.T) {
 	spy := newSpy()
 	g, err := authz.NewGate(appconfig.AuthzSettings{}, nil, false)
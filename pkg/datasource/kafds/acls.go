@@ -46,10 +46,11 @@ func parsePermission(s string) (sarama.AclPermissionType, error) {
 // GetACLsFiltered implements api.KafkaDataSource. Empty filter fields match any
 // value. Results are stably sorted by principal -> resourceType -> resourceName.
 func (kp KafkaDataSourceKaf) GetACLsFiltered(filter api.ACLFilter) ([]api.ACLEntry, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 
 	saramaFilter := sarama.AclFilter{
 		Version:                   1, // v1 carries the pattern-type filter
@@ -133,10 +134,11 @@ func (kp KafkaDataSourceKaf) CreateACL(entry api.ACLEntry) error {
 		return err
 	}
 
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	resourceACLs := &sarama.ResourceAcls{Resource: resource, Acls: []*sarama.Acl{&acl}}
 	if err := admin.CreateACLs([]*sarama.ResourceAcls{resourceACLs}); err != nil {
 		return fmt.Errorf("failed to create ACL: %w", err)
@@ -173,10 +175,11 @@ func (kp KafkaDataSourceKaf) DeleteACL(entry api.ACLEntry) error {
 		PermissionType:            acl.PermissionType,
 	}
 
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	matching, err := admin.DeleteACL(filter, false)
 	if err != nil {
 		return fmt.Errorf("failed to delete ACL: %w", err)
@@ -28,10 +28,9 @@ func TestGetACLs_MappingAndPatternType(t *testing.T) {
 			},
 		},
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	got, err := KafkaDataSourceKaf{}.GetACLs()
+	got, err := ds.GetACLs()
 	require.NoError(t, err)
 	require.Len(t, got, 2)
 	// Sorted by principal: alice first.
@@ -41,15 +40,14 @@ func TestGetACLs_MappingAndPatternType(t *testing.T) {
 
 func TestGetACLsFiltered_TranslatesFilter(t *testing.T) {
 	admin := &MockClusterAdmin{}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	_, err := KafkaDataSourceKaf{}.GetACLsFiltered(api.ACLFilter{ResourceType: "Topic", ResourceName: "orders", PatternType: "Prefixed"})
+	_, err := ds.GetACLsFiltered(api.ACLFilter{ResourceType: "Topic", ResourceName: "orders", PatternType: "Prefixed"})
 	require.NoError(t, err)
 
 	// ListAcls is a passthrough on the mock; assert nothing errored and the
 	// translation of a bad enum is rejected before any call.
-	_, err = KafkaDataSourceKaf{}.GetACLsFiltered(api.ACLFilter{ResourceType: "Nonsense"})
+	_, err = ds.GetACLsFiltered(api.ACLFilter{ResourceType: "Nonsense"})
 	var ve api.ACLValidationError
 	assert.True(t, errors.As(err, &ve))
 }
@@ -57,10 +55,9 @@ func TestGetACLsFiltered_TranslatesFilter(t *testing.T) {
 func TestCreateACL(t *testing.T) {
 	t.Run("success maps enums", func(t *testing.T) {
 		admin := &MockClusterAdmin{}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := KafkaDataSourceKaf{}.CreateACL(api.ACLEntry{
+		err := ds.CreateACL(api.ACLEntry{
 			Principal: "User:alice", ResourceType: "Topic", ResourceName: "orders",
 			PatternType: "Prefixed", Operation: "Read", Permission: "Allow",
 		})
@@ -76,10 +73,9 @@ func TestCreateACL(t *testing.T) {
 
 	t.Run("invalid principal rejected before call", func(t *testing.T) {
 		admin := &MockClusterAdmin{}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := KafkaDataSourceKaf{}.CreateACL(api.ACLEntry{Principal: "alice", ResourceType: "Topic", ResourceName: "x", Operation: "Read", Permission: "Allow"})
+		err := ds.CreateACL(api.ACLEntry{Principal: "alice", ResourceType: "Topic", ResourceName: "x", Operation: "Read", Permission: "Allow"})
 		var ve api.ACLValidationError
 		assert.True(t, errors.As(err, &ve))
 		assert.Empty(t, admin.CreateACLsCalls)
@@ -87,10 +83,9 @@ func TestCreateACL(t *testing.T) {
 
 	t.Run("invalid operation string rejected", func(t *testing.T) {
 		admin := &MockClusterAdmin{}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := KafkaDataSourceKaf{}.CreateACL(api.ACLEntry{Principal: "User:alice", ResourceType: "Topic", ResourceName: "x", Operation: "Frobnicate", Permission: "Allow"})
+		err := ds.CreateACL(api.ACLEntry{Principal: "User:alice", ResourceType: "Topic", ResourceName: "x", Operation: "Frobnicate", Permission: "Allow"})
 		var ve api.ACLValidationError
 		assert.True(t, errors.As(err, &ve))
 		assert.Empty(t, admin.CreateACLsCalls)
@@ -102,10 +97,9 @@ func TestDeleteACL(t *testing.T) {
 
 	t.Run("success builds exact filter", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockMatchingAcls: []sarama.MatchingAcl{{}}}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := KafkaDataSourceKaf{}.DeleteACL(entry)
+		err := ds.DeleteACL(entry)
 		require.NoError(t, err)
 		require.Len(t, admin.DeleteACLCalls, 1)
 		f := admin.DeleteACLCalls[0]
@@ -118,10 +112,9 @@ func TestDeleteACL(t *testing.T) {
 
 	t.Run("zero matches -> ACLNotFoundError", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockMatchingAcls: nil}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := KafkaDataSourceKaf{}.DeleteACL(entry)
+		err := ds.DeleteACL(entry)
 		var nf api.ACLNotFoundError
 		assert.True(t, errors.As(err, &nf))
 	})
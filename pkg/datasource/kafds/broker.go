@@ -23,10 +23,11 @@ var clusterReadOnly = func() bool { return false }
 // GetBrokers implements api.KafkaDataSource. It describes the cluster and maps
 // each online broker to api.BrokerInfo, marking the active controller.
 func (kp KafkaDataSourceKaf) GetBrokers() ([]api.BrokerInfo, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 	brokers, controllerID, err := admin.DescribeCluster()
 	if err != nil {
 		return nil, fmt.Errorf("describing cluster: %w", err)
@@ -63,10 +64,11 @@ func splitHostPort(addr string) (string, int32) {
 
 // GetBrokerConfig implements api.KafkaDataSource.
 func (kp KafkaDataSourceKaf) GetBrokerConfig(brokerID int32) ([]api.BrokerConfigEntry, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 	brokers, _, err := admin.DescribeCluster()
 	if err != nil {
 		return nil, fmt.Errorf("describing cluster: %w", err)
@@ -131,10 +133,11 @@ func configSourceString(s sarama.ConfigSource) string {
 // AlterBrokerConfig implements api.KafkaDataSource using an incremental SET so
 // other dynamic configs are preserved.
 func (kp KafkaDataSourceKaf) AlterBrokerConfig(brokerID int32, key, value string) error {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	brokers, _, err := admin.DescribeCluster()
 	if err != nil {
 		return fmt.Errorf("describing cluster: %w", err)
@@ -153,16 +156,6 @@ func (kp KafkaDataSourceKaf) AlterBrokerConfig(brokerID int32, key, value string
 	return nil
 }
 
-// AlterReplicaLogDir implements api.KafkaDataSource.
-//
-// sarama v1.45.1 does not implement AlterReplicaLogDirsRequest (only the API-key
-// constant exists), and ClusterAdmin exposes no helper, so there is no protocol
-// path to perform the move against a real cluster. We therefore return a typed
-// NotSupportedError; the mock datasource implements the full flow for UI work.
-func (kp KafkaDataSourceKaf) AlterReplicaLogDir(brokerID int32, topic string, partition int32, logDir string) error {
-	return api.NotSupportedError{Operation: "AlterReplicaLogDir"}
-}
-
 // GetBrokerMetrics implements api.KafkaDataSource. Per-broker metrics require the
 // metrics-collection pipeline (feature 12), which does not exist yet.
 func (kp KafkaDataSourceKaf) GetBrokerMetrics(brokerID int32) (string, error) {
@@ -171,10 +164,11 @@ func (kp KafkaDataSourceKaf) GetBrokerMetrics(brokerID int32) (string, error) {
 
 // GetBrokerLogDirs implements api.KafkaDataSource.
 func (kp KafkaDataSourceKaf) GetBrokerLogDirs(brokerIDs []int32) (map[int32][]api.BrokerLogDir, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 
 	clusterBrokers, _, err := admin.DescribeCluster()
 	if err != nil {
@@ -0,0 +1,276 @@
+package kafds
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+)
+
+// brokerConn sends hand-encoded requests for APIs sarama v1.45.1 does not
+// implement over a dedicated connection to one broker. SASL authentication on
+// that connection is hand-encoded too (SaslHandshake v1 + SaslAuthenticate v0),
+// which every broker since Kafka 1.0 supports.
+
+const (
+	apiKeySaslHandshake    int16 = 17
+	apiKeyApiVersions      int16 = 18
+	apiKeySaslAuthenticate int16 = 36
+
+	// maxResponseSize bounds a response frame so a confused peer cannot make
+	// us allocate without limit.
+	maxResponseSize = 16 << 20
+)
+
+// dialBroker opens a raw connection to a broker, honouring the TLS settings of
+// cfg. It is a var so tests can serve the broker side over a net.Pipe.
+var dialBroker = func(cfg *sarama.Config, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: cfg.Net.DialTimeout, KeepAlive: cfg.Net.KeepAlive}
+	if cfg.Net.TLS.Enable {
+		tc := cfg.Net.TLS.Config
+		if tc == nil {
+			tc = &tls.Config{}
+		}
+		return tls.DialWithDialer(dialer, "tcp", addr, tc)
+	}
+	return dialer.Dial("tcp", addr)
+}
+
+// brokerConn is a raw request/response connection to a single broker.
+type brokerConn struct {
+	conn          net.Conn
+	cfg           *sarama.Config
+	correlationID int32
+}
+
+// openBrokerConn dials addr and authenticates when SASL is configured;
+// operation names the caller's request in errors. The caller closes the
+// returned connection.
+func openBrokerConn(cfg *sarama.Config, addr string, brokerID int32, operation string) (*brokerConn, error) {
+	conn, err := dialBroker(cfg, addr)
+	if err != nil {
+		return nil, api.NewConnectionErrorWithCause(fmt.Sprintf("unable to connect to broker %d", brokerID), err)
+	}
+	bc := &brokerConn{conn: conn, cfg: cfg}
+	if cfg.Net.SASL.Enable {
+		if err := bc.authenticate(operation); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return bc, nil
+}
+
+func (c *brokerConn) Close() error { return c.conn.Close() }
+
+// roundTripFlexible is roundTrip for flexible API versions: request header v2
+// and response header v1 each add an (empty) tagged-field section.
+func (c *brokerConn) roundTripFlexible(apiKey, version int16, body []byte) ([]byte, error) {
+	// Header v2 is header v1 followed by its tags, so they lead the body.
+	resp, err := c.roundTrip(apiKey, version, append([]byte{0}, body...))
+	if err != nil {
+		return nil, err
+	}
+	d := wireDecoder{buf: resp}
+	d.skipTags()
+	if d.err != nil {
+		return nil, d.err
+	}
+	return resp[d.off:], nil
+}
+
+// apiVersions returns the highest version the broker supports per API key
+// (ApiVersions v0, which every broker answers).
+func (c *brokerConn) apiVersions() (map[int16]int16, error) {
+	resp, err := c.roundTrip(apiKeyApiVersions, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	d := wireDecoder{buf: resp}
+	code := sarama.KError(d.int16())
+	out := map[int16]int16{}
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		key := d.int16()
+		d.int16() // min version
+		out[key] = d.int16()
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("decoding ApiVersions response: %w", d.err)
+	}
+	if code != sarama.ErrNoError {
+		return nil, code
+	}
+	return out, nil
+}
+
+// roundTrip sends one request and returns the response body (the bytes after
+// the v0 response header).
+func (c *brokerConn) roundTrip(apiKey, version int16, body []byte) ([]byte, error) {
+	c.correlationID++
+	if t := c.cfg.Net.WriteTimeout; t > 0 {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(t))
+	}
+	if _, err := c.conn.Write(requestFrame(apiKey, version, c.correlationID, c.cfg.ClientID, body)); err != nil {
+		return nil, err
+	}
+	if t := c.cfg.Net.ReadTimeout; t > 0 {
+		_ = c.conn.SetReadDeadline(time.Now().Add(t))
+	}
+	resp, err := readFrame(c.conn)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 4 {
+		return nil, errShortResponse
+	}
+	if got := int32(binary.BigEndian.Uint32(resp)); got != c.correlationID {
+		return nil, fmt.Errorf("correlation id mismatch: sent %d, got %d", c.correlationID, got)
+	}
+	return resp[4:], nil
+}
+
+// authenticate runs the SASL exchange configured in cfg.Net.SASL.
+func (c *brokerConn) authenticate(operation string) error {
+	sasl := c.cfg.Net.SASL
+	mech := string(sasl.Mechanism)
+	if mech == "" {
+		mech = sarama.SASLTypePlaintext
+	}
+
+	var e wireEncoder
+	e.putString(mech)
+	resp, err := c.roundTrip(apiKeySaslHandshake, 1, e.buf)
+	if err != nil {
+		return api.NewConnectionErrorWithCause("SASL handshake failed", err)
+	}
+	d := wireDecoder{buf: resp}
+	code := sarama.KError(d.int16())
+	var offered []string
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		offered = append(offered, d.string())
+	}
+	if d.err != nil {
+		return fmt.Errorf("decoding SaslHandshake response: %w", d.err)
+	}
+	if code != sarama.ErrNoError {
+		return api.NewAuthenticationError(fmt.Sprintf("%v (broker offers %s)", code, strings.Join(offered, ", ")), mech)
+	}
+
+	switch mech {
+	case sarama.SASLTypePlaintext:
+		_, err := c.saslAuthenticate(mech, []byte(sasl.AuthIdentity+"\x00"+sasl.User+"\x00"+sasl.Password))
+		return err
+	case sarama.SASLTypeSCRAMSHA256, sarama.SASLTypeSCRAMSHA512:
+		if sasl.SCRAMClientGeneratorFunc == nil {
+			return api.NewAuthenticationError("no SCRAM client configured", mech)
+		}
+		scram := sasl.SCRAMClientGeneratorFunc()
+		if err := scram.Begin(sasl.User, sasl.Password, sasl.SCRAMAuthzID); err != nil {
+			return api.NewAuthenticationError(err.Error(), mech)
+		}
+		msg, err := scram.Step("")
+		for err == nil && !scram.Done() {
+			var challenge []byte
+			if challenge, err = c.saslAuthenticate(mech, []byte(msg)); err != nil {
+				return err
+			}
+			msg, err = scram.Step(string(challenge))
+		}
+		if err != nil {
+			return api.NewAuthenticationError(err.Error(), mech)
+		}
+		return nil
+	case sarama.SASLTypeOAuth:
+		if sasl.TokenProvider == nil {
+			return api.NewAuthenticationError("no token provider configured", mech)
+		}
+		tok, err := sasl.TokenProvider.Token()
+		if err != nil {
+			return api.NewAuthenticationError(err.Error(), mech)
+		}
+		_, err = c.saslAuthenticate(mech, oauthBearerMessage(tok))
+		return err
+	default:
+		return api.NotSupportedError{Operation: operation + " over SASL/" + mech}
+	}
+}
+
+// saslAuthenticate sends one SaslAuthenticate v0 step and returns the
+// broker's challenge.
+func (c *brokerConn) saslAuthenticate(mech string, auth []byte) ([]byte, error) {
+	var e wireEncoder
+	e.putBytes(auth)
+	resp, err := c.roundTrip(apiKeySaslAuthenticate, 0, e.buf)
+	if err != nil {
+		return nil, api.NewConnectionErrorWithCause("SASL authenticate failed", err)
+	}
+	d := wireDecoder{buf: resp}
+	code := sarama.KError(d.int16())
+	msg := d.string()
+	challenge := d.bytes()
+	if d.err != nil {
+		return nil, fmt.Errorf("decoding SaslAuthenticate response: %w", d.err)
+	}
+	if code != sarama.ErrNoError {
+		if msg == "" {
+			msg = code.Error()
+		}
+		return nil, api.NewAuthenticationError(msg, mech)
+	}
+	return challenge, nil
+}
+
+// oauthBearerMessage builds the RFC 7628 client initial response, with
+// extensions in a stable order.
+func oauthBearerMessage(tok *sarama.AccessToken) []byte {
+	var b strings.Builder
+	b.WriteString("n,,\x01auth=Bearer " + tok.Token + "\x01")
+	keys := make([]string, 0, len(tok.Extensions))
+	for k := range tok.Extensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(k + "=" + tok.Extensions[k] + "\x01")
+	}
+	b.WriteString("\x01")
+	return []byte(b.String())
+}
+
+// requestFrame wraps body in a size-prefixed frame with a v1 request header
+// (api key, api version, correlation id, client id).
+func requestFrame(apiKey, version int16, correlationID int32, clientID string, body []byte) []byte {
+	var e wireEncoder
+	e.putInt32(0) // size, patched below
+	e.putInt16(apiKey)
+	e.putInt16(version)
+	e.putInt32(correlationID)
+	e.putString(clientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	return e.buf
+}
+
+// readFrame reads one size-prefixed response frame.
+func readFrame(r io.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxResponseSize {
+		return nil, fmt.Errorf("kafka response of %d bytes exceeds limit", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
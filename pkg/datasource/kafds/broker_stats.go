@@ -11,19 +11,27 @@ import (
 // GetBrokerStats implements api.KafkaDataSource. It combines partition
 // distribution (from topic metadata) with disk usage (from log dirs).
 func (kp KafkaDataSourceKaf) GetBrokerStats() (map[int32]api.BrokerStats, api.BrokerSummary, error) {
-	admin, err := getClusterAdmin()
+	stats, summary, _, err := kp.brokerStats(false)
+	return stats, summary, err
+}
+
+// brokerStats is GetBrokerStats plus, when withQuorum is set, the controller
+// quorum (nil for ZooKeeper clusters and KRaft clusters without DescribeQuorum).
+func (kp KafkaDataSourceKaf) brokerStats(withQuorum bool) (map[int32]api.BrokerStats, api.BrokerSummary, *api.QuorumInfo, error) {
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
-		return nil, api.BrokerSummary{}, err
+		return nil, api.BrokerSummary{}, nil, err
 	}
+	defer admin.Close()
 
 	brokers, controllerID, err := admin.DescribeCluster()
 	if err != nil {
-		return nil, api.BrokerSummary{}, fmt.Errorf("describing cluster: %w", err)
+		return nil, api.BrokerSummary{}, nil, fmt.Errorf("describing cluster: %w", err)
 	}
 
 	topicDetails, err := admin.ListTopics()
 	if err != nil {
-		return nil, api.BrokerSummary{}, fmt.Errorf("listing topics: %w", err)
+		return nil, api.BrokerSummary{}, nil, fmt.Errorf("listing topics: %w", err)
 	}
 	names := make([]string, 0, len(topicDetails))
 	for name := range topicDetails {
@@ -34,7 +42,7 @@ func (kp KafkaDataSourceKaf) GetBrokerStats() (map[int32]api.BrokerStats, api.Br
 	if len(names) > 0 {
 		metadata, err = admin.DescribeTopics(names)
 		if err != nil {
-			return nil, api.BrokerSummary{}, fmt.Errorf("describing topics: %w", err)
+			return nil, api.BrokerSummary{}, nil, fmt.Errorf("describing topics: %w", err)
 		}
 	}
 
@@ -47,6 +55,10 @@ func (kp KafkaDataSourceKaf) GetBrokerStats() (map[int32]api.BrokerStats, api.Br
 	summary.ControllerID = controllerIDPtr(controllerID)
 	summary.ClusterVersion = kp.bestEffortClusterVersion(admin, controllerID)
 	summary.ControllerType = "Unknown"
+	var quorum *api.QuorumInfo
+	if len(brokers) > 0 {
+		summary.ControllerType, quorum = kp.detectCoordination(admin, brokers, controllerID, withQuorum)
+	}
 
 	// Fold disk usage from log dirs into the per-broker stats (best effort).
 	if logDirs, ldErr := kp.GetBrokerLogDirs(brokerIDs); ldErr == nil {
@@ -59,7 +71,7 @@ func (kp KafkaDataSourceKaf) GetBrokerStats() (map[int32]api.BrokerStats, api.Br
 		}
 	}
 
-	return stats, summary, nil
+	return stats, summary, quorum, nil
 }
 
 // computePartitionStats is the pure partition-distribution computation. It
@@ -22,22 +22,22 @@ func newTestBroker(id int32, addr string) *sarama.Broker {
 	return b
 }
 
-// installMockAdmin wires the given admin as the active cluster admin and returns
-// a restore func.
-func installMockAdmin(admin *MockClusterAdmin) func() {
-	origFactory := kafkaClientFactory
-	origCluster := currentCluster
-	kafkaClientFactory = &MockKafkaClientFactory{MockClusterAdmin: admin}
-	currentCluster = &config.Cluster{Brokers: []string{"localhost:9092"}}
-	return func() {
-		kafkaClientFactory = origFactory
-		currentCluster = origCluster
+// newTestDS builds a datasource whose session targets cluster and whose admin
+// and client come from factory. Each test gets its own instance, so nothing is
+// shared through package state.
+func newTestDS(factory KafkaClientFactory, cluster *config.Cluster) KafkaDataSourceKaf {
+	ds := NewKafkaDataSourceKafWithDeps(factory, &DefaultConfigManager{})
+	ds.session.current = cluster
+	if cluster != nil {
+		ds.session.cfg.Clusters = []*config.Cluster{cluster}
+		ds.session.cfg.CurrentCluster = cluster.Name
 	}
+	return *ds
 }
 
-func brokerDS() KafkaDataSourceKaf {
-	// getClusterAdmin uses the global kafkaClientFactory, so a zero value suffices.
-	return KafkaDataSourceKaf{}
+// mockAdminDS returns a datasource whose cluster admin is the given mock.
+func mockAdminDS(admin ClusterAdminInterface) KafkaDataSourceKaf {
+	return newTestDS(&MockKafkaClientFactory{MockClusterAdmin: admin}, &config.Cluster{Brokers: []string{"localhost:9092"}})
 }
 
 // --- BR-3: GetBrokers ---
@@ -88,10 +88,9 @@ func TestGetBrokers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			restore := installMockAdmin(tt.admin)
-			defer restore()
+			ds := mockAdminDS(tt.admin)
 
-			brokers, err := brokerDS().GetBrokers()
+			brokers, err := ds.GetBrokers()
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -113,13 +112,12 @@ func TestGetBrokers(t *testing.T) {
 }
 
 func TestGetBrokers_HostPortParsing(t *testing.T) {
-	restore := installMockAdmin(&MockClusterAdmin{
+	ds := mockAdminDS(&MockClusterAdmin{
 		MockBrokers:      []*sarama.Broker{newTestBroker(1, "kafka.internal:9092")},
 		MockControllerID: 1,
 	})
-	defer restore()
 
-	brokers, err := brokerDS().GetBrokers()
+	brokers, err := ds.GetBrokers()
 	assert.NoError(t, err)
 	assert.Equal(t, "kafka.internal", brokers[0].Host)
 	assert.Equal(t, int32(9092), brokers[0].Port)
@@ -199,18 +197,16 @@ func logDirsAdmin() *MockClusterAdmin {
 func TestGetBrokerLogDirs_Filter(t *testing.T) {
 	t.Run("empty means all cluster brokers requested", func(t *testing.T) {
 		admin := logDirsAdmin()
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 		// Mock returns whatever it has; the request must include all 3 IDs.
-		_, err := brokerDS().GetBrokerLogDirs(nil)
+		_, err := ds.GetBrokerLogDirs(nil)
 		assert.NoError(t, err)
 	})
 
 	t.Run("subset and unknown IDs dropped", func(t *testing.T) {
 		admin := logDirsAdmin()
-		restore := installMockAdmin(admin)
-		defer restore()
-		res, err := brokerDS().GetBrokerLogDirs([]int32{1, 99})
+		ds := mockAdminDS(admin)
+		res, err := ds.GetBrokerLogDirs([]int32{1, 99})
 		assert.NoError(t, err)
 		// dir 1 present; broker 99 not in cluster so never requested.
 		assert.Contains(t, res, int32(1))
@@ -220,9 +216,8 @@ func TestGetBrokerLogDirs_Filter(t *testing.T) {
 
 	t.Run("dir error mapped to Error string", func(t *testing.T) {
 		admin := logDirsAdmin()
-		restore := installMockAdmin(admin)
-		defer restore()
-		res, err := brokerDS().GetBrokerLogDirs([]int32{2})
+		ds := mockAdminDS(admin)
+		res, err := ds.GetBrokerLogDirs([]int32{2})
 		assert.NoError(t, err)
 		assert.NotEmpty(t, res[2][0].Error)
 	})
@@ -263,10 +258,9 @@ func TestGetBrokerConfig_Mapping(t *testing.T) {
 			}},
 		},
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	entries, err := brokerDS().GetBrokerConfig(1)
+	entries, err := ds.GetBrokerConfig(1)
 	assert.NoError(t, err)
 	byName := map[string]api.BrokerConfigEntry{}
 	for _, e := range entries {
@@ -287,10 +281,9 @@ func TestGetBrokerConfig_UnknownBroker(t *testing.T) {
 		MockBrokers:      []*sarama.Broker{newTestBroker(1, "h:9092")},
 		MockControllerID: 1,
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	_, err := brokerDS().GetBrokerConfig(99)
+	_, err := ds.GetBrokerConfig(99)
 	var bnf api.BrokerNotFoundError
 	assert.True(t, errors.As(err, &bnf))
 	assert.Equal(t, int32(99), bnf.BrokerID)
@@ -304,27 +297,25 @@ func TestGetBrokerConfig_ReadOnlyClusterOverride(t *testing.T) {
 			{Name: "a", Value: "1", Source: sarama.SourceDynamicBroker, ReadOnly: false},
 		},
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
 	origRO := clusterReadOnly
 	clusterReadOnly = func() bool { return true }
 	defer func() { clusterReadOnly = origRO }()
 
-	entries, err := brokerDS().GetBrokerConfig(1)
+	entries, err := ds.GetBrokerConfig(1)
 	assert.NoError(t, err)
 	assert.True(t, entries[0].ReadOnly, "read-only cluster forces ReadOnly=true")
 }
 
-// --- BR-7: AlterBrokerConfig / AlterReplicaLogDir ---
+// --- BR-7: AlterBrokerConfig (AlterReplicaLogDir: replica_logdir_test.go) ---
 
 func TestAlterBrokerConfig(t *testing.T) {
 	t.Run("success records SET call", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockBrokers: []*sarama.Broker{newTestBroker(1, "h:9092")}, MockControllerID: 1}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().AlterBrokerConfig(1, "retention.ms", "1000")
+		err := ds.AlterBrokerConfig(1, "retention.ms", "1000")
 		assert.NoError(t, err)
 		assert.Len(t, admin.IncrementalAlterConfigCalls, 1)
 		assert.Equal(t, AlterConfigCall{Name: "1", Key: "retention.ms", Value: "1000"}, admin.IncrementalAlterConfigCalls[0])
@@ -336,10 +327,9 @@ func TestAlterBrokerConfig(t *testing.T) {
 			MockControllerID: 1,
 			AlterConfigErr:   errors.New("policy violation"),
 		}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().AlterBrokerConfig(1, "k", "v")
+		err := ds.AlterBrokerConfig(1, "k", "v")
 		var ice api.InvalidConfigError
 		assert.True(t, errors.As(err, &ice))
 		assert.Equal(t, "k", ice.Key)
@@ -348,29 +338,18 @@ func TestAlterBrokerConfig(t *testing.T) {
 
 	t.Run("unknown broker -> BrokerNotFoundError", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockBrokers: []*sarama.Broker{newTestBroker(1, "h:9092")}, MockControllerID: 1}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().AlterBrokerConfig(42, "k", "v")
+		err := ds.AlterBrokerConfig(42, "k", "v")
 		var bnf api.BrokerNotFoundError
 		assert.True(t, errors.As(err, &bnf))
 	})
 }
 
-func TestAlterReplicaLogDir_NotSupported(t *testing.T) {
-	restore := installMockAdmin(&MockClusterAdmin{})
-	defer restore()
-
-	err := brokerDS().AlterReplicaLogDir(1, "t", 0, "/d")
-	var ns api.NotSupportedError
-	assert.True(t, errors.As(err, &ns))
-}
-
 func TestGetBrokerMetrics_NotAvailable(t *testing.T) {
-	restore := installMockAdmin(&MockClusterAdmin{})
-	defer restore()
+	ds := mockAdminDS(&MockClusterAdmin{})
 
-	_, err := brokerDS().GetBrokerMetrics(1)
+	_, err := ds.GetBrokerMetrics(1)
 	var mna api.MetricsNotAvailableError
 	assert.True(t, errors.As(err, &mna))
 	assert.Equal(t, int32(1), mna.BrokerID)
@@ -10,13 +10,18 @@ import (
 
 // GetClusterStatistics implements api.KafkaDataSource by reusing the broker
 // statistics collector (DescribeCluster + metadata + DescribeLogDirs) and
-// folding its per-broker/summary view into a cluster-level snapshot.
+// folding its per-broker/summary view into a cluster-level snapshot. The
+// coordination type comes from DescribeQuorum, falling back to broker config.
+// Clusters other than the active one are read through a session of their own.
 //
-// ponytail: KRaft/ZooKeeper quorum detection is not wrapped by
-// ClusterAdminInterface, so CoordinationType is best-effort "unknown". Byte
-// throughput isn't available from Sarama admin APIs (metrics feature owns it).
-func (kp KafkaDataSourceKaf) GetClusterStatistics(_ context.Context, _ string) (api.ClusterStatistics, error) {
-	perBroker, summary, err := kp.GetBrokerStats()
+// ponytail: byte throughput isn't available from Sarama admin APIs (metrics
+// feature owns it).
+func (kp KafkaDataSourceKaf) GetClusterStatistics(_ context.Context, clusterName string) (api.ClusterStatistics, error) {
+	src, err := kp.forCluster(clusterName)
+	if err != nil {
+		return api.ClusterStatistics{}, err
+	}
+	perBroker, summary, quorum, err := src.brokerStats(true)
 	if err != nil {
 		return api.ClusterStatistics{}, err
 	}
@@ -30,6 +35,7 @@ func (kp KafkaDataSourceKaf) GetClusterStatistics(_ context.Context, _ string) (
 		UnderReplicatedPartitions: summary.UnderReplicated,
 		Version:                   summary.ClusterVersion,
 		CoordinationType:          coordinationType(summary.ControllerType),
+		Quorum:                    quorum,
 	}
 	if summary.ControllerID != nil {
 		stats.ControllerID = *summary.ControllerID
@@ -46,6 +52,24 @@ func (kp KafkaDataSourceKaf) GetClusterStatistics(_ context.Context, _ string) (
 	return stats, nil
 }
 
+// forCluster returns a datasource reading the named cluster: kp itself for the
+// active cluster (or an empty name), otherwise one over the cluster's peer
+// session.
+func (kp KafkaDataSourceKaf) forCluster(name string) (KafkaDataSourceKaf, error) {
+	if name == "" {
+		return kp, nil
+	}
+	s := kp.sess()
+	p, ok := s.peer(name)
+	if !ok {
+		return KafkaDataSourceKaf{}, api.ClusterNotFoundError{Name: name}
+	}
+	if p == s {
+		return kp, nil
+	}
+	return KafkaDataSourceKaf{clientFactory: kp.clientFactory, configManager: kp.configManager, session: p}, nil
+}
+
 // coordinationType normalizes the broker-summary controller type to the
 // cluster-statistics vocabulary.
 func coordinationType(controllerType string) string {
@@ -78,7 +102,7 @@ func (kp KafkaDataSourceKaf) GetClusterCapabilities(_ context.Context, clusterNa
 	// acl-view: best effort, only meaningful for the active cluster (uses the
 	// active admin client). A DescribeAcls that succeeds implies view access.
 	if clusterName == kp.GetContext() {
-		if admin, err := getClusterAdmin(); err == nil {
+		if admin, err := kp.getClusterAdmin(); err == nil {
 			if _, err := admin.ListAcls(sarama.AclFilter{
 				ResourceType:   sarama.AclResourceAny,
 				PermissionType: sarama.AclPermissionAny,
@@ -1,10 +1,16 @@
 package kafds
 
 import (
+	"context"
+	"errors"
+	"net"
 	"testing"
 
 	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+	"github.com/birdayz/kaf/pkg/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCoordinationType(t *testing.T) {
@@ -21,3 +27,60 @@ func TestClusterStatisticsShape(t *testing.T) {
 	assert.Equal(t, 3, s.BrokerCount)
 	assert.Equal(t, int32(1), s.ControllerID)
 }
+
+// adminsByBroker hands out the cluster admin registered for the first
+// bootstrap broker, i.e. one admin per configured cluster.
+type adminsByBroker map[string]ClusterAdminInterface
+
+func (f adminsByBroker) CreateClusterAdmin(brokers []string, _ *sarama.Config) (ClusterAdminInterface, error) {
+	return f[brokers[0]], nil
+}
+
+func (f adminsByBroker) CreateClient([]string, *sarama.Config) (sarama.Client, error) {
+	return nil, errors.New("no client")
+}
+
+func TestGetClusterStatisticsReadsTheNamedCluster(t *testing.T) {
+	orig := dialBroker
+	t.Cleanup(func() { dialBroker = orig })
+	dialBroker = func(*sarama.Config, string) (net.Conn, error) { return nil, errors.New("offline") }
+
+	zk := &MockClusterAdmin{
+		MockBrokers:       []*sarama.Broker{newTestBroker(1, "a:9092")},
+		MockControllerID:  1,
+		MockConfigEntries: []sarama.ConfigEntry{{Name: "zookeeper.connect", Value: "zk:2181"}},
+	}
+	kraft := &MockClusterAdmin{
+		MockBrokers:       []*sarama.Broker{newTestBroker(1, "b:9092"), newTestBroker(2, "b2:9092"), newTestBroker(3, "b3:9092")},
+		MockControllerID:  1,
+		MockConfigEntries: []sarama.ConfigEntry{{Name: "process.roles", Value: "broker"}},
+	}
+	ds := NewKafkaDataSourceKafWithDeps(adminsByBroker{"a:9092": zk, "b:9092": kraft}, &DefaultConfigManager{})
+	ds.session.cfg = config.Config{Clusters: []*config.Cluster{
+		{Name: "a", Brokers: []string{"a:9092"}},
+		{Name: "b", Brokers: []string{"b:9092"}},
+	}}
+	require.NoError(t, ds.SetContext("a"))
+
+	ctx := context.Background()
+	a, err := ds.GetClusterStatistics(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.BrokerCount)
+	assert.Equal(t, "zookeeper", a.CoordinationType)
+
+	b, err := ds.GetClusterStatistics(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, 3, b.BrokerCount, "the inactive cluster is read through its own session")
+	assert.Equal(t, "kraft", b.CoordinationType)
+	assert.Equal(t, "a", ds.GetContext(), "reading another cluster does not switch context")
+
+	_, err = ds.GetClusterStatistics(ctx, "missing")
+	var nf api.ClusterNotFoundError
+	assert.ErrorAs(t, err, &nf)
+
+	peer, _ := ds.session.peer("b")
+	require.NoError(t, ds.SetContext("b"))
+	assert.Empty(t, ds.session.peers, "peers are dropped with the session's caches")
+	mode, _ := peer.cachedCoordination()
+	assert.Empty(t, mode, "a dropped peer forgets its mode")
+}
@@ -0,0 +1,222 @@
+package kafds
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/ui/shared"
+	"github.com/IBM/sarama"
+)
+
+// connProbeAfter is how long a pooled connection may sit idle before the next
+// lease probes it (metadata refresh for the client, DescribeCluster for the
+// admin). A failed probe drops the connection and dials a fresh one.
+var connProbeAfter = time.Minute
+
+// connPool keeps one long-lived sarama client and cluster admin for the
+// session's active cluster. Building either costs a full metadata bootstrap
+// (plus SASL/OAuth handshakes), so reusing them keeps remote clusters
+// responsive. The pool belongs to a session and is torn down whenever the
+// active cluster changes.
+//
+// mu only guards the bookkeeping: dials and health probes run without it, so
+// one slow or unreachable broker does not stall every other caller.
+type connPool struct {
+	mu     sync.Mutex
+	closed bool
+
+	client *pooledConn
+	admin  *pooledConn
+}
+
+// pooledConn is one pooled connection and the number of leases on it. A
+// retired connection (replaced after a failed probe, or dropped with the pool)
+// is closed when its last lease is released, never under a caller using it.
+type pooledConn struct {
+	conn    io.Closer
+	used    time.Time
+	leases  int
+	retired bool
+}
+
+// connHealth checks a pooled connection before it is leased again. dead is
+// cheap and runs on every lease; probe is a broker round trip run only once
+// the connection has been idle for connProbeAfter. Either may be nil.
+type connHealth struct {
+	dead  func(io.Closer) bool
+	probe func(io.Closer) error
+}
+
+func newConnPool() *connPool {
+	return &connPool{}
+}
+
+// leaseClient returns a lease on the pooled client, dialing one when none is
+// alive. Closing the lease releases it; the client stays open for the pool.
+func (p *connPool) leaseClient(dial func() (sarama.Client, error)) (sarama.Client, error) {
+	pc, err := p.lease(&p.client, "client", func() (io.Closer, error) {
+		client, err := dial()
+		if client == nil {
+			return nil, err
+		}
+		return client, err
+	}, connHealth{
+		dead:  func(c io.Closer) bool { return c.(sarama.Client).Closed() },
+		probe: func(c io.Closer) error { return c.(sarama.Client).RefreshMetadata() },
+	})
+	if pc == nil {
+		return nil, err
+	}
+	return pooledClient{Client: pc.conn.(sarama.Client), release: p.releaser(pc)}, nil
+}
+
+// leaseAdmin returns a lease on the pooled cluster admin, dialing one when
+// none is alive. Closing the lease releases it.
+func (p *connPool) leaseAdmin(dial func() (ClusterAdminInterface, error)) (ClusterAdminInterface, error) {
+	pc, err := p.lease(&p.admin, "admin", func() (io.Closer, error) {
+		admin, err := dial()
+		if admin == nil {
+			return nil, err
+		}
+		return admin, err
+	}, connHealth{
+		probe: func(c io.Closer) error {
+			_, _, err := c.(ClusterAdminInterface).DescribeCluster()
+			return err
+		},
+	})
+	if pc == nil {
+		return nil, err
+	}
+	return pooledAdmin{ClusterAdminInterface: pc.conn.(ClusterAdminInterface), release: p.releaser(pc)}, nil
+}
+
+// lease takes a lease on the connection in slot, replacing it when it is dead
+// or fails its idle probe. A nil result carries the dial error; failed dials
+// are never cached, so the next lease retries.
+func (p *connPool) lease(slot **pooledConn, what string, dial func() (io.Closer, error), health connHealth) (*pooledConn, error) {
+	p.mu.Lock()
+	pc := *slot
+	if p.closed {
+		pc = nil
+	}
+	var dead io.Closer
+	if pc != nil && health.dead != nil && health.dead(pc.conn) {
+		if p.retireLocked(slot, pc) {
+			dead = pc.conn
+		}
+		pc = nil
+	}
+	probe := false
+	if pc != nil {
+		pc.leases++
+		// Stamp now so concurrent leases don't all probe the same connection.
+		probe = health.probe != nil && time.Since(pc.used) > connProbeAfter
+		pc.used = time.Now()
+	}
+	p.mu.Unlock()
+	if dead != nil {
+		_ = dead.Close()
+	}
+
+	if probe {
+		if err := health.probe(pc.conn); err != nil {
+			shared.Log.Warn("pooled kafka "+what+" unhealthy, reconnecting", "err", err)
+			p.mu.Lock()
+			p.retireLocked(slot, pc)
+			p.mu.Unlock()
+			p.release(pc)
+			pc = nil
+		}
+	}
+	if pc != nil {
+		return pc, nil
+	}
+
+	conn, err := dial()
+	if err != nil || conn == nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fresh := &pooledConn{conn: conn, used: time.Now(), leases: 1}
+	if p.closed || *slot != nil {
+		// Torn down meanwhile, or a concurrent lease dialed first: this
+		// connection serves only the caller and closes with its lease.
+		fresh.retired = true
+	} else {
+		*slot = fresh
+	}
+	return fresh, nil
+}
+
+// retireLocked takes pc out of slot and reports whether it has no leases left,
+// i.e. the caller must close it. Callers hold mu.
+func (p *connPool) retireLocked(slot **pooledConn, pc *pooledConn) bool {
+	if *slot == pc {
+		*slot = nil
+	}
+	if pc.retired {
+		return false
+	}
+	pc.retired = true
+	return pc.leases == 0
+}
+
+// release drops one lease on pc and closes it when it was the last lease on a
+// retired connection.
+func (p *connPool) release(pc *pooledConn) {
+	p.mu.Lock()
+	pc.leases--
+	last := pc.retired && pc.leases == 0
+	p.mu.Unlock()
+	if last {
+		_ = pc.conn.Close()
+	}
+}
+
+// releaser returns the Close of a lease; closing a lease twice releases once.
+func (p *connPool) releaser(pc *pooledConn) func() {
+	return sync.OnceFunc(func() { p.release(pc) })
+}
+
+// close retires the pooled connections: idle ones close now, leased ones when
+// their last lease is released. Leases handed out afterwards are one-shot
+// connections that close with their lease.
+func (p *connPool) close() {
+	p.mu.Lock()
+	p.closed = true
+	var idle []io.Closer
+	for _, slot := range []**pooledConn{&p.client, &p.admin} {
+		if pc := *slot; pc != nil && p.retireLocked(slot, pc) {
+			idle = append(idle, pc.conn)
+		}
+	}
+	p.mu.Unlock()
+	for _, c := range idle {
+		_ = c.Close()
+	}
+}
+
+// pooledClient is a lease on a pooled client: Close releases the lease.
+type pooledClient struct {
+	sarama.Client
+	release func()
+}
+
+func (c pooledClient) Close() error {
+	c.release()
+	return nil
+}
+
+// pooledAdmin is a lease on a pooled admin: Close releases the lease.
+type pooledAdmin struct {
+	ClusterAdminInterface
+	release func()
+}
+
+func (a pooledAdmin) Close() error {
+	a.release()
+	return nil
+}
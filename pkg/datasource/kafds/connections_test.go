@@ -0,0 +1,212 @@
+package kafds
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/birdayz/kaf/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeCountingAdmin records Close calls on the underlying (pooled) admin.
+type closeCountingAdmin struct {
+	*MockClusterAdmin
+	closes atomic.Int32
+}
+
+func (a *closeCountingAdmin) Close() error {
+	a.closes.Add(1)
+	return nil
+}
+
+// poolFakeClient is a sarama.Client whose health the test controls.
+type poolFakeClient struct {
+	sarama.Client
+	closed     bool
+	refreshErr error
+	closes     int
+}
+
+func (c *poolFakeClient) Closed() bool { return c.closed }
+func (c *poolFakeClient) Close() error {
+	c.closes++
+	c.closed = true
+	return nil
+}
+func (c *poolFakeClient) RefreshMetadata(...string) error { return c.refreshErr }
+
+func TestConnPool_AdminReusedAcrossCalls(t *testing.T) {
+	f := &countingFactory{admin: &MockClusterAdmin{}}
+	ds := newTestDS(f, &config.Cluster{Name: "a", Brokers: []string{"a:9092"}})
+
+	for i := 0; i < 3; i++ {
+		_, err := ds.GetTopics()
+		require.NoError(t, err)
+	}
+	_, err := ds.GetConsumerGroups()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, f.calls, "one admin bootstrap should serve every call")
+}
+
+func TestConnPool_LeaseCloseKeepsConnectionOpen(t *testing.T) {
+	admin := &closeCountingAdmin{MockClusterAdmin: &MockClusterAdmin{}}
+	ds := mockAdminDS(admin)
+
+	lease, err := ds.getClusterAdmin()
+	require.NoError(t, err)
+	require.NoError(t, lease.Close())
+
+	assert.Equal(t, int32(0), admin.closes.Load())
+}
+
+func TestConnPool_SetContextTearsDown(t *testing.T) {
+	admin := &closeCountingAdmin{MockClusterAdmin: &MockClusterAdmin{}}
+	f := &countingFactory{admin: admin}
+	ds := newTestDS(f, &config.Cluster{Name: "a", Brokers: []string{"a:9092"}})
+	ds.session.cfg.Clusters = append(ds.session.cfg.Clusters, &config.Cluster{Name: "b", Brokers: []string{"b:9092"}})
+
+	_, err := ds.GetTopics()
+	require.NoError(t, err)
+	require.NoError(t, ds.SetContext("b"))
+
+	assert.Eventually(t, func() bool { return admin.closes.Load() == 1 }, time.Second, time.Millisecond,
+		"the previous cluster's admin must be closed")
+
+	_, err = ds.GetTopics()
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.calls, "the new cluster gets its own admin")
+}
+
+func TestConnPool_FailedDialNotCached(t *testing.T) {
+	f := &countingFactory{admin: &MockClusterAdmin{}, failAdmin: true}
+	ds := newTestDS(f, &config.Cluster{Brokers: []string{"localhost:9092"}})
+
+	_, err := ds.GetTopics()
+	require.Error(t, err)
+
+	f.failAdmin = false
+	_, err = ds.GetTopics()
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.calls)
+}
+
+func TestConnPool_ClientReconnect(t *testing.T) {
+	t.Run("closed client is redialed", func(t *testing.T) {
+		p := newConnPool()
+		first := &poolFakeClient{}
+		dials := 0
+		dial := func() (sarama.Client, error) {
+			dials++
+			if dials == 1 {
+				return first, nil
+			}
+			return &poolFakeClient{}, nil
+		}
+
+		_, err := p.leaseClient(dial)
+		require.NoError(t, err)
+		_, err = p.leaseClient(dial)
+		require.NoError(t, err)
+		assert.Equal(t, 1, dials)
+
+		first.closed = true
+		_, err = p.leaseClient(dial)
+		require.NoError(t, err)
+		assert.Equal(t, 2, dials)
+	})
+
+	t.Run("idle client failing its probe is replaced", func(t *testing.T) {
+		orig := connProbeAfter
+		t.Cleanup(func() { connProbeAfter = orig })
+		connProbeAfter = 0
+
+		p := newConnPool()
+		stale := &poolFakeClient{}
+		dials := 0
+		dial := func() (sarama.Client, error) {
+			dials++
+			if dials == 1 {
+				return stale, nil
+			}
+			return &poolFakeClient{}, nil
+		}
+
+		lease, err := p.leaseClient(dial)
+		require.NoError(t, err)
+		require.NoError(t, lease.Close())
+		stale.refreshErr = sarama.ErrOutOfBrokers
+		time.Sleep(time.Millisecond)
+		_, err = p.leaseClient(dial)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, dials)
+		assert.Equal(t, 1, stale.closes)
+	})
+
+	t.Run("leases after close are one-shot", func(t *testing.T) {
+		p := newConnPool()
+		p.close()
+		c := &poolFakeClient{}
+		got, err := p.leaseClient(func() (sarama.Client, error) { return c, nil })
+		require.NoError(t, err)
+		require.NoError(t, got.Close())
+		assert.Equal(t, 1, c.closes, "the caller owns a client dialed after teardown")
+	})
+
+	t.Run("dial error surfaces", func(t *testing.T) {
+		p := newConnPool()
+		_, err := p.leaseClient(func() (sarama.Client, error) { return nil, errors.New("refused") })
+		require.Error(t, err)
+	})
+}
+
+func TestConnPool_RetiredClientClosedAfterLastRelease(t *testing.T) {
+	p := newConnPool()
+	c := &poolFakeClient{}
+	held, err := p.leaseClient(func() (sarama.Client, error) { return c, nil })
+	require.NoError(t, err)
+	other, err := p.leaseClient(func() (sarama.Client, error) { return &poolFakeClient{}, nil })
+	require.NoError(t, err)
+
+	p.close()
+	assert.Equal(t, 0, c.closes, "a leased client survives teardown")
+	require.NoError(t, held.Close())
+	require.NoError(t, held.Close(), "closing a lease twice releases once")
+	assert.Equal(t, 0, c.closes)
+	require.NoError(t, other.Close())
+	assert.Equal(t, 1, c.closes, "the last release closes the retired client")
+}
+
+func TestConnPool_SlowDialDoesNotBlockOtherLeases(t *testing.T) {
+	p := newConnPool()
+	_, err := p.leaseClient(func() (sarama.Client, error) { return &poolFakeClient{}, nil })
+	require.NoError(t, err)
+
+	dialing, unblock := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _ = p.leaseAdmin(func() (ClusterAdminInterface, error) {
+			close(dialing)
+			<-unblock
+			return &MockClusterAdmin{}, nil
+		})
+	}()
+	<-dialing
+	defer close(unblock)
+
+	leased := make(chan error, 1)
+	go func() {
+		_, err := p.leaseClient(func() (sarama.Client, error) { return &poolFakeClient{}, nil })
+		leased <- err
+	}()
+	select {
+	case err := <-leased:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("client lease blocked behind an admin dial")
+	}
+}
@@ -36,6 +36,7 @@ var (
 	keyProtoType    string
 	flagPartitions  []int32
 	limitMessagesFlag int64
+	decodeMsgPack   bool
 	reg             *proto.DescriptorRegistry
 	handler         api.MessageHandlerFunc // Global handler for backward compatibility
 )
@@ -105,10 +106,6 @@ func DefaultConsumeConfig() *ConsumeConfig {
 	}
 }
 
-func DoConsume(ctx context.Context, topic string, consumeFlags api.ConsumeFlags, handleMessage api.MessageHandlerFunc, onError func(err any)) {
-	DoConsumeWithDeps(ctx, topic, consumeFlags, handleMessage, onError, configProviderInstance, consumerInstance, messageProcessorInstance)
-}
-
 func DoConsumeWithDeps(ctx context.Context, topic string, consumeFlags api.ConsumeFlags, handleMessage api.MessageHandlerFunc, onError func(err any), configProvider ConfigProviderInterface, consumer ConsumerInterface, processor MessageProcessorInterface) {
 	config := DefaultConsumeConfig()
 	DoConsumeWithConfig(ctx, topic, consumeFlags, handleMessage, onError, configProvider, consumer, processor, config)
@@ -121,6 +118,14 @@ func DoConsumeWithConfig(ctx context.Context, topic string, consumeFlags api.Con
 		onError(err)
 		return
 	}
+	// The isolation level is part of the client config the consumer inherits,
+	// so it must be set before the client is created.
+	// ponytail: sarama drops control batches and never reports which records
+	// belong to an aborted transaction, so commit/abort markers and an
+	// "aborted" badge can't be shown; read_committed filtering is exact.
+	if consumeFlags.ReadCommitted() {
+		cfg.Consumer.IsolationLevel = sarama.ReadCommitted
+	}
 	client, err := configProvider.GetClientFromConfig(cfg)
 	if err != nil {
 		onError(err)
@@ -157,16 +162,6 @@ func DoConsumeWithConfig(ctx context.Context, topic string, consumeFlags api.Con
 		config.OutputFormat = OutputFormatRaw
 	}
 
-	// Initialize the Avro schema cache from the active cluster config so that
-	// Avro-encoded message values and keys are decoded to JSON automatically.
-	// getSchemaCache() returns nil (not an error) when no registry URL is set,
-	// in which case avroDecodeWithCache passes the raw bytes through unchanged.
-	if config.SchemaCache == nil {
-		if sc, err := getSchemaCache(); err == nil {
-			config.SchemaCache = sc
-		}
-	}
-
 	switch config.OffsetFlag {
 	case "oldest":
 		offset = sarama.OffsetOldest
@@ -339,7 +334,8 @@ func withoutConsumerGroupWithDeps(ctx context.Context, client sarama.Client, top
 			// In non-follow mode, reset this timer on every received message.
 			// If no message arrives within the window we've likely hit the end of
 			// readable messages (remaining offsets are control/transaction markers
-			// that Sarama filters out but that advance the high-water mark).
+			// that Sarama filters out but that advance the high-water mark, or,
+			// under read_committed, records beyond the last stable offset).
 			const idleTimeout = 300 * time.Millisecond
 			idleTimer := func() <-chan time.Time {
 				if config.Follow {
@@ -365,6 +365,33 @@ func TestDoConsumeWithDeps_ClientError(t *testing.T) {
 	mockConfigProvider.AssertExpectations(t)
 }
 
+func TestDoConsumeWithDeps_IsolationLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		isolation api.IsolationLevel
+		want      sarama.IsolationLevel
+	}{
+		{"default is read_uncommitted", "", sarama.ReadUncommitted},
+		{"read_uncommitted", api.IsolationReadUncommitted, sarama.ReadUncommitted},
+		{"read_committed", api.IsolationReadCommitted, sarama.ReadCommitted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfigProvider := &MockConfigProvider{}
+			config := sarama.NewConfig()
+			mockConfigProvider.On("GetConsumerConfig").Return(config, nil)
+			mockConfigProvider.On("GetClientFromConfig", config).Return(nil, errors.New("client error"))
+
+			flags := api.ConsumeFlags{Isolation: tt.isolation}
+			DoConsumeWithDeps(context.Background(), "test-topic", flags, func(api.Message) {}, func(any) {},
+				mockConfigProvider, &MockConsumer{}, &MockMessageProcessor{})
+
+			// The client the consumer is built from carries the isolation level.
+			assert.Equal(t, tt.want, config.Consumer.IsolationLevel)
+		})
+	}
+}
+
 func TestDoConsumeWithDeps_OffsetParsing(t *testing.T) {
 	mockConfigProvider := &MockConfigProvider{}
 	mockConsumer := &MockConsumer{}
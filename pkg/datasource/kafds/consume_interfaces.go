@@ -80,15 +80,18 @@ func (p *DefaultMessageProcessor) DecodeProto(data []byte, protoType string) ([]
 	return data, nil
 }
 
-// DefaultConfigProvider implements ConfigProviderInterface
-type DefaultConfigProvider struct{}
+// DefaultConfigProvider implements ConfigProviderInterface against the
+// datasource's active cluster.
+type DefaultConfigProvider struct {
+	ds KafkaDataSourceKaf
+}
 
 func (cp *DefaultConfigProvider) GetConsumerConfig() (*sarama.Config, error) {
-	return getConfig()
+	return cp.ds.getConfig()
 }
 
 func (cp *DefaultConfigProvider) GetClientFromConfig(config *sarama.Config) (sarama.Client, error) {
-	return getClientFromConfig(config)
+	return cp.ds.getClientFromConfig(config)
 }
 
 // Helper function to convert Sarama headers to API headers
@@ -107,5 +110,4 @@ func convertHeaders(saramaHeaders []*sarama.RecordHeader) []api.MessageHeader {
 var (
 	consumerInstance       ConsumerInterface       = &DefaultConsumer{}
 	messageProcessorInstance MessageProcessorInterface = &DefaultMessageProcessor{}
-	configProviderInstance ConfigProviderInterface   = &DefaultConfigProvider{}
 )
\ No newline at end of file
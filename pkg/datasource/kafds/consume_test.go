@@ -1066,20 +1066,12 @@ func TestOutputFormat(t *testing.T) {
 	})
 }
 
-// TestDoConsume tests the main DoConsume function
-func TestDoConsume(t *testing.T) {
-	// Save original global variables
-	originalCurrentCluster := currentCluster
-	
-	defer func() {
-		// Restore original values
-		currentCluster = originalCurrentCluster
-	}()
-	
+// TestConsumeTopic tests the datasource's ConsumeTopic entry point
+func TestConsumeTopic(t *testing.T) {
 	// Set up a minimal test cluster configuration to avoid nil pointer panics
-	currentCluster = &config.Cluster{
+	ds := newTestDS(&DefaultKafkaClientFactory{}, &config.Cluster{
 		Brokers: []string{"localhost:9092"},
-	}
+	})
 	
 	t.Run("consume_with_oldest_offset", func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
@@ -1106,7 +1098,7 @@ func TestDoConsume(t *testing.T) {
 		}
 		
 		// This will fail due to missing Kafka broker, but we can test the setup
-		DoConsume(ctx, topic, flags, handleMessage, onError)
+		ds.ConsumeTopic(ctx, topic, flags, handleMessage, onError)
 		
 		// Verify that error handling was called (expected due to missing broker)
 		assert.True(t, len(capturedErrors) > 0, "Expected error due to missing broker connection")
@@ -1135,7 +1127,7 @@ func TestDoConsume(t *testing.T) {
 			cancel()
 		}
 		
-		DoConsume(ctx, topic, flags, handleMessage, onError)
+		ds.ConsumeTopic(ctx, topic, flags, handleMessage, onError)
 		
 		// Verify that error handling was called (expected due to missing broker)
 		assert.True(t, len(capturedErrors) > 0, "Expected error due to missing broker connection")
@@ -1164,7 +1156,7 @@ func TestDoConsume(t *testing.T) {
 			cancel()
 		}
 		
-		DoConsume(ctx, topic, flags, handleMessage, onError)
+		ds.ConsumeTopic(ctx, topic, flags, handleMessage, onError)
 		
 		// Verify that error handling was called (expected due to missing broker)
 		assert.True(t, len(capturedErrors) > 0, "Expected error due to missing broker connection")
@@ -1193,7 +1185,7 @@ func TestDoConsume(t *testing.T) {
 			cancel()
 		}
 		
-		DoConsume(ctx, topic, flags, handleMessage, onError)
+		ds.ConsumeTopic(ctx, topic, flags, handleMessage, onError)
 		
 		// Should capture error due to invalid offset parsing
 		assert.True(t, len(capturedErrors) > 0, "Expected error due to invalid offset or missing broker")
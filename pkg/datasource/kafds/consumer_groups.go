@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"sort"
-	"sync"
 	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
@@ -23,8 +22,8 @@ type groupOffsetReader interface {
 }
 
 // newGroupOffsetReader creates a client-side reader. Replaceable in tests.
-var newGroupOffsetReader = func() (groupOffsetReader, error) {
-	return getClient()
+var newGroupOffsetReader = func(kp KafkaDataSourceKaf) (groupOffsetReader, error) {
+	return kp.getClient()
 }
 
 // normalizeGroupState maps Sarama's backend state strings to the canonical
@@ -143,7 +142,7 @@ func distinctTopics(committed map[string]map[int32]int64, members []api.GroupMem
 
 // GetConsumerGroupDetail implements api.KafkaDataSource (CG-3).
 func (kp KafkaDataSourceKaf) GetConsumerGroupDetail(groupID string) (api.ConsumerGroupDetail, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return api.ConsumerGroupDetail{}, err
 	}
@@ -171,7 +170,7 @@ func (kp KafkaDataSourceKaf) GetConsumerGroupDetail(groupID string) (api.Consume
 		return api.ConsumerGroupDetail{}, fmt.Errorf("listing offsets for group %q: %w", groupID, err)
 	}
 
-	reader, err := newGroupOffsetReader()
+	reader, err := newGroupOffsetReader(kp)
 	if err != nil {
 		return api.ConsumerGroupDetail{}, err
 	}
@@ -263,11 +262,8 @@ type groupDetailCacheEntry struct {
 	at    time.Time
 }
 
-var (
-	groupDetailCache   = map[string]groupDetailCacheEntry{}
-	groupDetailCacheMu sync.Mutex
-	groupDetailTTL     = 30 * time.Second
-)
+// groupDetailTTL is how long an enriched row is served from the session.
+var groupDetailTTL = 30 * time.Second
 
 // GetConsumerGroupDetails implements api.KafkaDataSource (CG-4).
 func (kp KafkaDataSourceKaf) GetConsumerGroupDetails(groupIDs []string) ([]api.ConsumerGroup, error) {
@@ -278,19 +274,17 @@ func (kp KafkaDataSourceKaf) GetConsumerGroupDetails(groupIDs []string) ([]api.C
 	// Resolve cache hits first; collect misses to describe.
 	cached := map[string]api.ConsumerGroup{}
 	var misses []string
-	groupDetailCacheMu.Lock()
-	now := time.Now()
+	sess := kp.sess()
 	for _, id := range groupIDs {
-		if e, ok := groupDetailCache[id]; ok && now.Sub(e.at) < groupDetailTTL {
-			cached[id] = e.group
+		if g, ok := sess.cachedGroupDetail(id); ok {
+			cached[id] = g
 		} else {
 			misses = append(misses, id)
 		}
 	}
-	groupDetailCacheMu.Unlock()
 
 	if len(misses) > 0 {
-		admin, err := getClusterAdmin()
+		admin, err := kp.getClusterAdmin()
 		if err != nil {
 			return nil, err
 		}
@@ -301,19 +295,17 @@ func (kp KafkaDataSourceKaf) GetConsumerGroupDetails(groupIDs []string) ([]api.C
 			return nil, fmt.Errorf("describing consumer groups: %w", err)
 		}
 
-		reader, err := newGroupOffsetReader()
+		reader, err := newGroupOffsetReader(kp)
 		if err != nil {
 			return nil, err
 		}
 		defer reader.Close()
 
-		groupDetailCacheMu.Lock()
 		for _, id := range misses {
 			row := enrichGroup(id, findGroupDesc(descs, id), admin, reader)
 			cached[id] = row
-			groupDetailCache[id] = groupDetailCacheEntry{group: row, at: time.Now()}
+			sess.setGroupDetail(id, row)
 		}
-		groupDetailCacheMu.Unlock()
 	}
 
 	out := make([]api.ConsumerGroup, 0, len(groupIDs))
@@ -354,7 +346,7 @@ func enrichGroup(name string, desc *sarama.GroupDescription, admin ClusterAdminI
 
 // GetConsumerGroupsForTopic implements api.KafkaDataSource (CG-5).
 func (kp KafkaDataSourceKaf) GetConsumerGroupsForTopic(topic string) ([]api.ConsumerGroup, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
@@ -370,7 +362,7 @@ func (kp KafkaDataSourceKaf) GetConsumerGroupsForTopic(topic string) ([]api.Cons
 	}
 	sort.Strings(allNames)
 
-	reader, err := newGroupOffsetReader()
+	reader, err := newGroupOffsetReader(kp)
 	if err != nil {
 		return nil, err
 	}
@@ -463,13 +455,13 @@ func mapGroupError(groupID string, err error) error {
 
 // DeleteConsumerGroup implements api.KafkaDataSource (CG-6).
 func (kp KafkaDataSourceKaf) DeleteConsumerGroup(groupID string) error {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
 	defer admin.Close()
 
-	invalidateGroupCache(groupID)
+	kp.sess().dropGroupDetail(groupID)
 	if err := admin.DeleteConsumerGroup(groupID); err != nil {
 		return mapGroupError(groupID, err)
 	}
@@ -479,7 +471,7 @@ func (kp KafkaDataSourceKaf) DeleteConsumerGroup(groupID string) error {
 // DeleteConsumerGroupOffsets implements api.KafkaDataSource (CG-6). It deletes
 // only the named topic's committed offsets, leaving other topics intact.
 func (kp KafkaDataSourceKaf) DeleteConsumerGroupOffsets(groupID string, topic string) error {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
@@ -490,7 +482,7 @@ func (kp KafkaDataSourceKaf) DeleteConsumerGroupOffsets(groupID string, topic st
 		return fmt.Errorf("listing offsets for group %q: %w", groupID, err)
 	}
 	committed := committedOffsets(offsetsResp)
-	invalidateGroupCache(groupID)
+	kp.sess().dropGroupDetail(groupID)
 
 	partitions := committed[topic]
 	// Delete deterministically for stable test behaviour.
@@ -506,10 +498,3 @@ func (kp KafkaDataSourceKaf) DeleteConsumerGroupOffsets(groupID string, topic st
 	}
 	return nil
 }
-
-// invalidateGroupCache drops any cached enrichment for a group after a mutation.
-func invalidateGroupCache(groupID string) {
-	groupDetailCacheMu.Lock()
-	delete(groupDetailCache, groupID)
-	groupDetailCacheMu.Unlock()
-}
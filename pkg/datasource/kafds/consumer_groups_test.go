@@ -54,7 +54,7 @@ func (f *fakeGroupReader) Close() error { f.closed = true; return nil }
 
 func installFakeReader(r groupOffsetReader) func() {
 	orig := newGroupOffsetReader
-	newGroupOffsetReader = func() (groupOffsetReader, error) { return r, nil }
+	newGroupOffsetReader = func(KafkaDataSourceKaf) (groupOffsetReader, error) { return r, nil }
 	return func() { newGroupOffsetReader = orig }
 }
 
@@ -100,12 +100,6 @@ func offsetsResp(committed map[string]map[int32]int64) *sarama.OffsetFetchRespon
 	return resp
 }
 
-func clearGroupCache() {
-	groupDetailCacheMu.Lock()
-	groupDetailCache = map[string]groupDetailCacheEntry{}
-	groupDetailCacheMu.Unlock()
-}
-
 // --- CG-3: GetConsumerGroupDetail ---
 
 func TestGetConsumerGroupDetail(t *testing.T) {
@@ -122,15 +116,14 @@ func TestGetConsumerGroupDetail(t *testing.T) {
 		// committed on p0 only; p1 assigned but uncommitted.
 		MockGroupOffsets: offsetsResp(map[string]map[int32]int64{"t1": {0: 100}}),
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreReader := installFakeReader(&fakeGroupReader{
 		newest: map[string]map[int32]int64{"t1": {0: 150, 1: 500}},
 		coord:  7,
 	})
 	defer restoreReader()
 
-	detail, err := brokerDS().GetConsumerGroupDetail("g1")
+	detail, err := ds.GetConsumerGroupDetail("g1")
 	assert.NoError(t, err)
 	assert.Equal(t, api.GroupStateStable, detail.State)
 	assert.Equal(t, "range", detail.PartitionAssignor)
@@ -158,12 +151,11 @@ func TestGetConsumerGroupDetail(t *testing.T) {
 
 func TestGetConsumerGroupDetail_NotFound(t *testing.T) {
 	admin := &MockClusterAdmin{MockConsumerGroups: map[string]string{"other": "consumer"}}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreReader := installFakeReader(&fakeGroupReader{})
 	defer restoreReader()
 
-	_, err := brokerDS().GetConsumerGroupDetail("missing")
+	_, err := ds.GetConsumerGroupDetail("missing")
 	var nf api.GroupNotFoundError
 	assert.True(t, errors.As(err, &nf))
 	assert.Equal(t, "missing", nf.GroupID)
@@ -175,14 +167,13 @@ func TestGetConsumerGroupDetail_EndOffsetUnavailable(t *testing.T) {
 		MockGroupDescriptions: []*sarama.GroupDescription{{GroupId: "g1", State: "Empty", ProtocolType: "consumer"}},
 		MockGroupOffsets:      offsetsResp(map[string]map[int32]int64{"t1": {0: 100}}),
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreReader := installFakeReader(&fakeGroupReader{
 		errAt: map[string]map[int32]bool{"t1": {0: true}},
 	})
 	defer restoreReader()
 
-	detail, err := brokerDS().GetConsumerGroupDetail("g1")
+	detail, err := ds.GetConsumerGroupDetail("g1")
 	assert.NoError(t, err)
 	assert.Len(t, detail.TopicOffsets, 1)
 	// committed present but end unavailable -> lag defined as 0.
@@ -193,7 +184,6 @@ func TestGetConsumerGroupDetail_EndOffsetUnavailable(t *testing.T) {
 // --- CG-4: GetConsumerGroupDetails (batch) ---
 
 func TestGetConsumerGroupDetails_BatchBestEffort(t *testing.T) {
-	clearGroupCache()
 	memberG1 := memberWithAssignment(t, "m1", "c1", "h1", map[string][]int32{"t1": {0}})
 	admin := &MockClusterAdmin{
 		MockGroupDescriptions: []*sarama.GroupDescription{
@@ -203,15 +193,14 @@ func TestGetConsumerGroupDetails_BatchBestEffort(t *testing.T) {
 		},
 		MockGroupOffsets: offsetsResp(map[string]map[int32]int64{"t1": {0: 10}}),
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreReader := installFakeReader(&fakeGroupReader{
 		newest: map[string]map[int32]int64{"t1": {0: 60}},
 		coord:  1,
 	})
 	defer restoreReader()
 
-	rows, err := brokerDS().GetConsumerGroupDetails([]string{"g1", "g2", "g3"})
+	rows, err := ds.GetConsumerGroupDetails([]string{"g1", "g2", "g3"})
 	assert.NoError(t, err)
 	assert.Len(t, rows, 3)
 	byName := map[string]api.ConsumerGroup{}
@@ -229,7 +218,6 @@ func TestGetConsumerGroupDetails_BatchBestEffort(t *testing.T) {
 }
 
 func TestGetConsumerGroupDetails_TopicCountDistinct(t *testing.T) {
-	clearGroupCache()
 	// committed on t1; assigned to t1 and t2 -> distinct topics = 2.
 	member := memberWithAssignment(t, "m1", "c1", "h1", map[string][]int32{"t1": {0}, "t2": {0}})
 	admin := &MockClusterAdmin{
@@ -238,44 +226,59 @@ func TestGetConsumerGroupDetails_TopicCountDistinct(t *testing.T) {
 		},
 		MockGroupOffsets: offsetsResp(map[string]map[int32]int64{"t1": {0: 5}}),
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreReader := installFakeReader(&fakeGroupReader{newest: map[string]map[int32]int64{"t1": {0: 5}}})
 	defer restoreReader()
 
-	rows, err := brokerDS().GetConsumerGroupDetails([]string{"g1"})
+	rows, err := ds.GetConsumerGroupDetails([]string{"g1"})
 	assert.NoError(t, err)
 	assert.Equal(t, 2, rows[0].TopicCount)
 }
 
 func TestGetConsumerGroupDetails_CacheHitAvoidsDescribe(t *testing.T) {
-	clearGroupCache()
 	admin := &MockClusterAdmin{
 		MockGroupDescriptions: []*sarama.GroupDescription{{GroupId: "g1", State: "Stable", ProtocolType: "consumer"}},
 		MockGroupOffsets:      offsetsResp(nil),
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreReader := installFakeReader(&fakeGroupReader{})
 	defer restoreReader()
 
-	_, err := brokerDS().GetConsumerGroupDetails([]string{"g1"})
+	_, err := ds.GetConsumerGroupDetails([]string{"g1"})
+	assert.NoError(t, err)
+
+	// Second call: make describe fail. The cache hit must avoid touching it.
+	admin.ShouldFailDescribeGroups = true
+	rows, err := ds.GetConsumerGroupDetails([]string{"g1"})
 	assert.NoError(t, err)
+	assert.Equal(t, api.GroupStateStable, rows[0].State)
+}
 
-	// Second call: swap in an admin that would fail describe. Cache hit must
-	// avoid touching it.
-	failAdmin := &MockClusterAdmin{ShouldFailDescribeGroups: true}
-	restore2 := installMockAdmin(failAdmin)
-	defer restore2()
-	rows, err := brokerDS().GetConsumerGroupDetails([]string{"g1"})
+func TestGetConsumerGroupDetails_CachePerDatasource(t *testing.T) {
+	restoreReader := installFakeReader(&fakeGroupReader{})
+	defer restoreReader()
+	dsFor := func(state string) KafkaDataSourceKaf {
+		return mockAdminDS(&MockClusterAdmin{
+			MockGroupDescriptions: []*sarama.GroupDescription{{GroupId: "g1", State: state, ProtocolType: "consumer"}},
+			MockGroupOffsets:      offsetsResp(nil),
+		})
+	}
+	stable, empty := dsFor("Stable"), dsFor("Empty")
+
+	rows, err := stable.GetConsumerGroupDetails([]string{"g1"})
 	assert.NoError(t, err)
 	assert.Equal(t, api.GroupStateStable, rows[0].State)
+	rows, err = empty.GetConsumerGroupDetails([]string{"g1"})
+	assert.NoError(t, err)
+	assert.Equal(t, api.GroupStateEmpty, rows[0].State, "served another datasource's cached row")
+
+	stable.sess().invalidate()
+	assert.Empty(t, stable.sess().groupDetails, "a context switch drops the cache")
 }
 
 // --- CG-5: GetConsumerGroupsForTopic ---
 
 func TestGetConsumerGroupsForTopic(t *testing.T) {
-	clearGroupCache()
 	// g1 related by assignment only (no committed for t1).
 	g1Member := memberWithAssignment(t, "m1", "c1", "h1", map[string][]int32{"t1": {0}})
 	// g2 unrelated (works on t9).
@@ -288,12 +291,11 @@ func TestGetConsumerGroupsForTopic(t *testing.T) {
 		},
 		MockGroupOffsets: offsetsResp(nil), // no committed offsets for either
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreReader := installFakeReader(&fakeGroupReader{newest: map[string]map[int32]int64{"t1": {0: 100}}})
 	defer restoreReader()
 
-	groups, err := brokerDS().GetConsumerGroupsForTopic("t1")
+	groups, err := ds.GetConsumerGroupsForTopic("t1")
 	assert.NoError(t, err)
 	assert.Len(t, groups, 1)
 	assert.Equal(t, "g1", groups[0].Name)
@@ -307,27 +309,24 @@ func TestGetConsumerGroupsForTopic(t *testing.T) {
 func TestDeleteConsumerGroup(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		admin := &MockClusterAdmin{}
-		restore := installMockAdmin(admin)
-		defer restore()
-		err := brokerDS().DeleteConsumerGroup("g1")
+		ds := mockAdminDS(admin)
+		err := ds.DeleteConsumerGroup("g1")
 		assert.NoError(t, err)
 		assert.Equal(t, []string{"g1"}, admin.DeleteConsumerGroupCalls)
 	})
 
 	t.Run("not found mapped", func(t *testing.T) {
 		admin := &MockClusterAdmin{DeleteConsumerGroupErr: sarama.ErrGroupIDNotFound}
-		restore := installMockAdmin(admin)
-		defer restore()
-		err := brokerDS().DeleteConsumerGroup("g1")
+		ds := mockAdminDS(admin)
+		err := ds.DeleteConsumerGroup("g1")
 		var nf api.GroupNotFoundError
 		assert.True(t, errors.As(err, &nf))
 	})
 
 	t.Run("not empty mapped", func(t *testing.T) {
 		admin := &MockClusterAdmin{DeleteConsumerGroupErr: sarama.ErrNonEmptyGroup}
-		restore := installMockAdmin(admin)
-		defer restore()
-		err := brokerDS().DeleteConsumerGroup("g1")
+		ds := mockAdminDS(admin)
+		err := ds.DeleteConsumerGroup("g1")
 		var ne api.GroupNotEmptyError
 		assert.True(t, errors.As(err, &ne))
 	})
@@ -340,10 +339,9 @@ func TestDeleteConsumerGroupOffsets_OnlyNamedTopic(t *testing.T) {
 			"t2": {0: 9},
 		}),
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	err := brokerDS().DeleteConsumerGroupOffsets("g1", "t1")
+	err := ds.DeleteConsumerGroupOffsets("g1", "t1")
 	assert.NoError(t, err)
 	// Only t1's partitions deleted (0 and 1), never t2.
 	assert.Equal(t, []DeleteOffsetCall{
@@ -18,23 +18,24 @@ import (
 	"github.com/Benny93/kafui/pkg/ui/shared"
 	"github.com/IBM/sarama"
 	"github.com/birdayz/kaf/pkg/avro"
-	"github.com/birdayz/kaf/pkg/config"
 	"github.com/mattn/go-colorable"
-	"github.com/spf13/cobra"
 	//"github.com/birdayz/kaf/pkg/proto"
 )
 
+// KafkaDataSourceKaf is the sarama-backed datasource. Each instance owns its
+// kaf config, active cluster and derived caches (see session), so several
+// instances can talk to different clusters side by side. The zero value has no
+// session of its own and behaves like an uninitialised datasource; use
+// NewKafkaDataSourceKaf.
 type KafkaDataSourceKaf struct {
 	clientFactory KafkaClientFactory
 	configManager ConfigManager
+	session       *session
 }
 
 // NewKafkaDataSourceKaf creates a new instance with default dependencies
 func NewKafkaDataSourceKaf() *KafkaDataSourceKaf {
-	return &KafkaDataSourceKaf{
-		clientFactory: kafkaClientFactory,
-		configManager: configManager,
-	}
+	return NewKafkaDataSourceKafWithDeps(&DefaultKafkaClientFactory{}, &DefaultConfigManager{})
 }
 
 // NewKafkaDataSourceKafWithDeps creates a new instance with custom dependencies for testing
@@ -42,23 +43,72 @@ func NewKafkaDataSourceKafWithDeps(clientFactory KafkaClientFactory, configManag
 	return &KafkaDataSourceKaf{
 		clientFactory: clientFactory,
 		configManager: configManager,
+		session:       newSession(),
+	}
+}
+
+// sess returns the datasource's session. A zero-value datasource gets a fresh,
+// uninitialised session on every call, so nothing leaks between instances.
+func (kp KafkaDataSourceKaf) sess() *session {
+	if kp.session != nil {
+		return kp.session
+	}
+	return newSession()
+}
+
+func (kp KafkaDataSourceKaf) factory() KafkaClientFactory {
+	if kp.clientFactory != nil {
+		return kp.clientFactory
+	}
+	return &DefaultKafkaClientFactory{}
+}
+
+func (kp KafkaDataSourceKaf) configs() ConfigManager {
+	if kp.configManager != nil {
+		return kp.configManager
 	}
+	return &DefaultConfigManager{}
 }
 
-var cfgFile string
+// SetOverrides applies CLI overrides before Init runs. Empty/nil values leave
+// the corresponding config value untouched.
+func (kp *KafkaDataSourceKaf) SetOverrides(o Overrides) {
+	if kp.session == nil {
+		kp.session = newSession()
+	}
+	kp.session.overrides = o
+}
 
 func (kp *KafkaDataSourceKaf) Init(cfgOption string) {
+	if kp.session == nil {
+		kp.session = newSession()
+	}
 	if cfgOption != "" {
-		cfgFile = cfgOption
+		kp.session.cfgFile = cfgOption
 	}
-	onInit()
+	_ = kp.session.load(kp.configs(), true)
+}
+
+// InitFromConfig reads the kaf config file at cfgPath (pass "" to use the
+// default ~/.kaf/config) and sets the active cluster. Unlike Init it reports a
+// missing or unreadable config file; use it in examples and standalone
+// programs that want to fail fast.
+func (kp *KafkaDataSourceKaf) InitFromConfig(cfgPath string) error {
+	if kp.session == nil {
+		kp.session = newSession()
+	}
+	kp.session.cfgFile = cfgPath
+	if err := kp.session.load(kp.configs(), false); err != nil {
+		return fmt.Errorf("reading kaf config: %w", err)
+	}
+	return nil
 }
 
 // GetTopicNames returns only the topic names using a lightweight Sarama client
 // metadata request. This is faster than GetTopics() because it skips the full
 // per-partition replica assignment that ListTopics() returns.
 func (kp KafkaDataSourceKaf) GetTopicNames() ([]string, error) {
-	client, err := getClient()
+	client, err := kp.getClient()
 	if err != nil {
 		return nil, err
 	}
@@ -72,10 +122,11 @@ func (kp KafkaDataSourceKaf) GetTopicNames() ([]string, error) {
 // GetTopics retrieves a list of Kafka topics
 func (kp KafkaDataSourceKaf) GetTopics() (map[string]api.Topic, error) {
 
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 	topicDetails, err := admin.ListTopics()
 	if err != nil {
 		return nil, err
@@ -114,7 +165,7 @@ func (kp KafkaDataSourceKaf) GetTopics() (map[string]api.Topic, error) {
 // for all topics to avoid repeated connection overhead. Topics that fail individually are
 // skipped so a partial result is always returned.
 func (kp KafkaDataSourceKaf) GetTopicMessageCounts(topics map[string]int32) (map[string]int64, error) {
-	client, err := getClient()
+	client, err := kp.getClient()
 	if err != nil {
 		return nil, err
 	}
@@ -136,12 +187,17 @@ func (kp KafkaDataSourceKaf) GetTopicMessageCounts(topics map[string]int32) (map
 }
 
 func (kp KafkaDataSourceKaf) GetContext() string {
+	s := kp.sess()
+	s.mu.Lock()
+	cfg := s.cfg
+	s.mu.Unlock()
+
 	// Check if cfg is properly initialized
 	if cfg.Clusters == nil {
 		return "default localhost:9092 (config not loaded)"
 	}
 
-	activeCluster := kp.configManager.GetActiveCluster(cfg)
+	activeCluster := kp.configs().GetActiveCluster(cfg)
 	if activeCluster == nil {
 		return "default localhost:9092"
 	}
@@ -152,7 +208,7 @@ func (kp KafkaDataSourceKaf) GetContext() string {
 func (kp KafkaDataSourceKaf) GetContexts() ([]string, error) {
 	// Logic to fetch the list of contexts from Kafka
 	var contexts []string
-	for _, cluster := range cfg.Clusters {
+	for _, cluster := range kp.sess().clusters() {
 
 		contexts = append(contexts, cluster.Name)
 	}
@@ -162,7 +218,7 @@ func (kp KafkaDataSourceKaf) GetContexts() ([]string, error) {
 // GetClusterDetails returns configuration details for the named cluster.
 func (kp KafkaDataSourceKaf) GetClusterDetails(clusterName string) (api.ClusterInfo, error) {
 	currentCtx := kp.GetContext()
-	for _, cluster := range cfg.Clusters {
+	for _, cluster := range kp.sess().clusters() {
 		if cluster.Name == clusterName {
 			return api.ClusterInfo{
 				Name:              cluster.Name,
@@ -182,6 +238,14 @@ func (kp KafkaDataSourceKaf) GetClusterDetails(clusterName string) (api.ClusterI
 // entirely in memory. Called after an in-UI config apply to take effect without
 // restarting the process.
 func (kp *KafkaDataSourceKaf) Reload(effective appconfig.Config) error {
+	if kp.session == nil {
+		kp.session = newSession()
+	}
+	s := kp.session
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg := &s.cfg
+
 	for name, ext := range effective.Clusters {
 		if !ext.IsFullyDefined() {
 			continue // overlay-only entry; it decorates an existing kaf cluster
@@ -203,14 +267,14 @@ func (kp *KafkaDataSourceKaf) Reload(effective appconfig.Config) error {
 	// Re-resolve the active cluster: keep the current one if it still exists,
 	// otherwise fall back to the first configured cluster.
 	target := cfg.CurrentCluster
-	if currentCluster != nil && currentCluster.Name != "" {
-		target = currentCluster.Name
+	if s.current != nil && s.current.Name != "" {
+		target = s.current.Name
 	}
 	found := false
 	for _, c := range cfg.Clusters {
 		if c.Name == target {
 			cc := *c
-			currentCluster = &cc
+			s.current = &cc
 			cfg.CurrentCluster = cc.Name
 			found = true
 			break
@@ -218,26 +282,27 @@ func (kp *KafkaDataSourceKaf) Reload(effective appconfig.Config) error {
 	}
 	if !found && len(cfg.Clusters) > 0 {
 		cc := *cfg.Clusters[0]
-		currentCluster = &cc
+		s.current = &cc
 		cfg.CurrentCluster = cc.Name
 	}
 
 	// Invalidate caches (mirror SetContext).
-	cachedSchemaCache = nil
-	invalidateSerdeRegistry()
+	s.invalidateLocked()
 	return nil
 }
 
 func (kp KafkaDataSourceKaf) SetContext(contextName string) error {
-	// Only update the in-memory currentCluster pointer — never write to disk.
+	s := kp.sess()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Only update the in-memory active cluster pointer — never write to disk.
 	// Calling cfg.SetCurrentCluster() would truncate ~/.kaf/config and re-serialize
 	// it, which strips TLS cert paths due to missing YAML tags in the kaf library.
-	for _, cluster := range cfg.Clusters {
+	for _, cluster := range s.cfg.Clusters {
 		if cluster.Name == contextName {
-			currentCluster = cluster
-			cfg.CurrentCluster = contextName
-			cachedSchemaCache = nil // invalidate schema cache on cluster switch
-			invalidateSerdeRegistry()
+			s.current = cluster
+			s.cfg.CurrentCluster = contextName
+			s.invalidateLocked() // schema cache, serdes and tokens are per cluster
 			return nil
 		}
 	}
@@ -245,10 +310,11 @@ func (kp KafkaDataSourceKaf) SetContext(contextName string) error {
 }
 
 func (kp KafkaDataSourceKaf) GetConsumerGroups() ([]api.ConsumerGroup, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 
 	// ListConsumerGroups is a single fast broker round-trip that returns every
 	// group name and its protocol type.  DescribeConsumerGroups is intentionally
@@ -282,7 +348,15 @@ func (kp KafkaDataSourceKaf) GetConsumerGroups() ([]api.ConsumerGroup, error) {
 }
 
 func (kp KafkaDataSourceKaf) ConsumeTopic(ctx context.Context, topicName string, flags api.ConsumeFlags, handleMessage api.MessageHandlerFunc, onError func(err any)) error {
-	DoConsume(ctx, topicName, flags, handleMessage, onError)
+	config := DefaultConsumeConfig()
+	// Initialize the Avro schema cache from the active cluster config so that
+	// Avro-encoded message values and keys are decoded to JSON automatically.
+	// getSchemaCache() returns nil (not an error) when no registry URL is set,
+	// in which case avroDecodeWithCache passes the raw bytes through unchanged.
+	if sc, err := kp.getSchemaCache(); err == nil {
+		config.SchemaCache = sc
+	}
+	DoConsumeWithConfig(ctx, topicName, flags, handleMessage, onError, &DefaultConfigProvider{ds: kp}, consumerInstance, messageProcessorInstance, config)
 	return nil
 }
 
@@ -315,12 +389,12 @@ func (kp KafkaDataSourceKaf) GetMessageSchemaInfo(keySchemaID, valueSchemaID str
 
 // DecodeMessage decodes Avro-encoded raw bytes stored in msg.RawKey / msg.RawValue
 // into human-readable strings. Messages without raw bytes are returned unchanged.
-// The schema registry client is shared across calls (see cachedSchemaCache).
+// The schema registry client is shared across calls (see getOrInitSchemaCache).
 func (kp KafkaDataSourceKaf) DecodeMessage(_ context.Context, msg api.Message) (api.Message, error) {
 	if len(msg.RawKey) == 0 && len(msg.RawValue) == 0 {
 		return msg, nil
 	}
-	reg := getSerdeRegistry()
+	reg := kp.getSerdeRegistry()
 	if len(msg.RawKey) > 0 {
 		text, name, _ := serde.Decode(reg, "", msg.RawKey)
 		msg.Key, msg.KeySerde = text, name
@@ -335,15 +409,20 @@ func (kp KafkaDataSourceKaf) DecodeMessage(_ context.Context, msg api.Message) (
 // ListSerdes returns the names of serdes available for decoding, driven by the
 // active cluster's registry (built-ins + configured). (MSG-18)
 func (kp KafkaDataSourceKaf) ListSerdes() []string {
-	return getSerdeRegistry().Names()
+	return kp.getSerdeRegistry().Names()
 }
 
-func getConfig() (saramaConfig *sarama.Config, e error) {
+func (kp KafkaDataSourceKaf) getConfig() (saramaConfig *sarama.Config, e error) {
 	saramaConfig = sarama.NewConfig()
 	saramaConfig.Version = sarama.V1_1_0_0
 	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Partitioner = newRecordPartitioner
 
-	cluster := currentCluster
+	s := kp.sess()
+	cluster := s.activeCluster()
+	if cluster == nil {
+		return nil, fmt.Errorf("no Kafka cluster configured. Please check your configuration or ensure Kafka is running")
+	}
 	if cluster.Version != "" {
 		parsedVersion, err := sarama.ParseKafkaVersion(cluster.Version)
 		if err != nil {
@@ -420,15 +499,15 @@ func getConfig() (saramaConfig *sarama.Config, e error) {
 	}
 	if cluster.SecurityProtocol == "SASL_SSL" || cluster.SecurityProtocol == "SASL_PLAINTEXT" {
 		if cluster.SASL.Mechanism == "SCRAM-SHA-512" {
-			saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &XDGSCRAMClient{HashGeneratorFcn: SHA512} }
+			saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = scramClientGenerator(s.delegationTokenAuth(cluster.Name), SHA512)
 			saramaConfig.Net.SASL.Mechanism = sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512)
 		} else if cluster.SASL.Mechanism == "SCRAM-SHA-256" {
-			saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &XDGSCRAMClient{HashGeneratorFcn: SHA256} }
+			saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = scramClientGenerator(s.delegationTokenAuth(cluster.Name), SHA256)
 			saramaConfig.Net.SASL.Mechanism = sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA256)
 		} else if cluster.SASL.Mechanism == "OAUTHBEARER" {
 			//Here setup get token function
 			saramaConfig.Net.SASL.Mechanism = sarama.SASLMechanism(sarama.SASLTypeOAuth)
-			saramaConfig.Net.SASL.TokenProvider = newTokenProvider()
+			saramaConfig.Net.SASL.TokenProvider = s.oauthTokenProvider()
 
 		}
 	}
@@ -457,224 +536,89 @@ func InitTUIWriters() {
 	sarama.Logger = log.New(w, "[sarama] ", 0)
 }
 
-// Will be replaced by GitHub action and by goreleaser
-// see https://goreleaser.com/customization/build/
-var commit string = "HEAD"
-var version string = "latest"
-
-var rootCmd = &cobra.Command{
-	Use:     "kaf",
-	Short:   "Kafka Command Line utility for cluster management",
-	Version: fmt.Sprintf("%s (%s)", version, commit),
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		outWriter = cmd.OutOrStdout()
-		errWriter = cmd.ErrOrStderr()
-		inReader = cmd.InOrStdin()
-
-		if outWriter != os.Stdout {
-			colorableOut = outWriter
-		}
-	},
-}
-
-func main() {
-	if err := rootCmd.Execute(); err != nil {
-		shared.Log.Error("root command failed", "err", err)
-		os.Exit(1)
-	}
-}
-
-var cfg config.Config
-var currentCluster *config.Cluster
-
-var (
-	brokersFlag       []string
-	schemaRegistryURL string
-	protoFiles        []string
-	protoExclude      []string
-	decodeMsgPack     bool
-	verbose           bool
-	clusterOverride   string
-)
-
-// SetOverrides applies CLI overrides before Init/onInit runs. Empty/nil values
-// leave the corresponding config value untouched. Kafui's own CLI calls this;
-// the embedded rootCmd below is never executed.
-func SetOverrides(brokers []string, schemaRegistry, cluster string, verboseLogging bool) {
-	if len(brokers) > 0 {
-		brokersFlag = brokers
-	}
-	if schemaRegistry != "" {
-		schemaRegistryURL = schemaRegistry
-	}
-	if cluster != "" {
-		clusterOverride = cluster
-	}
-	verbose = verboseLogging
-}
-
-func init() {
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.kaf/config)")
-	rootCmd.PersistentFlags().StringSliceVarP(&brokersFlag, "brokers", "b", nil, "Comma separated list of broker ip:port pairs")
-	rootCmd.PersistentFlags().StringVar(&schemaRegistryURL, "schema-registry", "", "URL to a Confluent schema registry. Used for attempting to decode Avro-encoded messages")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Whether to turn on sarama logging")
-	rootCmd.PersistentFlags().StringVarP(&clusterOverride, "cluster", "c", "", "set a temporary current cluster")
-	cobra.OnInitialize(onInit)
-}
-
-/*
-var setupProtoDescriptorRegistry = func(cmd *cobra.Command, args []string) {
-	if protoType != "" {
-		r, err := proto.NewDescriptorRegistry(protoFiles, protoExclude)
-		if err != nil {
-			errorExit("Failed to load protobuf files: %v\n", err)
-		}
-		reg = r
-	}
-}*/
-
-// protectConfigFile is intentionally not called at runtime. The primary
-// protection against config corruption is that SetContext() never calls
-// cfg.Write() or cfg.SetCurrentCluster(). This function is kept for
-// reference but should not be invoked automatically.
-func protectConfigFile(cfgPath string) error {
-	path := cfgPath
-	if path == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return err
-		}
-		path = home + "/.kaf/config"
-	}
-	return os.Chmod(path, 0444)
-}
-
-// InitFromConfig reads the kaf config file at cfgPath (pass "" to use the
-// default ~/.kaf/config) and sets the active cluster. This is identical to the
-// setup that the main CLI performs via cobra.OnInitialize; use it in examples
-// and standalone programs that do not go through the Cobra entry point.
-func InitFromConfig(cfgPath string) error {
-	var err error
-	cfg, err = config.ReadConfig(cfgPath)
-	if err != nil {
-		return fmt.Errorf("reading kaf config: %w", err)
-	}
-	cluster := cfg.ActiveCluster()
-	if cluster != nil {
-		currentCluster = cluster
-	} else {
-		currentCluster = &config.Cluster{
-			Brokers: []string{"localhost:9092"},
-		}
-	}
-	return nil
-}
-
-func onInit() {
-	var err error
-	cfg, err = config.ReadConfig(cfgFile)
-	if err != nil {
-		// Instead of panicking, create a default config
-		shared.Log.Warn("could not read config file, using defaults", "err", err)
-		cfg = config.Config{
-			Clusters: []*config.Cluster{},
-		}
-	}
-
-	cfg.ClusterOverride = clusterOverride
-
-	cluster := cfg.ActiveCluster()
-	if cluster != nil {
-		// Use active cluster from config
-		currentCluster = cluster
-	} else {
-		// Create sane default if not configured
-		currentCluster = &config.Cluster{
-			Brokers: []string{"localhost:9092"},
-		}
-	}
-
-	// Any set flags override the configuration
-	if schemaRegistryURL != "" {
-		currentCluster.SchemaRegistryURL = schemaRegistryURL
-		currentCluster.SchemaRegistryCredentials = nil
-	}
-
-	if brokersFlag != nil {
-		currentCluster.Brokers = brokersFlag
-	}
-	// sarama.Logger is set by InitTUIWriters() before the TUI starts.
+// getClusterAdmin returns a lease on the session's pooled cluster admin.
+// Callers must Close it to release the lease; the underlying admin stays open
+// until the active cluster changes and its last lease is released.
+func (kp KafkaDataSourceKaf) getClusterAdmin() (admin ClusterAdminInterface, e error) {
+	return kp.sess().connections().leaseAdmin(kp.dialClusterAdmin)
 }
 
-func getClusterAdmin() (admin ClusterAdminInterface, e error) {
-	cfg, err := getConfig()
+func (kp KafkaDataSourceKaf) dialClusterAdmin() (ClusterAdminInterface, error) {
+	cfg, err := kp.getConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Kafka config: %v", err)
 	}
 
-	if currentCluster == nil {
-		return nil, fmt.Errorf("no Kafka cluster configured. Please check your configuration or ensure Kafka is running")
-	}
-
-	clusterAdmin, err := kafkaClientFactory.CreateClusterAdmin(currentCluster.Brokers, cfg)
+	brokers := kp.sess().activeCluster().Brokers
+	clusterAdmin, err := kp.factory().CreateClusterAdmin(brokers, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("unable to connect to Kafka cluster at %v: %v\nPlease ensure Kafka is running and accessible", currentCluster.Brokers, err)
+		return nil, fmt.Errorf("unable to connect to Kafka cluster at %v: %v\nPlease ensure Kafka is running and accessible", brokers, err)
 	}
 
 	return clusterAdmin, nil
 }
 
-func getClient() (client sarama.Client, e error) {
-	cfg, err := getConfig()
-	if err != nil {
-		return nil, err
-	}
-	client, err = sarama.NewClient(currentCluster.Brokers, cfg)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to get client: %v\n", err)
-	}
-	return client, nil
+// getClient returns a lease on the session's pooled sarama client. Like
+// getClusterAdmin, closing the lease leaves the client open.
+func (kp KafkaDataSourceKaf) getClient() (client sarama.Client, e error) {
+	return kp.sess().connections().leaseClient(func() (sarama.Client, error) {
+		cfg, err := kp.getConfig()
+		if err != nil {
+			return nil, err
+		}
+		return kp.getClientFromConfig(cfg)
+	})
 }
 
-func getClientFromConfig(config *sarama.Config) (sarama.Client, error) {
-	client, err := sarama.NewClient(currentCluster.Brokers, config)
+// getClientFromConfig dials a dedicated client with a caller-tuned config. It
+// bypasses the pool, so the caller owns (and must Close) the client.
+func (kp KafkaDataSourceKaf) getClientFromConfig(config *sarama.Config) (sarama.Client, error) {
+	cluster := kp.sess().activeCluster()
+	if cluster == nil {
+		return nil, fmt.Errorf("no Kafka cluster configured. Please check your configuration or ensure Kafka is running")
+	}
+	client, err := kp.factory().CreateClient(cluster.Brokers, config)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to get client: %v\n", err)
 	}
 	return client, nil
 }
 
-func getSchemaCache() (cache *avro.SchemaCache, er error) {
-	if currentCluster == nil || currentCluster.SchemaRegistryURL == "" {
+func (kp KafkaDataSourceKaf) getSchemaCache() (cache *avro.SchemaCache, er error) {
+	cluster := kp.sess().activeCluster()
+	if cluster == nil || cluster.SchemaRegistryURL == "" {
 		return nil, nil
 	}
 	var username, password string
-	if creds := currentCluster.SchemaRegistryCredentials; creds != nil {
+	if creds := cluster.SchemaRegistryCredentials; creds != nil {
 		username = creds.Username
 		password = creds.Password
 	}
-	cache, err := avro.NewSchemaCache(currentCluster.SchemaRegistryURL, username, password)
+	cache, err := avro.NewSchemaCache(cluster.SchemaRegistryURL, username, password)
 	if err != nil {
 		return nil, err
 	}
 	return cache, nil
 }
 
-// cachedSchemaCache is a process-lifetime cache of the schema registry client.
-// It is invalidated when SetContext switches the active cluster.
-var cachedSchemaCache *avro.SchemaCache
-
-// getOrInitSchemaCache returns the cached SchemaCache, initialising it on first
-// call. Returns nil (not an error) when no schema registry is configured.
-func getOrInitSchemaCache() (*avro.SchemaCache, error) {
-	if cachedSchemaCache != nil {
-		return cachedSchemaCache, nil
+// getOrInitSchemaCache returns the session's cached SchemaCache, initialising
+// it on first call. It is invalidated when SetContext switches the active
+// cluster. Returns nil (not an error) when no schema registry is configured.
+func (kp KafkaDataSourceKaf) getOrInitSchemaCache() (*avro.SchemaCache, error) {
+	s := kp.sess()
+	s.mu.Lock()
+	cached := s.schemaCache
+	s.mu.Unlock()
+	if cached != nil {
+		return cached, nil
 	}
-	sc, err := getSchemaCache()
+	sc, err := kp.getSchemaCache()
 	if err != nil {
 		return nil, err
 	}
-	cachedSchemaCache = sc
+	s.mu.Lock()
+	s.schemaCache = sc
+	s.mu.Unlock()
 	return sc, nil
 }
 
@@ -29,7 +29,7 @@ func TestKafkaDataSourceKaf_Init(t *testing.T) {
 			// This should not panic
 			kds.Init(tt.cfgOption)
 			if tt.cfgOption != "" {
-				assert.Equal(t, tt.cfgOption, cfgFile)
+				assert.Equal(t, tt.cfgOption, kds.session.cfgFile)
 			}
 		})
 	}
@@ -47,19 +47,15 @@ func TestKafkaDataSourceKaf_GetTopics_Integration(t *testing.T) {
 }
 
 func TestKafkaDataSourceKaf_GetContexts(t *testing.T) {
-	// Save original cfg and restore after test
-	originalCfg := cfg
-	defer func() { cfg = originalCfg }()
-
 	// Test with empty clusters
-	cfg.Clusters = []*config.Cluster{}
-	kds := &KafkaDataSourceKaf{}
+	kds := NewKafkaDataSourceKaf()
+	kds.session.cfg.Clusters = []*config.Cluster{}
 	contexts, err := kds.GetContexts()
 	assert.NoError(t, err)
 	assert.Empty(t, contexts)
 
 	// Test with some clusters
-	cfg.Clusters = []*config.Cluster{
+	kds.session.cfg.Clusters = []*config.Cluster{
 		{Name: "cluster1"},
 		{Name: "cluster2"},
 	}
@@ -71,21 +67,16 @@ func TestKafkaDataSourceKaf_GetContexts(t *testing.T) {
 }
 
 func TestKafkaDataSourceKaf_GetContext(t *testing.T) {
-	// Save original cfg and restore after test
-	originalCfg := cfg
-	defer func() { cfg = originalCfg }()
-
-	// Initialize cfg for the test with proper clusters slice
-	cfg = config.Config{
-		Clusters: []*config.Cluster{},
-	}
-
 	// Test when no active cluster
 	mockConfigManager := &MockConfigManager{
 		MockActiveCluster: nil,
 	}
 	mockClientFactory := &MockKafkaClientFactory{}
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	// Initialize cfg for the test with proper clusters slice
+	kds.session.cfg = config.Config{
+		Clusters: []*config.Cluster{},
+	}
 
 	context := kds.GetContext()
 	assert.Equal(t, "default localhost:9092", context)
@@ -94,6 +85,9 @@ func TestKafkaDataSourceKaf_GetContext(t *testing.T) {
 	mockCluster := &config.Cluster{Name: "test-cluster"}
 	mockConfigManager.MockActiveCluster = mockCluster
 	kds = NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.cfg = config.Config{
+		Clusters: []*config.Cluster{},
+	}
 
 	context = kds.GetContext()
 	assert.Equal(t, "test-cluster", context)
@@ -122,16 +116,14 @@ currentCluster: test-cluster
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Create temporary config file and load it into the package-level cfg.
+			// Create temporary config file and load it into the datasource.
 			tmpFile := "tmp_rovodev_test_config.yaml"
 			err := createTempConfigFile(tmpFile, tempConfig)
 			assert.NoError(t, err)
 			defer deleteFile(tmpFile)
 
-			cfgFile = tmpFile
-			onInit() // populate the package-level cfg from the temp file
-
 			kds := NewKafkaDataSourceKaf()
+			kds.Init(tmpFile)
 			err = kds.SetContext(tt.contextName)
 
 			if tt.expectError {
@@ -164,6 +156,12 @@ func TestKafkaDataSourceKaf_ConsumeTopic_Integration(t *testing.T) {
 }
 
 // Helper functions for testing
+
+// testCluster is the single-broker cluster the mock-backed tests target.
+func testCluster() *config.Cluster {
+	return &config.Cluster{Name: "test", Brokers: []string{"localhost:9092"}}
+}
+
 func createTempConfigFile(filename, content string) error {
 	file, err := os.Create(filename)
 	if err != nil {
@@ -179,6 +177,45 @@ func deleteFile(filename string) {
 	os.Remove(filename)
 }
 
+// TestKafkaDataSourceKaf_IndependentSessions verifies that two datasources keep
+// their own active cluster: switching one never affects the other.
+func TestKafkaDataSourceKaf_IndependentSessions(t *testing.T) {
+	clusters := func() config.Config {
+		return config.Config{Clusters: []*config.Cluster{
+			{Name: "a", Brokers: []string{"a:9092"}},
+			{Name: "b", Brokers: []string{"b:9092"}},
+		}}
+	}
+	ds1 := NewKafkaDataSourceKaf()
+	ds1.session.cfg = clusters()
+	ds2 := NewKafkaDataSourceKaf()
+	ds2.session.cfg = clusters()
+
+	assert.NoError(t, ds1.SetContext("a"))
+	assert.NoError(t, ds2.SetContext("b"))
+
+	assert.Equal(t, "a", ds1.GetContext())
+	assert.Equal(t, "b", ds2.GetContext())
+	assert.Equal(t, []string{"a:9092"}, ds1.session.activeCluster().Brokers)
+	assert.Equal(t, []string{"b:9092"}, ds2.session.activeCluster().Brokers)
+}
+
+// TestKafkaDataSourceKaf_SetOverrides verifies CLI overrides are applied to the
+// instance's active cluster on Init.
+func TestKafkaDataSourceKaf_SetOverrides(t *testing.T) {
+	cm := &MockConfigManager{MockConfig: config.Config{
+		CurrentCluster: "c1",
+		Clusters:       []*config.Cluster{{Name: "c1", Brokers: []string{"c1:9092"}}},
+	}}
+	kds := NewKafkaDataSourceKafWithDeps(&MockKafkaClientFactory{}, cm)
+	kds.SetOverrides(Overrides{Brokers: []string{"override:9092"}, SchemaRegistry: "http://sr:8081"})
+	kds.Init("")
+
+	cluster := kds.session.activeCluster()
+	assert.Equal(t, []string{"override:9092"}, cluster.Brokers)
+	assert.Equal(t, "http://sr:8081", cluster.SchemaRegistryURL)
+}
+
 // TestNewKafkaDataSourceKaf tests the constructor
 func TestNewKafkaDataSourceKaf(t *testing.T) {
 	kds := NewKafkaDataSourceKaf()
@@ -225,12 +262,8 @@ func TestKafkaDataSourceKaf_GetTopics_Success(t *testing.T) {
 
 	mockConfigManager := &MockConfigManager{}
 
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 
 	topics, err := kds.GetTopics()
 
@@ -250,12 +283,8 @@ func TestKafkaDataSourceKaf_GetTopics_AdminError(t *testing.T) {
 
 	mockConfigManager := &MockConfigManager{}
 
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 
 	topics, err := kds.GetTopics()
 
@@ -276,12 +305,8 @@ func TestKafkaDataSourceKaf_GetTopics_ListTopicsError(t *testing.T) {
 
 	mockConfigManager := &MockConfigManager{}
 
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 
 	topics, err := kds.GetTopics()
 
@@ -292,15 +317,6 @@ func TestKafkaDataSourceKaf_GetTopics_ListTopicsError(t *testing.T) {
 
 // TestKafkaDataSourceKaf_GetContext_WithActiveCluster tests context retrieval with active cluster
 func TestKafkaDataSourceKaf_GetContext_WithActiveCluster(t *testing.T) {
-	// Save original cfg and restore after test
-	originalCfg := cfg
-	defer func() { cfg = originalCfg }()
-
-	// Initialize cfg for the test with proper clusters slice
-	cfg = config.Config{
-		Clusters: []*config.Cluster{},
-	}
-
 	mockCluster := &config.Cluster{
 		Name: "test-cluster",
 	}
@@ -312,6 +328,9 @@ func TestKafkaDataSourceKaf_GetContext_WithActiveCluster(t *testing.T) {
 	mockClientFactory := &MockKafkaClientFactory{}
 
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.cfg = config.Config{
+		Clusters: []*config.Cluster{},
+	}
 
 	context := kds.GetContext()
 
@@ -320,15 +339,6 @@ func TestKafkaDataSourceKaf_GetContext_WithActiveCluster(t *testing.T) {
 
 // TestKafkaDataSourceKaf_GetContext_NoActiveCluster tests context retrieval without active cluster
 func TestKafkaDataSourceKaf_GetContext_NoActiveCluster(t *testing.T) {
-	// Save original cfg and restore after test
-	originalCfg := cfg
-	defer func() { cfg = originalCfg }()
-
-	// Initialize cfg for the test with proper clusters slice
-	cfg = config.Config{
-		Clusters: []*config.Cluster{},
-	}
-
 	mockConfigManager := &MockConfigManager{
 		MockActiveCluster: nil,
 	}
@@ -336,6 +346,9 @@ func TestKafkaDataSourceKaf_GetContext_NoActiveCluster(t *testing.T) {
 	mockClientFactory := &MockKafkaClientFactory{}
 
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.cfg = config.Config{
+		Clusters: []*config.Cluster{},
+	}
 
 	context := kds.GetContext()
 
@@ -344,31 +357,31 @@ func TestKafkaDataSourceKaf_GetContext_NoActiveCluster(t *testing.T) {
 
 // TestKafkaDataSourceKaf_SetContext_Success tests successful context setting
 func TestKafkaDataSourceKaf_SetContext_Success(t *testing.T) {
-	// SetContext reads from the package-level cfg, so populate it directly.
-	cfg = config.Config{
+	// SetContext reads from the datasource's own cfg, so populate it directly.
+	kds := NewKafkaDataSourceKaf()
+	kds.session.cfg = config.Config{
 		Clusters: []*config.Cluster{
 			{Name: "cluster1"},
 			{Name: "cluster2"},
 		},
 	}
 
-	kds := NewKafkaDataSourceKaf()
 	err := kds.SetContext("cluster1")
 
 	assert.NoError(t, err)
-	assert.Equal(t, "cluster1", cfg.CurrentCluster)
+	assert.Equal(t, "cluster1", kds.session.cfg.CurrentCluster)
 }
 
 // TestKafkaDataSourceKaf_SetContext_ClusterNotFound tests setting non-existent context
 func TestKafkaDataSourceKaf_SetContext_ClusterNotFound(t *testing.T) {
-	cfg = config.Config{
+	kds := NewKafkaDataSourceKaf()
+	kds.session.cfg = config.Config{
 		Clusters: []*config.Cluster{
 			{Name: "cluster1"},
 			{Name: "cluster2"},
 		},
 	}
 
-	kds := NewKafkaDataSourceKaf()
 	err := kds.SetContext("nonexistent")
 
 	assert.Error(t, err)
@@ -382,7 +395,7 @@ func TestKafkaDataSourceKaf_SetContext_NoWrite(t *testing.T) {
 	mockConfigManager := &MockConfigManager{}
 	kds := NewKafkaDataSourceKafWithDeps(&MockKafkaClientFactory{}, mockConfigManager)
 
-	cfg = config.Config{
+	kds.session.cfg = config.Config{
 		Clusters: []*config.Cluster{
 			{Name: "safe-cluster", Brokers: []string{"localhost:9092"}},
 		},
@@ -412,12 +425,8 @@ func TestKafkaDataSourceKaf_GetConsumerGroups_Success(t *testing.T) {
 
 	mockConfigManager := &MockConfigManager{}
 
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 
 	groups, err := kds.GetConsumerGroups()
 
@@ -439,12 +448,8 @@ func TestKafkaDataSourceKaf_GetConsumerGroups_AdminError(t *testing.T) {
 	
 	mockConfigManager := &MockConfigManager{}
 	
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-	
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 	
 	groups, err := kds.GetConsumerGroups()
 	
@@ -464,12 +469,8 @@ func TestKafkaDataSourceKaf_GetConsumerGroups_ListGroupsError(t *testing.T) {
 	
 	mockConfigManager := &MockConfigManager{}
 	
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-	
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 	
 	groups, err := kds.GetConsumerGroups()
 	
@@ -491,12 +492,8 @@ func TestKafkaDataSourceKaf_GetConsumerGroups_DescribeGroupsError(t *testing.T)
 
 	mockConfigManager := &MockConfigManager{}
 
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 
 	groups, err := kds.GetConsumerGroups()
 
@@ -523,12 +520,8 @@ func TestKafkaDataSourceKaf_ConsumeTopic(t *testing.T) {
 	
 	mockConfigManager := &MockConfigManager{}
 	
-	// Set up global state for getClusterAdmin
-	originalFactory := kafkaClientFactory
-	defer func() { kafkaClientFactory = originalFactory }()
-	kafkaClientFactory = mockClientFactory
-	
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	kds.session.current = testCluster()
 	
 	// Create a context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
@@ -579,19 +572,14 @@ func TestKafkaDataSourceKaf_ConsumeTopic_AdminError(t *testing.T) {
 
 // TestKafkaDataSourceKaf_GetContexts_EmptyConfig tests GetContexts with empty config
 func TestKafkaDataSourceKaf_GetContexts_EmptyConfig(t *testing.T) {
-	// Save original cfg and restore after test
-	originalCfg := cfg
-	defer func() { cfg = originalCfg }()
-	
-	// Initialize cfg with empty clusters
-	cfg = config.Config{
-		Clusters: []*config.Cluster{},
-	}
-	
 	mockConfigManager := &MockConfigManager{}
 	mockClientFactory := &MockKafkaClientFactory{}
 	
 	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
+	// Initialize cfg with empty clusters
+	kds.session.cfg = config.Config{
+		Clusters: []*config.Cluster{},
+	}
 	
 	contexts, err := kds.GetContexts()
 	
@@ -601,12 +589,12 @@ func TestKafkaDataSourceKaf_GetContexts_EmptyConfig(t *testing.T) {
 
 // TestKafkaDataSourceKaf_GetContexts_WithClusters tests GetContexts with clusters
 func TestKafkaDataSourceKaf_GetContexts_WithClusters(t *testing.T) {
-	// Save original cfg and restore after test
-	originalCfg := cfg
-	defer func() { cfg = originalCfg }()
+	mockConfigManager := &MockConfigManager{}
+	mockClientFactory := &MockKafkaClientFactory{}
 	
+	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
 	// Initialize cfg with clusters
-	cfg = config.Config{
+	kds.session.cfg = config.Config{
 		Clusters: []*config.Cluster{
 			{Name: "cluster1"},
 			{Name: "cluster2"},
@@ -614,11 +602,6 @@ func TestKafkaDataSourceKaf_GetContexts_WithClusters(t *testing.T) {
 		},
 	}
 	
-	mockConfigManager := &MockConfigManager{}
-	mockClientFactory := &MockKafkaClientFactory{}
-	
-	kds := NewKafkaDataSourceKafWithDeps(mockClientFactory, mockConfigManager)
-	
 	contexts, err := kds.GetContexts()
 	
 	assert.NoError(t, err)
@@ -630,10 +613,6 @@ func TestKafkaDataSourceKaf_GetContexts_WithClusters(t *testing.T) {
 
 // TestKafkaDataSourceKaf_Init_WithDeps tests the Init method with dependencies
 func TestKafkaDataSourceKaf_Init_WithDeps(t *testing.T) {
-	// Save original cfgFile and restore after test
-	originalCfgFile := cfgFile
-	defer func() { cfgFile = originalCfgFile }()
-	
 	mockConfigManager := &MockConfigManager{}
 	mockClientFactory := &MockKafkaClientFactory{}
 	
@@ -642,10 +621,12 @@ func TestKafkaDataSourceKaf_Init_WithDeps(t *testing.T) {
 	// Test with empty config option
 	kds.Init("")
 	// cfgFile should remain unchanged
+	assert.Equal(t, "", kds.session.cfgFile)
 	
 	// Test with config option
 	kds.Init("test-config.yaml")
-	assert.Equal(t, "test-config.yaml", cfgFile)
+	assert.Equal(t, "test-config.yaml", kds.session.cfgFile)
+	assert.Equal(t, 2, mockConfigManager.ReadConfigCallCount, "Init reads through the injected config manager")
 }
 
 // TestDefaultKafkaClientFactory tests the default factory
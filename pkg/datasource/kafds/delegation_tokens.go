@@ -0,0 +1,270 @@
+package kafds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+)
+
+// sarama has no delegation-token requests, so CreateDelegationToken,
+// RenewDelegationToken, ExpireDelegationToken and DescribeDelegationTokens v1
+// (Kafka 2.0+, non-flexible) are hand-encoded. Any broker serves them; the
+// controller is used because controllerConn already checks ApiVersions.
+
+const (
+	apiKeyCreateDelegationToken    int16 = 38
+	apiKeyRenewDelegationToken     int16 = 39
+	apiKeyExpireDelegationToken    int16 = 40
+	apiKeyDescribeDelegationTokens int16 = 41
+
+	delegationTokenVersion   = 1
+	delegationTokenOperation = "delegation tokens (Kafka 2.0+)"
+)
+
+// DescribeDelegationTokens implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) DescribeDelegationTokens(owners []string) ([]api.DelegationToken, error) {
+	for _, o := range owners {
+		if err := api.ValidatePrincipal(o); err != nil {
+			return nil, api.InvalidDelegationTokenError{Reason: "owner " + o + ": must be in the form <type>:<name>"}
+		}
+	}
+	resp, err := kp.delegationTokenRoundTrip(apiKeyDescribeDelegationTokens, "DescribeDelegationTokens", encodeDescribeDelegationTokensRequest(owners))
+	if err != nil {
+		return nil, err
+	}
+	return decodeDescribeDelegationTokensResponse(resp)
+}
+
+// CreateDelegationToken implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) CreateDelegationToken(req api.CreateDelegationTokenRequest) (api.DelegationToken, error) {
+	if err := api.ValidateCreateDelegationToken(req); err != nil {
+		return api.DelegationToken{}, err
+	}
+	resp, err := kp.delegationTokenRoundTrip(apiKeyCreateDelegationToken, "CreateDelegationToken", encodeCreateDelegationTokenRequest(req))
+	if err != nil {
+		return api.DelegationToken{}, err
+	}
+	return decodeCreateDelegationTokenResponse(resp, req.Renewers)
+}
+
+// RenewDelegationToken implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) RenewDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	if err := api.ValidateDelegationTokenTarget(token); err != nil {
+		return time.Time{}, err
+	}
+	resp, err := kp.delegationTokenRoundTrip(apiKeyRenewDelegationToken, "RenewDelegationToken", encodeDelegationTokenPeriodRequest(token.HMAC, period))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return decodeDelegationTokenExpiryResponse(resp, "renew")
+}
+
+// ExpireDelegationToken implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) ExpireDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	if err := api.ValidateDelegationTokenTarget(token); err != nil {
+		return time.Time{}, err
+	}
+	resp, err := kp.delegationTokenRoundTrip(apiKeyExpireDelegationToken, "ExpireDelegationToken", encodeDelegationTokenPeriodRequest(token.HMAC, period))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return decodeDelegationTokenExpiryResponse(resp, "expire")
+}
+
+// delegationTokenRoundTrip sends one delegation-token request at
+// delegationTokenVersion and returns the response body.
+func (kp KafkaDataSourceKaf) delegationTokenRoundTrip(apiKey int16, name string, body []byte) ([]byte, error) {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+	bc, err := kp.controllerConn(admin, apiKey, delegationTokenVersion, delegationTokenOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer bc.Close()
+
+	resp, err := bc.roundTrip(apiKey, delegationTokenVersion, body)
+	if err != nil {
+		return nil, api.NewConnectionErrorWithCause(name, err)
+	}
+	return resp, nil
+}
+
+// durationMs converts a period to milliseconds; <= 0 becomes -1, which the
+// broker reads as "use the default" (or "now" for expire).
+func durationMs(d time.Duration) int64 {
+	if d <= 0 {
+		return -1
+	}
+	return d.Milliseconds()
+}
+
+// splitPrincipal splits "<type>:<name>" at the first colon.
+func splitPrincipal(p string) (string, string) {
+	typ, name, _ := strings.Cut(p, ":")
+	return typ, name
+}
+
+func (e *wireEncoder) putPrincipal(p string) {
+	typ, name := splitPrincipal(p)
+	e.putString(typ)
+	e.putString(name)
+}
+
+func (d *wireDecoder) principal() string {
+	typ := d.string()
+	return typ + ":" + d.string()
+}
+
+func timeFromMs(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// delegationTokenErr maps a response error code; op is "create", "renew",
+// "expire" or "describe".
+func delegationTokenErr(op string, code sarama.KError) error {
+	switch code {
+	case sarama.ErrNoError:
+		return nil
+	case sarama.ErrDelegationTokenAuthDisabled:
+		return api.NotSupportedError{Operation: "delegation tokens (disabled on this cluster; set delegation.token.secret.key)"}
+	case sarama.ErrDelegationTokenRequestNotAllowed:
+		return fmt.Errorf("failed to %s delegation token: tokens can only be managed over a SASL connection not itself authenticated with a token: %w", op, code)
+	case sarama.ErrDelegationTokenAuthorizationFailed, sarama.ErrDelegationTokenOwnerMismatch:
+		return api.NewAuthorizationError(code.Error(), "delegation-token", op)
+	}
+	return fmt.Errorf("failed to %s delegation token: %w", op, code)
+}
+
+// encodeCreateDelegationTokenRequest encodes a CreateDelegationToken v1 body:
+//
+//	[renewers] max_lifetime_ms
+//	  renewers => principal_type principal_name
+func encodeCreateDelegationTokenRequest(req api.CreateDelegationTokenRequest) []byte {
+	var e wireEncoder
+	e.putArrayLen(len(req.Renewers))
+	for _, r := range req.Renewers {
+		e.putPrincipal(r)
+	}
+	e.putInt64(durationMs(req.MaxLifetime))
+	return e.buf
+}
+
+// decodeCreateDelegationTokenResponse decodes a CreateDelegationToken v1 body;
+// the response does not echo the renewers, so the requested ones are kept.
+//
+//	error_code principal_type principal_name issue_timestamp_ms
+//	expiry_timestamp_ms max_timestamp_ms token_id hmac throttle_time_ms
+func decodeCreateDelegationTokenResponse(body []byte, renewers []string) (api.DelegationToken, error) {
+	d := wireDecoder{buf: body}
+	code := sarama.KError(d.int16())
+	t := api.DelegationToken{Owner: d.principal(), Renewers: renewers}
+	t.IssueTime = timeFromMs(d.int64())
+	t.ExpiryTime = timeFromMs(d.int64())
+	t.MaxTime = timeFromMs(d.int64())
+	t.TokenID = d.string()
+	t.HMAC = d.bytes()
+	d.int32() // throttle_time_ms
+	if d.err != nil {
+		return api.DelegationToken{}, fmt.Errorf("decoding CreateDelegationToken response: %w", d.err)
+	}
+	if err := delegationTokenErr("create", code); err != nil {
+		return api.DelegationToken{}, err
+	}
+	return t, nil
+}
+
+// encodeDelegationTokenPeriodRequest encodes a RenewDelegationToken or
+// ExpireDelegationToken v1 body, which share one layout:
+//
+//	hmac renew_period_ms|expiry_time_period_ms
+func encodeDelegationTokenPeriodRequest(hmac []byte, period time.Duration) []byte {
+	var e wireEncoder
+	e.putBytes(hmac)
+	e.putInt64(durationMs(period))
+	return e.buf
+}
+
+// decodeDelegationTokenExpiryResponse decodes a RenewDelegationToken or
+// ExpireDelegationToken v1 body:
+//
+//	error_code expiry_timestamp_ms throttle_time_ms
+func decodeDelegationTokenExpiryResponse(body []byte, op string) (time.Time, error) {
+	d := wireDecoder{buf: body}
+	code := sarama.KError(d.int16())
+	expiry := d.int64()
+	d.int32() // throttle_time_ms
+	if d.err != nil {
+		return time.Time{}, fmt.Errorf("decoding %s response: %w", op, d.err)
+	}
+	if err := delegationTokenErr(op, code); err != nil {
+		return time.Time{}, err
+	}
+	return timeFromMs(expiry), nil
+}
+
+// encodeDescribeDelegationTokensRequest encodes a DescribeDelegationTokens v1
+// body; no owners encodes null, which describes every token:
+//
+//	[owners]
+//	  owners => principal_type principal_name
+func encodeDescribeDelegationTokensRequest(owners []string) []byte {
+	var e wireEncoder
+	if len(owners) == 0 {
+		e.putInt32(-1)
+		return e.buf
+	}
+	e.putArrayLen(len(owners))
+	for _, o := range owners {
+		e.putPrincipal(o)
+	}
+	return e.buf
+}
+
+// decodeDescribeDelegationTokensResponse decodes a DescribeDelegationTokens v1
+// body into tokens ordered by expiry:
+//
+//	error_code [tokens] throttle_time_ms
+//	  tokens => principal_type principal_name issue_timestamp expiry_timestamp
+//	            max_timestamp token_id hmac [renewers]
+//	    renewers => principal_type principal_name
+func decodeDescribeDelegationTokensResponse(body []byte) ([]api.DelegationToken, error) {
+	d := wireDecoder{buf: body}
+	code := sarama.KError(d.int16())
+	var out []api.DelegationToken
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		t := api.DelegationToken{Owner: d.principal()}
+		t.IssueTime = timeFromMs(d.int64())
+		t.ExpiryTime = timeFromMs(d.int64())
+		t.MaxTime = timeFromMs(d.int64())
+		t.TokenID = d.string()
+		t.HMAC = d.bytes()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			t.Renewers = append(t.Renewers, d.principal())
+		}
+		out = append(out, t)
+	}
+	d.int32() // throttle_time_ms
+	if d.err != nil {
+		return nil, fmt.Errorf("decoding DescribeDelegationTokens response: %w", d.err)
+	}
+	if err := delegationTokenErr("describe", code); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].ExpiryTime.Equal(out[j].ExpiryTime) {
+			return out[i].ExpiryTime.Before(out[j].ExpiryTime)
+		}
+		return out[i].TokenID < out[j].TokenID
+	})
+	return out, nil
+}
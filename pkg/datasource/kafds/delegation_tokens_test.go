@@ -0,0 +1,156 @@
+package kafds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	createDelegationTokenRequestBody = `
+		00000001 0004 55736572 0003 626f62   # renewers (1), User:bob
+		00000000 0036ee80                    # max_lifetime_ms 3600000`
+
+	createDelegationTokenResponseBody = `
+		0000                                 # error_code
+		0004 55736572 0005 616c696365        # User:alice
+		00000000000003e8 00000000000007d0    # issue 1000, expiry 2000
+		0000000000000bb8                     # max 3000
+		0002 746b 00000002 abcd              # token_id "tk", hmac
+		00000000                             # throttle`
+
+	describeDelegationTokensResponseBody = `
+		0000 00000002                        # error_code, tokens (2)
+		0004 55736572 0005 616c696365        # User:alice
+		00000000000003e8 0000000000001388    # issue 1000, expiry 5000
+		0000000000002710 0001 62 00000001 ff # max 10000, "b", hmac
+		00000000                             # renewers (0)
+		0004 55736572 0005 616c696365        # User:alice
+		00000000000003e8 00000000000007d0    # issue 1000, expiry 2000
+		0000000000002710 0001 61 00000001 ee # max 10000, "a", hmac
+		00000001 0004 55736572 0003 626f62   # renewers (1), User:bob
+		00000000                             # throttle`
+
+	// ApiVersions v0 replies advertising CreateDelegationToken and
+	// DescribeDelegationTokens up to v1.
+	apiVersionsCreateTokenV1   = `0000 00000001 0026 0000 0001`
+	apiVersionsDescribeTokenV1 = `0000 00000001 0029 0000 0001`
+)
+
+func TestEncodeCreateDelegationTokenRequest(t *testing.T) {
+	got := encodeCreateDelegationTokenRequest(api.CreateDelegationTokenRequest{Renewers: []string{"User:bob"}, MaxLifetime: time.Hour})
+	assert.Equal(t, unhex(t, stripComments(createDelegationTokenRequestBody)), got)
+
+	t.Run("broker default lifetime", func(t *testing.T) {
+		got := encodeCreateDelegationTokenRequest(api.CreateDelegationTokenRequest{})
+		assert.Equal(t, unhex(t, "00000000 ffffffffffffffff"), got)
+	})
+}
+
+func TestDecodeCreateDelegationTokenResponse(t *testing.T) {
+	got, err := decodeCreateDelegationTokenResponse(unhex(t, stripComments(createDelegationTokenResponseBody)), []string{"User:bob"})
+	require.NoError(t, err)
+	assert.Equal(t, api.DelegationToken{
+		TokenID:    "tk",
+		Owner:      "User:alice",
+		Renewers:   []string{"User:bob"},
+		IssueTime:  time.UnixMilli(1000),
+		ExpiryTime: time.UnixMilli(2000),
+		MaxTime:    time.UnixMilli(3000),
+		HMAC:       []byte{0xab, 0xcd},
+	}, got)
+
+	t.Run("token auth disabled", func(t *testing.T) {
+		body := unhex(t, stripComments(createDelegationTokenResponseBody))
+		body[1] = byte(sarama.ErrDelegationTokenAuthDisabled)
+		_, err := decodeCreateDelegationTokenResponse(body, nil)
+		var ns api.NotSupportedError
+		assert.ErrorAs(t, err, &ns)
+	})
+
+	t.Run("request over a token connection", func(t *testing.T) {
+		body := unhex(t, stripComments(createDelegationTokenResponseBody))
+		body[1] = byte(sarama.ErrDelegationTokenRequestNotAllowed)
+		_, err := decodeCreateDelegationTokenResponse(body, nil)
+		assert.ErrorIs(t, err, sarama.ErrDelegationTokenRequestNotAllowed)
+		assert.Contains(t, err.Error(), "SASL connection")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		full := unhex(t, stripComments(createDelegationTokenResponseBody))
+		_, err := decodeCreateDelegationTokenResponse(full[:len(full)-6], nil)
+		assert.ErrorIs(t, err, errShortResponse)
+	})
+}
+
+func TestDelegationTokenPeriodRequest(t *testing.T) {
+	assert.Equal(t, unhex(t, "00000002 abcd 000000000036ee80"), encodeDelegationTokenPeriodRequest([]byte{0xab, 0xcd}, time.Hour))
+	assert.Equal(t, unhex(t, "00000001 ab ffffffffffffffff"), encodeDelegationTokenPeriodRequest([]byte{0xab}, 0), "zero period is sent as -1")
+
+	expiry, err := decodeDelegationTokenExpiryResponse(unhex(t, "0000 00000000000007d0 00000000"), "renew")
+	require.NoError(t, err)
+	assert.Equal(t, time.UnixMilli(2000), expiry)
+
+	_, err = decodeDelegationTokenExpiryResponse(unhex(t, "0041 0000000000000000 00000000"), "renew") // TOKEN_AUTHORIZATION_FAILED
+	var ae api.AuthorizationError
+	assert.ErrorAs(t, err, &ae)
+
+	_, err = decodeDelegationTokenExpiryResponse(unhex(t, "0042 0000000000000000 00000000"), "expire") // TOKEN_EXPIRED
+	assert.ErrorIs(t, err, sarama.ErrDelegationTokenExpired)
+}
+
+func TestDescribeDelegationTokensCodec(t *testing.T) {
+	assert.Equal(t, unhex(t, "ffffffff"), encodeDescribeDelegationTokensRequest(nil), "no owners describes every token")
+	assert.Equal(t, unhex(t, "00000001 0004 55736572 0003 626f62"), encodeDescribeDelegationTokensRequest([]string{"User:bob"}))
+
+	got, err := decodeDescribeDelegationTokensResponse(unhex(t, stripComments(describeDelegationTokensResponseBody)))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].TokenID, "ordered by expiry")
+	assert.Equal(t, []string{"User:bob"}, got[0].Renewers)
+	assert.Equal(t, "b", got[1].TokenID)
+	assert.Empty(t, got[1].Renewers)
+}
+
+func TestDelegationTokens(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockBrokers:      []*sarama.Broker{newTestBroker(1, "b1:9092")},
+		MockControllerID: 1,
+	}
+
+	t.Run("create", func(t *testing.T) {
+		keys := withBrokerPipe(t, "b1:9092", unhex(t, apiVersionsCreateTokenV1), unhex(t, stripComments(createDelegationTokenResponseBody)))
+		got, err := mockAdminDS(admin).CreateDelegationToken(api.CreateDelegationTokenRequest{MaxLifetime: time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, "tk", got.TokenID)
+		assert.Equal(t, []int16{apiKeyApiVersions, apiKeyCreateDelegationToken}, collectKeys(keys))
+	})
+
+	t.Run("describe", func(t *testing.T) {
+		keys := withBrokerPipe(t, "b1:9092", unhex(t, apiVersionsDescribeTokenV1), unhex(t, stripComments(describeDelegationTokensResponseBody)))
+		got, err := mockAdminDS(admin).DescribeDelegationTokens(nil)
+		require.NoError(t, err)
+		assert.Len(t, got, 2)
+		assert.Equal(t, []int16{apiKeyApiVersions, apiKeyDescribeDelegationTokens}, collectKeys(keys))
+	})
+
+	t.Run("broker older than 2.0", func(t *testing.T) {
+		withBrokerPipe(t, "b1:9092", unhex(t, `0000 00000001 0026 0000 0000`))
+		_, err := mockAdminDS(admin).CreateDelegationToken(api.CreateDelegationTokenRequest{})
+		var ns api.NotSupportedError
+		assert.ErrorAs(t, err, &ns)
+	})
+
+	t.Run("validated before dialing", func(t *testing.T) {
+		_, err := mockAdminDS(admin).CreateDelegationToken(api.CreateDelegationTokenRequest{Renewers: []string{"bob"}})
+		var ie api.InvalidDelegationTokenError
+		assert.ErrorAs(t, err, &ie)
+
+		_, err = mockAdminDS(admin).RenewDelegationToken(api.DelegationToken{TokenID: "tk"}, time.Hour)
+		assert.ErrorAs(t, err, &ie)
+	})
+}
@@ -31,17 +31,18 @@ var deviceAuthURLFor = func(cluster string) string {
 
 // PrepareOAuthDeviceFlow runs the interactive OAuth2 device-code grant for the
 // active cluster when configured, BEFORE the TUI redirects stdout (AA-13). It
-// loads the kaf config at cfgPath (pass "" for the default), validates the
+// loads the kaf config at cfgPath (pass "" for the default), honours the
+// datasource's --cluster override, validates the
 // OAUTHBEARER credential combination, and — when device flow applies and no
 // usable/refreshable cached token exists — displays the verification URL and
 // user code on w and caches the resulting token. It is a no-op for non-device
 // clusters and returns a descriptive error for invalid configuration.
-func PrepareOAuthDeviceFlow(cfgPath string, w io.Writer) error {
-	kc, err := kafcfg.ReadConfig(cfgPath)
+func (kp KafkaDataSourceKaf) PrepareOAuthDeviceFlow(cfgPath string, w io.Writer) error {
+	kc, err := kp.configs().ReadConfig(cfgPath)
 	if err != nil {
 		return nil // no kaf config: nothing to prepare (kafds handles defaults)
 	}
-	kc.ClusterOverride = clusterOverride
+	kc.ClusterOverride = kp.sess().overrides.Cluster
 	cluster := kc.ActiveCluster()
 	if cluster == nil || cluster.SASL == nil {
 		return nil
@@ -20,6 +20,13 @@ type ClusterAdminInterface interface {
 	DescribeClientQuotas(components []sarama.QuotaFilterComponent, strict bool) ([]sarama.DescribeClientQuotasEntry, error)
 	// AlterClientQuotas applies a single set/remove op to the entity's quotas.
 	AlterClientQuotas(entity []sarama.QuotaEntityComponent, op sarama.ClientQuotasOp, validateOnly bool) error
+	// DescribeUserScramCredentials returns the SCRAM mechanisms configured for
+	// the users (every user when empty).
+	DescribeUserScramCredentials(users []string) ([]*sarama.DescribeUserScramCredentialsResult, error)
+	// UpsertUserScramCredentials creates or replaces salted SCRAM credentials.
+	UpsertUserScramCredentials(upsert []sarama.AlterUserScramCredentialsUpsert) ([]*sarama.AlterUserScramCredentialsResult, error)
+	// DeleteUserScramCredentials removes SCRAM credentials.
+	DeleteUserScramCredentials(delete []sarama.AlterUserScramCredentialsDelete) ([]*sarama.AlterUserScramCredentialsResult, error)
 	// DescribeCluster returns the online brokers and the active controller ID.
 	DescribeCluster() (brokers []*sarama.Broker, controllerID int32, err error)
 	// DescribeConfig returns the config entries for a resource (e.g. a broker).
@@ -84,9 +91,3 @@ func (m *DefaultConfigManager) ReadConfig(configFile string) (config.Config, err
 func (m *DefaultConfigManager) GetActiveCluster(cfg config.Config) *config.Cluster {
 	return cfg.ActiveCluster()
 }
-
-// Global instances that can be replaced for testing
-var (
-	kafkaClientFactory KafkaClientFactory = &DefaultKafkaClientFactory{}
-	configManager      ConfigManager      = &DefaultConfigManager{}
-)
@@ -118,20 +118,14 @@ func TestNewKsqlClient_NotConfigured(t *testing.T) {
 	assert.Nil(t, c)
 }
 
-// withKsql overrides the ksql endpoint resolver for the duration of a test. It
-// also neutralizes the package-level cfg.Clusters so KafkaDataSourceKaf{}.
-// GetContext takes its safe early-return path (a zero-value datasource has a nil
-// configManager) regardless of state left by other tests in the package.
+// withKsql overrides the ksql endpoint resolver for the duration of a test.
+// A zero-value KafkaDataSourceKaf has no loaded config, so GetContext takes its
+// safe early-return path.
 func withKsql(t *testing.T, ep *appconfig.KsqlEndpoint) {
 	t.Helper()
 	prev := loadKsqlEndpoint
 	loadKsqlEndpoint = func(context string) *appconfig.KsqlEndpoint { return ep }
-	prevClusters := cfg.Clusters
-	cfg.Clusters = nil
-	t.Cleanup(func() {
-		loadKsqlEndpoint = prev
-		cfg.Clusters = prevClusters
-	})
+	t.Cleanup(func() { loadKsqlEndpoint = prev })
 }
 
 func TestListKsqlStreams_NotConfigured(t *testing.T) {
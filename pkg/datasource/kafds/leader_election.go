@@ -0,0 +1,178 @@
+package kafds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+)
+
+// sarama's ElectLeaders encodes v0/v1 with compact arrays and cannot ask for
+// every partition, so ElectLeaders v2 (Kafka 2.4+, the first version with
+// unclean elections) is hand-encoded and sent to the controller.
+
+const (
+	apiKeyElectLeaders int16 = 43
+
+	electLeadersVersion   = 2
+	electLeadersOperation = "leader election (Kafka 2.4+)"
+	// electLeadersTimeoutMs is how long the controller waits for the elections
+	// to complete before answering; the Java admin client's default.
+	electLeadersTimeoutMs = 30_000
+)
+
+// DescribeLeadership implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) DescribeLeadership(_ context.Context, topics []string) ([]api.PartitionLeadership, error) {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+	if len(topics) == 0 {
+		details, err := admin.ListTopics()
+		if err != nil {
+			return nil, fmt.Errorf("listing topics: %w", err)
+		}
+		for name := range details {
+			topics = append(topics, name)
+		}
+	}
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	metadata, err := admin.DescribeTopics(topics)
+	if err != nil {
+		return nil, fmt.Errorf("describing topics: %w", err)
+	}
+	var out []api.PartitionLeadership
+	for _, t := range metadata {
+		if t == nil {
+			continue
+		}
+		if t.Err != sarama.ErrNoError {
+			return nil, api.TopicNotFoundError{TopicName: t.Name}
+		}
+		for _, p := range t.Partitions {
+			out = append(out, api.PartitionLeadership{
+				Topic: t.Name, Partition: p.ID, Leader: p.Leader, Replicas: p.Replicas, ISR: p.Isr,
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Topic != out[j].Topic {
+			return out[i].Topic < out[j].Topic
+		}
+		return out[i].Partition < out[j].Partition
+	})
+	return out, nil
+}
+
+// ElectLeaders implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) ElectLeaders(_ context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	electionType, err := saramaElectionType(req.Type)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+	bc, err := kp.controllerConn(admin, apiKeyElectLeaders, electLeadersVersion, electLeadersOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer bc.Close()
+
+	resp, err := bc.roundTripFlexible(apiKeyElectLeaders, electLeadersVersion, encodeElectLeadersRequest(electionType, req.Partitions))
+	if err != nil {
+		return nil, api.NewConnectionErrorWithCause("ElectLeaders", err)
+	}
+	return decodeElectLeadersResponse(resp)
+}
+
+func saramaElectionType(t api.ElectionType) (sarama.ElectionType, error) {
+	switch t {
+	case api.ElectPreferred:
+		return sarama.PreferredElection, nil
+	case api.ElectUnclean:
+		return sarama.UncleanElection, nil
+	}
+	return 0, api.ElectionError{Reason: fmt.Sprintf("unknown election type %q", t)}
+}
+
+// encodeElectLeadersRequest encodes an ElectLeaders v2 body (flexible); no
+// partitions encodes null topic_partitions, which elects every partition:
+//
+//	election_type [topic_partitions] timeout_ms _tags
+//	  topic_partitions => topic [partitions] _tags
+func encodeElectLeadersRequest(t sarama.ElectionType, partitions []api.TopicPartition) []byte {
+	var e wireEncoder
+	e.putInt8(int8(t))
+	if len(partitions) == 0 {
+		e.putUvarint(0) // null => all partitions
+	} else {
+		byTopic := map[string][]int32{}
+		for _, tp := range partitions {
+			byTopic[tp.Topic] = append(byTopic[tp.Topic], tp.Partition)
+		}
+		e.putCompactArrayLen(len(byTopic))
+		for _, topic := range sortedKeys(byTopic) {
+			e.putCompactString(topic)
+			e.putCompactInt32s(sortedBrokers(byTopic[topic]))
+			e.putEmptyTags()
+		}
+	}
+	e.putInt32(electLeadersTimeoutMs)
+	e.putEmptyTags()
+	return e.buf
+}
+
+// decodeElectLeadersResponse decodes an ElectLeaders v2 body into sorted
+// per-partition results. ELECTION_NOT_NEEDED counts as success.
+//
+//	throttle_time_ms error_code [replica_election_results] _tags
+//	  replica_election_results => topic [partition_result] _tags
+//	    partition_result => partition_id error_code error_message _tags
+func decodeElectLeadersResponse(body []byte) ([]api.ElectionResult, error) {
+	d := wireDecoder{buf: body}
+	d.int32() // throttle_time_ms
+	code := sarama.KError(d.int16())
+	var out []api.ElectionResult
+	for i, n := 0, d.compactArrayLen(); i < n; i++ {
+		topic := d.compactString()
+		for j, m := 0, d.compactArrayLen(); j < m; j++ {
+			r := api.ElectionResult{Topic: topic, Partition: d.int32()}
+			pcode := sarama.KError(d.int16())
+			msg := d.compactString()
+			d.skipTags()
+			if pcode != sarama.ErrNoError && pcode != sarama.ErrElectionNotNeeded {
+				r.Err = pcode
+				if msg != "" {
+					r.Err = fmt.Errorf("%s: %w", msg, pcode)
+				}
+			}
+			out = append(out, r)
+		}
+		d.skipTags()
+	}
+	d.skipTags()
+	if d.err != nil {
+		return nil, fmt.Errorf("decoding ElectLeaders response: %w", d.err)
+	}
+	switch {
+	case code == sarama.ErrClusterAuthorizationFailed:
+		return nil, api.NewAuthorizationError(code.Error(), "cluster", "Alter")
+	case code != sarama.ErrNoError:
+		return nil, api.ElectionError{Reason: "the controller rejected the election", Cause: code}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Topic != out[j].Topic {
+			return out[i].Topic < out[j].Topic
+		}
+		return out[i].Partition < out[j].Partition
+	})
+	return out, nil
+}
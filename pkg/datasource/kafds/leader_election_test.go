@@ -0,0 +1,126 @@
+package kafds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	electLeadersRequestBody = `
+		00                         # election_type preferred
+		02 0274                    # topic_partitions (1), "t"
+		03 00000000 00000001 00    # partitions [0 1], tags
+		00007530 00                # timeout_ms 30000, tags`
+
+	electLeadersResponseBody = `
+		00000000 0000              # throttle, error_code
+		02 0274                    # results (1), "t"
+		04                         # partitions (3)
+		00000000 0000 00 00        # 0 elected
+		00000001 0054 00 00        # 1 ELECTION_NOT_NEEDED
+		00000002 0050 0378 78 00   # 2 PREFERRED_LEADER_NOT_AVAILABLE "xx"
+		00 00                      # topic, response tags`
+
+	// ApiVersions v0 replies advertising ElectLeaders up to v2 and only v0.
+	apiVersionsElectV2 = `0000 00000001 002b 0000 0002`
+	apiVersionsElectV0 = `0000 00000001 002b 0000 0000`
+)
+
+func TestEncodeElectLeadersRequest(t *testing.T) {
+	got := encodeElectLeadersRequest(sarama.PreferredElection, []api.TopicPartition{{Topic: "t", Partition: 1}, {Topic: "t", Partition: 0}})
+	assert.Equal(t, unhex(t, stripComments(electLeadersRequestBody)), got)
+
+	t.Run("whole cluster", func(t *testing.T) {
+		assert.Equal(t, unhex(t, "01 00 00007530 00"), encodeElectLeadersRequest(sarama.UncleanElection, nil))
+	})
+}
+
+func TestDecodeElectLeadersResponse(t *testing.T) {
+	got, err := decodeElectLeadersResponse(unhex(t, stripComments(electLeadersResponseBody)))
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.NoError(t, got[0].Err)
+	assert.NoError(t, got[1].Err, "no election needed is not a failure")
+	assert.ErrorIs(t, got[2].Err, sarama.ErrPreferredLeaderNotAvailable)
+	assert.Contains(t, got[2].Err.Error(), "xx")
+
+	t.Run("cluster authorization", func(t *testing.T) {
+		_, err := decodeElectLeadersResponse(unhex(t, "00000000 001f 01 00"))
+		var ae api.AuthorizationError
+		assert.ErrorAs(t, err, &ae)
+	})
+
+	t.Run("top-level error", func(t *testing.T) {
+		_, err := decodeElectLeadersResponse(unhex(t, "00000000 0029 01 00")) // NOT_CONTROLLER
+		var ee api.ElectionError
+		require.ErrorAs(t, err, &ee)
+		assert.ErrorIs(t, err, sarama.ErrNotController)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		full := unhex(t, stripComments(electLeadersResponseBody))
+		_, err := decodeElectLeadersResponse(full[:len(full)-6])
+		assert.ErrorIs(t, err, errShortResponse)
+	})
+}
+
+func TestElectLeaders(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockBrokers:      []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092")},
+		MockControllerID: 2,
+	}
+	req := api.ElectLeadersRequest{Type: api.ElectPreferred, Partitions: []api.TopicPartition{{Topic: "t", Partition: 0}}}
+
+	t.Run("asks the controller", func(t *testing.T) {
+		reply := append([]byte{0}, unhex(t, stripComments(electLeadersResponseBody))...)
+		keys := withBrokerPipe(t, "b2:9092", unhex(t, apiVersionsElectV2), reply)
+		got, err := mockAdminDS(admin).ElectLeaders(context.Background(), req)
+		require.NoError(t, err)
+		assert.Len(t, got, 3)
+		assert.Equal(t, []int16{apiKeyApiVersions, apiKeyElectLeaders}, collectKeys(keys))
+	})
+
+	t.Run("broker older than 2.4", func(t *testing.T) {
+		withBrokerPipe(t, "b2:9092", unhex(t, apiVersionsElectV0))
+		_, err := mockAdminDS(admin).ElectLeaders(context.Background(), req)
+		var ns api.NotSupportedError
+		assert.ErrorAs(t, err, &ns)
+	})
+
+	t.Run("unknown election type", func(t *testing.T) {
+		_, err := mockAdminDS(admin).ElectLeaders(context.Background(), api.ElectLeadersRequest{Type: "random"})
+		var ee api.ElectionError
+		assert.ErrorAs(t, err, &ee)
+	})
+}
+
+func TestDescribeLeadership(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockTopics: map[string]sarama.TopicDetail{"b": {}, "a": {}},
+		MockTopicMetadata: []*sarama.TopicMetadata{
+			{Name: "b", Partitions: []*sarama.PartitionMetadata{{ID: 0, Leader: 2, Replicas: []int32{1, 2}, Isr: []int32{1, 2}}}},
+			{Name: "a", Partitions: []*sarama.PartitionMetadata{
+				{ID: 1, Leader: -1, Replicas: []int32{3}, Isr: nil},
+				{ID: 0, Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}},
+			}},
+		},
+	}
+	got, err := mockAdminDS(admin).DescribeLeadership(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, "a", got[0].Topic)
+	assert.Equal(t, int32(0), got[0].Partition)
+	assert.True(t, got[1].Leaderless())
+	assert.True(t, got[2].NonPreferred())
+	assert.True(t, got[2].PreferredInSync())
+
+	admin.MockTopicMetadata = []*sarama.TopicMetadata{{Name: "gone", Err: sarama.ErrUnknownTopicOrPartition}}
+	_, err = mockAdminDS(admin).DescribeLeadership(context.Background(), []string{"gone"})
+	var nf api.TopicNotFoundError
+	assert.ErrorAs(t, err, &nf)
+}
@@ -62,6 +62,9 @@ type MockClusterAdmin struct {
 	MockControllerID          int32
 	ShouldFailDescribeCluster bool
 	MockConfigEntries         []sarama.ConfigEntry
+	// MockConfigsByName, when set, answers DescribeConfig per resource name
+	// instead of MockConfigEntries.
+	MockConfigsByName         map[string][]sarama.ConfigEntry
 	ShouldFailDescribeConfig  bool
 	MockLogDirs               map[int32][]sarama.DescribeLogDirsResponseDirMetadata
 	ShouldFailDescribeLogDirs bool
@@ -107,6 +110,15 @@ type MockClusterAdmin struct {
 	DescribeQuotasCalls    []DescribeQuotasCall
 	AlterQuotasErr         error
 	AlterClientQuotasCalls []AlterQuotaCall
+
+	// SCRAM credential fields.
+	MockScramUsers        []*sarama.DescribeUserScramCredentialsResult
+	DescribeScramErr      error
+	DescribeScramCalls    [][]string
+	MockScramAlterResults []*sarama.AlterUserScramCredentialsResult
+	AlterScramErr         error
+	UpsertScramCalls      [][]sarama.AlterUserScramCredentialsUpsert
+	DeleteScramCalls      [][]sarama.AlterUserScramCredentialsDelete
 }
 
 // DescribeQuotasCall captures the arguments of a DescribeClientQuotas call.
@@ -215,6 +227,30 @@ func (m *MockClusterAdmin) AlterClientQuotas(entity []sarama.QuotaEntityComponen
 	return m.AlterQuotasErr
 }
 
+func (m *MockClusterAdmin) DescribeUserScramCredentials(users []string) ([]*sarama.DescribeUserScramCredentialsResult, error) {
+	m.DescribeScramCalls = append(m.DescribeScramCalls, users)
+	if m.DescribeScramErr != nil {
+		return nil, m.DescribeScramErr
+	}
+	return m.MockScramUsers, nil
+}
+
+func (m *MockClusterAdmin) UpsertUserScramCredentials(upsert []sarama.AlterUserScramCredentialsUpsert) ([]*sarama.AlterUserScramCredentialsResult, error) {
+	m.UpsertScramCalls = append(m.UpsertScramCalls, upsert)
+	if m.AlterScramErr != nil {
+		return nil, m.AlterScramErr
+	}
+	return m.MockScramAlterResults, nil
+}
+
+func (m *MockClusterAdmin) DeleteUserScramCredentials(delete []sarama.AlterUserScramCredentialsDelete) ([]*sarama.AlterUserScramCredentialsResult, error) {
+	m.DeleteScramCalls = append(m.DeleteScramCalls, delete)
+	if m.AlterScramErr != nil {
+		return nil, m.AlterScramErr
+	}
+	return m.MockScramAlterResults, nil
+}
+
 func (m *MockClusterAdmin) DescribeCluster() ([]*sarama.Broker, int32, error) {
 	if m.ShouldFailDescribeCluster {
 		return nil, 0, errors.New("mock describe cluster failed")
@@ -226,6 +262,9 @@ func (m *MockClusterAdmin) DescribeConfig(resource sarama.ConfigResource) ([]sar
 	if m.ShouldFailDescribeConfig {
 		return nil, errors.New("mock describe config failed")
 	}
+	if m.MockConfigsByName != nil {
+		return m.MockConfigsByName[resource.Name], nil
+	}
 	return m.MockConfigEntries, nil
 }
 
@@ -9,13 +9,12 @@ import (
 
 	"github.com/Benny93/kafui/pkg/ui/shared"
 	"github.com/IBM/sarama"
+	"github.com/birdayz/kaf/pkg/config"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
 var (
-	once              sync.Once
-	tokenProv         *tokenProvider
 	refreshBuffer     time.Duration = time.Second * 20
 	tokenFetchTimeout time.Duration = time.Second * 10
 )
@@ -46,65 +45,64 @@ type tokenProvider struct {
 	refreshTok string
 }
 
-// This is a singleton
-func newTokenProvider() *tokenProvider {
-	once.Do(func() {
-		cluster := currentCluster
-		deviceURL := deviceAuthURLFor(cluster.Name)
+// newTokenProvider builds the OAUTHBEARER token provider for a cluster. The
+// datasource session keeps one per active cluster (see oauthTokenProvider).
+func newTokenProvider(cluster *config.Cluster) *tokenProvider {
+	var tokenProv *tokenProvider
+	deviceURL := deviceAuthURLFor(cluster.Name)
 
-		//token from static value, device-code grant, or client-credentials
-		switch {
-		case len(cluster.SASL.Token) != 0:
-			tokenProv = &tokenProvider{
-				oauthClientCFG: &clientcredentials.Config{},
-				staticToken:    true,
-				currentToken:   cluster.SASL.Token,
-			}
-		case isDeviceFlow(cluster.SASL, deviceURL):
-			tokenProv = &tokenProvider{
-				deviceMode: true,
-				cluster:    cluster.Name,
-				deviceCfg: &deviceAuthConfig{
-					DeviceAuthURL: deviceURL,
-					TokenURL:      cluster.SASL.TokenURL,
-					ClientID:      cluster.SASL.ClientID,
-					Scopes:        cluster.SASL.Scopes,
-				},
-			}
-			// Seed from the cache written by PrepareOAuthDeviceFlow.
-			if ct, _ := loadCachedToken(cluster.Name); ct != nil {
-				tokenProv.currentToken = ct.AccessToken
-				tokenProv.expiresAt = ct.Expiry
-				tokenProv.replaceAt = ct.Expiry.Add(-refreshBuffer)
-				tokenProv.refreshTok = ct.RefreshToken
-			}
-		default:
-			tokenProv = &tokenProvider{
-				oauthClientCFG: &clientcredentials.Config{
-					ClientID:     cluster.SASL.ClientID,
-					ClientSecret: cluster.SASL.ClientSecret,
-					TokenURL:     cluster.SASL.TokenURL,
-				},
-				staticToken: false,
-			}
+	//token from static value, device-code grant, or client-credentials
+	switch {
+	case len(cluster.SASL.Token) != 0:
+		tokenProv = &tokenProvider{
+			oauthClientCFG: &clientcredentials.Config{},
+			staticToken:    true,
+			currentToken:   cluster.SASL.Token,
+		}
+	case isDeviceFlow(cluster.SASL, deviceURL):
+		tokenProv = &tokenProvider{
+			deviceMode: true,
+			cluster:    cluster.Name,
+			deviceCfg: &deviceAuthConfig{
+				DeviceAuthURL: deviceURL,
+				TokenURL:      cluster.SASL.TokenURL,
+				ClientID:      cluster.SASL.ClientID,
+				Scopes:        cluster.SASL.Scopes,
+			},
 		}
-		if !tokenProv.staticToken && !tokenProv.deviceMode {
-			// create context with timeout
-			ctx := context.Background()
-			httpClient := &http.Client{Timeout: tokenFetchTimeout}
-			ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
-			tokenProv.ctx = ctx
+		// Seed from the cache written by PrepareOAuthDeviceFlow.
+		if ct, _ := loadCachedToken(cluster.Name); ct != nil {
+			tokenProv.currentToken = ct.AccessToken
+			tokenProv.expiresAt = ct.Expiry
+			tokenProv.replaceAt = ct.Expiry.Add(-refreshBuffer)
+			tokenProv.refreshTok = ct.RefreshToken
+		}
+	default:
+		tokenProv = &tokenProvider{
+			oauthClientCFG: &clientcredentials.Config{
+				ClientID:     cluster.SASL.ClientID,
+				ClientSecret: cluster.SASL.ClientSecret,
+				TokenURL:     cluster.SASL.TokenURL,
+			},
+			staticToken: false,
+		}
+	}
+	if !tokenProv.staticToken && !tokenProv.deviceMode {
+		// create context with timeout
+		ctx := context.Background()
+		httpClient := &http.Client{Timeout: tokenFetchTimeout}
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+		tokenProv.ctx = ctx
 
-			// get first token
-			firstToken, err := tokenProv.oauthClientCFG.Token(ctx)
-			if err != nil {
-				panic(fmt.Errorf("Could not fetch OAUTH token: " + err.Error()))
-			}
-			tokenProv.currentToken = firstToken.AccessToken
-			tokenProv.expiresAt = firstToken.Expiry
-			tokenProv.replaceAt = firstToken.Expiry.Add(-refreshBuffer)
+		// get first token
+		firstToken, err := tokenProv.oauthClientCFG.Token(ctx)
+		if err != nil {
+			panic(fmt.Errorf("Could not fetch OAUTH token: " + err.Error()))
 		}
-	})
+		tokenProv.currentToken = firstToken.AccessToken
+		tokenProv.expiresAt = firstToken.Expiry
+		tokenProv.replaceAt = firstToken.Expiry.Add(-refreshBuffer)
+	}
 	return tokenProv
 }
 
@@ -14,12 +14,6 @@ import (
 	"golang.org/x/oauth2"
 )
 
-// resetTokenProvider resets the singleton for testing
-func resetTokenProvider() {
-	once = sync.Once{}
-	tokenProv = nil
-}
-
 // mockOAuthServer creates a mock OAuth server for testing
 func mockOAuthServer(t *testing.T, responseToken string, statusCode int) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -44,23 +38,15 @@ func mockOAuthServer(t *testing.T, responseToken string, statusCode int) *httpte
 }
 
 func TestTokenProvider_Token(t *testing.T) {
-	originalCluster := currentCluster
-	defer func() {
-		currentCluster = originalCluster
-		resetTokenProvider()
-	}()
-
 	t.Run("valid_static_token", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Set up static token configuration
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				Token: "static-test-token-123",
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		assert.True(t, tp.staticToken)
 		assert.Equal(t, "static-test-token-123", tp.currentToken)
@@ -73,14 +59,12 @@ func TestTokenProvider_Token(t *testing.T) {
 	})
 	
 	t.Run("valid_dynamic_token", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Create mock OAuth server
 		server := mockOAuthServer(t, "dynamic-test-token-456", http.StatusOK)
 		defer server.Close()
 		
 		// Set up dynamic token configuration
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
@@ -88,7 +72,7 @@ func TestTokenProvider_Token(t *testing.T) {
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		assert.False(t, tp.staticToken)
 		assert.Equal(t, "dynamic-test-token-456", tp.currentToken)
@@ -101,14 +85,12 @@ func TestTokenProvider_Token(t *testing.T) {
 	})
 	
 	t.Run("empty_static_token", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Set up empty static token configuration - this will actually trigger dynamic token flow
 		// because len(cluster.SASL.Token) == 0, so let's provide a mock server
 		server := mockOAuthServer(t, "empty-fallback-token", http.StatusOK)
 		defer server.Close()
 		
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				Token:        "",
 				ClientID:     "test-client-id",
@@ -117,7 +99,7 @@ func TestTokenProvider_Token(t *testing.T) {
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		assert.False(t, tp.staticToken) // Will be false because empty token triggers dynamic flow
 		assert.Equal(t, "empty-fallback-token", tp.currentToken)
@@ -129,14 +111,12 @@ func TestTokenProvider_Token(t *testing.T) {
 	})
 	
 	t.Run("oauth_server_error", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Create mock OAuth server that returns error
 		server := mockOAuthServer(t, "", http.StatusUnauthorized)
 		defer server.Close()
 		
 		// Set up dynamic token configuration
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "invalid-client-id",
 				ClientSecret: "invalid-client-secret",
@@ -144,29 +124,21 @@ func TestTokenProvider_Token(t *testing.T) {
 			},
 		}
 		
-		// This should panic during newTokenProvider() due to initial token fetch failure
+		// This should panic during newTokenProvider(cluster) due to initial token fetch failure
 		assert.Panics(t, func() {
-			newTokenProvider()
+			newTokenProvider(cluster)
 		})
 	})
 }
 
 func TestTokenProvider_RefreshToken(t *testing.T) {
-	originalCluster := currentCluster
-	defer func() {
-		currentCluster = originalCluster
-		resetTokenProvider()
-	}()
-	
 	t.Run("valid_refresh", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Create mock OAuth server
 		server := mockOAuthServer(t, "refreshed-token-789", http.StatusOK)
 		defer server.Close()
 		
 		// Set up dynamic token configuration
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
@@ -174,7 +146,7 @@ func TestTokenProvider_RefreshToken(t *testing.T) {
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		
 		// Force token to be expired by setting replaceAt to past
@@ -188,14 +160,12 @@ func TestTokenProvider_RefreshToken(t *testing.T) {
 	})
 	
 	t.Run("refresh_with_server_error", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Create mock OAuth server that returns error
 		server := mockOAuthServer(t, "", http.StatusInternalServerError)
 		defer server.Close()
 		
 		// Set up dynamic token configuration with initial success
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
@@ -205,19 +175,17 @@ func TestTokenProvider_RefreshToken(t *testing.T) {
 		
 		// This will panic during initial token fetch
 		assert.Panics(t, func() {
-			newTokenProvider()
+			newTokenProvider(cluster)
 		})
 	})
 	
 	t.Run("concurrent_refresh", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Create mock OAuth server
 		server := mockOAuthServer(t, "concurrent-token", http.StatusOK)
 		defer server.Close()
 		
 		// Set up dynamic token configuration
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
@@ -225,7 +193,7 @@ func TestTokenProvider_RefreshToken(t *testing.T) {
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		
 		// Force token to be expired
@@ -254,14 +222,12 @@ func TestTokenProvider_RefreshToken(t *testing.T) {
 	})
 	
 	t.Run("no_refresh_needed", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Create mock OAuth server
 		server := mockOAuthServer(t, "no-refresh-needed", http.StatusOK)
 		defer server.Close()
 		
 		// Set up dynamic token configuration
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
@@ -269,7 +235,7 @@ func TestTokenProvider_RefreshToken(t *testing.T) {
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		
 		originalToken := tp.currentToken
@@ -285,48 +251,47 @@ func TestTokenProvider_RefreshToken(t *testing.T) {
 	})
 }
 
-func TestTokenProvider_Singleton(t *testing.T) {
-	originalCluster := currentCluster
-	defer func() {
-		currentCluster = originalCluster
-		resetTokenProvider()
-	}()
-	
-	resetTokenProvider()
-	
+func TestTokenProvider_PerSession(t *testing.T) {
 	// Set up static token configuration
-	currentCluster = &config.Cluster{
+	s := newSession()
+	s.current = &config.Cluster{
 		SASL: &config.SASL{
-			Token: "singleton-test-token",
+			Token: "session-test-token",
 		},
 	}
-	
-	// Create multiple instances - should return the same singleton
-	tp1 := newTokenProvider()
-	tp2 := newTokenProvider()
-	
-	assert.Same(t, tp1, tp2, "newTokenProvider should return the same singleton instance")
-	assert.Equal(t, "singleton-test-token", tp1.currentToken)
-	assert.Equal(t, "singleton-test-token", tp2.currentToken)
+
+	// Repeated lookups reuse the session's provider...
+	tp1 := s.oauthTokenProvider()
+	tp2 := s.oauthTokenProvider()
+	assert.Same(t, tp1, tp2, "oauthTokenProvider should cache the provider per session")
+	assert.Equal(t, "session-test-token", tp1.currentToken)
+
+	// ...until a context switch invalidates it.
+	s.current = &config.Cluster{
+		SASL: &config.SASL{
+			Token: "other-cluster-token",
+		},
+	}
+	s.invalidate()
+	tp3 := s.oauthTokenProvider()
+	assert.NotSame(t, tp1, tp3)
+	assert.Equal(t, "other-cluster-token", tp3.currentToken)
+
+	// Another session never sees this one's provider.
+	other := newSession()
+	other.current = &config.Cluster{SASL: &config.SASL{Token: "independent"}}
+	assert.Equal(t, "independent", other.oauthTokenProvider().currentToken)
 }
 
 func TestTokenProvider_Interface(t *testing.T) {
-	originalCluster := currentCluster
-	defer func() {
-		currentCluster = originalCluster
-		resetTokenProvider()
-	}()
-	
-	resetTokenProvider()
-	
 	// Set up static token configuration
-	currentCluster = &config.Cluster{
+	cluster := &config.Cluster{
 		SASL: &config.SASL{
 			Token: "interface-test-token",
 		},
 	}
 	
-	tp := newTokenProvider()
+	tp := newTokenProvider(cluster)
 	
 	// Verify it implements sarama.AccessTokenProvider interface
 	var _ sarama.AccessTokenProvider = tp
@@ -339,15 +304,7 @@ func TestTokenProvider_Interface(t *testing.T) {
 }
 
 func TestTokenProvider_TokenExpiration(t *testing.T) {
-	originalCluster := currentCluster
-	defer func() {
-		currentCluster = originalCluster
-		resetTokenProvider()
-	}()
-	
 	t.Run("token_refresh_on_expiration", func(t *testing.T) {
-		resetTokenProvider()
-		
 		// Create mock OAuth server that returns different tokens on subsequent calls
 		callCount := 0
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -372,7 +329,7 @@ func TestTokenProvider_TokenExpiration(t *testing.T) {
 		defer server.Close()
 		
 		// Set up dynamic token configuration
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "test-client-id",
 				ClientSecret: "test-client-secret",
@@ -380,7 +337,7 @@ func TestTokenProvider_TokenExpiration(t *testing.T) {
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		assert.Equal(t, "initial-token", tp.currentToken)
 		
@@ -396,19 +353,11 @@ func TestTokenProvider_TokenExpiration(t *testing.T) {
 }
 
 func TestTokenProvider_Configuration(t *testing.T) {
-	originalCluster := currentCluster
-	defer func() {
-		currentCluster = originalCluster
-		resetTokenProvider()
-	}()
-	
 	t.Run("oauth_configuration_setup", func(t *testing.T) {
-		resetTokenProvider()
-		
 		server := mockOAuthServer(t, "config-test-token", http.StatusOK)
 		defer server.Close()
 		
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "test-client-123",
 				ClientSecret: "test-secret-456",
@@ -416,7 +365,7 @@ func TestTokenProvider_Configuration(t *testing.T) {
 			},
 		}
 		
-		tp := newTokenProvider()
+		tp := newTokenProvider(cluster)
 		require.NotNil(t, tp)
 		assert.False(t, tp.staticToken)
 		assert.Equal(t, "test-client-123", tp.oauthClientCFG.ClientID)
@@ -437,29 +386,19 @@ func TestTokenProvider_GlobalVariables(t *testing.T) {
 }
 
 func TestTokenProvider_EdgeCases(t *testing.T) {
-	originalCluster := currentCluster
-	defer func() {
-		currentCluster = originalCluster
-		resetTokenProvider()
-	}()
-	
 	t.Run("nil_cluster_sasl", func(t *testing.T) {
-		resetTokenProvider()
-		
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: nil,
 		}
 		
 		// This should panic or handle gracefully
 		assert.Panics(t, func() {
-			newTokenProvider()
+			newTokenProvider(cluster)
 		})
 	})
 	
 	t.Run("empty_oauth_config", func(t *testing.T) {
-		resetTokenProvider()
-		
-		currentCluster = &config.Cluster{
+		cluster := &config.Cluster{
 			SASL: &config.SASL{
 				ClientID:     "",
 				ClientSecret: "",
@@ -469,7 +408,7 @@ func TestTokenProvider_EdgeCases(t *testing.T) {
 		
 		// This should panic during initial token fetch
 		assert.Panics(t, func() {
-			newTokenProvider()
+			newTokenProvider(cluster)
 		})
 	})
 }
\ No newline at end of file
@@ -3,6 +3,8 @@ package kafds
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/IBM/sarama"
@@ -15,6 +17,9 @@ import (
 type offsetResetter interface {
 	GetOffset(topic string, partition int32, time int64) (int64, error)
 	Partitions(topic string) ([]int32, error)
+	// Committed returns the group's committed offset per partition of the
+	// topic; partitions without a commit are absent.
+	Committed(groupID, topic string, partitions []int32) (map[int32]int64, error)
 	// Commit sets the group's committed offset for each partition of the topic.
 	Commit(groupID, topic string, offsets map[int32]int64) error
 	Close() error
@@ -22,12 +27,8 @@ type offsetResetter interface {
 
 // newOffsetResetter builds a real resetter backed by a Sarama client and an
 // OffsetManager joined as the group. Replaceable in tests.
-var newOffsetResetter = func(groupID string) (offsetResetter, error) {
-	cfg, err := getConfig()
-	if err != nil {
-		return nil, err
-	}
-	client, err := getClientFromConfig(cfg)
+var newOffsetResetter = func(kp KafkaDataSourceKaf, groupID string) (offsetResetter, error) {
+	client, err := kp.getClient()
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +53,28 @@ func (r *saramaOffsetResetter) Partitions(topic string) ([]int32, error) {
 	return r.client.Partitions(topic)
 }
 
+func (r *saramaOffsetResetter) Committed(groupID, topic string, partitions []int32) (map[int32]int64, error) {
+	coordinator, err := r.client.Coordinator(groupID)
+	if err != nil {
+		return nil, fmt.Errorf("finding coordinator for group %q: %w", groupID, err)
+	}
+	req := &sarama.OffsetFetchRequest{Version: 1, ConsumerGroup: groupID}
+	for _, p := range partitions {
+		req.AddPartition(topic, p)
+	}
+	resp, err := coordinator.FetchOffset(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching offsets for group %q: %w", groupID, err)
+	}
+	out := make(map[int32]int64, len(partitions))
+	for _, p := range partitions {
+		if block := resp.GetBlock(topic, p); block != nil && block.Err == sarama.ErrNoError && block.Offset >= 0 {
+			out[p] = block.Offset
+		}
+	}
+	return out, nil
+}
+
 func (r *saramaOffsetResetter) Commit(groupID, topic string, offsets map[int32]int64) error {
 	for p, off := range offsets {
 		pom, err := r.om.ManagePartition(topic, p)
@@ -77,7 +100,7 @@ func (kp KafkaDataSourceKaf) ResetConsumerGroupOffsets(ctx context.Context, req
 	}
 
 	// Precondition: the group must exist and be inactive (Empty or Dead).
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
@@ -104,38 +127,148 @@ func (kp KafkaDataSourceKaf) ResetConsumerGroupOffsets(ctx context.Context, req
 		return api.GroupNotEmptyError{GroupID: req.GroupID, State: state}
 	}
 
-	resetter, err := newOffsetResetter(req.GroupID)
+	resetter, err := newOffsetResetter(kp, req.GroupID)
 	if err != nil {
 		return err
 	}
 	defer resetter.Close()
 
-	// Resolve target partitions.
+	offsets := req.Planned
+	if offsets != nil {
+		if err := checkPlannedOffsets(req, resetter); err != nil {
+			return err
+		}
+	} else {
+		plan, err := planOffsetReset(ctx, req, resetter, time.Now())
+		if err != nil {
+			return err
+		}
+		offsets = make(map[int32]int64, len(plan))
+		for _, p := range plan {
+			offsets[p.Partition] = p.Target
+		}
+	}
+
+	kp.sess().dropGroupDetail(req.GroupID)
+	if err := resetter.Commit(req.GroupID, req.Topic, offsets); err != nil {
+		return fmt.Errorf("committing reset offsets for group %q: %w", req.GroupID, err)
+	}
+	return nil
+}
+
+// PlanConsumerGroupOffsetReset implements api.KafkaDataSource. It resolves the
+// same targets as ResetConsumerGroupOffsets but never commits, so it works on
+// active groups too.
+func (kp KafkaDataSourceKaf) PlanConsumerGroupOffsetReset(ctx context.Context, req api.OffsetResetRequest) ([]api.PartitionResetPlan, error) {
+	if err := validateOffsetResetRequest(req); err != nil {
+		return nil, err
+	}
+	resetter, err := newOffsetResetter(kp, req.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer resetter.Close()
+	return planOffsetReset(ctx, req, resetter, time.Now())
+}
+
+// planOffsetReset resolves the target partitions, their committed offsets and
+// offset ranges, and the target offset of each. now anchors by-duration resets.
+func planOffsetReset(ctx context.Context, req api.OffsetResetRequest, r offsetResetter, now time.Time) ([]api.PartitionResetPlan, error) {
 	partitions := req.Partitions
 	if len(partitions) == 0 {
-		partitions, err = resetter.Partitions(req.Topic)
+		var err error
+		partitions, err = r.Partitions(req.Topic)
 		if err != nil {
-			return fmt.Errorf("listing partitions for topic %q: %w", req.Topic, err)
+			return nil, fmt.Errorf("listing partitions for topic %q: %w", req.Topic, err)
+		}
+	}
+	committed, err := r.Committed(req.GroupID, req.Topic, partitions)
+	if err != nil {
+		return nil, err
+	}
+	if req.Mode == api.OffsetResetDuration {
+		ts := now.Add(-req.Duration)
+		req.Timestamp = &ts
+	}
+	var source map[int32]int64
+	if req.Mode == api.OffsetResetFromGroup {
+		if source, err = r.Committed(req.SourceGroupID, req.Topic, partitions); err != nil {
+			return nil, err
+		}
+		if len(source) == 0 {
+			return nil, api.InvalidOffsetResetError{Reason: fmt.Sprintf("group %q has no committed offsets for topic %q", req.SourceGroupID, req.Topic)}
 		}
+		req.PartitionOffsets = source
 	}
 
-	offsets := make(map[int32]int64, len(partitions))
+	plan := make([]api.PartitionResetPlan, 0, len(partitions))
 	for _, p := range partitions {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
-		target, err := resolveResetOffset(req, resetter, p)
-		if err != nil {
-			return err
+		if _, ok := source[p]; req.Mode == api.OffsetResetFromGroup && !ok {
+			continue
+		}
+		entry := api.PartitionResetPlan{Topic: req.Topic, Partition: p}
+		if c, ok := committed[p]; ok {
+			entry.Current = &c
+		}
+		if entry.LogStart, err = r.GetOffset(req.Topic, p, sarama.OffsetOldest); err != nil {
+			return nil, err
+		}
+		if entry.LogEnd, err = r.GetOffset(req.Topic, p, sarama.OffsetNewest); err != nil {
+			return nil, err
 		}
-		offsets[p] = target
+		if entry.Target, err = resolveResetOffset(req, r, p, entry.Current); err != nil {
+			return nil, err
+		}
+		plan = append(plan, entry)
 	}
+	return plan, nil
+}
 
-	invalidateGroupCache(req.GroupID)
-	if err := resetter.Commit(req.GroupID, req.Topic, offsets); err != nil {
-		return fmt.Errorf("committing reset offsets for group %q: %w", req.GroupID, err)
+// checkPlannedOffsets rejects planned targets that are not commit-ready: a
+// partition outside req.Partitions (or the topic when empty), or an offset
+// outside the partition's current [log start, log end] range, e.g. because
+// retention moved past it since the preview.
+func checkPlannedOffsets(req api.OffsetResetRequest, r offsetResetter) error {
+	partitions := req.Partitions
+	if len(partitions) == 0 {
+		var err error
+		if partitions, err = r.Partitions(req.Topic); err != nil {
+			return fmt.Errorf("listing partitions for topic %q: %w", req.Topic, err)
+		}
+	}
+	known := make(map[int32]bool, len(partitions))
+	for _, p := range partitions {
+		known[p] = true
+	}
+	planned := make([]int32, 0, len(req.Planned))
+	for p := range req.Planned {
+		planned = append(planned, p)
+	}
+	sort.Slice(planned, func(i, j int) bool { return planned[i] < planned[j] })
+	for _, p := range planned {
+		off := req.Planned[p]
+		if !known[p] {
+			return api.InvalidOffsetResetError{Reason: fmt.Sprintf("planned partition %d is not part of the reset of %q", p, req.Topic)}
+		}
+		if off < 0 {
+			return api.InvalidOffsetResetError{Reason: fmt.Sprintf("planned offset %d for partition %d is negative", off, p)}
+		}
+		oldest, err := r.GetOffset(req.Topic, p, sarama.OffsetOldest)
+		if err != nil {
+			return err
+		}
+		newest, err := r.GetOffset(req.Topic, p, sarama.OffsetNewest)
+		if err != nil {
+			return err
+		}
+		if off < oldest || off > newest {
+			return api.InvalidOffsetResetError{Reason: fmt.Sprintf("planned offset %d for partition %d is outside its range [%d, %d]; plan the reset again", off, p, oldest, newest)}
+		}
 	}
 	return nil
 }
@@ -152,6 +285,22 @@ func validateOffsetResetRequest(req api.OffsetResetRequest) error {
 		if len(req.PartitionOffsets) == 0 {
 			return api.InvalidOffsetResetError{Reason: "explicit mode requires per-partition offsets"}
 		}
+	case api.OffsetResetShiftBy:
+		if req.ShiftBy == 0 {
+			return api.InvalidOffsetResetError{Reason: "shift-by mode requires a non-zero shift"}
+		}
+	case api.OffsetResetDuration:
+		if req.Duration <= 0 {
+			return api.InvalidOffsetResetError{Reason: "by-duration mode requires a positive duration"}
+		}
+	case api.OffsetResetCurrent:
+	case api.OffsetResetFromGroup:
+		if req.SourceGroupID == "" {
+			return api.InvalidOffsetResetError{Reason: "copy-from-group mode requires a source group"}
+		}
+		if req.SourceGroupID == req.GroupID {
+			return api.InvalidOffsetResetError{Reason: "source group must differ from the group being reset"}
+		}
 	default:
 		return api.InvalidOffsetResetError{Reason: fmt.Sprintf("unrecognized reset mode %q", req.Mode)}
 	}
@@ -159,14 +308,17 @@ func validateOffsetResetRequest(req api.OffsetResetRequest) error {
 }
 
 // resolveResetOffset computes the target offset for a single partition based on
-// the reset mode (CG-7 earliest/latest, CG-8 timestamp/explicit with clamping).
-func resolveResetOffset(req api.OffsetResetRequest, r offsetResetter, partition int32) (int64, error) {
+// the reset mode (CG-7 earliest/latest, CG-8 timestamp/explicit with clamping,
+// shift-by/to-current relative to current, with clamping). by-duration requests
+// arrive with Timestamp set to now-Duration and copy-from-group requests with
+// PartitionOffsets set to the source group's committed offsets.
+func resolveResetOffset(req api.OffsetResetRequest, r offsetResetter, partition int32, current *int64) (int64, error) {
 	switch req.Mode {
 	case api.OffsetResetEarliest:
 		return r.GetOffset(req.Topic, partition, sarama.OffsetOldest)
 	case api.OffsetResetLatest:
 		return r.GetOffset(req.Topic, partition, sarama.OffsetNewest)
-	case api.OffsetResetTimestamp:
+	case api.OffsetResetTimestamp, api.OffsetResetDuration:
 		off, err := r.GetOffset(req.Topic, partition, req.Timestamp.UnixMilli())
 		if err != nil {
 			return 0, err
@@ -179,6 +331,25 @@ func resolveResetOffset(req api.OffsetResetRequest, r offsetResetter, partition
 	case api.OffsetResetExplicit:
 		requested := req.PartitionOffsets[partition] // missing => 0
 		return clampOffset(req.Topic, partition, requested, r)
+	case api.OffsetResetShiftBy:
+		base := int64(0)
+		if current != nil {
+			base = *current
+		} else {
+			end, err := r.GetOffset(req.Topic, partition, sarama.OffsetNewest)
+			if err != nil {
+				return 0, err
+			}
+			base = end
+		}
+		return clampOffset(req.Topic, partition, base+req.ShiftBy, r)
+	case api.OffsetResetCurrent:
+		if current == nil {
+			return r.GetOffset(req.Topic, partition, sarama.OffsetNewest)
+		}
+		return clampOffset(req.Topic, partition, *current, r)
+	case api.OffsetResetFromGroup:
+		return clampOffset(req.Topic, partition, req.PartitionOffsets[partition], r)
 	default:
 		return 0, api.InvalidOffsetResetError{Reason: fmt.Sprintf("unrecognized reset mode %q", req.Mode)}
 	}
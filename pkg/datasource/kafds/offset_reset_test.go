@@ -17,7 +17,8 @@ type fakeResetter struct {
 	oldest     map[string]map[int32]int64
 	tsOffset   map[string]map[int32]int64 // returned for timestamp lookups (t > 0)
 	partitions map[string][]int32
-	committed  map[int32]int64 // captured on Commit
+	current    map[string]map[int32]int64 // returned by Committed
+	committed  map[int32]int64            // captured on Commit
 	commitErr  error
 }
 
@@ -41,6 +42,16 @@ func (f *fakeResetter) Partitions(topic string) ([]int32, error) {
 	return f.partitions[topic], nil
 }
 
+func (f *fakeResetter) Committed(groupID, topic string, partitions []int32) (map[int32]int64, error) {
+	out := map[int32]int64{}
+	for _, p := range partitions {
+		if v, ok := f.current[topic][p]; ok {
+			out[p] = v
+		}
+	}
+	return out, nil
+}
+
 func (f *fakeResetter) Commit(groupID, topic string, offsets map[int32]int64) error {
 	if f.commitErr != nil {
 		return f.commitErr
@@ -53,7 +64,7 @@ func (f *fakeResetter) Close() error { return nil }
 
 func installFakeResetter(r offsetResetter) func() {
 	orig := newOffsetResetter
-	newOffsetResetter = func(groupID string) (offsetResetter, error) { return r, nil }
+	newOffsetResetter = func(_ KafkaDataSourceKaf, groupID string) (offsetResetter, error) { return r, nil }
 	return func() { newOffsetResetter = orig }
 }
 
@@ -70,8 +81,7 @@ func ptrTime(t time.Time) *time.Time { return &t }
 // --- CG-7: validation ---
 
 func TestResetOffsets_Validation(t *testing.T) {
-	restore := installMockAdmin(emptyGroupAdmin("g1"))
-	defer restore()
+	ds := mockAdminDS(emptyGroupAdmin("g1"))
 	restoreR := installFakeResetter(&fakeResetter{})
 	defer restoreR()
 
@@ -82,10 +92,14 @@ func TestResetOffsets_Validation(t *testing.T) {
 		{"unknown mode", api.OffsetResetRequest{GroupID: "g1", Topic: "t1", Mode: "bogus"}},
 		{"timestamp without ts", api.OffsetResetRequest{GroupID: "g1", Topic: "t1", Mode: api.OffsetResetTimestamp}},
 		{"explicit without offsets", api.OffsetResetRequest{GroupID: "g1", Topic: "t1", Mode: api.OffsetResetExplicit}},
+		{"shift-by zero", api.OffsetResetRequest{GroupID: "g1", Topic: "t1", Mode: api.OffsetResetShiftBy}},
+		{"by-duration without duration", api.OffsetResetRequest{GroupID: "g1", Topic: "t1", Mode: api.OffsetResetDuration}},
+		{"copy-from-group without source", api.OffsetResetRequest{GroupID: "g1", Topic: "t1", Mode: api.OffsetResetFromGroup}},
+		{"copy-from-group from itself", api.OffsetResetRequest{GroupID: "g1", Topic: "t1", Mode: api.OffsetResetFromGroup, SourceGroupID: "g1"}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := brokerDS().ResetConsumerGroupOffsets(context.Background(), tt.req)
+			err := ds.ResetConsumerGroupOffsets(context.Background(), tt.req)
 			var ivr api.InvalidOffsetResetError
 			assert.True(t, errors.As(err, &ivr), "want InvalidOffsetResetError, got %v", err)
 		})
@@ -99,12 +113,11 @@ func TestResetOffsets_RejectsActiveGroup(t *testing.T) {
 		MockConsumerGroups:    map[string]string{"g1": "consumer"},
 		MockGroupDescriptions: []*sarama.GroupDescription{{GroupId: "g1", State: "Stable", ProtocolType: "consumer"}},
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 	restoreR := installFakeResetter(&fakeResetter{})
 	defer restoreR()
 
-	err := brokerDS().ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+	err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
 		GroupID: "g1", Topic: "t1", Mode: api.OffsetResetEarliest,
 	})
 	var ne api.GroupNotEmptyError
@@ -113,12 +126,11 @@ func TestResetOffsets_RejectsActiveGroup(t *testing.T) {
 }
 
 func TestResetOffsets_GroupNotFound(t *testing.T) {
-	restore := installMockAdmin(&MockClusterAdmin{MockConsumerGroups: map[string]string{}})
-	defer restore()
+	ds := mockAdminDS(&MockClusterAdmin{MockConsumerGroups: map[string]string{}})
 	restoreR := installFakeResetter(&fakeResetter{})
 	defer restoreR()
 
-	err := brokerDS().ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+	err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
 		GroupID: "missing", Topic: "t1", Mode: api.OffsetResetEarliest,
 	})
 	var nf api.GroupNotFoundError
@@ -153,8 +165,7 @@ func TestResetOffsets_EarliestLatestAndScope(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			restore := installMockAdmin(emptyGroupAdmin("g1"))
-			defer restore()
+			ds := mockAdminDS(emptyGroupAdmin("g1"))
 			r := &fakeResetter{
 				oldest:     map[string]map[int32]int64{"t1": {0: 10, 1: 20}},
 				newest:     map[string]map[int32]int64{"t1": {0: 100, 1: 200}},
@@ -163,7 +174,7 @@ func TestResetOffsets_EarliestLatestAndScope(t *testing.T) {
 			restoreR := installFakeResetter(r)
 			defer restoreR()
 
-			err := brokerDS().ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+			err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
 				GroupID: "g1", Topic: "t1", Mode: tt.mode, Partitions: tt.partitions,
 			})
 			assert.NoError(t, err)
@@ -175,8 +186,7 @@ func TestResetOffsets_EarliestLatestAndScope(t *testing.T) {
 // --- CG-8: timestamp + explicit clamping ---
 
 func TestResetOffsets_TimestampMode(t *testing.T) {
-	restore := installMockAdmin(emptyGroupAdmin("g1"))
-	defer restore()
+	ds := mockAdminDS(emptyGroupAdmin("g1"))
 	r := &fakeResetter{
 		newest:     map[string]map[int32]int64{"t1": {0: 500, 1: 900}},
 		partitions: map[string][]int32{"t1": {0, 1}},
@@ -187,7 +197,7 @@ func TestResetOffsets_TimestampMode(t *testing.T) {
 	defer restoreR()
 
 	ts := time.Now()
-	err := brokerDS().ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+	err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
 		GroupID: "g1", Topic: "t1", Mode: api.OffsetResetTimestamp, Timestamp: ptrTime(ts),
 	})
 	assert.NoError(t, err)
@@ -196,8 +206,7 @@ func TestResetOffsets_TimestampMode(t *testing.T) {
 }
 
 func TestResetOffsets_ExplicitClamping(t *testing.T) {
-	restore := installMockAdmin(emptyGroupAdmin("g1"))
-	defer restore()
+	ds := mockAdminDS(emptyGroupAdmin("g1"))
 	r := &fakeResetter{
 		oldest: map[string]map[int32]int64{"t1": {0: 10, 1: 10, 2: 10}},
 		newest: map[string]map[int32]int64{"t1": {0: 100, 1: 100, 2: 100}},
@@ -205,7 +214,7 @@ func TestResetOffsets_ExplicitClamping(t *testing.T) {
 	restoreR := installFakeResetter(r)
 	defer restoreR()
 
-	err := brokerDS().ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+	err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
 		GroupID:    "g1",
 		Topic:      "t1",
 		Mode:       api.OffsetResetExplicit,
@@ -221,3 +230,172 @@ func TestResetOffsets_ExplicitClamping(t *testing.T) {
 	assert.Equal(t, int64(100), r.committed[1])
 	assert.Equal(t, int64(10), r.committed[2])
 }
+
+// --- shift-by / by-duration + dry-run plan ---
+
+func TestResetOffsets_ShiftByClampsAndFallsBackToEnd(t *testing.T) {
+	ds := mockAdminDS(emptyGroupAdmin("g1"))
+	r := &fakeResetter{
+		oldest:     map[string]map[int32]int64{"t1": {0: 10, 1: 10, 2: 10}},
+		newest:     map[string]map[int32]int64{"t1": {0: 100, 1: 100, 2: 100}},
+		partitions: map[string][]int32{"t1": {0, 1, 2}},
+		current:    map[string]map[int32]int64{"t1": {0: 50, 1: 15}},
+	}
+	restoreR := installFakeResetter(r)
+	defer restoreR()
+
+	err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+		GroupID: "g1", Topic: "t1", Mode: api.OffsetResetShiftBy, ShiftBy: -20,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]int64{
+		0: 30, // 50 - 20
+		1: 10, // 15 - 20 clamped up to oldest
+		2: 80, // no commit: shifted from the end (100 - 20)
+	}, r.committed)
+}
+
+func TestResetOffsets_CommitsPlannedTargets(t *testing.T) {
+	ds := mockAdminDS(emptyGroupAdmin("g1"))
+	// The source group has moved on since the plan; the planned targets win.
+	r := &fakeResetter{
+		newest:     map[string]map[int32]int64{"t1": {0: 100, 1: 100}},
+		partitions: map[string][]int32{"t1": {0, 1}},
+		current:    map[string]map[int32]int64{"t1": {0: 90, 1: 90}},
+	}
+	restoreR := installFakeResetter(r)
+	defer restoreR()
+
+	err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+		GroupID: "g1", Topic: "t1", Mode: api.OffsetResetFromGroup, SourceGroupID: "g2",
+		Partitions: []int32{0, 1}, Planned: map[int32]int64{0: 40, 1: 45},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]int64{0: 40, 1: 45}, r.committed)
+}
+
+func TestResetOffsets_RejectsInvalidPlannedTargets(t *testing.T) {
+	ds := mockAdminDS(emptyGroupAdmin("g1"))
+	tests := []struct {
+		name       string
+		partitions []int32
+		planned    map[int32]int64
+	}{
+		{"negative offset", []int32{0}, map[int32]int64{0: -1}},
+		{"below log start", []int32{0}, map[int32]int64{0: 5}},
+		{"past the high watermark", []int32{0}, map[int32]int64{0: 101}},
+		{"partition outside the request", []int32{0}, map[int32]int64{0: 50, 1: 50}},
+		{"partition outside the topic", nil, map[int32]int64{7: 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeResetter{
+				oldest:     map[string]map[int32]int64{"t1": {0: 10, 1: 10}},
+				newest:     map[string]map[int32]int64{"t1": {0: 100, 1: 100}},
+				partitions: map[string][]int32{"t1": {0, 1}},
+			}
+			restoreR := installFakeResetter(r)
+			defer restoreR()
+
+			err := ds.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+				GroupID: "g1", Topic: "t1", Mode: api.OffsetResetEarliest,
+				Partitions: tt.partitions, Planned: tt.planned,
+			})
+			var ivr api.InvalidOffsetResetError
+			assert.True(t, errors.As(err, &ivr), "err = %v", err)
+			assert.Nil(t, r.committed, "nothing may be committed")
+		})
+	}
+}
+
+func TestPlanOffsetReset_ByDuration(t *testing.T) {
+	var lookedUp []int64
+	r := &recordingResetter{fakeResetter: fakeResetter{
+		oldest:     map[string]map[int32]int64{"t1": {0: 0}},
+		newest:     map[string]map[int32]int64{"t1": {0: 90}},
+		partitions: map[string][]int32{"t1": {0}},
+		tsOffset:   map[string]map[int32]int64{"t1": {0: 40}},
+		current:    map[string]map[int32]int64{"t1": {0: 85}},
+	}, lookups: &lookedUp}
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	plan, err := planOffsetReset(context.Background(), api.OffsetResetRequest{
+		GroupID: "g1", Topic: "t1", Mode: api.OffsetResetDuration, Duration: 2 * time.Hour,
+	}, r, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{now.Add(-2 * time.Hour).UnixMilli()}, lookedUp)
+	if assert.Len(t, plan, 1) {
+		p := plan[0]
+		assert.Equal(t, int64(85), *p.Current)
+		assert.Equal(t, int64(40), p.Target)
+		assert.Equal(t, int64(0), p.LogStart)
+		assert.Equal(t, int64(90), p.LogEnd)
+		assert.Equal(t, int64(5), *p.CurrentLag())
+		assert.Equal(t, int64(50), p.TargetLag())
+	}
+	assert.Nil(t, r.committed, "planning must not commit")
+}
+
+// recordingResetter records the timestamps of timestamp lookups.
+type recordingResetter struct {
+	fakeResetter
+	lookups *[]int64
+}
+
+func (r *recordingResetter) GetOffset(topic string, p int32, t int64) (int64, error) {
+	if t >= 0 {
+		*r.lookups = append(*r.lookups, t)
+	}
+	return r.fakeResetter.GetOffset(topic, p, t)
+}
+
+// groupResetter serves committed offsets per group for copy-from-group.
+type groupResetter struct {
+	fakeResetter
+	byGroup map[string]map[int32]int64
+}
+
+func (g *groupResetter) Committed(groupID, topic string, partitions []int32) (map[int32]int64, error) {
+	out := map[int32]int64{}
+	for _, p := range partitions {
+		if v, ok := g.byGroup[groupID][p]; ok {
+			out[p] = v
+		}
+	}
+	return out, nil
+}
+
+func TestPlanOffsetReset_ToCurrentAndCopyFromGroup(t *testing.T) {
+	r := &groupResetter{
+		fakeResetter: fakeResetter{
+			oldest:     map[string]map[int32]int64{"t1": {0: 50, 1: 0, 2: 0}},
+			newest:     map[string]map[int32]int64{"t1": {0: 100, 1: 100, 2: 100}},
+			partitions: map[string][]int32{"t1": {0, 1, 2}},
+		},
+		byGroup: map[string]map[int32]int64{
+			"b": {0: 20, 1: 70},         // p0 fell off the log start
+			"a": {0: 90, 1: 500, 2: 60}, // p1 beyond the end
+		},
+	}
+
+	plan, err := planOffsetReset(context.Background(), api.OffsetResetRequest{GroupID: "b", Topic: "t1", Mode: api.OffsetResetCurrent}, r, time.Now())
+	assert.NoError(t, err)
+	targets := map[int32]int64{}
+	for _, p := range plan {
+		targets[p.Partition] = p.Target
+	}
+	assert.Equal(t, map[int32]int64{0: 50, 1: 70, 2: 100}, targets)
+
+	plan, err = planOffsetReset(context.Background(), api.OffsetResetRequest{GroupID: "b", Topic: "t1", Mode: api.OffsetResetFromGroup, SourceGroupID: "a"}, r, time.Now())
+	assert.NoError(t, err)
+	targets = map[int32]int64{}
+	for _, p := range plan {
+		targets[p.Partition] = p.Target
+	}
+	assert.Equal(t, map[int32]int64{0: 90, 1: 100, 2: 60}, targets)
+
+	delete(r.byGroup, "a")
+	_, err = planOffsetReset(context.Background(), api.OffsetResetRequest{GroupID: "b", Topic: "t1", Mode: api.OffsetResetFromGroup, SourceGroupID: "a"}, r, time.Now())
+	var ivr api.InvalidOffsetResetError
+	assert.True(t, errors.As(err, &ivr), "source without commits: %v", err)
+}
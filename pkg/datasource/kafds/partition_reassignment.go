@@ -0,0 +1,693 @@
+package kafds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+)
+
+// sarama's ClusterAdmin can only reassign a topic's partitions 0..n as a block,
+// hides per-partition errors and cannot list every in-flight reassignment, so
+// AlterPartitionReassignments and ListPartitionReassignments are hand-encoded
+// like AlterReplicaLogDirs and sent to the controller.
+
+const (
+	apiKeyAlterPartitionReassignments int16 = 45
+	apiKeyListPartitionReassignments  int16 = 46
+
+	reassignmentTimeoutMs = 60_000
+	reassignmentOperation = "partition reassignment (Kafka 2.4+)"
+
+	leaderThrottleRate        = "leader.replication.throttled.rate"
+	followerThrottleRate      = "follower.replication.throttled.rate"
+	leaderThrottledReplicas   = "leader.replication.throttled.replicas"
+	followerThrottledReplicas = "follower.replication.throttled.replicas"
+)
+
+// PlanReassignment implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) PlanReassignment(_ context.Context, req api.ReassignmentRequest) (api.ReassignmentPlan, error) {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return api.ReassignmentPlan{}, err
+	}
+	defer admin.Close()
+	brokers, _, err := admin.DescribeCluster()
+	if err != nil {
+		return api.ReassignmentPlan{}, fmt.Errorf("describing cluster: %w", err)
+	}
+	ids := make([]int32, 0, len(brokers))
+	for _, b := range brokers {
+		ids = append(ids, b.ID())
+	}
+	// Draining a broker must move every replica off it, internal topics
+	// (__consumer_offsets, __transaction_state) included.
+	parts, err := reassignableParts(admin, req.Topics, req.Goal == api.ReassignDecommission)
+	if err != nil {
+		return api.ReassignmentPlan{}, err
+	}
+	others, err := otherReplicaLoad(admin, parts)
+	if err != nil {
+		return api.ReassignmentPlan{}, err
+	}
+	return planReassignment(req, ids, parts, others)
+}
+
+// planReassignment is PlanReassignment after the cluster layout is known.
+// parts must be sorted by topic and partition; others counts, per broker, the
+// replicas of every partition not in parts.
+func planReassignment(req api.ReassignmentRequest, brokers []int32, parts []api.PartitionMove, others map[int32]int) (api.ReassignmentPlan, error) {
+	current := make([][]int32, len(parts))
+	for i, p := range parts {
+		current[i] = p.Current
+	}
+
+	var target [][]int32
+	switch req.Goal {
+	case api.ReassignDecommission:
+		if len(req.Brokers) == 0 {
+			return api.ReassignmentPlan{}, api.ReassignmentError{Reason: "no brokers to decommission"}
+		}
+		known := replicaSet(brokers)
+		for _, id := range req.Brokers {
+			if !known[id] {
+				return api.ReassignmentPlan{}, api.BrokerNotFoundError{BrokerID: id}
+			}
+		}
+		var err error
+		if target, err = computeDecommission(current, brokers, req.Brokers, others); err != nil {
+			return api.ReassignmentPlan{}, api.ReassignmentError{Reason: err.Error()}
+		}
+	case api.ReassignRebalance:
+		target = computeRebalance(current, brokers, others)
+	default:
+		return api.ReassignmentPlan{}, api.ReassignmentError{Reason: fmt.Sprintf("unknown goal %q", req.Goal)}
+	}
+
+	plan := api.ReassignmentPlan{Goal: req.Goal}
+	for i, p := range parts {
+		if !equalReplicas(p.Current, target[i]) {
+			p.Target = target[i]
+			plan.Moves = append(plan.Moves, p)
+		}
+	}
+	return plan, nil
+}
+
+// reassignableParts returns the current replicas of every partition of the
+// named topics, sorted. With no names it covers every topic, internal ones only
+// when withInternal is set.
+func reassignableParts(admin ClusterAdminInterface, topics []string, withInternal bool) ([]api.PartitionMove, error) {
+	skipInternal := len(topics) == 0 && !withInternal
+	if len(topics) == 0 {
+		details, err := admin.ListTopics()
+		if err != nil {
+			return nil, fmt.Errorf("listing topics: %w", err)
+		}
+		for name := range details {
+			topics = append(topics, name)
+		}
+	}
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	metadata, err := admin.DescribeTopics(topics)
+	if err != nil {
+		return nil, fmt.Errorf("describing topics: %w", err)
+	}
+	var parts []api.PartitionMove
+	for _, t := range metadata {
+		if t == nil || t.IsInternal && skipInternal {
+			continue
+		}
+		if t.Err != sarama.ErrNoError {
+			return nil, api.TopicNotFoundError{TopicName: t.Name}
+		}
+		for _, p := range t.Partitions {
+			parts = append(parts, api.PartitionMove{Topic: t.Name, Partition: p.ID, Current: p.Replicas})
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		if parts[i].Topic != parts[j].Topic {
+			return parts[i].Topic < parts[j].Topic
+		}
+		return parts[i].Partition < parts[j].Partition
+	})
+	return parts, nil
+}
+
+// otherReplicaLoad counts, per broker, the replicas of every partition in the
+// cluster (internal topics included) that is not in parts, so a plan for some
+// topics still sees the load the rest of the cluster puts on each broker.
+func otherReplicaLoad(admin ClusterAdminInterface, parts []api.PartitionMove) (map[int32]int, error) {
+	details, err := admin.ListTopics()
+	if err != nil {
+		return nil, fmt.Errorf("listing topics: %w", err)
+	}
+	if len(details) == 0 {
+		return nil, nil
+	}
+	metadata, err := admin.DescribeTopics(sortedKeys(details))
+	if err != nil {
+		return nil, fmt.Errorf("describing topics: %w", err)
+	}
+	planned := make(map[string]map[int32]bool)
+	for _, p := range parts {
+		if planned[p.Topic] == nil {
+			planned[p.Topic] = map[int32]bool{}
+		}
+		planned[p.Topic][p.Partition] = true
+	}
+	load := map[int32]int{}
+	for _, t := range metadata {
+		if t == nil || t.Err != sarama.ErrNoError {
+			continue
+		}
+		for _, p := range t.Partitions {
+			if planned[t.Name][p.ID] {
+				continue
+			}
+			for _, b := range p.Replicas {
+				load[b]++
+			}
+		}
+	}
+	return load, nil
+}
+
+func equalReplicas(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ExecuteReassignment implements api.KafkaDataSource. Throttles are applied
+// before the moves are submitted so the first fetches are already capped; if
+// the throttling or the submission fails, the throttle configs this call wrote
+// are restored to their previous values, since nothing moves that would need
+// them. Other in-flight reassignments keep theirs.
+func (kp KafkaDataSourceKaf) ExecuteReassignment(_ context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	if len(plan.Moves) == 0 {
+		return api.ReassignmentError{Reason: "the plan has no moves"}
+	}
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+	targets := map[string]map[int32][]int32{}
+	for _, m := range plan.Moves {
+		if targets[m.Topic] == nil {
+			targets[m.Topic] = map[int32][]int32{}
+		}
+		targets[m.Topic][m.Partition] = m.Target
+	}
+	if opts.ThrottleBytesPerSec <= 0 {
+		return kp.alterReassignments(admin, targets)
+	}
+
+	written, err := applyReplicationThrottle(admin, plan.Moves, opts.ThrottleBytesPerSec)
+	if err == nil {
+		err = kp.alterReassignments(admin, targets)
+	}
+	if err != nil {
+		if rerr := restoreThrottleConfigs(admin, written); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+	}
+	return err
+}
+
+// CancelReassignments implements api.KafkaDataSource by submitting a null
+// replica list for each partition, which reverts it to its original replicas.
+func (kp KafkaDataSourceKaf) CancelReassignments(_ context.Context, partitions []api.TopicPartition) error {
+	if len(partitions) == 0 {
+		return nil
+	}
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+	targets := map[string]map[int32][]int32{}
+	for _, tp := range partitions {
+		if targets[tp.Topic] == nil {
+			targets[tp.Topic] = map[int32][]int32{}
+		}
+		targets[tp.Topic][tp.Partition] = nil
+	}
+	return kp.alterReassignments(admin, targets)
+}
+
+// alterReassignments sends one AlterPartitionReassignments request to the
+// controller and joins the per-partition errors.
+func (kp KafkaDataSourceKaf) alterReassignments(admin ClusterAdminInterface, targets map[string]map[int32][]int32) error {
+	bc, err := kp.controllerConn(admin, apiKeyAlterPartitionReassignments, 0, reassignmentOperation)
+	if err != nil {
+		return err
+	}
+	defer bc.Close()
+
+	resp, err := bc.roundTripFlexible(apiKeyAlterPartitionReassignments, 0, encodeAlterPartitionReassignmentsRequest(targets))
+	if err != nil {
+		return api.NewConnectionErrorWithCause("AlterPartitionReassignments", err)
+	}
+	return decodeAlterPartitionReassignmentsResponse(resp)
+}
+
+// ListReassignments implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) ListReassignments(_ context.Context) ([]api.PartitionReassignment, error) {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+	bc, err := kp.controllerConn(admin, apiKeyListPartitionReassignments, 0, reassignmentOperation)
+	if err != nil {
+		return nil, err
+	}
+	defer bc.Close()
+
+	resp, err := bc.roundTripFlexible(apiKeyListPartitionReassignments, 0, encodeListPartitionReassignmentsRequest())
+	if err != nil {
+		return nil, api.NewConnectionErrorWithCause("ListPartitionReassignments", err)
+	}
+	inFlight, err := decodeListPartitionReassignmentsResponse(resp)
+	if err != nil || len(inFlight) == 0 {
+		return inFlight, err
+	}
+	kp.fillReassignmentProgress(admin, inFlight)
+	return inFlight, nil
+}
+
+// fillReassignmentProgress estimates each reassignment's progress from the
+// ISR and the adding replicas' log sizes. Both lookups are best effort: a
+// failure leaves the progress at what the other source could tell.
+func (kp KafkaDataSourceKaf) fillReassignmentProgress(admin ClusterAdminInterface, inFlight []api.PartitionReassignment) {
+	type tp struct {
+		topic     string
+		partition int32
+	}
+	isr := map[tp]map[int32]bool{}
+	leaders := map[tp]int32{}
+	var topics []string
+	seen := map[string]bool{}
+	for _, r := range inFlight {
+		if !seen[r.Topic] {
+			seen[r.Topic] = true
+			topics = append(topics, r.Topic)
+		}
+	}
+	if metadata, err := admin.DescribeTopics(topics); err == nil {
+		for _, t := range metadata {
+			if t == nil {
+				continue
+			}
+			for _, p := range t.Partitions {
+				isr[tp{t.Name, p.ID}] = replicaSet(p.Isr)
+				leaders[tp{t.Name, p.ID}] = p.Leader
+			}
+		}
+	}
+
+	sizes := map[tp]map[int32]int64{}
+	if dirs, err := kp.GetBrokerLogDirs(nil); err == nil {
+		for broker, ds := range dirs {
+			for _, d := range ds {
+				for _, t := range d.Topics {
+					for _, p := range t.Partitions {
+						key := tp{t.Topic, p.Partition}
+						if sizes[key] == nil {
+							sizes[key] = map[int32]int64{}
+						}
+						sizes[key][broker] += p.Size
+					}
+				}
+			}
+		}
+	}
+
+	for i, r := range inFlight {
+		key := tp{r.Topic, r.Partition}
+		inFlight[i].Progress = reassignmentProgress(r.Adding, isr[key], sizes[key], leaders[key])
+	}
+}
+
+// reassignmentProgress averages the adding replicas' catch-up: 1 once in the
+// ISR, otherwise the replica's log size over the leader's.
+func reassignmentProgress(adding []int32, isr map[int32]bool, sizes map[int32]int64, leader int32) float64 {
+	if len(adding) == 0 {
+		return 1
+	}
+	var sum float64
+	for _, b := range adding {
+		switch {
+		case isr[b]:
+			sum++
+		case sizes[leader] > 0:
+			sum += min(float64(sizes[b])/float64(sizes[leader]), 1)
+		}
+	}
+	return sum / float64(len(adding))
+}
+
+// ClearReplicationThrottles implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) ClearReplicationThrottles(_ context.Context, topics []string) error {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+	brokers, _, err := admin.DescribeCluster()
+	if err != nil {
+		return fmt.Errorf("describing cluster: %w", err)
+	}
+	del := sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+	var errs []error
+	for _, b := range brokers {
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{leaderThrottleRate: del, followerThrottleRate: del}
+		if err := admin.IncrementalAlterConfig(sarama.BrokerResource, strconv.Itoa(int(b.ID())), entries, false); err != nil {
+			errs = append(errs, fmt.Errorf("broker %d: %w", b.ID(), err))
+		}
+	}
+	for _, t := range topics {
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{leaderThrottledReplicas: del, followerThrottledReplicas: del}
+		if err := admin.IncrementalAlterConfig(sarama.TopicResource, t, entries, false); err != nil {
+			errs = append(errs, fmt.Errorf("topic %s: %w", t, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("clearing replication throttles: %w", err)
+	}
+	return nil
+}
+
+// throttleWrite is one config resource applyReplicationThrottle changed, with
+// the values its keys held before (nil when unset).
+type throttleWrite struct {
+	typ  sarama.ConfigResourceType
+	name string
+	prev map[string]*string
+}
+
+// applyReplicationThrottle caps replication of the moved replicas at rate
+// bytes/s, as kafka-reassign-partitions does: the current replicas are
+// leader-throttled, the adding ones follower-throttled, and the rate is set on
+// every broker involved. It returns the resources it wrote, also on error, so
+// restoreThrottleConfigs can undo exactly those.
+func applyReplicationThrottle(admin ClusterAdminInterface, moves []api.PartitionMove, rate int64) ([]throttleWrite, error) {
+	leader := map[string][]string{}
+	follower := map[string][]string{}
+	involved := map[int32]bool{}
+	for _, m := range moves {
+		for _, b := range m.Current {
+			leader[m.Topic] = append(leader[m.Topic], fmt.Sprintf("%d:%d", m.Partition, b))
+			involved[b] = true
+		}
+		for _, b := range m.Adding() {
+			follower[m.Topic] = append(follower[m.Topic], fmt.Sprintf("%d:%d", m.Partition, b))
+			involved[b] = true
+		}
+	}
+
+	var written []throttleWrite
+	write := func(typ sarama.ConfigResourceType, name string, entries map[string]sarama.IncrementalAlterConfigsEntry) error {
+		w := throttleWrite{typ: typ, name: name}
+		var err error
+		if w.prev, err = throttleConfigs(admin, typ, name, entries); err != nil {
+			return err
+		}
+		if err := admin.IncrementalAlterConfig(typ, name, entries, false); err != nil {
+			return err
+		}
+		written = append(written, w)
+		return nil
+	}
+
+	for _, topic := range sortedKeys(leader) {
+		l := strings.Join(leader[topic], ",")
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{
+			leaderThrottledReplicas: {Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &l},
+		}
+		if f, ok := follower[topic]; ok {
+			v := strings.Join(f, ",")
+			entries[followerThrottledReplicas] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &v}
+		}
+		if err := write(sarama.TopicResource, topic, entries); err != nil {
+			return written, fmt.Errorf("throttling replicas of %s: %w", topic, err)
+		}
+	}
+
+	r := strconv.FormatInt(rate, 10)
+	set := sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: &r}
+	ids := make([]int32, 0, len(involved))
+	for b := range involved {
+		ids = append(ids, b)
+	}
+	for _, b := range sortedBrokers(ids) {
+		entries := map[string]sarama.IncrementalAlterConfigsEntry{leaderThrottleRate: set, followerThrottleRate: set}
+		if err := write(sarama.BrokerResource, strconv.Itoa(int(b)), entries); err != nil {
+			return written, fmt.Errorf("setting the replication throttle on broker %d: %w", b, err)
+		}
+	}
+	return written, nil
+}
+
+// throttleConfigs returns the values the keys of entries are set to on the
+// resource itself (not inherited defaults), nil for unset keys.
+func throttleConfigs(admin ClusterAdminInterface, typ sarama.ConfigResourceType, name string, entries map[string]sarama.IncrementalAlterConfigsEntry) (map[string]*string, error) {
+	keys := sortedKeys(entries)
+	current, err := admin.DescribeConfig(sarama.ConfigResource{Type: typ, Name: name, ConfigNames: keys})
+	if err != nil {
+		return nil, fmt.Errorf("reading current throttle: %w", err)
+	}
+	own := sarama.SourceTopic
+	if typ == sarama.BrokerResource {
+		own = sarama.SourceDynamicBroker
+	}
+	prev := make(map[string]*string, len(keys))
+	for _, k := range keys {
+		prev[k] = nil
+	}
+	for _, e := range current {
+		if _, ok := prev[e.Name]; !ok || e.Default || e.Value == "" {
+			continue
+		}
+		if e.Source == own || e.Source == sarama.SourceUnknown {
+			v := e.Value
+			prev[e.Name] = &v
+		}
+	}
+	return prev, nil
+}
+
+// restoreThrottleConfigs puts every key applyReplicationThrottle wrote back to
+// its previous value, deleting the ones that were unset.
+func restoreThrottleConfigs(admin ClusterAdminInterface, written []throttleWrite) error {
+	var errs []error
+	for _, w := range written {
+		entries := make(map[string]sarama.IncrementalAlterConfigsEntry, len(w.prev))
+		for k, v := range w.prev {
+			if v == nil {
+				entries[k] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationDelete}
+			} else {
+				entries[k] = sarama.IncrementalAlterConfigsEntry{Operation: sarama.IncrementalAlterConfigsOperationSet, Value: v}
+			}
+		}
+		if err := admin.IncrementalAlterConfig(w.typ, w.name, entries, false); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", w.name, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("restoring replication throttles: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// controllerConn opens a connection to the controller reported by
+// DescribeCluster and checks that it supports apiKey at minVersion or later;
+// operation names the feature in the NotSupportedError otherwise.
+func (kp KafkaDataSourceKaf) controllerConn(admin ClusterAdminInterface, apiKey, minVersion int16, operation string) (*brokerConn, error) {
+	brokers, controllerID, err := admin.DescribeCluster()
+	if err != nil {
+		return nil, fmt.Errorf("describing cluster: %w", err)
+	}
+	target := probeBroker(brokers, controllerID)
+	if target == nil {
+		return nil, api.NewConnectionError("no broker available")
+	}
+	cfg, err := kp.getConfig()
+	if err != nil {
+		return nil, err
+	}
+	bc, err := openBrokerConn(cfg, target.Addr(), target.ID(), operation)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := bc.apiVersions()
+	if err != nil {
+		bc.Close()
+		return nil, api.NewConnectionErrorWithCause(fmt.Sprintf("ApiVersions on broker %d", target.ID()), err)
+	}
+	if v, ok := versions[apiKey]; !ok || v < minVersion {
+		bc.Close()
+		return nil, api.NotSupportedError{Operation: operation}
+	}
+	return bc, nil
+}
+
+// encodeAlterPartitionReassignmentsRequest encodes an AlterPartitionReassignments
+// v0 body (flexible); a nil replica list cancels that partition:
+//
+//	timeout_ms [topics] _tags
+//	  topics => name [partitions] _tags
+//	    partitions => partition_index [replicas] _tags
+func encodeAlterPartitionReassignmentsRequest(targets map[string]map[int32][]int32) []byte {
+	var e wireEncoder
+	e.putInt32(reassignmentTimeoutMs)
+	e.putCompactArrayLen(len(targets))
+	for _, topic := range sortedKeys(targets) {
+		e.putCompactString(topic)
+		parts := make([]int32, 0, len(targets[topic]))
+		for p := range targets[topic] {
+			parts = append(parts, p)
+		}
+		parts = sortedBrokers(parts)
+		e.putCompactArrayLen(len(parts))
+		for _, p := range parts {
+			e.putInt32(p)
+			e.putCompactInt32s(targets[topic][p])
+			e.putEmptyTags()
+		}
+		e.putEmptyTags()
+	}
+	e.putEmptyTags()
+	return e.buf
+}
+
+// decodeAlterPartitionReassignmentsResponse decodes an
+// AlterPartitionReassignments v0 body into the joined per-partition errors:
+//
+//	throttle_time_ms error_code error_message [responses] _tags
+//	  responses => name [partitions] _tags
+//	    partitions => partition_index error_code error_message _tags
+func decodeAlterPartitionReassignmentsResponse(body []byte) error {
+	d := wireDecoder{buf: body}
+	d.int32() // throttle_time_ms
+	code := sarama.KError(d.int16())
+	msg := d.compactString()
+	var errs []error
+	for i, n := 0, d.compactArrayLen(); i < n; i++ {
+		topic := d.compactString()
+		for j, m := 0, d.compactArrayLen(); j < m; j++ {
+			p := d.int32()
+			pcode := sarama.KError(d.int16())
+			pmsg := d.compactString()
+			d.skipTags()
+			if pcode != sarama.ErrNoError {
+				errs = append(errs, reassignmentPartitionError(topic, p, pcode, pmsg))
+			}
+		}
+		d.skipTags()
+	}
+	d.skipTags()
+	if d.err != nil {
+		return fmt.Errorf("decoding AlterPartitionReassignments response: %w", d.err)
+	}
+	switch {
+	case code == sarama.ErrClusterAuthorizationFailed:
+		return api.NewAuthorizationError(code.Error(), "cluster", "Alter")
+	case code != sarama.ErrNoError:
+		return api.ReassignmentError{Reason: orKErrorText(msg, code), Cause: code}
+	case len(errs) > 0:
+		return api.ReassignmentError{Reason: "the controller rejected some partitions", Cause: errors.Join(errs...)}
+	}
+	return nil
+}
+
+func reassignmentPartitionError(topic string, partition int32, code sarama.KError, msg string) error {
+	return fmt.Errorf("%s-%d: %s: %w", topic, partition, orKErrorText(msg, code), code)
+}
+
+// orKErrorText prefers the broker's error message over the generic code text.
+func orKErrorText(msg string, code sarama.KError) string {
+	if msg != "" {
+		return msg
+	}
+	return code.Error()
+}
+
+// encodeListPartitionReassignmentsRequest encodes a ListPartitionReassignments
+// v0 body (flexible) asking for every in-flight reassignment:
+//
+//	timeout_ms [topics] _tags    (topics null => all)
+func encodeListPartitionReassignmentsRequest() []byte {
+	var e wireEncoder
+	e.putInt32(reassignmentTimeoutMs)
+	e.putUvarint(0) // null topics
+	e.putEmptyTags()
+	return e.buf
+}
+
+// decodeListPartitionReassignmentsResponse decodes a ListPartitionReassignments
+// v0 body:
+//
+//	throttle_time_ms error_code error_message [topics] _tags
+//	  topics => name [partitions] _tags
+//	    partitions => partition_index [replicas] [adding_replicas] [removing_replicas] _tags
+func decodeListPartitionReassignmentsResponse(body []byte) ([]api.PartitionReassignment, error) {
+	d := wireDecoder{buf: body}
+	d.int32() // throttle_time_ms
+	code := sarama.KError(d.int16())
+	msg := d.compactString()
+	var out []api.PartitionReassignment
+	for i, n := 0, d.compactArrayLen(); i < n; i++ {
+		topic := d.compactString()
+		for j, m := 0, d.compactArrayLen(); j < m; j++ {
+			r := api.PartitionReassignment{Topic: topic, Partition: d.int32()}
+			r.Replicas = d.compactInt32s()
+			r.Adding = d.compactInt32s()
+			r.Removing = d.compactInt32s()
+			d.skipTags()
+			out = append(out, r)
+		}
+		d.skipTags()
+	}
+	d.skipTags()
+	if d.err != nil {
+		return nil, fmt.Errorf("decoding ListPartitionReassignments response: %w", d.err)
+	}
+	switch {
+	case code == sarama.ErrClusterAuthorizationFailed:
+		return nil, api.NewAuthorizationError(code.Error(), "cluster", "Describe")
+	case code != sarama.ErrNoError:
+		return nil, fmt.Errorf("listing reassignments: %s: %w", orKErrorText(msg, code), code)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Topic != out[j].Topic {
+			return out[i].Topic < out[j].Topic
+		}
+		return out[i].Partition < out[j].Partition
+	})
+	return out, nil
+}
@@ -0,0 +1,318 @@
+package kafds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	alterReassignmentsRequestBody = `
+		0000ea60                   # timeout_ms 60000
+		02 0274                    # topics (1), "t"
+		03                         # partitions (2)
+		00000000 03 00000002 00000003 00 # 0 -> [2 3]
+		00000001 00 00             # 1 -> null (cancel)
+		00 00                      # topic, request tags`
+
+	alterReassignmentsResponseBody = `
+		00000000 0000 00           # throttle, error_code, null message
+		02 0274                    # responses (1), "t"
+		02 00000000 0000 00 00     # partition 0 ok
+		00 00                      # topic, response tags`
+
+	listReassignmentsResponseBody = `
+		00000000 0000 00           # throttle, error_code, null message
+		02 0274                    # topics (1), "t"
+		02 00000000                # partitions (1), partition 0
+		04 00000001 00000002 00000003 # replicas [1 2 3]
+		02 00000003                # adding [3]
+		02 00000001                # removing [1]
+		00 00 00                   # partition, topic, response tags`
+
+	// ApiVersions v0 reply advertising AlterPartitionReassignments (45) and
+	// ListPartitionReassignments (46).
+	apiVersionsReassign = `0000 00000002 002d 0000 0000 002e 0000 0000`
+)
+
+func TestPlanReassignment(t *testing.T) {
+	parts := []api.PartitionMove{
+		{Topic: "a", Partition: 0, Current: []int32{1, 2}},
+		{Topic: "a", Partition: 1, Current: []int32{2, 3}},
+		{Topic: "b", Partition: 0, Current: []int32{3, 1}},
+	}
+	brokers := []int32{1, 2, 3, 4}
+
+	t.Run("decommission moves only the drained replicas", func(t *testing.T) {
+		plan, err := planReassignment(api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{3}}, brokers, parts, nil)
+		require.NoError(t, err)
+		require.Len(t, plan.Moves, 2)
+		assert.Equal(t, "a", plan.Moves[0].Topic)
+		assert.Equal(t, int32(1), plan.Moves[0].Partition)
+		assert.Equal(t, []int32{2, 4}, plan.Moves[0].Target)
+		assert.Equal(t, []int32{4, 1}, plan.Moves[1].Target, "the drained leader is replaced in place")
+		assert.Equal(t, []int32{3}, plan.Moves[1].Removing())
+	})
+
+	t.Run("unknown broker", func(t *testing.T) {
+		_, err := planReassignment(api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{9}}, brokers, parts, nil)
+		var nf api.BrokerNotFoundError
+		assert.ErrorAs(t, err, &nf)
+	})
+
+	t.Run("nothing to drain", func(t *testing.T) {
+		_, err := planReassignment(api.ReassignmentRequest{Goal: api.ReassignDecommission}, brokers, parts, nil)
+		var re api.ReassignmentError
+		assert.ErrorAs(t, err, &re)
+	})
+
+	t.Run("too few brokers would remain", func(t *testing.T) {
+		_, err := planReassignment(api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{2, 3, 4}}, brokers, parts, nil)
+		var re api.ReassignmentError
+		assert.ErrorAs(t, err, &re)
+	})
+
+	t.Run("unknown goal", func(t *testing.T) {
+		_, err := planReassignment(api.ReassignmentRequest{Goal: "shuffle"}, brokers, parts, nil)
+		var re api.ReassignmentError
+		assert.ErrorAs(t, err, &re)
+	})
+
+	t.Run("decommission avoids brokers loaded by other topics", func(t *testing.T) {
+		plan, err := planReassignment(api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{3}}, brokers, parts, map[int32]int{4: 10})
+		require.NoError(t, err)
+		for _, m := range plan.Moves {
+			assert.NotContains(t, m.Target, int32(4), "%s/%d", m.Topic, m.Partition)
+		}
+	})
+
+	t.Run("rebalance of a balanced layout is empty", func(t *testing.T) {
+		plan, err := planReassignment(api.ReassignmentRequest{Goal: api.ReassignRebalance}, []int32{1, 2, 3}, parts, nil)
+		require.NoError(t, err)
+		assert.Empty(t, plan.Moves)
+	})
+}
+
+func TestPlanReassignmentInternalTopics(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockBrokers: []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092"), newTestBroker(3, "b3:9092"), newTestBroker(4, "b4:9092")},
+		MockTopics:  map[string]sarama.TopicDetail{"a": {}, "__consumer_offsets": {}},
+		MockTopicMetadata: []*sarama.TopicMetadata{
+			{Name: "a", Partitions: []*sarama.PartitionMetadata{{ID: 0, Replicas: []int32{1, 3}}}},
+			{Name: "__consumer_offsets", IsInternal: true, Partitions: []*sarama.PartitionMetadata{{ID: 0, Replicas: []int32{2, 3}}, {ID: 1, Replicas: []int32{2, 3}}}},
+		},
+	}
+	ds := mockAdminDS(admin)
+
+	plan, err := ds.PlanReassignment(context.Background(), api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{2}})
+	require.NoError(t, err)
+	require.Len(t, plan.Moves, 2, "every replica leaves the drained broker, internal ones included")
+	for _, m := range plan.Moves {
+		assert.Equal(t, "__consumer_offsets", m.Topic)
+		assert.NotContains(t, m.Target, int32(2))
+	}
+
+	plan, err = ds.PlanReassignment(context.Background(), api.ReassignmentRequest{Goal: api.ReassignRebalance})
+	require.NoError(t, err)
+	for _, m := range plan.Moves {
+		assert.NotEqual(t, "__consumer_offsets", m.Topic, "a rebalance leaves internal topics alone")
+	}
+}
+
+func TestOtherReplicaLoad(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockTopics: map[string]sarama.TopicDetail{"a": {}, "b": {}, "__consumer_offsets": {}},
+		MockTopicMetadata: []*sarama.TopicMetadata{
+			{Name: "a", Partitions: []*sarama.PartitionMetadata{{ID: 0, Replicas: []int32{1, 2}}, {ID: 1, Replicas: []int32{2, 3}}}},
+			{Name: "b", Partitions: []*sarama.PartitionMetadata{{ID: 0, Replicas: []int32{3, 1}}}},
+			{Name: "__consumer_offsets", IsInternal: true, Partitions: []*sarama.PartitionMetadata{{ID: 0, Replicas: []int32{3}}}},
+		},
+	}
+	parts := []api.PartitionMove{{Topic: "a", Partition: 0}, {Topic: "a", Partition: 1}}
+	load, err := otherReplicaLoad(admin, parts)
+	require.NoError(t, err)
+	assert.Equal(t, map[int32]int{1: 1, 3: 2}, load, "b and the internal topic count, a does not")
+}
+
+func TestEncodeAlterPartitionReassignmentsRequest(t *testing.T) {
+	got := encodeAlterPartitionReassignmentsRequest(map[string]map[int32][]int32{"t": {1: nil, 0: {2, 3}}})
+	assert.Equal(t, unhex(t, stripComments(alterReassignmentsRequestBody)), got)
+}
+
+func TestDecodeAlterPartitionReassignmentsResponse(t *testing.T) {
+	assert.NoError(t, decodeAlterPartitionReassignmentsResponse(unhex(t, stripComments(alterReassignmentsResponseBody))))
+
+	t.Run("partition error", func(t *testing.T) {
+		body := unhex(t, stripComments(alterReassignmentsResponseBody))
+		body[4+2+1+1+2+1+4+1] = 0x55 // low byte of the partition error_code => NO_REASSIGNMENT_IN_PROGRESS
+		err := decodeAlterPartitionReassignmentsResponse(body)
+		var re api.ReassignmentError
+		require.ErrorAs(t, err, &re)
+		assert.ErrorIs(t, err, sarama.ErrNoReassignmentInProgress)
+		assert.Contains(t, err.Error(), "t-0")
+	})
+
+	t.Run("cluster authorization", func(t *testing.T) {
+		err := decodeAlterPartitionReassignmentsResponse(unhex(t, "00000000 001f 00 01 00"))
+		var ae api.AuthorizationError
+		assert.ErrorAs(t, err, &ae)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		full := unhex(t, stripComments(alterReassignmentsResponseBody))
+		assert.ErrorIs(t, decodeAlterPartitionReassignmentsResponse(full[:len(full)-5]), errShortResponse)
+	})
+}
+
+func TestDecodeListPartitionReassignmentsResponse(t *testing.T) {
+	got, err := decodeListPartitionReassignmentsResponse(unhex(t, stripComments(listReassignmentsResponseBody)))
+	require.NoError(t, err)
+	assert.Equal(t, []api.PartitionReassignment{{
+		Topic: "t", Partition: 0, Replicas: []int32{1, 2, 3}, Adding: []int32{3}, Removing: []int32{1},
+	}}, got)
+}
+
+func TestReassignmentProgress(t *testing.T) {
+	sizes := map[int32]int64{1: 1000, 2: 250, 3: 2000}
+	tests := []struct {
+		name   string
+		adding []int32
+		isr    map[int32]bool
+		want   float64
+	}{
+		{"nothing to add", nil, nil, 1},
+		{"in the ISR", []int32{2}, map[int32]bool{2: true}, 1},
+		{"log size over the leader's", []int32{2}, nil, 0.25},
+		{"capped at 1", []int32{3}, nil, 1},
+		{"averaged", []int32{2, 4}, map[int32]bool{4: true}, 0.625},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, reassignmentProgress(tt.adding, tt.isr, sizes, 1), 1e-9)
+		})
+	}
+}
+
+func TestApplyReplicationThrottle(t *testing.T) {
+	admin := &MockClusterAdmin{}
+	moves := []api.PartitionMove{{Topic: "t", Partition: 0, Current: []int32{1, 2}, Target: []int32{2, 3}}}
+	written, err := applyReplicationThrottle(admin, moves, 1024)
+	require.NoError(t, err)
+	assert.Len(t, written, 4, "one topic and three brokers")
+
+	got := map[string]string{}
+	for _, c := range admin.IncrementalAlterConfigCalls {
+		got[c.Name+"/"+c.Key] = c.Value
+	}
+	assert.Equal(t, map[string]string{
+		"t/" + leaderThrottledReplicas:   "0:1,0:2",
+		"t/" + followerThrottledReplicas: "0:3",
+		"1/" + leaderThrottleRate:        "1024",
+		"1/" + followerThrottleRate:      "1024",
+		"2/" + leaderThrottleRate:        "1024",
+		"2/" + followerThrottleRate:      "1024",
+		"3/" + leaderThrottleRate:        "1024",
+		"3/" + followerThrottleRate:      "1024",
+	}, got)
+}
+
+func TestClearReplicationThrottles(t *testing.T) {
+	admin := &MockClusterAdmin{MockBrokers: []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092")}}
+	require.NoError(t, mockAdminDS(admin).ClearReplicationThrottles(context.Background(), []string{"t"}))
+	assert.Len(t, admin.IncrementalAlterConfigCalls, 6, "two rates per broker, two replica lists per topic")
+	for _, c := range admin.IncrementalAlterConfigCalls {
+		assert.Empty(t, c.Value, "%s/%s should be deleted", c.Name, c.Key)
+	}
+}
+
+func TestExecuteReassignment(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockBrokers:      []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092")},
+		MockControllerID: 2,
+	}
+	plan := api.ReassignmentPlan{Moves: []api.PartitionMove{{Topic: "t", Partition: 0, Current: []int32{1}, Target: []int32{2}}}}
+	reply := append([]byte{0}, unhex(t, stripComments(alterReassignmentsResponseBody))...)
+
+	t.Run("sent to the controller after throttling", func(t *testing.T) {
+		admin.IncrementalAlterConfigCalls = nil
+		keys := withBrokerPipe(t, "b2:9092", unhex(t, apiVersionsReassign), reply)
+		require.NoError(t, mockAdminDS(admin).ExecuteReassignment(context.Background(), plan, api.ReassignmentOptions{ThrottleBytesPerSec: 1 << 20}))
+		assert.Equal(t, []int16{apiKeyApiVersions, apiKeyAlterPartitionReassignments}, collectKeys(keys))
+		assert.NotEmpty(t, admin.IncrementalAlterConfigCalls)
+	})
+
+	t.Run("only its own throttles rolled back when the submission fails", func(t *testing.T) {
+		prior := "500"
+		admin := &MockClusterAdmin{
+			MockBrokers:      []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092"), newTestBroker(3, "b3:9092")},
+			MockControllerID: 2,
+			MockConfigsByName: map[string][]sarama.ConfigEntry{
+				"2": {
+					{Name: leaderThrottleRate, Value: prior, Source: sarama.SourceDynamicBroker},
+					{Name: followerThrottleRate, Value: prior, Source: sarama.SourceDynamicBroker},
+				},
+			},
+		}
+		withBrokerPipe(t, "b2:9092", unhex(t, apiVersionsZK))
+		err := mockAdminDS(admin).ExecuteReassignment(context.Background(), plan, api.ReassignmentOptions{ThrottleBytesPerSec: 1 << 20})
+		var ns api.NotSupportedError
+		require.ErrorAs(t, err, &ns)
+
+		final := map[string]string{}
+		for _, c := range admin.IncrementalAlterConfigCalls {
+			final[c.Name+"/"+c.Key] = c.Value
+		}
+		assert.Equal(t, map[string]string{
+			"t/" + leaderThrottledReplicas:   "",
+			"t/" + followerThrottledReplicas: "",
+			"1/" + leaderThrottleRate:        "",
+			"1/" + followerThrottleRate:      "",
+			"2/" + leaderThrottleRate:        prior,
+			"2/" + followerThrottleRate:      prior,
+		}, final, "broker 3 is not involved and broker 2 keeps its earlier rate")
+	})
+
+	t.Run("empty plan", func(t *testing.T) {
+		err := mockAdminDS(admin).ExecuteReassignment(context.Background(), api.ReassignmentPlan{}, api.ReassignmentOptions{})
+		var re api.ReassignmentError
+		assert.ErrorAs(t, err, &re)
+	})
+
+	t.Run("not advertised", func(t *testing.T) {
+		withBrokerPipe(t, "b2:9092", unhex(t, apiVersionsZK))
+		err := mockAdminDS(admin).CancelReassignments(context.Background(), []api.TopicPartition{{Topic: "t", Partition: 0}})
+		var ns api.NotSupportedError
+		assert.ErrorAs(t, err, &ns)
+	})
+}
+
+func TestListReassignments(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockBrokers:      []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092"), newTestBroker(3, "b3:9092")},
+		MockControllerID: 1,
+		MockTopicMetadata: []*sarama.TopicMetadata{{Name: "t", Partitions: []*sarama.PartitionMetadata{
+			{ID: 0, Leader: 2, Replicas: []int32{1, 2, 3}, Isr: []int32{1, 2}},
+		}}},
+		MockLogDirs: map[int32][]sarama.DescribeLogDirsResponseDirMetadata{
+			2: {{Path: "/d", Topics: []sarama.DescribeLogDirsResponseTopic{
+				{Topic: "t", Partitions: []sarama.DescribeLogDirsResponsePartition{{PartitionID: 0, Size: 400}}},
+			}}},
+			3: {{Path: "/d", Topics: []sarama.DescribeLogDirsResponseTopic{
+				{Topic: "t", Partitions: []sarama.DescribeLogDirsResponsePartition{{PartitionID: 0, Size: 100}}},
+			}}},
+		},
+	}
+	reply := append([]byte{0}, unhex(t, stripComments(listReassignmentsResponseBody))...)
+	keys := withBrokerPipe(t, "b1:9092", unhex(t, apiVersionsReassign), reply)
+
+	got, err := mockAdminDS(admin).ListReassignments(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []int32{3}, got[0].Adding)
+	assert.InDelta(t, 0.25, got[0].Progress, 1e-9)
+	assert.Equal(t, []int16{apiKeyApiVersions, apiKeyListPartitionReassignments}, collectKeys(keys))
+}
@@ -2,6 +2,7 @@ package kafds
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Benny93/kafui/pkg/api"
@@ -14,29 +15,125 @@ var newSyncProducer = func(client sarama.Client) (sarama.SyncProducer, error) {
 	return sarama.NewSyncProducerFromClient(client)
 }
 
+// pinnedPartition marks a ProducerMessage whose Partition was chosen by the
+// caller rather than derived from its key.
+type pinnedPartition struct{}
+
+// recordPartitioner honours pinned partitions and hashes the key otherwise, so
+// one pooled client serves both kinds of produce request.
+type recordPartitioner struct {
+	hash sarama.Partitioner
+}
+
+func newRecordPartitioner(topic string) sarama.Partitioner {
+	return recordPartitioner{hash: sarama.NewHashPartitioner(topic)}
+}
+
+func (p recordPartitioner) Partition(msg *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if _, ok := msg.Metadata.(pinnedPartition); ok {
+		return msg.Partition, nil
+	}
+	return p.hash.Partition(msg, numPartitions)
+}
+
+func (p recordPartitioner) RequiresConsistency() bool { return true }
+
 // ProduceMessage implements api.KafkaDataSource (MSG-30).
 func (kp KafkaDataSourceKaf) ProduceMessage(ctx context.Context, topic string, rec api.ProduceRecord) error {
-	cfg, err := getConfig()
-	if err != nil {
-		return err
-	}
-	cfg.Producer.Return.Successes = true
-	// Honour an explicit partition when one is requested.
-	if rec.Partition != nil {
-		cfg.Producer.Partitioner = sarama.NewManualPartitioner
-	}
-	client, err := getClientFromConfig(cfg)
+	client, err := kp.getClient()
 	if err != nil {
 		return api.NewConnectionErrorWithCause("unable to create client for produce", err)
 	}
 	defer client.Close()
+	// The pooled client's metadata may predate a partition change; refresh
+	// just this topic (an unknown topic is reported by doProduce).
+	_ = client.RefreshMetadata(topic)
 	return doProduce(ctx, client, topic, rec)
 }
 
-// doProduce validates the request against topic metadata and sends the record.
-// It is separated from ProduceMessage so it can be unit-tested with a fake
-// client and producer.
-func doProduce(ctx context.Context, client sarama.Client, topic string, rec api.ProduceRecord) error {
+// ProduceBatch implements api.KafkaDataSource. Default options reuse the
+// pooled client; any tuned option needs its own client because sarama reads
+// producer settings from the client config.
+func (kp KafkaDataSourceKaf) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, nil
+	}
+
+	var client sarama.Client
+	if opts == (api.ProducerOptions{}) {
+		c, err := kp.getClient()
+		if err != nil {
+			return nil, api.NewConnectionErrorWithCause("unable to create client for produce", err)
+		}
+		client = c
+	} else {
+		cfg, err := kp.getConfig()
+		if err != nil {
+			return nil, err
+		}
+		applyProducerOptions(cfg, opts)
+		c, err := kp.getClientFromConfig(cfg)
+		if err != nil {
+			return nil, api.NewConnectionErrorWithCause("unable to create client for produce", err)
+		}
+		client = c
+	}
+	defer client.Close()
+	_ = client.RefreshMetadata(topic)
+	return doProduceBatch(ctx, client, topic, recs, opts)
+}
+
+// applyProducerOptions maps api.ProducerOptions onto a sarama config,
+// including the settings sarama requires for idempotence and transactions.
+func applyProducerOptions(cfg *sarama.Config, opts api.ProducerOptions) {
+	switch opts.Acks {
+	case api.AcksLeader:
+		cfg.Producer.RequiredAcks = sarama.WaitForLocal
+	case api.AcksNone:
+		cfg.Producer.RequiredAcks = sarama.NoResponse
+	default:
+		cfg.Producer.RequiredAcks = sarama.WaitForAll
+	}
+	switch opts.Compression {
+	case api.CompressionGzip:
+		cfg.Producer.Compression = sarama.CompressionGZIP
+	case api.CompressionSnappy:
+		cfg.Producer.Compression = sarama.CompressionSnappy
+	case api.CompressionLZ4:
+		cfg.Producer.Compression = sarama.CompressionLZ4
+	case api.CompressionZstd:
+		cfg.Producer.Compression = sarama.CompressionZSTD
+	default:
+		cfg.Producer.Compression = sarama.CompressionNone
+	}
+	if opts.Idempotent || opts.Transactional() {
+		cfg.Producer.Idempotent = true
+		cfg.Producer.RequiredAcks = sarama.WaitForAll
+		cfg.Net.MaxOpenRequests = 1
+		if cfg.Producer.Retry.Max < 1 {
+			cfg.Producer.Retry.Max = 1
+		}
+		if !cfg.Version.IsAtLeast(sarama.V0_11_0_0) {
+			cfg.Version = sarama.V0_11_0_0
+		}
+	}
+	if opts.Transactional() {
+		cfg.Producer.Transaction.ID = opts.TransactionalID
+	}
+	if opts.Linger > 0 {
+		cfg.Producer.Flush.Frequency = opts.Linger
+	}
+	if opts.BatchSize > 0 {
+		cfg.Producer.Flush.Bytes = opts.BatchSize
+	}
+}
+
+// checkProduceTarget validates that the topic exists and that every explicit
+// partition is in range.
+func checkProduceTarget(client sarama.Client, topic string, recs ...api.ProduceRecord) error {
 	parts, err := client.Partitions(topic)
 	if err != nil {
 		return api.TopicNotFoundError{TopicName: topic, Cause: err}
@@ -44,7 +141,10 @@ func doProduce(ctx context.Context, client sarama.Client, topic string, rec api.
 	if len(parts) == 0 {
 		return api.TopicNotFoundError{TopicName: topic}
 	}
-	if rec.Partition != nil {
+	for _, rec := range recs {
+		if rec.Partition == nil {
+			continue
+		}
 		p := *rec.Partition
 		if p < 0 || int(p) >= len(parts) {
 			return api.NewPartitionError(
@@ -52,13 +152,11 @@ func doProduce(ctx context.Context, client sarama.Client, topic string, rec api.
 				topic, p)
 		}
 	}
+	return nil
+}
 
-	producer, err := newSyncProducer(client)
-	if err != nil {
-		return api.ProduceError{Topic: topic, Reason: "cannot create producer", Cause: err}
-	}
-	defer producer.Close()
-
+// producerMessage converts a ProduceRecord into a sarama message.
+func producerMessage(topic string, rec api.ProduceRecord) *sarama.ProducerMessage {
 	pm := &sarama.ProducerMessage{Topic: topic}
 	if rec.Key != nil { // nil key => null record key
 		pm.Key = sarama.ByteEncoder(rec.Key)
@@ -68,6 +166,7 @@ func doProduce(ctx context.Context, client sarama.Client, topic string, rec api.
 	}
 	if rec.Partition != nil {
 		pm.Partition = *rec.Partition
+		pm.Metadata = pinnedPartition{}
 	}
 	for _, h := range rec.Headers {
 		pm.Headers = append(pm.Headers, sarama.RecordHeader{
@@ -75,9 +174,105 @@ func doProduce(ctx context.Context, client sarama.Client, topic string, rec api.
 			Value: []byte(h.Value),
 		})
 	}
+	return pm
+}
+
+// doProduce validates the request against topic metadata and sends the record.
+// It is separated from ProduceMessage so it can be unit-tested with a fake
+// client and producer.
+func doProduce(ctx context.Context, client sarama.Client, topic string, rec api.ProduceRecord) error {
+	if err := checkProduceTarget(client, topic, rec); err != nil {
+		return err
+	}
 
-	if _, _, err := producer.SendMessage(pm); err != nil {
+	producer, err := newSyncProducer(client)
+	if err != nil {
+		return api.ProduceError{Topic: topic, Reason: "cannot create producer", Cause: err}
+	}
+	defer producer.Close()
+
+	if _, _, err := producer.SendMessage(producerMessage(topic, rec)); err != nil {
 		return api.ProduceError{Topic: topic, Reason: "send failed", Cause: err}
 	}
 	return nil
 }
+
+// doProduceBatch is ProduceBatch after the client is resolved. Like doProduce
+// it is separated for unit tests with a fake client and producer.
+func doProduceBatch(ctx context.Context, client sarama.Client, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	if err := checkProduceTarget(client, topic, recs...); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	producer, err := newSyncProducer(client)
+	if err != nil {
+		return nil, api.ProduceError{Topic: topic, Reason: "cannot create producer", Cause: err}
+	}
+	defer producer.Close()
+
+	msgs := make([]*sarama.ProducerMessage, len(recs))
+	for i, rec := range recs {
+		msgs[i] = producerMessage(topic, rec)
+	}
+	results := make([]api.ProduceResult, len(recs))
+	failAll := func(reason string, cause error) ([]api.ProduceResult, error) {
+		for i := range results {
+			results[i] = api.ProduceResult{Partition: -1, Offset: -1, Err: cause}
+		}
+		return results, api.ProduceError{Topic: topic, Reason: reason, Cause: cause}
+	}
+
+	if opts.Transactional() {
+		if err := producer.BeginTxn(); err != nil {
+			return failAll("cannot begin transaction", err)
+		}
+		if err := producer.SendMessages(msgs); err != nil {
+			if abortErr := producer.AbortTxn(); abortErr != nil {
+				err = fmt.Errorf("%w (abort failed: %v)", err, abortErr)
+			}
+			return failAll("transaction aborted", err)
+		}
+		if err := producer.CommitTxn(); err != nil {
+			_ = producer.AbortTxn()
+			return failAll("transaction commit failed", err)
+		}
+	} else if err := producer.SendMessages(msgs); err != nil {
+		var perrs sarama.ProducerErrors
+		if !errors.As(err, &perrs) {
+			return failAll("send failed", err)
+		}
+		failed := make(map[*sarama.ProducerMessage]error, len(perrs))
+		for _, pe := range perrs {
+			failed[pe.Msg] = pe.Err
+		}
+		for i, m := range msgs {
+			if ferr, ok := failed[m]; ok {
+				results[i] = api.ProduceResult{Partition: -1, Offset: -1, Err: ferr}
+				continue
+			}
+			results[i] = batchResult(m, opts)
+		}
+		return results, api.ProduceError{
+			Topic:  topic,
+			Reason: fmt.Sprintf("%d of %d records failed", len(failed), len(msgs)),
+			Cause:  perrs[0].Err,
+		}
+	}
+
+	for i, m := range msgs {
+		results[i] = batchResult(m, opts)
+	}
+	return results, nil
+}
+
+// batchResult reads the partition and offset sarama filled in after a send.
+// Without acks the broker never reports an offset.
+func batchResult(m *sarama.ProducerMessage, opts api.ProducerOptions) api.ProduceResult {
+	if opts.Acks == api.AcksNone {
+		return api.ProduceResult{Partition: m.Partition, Offset: -1}
+	}
+	return api.ProduceResult{Partition: m.Partition, Offset: m.Offset}
+}
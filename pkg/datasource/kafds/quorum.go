@@ -0,0 +1,256 @@
+package kafds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+	"github.com/IBM/sarama"
+)
+
+// sarama v1.45.1 has no DescribeQuorum, so it is hand-encoded like
+// AlterReplicaLogDirs. Since Kafka 3.3 every KRaft broker forwards it to the
+// active controller; brokers advertise it in ApiVersions only in KRaft mode.
+
+const (
+	apiKeyDescribeQuorum int16 = 55
+	metadataTopic              = "__cluster_metadata"
+)
+
+// DescribeQuorum implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) DescribeQuorum(_ context.Context) (api.QuorumInfo, error) {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return api.QuorumInfo{}, err
+	}
+	defer admin.Close()
+	brokers, controllerID, err := admin.DescribeCluster()
+	if err != nil {
+		return api.QuorumInfo{}, fmt.Errorf("describing cluster: %w", err)
+	}
+	return kp.describeQuorumVia(brokers, controllerID)
+}
+
+// describeQuorumVia asks the reported controller (or any broker) for the
+// quorum state.
+func (kp KafkaDataSourceKaf) describeQuorumVia(brokers []*sarama.Broker, controllerID int32) (api.QuorumInfo, error) {
+	target := probeBroker(brokers, controllerID)
+	if target == nil {
+		return api.QuorumInfo{}, api.NewConnectionError("no broker available for DescribeQuorum")
+	}
+	cfg, err := kp.getConfig()
+	if err != nil {
+		return api.QuorumInfo{}, err
+	}
+	return describeQuorum(cfg, target.Addr(), target.ID())
+}
+
+// probeBroker prefers the controller reported by DescribeCluster and falls
+// back to the first broker.
+func probeBroker(brokers []*sarama.Broker, controllerID int32) *sarama.Broker {
+	for _, b := range brokers {
+		if b.ID() == controllerID {
+			return b
+		}
+	}
+	if len(brokers) > 0 {
+		return brokers[0]
+	}
+	return nil
+}
+
+// describeQuorum is DescribeQuorum after the broker address and config are
+// resolved.
+func describeQuorum(cfg *sarama.Config, addr string, brokerID int32) (api.QuorumInfo, error) {
+	bc, err := openBrokerConn(cfg, addr, brokerID, "DescribeQuorum")
+	if err != nil {
+		return api.QuorumInfo{}, err
+	}
+	defer bc.Close()
+
+	versions, err := bc.apiVersions()
+	if err != nil {
+		return api.QuorumInfo{}, api.NewConnectionErrorWithCause(fmt.Sprintf("ApiVersions on broker %d", brokerID), err)
+	}
+	maxVersion, ok := versions[apiKeyDescribeQuorum]
+	if !ok {
+		return api.QuorumInfo{}, api.NotSupportedError{Operation: "DescribeQuorum"}
+	}
+	version := min(maxVersion, 1)
+
+	resp, err := bc.roundTripFlexible(apiKeyDescribeQuorum, version, encodeDescribeQuorumRequest())
+	if err != nil {
+		return api.QuorumInfo{}, api.NewConnectionErrorWithCause(fmt.Sprintf("DescribeQuorum on broker %d", brokerID), err)
+	}
+	return decodeDescribeQuorumResponse(resp, version)
+}
+
+// encodeDescribeQuorumRequest encodes a DescribeQuorum v0/v1 body (flexible)
+// for the metadata log partition:
+//
+//	topics => topic_name [partitions] _tags
+//	  partitions => partition_index _tags
+//	_tags
+func encodeDescribeQuorumRequest() []byte {
+	var e wireEncoder
+	e.putCompactArrayLen(1)
+	e.putCompactString(metadataTopic)
+	e.putCompactArrayLen(1)
+	e.putInt32(0)
+	e.putEmptyTags()
+	e.putEmptyTags()
+	e.putEmptyTags()
+	return e.buf
+}
+
+// decodeDescribeQuorumResponse decodes a DescribeQuorum v0/v1 body:
+//
+//	error_code [topics] _tags
+//	  topics => topic_name [partitions] _tags
+//	    partitions => partition_index error_code leader_id leader_epoch
+//	                  high_watermark [current_voters] [observers] _tags
+//	      replica => replica_id log_end_offset
+//	                 last_fetch_timestamp (v1+) last_caught_up_timestamp (v1+) _tags
+func decodeDescribeQuorumResponse(body []byte, version int16) (api.QuorumInfo, error) {
+	d := wireDecoder{buf: body}
+	code := sarama.KError(d.int16())
+	var (
+		info    api.QuorumInfo
+		found   bool
+		partErr sarama.KError
+	)
+	for i, n := 0, d.compactArrayLen(); i < n; i++ {
+		topic := d.compactString()
+		for j, m := 0, d.compactArrayLen(); j < m; j++ {
+			var p api.QuorumInfo
+			idx := d.int32()
+			pcode := sarama.KError(d.int16())
+			p.LeaderID = d.int32()
+			p.LeaderEpoch = d.int32()
+			p.HighWatermark = d.int64()
+			p.Voters = decodeQuorumReplicas(&d, version)
+			p.Observers = decodeQuorumReplicas(&d, version)
+			d.skipTags()
+			if topic == metadataTopic && idx == 0 {
+				info, found, partErr = p, true, pcode
+			}
+		}
+		d.skipTags()
+	}
+	d.skipTags()
+	if d.err != nil {
+		return api.QuorumInfo{}, fmt.Errorf("decoding DescribeQuorum response: %w", d.err)
+	}
+	if code == sarama.ErrNoError {
+		code = partErr
+	}
+	switch {
+	case code == sarama.ErrClusterAuthorizationFailed:
+		return api.QuorumInfo{}, api.NewAuthorizationError(code.Error(), "cluster", "Describe")
+	case code != sarama.ErrNoError:
+		return api.QuorumInfo{}, fmt.Errorf("DescribeQuorum: %w", code)
+	case !found:
+		return api.QuorumInfo{}, fmt.Errorf("DescribeQuorum returned no %s-0 partition", metadataTopic)
+	}
+	fillQuorumLag(&info)
+	return info, nil
+}
+
+func decodeQuorumReplicas(d *wireDecoder, version int16) []api.QuorumReplica {
+	n := d.compactArrayLen()
+	out := make([]api.QuorumReplica, 0, n)
+	for i := 0; i < n; i++ {
+		r := api.QuorumReplica{ReplicaID: d.int32(), LogEndOffset: d.int64()}
+		if version >= 1 {
+			r.LastFetch = quorumTime(d.int64())
+			r.LastCaughtUp = quorumTime(d.int64())
+		}
+		d.skipTags()
+		out = append(out, r)
+	}
+	return out
+}
+
+// quorumTime converts a DescribeQuorum epoch-millis timestamp; -1 is unknown.
+func quorumTime(ms int64) time.Time {
+	if ms < 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// fillQuorumLag sets each replica's lag behind the leader's log end offset
+// (the high watermark when the leader is not among the voters).
+func fillQuorumLag(q *api.QuorumInfo) {
+	leaderLEO := q.HighWatermark
+	for _, v := range q.Voters {
+		if v.ReplicaID == q.LeaderID {
+			leaderLEO = v.LogEndOffset
+		}
+	}
+	for _, rs := range [][]api.QuorumReplica{q.Voters, q.Observers} {
+		for i := range rs {
+			rs[i].Lag = max(leaderLEO-rs[i].LogEndOffset, 0)
+		}
+	}
+}
+
+// detectCoordination reports whether the active cluster runs on KRaft or
+// ZooKeeper ("KRaft" | "ZooKeeper" | "Unknown", the BrokerSummary vocabulary),
+// with the quorum for KRaft clusters that answer DescribeQuorum. Clusters that
+// don't fall back to the brokers' own configuration. The mode is cached on the
+// session for coordinationTTL; until then calls only open a broker connection
+// when withQuorum asks for the quorum of a KRaft cluster that supports
+// DescribeQuorum.
+func (kp KafkaDataSourceKaf) detectCoordination(admin ClusterAdminInterface, brokers []*sarama.Broker, controllerID int32, withQuorum bool) (string, *api.QuorumInfo) {
+	mode, noQuorum := kp.sess().cachedCoordination()
+	if mode == "ZooKeeper" || mode == "KRaft" && (!withQuorum || noQuorum) {
+		return mode, nil
+	}
+	q, err := kp.describeQuorumVia(brokers, controllerID)
+	if err == nil {
+		kp.sess().setCoordination("KRaft", false)
+		return "KRaft", &q
+	}
+	if mode != "" {
+		// Known KRaft cluster; the quorum is just unavailable right now.
+		return mode, nil
+	}
+	shared.Log.Debug("DescribeQuorum unavailable, falling back to broker config", "err", err)
+	mode = coordinationFromConfig(admin, probeBroker(brokers, controllerID))
+	if mode != "Unknown" {
+		var ns api.NotSupportedError
+		kp.sess().setCoordination(mode, errors.As(err, &ns))
+	}
+	return mode, nil
+}
+
+// coordinationFromConfig infers the mode from broker configuration: KRaft
+// brokers set process.roles, ZooKeeper brokers set zookeeper.connect.
+func coordinationFromConfig(admin ClusterAdminInterface, b *sarama.Broker) string {
+	if b == nil {
+		return "Unknown"
+	}
+	entries, err := admin.DescribeConfig(sarama.ConfigResource{
+		Type:        sarama.BrokerResource,
+		Name:        strconv.Itoa(int(b.ID())),
+		ConfigNames: []string{"process.roles", "zookeeper.connect"},
+	})
+	if err != nil {
+		return "Unknown"
+	}
+	mode := "Unknown"
+	for _, e := range entries {
+		switch {
+		case e.Name == "process.roles" && e.Value != "":
+			return "KRaft"
+		case e.Name == "zookeeper.connect" && e.Value != "":
+			mode = "ZooKeeper"
+		}
+	}
+	return mode
+}
@@ -0,0 +1,178 @@
+package kafds
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	describeQuorumRequestFrame = `
+		0000002c                   # size 44
+		0037 0001 00000007         # api key 55, v1, correlation id 7
+		0005 6b61667569            # client id "kafui"
+		00                         # header tags
+		02                         # topics (compact, 1)
+		13 5f5f636c75737465725f6d65746164617461 # "__cluster_metadata"
+		02                         # partitions (compact, 1)
+		00000000 00                # partition 0, tags
+		00 00                      # topic tags, request tags`
+
+	describeQuorumResponseBody = `
+		0000                       # error_code
+		02                         # topics
+		13 5f5f636c75737465725f6d65746164617461 # "__cluster_metadata"
+		02                         # partitions
+		00000000 0000              # partition 0, NONE
+		00000001 0000000c          # leader 1, epoch 12
+		00000000000001f4           # high watermark 500
+		04                         # current_voters (3)
+		00000001 00000000000001f6 00000000000003e8 00000000000003e8 00 # 1: LEO 502
+		00000002 00000000000001f6 00000000000003e8 00000000000003e8 00 # 2: LEO 502
+		00000003 00000000000001e0 00000000000003e8 ffffffffffffffff 00 # 3: LEO 480, never caught up
+		02                         # observers (1)
+		00000004 00000000000001c2 ffffffffffffffff ffffffffffffffff 00 # 4: LEO 450
+		00 00 00                   # partition, topic, response tags`
+
+	// ApiVersions v0 replies: with DescribeQuorum (0..1) and without it.
+	apiVersionsKRaft = `0000 00000001 0037 0000 0001`
+	apiVersionsZK    = `0000 00000001 0012 0000 0003`
+)
+
+func TestEncodeDescribeQuorumRequest(t *testing.T) {
+	got := requestFrame(apiKeyDescribeQuorum, 1, 7, "kafui", append([]byte{0}, encodeDescribeQuorumRequest()...))
+	assert.Equal(t, unhex(t, stripComments(describeQuorumRequestFrame)), got)
+}
+
+func TestDecodeDescribeQuorumResponse(t *testing.T) {
+	q, err := decodeDescribeQuorumResponse(unhex(t, stripComments(describeQuorumResponseBody)), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), q.LeaderID)
+	assert.Equal(t, int32(12), q.LeaderEpoch)
+	assert.Equal(t, int64(500), q.HighWatermark)
+	require.Len(t, q.Voters, 3)
+	require.Len(t, q.Observers, 1)
+
+	// Lag is measured against the leader's log end offset (502).
+	assert.Equal(t, []int64{0, 0, 22}, []int64{q.Voters[0].Lag, q.Voters[1].Lag, q.Voters[2].Lag})
+	assert.Equal(t, int64(52), q.Observers[0].Lag)
+	assert.Equal(t, time.UnixMilli(1000), q.Voters[2].LastFetch)
+	assert.True(t, q.Voters[2].LastCaughtUp.IsZero())
+	assert.Equal(t, "observer", q.Role(4))
+
+	t.Run("truncated", func(t *testing.T) {
+		full := unhex(t, stripComments(describeQuorumResponseBody))
+		_, err := decodeDescribeQuorumResponse(full[:len(full)-4], 1)
+		assert.ErrorIs(t, err, errShortResponse)
+	})
+
+	t.Run("cluster authorization", func(t *testing.T) {
+		_, err := decodeDescribeQuorumResponse(unhex(t, "001f 01 00"), 1)
+		var ae api.AuthorizationError
+		assert.ErrorAs(t, err, &ae)
+	})
+
+	t.Run("partition error", func(t *testing.T) {
+		body := unhex(t, stripComments(describeQuorumResponseBody))
+		body[2+1+19+1+4+1] = 0x06 // low byte of the partition error_code => NOT_LEADER_OR_FOLLOWER
+		_, err := decodeDescribeQuorumResponse(body, 1)
+		assert.ErrorIs(t, err, sarama.ErrNotLeaderForPartition)
+	})
+}
+
+func TestDescribeQuorum(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockBrokers:      []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092")},
+		MockControllerID: 2,
+	}
+	// The response header carries an empty tag section before the body.
+	reply := append([]byte{0}, unhex(t, stripComments(describeQuorumResponseBody))...)
+
+	t.Run("asks the controller", func(t *testing.T) {
+		keys := withBrokerPipe(t, "b2:9092", unhex(t, apiVersionsKRaft), reply)
+		q, err := mockAdminDS(admin).DescribeQuorum(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), q.LeaderID)
+		assert.Equal(t, []int16{apiKeyApiVersions, apiKeyDescribeQuorum}, collectKeys(keys))
+	})
+
+	t.Run("not advertised", func(t *testing.T) {
+		withBrokerPipe(t, "b2:9092", unhex(t, apiVersionsZK))
+		_, err := mockAdminDS(admin).DescribeQuorum(context.Background())
+		var ns api.NotSupportedError
+		assert.ErrorAs(t, err, &ns)
+	})
+}
+
+func TestDetectCoordination(t *testing.T) {
+	brokers := []*sarama.Broker{newTestBroker(1, "b1:9092")}
+
+	t.Run("kraft via DescribeQuorum", func(t *testing.T) {
+		reply := append([]byte{0}, unhex(t, stripComments(describeQuorumResponseBody))...)
+		withBrokerPipe(t, "b1:9092", unhex(t, apiVersionsKRaft), reply)
+		admin := &MockClusterAdmin{MockBrokers: brokers}
+		mode, q := mockAdminDS(admin).detectCoordination(admin, brokers, 1, true)
+		assert.Equal(t, "KRaft", mode)
+		require.NotNil(t, q)
+		assert.Len(t, q.Voters, 3)
+	})
+
+	tests := []struct {
+		name    string
+		entries []sarama.ConfigEntry
+		want    string
+	}{
+		{"zookeeper.connect", []sarama.ConfigEntry{{Name: "zookeeper.connect", Value: "zk:2181"}, {Name: "process.roles"}}, "ZooKeeper"},
+		{"pre-3.3 kraft", []sarama.ConfigEntry{{Name: "process.roles", Value: "broker,controller"}}, "KRaft"},
+		{"nothing set", nil, "Unknown"},
+	}
+	for _, tt := range tests {
+		t.Run("fallback "+tt.name, func(t *testing.T) {
+			withBrokerPipe(t, "b1:9092", unhex(t, apiVersionsZK))
+			admin := &MockClusterAdmin{MockBrokers: brokers, MockConfigEntries: tt.entries}
+			mode, q := mockAdminDS(admin).detectCoordination(admin, brokers, 1, true)
+			assert.Equal(t, tt.want, mode)
+			assert.Nil(t, q)
+		})
+	}
+}
+
+func TestDetectCoordinationCachedPerSession(t *testing.T) {
+	brokers := []*sarama.Broker{newTestBroker(1, "b1:9092")}
+	dials := 0
+	orig := dialBroker
+	t.Cleanup(func() { dialBroker = orig })
+	dialBroker = func(*sarama.Config, string) (net.Conn, error) {
+		dials++
+		client, server := net.Pipe()
+		serveBroker(server, unhex(t, apiVersionsZK))
+		return client, nil
+	}
+
+	admin := &MockClusterAdmin{MockBrokers: brokers, MockConfigEntries: []sarama.ConfigEntry{{Name: "zookeeper.connect", Value: "zk:2181"}}}
+	ds := mockAdminDS(admin)
+	for i := 0; i < 3; i++ {
+		mode, _ := ds.detectCoordination(admin, brokers, 1, i%2 == 0)
+		assert.Equal(t, "ZooKeeper", mode)
+	}
+	assert.Equal(t, 1, dials, "the mode is probed once per session")
+
+	ds.session.invalidate()
+	ds.detectCoordination(admin, brokers, 1, false)
+	assert.Equal(t, 2, dials, "a context switch probes again")
+
+	// A cluster migrated to KRaft is noticed once the cached mode expires.
+	admin.MockConfigEntries = []sarama.ConfigEntry{{Name: "process.roles", Value: "broker"}}
+	mode, _ := ds.detectCoordination(admin, brokers, 1, false)
+	assert.Equal(t, "ZooKeeper", mode, "still cached")
+	ds.session.coordinationAt = ds.session.coordinationAt.Add(-coordinationTTL - time.Second)
+	mode, _ = ds.detectCoordination(admin, brokers, 1, false)
+	assert.Equal(t, "KRaft", mode)
+	assert.Equal(t, 3, dials)
+}
@@ -12,10 +12,11 @@ import (
 // client quota and orders the result by user -> client-id -> ip, with absent
 // identifiers sorted last.
 func (kp KafkaDataSourceKaf) GetClientQuotas() ([]api.ClientQuotaEntry, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 
 	entries, err := admin.DescribeClientQuotas(nil, false)
 	if err != nil {
@@ -83,10 +84,11 @@ func (kp KafkaDataSourceKaf) AlterClientQuotas(entity api.ClientQuotaEntity, quo
 		return err
 	}
 
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 
 	components := quotaEntityComponents(entity)
 
@@ -17,10 +17,9 @@ func TestGetClientQuotas_Ordering(t *testing.T) {
 			{Entity: []sarama.QuotaEntityComponent{{EntityType: sarama.QuotaEntityUser, Name: "alice"}}, Values: map[string]float64{"producer_byte_rate": 2}},
 		},
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	got, err := KafkaDataSourceKaf{}.GetClientQuotas()
+	got, err := ds.GetClientQuotas()
 	require.NoError(t, err)
 	require.Len(t, got, 3)
 	// user alice, user bob, then ip-only (user absent sorts last).
@@ -40,10 +39,9 @@ func TestAlterClientQuotas_SetAndRemove(t *testing.T) {
 			{Entity: []sarama.QuotaEntityComponent{{EntityType: sarama.QuotaEntityUser, Name: "alice"}}, Values: map[string]float64{"a": 1, "b": 2}},
 		},
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	err := KafkaDataSourceKaf{}.AlterClientQuotas(api.ClientQuotaEntity{User: strptr("alice")}, map[string]float64{"a": 10, "c": 3})
+	err := ds.AlterClientQuotas(api.ClientQuotaEntity{User: strptr("alice")}, map[string]float64{"a": 10, "c": 3})
 	require.NoError(t, err)
 
 	sets := map[string]float64{}
@@ -65,10 +63,9 @@ func TestAlterClientQuotas_EmptyMapRemovesAll(t *testing.T) {
 			{Entity: []sarama.QuotaEntityComponent{{EntityType: sarama.QuotaEntityUser, Name: "alice"}}, Values: map[string]float64{"a": 1, "b": 2}},
 		},
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	err := KafkaDataSourceKaf{}.AlterClientQuotas(api.ClientQuotaEntity{User: strptr("alice")}, nil)
+	err := ds.AlterClientQuotas(api.ClientQuotaEntity{User: strptr("alice")}, nil)
 	require.NoError(t, err)
 	require.Len(t, admin.AlterClientQuotasCalls, 2)
 	for _, call := range admin.AlterClientQuotasCalls {
@@ -78,10 +75,9 @@ func TestAlterClientQuotas_EmptyMapRemovesAll(t *testing.T) {
 
 func TestAlterClientQuotas_NoEntity(t *testing.T) {
 	admin := &MockClusterAdmin{}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
-	err := KafkaDataSourceKaf{}.AlterClientQuotas(api.ClientQuotaEntity{}, map[string]float64{"a": 1})
+	err := ds.AlterClientQuotas(api.ClientQuotaEntity{}, map[string]float64{"a": 1})
 	var qe api.QuotaValidationError
 	assert.ErrorAs(t, err, &qe)
 	assert.Empty(t, admin.AlterClientQuotasCalls)
@@ -73,18 +73,7 @@ func increaseReplicas(replicas []int32, onlineBrokers []int32, onlineSet map[int
 		assigned[b] = true
 	}
 	for len(out) < newFactor {
-		best := int32(-1)
-		bestLoad := int(^uint(0) >> 1)
-		// Iterate a sorted copy for deterministic tie-breaking by broker id.
-		for _, b := range sortedBrokers(onlineBrokers) {
-			if assigned[b] {
-				continue
-			}
-			if load[b] < bestLoad {
-				best = b
-				bestLoad = load[b]
-			}
-		}
+		best := leastLoaded(onlineBrokers, assigned, load)
 		if best < 0 {
 			break // no eligible online broker left
 		}
@@ -94,6 +83,24 @@ func increaseReplicas(replicas []int32, onlineBrokers []int32, onlineSet map[int
 	return out
 }
 
+// leastLoaded returns the eligible broker with the lowest load that is not in
+// exclude, or -1 when none is left.
+func leastLoaded(eligible []int32, exclude map[int32]bool, load map[int32]int) int32 {
+	best := int32(-1)
+	bestLoad := int(^uint(0) >> 1)
+	// Iterate a sorted copy for deterministic tie-breaking by broker id.
+	for _, b := range sortedBrokers(eligible) {
+		if exclude[b] {
+			continue
+		}
+		if load[b] < bestLoad {
+			best = b
+			bestLoad = load[b]
+		}
+	}
+	return best
+}
+
 // decreaseReplicas removes the most-loaded non-leader replicas until newFactor is
 // reached, always keeping the leader.
 func decreaseReplicas(replicas []int32, leader int32, load map[int32]int, newFactor int) []int32 {
@@ -124,8 +131,150 @@ func decreaseReplicas(replicas []int32, leader int32, load map[int32]int, newFac
 	return out
 }
 
+func replicaSet(rs []int32) map[int32]bool {
+	m := make(map[int32]bool, len(rs))
+	for _, r := range rs {
+		m[r] = true
+	}
+	return m
+}
+
 func sortedBrokers(brokers []int32) []int32 {
 	s := append([]int32{}, brokers...)
 	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
 	return s
 }
+
+// computeDecommission replaces every replica hosted on a drained broker with
+// the least-loaded remaining broker not already hosting the partition. Replica
+// positions are kept, so a drained preferred leader is replaced in place. Load
+// is seeded with the cluster-wide replica counts (others holds the replicas of
+// partitions outside current) so the drained replicas land on the emptiest
+// brokers. The result is parallel to current.
+func computeDecommission(current [][]int32, brokers, drain []int32, others map[int32]int) ([][]int32, error) {
+	drained := replicaSet(drain)
+	var remaining []int32
+	for _, b := range brokers {
+		if !drained[b] {
+			remaining = append(remaining, b)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil, fmt.Errorf("no brokers would remain")
+	}
+	load := make(map[int32]int, len(remaining))
+	for _, b := range remaining {
+		load[b] = others[b]
+	}
+	for _, replicas := range current {
+		if len(replicas) > len(remaining) {
+			return nil, fmt.Errorf("a replication factor of %d needs more than the %d remaining broker(s)", len(replicas), len(remaining))
+		}
+		for _, b := range replicas {
+			if !drained[b] {
+				load[b]++
+			}
+		}
+	}
+
+	result := make([][]int32, len(current))
+	for i, replicas := range current {
+		out := append([]int32{}, replicas...)
+		hosting := replicaSet(out)
+		for j, b := range out {
+			if !drained[b] {
+				continue
+			}
+			nb := leastLoaded(remaining, hosting, load)
+			out[j] = nb
+			hosting[nb] = true
+			load[nb]++
+		}
+		result[i] = out
+	}
+	return result, nil
+}
+
+// computeRebalance evens out replicas, then preferred leaders, across brokers.
+// A replica moves from its broker to the least-loaded broker not hosting the
+// partition while that lowers the gap by more than one; afterwards preferred
+// leaders (the first replica) are swapped within each replica list the same
+// way. Replica load is seeded with others, the replicas of partitions outside
+// current, so brokers already busy with other topics receive fewer moves.
+// Replicas on brokers outside the list are left in place. The result is
+// parallel to current.
+func computeRebalance(current [][]int32, brokers []int32, others map[int32]int) [][]int32 {
+	result := make([][]int32, len(current))
+	known := replicaSet(brokers)
+	load := make(map[int32]int, len(brokers))
+	for _, b := range brokers {
+		load[b] = others[b]
+	}
+	leaders := make(map[int32]int, len(brokers))
+	for i, replicas := range current {
+		result[i] = append([]int32{}, replicas...)
+		for _, b := range replicas {
+			load[b]++
+		}
+		if len(replicas) > 0 {
+			leaders[replicas[0]]++
+		}
+	}
+
+	// Every move strictly reduces the spread, so this converges; the bound
+	// only guards against surprises.
+	for pass := 0; pass < len(current)+1; pass++ {
+		moved := false
+		for i, replicas := range result {
+			hosting := replicaSet(replicas)
+			for j, b := range replicas {
+				if !known[b] {
+					continue
+				}
+				nb := leastLoaded(brokers, hosting, load)
+				if nb < 0 || load[b]-load[nb] <= 1 {
+					continue
+				}
+				result[i][j] = nb
+				delete(hosting, b)
+				hosting[nb] = true
+				load[b]--
+				load[nb]++
+				if j == 0 {
+					leaders[b]--
+					leaders[nb]++
+				}
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+
+	for pass := 0; pass < len(current)+1; pass++ {
+		moved := false
+		for _, replicas := range result {
+			if len(replicas) < 2 || !known[replicas[0]] {
+				continue
+			}
+			best := 0
+			for j := 1; j < len(replicas); j++ {
+				if known[replicas[j]] && leaders[replicas[j]] < leaders[replicas[best]] {
+					best = j
+				}
+			}
+			if leaders[replicas[0]]-leaders[replicas[best]] <= 1 {
+				continue
+			}
+			leaders[replicas[0]]--
+			leaders[replicas[best]]++
+			replicas[0], replicas[best] = replicas[best], replicas[0]
+			moved = true
+		}
+		if !moved {
+			break
+		}
+	}
+	return result
+}
@@ -7,14 +7,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func replicaSet(rs []int32) map[int32]bool {
-	m := make(map[int32]bool, len(rs))
-	for _, r := range rs {
-		m[r] = true
-	}
-	return m
-}
-
 func TestComputeReassignment_Validation(t *testing.T) {
 	current := [][]int32{{1, 2}, {2, 3}}
 	leaders := []int32{1, 2}
@@ -102,3 +94,66 @@ func TestComputeReassignment_OfflineBrokerExcluded(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeDecommission(t *testing.T) {
+	current := [][]int32{{1, 2}, {2, 3}, {3, 1}, {3, 2}}
+	brokers := []int32{1, 2, 3, 4}
+
+	result, err := computeDecommission(current, brokers, []int32{3}, nil)
+	require.NoError(t, err)
+	require.Len(t, result, 4)
+	for i, rs := range result {
+		assert.NotContains(t, rs, int32(3), "partition %d still on the drained broker", i)
+		assert.Len(t, replicaSet(rs), len(current[i]), "partition %d lost or duplicated a replica", i)
+	}
+	// Undrained replicas keep their position; broker 4 starts empty and takes
+	// the drained replicas first.
+	assert.Equal(t, []int32{1, 2}, result[0])
+	assert.Equal(t, []int32{2, 4}, result[1])
+	assert.Equal(t, []int32{4, 1}, result[2])
+
+	t.Run("nothing remains", func(t *testing.T) {
+		_, err := computeDecommission(current, []int32{3}, []int32{3}, nil)
+		assert.Error(t, err)
+	})
+	t.Run("factor above remaining brokers", func(t *testing.T) {
+		_, err := computeDecommission(current, []int32{1, 2, 3}, []int32{2, 3}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestComputeRebalance(t *testing.T) {
+	// Everything on brokers 1 and 2; broker 3 was just added.
+	current := [][]int32{{1, 2}, {1, 2}, {1, 2}, {2, 1}, {1, 2}, {2, 1}}
+	brokers := []int32{1, 2, 3}
+
+	result := computeRebalance(current, brokers, nil)
+	load := map[int32]int{}
+	leaders := map[int32]int{}
+	for i, rs := range result {
+		assert.Len(t, replicaSet(rs), 2, "partition %d", i)
+		for _, b := range rs {
+			load[b]++
+		}
+		leaders[rs[0]]++
+	}
+	for _, b := range brokers {
+		assert.InDelta(t, 4, load[b], 1, "broker %d replicas", b)
+		assert.InDelta(t, 2, leaders[b], 1, "broker %d leaders", b)
+	}
+	assert.Equal(t, [][]int32{{1, 2}, {1, 2}, {1, 2}, {2, 1}, {1, 2}, {2, 1}}, current, "input must not be modified")
+
+	t.Run("balanced layout unchanged", func(t *testing.T) {
+		balanced := [][]int32{{1, 2}, {2, 3}, {3, 1}}
+		assert.Equal(t, balanced, computeRebalance(balanced, brokers, nil))
+	})
+
+	t.Run("load from other topics counts", func(t *testing.T) {
+		// Broker 3 is empty for these topics but already hosts 6 replicas of
+		// others, so nothing should move onto it.
+		result := computeRebalance(current, brokers, map[int32]int{3: 6})
+		for i, rs := range result {
+			assert.NotContains(t, rs, int32(3), "partition %d", i)
+		}
+	})
+}
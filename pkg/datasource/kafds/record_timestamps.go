@@ -0,0 +1,103 @@
+package kafds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// recordFetchTimeout bounds the wait for the record at an offset. The offset
+// is below the high-water mark, so the fetch normally returns immediately.
+const recordFetchTimeout = 10 * time.Second
+
+// recordTimestampReader looks up the timestamp of the record at an offset. It
+// is a seam so tests can fake the fetches.
+type recordTimestampReader interface {
+	GetOffset(topic string, partition int32, time int64) (int64, error)
+	TimestampAt(ctx context.Context, topic string, partition int32, offset int64) (time.Time, error)
+	Close() error
+}
+
+// newRecordTimestampReader builds a reader backed by a Sarama consumer.
+// Replaceable in tests.
+var newRecordTimestampReader = func(kp KafkaDataSourceKaf) (recordTimestampReader, error) {
+	client, err := kp.getClient()
+	if err != nil {
+		return nil, err
+	}
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &saramaTimestampReader{client: client, consumer: consumer}, nil
+}
+
+type saramaTimestampReader struct {
+	client   sarama.Client
+	consumer sarama.Consumer
+}
+
+func (r *saramaTimestampReader) GetOffset(topic string, partition int32, t int64) (int64, error) {
+	return r.client.GetOffset(topic, partition, t)
+}
+
+func (r *saramaTimestampReader) TimestampAt(ctx context.Context, topic string, partition int32, offset int64) (time.Time, error) {
+	pc, err := r.consumer.ConsumePartition(topic, partition, offset)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading %s/%d at offset %d: %w", topic, partition, offset, err)
+	}
+	defer pc.Close()
+	select {
+	case msg := <-pc.Messages():
+		return msg.Timestamp, nil
+	case cerr := <-pc.Errors():
+		return time.Time{}, fmt.Errorf("reading %s/%d at offset %d: %w", topic, partition, offset, cerr)
+	case <-time.After(recordFetchTimeout):
+		return time.Time{}, fmt.Errorf("reading %s/%d at offset %d: timed out", topic, partition, offset)
+	case <-ctx.Done():
+		return time.Time{}, ctx.Err()
+	}
+}
+
+func (r *saramaTimestampReader) Close() error {
+	_ = r.consumer.Close()
+	return r.client.Close()
+}
+
+// GetRecordTimestamps implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) GetRecordTimestamps(ctx context.Context, topic string, offsets map[int32]int64) (map[int32]time.Time, error) {
+	r, err := newRecordTimestampReader(kp)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readRecordTimestamps(ctx, r, topic, offsets)
+}
+
+// readRecordTimestamps skips offsets at or past the log end (no record yet)
+// and below the log start (the record is gone) and fetches the rest.
+func readRecordTimestamps(ctx context.Context, r recordTimestampReader, topic string, offsets map[int32]int64) (map[int32]time.Time, error) {
+	out := make(map[int32]time.Time, len(offsets))
+	for p, off := range offsets {
+		end, err := r.GetOffset(topic, p, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("getting end offset of %s/%d: %w", topic, p, err)
+		}
+		start, err := r.GetOffset(topic, p, sarama.OffsetOldest)
+		if err != nil {
+			return nil, fmt.Errorf("getting start offset of %s/%d: %w", topic, p, err)
+		}
+		if off >= end || off < start {
+			continue
+		}
+		ts, err := r.TimestampAt(ctx, topic, p, off)
+		if err != nil {
+			return nil, err
+		}
+		out[p] = ts
+	}
+	return out, nil
+}
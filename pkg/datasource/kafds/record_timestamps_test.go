@@ -0,0 +1,38 @@
+package kafds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTimestampReader serves offsets from fakeResetter and derives a record's
+// timestamp from its offset.
+type fakeTimestampReader struct {
+	fakeResetter
+	base    time.Time
+	fetched []int64
+}
+
+func (f *fakeTimestampReader) TimestampAt(_ context.Context, _ string, _ int32, offset int64) (time.Time, error) {
+	f.fetched = append(f.fetched, offset)
+	return f.base.Add(time.Duration(offset) * time.Second), nil
+}
+
+func TestReadRecordTimestamps_SkipsOffsetsOutsideTheLog(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := &fakeTimestampReader{
+		fakeResetter: fakeResetter{
+			oldest: map[string]map[int32]int64{"t1": {0: 0, 1: 0, 2: 40}},
+			newest: map[string]map[int32]int64{"t1": {0: 100, 1: 100, 2: 100}},
+		},
+		base: base,
+	}
+
+	got, err := readRecordTimestamps(context.Background(), r, "t1", map[int32]int64{0: 10, 1: 100, 2: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]time.Time{0: base.Add(10 * time.Second)}, got)
+	assert.Equal(t, []int64{10}, r.fetched, "end-of-log and truncated offsets are not fetched")
+}
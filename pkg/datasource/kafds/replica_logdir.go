@@ -0,0 +1,142 @@
+package kafds
+
+import (
+	"fmt"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+)
+
+// sarama v1.45.1 only defines the API-key constant for AlterReplicaLogDirs, so
+// the request is encoded by hand and sent over a raw broker connection
+// (broker_conn.go): it must reach the broker that owns the replica.
+
+const apiKeyAlterReplicaLogDirs int16 = 34
+
+// AlterReplicaLogDir implements api.KafkaDataSource by sending an
+// AlterReplicaLogDirs request to the broker holding the replica. The broker
+// acknowledges once the future replica is created in the target directory; the
+// data copy then continues in the background and shows up in the log-dir
+// listing when it completes.
+func (kp KafkaDataSourceKaf) AlterReplicaLogDir(brokerID int32, topic string, partition int32, logDir string) error {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+	brokers, _, err := admin.DescribeCluster()
+	if err != nil {
+		return fmt.Errorf("describing cluster: %w", err)
+	}
+	var addr string
+	for _, b := range brokers {
+		if b.ID() == brokerID {
+			addr = b.Addr()
+		}
+	}
+	if addr == "" {
+		return api.BrokerNotFoundError{BrokerID: brokerID}
+	}
+
+	cfg, err := kp.getConfig()
+	if err != nil {
+		return err
+	}
+	return alterReplicaLogDir(cfg, addr, brokerID, topic, partition, logDir)
+}
+
+// alterReplicaLogDir is AlterReplicaLogDir after the broker address and config
+// are resolved.
+func alterReplicaLogDir(cfg *sarama.Config, addr string, brokerID int32, topic string, partition int32, logDir string) error {
+	bc, err := openBrokerConn(cfg, addr, brokerID, "AlterReplicaLogDir")
+	if err != nil {
+		return err
+	}
+	defer bc.Close()
+
+	// v0 and v1 share a schema; v1 only changes throttling behaviour (2.0+).
+	version := int16(0)
+	if cfg.Version.IsAtLeast(sarama.V2_0_0_0) {
+		version = 1
+	}
+	resp, err := bc.roundTrip(apiKeyAlterReplicaLogDirs, version, encodeAlterReplicaLogDirsRequest(logDir, topic, partition))
+	if err != nil {
+		return api.NewConnectionErrorWithCause(fmt.Sprintf("AlterReplicaLogDirs on broker %d", brokerID), err)
+	}
+	results, err := decodeAlterReplicaLogDirsResponse(resp)
+	if err != nil {
+		return fmt.Errorf("decoding AlterReplicaLogDirs response: %w", err)
+	}
+	for _, r := range results {
+		if r.Topic == topic && r.Partition == partition {
+			return replicaLogDirError(r.Err, brokerID, topic, partition, logDir)
+		}
+	}
+	return fmt.Errorf("broker %d returned no result for %s-%d", brokerID, topic, partition)
+}
+
+// replicaLogDirError maps a per-partition AlterReplicaLogDirs error code to the
+// typed errors the mock datasource returns for the same situations.
+func replicaLogDirError(code sarama.KError, brokerID int32, topic string, partition int32, logDir string) error {
+	switch code {
+	case sarama.ErrNoError:
+		return nil
+	case sarama.ErrLogDirNotFound:
+		return api.LogDirNotFoundError{Path: logDir, Cause: code}
+	case sarama.ErrUnknownTopicOrPartition, sarama.ErrReplicaNotAvailable:
+		return api.NewPartitionError(fmt.Sprintf("topic/partition not found on broker %d: %v", brokerID, code), topic, partition)
+	case sarama.ErrKafkaStorageError:
+		return fmt.Errorf("log directory %s on broker %d is offline: %w", logDir, brokerID, code)
+	case sarama.ErrClusterAuthorizationFailed:
+		return api.NewAuthorizationError(code.Error(), "cluster", "Alter")
+	default:
+		return fmt.Errorf("moving %s-%d to %s: %w", topic, partition, logDir, code)
+	}
+}
+
+// replicaLogDirResult is one partition entry of an AlterReplicaLogDirs response.
+type replicaLogDirResult struct {
+	Topic     string
+	Partition int32
+	Err       sarama.KError
+}
+
+// encodeAlterReplicaLogDirsRequest encodes an AlterReplicaLogDirs v0/v1 body
+// moving a single partition:
+//
+//	dirs => path [topics]
+//	  topics => name [partitions]
+//	    partitions => int32
+func encodeAlterReplicaLogDirsRequest(logDir, topic string, partition int32) []byte {
+	var e wireEncoder
+	e.putArrayLen(1)
+	e.putString(logDir)
+	e.putArrayLen(1)
+	e.putString(topic)
+	e.putArrayLen(1)
+	e.putInt32(partition)
+	return e.buf
+}
+
+// decodeAlterReplicaLogDirsResponse decodes an AlterReplicaLogDirs v0/v1 body:
+//
+//	throttle_time_ms [results]
+//	  results => topic_name [partitions]
+//	    partitions => partition_index error_code
+func decodeAlterReplicaLogDirsResponse(body []byte) ([]replicaLogDirResult, error) {
+	d := wireDecoder{buf: body}
+	d.int32() // throttle_time_ms
+	var out []replicaLogDirResult
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		topic := d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			p := d.int32()
+			code := sarama.KError(d.int16())
+			out = append(out, replicaLogDirResult{Topic: topic, Partition: p, Err: code})
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return out, nil
+}
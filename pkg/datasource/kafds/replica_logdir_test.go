@@ -0,0 +1,296 @@
+package kafds
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unhex decodes a fixture written as space/newline separated hex.
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	require.NoError(t, err)
+	return b
+}
+
+// Wire fixtures for moving orders-3 to /data/kafka-2 on a Kafka 2.0+ broker,
+// laid out field by field as in the protocol guide. They were not captured
+// from a broker; TestAlterReplicaLogDirsRequestMatchesSarama checks the
+// framing and topic section against bytes sarama writes on a socket.
+const (
+	alterLogDirsRequestFrame = `
+		00000036                   # size 54
+		0022 0001 00000007         # api key 34, v1, correlation id 7
+		0005 6b61667569            # client id "kafui"
+		00000001                   # dirs
+		000d 2f646174612f6b61666b612d32 # "/data/kafka-2"
+		00000001                   # topics
+		0006 6f7264657273          # "orders"
+		00000001 00000003          # partitions [3]`
+
+	alterLogDirsResponseBody = `
+		00000000                   # throttle_time_ms
+		00000001                   # results
+		0006 6f7264657273          # "orders"
+		00000002                   # partitions
+		00000003 0000              # 3 => NONE
+		00000004 0039              # 4 => LOG_DIR_NOT_FOUND`
+)
+
+// stripComments drops the "# ..." annotations from a fixture.
+func stripComments(s string) string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+func TestEncodeAlterReplicaLogDirsRequest(t *testing.T) {
+	got := requestFrame(apiKeyAlterReplicaLogDirs, 1, 7, "kafui",
+		encodeAlterReplicaLogDirsRequest("/data/kafka-2", "orders", 3))
+	assert.Equal(t, unhex(t, stripComments(alterLogDirsRequestFrame)), got)
+}
+
+// captureSaramaFrame returns the request frame (without its size) that
+// sarama's own broker client writes when send runs, read off a local socket.
+func captureSaramaFrame(t *testing.T, send func(*sarama.Broker)) []byte {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	frames := make(chan []byte, 1)
+	go func() {
+		defer close(frames)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if f, err := readFrame(conn); err == nil {
+			frames <- f
+		}
+	}()
+
+	cfg := sarama.NewConfig()
+	cfg.ClientID = "kafui"
+	cfg.Version = sarama.V2_0_0_0
+	cfg.ApiVersionsRequest = false
+	cfg.Net.ReadTimeout = time.Second
+	b := sarama.NewBroker(ln.Addr().String())
+	require.NoError(t, b.Open(cfg))
+	defer b.Close()
+	send(b) // fails once the socket closes unanswered
+	frame, ok := <-frames
+	require.True(t, ok, "sarama sent no request")
+	return frame
+}
+
+func TestAlterReplicaLogDirsRequestMatchesSarama(t *testing.T) {
+	const apiKeyDescribeLogDirs = 35
+	frame := captureSaramaFrame(t, func(b *sarama.Broker) {
+		_, _ = b.DescribeLogDirs(&sarama.DescribeLogDirsRequest{
+			Version:        1,
+			DescribeTopics: []sarama.DescribeLogDirsRequestTopic{{Topic: "orders", PartitionIDs: []int32{3}}},
+		})
+	})
+	headerLen := 2 + 2 + 4 + 2 + len("kafui") // api key, version, correlation id, client id
+	require.Greater(t, len(frame), headerLen)
+	correlationID := int32(binary.BigEndian.Uint32(frame[4:8]))
+	topics := frame[headerLen:]
+
+	// Our framing of the same api, version and body is byte for byte sarama's.
+	assert.Equal(t, frame, requestFrame(apiKeyDescribeLogDirs, 1, correlationID, "kafui", topics)[4:])
+
+	// AlterReplicaLogDirs v1 is [dirs: path, topics] with topics encoded like
+	// DescribeLogDirs' ([name, [partition]]).
+	var dirs wireEncoder
+	dirs.putInt32(1)
+	dirs.putString("/data/kafka-2")
+	assert.Equal(t, append(dirs.buf, topics...), encodeAlterReplicaLogDirsRequest("/data/kafka-2", "orders", 3))
+}
+
+func TestDecodeAlterReplicaLogDirsResponse(t *testing.T) {
+	res, err := decodeAlterReplicaLogDirsResponse(unhex(t, stripComments(alterLogDirsResponseBody)))
+	require.NoError(t, err)
+	assert.Equal(t, []replicaLogDirResult{
+		{Topic: "orders", Partition: 3, Err: sarama.ErrNoError},
+		{Topic: "orders", Partition: 4, Err: sarama.ErrLogDirNotFound},
+	}, res)
+
+	t.Run("truncated", func(t *testing.T) {
+		full := unhex(t, stripComments(alterLogDirsResponseBody))
+		_, err := decodeAlterReplicaLogDirsResponse(full[:len(full)-1])
+		assert.ErrorIs(t, err, errShortResponse)
+	})
+
+	t.Run("absurd array length", func(t *testing.T) {
+		_, err := decodeAlterReplicaLogDirsResponse(unhex(t, "00000000 7fffffff"))
+		assert.ErrorIs(t, err, errShortResponse)
+	})
+}
+
+func TestSaslFixtures(t *testing.T) {
+	var e wireEncoder
+	e.putString("PLAIN")
+	assert.Equal(t, unhex(t, "0005 504c41494e"), e.buf)
+
+	tok := &sarama.AccessToken{Token: "tkn", Extensions: map[string]string{"b": "2", "a": "1"}}
+	assert.Equal(t, "n,,\x01auth=Bearer tkn\x01a=1\x01b=2\x01\x01", string(oauthBearerMessage(tok)))
+}
+
+func TestReplicaLogDirError(t *testing.T) {
+	assert.NoError(t, replicaLogDirError(sarama.ErrNoError, 1, "t", 0, "/d"))
+
+	var ld api.LogDirNotFoundError
+	err := replicaLogDirError(sarama.ErrLogDirNotFound, 1, "t", 0, "/d")
+	require.ErrorAs(t, err, &ld)
+	assert.Equal(t, "/d", ld.Path)
+	assert.ErrorIs(t, err, sarama.ErrLogDirNotFound)
+
+	var pe api.PartitionError
+	assert.ErrorAs(t, replicaLogDirError(sarama.ErrReplicaNotAvailable, 1, "t", 0, "/d"), &pe)
+	assert.ErrorAs(t, replicaLogDirError(sarama.ErrUnknownTopicOrPartition, 1, "t", 0, "/d"), &pe)
+
+	var ae api.AuthorizationError
+	assert.ErrorAs(t, replicaLogDirError(sarama.ErrClusterAuthorizationFailed, 1, "t", 0, "/d"), &ae)
+
+	assert.ErrorIs(t, replicaLogDirError(sarama.ErrKafkaStorageError, 1, "t", 0, "/d"), sarama.ErrKafkaStorageError)
+}
+
+// serveBroker plays the broker side of conn: for each reply it reads one
+// request frame, reports its api key, and answers with reply under the
+// request's correlation id.
+func serveBroker(conn net.Conn, replies ...[]byte) <-chan int16 {
+	keys := make(chan int16, len(replies))
+	go func() {
+		defer close(keys)
+		defer conn.Close()
+		for _, reply := range replies {
+			req, err := readFrame(conn)
+			if err != nil {
+				return
+			}
+			keys <- int16(binary.BigEndian.Uint16(req))
+			var e wireEncoder
+			e.putInt32(int32(4 + len(reply)))
+			e.buf = append(e.buf, req[4:8]...) // correlation id
+			e.buf = append(e.buf, reply...)
+			if _, err := conn.Write(e.buf); err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+func withBrokerPipe(t *testing.T, wantAddr string, replies ...[]byte) <-chan int16 {
+	t.Helper()
+	client, server := net.Pipe()
+	keys := serveBroker(server, replies...)
+	orig := dialBroker
+	t.Cleanup(func() { dialBroker = orig })
+	dialBroker = func(_ *sarama.Config, addr string) (net.Conn, error) {
+		assert.Equal(t, wantAddr, addr)
+		return client, nil
+	}
+	return keys
+}
+
+func collectKeys(keys <-chan int16) []int16 {
+	var out []int16
+	for k := range keys {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestAlterReplicaLogDir(t *testing.T) {
+	admin := &MockClusterAdmin{MockBrokers: []*sarama.Broker{newTestBroker(1, "b1:9092"), newTestBroker(2, "b2:9092")}}
+
+	t.Run("success on the owning broker", func(t *testing.T) {
+		keys := withBrokerPipe(t, "b2:9092", unhex(t, stripComments(alterLogDirsResponseBody)))
+		ds := mockAdminDS(admin)
+
+		require.NoError(t, ds.AlterReplicaLogDir(2, "orders", 3, "/data/kafka-2"))
+		assert.Equal(t, []int16{apiKeyAlterReplicaLogDirs}, collectKeys(keys))
+	})
+
+	t.Run("error code is mapped", func(t *testing.T) {
+		withBrokerPipe(t, "b1:9092", unhex(t, stripComments(alterLogDirsResponseBody)))
+		ds := mockAdminDS(admin)
+
+		err := ds.AlterReplicaLogDir(1, "orders", 4, "/nope")
+		var ld api.LogDirNotFoundError
+		assert.ErrorAs(t, err, &ld)
+	})
+
+	t.Run("unknown broker", func(t *testing.T) {
+		ds := mockAdminDS(admin)
+		err := ds.AlterReplicaLogDir(42, "orders", 3, "/d")
+		var bnf api.BrokerNotFoundError
+		assert.ErrorAs(t, err, &bnf)
+	})
+
+	t.Run("dial failure is a connection error", func(t *testing.T) {
+		orig := dialBroker
+		t.Cleanup(func() { dialBroker = orig })
+		dialBroker = func(*sarama.Config, string) (net.Conn, error) { return nil, errors.New("refused") }
+		ds := mockAdminDS(admin)
+
+		err := ds.AlterReplicaLogDir(1, "orders", 3, "/d")
+		var ce api.ConnectionError
+		assert.ErrorAs(t, err, &ce)
+	})
+}
+
+func TestAlterReplicaLogDir_SaslPlain(t *testing.T) {
+	cfg := sarama.NewConfig()
+	cfg.Net.SASL.Enable = true
+	cfg.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	cfg.Net.SASL.User = "alice"
+	cfg.Net.SASL.Password = "secret"
+
+	handshakeOK := unhex(t, "0000 00000001 0005 504c41494e") // NONE, [PLAIN]
+	authOK := unhex(t, "0000 ffff 00000000")                 // NONE, null message, empty bytes
+
+	t.Run("authenticates before the request", func(t *testing.T) {
+		keys := withBrokerPipe(t, "b:9092", handshakeOK, authOK, unhex(t, stripComments(alterLogDirsResponseBody)))
+		require.NoError(t, alterReplicaLogDir(cfg, "b:9092", 1, "orders", 3, "/data/kafka-2"))
+		assert.Equal(t, []int16{apiKeySaslHandshake, apiKeySaslAuthenticate, apiKeyAlterReplicaLogDirs}, collectKeys(keys))
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		authFailed := unhex(t, "003a 0006 626164207077 00000000") // SASL_AUTHENTICATION_FAILED, "bad pw"
+		withBrokerPipe(t, "b:9092", handshakeOK, authFailed)
+		err := alterReplicaLogDir(cfg, "b:9092", 1, "orders", 3, "/d")
+		var ae api.AuthenticationError
+		require.ErrorAs(t, err, &ae)
+		assert.Equal(t, "bad pw", ae.Message)
+	})
+}
+
+func TestBrokerConn_UnsupportedSaslNamesOperation(t *testing.T) {
+	cfg := sarama.NewConfig()
+	cfg.Net.SASL.Enable = true
+	cfg.Net.SASL.Mechanism = sarama.SASLTypeGSSAPI
+	withBrokerPipe(t, "b:9092", unhex(t, "0000 00000001 0006 475353415049")) // NONE, [GSSAPI]
+
+	_, err := describeQuorum(cfg, "b:9092", 1)
+	var ns api.NotSupportedError
+	require.ErrorAs(t, err, &ns)
+	assert.Equal(t, "DescribeQuorum over SASL/GSSAPI", ns.Operation)
+}
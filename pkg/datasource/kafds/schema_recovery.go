@@ -0,0 +1,196 @@
+package kafds
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/Benny93/kafui/pkg/api"
+)
+
+// GetDeletedSubjects lists subjects that only the deleted=true listing returns,
+// i.e. subjects whose versions are all soft-deleted.
+func (kp KafkaDataSourceKaf) GetDeletedSubjects() ([]string, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	if rc == nil {
+		return nil, api.SchemaRegistryNotConfiguredError{}
+	}
+	var all, active []string
+	if err := rc.doGet("/subjects?deleted=true", &all); err != nil {
+		return nil, mapRegistryError(err, "", 0)
+	}
+	if err := rc.doGet("/subjects", &active); err != nil {
+		return nil, mapRegistryError(err, "", 0)
+	}
+	live := make(map[string]bool, len(active))
+	for _, s := range active {
+		live[s] = true
+	}
+	deleted := []string{}
+	for _, s := range all {
+		if !live[s] {
+			deleted = append(deleted, s)
+		}
+	}
+	sort.Strings(deleted)
+	return deleted, nil
+}
+
+// GetDeletedSchemaVersions diffs the deleted=true version listing against the
+// active one and fetches each soft-deleted version's record.
+func (kp KafkaDataSourceKaf) GetDeletedSchemaVersions(subject string) ([]api.SchemaVersion, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	if rc == nil {
+		return nil, api.SchemaRegistryNotConfiguredError{}
+	}
+	return getDeletedSchemaVersions(rc, subject)
+}
+
+func getDeletedSchemaVersions(rc *registryClient, subject string) ([]api.SchemaVersion, error) {
+	var all, active []int
+	if err := rc.doGet("/subjects/"+subject+"/versions?deleted=true", &all); err != nil {
+		return nil, mapRegistryError(err, subject, 0)
+	}
+	// A fully soft-deleted subject is unknown to the plain listing.
+	if err := rc.doGet("/subjects/"+subject+"/versions", &active); err != nil && !isRegistryNotFound(err) {
+		return nil, mapRegistryError(err, subject, 0)
+	}
+	live := make(map[int]bool, len(active))
+	for _, v := range active {
+		live[v] = true
+	}
+	out := []api.SchemaVersion{}
+	for _, v := range all {
+		if live[v] {
+			continue
+		}
+		var rec registryVersion
+		if err := rc.doGet(fmt.Sprintf("/subjects/%s/versions/%d?deleted=true", subject, v), &rec); err != nil {
+			return nil, mapRegistryError(err, subject, v)
+		}
+		out = append(out, rec.toAPI())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// isRegistryNotFound reports a registry 404 (unknown subject or version).
+func isRegistryNotFound(err error) bool {
+	var re *registryError
+	return errors.As(err, &re) && re.StatusCode == http.StatusNotFound
+}
+
+// RestoreSchemaVersion re-registers a soft-deleted version. The registry has
+// no undelete, so this is a new registration of the same text, type and
+// references; it gets the next version number but keeps the schema ID.
+func (kp KafkaDataSourceKaf) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return api.Schema{}, err
+	}
+	if rc == nil {
+		return api.Schema{}, api.SchemaRegistryNotConfiguredError{}
+	}
+	deleted, err := getDeletedSchemaVersions(rc, subject)
+	if err != nil {
+		return api.Schema{}, err
+	}
+	for _, v := range deleted {
+		if v.Version == version {
+			return kp.RegisterSchema(subject, v.Schema, v.SchemaType, v.References)
+		}
+	}
+	return api.Schema{}, api.SchemaVersionNotFoundError{Subject: subject, Version: version}
+}
+
+// GetGlobalMode returns the registry's mode.
+func (kp KafkaDataSourceKaf) GetGlobalMode() (api.SchemaMode, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return "", err
+	}
+	if rc == nil {
+		return "", api.SchemaRegistryNotConfiguredError{}
+	}
+	return getGlobalMode(rc)
+}
+
+func getGlobalMode(rc *registryClient) (api.SchemaMode, error) {
+	var resp struct {
+		Mode string `json:"mode"`
+	}
+	if err := rc.doGet("/mode", &resp); err != nil {
+		return "", mapRegistryError(err, "", 0)
+	}
+	return api.SchemaMode(resp.Mode), nil
+}
+
+// GetSubjectMode returns a subject's own mode, falling back to the global mode
+// (isSubjectSpecific=false) when the subject has none.
+func (kp KafkaDataSourceKaf) GetSubjectMode(subject string) (api.SchemaMode, bool, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return "", false, err
+	}
+	if rc == nil {
+		return "", false, api.SchemaRegistryNotConfiguredError{}
+	}
+	var resp struct {
+		Mode string `json:"mode"`
+	}
+	err = rc.doGet("/mode/"+subject, &resp)
+	if err != nil {
+		var re *registryError
+		if errors.As(err, &re) && (re.StatusCode == http.StatusNotFound || re.ErrorCode == 40409) {
+			// No subject-specific mode — fall back to the global one.
+			mode, gerr := getGlobalMode(rc)
+			return mode, false, gerr
+		}
+		return "", false, mapRegistryError(err, subject, 0)
+	}
+	return api.SchemaMode(resp.Mode), true, nil
+}
+
+// SetGlobalMode sets the registry's mode. The registry refuses IMPORT while it
+// holds schemas; that refusal is surfaced unchanged.
+func (kp KafkaDataSourceKaf) SetGlobalMode(mode api.SchemaMode) error {
+	if !mode.Valid() {
+		return fmt.Errorf("invalid schema registry mode: %q", mode)
+	}
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return err
+	}
+	if rc == nil {
+		return api.SchemaRegistryNotConfiguredError{}
+	}
+	if err := rc.doPut("/mode", map[string]string{"mode": string(mode)}, nil); err != nil {
+		return mapRegistryError(err, "", 0)
+	}
+	return nil
+}
+
+// SetSubjectMode sets a subject's mode.
+func (kp KafkaDataSourceKaf) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	if !mode.Valid() {
+		return fmt.Errorf("invalid schema registry mode: %q", mode)
+	}
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return err
+	}
+	if rc == nil {
+		return api.SchemaRegistryNotConfiguredError{}
+	}
+	if err := rc.doPut("/mode/"+subject, map[string]string{"mode": string(mode)}, nil); err != nil {
+		return mapRegistryError(err, subject, 0)
+	}
+	return nil
+}
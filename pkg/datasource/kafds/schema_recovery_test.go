@@ -0,0 +1,136 @@
+package kafds
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletedSubjectsAndVersions(t *testing.T) {
+	var registered map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deleted := r.URL.Query().Get("deleted") == "true"
+		switch {
+		case r.URL.Path == "/subjects" && deleted:
+			w.Write([]byte(`["orders","legacy","payments"]`))
+		case r.URL.Path == "/subjects":
+			w.Write([]byte(`["orders"]`))
+		case r.URL.Path == "/subjects/orders/versions" && r.Method == http.MethodPost:
+			registered = nil
+			_ = json.NewDecoder(r.Body).Decode(&registered)
+			w.Write([]byte(`{"id":7}`))
+		case r.URL.Path == "/subjects/orders/versions" && deleted:
+			w.Write([]byte(`[1,2,3]`))
+		case r.URL.Path == "/subjects/orders/versions":
+			w.Write([]byte(`[3]`))
+		case r.URL.Path == "/subjects/orders/versions/1" && deleted:
+			w.Write([]byte(`{"subject":"orders","version":1,"id":7,"schema":"{\"type\":\"string\"}"}`))
+		case r.URL.Path == "/subjects/orders/versions/2" && deleted:
+			w.Write([]byte(`{"subject":"orders","version":2,"id":8,"schemaType":"JSON","schema":"{}"}`))
+		case r.URL.Path == "/subjects/legacy/versions" && deleted:
+			w.Write([]byte(`[1]`))
+		case r.URL.Path == "/subjects/legacy/versions/1" && deleted:
+			w.Write([]byte(`{"subject":"legacy","version":1,"id":3,"schema":"{}"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error_code": 40401, "message": "Subject not found"})
+		}
+	}))
+	defer srv.Close()
+	kp := withRegistry(t, srv.URL, nil)
+
+	t.Run("deleted subjects are those missing from the plain listing", func(t *testing.T) {
+		subjects, err := kp.GetDeletedSubjects()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"legacy", "payments"}, subjects)
+	})
+
+	t.Run("deleted versions of a live subject", func(t *testing.T) {
+		versions, err := kp.GetDeletedSchemaVersions("orders")
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		assert.Equal(t, 1, versions[0].Version)
+		assert.Equal(t, "AVRO", versions[0].SchemaType)
+		assert.Equal(t, 2, versions[1].Version)
+		assert.Equal(t, "JSON", versions[1].SchemaType)
+	})
+
+	t.Run("deleted versions of a fully deleted subject", func(t *testing.T) {
+		versions, err := kp.GetDeletedSchemaVersions("legacy")
+		require.NoError(t, err)
+		require.Len(t, versions, 1)
+		assert.Equal(t, 3, versions[0].ID)
+	})
+
+	t.Run("restore re-registers the deleted content", func(t *testing.T) {
+		schema, err := kp.RestoreSchemaVersion("orders", 1)
+		require.NoError(t, err)
+		assert.Equal(t, 7, schema.ID)
+		assert.Equal(t, `{"type":"string"}`, registered["schema"])
+	})
+
+	t.Run("restore of a live version is not found", func(t *testing.T) {
+		_, err := kp.RestoreSchemaVersion("orders", 3)
+		var e api.SchemaVersionNotFoundError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, 3, e.Version)
+	})
+}
+
+func TestSchemaMode(t *testing.T) {
+	var gotPath, gotMethod, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotMethod = r.URL.Path, r.Method
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		switch {
+		case r.Method == http.MethodPut:
+			w.Write(b)
+		case r.URL.Path == "/mode":
+			w.Write([]byte(`{"mode":"READWRITE"}`))
+		case r.URL.Path == "/mode/orders-value":
+			w.Write([]byte(`{"mode":"READONLY"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error_code": 40409, "message": "Subject does not have subject-level mode configured"})
+		}
+	}))
+	defer srv.Close()
+	kp := withRegistry(t, srv.URL, nil)
+
+	mode, err := kp.GetGlobalMode()
+	require.NoError(t, err)
+	assert.Equal(t, api.SchemaModeReadWrite, mode)
+
+	mode, specific, err := kp.GetSubjectMode("orders-value")
+	require.NoError(t, err)
+	assert.Equal(t, api.SchemaModeReadOnly, mode)
+	assert.True(t, specific)
+
+	mode, specific, err = kp.GetSubjectMode("payments-value")
+	require.NoError(t, err)
+	assert.Equal(t, api.SchemaModeReadWrite, mode, "falls back to the global mode")
+	assert.False(t, specific)
+
+	require.NoError(t, kp.SetGlobalMode(api.SchemaModeImport))
+	assert.Equal(t, http.MethodPut, gotMethod)
+	assert.Equal(t, "/mode", gotPath)
+	assert.JSONEq(t, `{"mode":"IMPORT"}`, gotBody)
+
+	require.NoError(t, kp.SetSubjectMode("orders-value", api.SchemaModeReadOnly))
+	assert.Equal(t, "/mode/orders-value", gotPath)
+	assert.JSONEq(t, `{"mode":"READONLY"}`, gotBody)
+
+	t.Run("invalid mode rejected without HTTP call", func(t *testing.T) {
+		gotPath = ""
+		require.Error(t, kp.SetSubjectMode("orders-value", api.SchemaMode("WRITEONLY")))
+		assert.Empty(t, gotPath)
+	})
+}
@@ -48,11 +48,12 @@ type registryClient struct {
 // Returns (nil, nil) when no registry is configured — callers decide whether
 // that is an empty listing or a SchemaRegistryNotConfiguredError.
 func (kp KafkaDataSourceKaf) newRegistryClient() (*registryClient, error) {
-	if currentCluster == nil {
+	cluster := kp.sess().activeCluster()
+	if cluster == nil {
 		return nil, nil
 	}
 	var urls []string
-	for _, u := range strings.Split(currentCluster.SchemaRegistryURL, ",") {
+	for _, u := range strings.Split(cluster.SchemaRegistryURL, ",") {
 		if u = strings.TrimRight(strings.TrimSpace(u), "/"); u != "" {
 			urls = append(urls, u)
 		}
@@ -65,7 +66,7 @@ func (kp KafkaDataSourceKaf) newRegistryClient() (*registryClient, error) {
 		baseURLs: urls,
 		http:     &http.Client{Timeout: 10 * time.Second},
 	}
-	if creds := currentCluster.SchemaRegistryCredentials; creds != nil {
+	if creds := cluster.SchemaRegistryCredentials; creds != nil {
 		rc.username = creds.Username
 		rc.password = creds.Password
 	}
@@ -171,6 +172,8 @@ func mapRegistryError(err error, subject string, version int) error {
 		return api.SubjectNotFoundError{Subject: subject, Cause: re}
 	case re.StatusCode == http.StatusConflict:
 		return api.SchemaIncompatibleError{Subject: subject, Message: re.Message, Cause: re}
+	case re.ErrorCode == 42205:
+		return api.SchemaModeError{Subject: subject, Message: re.Message, Cause: re}
 	case re.StatusCode == http.StatusUnprocessableEntity:
 		return api.SchemaValidationError{Message: re.Message, Cause: re}
 	default:
@@ -178,6 +181,49 @@ func mapRegistryError(err error, subject string, version int) error {
 	}
 }
 
+// registryVersion is the registry's subject-version record as returned by
+// GET /subjects/{subject}/versions/{version}.
+type registryVersion struct {
+	Subject    string                `json:"subject"`
+	Version    int                   `json:"version"`
+	ID         int                   `json:"id"`
+	SchemaType string                `json:"schemaType"`
+	Schema     string                `json:"schema"`
+	References []api.SchemaReference `json:"references"`
+}
+
+// toAPI converts the record, defaulting the omitted schemaType to AVRO.
+func (v registryVersion) toAPI() api.SchemaVersion {
+	st := v.SchemaType
+	if st == "" {
+		st = "AVRO"
+	}
+	return api.SchemaVersion{Version: v.Version, ID: v.ID, SchemaType: st, Schema: v.Schema, References: v.References}
+}
+
+// versionPath builds /subjects/{subject}/versions/{version}, using the
+// "latest" keyword for non-positive versions.
+func versionPath(subject string, version int) string {
+	if version > 0 {
+		return fmt.Sprintf("/subjects/%s/versions/%d", subject, version)
+	}
+	return "/subjects/" + subject + "/versions/latest"
+}
+
+// registrySchemaBody builds the request body shared by register and
+// compatibility-check calls. schemaType and references are omitted when they
+// carry the registry defaults (AVRO, none).
+func registrySchemaBody(schemaText, schemaType string, references []api.SchemaReference) map[string]interface{} {
+	body := map[string]interface{}{"schema": schemaText}
+	if t := strings.ToUpper(strings.TrimSpace(schemaType)); t != "" && t != "AVRO" {
+		body["schemaType"] = t
+	}
+	if len(references) > 0 {
+		body["references"] = references
+	}
+	return body
+}
+
 // GetSchemas returns all registered schema subjects. It performs only a single
 // HTTP request (GET /subjects) so it completes quickly even for large registries.
 // Call GetSchemaDetails to lazily load version/ID/type for a subset of subjects.
@@ -285,23 +331,61 @@ func (kp KafkaDataSourceKaf) GetSchemaContent(subject string, version int) (stri
 		return "", api.SchemaRegistryNotConfiguredError{}
 	}
 
-	versionStr := "latest"
-	if version > 0 {
-		versionStr = fmt.Sprintf("%d", version)
+	var response registryVersion
+	if err := rc.doGet(versionPath(subject, version), &response); err != nil {
+		return "", mapRegistryError(err, subject, version)
+	}
+	return response.Schema, nil
+}
+
+// GetSchemaVersion fetches one version's text, type and references. Pass
+// version=0 (or any non-positive value) to fetch the latest version.
+func (kp KafkaDataSourceKaf) GetSchemaVersion(subject string, version int) (api.SchemaVersion, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return api.SchemaVersion{}, err
 	}
+	if rc == nil {
+		return api.SchemaVersion{}, api.SchemaRegistryNotConfiguredError{}
+	}
+	var response registryVersion
+	if err := rc.doGet(versionPath(subject, version), &response); err != nil {
+		return api.SchemaVersion{}, mapRegistryError(err, subject, version)
+	}
+	return response.toAPI(), nil
+}
 
-	var response struct {
-		Subject    string `json:"subject"`
-		Version    int    `json:"version"`
-		ID         int    `json:"id"`
-		SchemaType string `json:"schemaType"`
-		Schema     string `json:"schema"`
+// GetSchemaReferencedBy resolves the schema IDs returned by the registry's
+// referencedby endpoint to subject versions. An ID registered under several
+// subjects yields one entry per subject. The result is sorted by subject and
+// version.
+func (kp KafkaDataSourceKaf) GetSchemaReferencedBy(subject string, version int) ([]api.SubjectVersion, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return nil, err
 	}
-	path := fmt.Sprintf("/subjects/%s/versions/%s", subject, versionStr)
-	if err := rc.doGet(path, &response); err != nil {
-		return "", mapRegistryError(err, subject, version)
+	if rc == nil {
+		return nil, api.SchemaRegistryNotConfiguredError{}
 	}
-	return response.Schema, nil
+	var ids []int
+	if err := rc.doGet(versionPath(subject, version)+"/referencedby", &ids); err != nil {
+		return nil, mapRegistryError(err, subject, version)
+	}
+	out := []api.SubjectVersion{}
+	for _, id := range ids {
+		var versions []api.SubjectVersion
+		if err := rc.doGet(fmt.Sprintf("/schemas/ids/%d/versions", id), &versions); err != nil {
+			return nil, mapRegistryError(err, "", 0)
+		}
+		out = append(out, versions...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Subject != out[j].Subject {
+			return out[i].Subject < out[j].Subject
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out, nil
 }
 
 // GetSchemaVersions lists all versions of a subject with per-version metadata
@@ -334,24 +418,14 @@ func (kp KafkaDataSourceKaf) GetSchemaVersions(subject string) ([]api.SchemaVers
 		go func() {
 			for idx := range jobs {
 				v := versionNums[idx]
-				var meta struct {
-					Version    int    `json:"version"`
-					ID         int    `json:"id"`
-					SchemaType string `json:"schemaType"`
-				}
+				var meta registryVersion
 				if err := rc.doGet(fmt.Sprintf("/subjects/%s/versions/%d", subject, v), &meta); err != nil {
 					results <- result{idx: idx, err: mapRegistryError(err, subject, v)}
 					continue
 				}
-				st := meta.SchemaType
-				if st == "" {
-					st = "AVRO"
-				}
-				results <- result{idx: idx, version: api.SchemaVersion{
-					Version:    meta.Version,
-					ID:         meta.ID,
-					SchemaType: st,
-				}}
+				sv := meta.toAPI()
+				sv.Schema = ""
+				results <- result{idx: idx, version: sv}
 			}
 		}()
 	}
@@ -434,7 +508,7 @@ func (kp KafkaDataSourceKaf) getSubjectCompatibility(rc *registryClient, subject
 
 // RegisterSchema registers a new schema (new subject or new version) and returns
 // the stored record re-fetched from versions/latest (SR-7).
-func (kp KafkaDataSourceKaf) RegisterSchema(subject, schemaText, schemaType string) (api.Schema, error) {
+func (kp KafkaDataSourceKaf) RegisterSchema(subject, schemaText, schemaType string, references []api.SchemaReference) (api.Schema, error) {
 	rc, err := kp.newRegistryClient()
 	if err != nil {
 		return api.Schema{}, err
@@ -443,10 +517,10 @@ func (kp KafkaDataSourceKaf) RegisterSchema(subject, schemaText, schemaType stri
 		return api.Schema{}, api.SchemaRegistryNotConfiguredError{}
 	}
 
-	body := map[string]interface{}{"schema": schemaText}
-	if t := strings.ToUpper(strings.TrimSpace(schemaType)); t != "" && t != "AVRO" {
-		body["schemaType"] = t
+	if err := api.ValidateSchemaReferences(references); err != nil {
+		return api.Schema{}, err
 	}
+	body := registrySchemaBody(schemaText, schemaType, references)
 
 	var reg struct {
 		ID int `json:"id"`
@@ -456,26 +530,56 @@ func (kp KafkaDataSourceKaf) RegisterSchema(subject, schemaText, schemaType stri
 	}
 
 	// Re-fetch the stored record for the version number.
-	var meta struct {
-		Subject    string `json:"subject"`
-		Version    int    `json:"version"`
-		ID         int    `json:"id"`
-		SchemaType string `json:"schemaType"`
-	}
+	var meta registryVersion
 	if err := rc.doGet("/subjects/"+subject+"/versions/latest", &meta); err != nil {
 		// Registration succeeded; return what we know.
-		return api.Schema{Subject: subject, ID: reg.ID, SchemaType: schemaType}, nil
+		return api.Schema{Subject: subject, ID: reg.ID, SchemaType: schemaType, References: references}, nil
 	}
-	st := meta.SchemaType
-	if st == "" {
-		st = "AVRO"
+	sv := meta.toAPI()
+	return api.Schema{Subject: meta.Subject, Version: sv.Version, ID: sv.ID, SchemaType: sv.SchemaType, References: sv.References}, nil
+}
+
+// ImportSchema registers a version under its original version number and
+// schema ID. The registry accepts both only in IMPORT mode and answers 42205
+// otherwise.
+func (kp KafkaDataSourceKaf) ImportSchema(subject string, version api.SchemaVersion) (api.Schema, error) {
+	rc, err := kp.newRegistryClient()
+	if err != nil {
+		return api.Schema{}, err
+	}
+	if rc == nil {
+		return api.Schema{}, api.SchemaRegistryNotConfiguredError{}
+	}
+
+	if err := api.ValidateSchemaReferences(version.References); err != nil {
+		return api.Schema{}, err
+	}
+	body := registrySchemaBody(version.Schema, version.SchemaType, version.References)
+	body["version"] = version.Version
+	body["id"] = version.ID
+
+	var reg struct {
+		ID int `json:"id"`
 	}
-	return api.Schema{Subject: meta.Subject, Version: meta.Version, ID: meta.ID, SchemaType: st}, nil
+	if err := rc.doPost("/subjects/"+subject+"/versions", body, &reg); err != nil {
+		return api.Schema{}, mapRegistryError(err, subject, version.Version)
+	}
+	return api.Schema{Subject: subject, Version: version.Version, ID: reg.ID, SchemaType: version.SchemaType, References: version.References}, nil
 }
 
 // CheckSchemaCompatibility tests a candidate schema against the subject's latest
 // version without registering it (SR-8).
-func (kp KafkaDataSourceKaf) CheckSchemaCompatibility(subject, schemaText, schemaType string) (bool, []string, error) {
+func (kp KafkaDataSourceKaf) CheckSchemaCompatibility(subject, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
+	return kp.checkCompatibility(subject, "latest", 0, schemaText, schemaType, references)
+}
+
+// CheckSchemaCompatibilityWithVersion tests a candidate schema against one
+// version of the subject.
+func (kp KafkaDataSourceKaf) CheckSchemaCompatibilityWithVersion(subject string, version int, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
+	return kp.checkCompatibility(subject, fmt.Sprint(version), version, schemaText, schemaType, references)
+}
+
+func (kp KafkaDataSourceKaf) checkCompatibility(subject, versionPath string, version int, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
 	rc, err := kp.newRegistryClient()
 	if err != nil {
 		return false, nil, err
@@ -484,17 +588,17 @@ func (kp KafkaDataSourceKaf) CheckSchemaCompatibility(subject, schemaText, schem
 		return false, nil, api.SchemaRegistryNotConfiguredError{}
 	}
 
-	body := map[string]interface{}{"schema": schemaText}
-	if t := strings.ToUpper(strings.TrimSpace(schemaType)); t != "" && t != "AVRO" {
-		body["schemaType"] = t
+	if err := api.ValidateSchemaReferences(references); err != nil {
+		return false, nil, err
 	}
+	body := registrySchemaBody(schemaText, schemaType, references)
 
 	var resp struct {
 		IsCompatible bool     `json:"is_compatible"`
 		Messages     []string `json:"messages"`
 	}
-	if err := rc.doPost("/compatibility/subjects/"+subject+"/versions/latest?verbose=true", body, &resp); err != nil {
-		return false, nil, mapRegistryError(err, subject, 0)
+	if err := rc.doPost("/compatibility/subjects/"+subject+"/versions/"+versionPath+"?verbose=true", body, &resp); err != nil {
+		return false, nil, mapRegistryError(err, subject, version)
 	}
 	return resp.IsCompatible, resp.Messages, nil
 }
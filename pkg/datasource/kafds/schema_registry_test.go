@@ -15,13 +15,11 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// withRegistry points the active cluster at the given base URL(s) for the
-// duration of fn, restoring the previous cluster afterwards.
-func withRegistry(t *testing.T, url string, creds *config.SchemaRegistryCredentials) func() {
+// withRegistry returns a datasource whose active cluster points at the given
+// schema registry base URL(s).
+func withRegistry(t *testing.T, url string, creds *config.SchemaRegistryCredentials) KafkaDataSourceKaf {
 	t.Helper()
-	prev := currentCluster
-	currentCluster = &config.Cluster{SchemaRegistryURL: url, SchemaRegistryCredentials: creds}
-	return func() { currentCluster = prev }
+	return newTestDS(&MockKafkaClientFactory{}, &config.Cluster{SchemaRegistryURL: url, SchemaRegistryCredentials: creds})
 }
 
 func TestRegistryClient_Verbs_AuthAndContentType(t *testing.T) {
@@ -36,9 +34,7 @@ func TestRegistryClient_Verbs_AuthAndContentType(t *testing.T) {
 		w.Write([]byte(`{"ok":true}`))
 	}))
 	defer srv.Close()
-	defer withRegistry(t, srv.URL, &config.SchemaRegistryCredentials{Username: "u", Password: "p"})()
-
-	kp := KafkaDataSourceKaf{}
+	kp := withRegistry(t, srv.URL, &config.SchemaRegistryCredentials{Username: "u", Password: "p"})
 	rc, err := kp.newRegistryClient()
 	require.NoError(t, err)
 	require.NotNil(t, rc)
@@ -102,14 +98,19 @@ func TestMapRegistryError(t *testing.T) {
 			require.True(t, errors.As(err, &e))
 			assert.Equal(t, "Invalid schema", e.Message)
 		}},
+		{"mode refusal by 42205", http.StatusUnprocessableEntity, 42205, "Subject orders is in read-only mode", func(t *testing.T, err error) {
+			var e api.SchemaModeError
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, "orders", e.Subject)
+			var ve api.SchemaValidationError
+			assert.False(t, errors.As(err, &ve), "a mode refusal is not a validation error")
+		}},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			srv := httptest.NewServer(registryErrorHandler(tc.status, tc.code, tc.message))
 			defer srv.Close()
-			defer withRegistry(t, srv.URL, nil)()
-
-			kp := KafkaDataSourceKaf{}
+			kp := withRegistry(t, srv.URL, nil)
 			rc, _ := kp.newRegistryClient()
 			err := rc.doGet("/subjects/orders/versions/latest", nil)
 			tc.check(t, mapRegistryError(err, "orders", 3))
@@ -132,9 +133,7 @@ func TestGetSchemaVersions(t *testing.T) {
 		}
 	}))
 	defer srv.Close()
-	defer withRegistry(t, srv.URL, nil)()
-
-	kp := KafkaDataSourceKaf{}
+	kp := withRegistry(t, srv.URL, nil)
 	versions, err := kp.GetSchemaVersions("orders")
 	require.NoError(t, err)
 	require.Len(t, versions, 2)
@@ -149,6 +148,75 @@ func TestGetSchemaVersions(t *testing.T) {
 	})
 }
 
+func TestSchemaReferences(t *testing.T) {
+	var posted map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			posted = nil
+			_ = json.NewDecoder(r.Body).Decode(&posted)
+			if strings.HasPrefix(r.URL.Path, "/compatibility/") {
+				w.Write([]byte(`{"is_compatible":true}`))
+				return
+			}
+			w.Write([]byte(`{"id":50}`))
+		case r.URL.Path == "/subjects/customers/versions/latest", r.URL.Path == "/subjects/customers/versions/2":
+			w.Write([]byte(`{"subject":"customers","version":2,"id":50,"schema":"{}","references":[{"name":"com.example.Address","subject":"address","version":1}]}`))
+		case r.URL.Path == "/subjects/address/versions/1/referencedby":
+			w.Write([]byte(`[50,51]`))
+		case r.URL.Path == "/schemas/ids/50/versions":
+			w.Write([]byte(`[{"subject":"customers","version":2}]`))
+		case r.URL.Path == "/schemas/ids/51/versions":
+			w.Write([]byte(`[{"subject":"billing","version":1},{"subject":"accounts","version":4}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"error_code": 40401, "message": "not found"})
+		}
+	}))
+	defer srv.Close()
+	kp := withRegistry(t, srv.URL, nil)
+	refs := []api.SchemaReference{{Name: "com.example.Address", Subject: "address", Version: 1}}
+
+	t.Run("register sends and returns references", func(t *testing.T) {
+		schema, err := kp.RegisterSchema("customers", "{}", "", refs)
+		require.NoError(t, err)
+		assert.Equal(t, refs, schema.References)
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "com.example.Address", "subject": "address", "version": float64(1)}}, posted["references"])
+	})
+
+	t.Run("compatibility check sends references", func(t *testing.T) {
+		_, _, err := kp.CheckSchemaCompatibility("customers", "{}", "", refs)
+		require.NoError(t, err)
+		assert.Contains(t, posted, "references")
+
+		_, _, err = kp.CheckSchemaCompatibility("customers", "{}", "", nil)
+		require.NoError(t, err)
+		assert.NotContains(t, posted, "references", "no references means no field")
+	})
+
+	t.Run("version carries references", func(t *testing.T) {
+		sv, err := kp.GetSchemaVersion("customers", 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, sv.Version)
+		assert.Equal(t, "AVRO", sv.SchemaType)
+		assert.Equal(t, refs, sv.References)
+	})
+
+	t.Run("referenced by resolves ids to subject versions", func(t *testing.T) {
+		by, err := kp.GetSchemaReferencedBy("address", 1)
+		require.NoError(t, err)
+		assert.Equal(t, []api.SubjectVersion{
+			{Subject: "accounts", Version: 4},
+			{Subject: "billing", Version: 1},
+			{Subject: "customers", Version: 2},
+		}, by)
+
+		_, err = kp.GetSchemaReferencedBy("nope", 1)
+		var e api.SubjectNotFoundError
+		assert.True(t, errors.As(err, &e))
+	})
+}
+
 func TestCompatibility_GetGlobalAndSubject(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
@@ -164,9 +232,7 @@ func TestCompatibility_GetGlobalAndSubject(t *testing.T) {
 		}
 	}))
 	defer srv.Close()
-	defer withRegistry(t, srv.URL, nil)()
-
-	kp := KafkaDataSourceKaf{}
+	kp := withRegistry(t, srv.URL, nil)
 
 	global, err := kp.GetGlobalCompatibility()
 	require.NoError(t, err)
@@ -195,9 +261,7 @@ func TestCompatibility_SetGlobalAndSubject(t *testing.T) {
 		w.Write([]byte(`{}`))
 	}))
 	defer srv.Close()
-	defer withRegistry(t, srv.URL, nil)()
-
-	kp := KafkaDataSourceKaf{}
+	kp := withRegistry(t, srv.URL, nil)
 
 	require.NoError(t, kp.SetGlobalCompatibility(api.CompatibilityForward))
 	assert.Equal(t, http.MethodPut, gotMethod)
@@ -212,7 +276,7 @@ func TestCompatibility_SetGlobalAndSubject(t *testing.T) {
 		called := false
 		srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
 		defer srv2.Close()
-		defer withRegistry(t, srv2.URL, nil)()
+		kp := withRegistry(t, srv2.URL, nil)
 		err := kp.SetGlobalCompatibility(api.CompatibilityLevel("NOPE"))
 		require.Error(t, err)
 		assert.False(t, called)
@@ -230,10 +294,8 @@ func TestRegisterSchema(t *testing.T) {
 			w.Write([]byte(`{"subject":"orders-value","version":4,"id":42,"schemaType":"AVRO"}`))
 		}))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-
-		kp := KafkaDataSourceKaf{}
-		schema, err := kp.RegisterSchema("orders-value", `{"type":"string"}`, "")
+		kp := withRegistry(t, srv.URL, nil)
+		schema, err := kp.RegisterSchema("orders-value", `{"type":"string"}`, "", nil)
 		require.NoError(t, err)
 		assert.Equal(t, 42, schema.ID)
 		assert.Equal(t, 4, schema.Version)
@@ -242,9 +304,8 @@ func TestRegisterSchema(t *testing.T) {
 	t.Run("409 maps to incompatible", func(t *testing.T) {
 		srv := httptest.NewServer(registryErrorHandler(http.StatusConflict, 0, "incompatible with v3"))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
-		_, err := kp.RegisterSchema("orders-value", "{}", "")
+		kp := withRegistry(t, srv.URL, nil)
+		_, err := kp.RegisterSchema("orders-value", "{}", "", nil)
 		var e api.SchemaIncompatibleError
 		require.True(t, errors.As(err, &e))
 		assert.Equal(t, "incompatible with v3", e.Message)
@@ -253,13 +314,60 @@ func TestRegisterSchema(t *testing.T) {
 	t.Run("422 maps to validation", func(t *testing.T) {
 		srv := httptest.NewServer(registryErrorHandler(http.StatusUnprocessableEntity, 42201, "bad avro"))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
-		_, err := kp.RegisterSchema("orders-value", "{", "")
+		kp := withRegistry(t, srv.URL, nil)
+		_, err := kp.RegisterSchema("orders-value", "{", "", nil)
 		var e api.SchemaValidationError
 		require.True(t, errors.As(err, &e))
 		assert.Equal(t, "bad avro", e.Message)
 	})
+
+	t.Run("invalid references rejected before the registry", func(t *testing.T) {
+		called := false
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			w.Write([]byte(`{"id":42}`))
+		}))
+		defer srv.Close()
+		kp := withRegistry(t, srv.URL, nil)
+		refs := []api.SchemaReference{{Name: "customer.proto", Subject: "customer", Version: 0}}
+
+		_, err := kp.RegisterSchema("orders-value", "{}", "PROTOBUF", refs)
+		var e api.SchemaValidationError
+		require.ErrorAs(t, err, &e)
+		assert.Contains(t, e.Message, "version must be 1 or greater")
+		_, err = kp.ImportSchema("orders-value", api.SchemaVersion{Version: 1, ID: 7, Schema: "{}", References: refs})
+		require.ErrorAs(t, err, &e)
+		_, _, err = kp.CheckSchemaCompatibility("orders-value", "{}", "PROTOBUF", refs)
+		require.ErrorAs(t, err, &e)
+		assert.False(t, called, "invalid references must not reach the registry")
+	})
+}
+
+func TestImportSchema(t *testing.T) {
+	var posted map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted = nil
+		_ = json.NewDecoder(r.Body).Decode(&posted)
+		w.Write([]byte(`{"id":101}`))
+	}))
+	defer srv.Close()
+	kp := withRegistry(t, srv.URL, nil)
+
+	schema, err := kp.ImportSchema("orders-value", api.SchemaVersion{Version: 3, ID: 101, SchemaType: "PROTOBUF", Schema: "syntax = \"proto3\";"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, schema.Version)
+	assert.Equal(t, 101, schema.ID)
+	assert.Equal(t, float64(3), posted["version"])
+	assert.Equal(t, float64(101), posted["id"])
+	assert.Equal(t, "PROTOBUF", posted["schemaType"])
+
+	t.Run("not in import mode", func(t *testing.T) {
+		srv := httptest.NewServer(registryErrorHandler(http.StatusUnprocessableEntity, 42205, "Subject orders-value is not in import mode"))
+		defer srv.Close()
+		_, err := withRegistry(t, srv.URL, nil).ImportSchema("orders-value", api.SchemaVersion{Version: 1, ID: 1, Schema: "{}"})
+		var e api.SchemaModeError
+		assert.True(t, errors.As(err, &e))
+	})
 }
 
 func TestCheckSchemaCompatibility(t *testing.T) {
@@ -269,9 +377,8 @@ func TestCheckSchemaCompatibility(t *testing.T) {
 			w.Write([]byte(`{"is_compatible":true}`))
 		}))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
-		ok, msgs, err := kp.CheckSchemaCompatibility("orders-value", "{}", "")
+		kp := withRegistry(t, srv.URL, nil)
+		ok, msgs, err := kp.CheckSchemaCompatibility("orders-value", "{}", "", nil)
 		require.NoError(t, err)
 		assert.True(t, ok)
 		assert.Empty(t, msgs)
@@ -282,9 +389,8 @@ func TestCheckSchemaCompatibility(t *testing.T) {
 			w.Write([]byte(`{"is_compatible":false,"messages":["field removed"]}`))
 		}))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
-		ok, msgs, err := kp.CheckSchemaCompatibility("orders-value", "{}", "")
+		kp := withRegistry(t, srv.URL, nil)
+		ok, msgs, err := kp.CheckSchemaCompatibility("orders-value", "{}", "", nil)
 		require.NoError(t, err)
 		assert.False(t, ok)
 		assert.Equal(t, []string{"field removed"}, msgs)
@@ -293,14 +399,41 @@ func TestCheckSchemaCompatibility(t *testing.T) {
 	t.Run("unknown subject", func(t *testing.T) {
 		srv := httptest.NewServer(registryErrorHandler(http.StatusNotFound, 40401, "Subject not found"))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
-		_, _, err := kp.CheckSchemaCompatibility("nope", "{}", "")
+		kp := withRegistry(t, srv.URL, nil)
+		_, _, err := kp.CheckSchemaCompatibility("nope", "{}", "", nil)
 		var e api.SubjectNotFoundError
 		assert.True(t, errors.As(err, &e))
 	})
 }
 
+func TestCheckSchemaCompatibilityWithVersion(t *testing.T) {
+	t.Run("checks the given version", func(t *testing.T) {
+		var gotPath string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotPath = r.URL.Path
+			assert.Contains(t, r.URL.RawQuery, "verbose=true")
+			w.Write([]byte(`{"is_compatible":false,"messages":["reader field 'x' has no default"]}`))
+		}))
+		defer srv.Close()
+		kp := withRegistry(t, srv.URL, nil)
+		ok, msgs, err := kp.CheckSchemaCompatibilityWithVersion("orders-value", 2, "{}", "", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "/compatibility/subjects/orders-value/versions/2", gotPath)
+		assert.False(t, ok)
+		assert.Equal(t, []string{"reader field 'x' has no default"}, msgs)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		srv := httptest.NewServer(registryErrorHandler(http.StatusNotFound, 40402, "Version not found"))
+		defer srv.Close()
+		kp := withRegistry(t, srv.URL, nil)
+		_, _, err := kp.CheckSchemaCompatibilityWithVersion("orders-value", 9, "{}", "", nil)
+		var e api.SchemaVersionNotFoundError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, 9, e.Version)
+	})
+}
+
 func TestDeleteSubjectAndVersion(t *testing.T) {
 	t.Run("soft delete subject", func(t *testing.T) {
 		var gotQuery string
@@ -309,8 +442,7 @@ func TestDeleteSubjectAndVersion(t *testing.T) {
 			w.Write([]byte(`[1,2,3]`))
 		}))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
+		kp := withRegistry(t, srv.URL, nil)
 		deleted, err := kp.DeleteSubject("orders-value", false)
 		require.NoError(t, err)
 		assert.Equal(t, []int{1, 2, 3}, deleted)
@@ -324,8 +456,7 @@ func TestDeleteSubjectAndVersion(t *testing.T) {
 			w.Write([]byte(`[1]`))
 		}))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
+		kp := withRegistry(t, srv.URL, nil)
 		_, err := kp.DeleteSubject("orders-value", true)
 		require.NoError(t, err)
 		assert.Equal(t, "permanent=true", gotQuery)
@@ -338,8 +469,7 @@ func TestDeleteSubjectAndVersion(t *testing.T) {
 			w.Write([]byte(`3`))
 		}))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
+		kp := withRegistry(t, srv.URL, nil)
 		require.NoError(t, kp.DeleteSchemaVersion("orders-value", -1, false))
 		assert.Equal(t, "/subjects/orders-value/versions/latest", gotPath)
 	})
@@ -347,8 +477,7 @@ func TestDeleteSubjectAndVersion(t *testing.T) {
 	t.Run("missing version maps to version not found", func(t *testing.T) {
 		srv := httptest.NewServer(registryErrorHandler(http.StatusNotFound, 40402, "Version not found"))
 		defer srv.Close()
-		defer withRegistry(t, srv.URL, nil)()
-		kp := KafkaDataSourceKaf{}
+		kp := withRegistry(t, srv.URL, nil)
 		err := kp.DeleteSchemaVersion("orders-value", 99, false)
 		var e api.SchemaVersionNotFoundError
 		assert.True(t, errors.As(err, &e))
@@ -356,8 +485,7 @@ func TestDeleteSubjectAndVersion(t *testing.T) {
 }
 
 func TestNotConfigured(t *testing.T) {
-	defer withRegistry(t, "", nil)()
-	kp := KafkaDataSourceKaf{}
+	kp := withRegistry(t, "", nil)
 
 	// Listing returns empty, not an error.
 	schemas, err := kp.GetSchemas()
@@ -382,9 +510,7 @@ func TestFailover(t *testing.T) {
 			w.Write([]byte(`["a","b"]`))
 		}))
 		defer live.Close()
-		defer withRegistry(t, deadURL+","+live.URL, nil)()
-
-		kp := KafkaDataSourceKaf{}
+		kp := withRegistry(t, deadURL+","+live.URL, nil)
 		schemas, err := kp.GetSchemas()
 		require.NoError(t, err)
 		assert.Len(t, schemas, 2)
@@ -396,9 +522,7 @@ func TestFailover(t *testing.T) {
 		u1, u2 := s1.URL, s2.URL
 		s1.Close()
 		s2.Close()
-		defer withRegistry(t, u1+","+u2, nil)()
-
-		kp := KafkaDataSourceKaf{}
+		kp := withRegistry(t, u1+","+u2, nil)
 		_, err := kp.GetSchemas()
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no live schema registry instances")
@@ -418,9 +542,7 @@ func TestFailover(t *testing.T) {
 			w.Write([]byte(`[]`))
 		}))
 		defer backup.Close()
-		defer withRegistry(t, bad.URL+","+backup.URL, nil)()
-
-		kp := KafkaDataSourceKaf{}
+		kp := withRegistry(t, bad.URL+","+backup.URL, nil)
 		_, err := kp.GetSchemas()
 		require.Error(t, err)
 		assert.Equal(t, 1, hits)
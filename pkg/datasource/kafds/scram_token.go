@@ -0,0 +1,171 @@
+package kafds
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/appconfig"
+	"github.com/IBM/sarama"
+	"github.com/xdg/scram"
+)
+
+// loadTokenAuthClusters returns the clusters that log in with a delegation
+// token, from the kafui overlay config. The session reads it once (see
+// session.delegationTokenAuth); it is a variable so tests can inject a value
+// without touching disk.
+var loadTokenAuthClusters = func() map[string]bool {
+	out := map[string]bool{}
+	cfg, err := appconfig.Load(appconfig.DefaultPath())
+	if err != nil {
+		return out
+	}
+	for name, ext := range cfg.Clusters {
+		if ext.SASL != nil && ext.SASL.TokenAuth {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// scramClientGenerator picks the SCRAM client: the token client for
+// delegation-token logins, xdg/scram otherwise.
+func scramClientGenerator(tokenAuth bool, hashGen scram.HashGeneratorFcn) func() sarama.SCRAMClient {
+	if tokenAuth {
+		return func() sarama.SCRAMClient { return &TokenSCRAMClient{HashGeneratorFcn: hashGen} }
+	}
+	return func() sarama.SCRAMClient { return &XDGSCRAMClient{HashGeneratorFcn: hashGen} }
+}
+
+// newScramNonce generates the client nonce; tests replace it.
+var newScramNonce = func() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawStdEncoding.EncodeToString(b), nil
+}
+
+// TokenSCRAMClient is a sarama.SCRAMClient for delegation-token logins. Kafka
+// recognises a token login by the "tokenauth=true" extension in the
+// client-first message, which xdg/scram cannot send; the username is the token
+// ID and the password its base64 HMAC.
+type TokenSCRAMClient struct {
+	scram.HashGeneratorFcn
+
+	user, password  string
+	nonce           string
+	clientFirstBare string
+	serverSignature []byte
+	step            int
+}
+
+func (c *TokenSCRAMClient) Begin(userName, password, _ string) error {
+	nonce, err := newScramNonce()
+	if err != nil {
+		return err
+	}
+	*c = TokenSCRAMClient{HashGeneratorFcn: c.HashGeneratorFcn, user: userName, password: password, nonce: nonce}
+	return nil
+}
+
+func (c *TokenSCRAMClient) Step(challenge string) (string, error) {
+	c.step++
+	switch c.step {
+	case 1:
+		c.clientFirstBare = "n=" + scramEscape(c.user) + ",r=" + c.nonce + ",tokenauth=true"
+		return "n,," + c.clientFirstBare, nil
+	case 2:
+		return c.clientFinal(challenge)
+	case 3:
+		return "", c.verifyServerFinal(challenge)
+	}
+	return "", errors.New("scram: conversation already complete")
+}
+
+func (c *TokenSCRAMClient) Done() bool { return c.step >= 3 }
+
+// clientFinal answers the server-first message ("r=...,s=...,i=...") with the
+// client proof and remembers the expected server signature.
+func (c *TokenSCRAMClient) clientFinal(serverFirst string) (string, error) {
+	attrs := scramAttrs(serverFirst)
+	nonce := attrs["r"]
+	if !strings.HasPrefix(nonce, c.nonce) || len(nonce) == len(c.nonce) {
+		return "", errors.New("scram: server nonce does not extend the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(attrs["s"])
+	if err != nil {
+		return "", fmt.Errorf("scram: invalid salt: %w", err)
+	}
+	iterations, err := strconv.Atoi(attrs["i"])
+	if err != nil || iterations < 1 {
+		return "", fmt.Errorf("scram: invalid iteration count %q", attrs["i"])
+	}
+
+	salted := c.hi([]byte(c.password), salt, iterations)
+	withoutProof := "c=biws,r=" + nonce
+	authMessage := []byte(c.clientFirstBare + "," + serverFirst + "," + withoutProof)
+
+	clientKey := c.mac(salted, []byte("Client Key"))
+	h := c.HashGeneratorFcn()
+	h.Write(clientKey)
+	clientSig := c.mac(h.Sum(nil), authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSig[i]
+	}
+	c.serverSignature = c.mac(c.mac(salted, []byte("Server Key")), authMessage)
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+func (c *TokenSCRAMClient) verifyServerFinal(serverFinal string) error {
+	attrs := scramAttrs(serverFinal)
+	if e, ok := attrs["e"]; ok {
+		return fmt.Errorf("scram: server error: %s", e)
+	}
+	sig, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil || !bytes.Equal(sig, c.serverSignature) {
+		return errors.New("scram: server signature mismatch")
+	}
+	return nil
+}
+
+func (c *TokenSCRAMClient) mac(key, msg []byte) []byte {
+	m := hmac.New(c.HashGeneratorFcn, key)
+	m.Write(msg)
+	return m.Sum(nil)
+}
+
+// hi is RFC 5802's Hi(), i.e. PBKDF2 with a single output block.
+func (c *TokenSCRAMClient) hi(password, salt []byte, iterations int) []byte {
+	u := c.mac(password, binary.BigEndian.AppendUint32(append([]byte{}, salt...), 1))
+	out := append([]byte{}, u...)
+	for i := 1; i < iterations; i++ {
+		u = c.mac(password, u)
+		for j := range out {
+			out[j] ^= u[j]
+		}
+	}
+	return out
+}
+
+// scramAttrs splits a SCRAM message into its "k=v" attributes.
+func scramAttrs(msg string) map[string]string {
+	attrs := map[string]string{}
+	for _, part := range strings.Split(msg, ",") {
+		if k, v, ok := strings.Cut(part, "="); ok {
+			attrs[k] = v
+		}
+	}
+	return attrs
+}
+
+func scramEscape(s string) string {
+	return strings.NewReplacer("=", "=3D", ",", "=2C").Replace(s)
+}
@@ -0,0 +1,104 @@
+package kafds
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xdg/scram"
+)
+
+// scramServer runs an xdg/scram server for user/password; it keeps the
+// client-first extensions in the auth message as Kafka does.
+func scramServer(t *testing.T, hashGen scram.HashGeneratorFcn, user, password string) *scram.ServerConversation {
+	t.Helper()
+	client, err := hashGen.NewClient(user, password, "")
+	require.NoError(t, err)
+	creds := client.GetStoredCredentials(scram.KeyFactors{Salt: "salt", Iters: 4096})
+	server, err := hashGen.NewServer(func(string) (scram.StoredCredentials, error) { return creds, nil })
+	require.NoError(t, err)
+	return server.NewConversation()
+}
+
+func TestTokenSCRAMClient(t *testing.T) {
+	orig := newScramNonce
+	newScramNonce = func() (string, error) { return "clientnonce", nil }
+	defer func() { newScramNonce = orig }()
+
+	for name, hashGen := range map[string]scram.HashGeneratorFcn{"sha256": SHA256, "sha512": SHA512} {
+		t.Run(name, func(t *testing.T) {
+			server := scramServer(t, hashGen, "token-id", "aG1hYw==")
+			c := &TokenSCRAMClient{HashGeneratorFcn: hashGen}
+			require.NoError(t, c.Begin("token-id", "aG1hYw==", ""))
+
+			first, err := c.Step("")
+			require.NoError(t, err)
+			assert.Equal(t, "n,,n=token-id,r=clientnonce,tokenauth=true", first)
+
+			serverFirst, err := server.Step(first)
+			require.NoError(t, err)
+			final, err := c.Step(serverFirst)
+			require.NoError(t, err)
+			serverFinal, err := server.Step(final)
+			require.NoError(t, err, "the server accepts the proof")
+			assert.True(t, server.Valid())
+
+			_, err = c.Step(serverFinal)
+			require.NoError(t, err)
+			assert.True(t, c.Done())
+		})
+	}
+
+	t.Run("wrong HMAC", func(t *testing.T) {
+		server := scramServer(t, SHA256, "token-id", "right")
+		c := &TokenSCRAMClient{HashGeneratorFcn: SHA256}
+		require.NoError(t, c.Begin("token-id", "wrong", ""))
+		first, _ := c.Step("")
+		serverFirst, _ := server.Step(first)
+		final, err := c.Step(serverFirst)
+		require.NoError(t, err)
+		serverFinal, _ := server.Step(final)
+		_, err = c.Step(serverFinal)
+		assert.Error(t, err)
+	})
+
+	t.Run("server nonce must extend ours", func(t *testing.T) {
+		c := &TokenSCRAMClient{HashGeneratorFcn: SHA256}
+		require.NoError(t, c.Begin("token-id", "x", ""))
+		_, _ = c.Step("")
+		_, err := c.Step("r=othernonce,s=c2FsdA==,i=4096")
+		assert.ErrorContains(t, err, "nonce")
+	})
+}
+
+func TestScramClientGenerator(t *testing.T) {
+	_, isToken := scramClientGenerator(true, SHA512)().(*TokenSCRAMClient)
+	assert.True(t, isToken)
+	_, isXDG := scramClientGenerator(false, SHA512)().(*XDGSCRAMClient)
+	assert.True(t, isXDG)
+
+	var _ sarama.SCRAMClient = &TokenSCRAMClient{}
+	assert.Equal(t, "a=3Db=2Cc", scramEscape("a=b,c"))
+	assert.True(t, strings.HasPrefix(scramAttrs("s=c2FsdA==,i=1")["s"], "c2FsdA=="))
+}
+
+func TestDelegationTokenAuthReadOncePerSession(t *testing.T) {
+	loads := 0
+	restore := loadTokenAuthClusters
+	loadTokenAuthClusters = func() map[string]bool {
+		loads++
+		return map[string]bool{"prod": true}
+	}
+	defer func() { loadTokenAuthClusters = restore }()
+
+	s := newSession()
+	assert.True(t, s.delegationTokenAuth("prod"))
+	assert.False(t, s.delegationTokenAuth("dev"))
+	assert.Equal(t, 1, loads, "the config is read once")
+
+	s.invalidate()
+	assert.True(t, s.delegationTokenAuth("prod"))
+	assert.Equal(t, 2, loads, "a context switch reads it again")
+}
@@ -0,0 +1,171 @@
+package kafds
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+)
+
+// scramSaltLen matches the salt length of Kafka's own ScramFormatter.
+const scramSaltLen = 32
+
+// errResourceNotFound is Errors.RESOURCE_NOT_FOUND, the per-user code for a
+// user or credential that does not exist. sarama v1.45.1 has no constant for it.
+const errResourceNotFound sarama.KError = 91
+
+// newScramSalt generates the random salt of an upserted credential; tests
+// replace it for deterministic requests.
+var newScramSalt = func() ([]byte, error) {
+	salt := make([]byte, scramSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// DescribeUserScramCredentials implements api.KafkaDataSource. Users named
+// explicitly but without any credential are omitted rather than reported as
+// errors.
+func (kp KafkaDataSourceKaf) DescribeUserScramCredentials(users []string) ([]api.ScramUser, error) {
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+
+	results, err := admin.DescribeUserScramCredentials(users)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe SCRAM credentials: %w", err)
+	}
+
+	out := make([]api.ScramUser, 0, len(results))
+	for _, r := range results {
+		if errors.Is(r.ErrorCode, errResourceNotFound) {
+			continue
+		}
+		if err := scramResultErr(r.User, r.ErrorCode, r.ErrorMessage); err != nil {
+			return nil, fmt.Errorf("failed to describe SCRAM credentials: %w", err)
+		}
+		user := api.ScramUser{Name: r.User}
+		for _, c := range r.CredentialInfos {
+			user.Credentials = append(user.Credentials, api.ScramCredentialInfo{
+				Mechanism:  api.ScramMechanism(c.Mechanism.String()),
+				Iterations: c.Iterations,
+			})
+		}
+		sort.Slice(user.Credentials, func(i, j int) bool {
+			return user.Credentials[i].Mechanism < user.Credentials[j].Mechanism
+		})
+		out = append(out, user)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// UpsertUserScramCredentials implements api.KafkaDataSource. Each credential
+// gets a fresh random salt; sarama derives the salted password client-side so
+// the clear-text password is never sent to the broker.
+func (kp KafkaDataSourceKaf) UpsertUserScramCredentials(upserts []api.ScramCredentialUpsert) error {
+	if len(upserts) == 0 {
+		return nil
+	}
+	reqs := make([]sarama.AlterUserScramCredentialsUpsert, 0, len(upserts))
+	for _, u := range upserts {
+		if err := api.ValidateScramUpsert(u); err != nil {
+			return err
+		}
+		salt, err := newScramSalt()
+		if err != nil {
+			return fmt.Errorf("failed to generate SCRAM salt: %w", err)
+		}
+		reqs = append(reqs, sarama.AlterUserScramCredentialsUpsert{
+			Name:       u.User,
+			Mechanism:  scramMechanismType(u.Mechanism),
+			Iterations: u.Iterations,
+			Salt:       salt,
+			Password:   []byte(u.Password),
+		})
+	}
+
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+	results, err := admin.UpsertUserScramCredentials(reqs)
+	if err != nil {
+		return fmt.Errorf("failed to upsert SCRAM credentials: %w", err)
+	}
+	return scramAlterErr("upsert", results)
+}
+
+// DeleteUserScramCredentials implements api.KafkaDataSource.
+func (kp KafkaDataSourceKaf) DeleteUserScramCredentials(deletions []api.ScramCredentialDeletion) error {
+	if len(deletions) == 0 {
+		return nil
+	}
+	reqs := make([]sarama.AlterUserScramCredentialsDelete, 0, len(deletions))
+	for _, d := range deletions {
+		if err := api.ValidateScramDeletion(d); err != nil {
+			return err
+		}
+		reqs = append(reqs, sarama.AlterUserScramCredentialsDelete{
+			Name:      d.User,
+			Mechanism: scramMechanismType(d.Mechanism),
+		})
+	}
+
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+	results, err := admin.DeleteUserScramCredentials(reqs)
+	if err != nil {
+		return fmt.Errorf("failed to delete SCRAM credentials: %w", err)
+	}
+	return scramAlterErr("delete", results)
+}
+
+func scramMechanismType(m api.ScramMechanism) sarama.ScramMechanismType {
+	switch m {
+	case api.ScramSHA256:
+		return sarama.SCRAM_MECHANISM_SHA_256
+	case api.ScramSHA512:
+		return sarama.SCRAM_MECHANISM_SHA_512
+	}
+	return sarama.SCRAM_MECHANISM_UNKNOWN
+}
+
+// scramAlterErr joins the per-user failures of an alter response.
+func scramAlterErr(op string, results []*sarama.AlterUserScramCredentialsResult) error {
+	var errs []error
+	for _, r := range results {
+		if err := scramResultErr(r.User, r.ErrorCode, r.ErrorMessage); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("failed to %s SCRAM credentials: %w", op, errors.Join(errs...))
+}
+
+// scramResultErr converts a per-user result code into an error naming the
+// user, preferring the broker's message over the generic code text.
+func scramResultErr(user string, code sarama.KError, msg *string) error {
+	if code == sarama.ErrNoError {
+		return nil
+	}
+	if msg != nil && *msg != "" {
+		return fmt.Errorf("user %q: %s: %w", user, *msg, code)
+	}
+	if code == errResourceNotFound {
+		return fmt.Errorf("user %q: credential not found: %w", user, code)
+	}
+	return fmt.Errorf("user %q: %w", user, code)
+}
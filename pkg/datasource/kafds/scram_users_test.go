@@ -0,0 +1,106 @@
+package kafds
+
+import (
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeUserScramCredentials(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockScramUsers: []*sarama.DescribeUserScramCredentialsResult{
+			{User: "bob", CredentialInfos: []*sarama.UserScramCredentialsResponseInfo{
+				{Mechanism: sarama.SCRAM_MECHANISM_SHA_512, Iterations: 8192},
+				{Mechanism: sarama.SCRAM_MECHANISM_SHA_256, Iterations: 4096},
+			}},
+			{User: "ghost", ErrorCode: errResourceNotFound},
+			{User: "alice", CredentialInfos: []*sarama.UserScramCredentialsResponseInfo{
+				{Mechanism: sarama.SCRAM_MECHANISM_SHA_256, Iterations: 4096},
+			}},
+		},
+	}
+	ds := mockAdminDS(admin)
+
+	got, err := ds.DescribeUserScramCredentials([]string{"alice", "bob", "ghost"})
+	require.NoError(t, err)
+	assert.Equal(t, []api.ScramUser{
+		{Name: "alice", Credentials: []api.ScramCredentialInfo{{Mechanism: api.ScramSHA256, Iterations: 4096}}},
+		{Name: "bob", Credentials: []api.ScramCredentialInfo{
+			{Mechanism: api.ScramSHA256, Iterations: 4096},
+			{Mechanism: api.ScramSHA512, Iterations: 8192},
+		}},
+	}, got, "sorted by name and mechanism; the unknown user is omitted")
+	assert.Equal(t, [][]string{{"alice", "bob", "ghost"}}, admin.DescribeScramCalls)
+}
+
+func TestDescribeUserScramCredentials_PerUserError(t *testing.T) {
+	msg := "not authorized"
+	admin := &MockClusterAdmin{
+		MockScramUsers: []*sarama.DescribeUserScramCredentialsResult{
+			{User: "alice", ErrorCode: sarama.ErrClusterAuthorizationFailed, ErrorMessage: &msg},
+		},
+	}
+	_, err := mockAdminDS(admin).DescribeUserScramCredentials(nil)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, sarama.ErrClusterAuthorizationFailed)
+	assert.Contains(t, err.Error(), `user "alice": not authorized`)
+}
+
+func TestUpsertUserScramCredentials(t *testing.T) {
+	orig := newScramSalt
+	newScramSalt = func() ([]byte, error) { return []byte("salt"), nil }
+	defer func() { newScramSalt = orig }()
+
+	admin := &MockClusterAdmin{}
+	ds := mockAdminDS(admin)
+
+	err := ds.UpsertUserScramCredentials([]api.ScramCredentialUpsert{
+		{User: "alice", Mechanism: api.ScramSHA512, Iterations: 8192, Password: "s3cret"},
+	})
+	require.NoError(t, err)
+	require.Len(t, admin.UpsertScramCalls, 1)
+	assert.Equal(t, []sarama.AlterUserScramCredentialsUpsert{{
+		Name:       "alice",
+		Mechanism:  sarama.SCRAM_MECHANISM_SHA_512,
+		Iterations: 8192,
+		Salt:       []byte("salt"),
+		Password:   []byte("s3cret"),
+	}}, admin.UpsertScramCalls[0])
+}
+
+func TestUpsertUserScramCredentials_ValidatesBeforeCall(t *testing.T) {
+	admin := &MockClusterAdmin{}
+	ds := mockAdminDS(admin)
+
+	err := ds.UpsertUserScramCredentials([]api.ScramCredentialUpsert{
+		{User: "alice", Mechanism: api.ScramSHA256, Iterations: 1000, Password: "x"},
+	})
+	var ve api.ScramValidationError
+	require.ErrorAs(t, err, &ve)
+	assert.Equal(t, "iterations", ve.Field)
+	assert.Empty(t, admin.UpsertScramCalls)
+}
+
+func TestDeleteUserScramCredentials_ResultErrors(t *testing.T) {
+	admin := &MockClusterAdmin{
+		MockScramAlterResults: []*sarama.AlterUserScramCredentialsResult{
+			{User: "alice"},
+			{User: "bob", ErrorCode: errResourceNotFound},
+		},
+	}
+	ds := mockAdminDS(admin)
+
+	err := ds.DeleteUserScramCredentials([]api.ScramCredentialDeletion{
+		{User: "alice", Mechanism: api.ScramSHA256},
+		{User: "bob", Mechanism: api.ScramSHA512},
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errResourceNotFound)
+	assert.Contains(t, err.Error(), `user "bob": credential not found`)
+	assert.NotContains(t, err.Error(), `user "alice"`)
+	require.Len(t, admin.DeleteScramCalls, 1)
+	assert.Equal(t, sarama.SCRAM_MECHANISM_SHA_512, admin.DeleteScramCalls[0][1].Mechanism)
+}
@@ -110,6 +110,11 @@ func TestResolvePartitionSeek(t *testing.T) {
 type fakeSyncProducer struct {
 	sent    *sarama.ProducerMessage
 	sendErr error
+
+	batch    []*sarama.ProducerMessage
+	batchErr error    // returned by SendMessages
+	txnErr   error    // returned by CommitTxn
+	txn      []string // BeginTxn/CommitTxn/AbortTxn calls in order
 }
 
 func (f *fakeSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
@@ -119,13 +124,21 @@ func (f *fakeSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int6
 	}
 	return msg.Partition, 1, nil
 }
-func (f *fakeSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error { return nil }
-func (f *fakeSyncProducer) Close() error                                      { return nil }
-func (f *fakeSyncProducer) TxnStatus() sarama.ProducerTxnStatusFlag           { return 0 }
-func (f *fakeSyncProducer) IsTransactional() bool                             { return false }
-func (f *fakeSyncProducer) BeginTxn() error                                   { return nil }
-func (f *fakeSyncProducer) CommitTxn() error                                  { return nil }
-func (f *fakeSyncProducer) AbortTxn() error                                   { return nil }
+
+// SendMessages assigns consecutive offsets the way a broker would.
+func (f *fakeSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	f.batch = msgs
+	for i, m := range msgs {
+		m.Offset = int64(100 + i)
+	}
+	return f.batchErr
+}
+func (f *fakeSyncProducer) Close() error                            { return nil }
+func (f *fakeSyncProducer) TxnStatus() sarama.ProducerTxnStatusFlag { return 0 }
+func (f *fakeSyncProducer) IsTransactional() bool                   { return false }
+func (f *fakeSyncProducer) BeginTxn() error                         { f.txn = append(f.txn, "begin"); return nil }
+func (f *fakeSyncProducer) CommitTxn() error                        { f.txn = append(f.txn, "commit"); return f.txnErr }
+func (f *fakeSyncProducer) AbortTxn() error                         { f.txn = append(f.txn, "abort"); return nil }
 func (f *fakeSyncProducer) AddOffsetsToTxn(map[string][]*sarama.PartitionOffsetMetadata, string) error {
 	return nil
 }
@@ -209,3 +222,106 @@ func TestDoProduce(t *testing.T) {
 		})
 	})
 }
+
+func TestDoProduceBatch(t *testing.T) {
+	client := &seekClient{MockClient: &MockClient{}, partitions: []int32{0, 1, 2}}
+	p1 := int32(1)
+	recs := []api.ProduceRecord{
+		{Value: []byte("a"), Partition: &p1},
+		{Value: []byte("b"), Partition: &p1},
+	}
+
+	t.Run("results carry partition and offset", func(t *testing.T) {
+		fake := &fakeSyncProducer{}
+		withFakeProducer(fake, func() {
+			res, err := doProduceBatch(context.Background(), client, "orders", recs, api.ProducerOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, []api.ProduceResult{{Partition: 1, Offset: 100}, {Partition: 1, Offset: 101}}, res)
+		})
+		require.Len(t, fake.batch, 2)
+		assert.Empty(t, fake.txn)
+	})
+
+	t.Run("acks none reports unknown offsets", func(t *testing.T) {
+		withFakeProducer(&fakeSyncProducer{}, func() {
+			res, err := doProduceBatch(context.Background(), client, "orders", recs, api.ProducerOptions{Acks: api.AcksNone})
+			require.NoError(t, err)
+			assert.Equal(t, int64(-1), res[0].Offset)
+		})
+	})
+
+	t.Run("partial failure maps errors per record", func(t *testing.T) {
+		withFakeProducer(&failSecondProducer{fakeSyncProducer: &fakeSyncProducer{}}, func() {
+			res, err := doProduceBatch(context.Background(), client, "orders", recs, api.ProducerOptions{})
+			var pe api.ProduceError
+			require.ErrorAs(t, err, &pe)
+			assert.Contains(t, pe.Reason, "1 of 2")
+			assert.NoError(t, res[0].Err)
+			assert.Equal(t, int64(100), res[0].Offset)
+			assert.ErrorIs(t, res[1].Err, sarama.ErrNotLeaderForPartition)
+		})
+	})
+
+	t.Run("transaction commits", func(t *testing.T) {
+		fake := &fakeSyncProducer{}
+		withFakeProducer(fake, func() {
+			_, err := doProduceBatch(context.Background(), client, "orders", recs, api.ProducerOptions{TransactionalID: "tx"})
+			require.NoError(t, err)
+		})
+		assert.Equal(t, []string{"begin", "commit"}, fake.txn)
+	})
+
+	t.Run("failed transaction aborts and fails every record", func(t *testing.T) {
+		fake := &fakeSyncProducer{batchErr: errors.New("fenced")}
+		withFakeProducer(fake, func() {
+			res, err := doProduceBatch(context.Background(), client, "orders", recs, api.ProducerOptions{TransactionalID: "tx"})
+			var pe api.ProduceError
+			require.ErrorAs(t, err, &pe)
+			assert.Equal(t, "transaction aborted", pe.Reason)
+			for _, r := range res {
+				assert.Error(t, r.Err)
+			}
+		})
+		assert.Equal(t, []string{"begin", "abort"}, fake.txn)
+	})
+
+	t.Run("bad partition rejects the whole batch before sending", func(t *testing.T) {
+		fake := &fakeSyncProducer{}
+		p9 := int32(9)
+		withFakeProducer(fake, func() {
+			_, err := doProduceBatch(context.Background(), client, "orders",
+				append(recs, api.ProduceRecord{Partition: &p9}), api.ProducerOptions{})
+			var pe api.PartitionError
+			assert.ErrorAs(t, err, &pe)
+		})
+		assert.Nil(t, fake.batch)
+	})
+}
+
+// failSecondProducer fails the second message of every batch.
+type failSecondProducer struct{ *fakeSyncProducer }
+
+func (f *failSecondProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	_ = f.fakeSyncProducer.SendMessages(msgs)
+	return sarama.ProducerErrors{{Msg: msgs[1], Err: sarama.ErrNotLeaderForPartition}}
+}
+
+func TestApplyProducerOptions(t *testing.T) {
+	cfg := sarama.NewConfig()
+	applyProducerOptions(cfg, api.ProducerOptions{
+		Compression: api.CompressionZstd, TransactionalID: "tx", Linger: 5 * time.Millisecond, BatchSize: 1024,
+	})
+	assert.Equal(t, sarama.CompressionZSTD, cfg.Producer.Compression)
+	assert.True(t, cfg.Producer.Idempotent)
+	assert.Equal(t, sarama.WaitForAll, cfg.Producer.RequiredAcks)
+	assert.Equal(t, 1, cfg.Net.MaxOpenRequests)
+	assert.Equal(t, "tx", cfg.Producer.Transaction.ID)
+	assert.Equal(t, 5*time.Millisecond, cfg.Producer.Flush.Frequency)
+	assert.Equal(t, 1024, cfg.Producer.Flush.Bytes)
+	assert.True(t, cfg.Version.IsAtLeast(sarama.V0_11_0_0))
+
+	cfg = sarama.NewConfig()
+	applyProducerOptions(cfg, api.ProducerOptions{Acks: api.AcksLeader})
+	assert.Equal(t, sarama.WaitForLocal, cfg.Producer.RequiredAcks)
+	assert.False(t, cfg.Producer.Idempotent)
+}
@@ -1,8 +1,6 @@
 package kafds
 
 import (
-	"sync"
-
 	"github.com/Benny93/kafui/pkg/appconfig"
 	"github.com/Benny93/kafui/pkg/serde"
 )
@@ -20,33 +18,23 @@ var loadSerdeConfigs = func(context string) []serde.SerdeConfig {
 	return nil
 }
 
-var (
-	serdeRegistryMu    sync.Mutex
-	cachedSerdeRegistry *serde.Registry
-)
-
-// invalidateSerdeRegistry drops the cached registry so it is rebuilt against the
-// new cluster's schema cache / serde config. Called on context switch/reload.
-func invalidateSerdeRegistry() {
-	serdeRegistryMu.Lock()
-	cachedSerdeRegistry = nil
-	serdeRegistryMu.Unlock()
-}
-
-// getSerdeRegistry returns the process-cached serde registry for the active
-// cluster, building it on first use. The schema-registry serde reuses the
-// existing Avro cache; Avro decode therefore flows through the same path as
-// before. On a build error it falls back to a built-in-only registry so
-// decoding still works.
-func getSerdeRegistry() *serde.Registry {
-	serdeRegistryMu.Lock()
-	defer serdeRegistryMu.Unlock()
-	if cachedSerdeRegistry != nil {
-		return cachedSerdeRegistry
+// getSerdeRegistry returns the session-cached serde registry for the active
+// cluster, building it on first use. The cache is dropped on context
+// switch/reload so it is rebuilt against the new cluster's schema cache / serde
+// config. The schema-registry serde reuses the existing Avro cache; Avro decode
+// therefore flows through the same path as before. On a build error it falls
+// back to a built-in-only registry so decoding still works.
+func (kp KafkaDataSourceKaf) getSerdeRegistry() *serde.Registry {
+	s := kp.sess()
+	s.mu.Lock()
+	cached, cluster := s.serdeRegistry, s.current
+	s.mu.Unlock()
+	if cached != nil {
+		return cached
 	}
 
 	decode := func(data []byte) ([]byte, error) {
-		cache, err := getOrInitSchemaCache()
+		cache, err := kp.getOrInitSchemaCache()
 		if err != nil {
 			return nil, err
 		}
@@ -54,14 +42,19 @@ func getSerdeRegistry() *serde.Registry {
 	}
 
 	context := ""
-	if currentCluster != nil {
-		context = currentCluster.Name
+	if cluster != nil {
+		context = cluster.Name
 	}
 	reg, err := serde.BuildRegistry(decode, loadSerdeConfigs(context))
 	if err != nil {
 		// Bad config (e.g. a missing descriptor file) must not break decoding.
 		reg, _ = serde.BuildRegistry(decode, nil)
 	}
-	cachedSerdeRegistry = reg
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current == cluster {
+		s.serdeRegistry = reg
+	}
 	return reg
 }
@@ -13,18 +13,11 @@ import (
 // TestDecodeMessageRoutesThroughRegistry verifies DecodeMessage decodes raw
 // bytes via the serde registry and records the winning serde name.
 func TestDecodeMessageRoutesThroughRegistry(t *testing.T) {
-	origCluster := currentCluster
 	origLoad := loadSerdeConfigs
-	t.Cleanup(func() {
-		currentCluster = origCluster
-		loadSerdeConfigs = origLoad
-		invalidateSerdeRegistry()
-	})
-	currentCluster = nil // no schema registry
+	t.Cleanup(func() { loadSerdeConfigs = origLoad })
 	loadSerdeConfigs = func(string) []serde.SerdeConfig { return nil }
-	invalidateSerdeRegistry()
 
-	kp := KafkaDataSourceKaf{}
+	kp := NewKafkaDataSourceKaf() // no cluster, so no schema registry
 	msg := api.Message{RawValue: []byte(`{"a":1}`)}
 	out, err := kp.DecodeMessage(context.Background(), msg)
 	require.NoError(t, err)
@@ -33,18 +26,11 @@ func TestDecodeMessageRoutesThroughRegistry(t *testing.T) {
 }
 
 func TestListSerdesFromRegistry(t *testing.T) {
-	origCluster := currentCluster
 	origLoad := loadSerdeConfigs
-	t.Cleanup(func() {
-		currentCluster = origCluster
-		loadSerdeConfigs = origLoad
-		invalidateSerdeRegistry()
-	})
-	currentCluster = nil
+	t.Cleanup(func() { loadSerdeConfigs = origLoad })
 	loadSerdeConfigs = func(string) []serde.SerdeConfig { return nil }
-	invalidateSerdeRegistry()
 
-	names := KafkaDataSourceKaf{}.ListSerdes()
+	names := NewKafkaDataSourceKaf().ListSerdes()
 	assert.Contains(t, names, serde.NameJSON)
 	assert.Contains(t, names, serde.NameString)
 	assert.Contains(t, names, serde.NameSchemaRegistry)
@@ -0,0 +1,278 @@
+package kafds
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/analysis"
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/serde"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+	"github.com/birdayz/kaf/pkg/avro"
+	"github.com/birdayz/kaf/pkg/config"
+)
+
+// Overrides are CLI values applied on top of the kaf config when the
+// datasource is initialised. Empty/nil fields leave the corresponding config
+// value untouched.
+type Overrides struct {
+	Brokers        []string
+	SchemaRegistry string
+	Cluster        string
+	Verbose        bool
+}
+
+// session is the connection state owned by a single KafkaDataSourceKaf: the
+// loaded kaf config, the active cluster and every cache derived from it.
+// Nothing in here is shared between datasources, so two instances can target
+// different clusters at the same time.
+type session struct {
+	mu        sync.Mutex
+	cfgFile   string
+	overrides Overrides
+	cfg       config.Config
+	current   *config.Cluster
+
+	// conns pools the sarama client and admin of the active cluster. It is
+	// replaced (and the old pool closed) by invalidate().
+	conns *connPool
+
+	// Lazily built caches, dropped by invalidate() on a context switch.
+	schemaCache   *avro.SchemaCache
+	serdeRegistry *serde.Registry
+	tokenProvider *tokenProvider
+	// tokenAuth holds the clusters with delegation-token logins, read from
+	// the kafui config on first use.
+	tokenAuth map[string]bool
+
+	// coordination is the active cluster's "KRaft" or "ZooKeeper" mode once
+	// detected at coordinationAt; it is probed again after coordinationTTL,
+	// since a cluster migrating from ZooKeeper to KRaft changes it. noQuorum
+	// records that the cluster does not answer DescribeQuorum.
+	coordination   string
+	coordinationAt time.Time
+	noQuorum       bool
+
+	// groupDetails caches enriched consumer-group rows for groupDetailTTL;
+	// deletionEnabled is the cluster's delete.topic.enable once read.
+	groupDetails    map[string]groupDetailCacheEntry
+	deletionEnabled *bool
+
+	// peers are sessions pinned to the other configured clusters, built on
+	// demand for per-cluster reads such as the cluster overview statistics.
+	peers map[string]*session
+
+	// analysis survives context switches: running scans keep their results.
+	analysis *analysis.Registry
+}
+
+// coordinationTTL is how long a detected coordination mode is trusted.
+var coordinationTTL = 5 * time.Minute
+
+func newSession() *session {
+	return &session{conns: newConnPool()}
+}
+
+// activeCluster returns the cluster the session currently talks to, or nil
+// before Init.
+func (s *session) activeCluster() *config.Cluster {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// clusters returns the configured kaf clusters.
+func (s *session) clusters() []*config.Cluster {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg.Clusters
+}
+
+// load reads the kaf config at cfgFile (empty ⇒ ~/.kaf/config) and applies the
+// session overrides. A missing or unreadable file falls back to an empty
+// config whose active cluster is localhost:9092.
+func (s *session) load(cm ConfigManager, tolerateMissing bool) error {
+	cfg, err := cm.ReadConfig(s.cfgFile)
+	if err != nil {
+		if !tolerateMissing {
+			return err
+		}
+		// Instead of failing, fall back to a default config.
+		shared.Log.Warn("could not read config file, using defaults", "err", err)
+		cfg = config.Config{Clusters: []*config.Cluster{}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+	s.cfg.ClusterOverride = s.overrides.Cluster
+
+	if cluster := s.cfg.ActiveCluster(); cluster != nil {
+		s.current = cluster
+	} else {
+		// Create sane default if not configured
+		s.current = &config.Cluster{
+			Brokers: []string{"localhost:9092"},
+		}
+	}
+
+	// Any set overrides take precedence over the configuration.
+	if s.overrides.SchemaRegistry != "" {
+		s.current.SchemaRegistryURL = s.overrides.SchemaRegistry
+		s.current.SchemaRegistryCredentials = nil
+	}
+	if s.overrides.Brokers != nil {
+		s.current.Brokers = s.overrides.Brokers
+	}
+	s.invalidateLocked()
+	return nil
+}
+
+// connections returns the connection pool of the active cluster.
+func (s *session) connections() *connPool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns == nil {
+		s.conns = newConnPool()
+	}
+	return s.conns
+}
+
+// invalidate drops every cache derived from the active cluster and tears down
+// its pooled connections.
+func (s *session) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invalidateLocked()
+}
+
+func (s *session) invalidateLocked() {
+	if old := s.conns; old != nil {
+		// Leased connections stay open until their callers release them;
+		// close off the lock since idle ones are closed right away.
+		go old.close()
+	}
+	s.conns = newConnPool()
+	s.schemaCache = nil
+	s.serdeRegistry = nil
+	s.tokenProvider = nil
+	s.tokenAuth = nil
+	s.coordination, s.noQuorum = "", false
+	s.groupDetails = nil
+	s.deletionEnabled = nil
+	for _, p := range s.peers {
+		p.invalidate()
+	}
+	s.peers = nil
+}
+
+// peer returns the session for the configured cluster name: s itself for the
+// active cluster, otherwise a session pinned to that cluster, created on first
+// use and dropped with s's caches. ok is false for an unknown name.
+func (s *session) peer(name string) (p *session, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != nil && s.current.Name == name {
+		return s, true
+	}
+	if p := s.peers[name]; p != nil {
+		return p, true
+	}
+	for _, cluster := range s.cfg.Clusters {
+		if cluster.Name != name {
+			continue
+		}
+		p := &session{cfgFile: s.cfgFile, cfg: s.cfg, current: cluster, conns: newConnPool()}
+		p.cfg.CurrentCluster, p.cfg.ClusterOverride = name, ""
+		if s.peers == nil {
+			s.peers = map[string]*session{}
+		}
+		s.peers[name] = p
+		return p, true
+	}
+	return nil, false
+}
+
+// oauthTokenProvider returns the session's OAUTHBEARER token provider for the
+// active cluster, creating it on first use.
+func (s *session) oauthTokenProvider() *tokenProvider {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokenProvider == nil {
+		s.tokenProvider = newTokenProvider(s.current)
+	}
+	return s.tokenProvider
+}
+
+// delegationTokenAuth reports whether cluster logs in with a delegation token.
+func (s *session) delegationTokenAuth(cluster string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokenAuth == nil {
+		s.tokenAuth = loadTokenAuthClusters()
+	}
+	return s.tokenAuth[cluster]
+}
+
+// cachedCoordination returns the detected coordination mode ("" before
+// detection or once it is older than coordinationTTL) and whether
+// DescribeQuorum is known to be unsupported.
+func (s *session) cachedCoordination() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.coordination == "" || time.Since(s.coordinationAt) > coordinationTTL {
+		return "", false
+	}
+	return s.coordination, s.noQuorum
+}
+
+func (s *session) setCoordination(mode string, noQuorum bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.coordination, s.coordinationAt, s.noQuorum = mode, time.Now(), noQuorum
+}
+
+// cachedGroupDetail returns the cached row for a group while it is younger
+// than groupDetailTTL.
+func (s *session) cachedGroupDetail(id string) (api.ConsumerGroup, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.groupDetails[id]
+	if !ok || time.Since(e.at) >= groupDetailTTL {
+		return api.ConsumerGroup{}, false
+	}
+	return e.group, true
+}
+
+func (s *session) setGroupDetail(id string, group api.ConsumerGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.groupDetails == nil {
+		s.groupDetails = map[string]groupDetailCacheEntry{}
+	}
+	s.groupDetails[id] = groupDetailCacheEntry{group: group, at: time.Now()}
+}
+
+// dropGroupDetail forgets a group's cached row after a mutation.
+func (s *session) dropGroupDetail(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.groupDetails, id)
+}
+
+// cachedDeletionEnabled returns delete.topic.enable once read for the active
+// cluster.
+func (s *session) cachedDeletionEnabled() (enabled, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.deletionEnabled == nil {
+		return false, false
+	}
+	return *s.deletionEnabled, true
+}
+
+func (s *session) setDeletionEnabled(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletionEnabled = &enabled
+}
@@ -1,12 +1,12 @@
 package kafds
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
@@ -24,9 +24,9 @@ var (
 )
 
 // fetchTopicOffsets returns earliest/latest offsets per partition. It is a seam:
-// getClient() talks to a real broker, so tests override this to inject offsets.
-var fetchTopicOffsets = func(topic string, partitions []int32) (map[int32]offsets, error) {
-	client, err := getClient()
+// kp.getClient() talks to a real broker, so tests override this to inject offsets.
+var fetchTopicOffsets = func(kp KafkaDataSourceKaf, topic string, partitions []int32) (map[int32]offsets, error) {
+	client, err := kp.getClient()
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +42,26 @@ var fetchTopicOffsets = func(topic string, partitions []int32) (map[int32]offset
 	return out, nil
 }
 
+// fetchOffsetsForTime resolves, per partition, the first offset whose record
+// timestamp is at or after ts (Unix millis); -1 when there is none. A seam like
+// fetchTopicOffsets.
+var fetchOffsetsForTime = func(kp KafkaDataSourceKaf, topic string, partitions []int32, ts int64) (map[int32]int64, error) {
+	client, err := kp.getClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	out := make(map[int32]int64, len(partitions))
+	for _, p := range partitions {
+		o, err := client.GetOffset(topic, p, ts)
+		if err != nil {
+			return nil, fmt.Errorf("resolving offset by time for %s/%d: %w", topic, p, err)
+		}
+		out[p] = o
+	}
+	return out, nil
+}
+
 // --- TP-2: GetTopicConfig ---
 
 // GetTopicConfig implements api.KafkaDataSource. On an authorization failure it
@@ -53,10 +73,11 @@ var fetchTopicOffsets = func(topic string, partitions []int32) (map[int32]offset
 // which can populate synonyms. Wiring a synonym-aware describe would need a new
 // admin method beyond the pass-through interface.
 func (kp KafkaDataSourceKaf) GetTopicConfig(topicName string) ([]api.TopicConfigEntry, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 	entries, err := admin.DescribeConfig(sarama.ConfigResource{
 		Type: sarama.TopicResource,
 		Name: topicName,
@@ -106,10 +127,11 @@ func deriveDefault(e sarama.ConfigEntry) string {
 
 // GetTopicDetails implements api.KafkaDataSource.
 func (kp KafkaDataSourceKaf) GetTopicDetails(topicName string) (api.TopicDetails, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return api.TopicDetails{}, err
 	}
+	defer admin.Close()
 	md, err := admin.DescribeTopics([]string{topicName})
 	if err != nil {
 		return api.TopicDetails{}, fmt.Errorf("describing topic %q: %w", topicName, err)
@@ -128,7 +150,7 @@ func (kp KafkaDataSourceKaf) GetTopicDetails(topicName string) (api.TopicDetails
 			ids = append(ids, p.ID)
 		}
 	}
-	offs, _ := fetchTopicOffsets(topicName, ids) // best effort
+	offs, _ := fetchTopicOffsets(kp, topicName, ids) // best effort
 	return buildTopicDetails(t, offs), nil
 }
 
@@ -175,10 +197,11 @@ func buildTopicDetails(t *sarama.TopicMetadata, offs map[int32]offsets) api.Topi
 // otherwise blocks the same tea.Cmd that resolves the OSR column, making both
 // spin forever in the topics table.
 func (kp KafkaDataSourceKaf) GetTopicSizes(topicNames []string) (map[string]int64, error) {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return nil, err
 	}
+	defer admin.Close()
 	brokers, _, err := admin.DescribeCluster()
 	if err != nil {
 		return nil, fmt.Errorf("describing cluster: %w", err)
@@ -254,10 +277,11 @@ func aggregateTopicSizes(logDirs map[int32][]sarama.DescribeLogDirsResponseDirMe
 
 // CreateTopic implements api.KafkaDataSource.
 func (kp KafkaDataSourceKaf) CreateTopic(name string, numPartitions int32, replicationFactor int16, configs map[string]*string) error {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	detail := &sarama.TopicDetail{
 		NumPartitions:     numPartitions,
 		ReplicationFactor: replicationFactor,
@@ -302,10 +326,11 @@ func mapTopicCreateError(name string, err error) error {
 
 // DeleteTopic implements api.KafkaDataSource.
 func (kp KafkaDataSourceKaf) DeleteTopic(name string) error {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	if err := admin.DeleteTopic(name); err != nil {
 		if errors.Is(err, sarama.ErrUnknownTopicOrPartition) {
 			return api.TopicNotFoundError{TopicName: name, Cause: err}
@@ -315,33 +340,18 @@ func (kp KafkaDataSourceKaf) DeleteTopic(name string) error {
 	return nil
 }
 
-var (
-	deletionEnabledCache   = map[string]bool{}
-	deletionEnabledCacheMu sync.Mutex
-)
-
-// resetTopicDeletionCache clears the per-context capability cache (test helper).
-func resetTopicDeletionCache() {
-	deletionEnabledCacheMu.Lock()
-	deletionEnabledCache = map[string]bool{}
-	deletionEnabledCacheMu.Unlock()
-}
-
 // IsTopicDeletionEnabled implements api.KafkaDataSource. It reads the controller
 // broker's delete.topic.enable config; missing/unparseable defaults to true.
 func (kp KafkaDataSourceKaf) IsTopicDeletionEnabled() (bool, error) {
-	ctx := kp.GetContext()
-	deletionEnabledCacheMu.Lock()
-	if v, ok := deletionEnabledCache[ctx]; ok {
-		deletionEnabledCacheMu.Unlock()
+	if v, ok := kp.sess().cachedDeletionEnabled(); ok {
 		return v, nil
 	}
-	deletionEnabledCacheMu.Unlock()
 
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return false, err
 	}
+	defer admin.Close()
 	_, controllerID, err := admin.DescribeCluster()
 	if err != nil {
 		return false, fmt.Errorf("describing cluster: %w", err)
@@ -357,9 +367,7 @@ func (kp KafkaDataSourceKaf) IsTopicDeletionEnabled() (bool, error) {
 		return false, fmt.Errorf("describing broker config: %w", err)
 	}
 	enabled := parseDeletionEnabled(entries)
-	deletionEnabledCacheMu.Lock()
-	deletionEnabledCache[ctx] = enabled
-	deletionEnabledCacheMu.Unlock()
+	kp.sess().setDeletionEnabled(enabled)
 	return enabled, nil
 }
 
@@ -383,10 +391,11 @@ func parseDeletionEnabled(entries []sarama.ConfigEntry) bool {
 // UpdateTopicConfig implements api.KafkaDataSource via an incremental alter so
 // unrelated dynamic configs are preserved. A nil value deletes the key.
 func (kp KafkaDataSourceKaf) UpdateTopicConfig(name string, entries map[string]*string) error {
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	ops := make(map[string]sarama.IncrementalAlterConfigsEntry, len(entries))
 	for key, val := range entries {
 		if val == nil {
@@ -418,10 +427,11 @@ func (kp KafkaDataSourceKaf) IncreasePartitions(name string, totalCount int32) e
 	case totalCount == current:
 		return api.PartitionNoopError{TopicName: name, Current: current}
 	}
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	if err := admin.CreatePartitions(name, totalCount, nil, false); err != nil {
 		return fmt.Errorf("increasing partitions for %q: %w", name, err)
 	}
@@ -453,16 +463,124 @@ func (kp KafkaDataSourceKaf) PurgeTopicMessages(name string, partition int32) er
 	if len(offsets) == 0 {
 		return api.PartitionError{Message: "partition not found", TopicName: name, PartitionID: partition}
 	}
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	if err := admin.DeleteRecords(name, offsets); err != nil {
 		return fmt.Errorf("purging messages for %q: %w", name, err)
 	}
 	return nil
 }
 
+// DeleteRecordsBefore implements api.KafkaDataSource. A time is resolved per
+// partition via GetOffset; a partition with no record at or after it is
+// deleted up to its high-watermark.
+func (kp KafkaDataSourceKaf) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	if err := validateDeleteRecordsRequest(req); err != nil {
+		return nil, err
+	}
+	policy, err := kp.topicCleanupPolicy(req.Topic)
+	if err != nil {
+		return nil, err
+	}
+	if !cleanupPolicyAllowsDelete(policy) {
+		return nil, api.CleanupPolicyError{TopicName: req.Topic, Policy: policy}
+	}
+	details, err := kp.GetTopicDetails(req.Topic)
+	if err != nil {
+		return nil, err
+	}
+	targets := req.Offsets
+	if req.Before != nil {
+		partitions := req.Partitions
+		if len(partitions) == 0 {
+			for _, p := range details.Partitions {
+				partitions = append(partitions, p.ID)
+			}
+		}
+		resolved, err := fetchOffsetsForTime(kp, req.Topic, partitions, req.Before.UnixMilli())
+		if err != nil {
+			return nil, err
+		}
+		targets = make(map[int32]int64, len(partitions))
+		for _, p := range partitions {
+			targets[p] = resolved[p]
+		}
+	}
+	out, offsets, err := planRecordDeletion(req.Topic, details.Partitions, targets)
+	if err != nil {
+		return nil, err
+	}
+	if len(offsets) == 0 {
+		return out, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	admin, err := kp.getClusterAdmin()
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+	if err := admin.DeleteRecords(req.Topic, offsets); err != nil {
+		return nil, fmt.Errorf("deleting records for %q: %w", req.Topic, err)
+	}
+	return out, nil
+}
+
+// validateDeleteRecordsRequest checks a request before any broker call.
+func validateDeleteRecordsRequest(req api.DeleteRecordsRequest) error {
+	switch {
+	case req.Topic == "":
+		return api.InvalidDeleteRecordsError{Reason: "a topic is required"}
+	case req.Before == nil && len(req.Offsets) == 0:
+		return api.InvalidDeleteRecordsError{Reason: "either per-partition offsets or a time is required"}
+	case req.Before != nil && len(req.Offsets) > 0:
+		return api.InvalidDeleteRecordsError{Reason: "offsets and a time are mutually exclusive"}
+	}
+	for p, off := range req.Offsets {
+		if off < 0 {
+			return api.InvalidDeleteRecordsError{Reason: fmt.Sprintf("negative offset %d for partition %d", off, p)}
+		}
+	}
+	return nil
+}
+
+// planRecordDeletion checks each target against its partition's offsets and
+// returns the sorted per-partition outcome plus the offsets worth sending: a
+// target at or below the log start deletes nothing. A negative target (no
+// record at or after the requested time) means the high-watermark.
+func planRecordDeletion(topic string, partitions []api.PartitionInfo, targets map[int32]int64) ([]api.RecordDeletion, map[int32]int64, error) {
+	byID := make(map[int32]api.PartitionInfo, len(partitions))
+	for _, p := range partitions {
+		byID[p.ID] = p
+	}
+	out := make([]api.RecordDeletion, 0, len(targets))
+	offsets := make(map[int32]int64)
+	for id, target := range targets {
+		p, ok := byID[id]
+		if !ok {
+			return nil, nil, api.PartitionError{Message: "partition not found", TopicName: topic, PartitionID: id}
+		}
+		if target < 0 {
+			target = p.LatestOffset
+		}
+		if target > p.LatestOffset {
+			return nil, nil, api.InvalidDeleteRecordsError{Reason: fmt.Sprintf("offset %d is past the end (%d) of partition %d", target, p.LatestOffset, id)}
+		}
+		d := api.RecordDeletion{Partition: id, Offset: max(target, p.EarliestOffset)}
+		if target > p.EarliestOffset {
+			d.Deleted = target - p.EarliestOffset
+			offsets[id] = target
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Partition < out[j].Partition })
+	return out, offsets, nil
+}
+
 // topicCleanupPolicy returns the effective cleanup.policy for a topic ("delete"
 // when the key is absent, matching Kafka's default).
 func (kp KafkaDataSourceKaf) topicCleanupPolicy(name string) (string, error) {
@@ -549,10 +667,11 @@ func (kp KafkaDataSourceKaf) ChangeReplicationFactor(name string, newFactor int1
 	if err != nil {
 		return err
 	}
-	admin, err := getClusterAdmin()
+	admin, err := kp.getClusterAdmin()
 	if err != nil {
 		return err
 	}
+	defer admin.Close()
 	brokers, _, err := admin.DescribeCluster()
 	if err != nil {
 		return fmt.Errorf("describing cluster: %w", err)
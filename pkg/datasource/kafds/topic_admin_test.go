@@ -1,29 +1,17 @@
 package kafds
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/IBM/sarama"
-	"github.com/birdayz/kaf/pkg/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// installAdminIface wires an arbitrary ClusterAdminInterface as the active admin.
-func installAdminIface(admin ClusterAdminInterface) func() {
-	origFactory := kafkaClientFactory
-	origCluster := currentCluster
-	kafkaClientFactory = &MockKafkaClientFactory{MockClusterAdmin: admin}
-	currentCluster = &config.Cluster{Brokers: []string{"localhost:9092"}}
-	return func() {
-		kafkaClientFactory = origFactory
-		currentCluster = origCluster
-	}
-}
-
 // withFastRetries shrinks the bounded-retry knobs for tests and restores them.
 func withFastRetries(t *testing.T) {
 	t.Helper()
@@ -41,7 +29,7 @@ func withFastRetries(t *testing.T) {
 func withOffsets(t *testing.T, offs map[int32]offsets) {
 	t.Helper()
 	orig := fetchTopicOffsets
-	fetchTopicOffsets = func(topic string, partitions []int32) (map[int32]offsets, error) {
+	fetchTopicOffsets = func(_ KafkaDataSourceKaf, topic string, partitions []int32) (map[int32]offsets, error) {
 		return offs, nil
 	}
 	t.Cleanup(func() { fetchTopicOffsets = orig })
@@ -65,10 +53,9 @@ func TestGetTopicConfig(t *testing.T) {
 			}},
 			{Name: "secret.key", Value: "", Source: sarama.SourceDefault, Sensitive: true},
 		}}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		entries, err := brokerDS().GetTopicConfig("t")
+		entries, err := ds.GetTopicConfig("t")
 		require.NoError(t, err)
 		byName := map[string]api.TopicConfigEntry{}
 		for _, e := range entries {
@@ -81,12 +68,10 @@ func TestGetTopicConfig(t *testing.T) {
 	})
 
 	t.Run("authorization failure returns empty slice", func(t *testing.T) {
-		restore := installMockAdmin(&MockClusterAdmin{})
-		defer restore()
-		// Swap in an admin whose DescribeConfig returns an authz error.
-		kafkaClientFactory = &MockKafkaClientFactory{MockClusterAdmin: authzErrAdmin{&MockClusterAdmin{}}}
+		// An admin whose DescribeConfig returns an authz error.
+		ds := mockAdminDS(authzErrAdmin{&MockClusterAdmin{}})
 
-		entries, err := brokerDS().GetTopicConfig("t")
+		entries, err := ds.GetTopicConfig("t")
 		require.NoError(t, err)
 		assert.Empty(t, entries)
 	})
@@ -120,10 +105,9 @@ func TestBuildTopicDetails(t *testing.T) {
 }
 
 func TestGetTopicDetails_NotFound(t *testing.T) {
-	restore := installMockAdmin(&MockClusterAdmin{MockTopicMetadata: nil})
-	defer restore()
+	ds := mockAdminDS(&MockClusterAdmin{MockTopicMetadata: nil})
 
-	_, err := brokerDS().GetTopicDetails("missing")
+	_, err := ds.GetTopicDetails("missing")
 	var nf api.TopicNotFoundError
 	assert.True(t, errors.As(err, &nf))
 }
@@ -167,10 +151,9 @@ func TestCreateTopic(t *testing.T) {
 
 	t.Run("success waits for visibility", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockTopicMetadata: []*sarama.TopicMetadata{{Name: "new", Err: sarama.ErrNoError}}}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().CreateTopic("new", 3, 2, map[string]*string{})
+		err := ds.CreateTopic("new", 3, 2, map[string]*string{})
 		require.NoError(t, err)
 		require.Len(t, admin.CreateTopicCalls, 1)
 		assert.Equal(t, int32(3), admin.CreateTopicCalls[0].Detail.NumPartitions)
@@ -179,30 +162,27 @@ func TestCreateTopic(t *testing.T) {
 
 	t.Run("already exists mapped", func(t *testing.T) {
 		admin := &MockClusterAdmin{CreateTopicErr: sarama.ErrTopicAlreadyExists}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().CreateTopic("dup", 1, 1, nil)
+		err := ds.CreateTopic("dup", 1, 1, nil)
 		var e api.TopicAlreadyExistsError
 		assert.True(t, errors.As(err, &e))
 	})
 
 	t.Run("validation error mapped", func(t *testing.T) {
 		admin := &MockClusterAdmin{CreateTopicErr: sarama.ErrInvalidReplicationFactor}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().CreateTopic("bad", 1, 99, nil)
+		err := ds.CreateTopic("bad", 1, 99, nil)
 		var e api.TopicValidationError
 		assert.True(t, errors.As(err, &e))
 	})
 
 	t.Run("visibility exhaustion returns timeout", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockTopicMetadata: nil} // never visible
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().CreateTopic("ghost", 1, 1, nil)
+		err := ds.CreateTopic("ghost", 1, 1, nil)
 		var e api.MetadataTimeoutError
 		assert.True(t, errors.As(err, &e))
 	})
@@ -213,16 +193,14 @@ func TestCreateTopic(t *testing.T) {
 func TestDeleteTopic(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		admin := &MockClusterAdmin{}
-		restore := installMockAdmin(admin)
-		defer restore()
-		require.NoError(t, brokerDS().DeleteTopic("t"))
+		ds := mockAdminDS(admin)
+		require.NoError(t, ds.DeleteTopic("t"))
 		assert.Equal(t, []string{"t"}, admin.DeleteTopicCalls)
 	})
 	t.Run("unknown topic mapped", func(t *testing.T) {
 		admin := &MockClusterAdmin{DeleteTopicErr: sarama.ErrUnknownTopicOrPartition}
-		restore := installMockAdmin(admin)
-		defer restore()
-		err := brokerDS().DeleteTopic("t")
+		ds := mockAdminDS(admin)
+		err := ds.DeleteTopic("t")
 		var e api.TopicNotFoundError
 		assert.True(t, errors.As(err, &e))
 	})
@@ -247,24 +225,21 @@ func TestParseDeletionEnabled(t *testing.T) {
 }
 
 func TestIsTopicDeletionEnabled(t *testing.T) {
-	resetTopicDeletionCache()
-	admin := &MockClusterAdmin{
-		MockBrokers:      []*sarama.Broker{newTestBroker(1, "h:9092")},
-		MockControllerID: 1,
-		MockConfigEntries: []sarama.ConfigEntry{
-			{Name: "delete.topic.enable", Value: "false"},
-		},
+	dsWith := func(value string) KafkaDataSourceKaf {
+		return mockAdminDS(&MockClusterAdmin{
+			MockBrokers:       []*sarama.Broker{newTestBroker(1, "h:9092")},
+			MockControllerID:  1,
+			MockConfigEntries: []sarama.ConfigEntry{{Name: "delete.topic.enable", Value: value}},
+		})
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	disabled, enabled := dsWith("false"), dsWith("true")
 
-	// IsTopicDeletionEnabled keys its cache by GetContext(), which reads the
-	// config manager — use a DS with real deps so it does not deref a nil one.
-	ds := NewKafkaDataSourceKafWithDeps(kafkaClientFactory, &DefaultConfigManager{})
-	enabled, err := ds.IsTopicDeletionEnabled()
+	got, err := disabled.IsTopicDeletionEnabled()
 	require.NoError(t, err)
-	assert.False(t, enabled)
-	resetTopicDeletionCache()
+	assert.False(t, got)
+	got, err = enabled.IsTopicDeletionEnabled()
+	require.NoError(t, err)
+	assert.True(t, got, "the capability is cached per datasource")
 }
 
 // --- TP-7: UpdateTopicConfig ---
@@ -272,22 +247,20 @@ func TestIsTopicDeletionEnabled(t *testing.T) {
 func TestUpdateTopicConfig(t *testing.T) {
 	t.Run("sends exactly the passed entries", func(t *testing.T) {
 		admin := &MockClusterAdmin{}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
 		v := "1000"
-		err := brokerDS().UpdateTopicConfig("t", map[string]*string{"retention.ms": &v})
+		err := ds.UpdateTopicConfig("t", map[string]*string{"retention.ms": &v})
 		require.NoError(t, err)
 		require.Len(t, admin.IncrementalAlterConfigCalls, 1)
 		assert.Equal(t, AlterConfigCall{Name: "t", Key: "retention.ms", Value: "1000"}, admin.IncrementalAlterConfigCalls[0])
 	})
 	t.Run("cluster rejection mapped", func(t *testing.T) {
 		admin := &MockClusterAdmin{AlterConfigErr: errors.New("policy violation")}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
 		v := "1"
-		err := brokerDS().UpdateTopicConfig("t", map[string]*string{"k": &v})
+		err := ds.UpdateTopicConfig("t", map[string]*string{"k": &v})
 		var e api.InvalidConfigError
 		assert.True(t, errors.As(err, &e))
 	})
@@ -308,19 +281,17 @@ func TestIncreasePartitions(t *testing.T) {
 
 	t.Run("increase ok", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockTopicMetadata: metaWithPartitions("t", 3)}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		require.NoError(t, brokerDS().IncreasePartitions("t", 6))
+		require.NoError(t, ds.IncreasePartitions("t", 6))
 		require.Len(t, admin.CreatePartitionsCalls, 1)
 		assert.Equal(t, int32(6), admin.CreatePartitionsCalls[0].Count)
 	})
 	t.Run("decrease rejected", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockTopicMetadata: metaWithPartitions("t", 3)}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().IncreasePartitions("t", 2)
+		err := ds.IncreasePartitions("t", 2)
 		var e api.PartitionDecreaseError
 		require.True(t, errors.As(err, &e))
 		assert.Equal(t, int32(3), e.Current)
@@ -328,10 +299,9 @@ func TestIncreasePartitions(t *testing.T) {
 	})
 	t.Run("equal rejected", func(t *testing.T) {
 		admin := &MockClusterAdmin{MockTopicMetadata: metaWithPartitions("t", 3)}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().IncreasePartitions("t", 3)
+		err := ds.IncreasePartitions("t", 3)
 		var e api.PartitionNoopError
 		assert.True(t, errors.As(err, &e))
 		assert.Empty(t, admin.CreatePartitionsCalls)
@@ -350,10 +320,9 @@ func TestPurgeTopicMessages(t *testing.T) {
 			MockTopicMetadata: metaWithPartitions("t", 2),
 			MockConfigEntries: deleteCfg,
 		}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		require.NoError(t, brokerDS().PurgeTopicMessages("t", -1))
+		require.NoError(t, ds.PurgeTopicMessages("t", -1))
 		require.Len(t, admin.DeleteRecordsCalls, 1)
 		assert.Equal(t, map[int32]int64{0: 100, 1: 200}, admin.DeleteRecordsCalls[0].PartitionOffsets)
 	})
@@ -362,10 +331,9 @@ func TestPurgeTopicMessages(t *testing.T) {
 			MockTopicMetadata: metaWithPartitions("t", 2),
 			MockConfigEntries: deleteCfg,
 		}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		require.NoError(t, brokerDS().PurgeTopicMessages("t", 1))
+		require.NoError(t, ds.PurgeTopicMessages("t", 1))
 		require.Len(t, admin.DeleteRecordsCalls, 1)
 		assert.Equal(t, map[int32]int64{1: 200}, admin.DeleteRecordsCalls[0].PartitionOffsets)
 	})
@@ -374,16 +342,80 @@ func TestPurgeTopicMessages(t *testing.T) {
 			MockTopicMetadata: metaWithPartitions("t", 2),
 			MockConfigEntries: []sarama.ConfigEntry{{Name: "cleanup.policy", Value: "compact"}},
 		}
-		restore := installMockAdmin(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().PurgeTopicMessages("t", -1)
+		err := ds.PurgeTopicMessages("t", -1)
 		var e api.CleanupPolicyError
 		assert.True(t, errors.As(err, &e))
 		assert.Empty(t, admin.DeleteRecordsCalls)
 	})
 }
 
+func TestDeleteRecordsBefore(t *testing.T) {
+	withOffsets(t, map[int32]offsets{0: {oldest: 10, newest: 100}, 1: {oldest: 0, newest: 200}})
+	deleteCfg := []sarama.ConfigEntry{{Name: "cleanup.policy", Value: "delete"}}
+	newAdmin := func() *MockClusterAdmin {
+		return &MockClusterAdmin{MockTopicMetadata: metaWithPartitions("t", 2), MockConfigEntries: deleteCfg}
+	}
+
+	t.Run("per-partition offsets", func(t *testing.T) {
+		admin := newAdmin()
+		got, err := mockAdminDS(admin).DeleteRecordsBefore(context.Background(), api.DeleteRecordsRequest{Topic: "t", Offsets: map[int32]int64{0: 5, 1: 50}})
+		require.NoError(t, err)
+		assert.Equal(t, []api.RecordDeletion{{Partition: 0, Offset: 10}, {Partition: 1, Offset: 50, Deleted: 50}}, got)
+		require.Len(t, admin.DeleteRecordsCalls, 1)
+		assert.Equal(t, map[int32]int64{1: 50}, admin.DeleteRecordsCalls[0].PartitionOffsets, "offsets below the log start are not sent")
+	})
+	t.Run("time resolved per partition", func(t *testing.T) {
+		orig := fetchOffsetsForTime
+		fetchOffsetsForTime = func(_ KafkaDataSourceKaf, topic string, partitions []int32, ts int64) (map[int32]int64, error) {
+			assert.Equal(t, int64(1_700_000_000_000), ts)
+			return map[int32]int64{0: 40, 1: -1}, nil
+		}
+		t.Cleanup(func() { fetchOffsetsForTime = orig })
+
+		admin := newAdmin()
+		before := time.UnixMilli(1_700_000_000_000)
+		got, err := mockAdminDS(admin).DeleteRecordsBefore(context.Background(), api.DeleteRecordsRequest{Topic: "t", Before: &before})
+		require.NoError(t, err)
+		assert.Equal(t, []api.RecordDeletion{{Partition: 0, Offset: 40, Deleted: 30}, {Partition: 1, Offset: 200, Deleted: 200}}, got)
+		assert.Equal(t, map[int32]int64{0: 40, 1: 200}, admin.DeleteRecordsCalls[0].PartitionOffsets, "no record after the time deletes to the HWM")
+	})
+	t.Run("offset past the end", func(t *testing.T) {
+		admin := newAdmin()
+		_, err := mockAdminDS(admin).DeleteRecordsBefore(context.Background(), api.DeleteRecordsRequest{Topic: "t", Offsets: map[int32]int64{0: 101}})
+		var e api.InvalidDeleteRecordsError
+		assert.True(t, errors.As(err, &e))
+		assert.Empty(t, admin.DeleteRecordsCalls)
+	})
+	t.Run("unknown partition", func(t *testing.T) {
+		_, err := mockAdminDS(newAdmin()).DeleteRecordsBefore(context.Background(), api.DeleteRecordsRequest{Topic: "t", Offsets: map[int32]int64{7: 1}})
+		var e api.PartitionError
+		assert.True(t, errors.As(err, &e))
+	})
+	t.Run("invalid requests", func(t *testing.T) {
+		now := time.Now()
+		for _, req := range []api.DeleteRecordsRequest{
+			{Topic: "t"},
+			{Topic: "t", Offsets: map[int32]int64{0: 1}, Before: &now},
+			{Topic: "t", Offsets: map[int32]int64{0: -2}},
+		} {
+			_, err := mockAdminDS(newAdmin()).DeleteRecordsBefore(context.Background(), req)
+			var e api.InvalidDeleteRecordsError
+			assert.True(t, errors.As(err, &e), "%+v", req)
+		}
+	})
+	t.Run("compact policy rejected", func(t *testing.T) {
+		admin := &MockClusterAdmin{
+			MockTopicMetadata: metaWithPartitions("t", 2),
+			MockConfigEntries: []sarama.ConfigEntry{{Name: "cleanup.policy", Value: "compact"}},
+		}
+		_, err := mockAdminDS(admin).DeleteRecordsBefore(context.Background(), api.DeleteRecordsRequest{Topic: "t", Offsets: map[int32]int64{0: 50}})
+		var e api.CleanupPolicyError
+		assert.True(t, errors.As(err, &e))
+	})
+}
+
 // --- TP-10: RecreateTopic ---
 
 // flakyCreateAdmin reports "already exists" for the first N CreateTopic calls.
@@ -412,19 +444,17 @@ func TestRecreateTopic(t *testing.T) {
 
 	t.Run("retries while deletion propagates then succeeds", func(t *testing.T) {
 		admin := &flakyCreateAdmin{MockClusterAdmin: base, failFirst: 2}
-		restore := installAdminIface(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		require.NoError(t, brokerDS().RecreateTopic("t"))
+		require.NoError(t, ds.RecreateTopic("t"))
 		assert.Equal(t, 3, admin.calls, "2 failures + 1 success")
 	})
 
 	t.Run("exhaustion returns timeout", func(t *testing.T) {
 		admin := &flakyCreateAdmin{MockClusterAdmin: base, failFirst: 99}
-		restore := installAdminIface(admin)
-		defer restore()
+		ds := mockAdminDS(admin)
 
-		err := brokerDS().RecreateTopic("t")
+		err := ds.RecreateTopic("t")
 		var e api.RecreateTimeoutError
 		assert.True(t, errors.As(err, &e))
 	})
@@ -442,12 +472,11 @@ func TestChangeReplicationFactor(t *testing.T) {
 		MockBrokers:      []*sarama.Broker{newTestBroker(1, "h:9092"), newTestBroker(2, "h:9093"), newTestBroker(3, "h:9094")},
 		MockControllerID: 1,
 	}
-	restore := installMockAdmin(admin)
-	defer restore()
+	ds := mockAdminDS(admin)
 
 	t.Run("increase applies reassignment", func(t *testing.T) {
 		admin.AlterReassignmentCalls = nil
-		require.NoError(t, brokerDS().ChangeReplicationFactor("t", 3))
+		require.NoError(t, ds.ChangeReplicationFactor("t", 3))
 		require.Len(t, admin.AlterReassignmentCalls, 1)
 		for _, rs := range admin.AlterReassignmentCalls[0].Assignment {
 			assert.Len(t, rs, 3)
@@ -455,7 +484,7 @@ func TestChangeReplicationFactor(t *testing.T) {
 	})
 	t.Run("equal factor rejected before broker call", func(t *testing.T) {
 		admin.AlterReassignmentCalls = nil
-		err := brokerDS().ChangeReplicationFactor("t", 2)
+		err := ds.ChangeReplicationFactor("t", 2)
 		var e api.InvalidReplicationFactorError
 		assert.True(t, errors.As(err, &e))
 		assert.Empty(t, admin.AlterReassignmentCalls)
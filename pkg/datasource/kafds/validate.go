@@ -163,12 +163,12 @@ func applyProbeSASL(sc *sarama.Config, s *appconfig.SASLConfig) error {
 	sc.Net.SASL.Enable = true
 	switch s.Mechanism {
 	case "SCRAM-SHA-512":
-		sc.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &XDGSCRAMClient{HashGeneratorFcn: SHA512} }
+		sc.Net.SASL.SCRAMClientGeneratorFunc = scramClientGenerator(s.TokenAuth, SHA512)
 		sc.Net.SASL.Mechanism = sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512)
 		sc.Net.SASL.User = s.Username
 		sc.Net.SASL.Password = s.Password
 	case "SCRAM-SHA-256":
-		sc.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient { return &XDGSCRAMClient{HashGeneratorFcn: SHA256} }
+		sc.Net.SASL.SCRAMClientGeneratorFunc = scramClientGenerator(s.TokenAuth, SHA256)
 		sc.Net.SASL.Mechanism = sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA256)
 		sc.Net.SASL.User = s.Username
 		sc.Net.SASL.Password = s.Password
@@ -304,7 +304,7 @@ func (kp KafkaDataSourceKaf) clusterExtensionFor(name string) (appconfig.Cluster
 	if ext.IsFullyDefined() {
 		return ext, nil
 	}
-	for _, c := range cfg.Clusters {
+	for _, c := range kp.sess().clusters() {
 		if c.Name != name {
 			continue
 		}
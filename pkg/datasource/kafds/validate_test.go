@@ -168,11 +168,11 @@ func TestReload_KafConfigUntouched(t *testing.T) {
 	kds := NewKafkaDataSourceKafWithDeps(&MockKafkaClientFactory{}, mockCM)
 
 	// Seed the in-memory kaf config with the existing cluster.
-	cfg = config.Config{
+	kds.session.cfg = config.Config{
 		CurrentCluster: "existing",
 		Clusters:       []*config.Cluster{{Name: "existing", Brokers: []string{"localhost:9092"}}},
 	}
-	currentCluster = &config.Cluster{Name: "existing", Brokers: []string{"localhost:9092"}}
+	kds.session.current = &config.Cluster{Name: "existing", Brokers: []string{"localhost:9092"}}
 
 	effective := appconfig.Config{Clusters: map[string]appconfig.ClusterExtension{
 		"newcluster": {Brokers: []string{"broker:9092"}},
@@ -181,7 +181,7 @@ func TestReload_KafConfigUntouched(t *testing.T) {
 
 	// The new, fully-kafui-defined cluster is merged in memory.
 	names := map[string]bool{}
-	for _, c := range cfg.Clusters {
+	for _, c := range kds.session.clusters() {
 		names[c.Name] = true
 	}
 	assert.True(t, names["existing"])
@@ -0,0 +1,195 @@
+package kafds
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errShortResponse = errors.New("kafka response truncated")
+
+// wireEncoder appends Kafka's primitive types, including the compact forms
+// used by flexible request versions.
+type wireEncoder struct{ buf []byte }
+
+func (e *wireEncoder) putInt8(v int8)    { e.buf = append(e.buf, byte(v)) }
+func (e *wireEncoder) putInt16(v int16)  { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *wireEncoder) putInt32(v int32)  { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *wireEncoder) putInt64(v int64)  { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+func (e *wireEncoder) putArrayLen(n int) { e.putInt32(int32(n)) }
+
+func (e *wireEncoder) putString(s string) {
+	e.putInt16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *wireEncoder) putBytes(b []byte) {
+	e.putInt32(int32(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *wireEncoder) putUvarint(v uint64) { e.buf = binary.AppendUvarint(e.buf, v) }
+
+// putCompactArrayLen writes a flexible-version array length (N+1).
+func (e *wireEncoder) putCompactArrayLen(n int) { e.putUvarint(uint64(n) + 1) }
+
+// putCompactString writes a flexible-version string (length+1, then bytes).
+func (e *wireEncoder) putCompactString(s string) {
+	e.putUvarint(uint64(len(s)) + 1)
+	e.buf = append(e.buf, s...)
+}
+
+// putCompactInt32s writes a flexible-version int32 array; nil is written as
+// null.
+func (e *wireEncoder) putCompactInt32s(vs []int32) {
+	if vs == nil {
+		e.putUvarint(0)
+		return
+	}
+	e.putCompactArrayLen(len(vs))
+	for _, v := range vs {
+		e.putInt32(v)
+	}
+}
+
+// putEmptyTags writes an empty tagged-field section.
+func (e *wireEncoder) putEmptyTags() { e.putUvarint(0) }
+
+// wireDecoder reads Kafka's primitive types. The first short read
+// sticks in err and turns every later read into a zero value.
+type wireDecoder struct {
+	buf []byte
+	off int
+	err error
+}
+
+func (d *wireDecoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.buf)-d.off < n {
+		d.err = errShortResponse
+		return nil
+	}
+	b := d.buf[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *wireDecoder) int16() int16 {
+	b := d.take(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *wireDecoder) int32() int32 {
+	b := d.take(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+// string reads a (nullable) string; null decodes as "".
+func (d *wireDecoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.take(int(n)))
+}
+
+// bytes reads (nullable) bytes; null decodes as nil.
+func (d *wireDecoder) bytes() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.take(int(n))
+}
+
+// arrayLen reads an array length; null decodes as empty. A length that cannot
+// fit in the remaining bytes is treated as truncation.
+func (d *wireDecoder) arrayLen() int {
+	n := int(d.int32())
+	if n < 0 {
+		return 0
+	}
+	if d.err == nil && n > len(d.buf)-d.off {
+		d.err = errShortResponse
+		return 0
+	}
+	return n
+}
+
+func (d *wireDecoder) int64() int64 {
+	b := d.take(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *wireDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf[d.off:])
+	if n <= 0 {
+		d.err = errShortResponse
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+// compactString reads a flexible-version (nullable) string; null decodes as "".
+func (d *wireDecoder) compactString() string {
+	n := d.uvarint()
+	if n == 0 {
+		return ""
+	}
+	if n-1 > uint64(len(d.buf)-d.off) {
+		d.err = errShortResponse
+		return ""
+	}
+	return string(d.take(int(n - 1)))
+}
+
+// compactArrayLen reads a flexible-version array length; null decodes as
+// empty. Like arrayLen, a length that cannot fit is treated as truncation.
+func (d *wireDecoder) compactArrayLen() int {
+	n := d.uvarint()
+	if n == 0 {
+		return 0
+	}
+	if d.err == nil && n-1 > uint64(len(d.buf)-d.off) {
+		d.err = errShortResponse
+		return 0
+	}
+	return int(n - 1)
+}
+
+// compactInt32s reads a flexible-version int32 array; null decodes as empty.
+func (d *wireDecoder) compactInt32s() []int32 {
+	n := d.compactArrayLen()
+	out := make([]int32, 0, n)
+	for i := 0; i < n; i++ {
+		out = append(out, d.int32())
+	}
+	return out
+}
+
+// skipTags skips a tagged-field section; kafui reads no tagged fields.
+func (d *wireDecoder) skipTags() {
+	for i, n := uint64(0), d.uvarint(); i < n && d.err == nil; i++ {
+		d.uvarint() // tag
+		size := d.uvarint()
+		if size > uint64(len(d.buf)-d.off) {
+			d.err = errShortResponse
+			return
+		}
+		d.take(int(size))
+	}
+}
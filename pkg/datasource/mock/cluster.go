@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 )
@@ -71,7 +72,51 @@ func (kp *KafkaDataSourceMock) GetClusterStatistics(_ context.Context, clusterNa
 	if p.offline {
 		return api.ClusterStatistics{}, api.NewConnectionError("mock cluster is offline")
 	}
-	return p.stats, nil
+	stats := p.stats
+	if stats.CoordinationType == "kraft" {
+		q := mockQuorum()
+		stats.Quorum = &q
+	}
+	return stats, nil
+}
+
+// DescribeQuorum implements api.KafkaDataSource for the active mock cluster:
+// kafka-dev is a KRaft cluster with three combined-mode voters, kafka-test runs
+// on ZooKeeper.
+func (kp *KafkaDataSourceMock) DescribeQuorum(_ context.Context) (api.QuorumInfo, error) {
+	p, err := kp.profile(currentContext)
+	if err != nil {
+		return api.QuorumInfo{}, err
+	}
+	if p.offline {
+		return api.QuorumInfo{}, api.NewConnectionError("mock cluster is offline")
+	}
+	if p.stats.CoordinationType != "kraft" {
+		return api.QuorumInfo{}, api.NotSupportedError{Operation: "DescribeQuorum"}
+	}
+	return mockQuorum(), nil
+}
+
+// mockQuorum is the kafka-dev controller quorum: broker 1 leads, broker 3 is a
+// little behind and one dedicated observer trails further.
+func mockQuorum() api.QuorumInfo {
+	now := time.Now()
+	replica := func(id int32, leo, lag int64, ago time.Duration) api.QuorumReplica {
+		return api.QuorumReplica{ReplicaID: id, LogEndOffset: leo, Lag: lag, LastFetch: now.Add(-ago), LastCaughtUp: now.Add(-ago)}
+	}
+	return api.QuorumInfo{
+		LeaderID:      1,
+		LeaderEpoch:   12,
+		HighWatermark: 48_210,
+		Voters: []api.QuorumReplica{
+			replica(1, 48_215, 0, 0),
+			replica(2, 48_215, 0, 120*time.Millisecond),
+			replica(3, 48_203, 12, 480*time.Millisecond),
+		},
+		Observers: []api.QuorumReplica{
+			replica(4, 48_150, 65, 2*time.Second),
+		},
+	}
 }
 
 // GetClusterCapabilities implements api.KafkaDataSource.
@@ -53,3 +53,30 @@ func TestMockClusterNotFound(t *testing.T) {
 	var nf api.ClusterNotFoundError
 	assert.True(t, errors.As(err, &nf))
 }
+
+func TestMockDescribeQuorum(t *testing.T) {
+	ds := newMock()
+	ctx := context.Background()
+	orig := currentContext
+	t.Cleanup(func() { currentContext = orig })
+
+	currentContext = "kafka-dev"
+	q, err := ds.DescribeQuorum(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), q.LeaderID)
+	assert.Len(t, q.Voters, 3)
+	assert.Equal(t, "observer", q.Role(4))
+
+	stats, err := ds.GetClusterStatistics(ctx, "kafka-dev")
+	require.NoError(t, err)
+	require.NotNil(t, stats.Quorum)
+	assert.Equal(t, q.HighWatermark, stats.Quorum.HighWatermark)
+
+	currentContext = "kafka-test"
+	_, err = ds.DescribeQuorum(ctx)
+	var ns api.NotSupportedError
+	assert.True(t, errors.As(err, &ns))
+	stats, err = ds.GetClusterStatistics(ctx, "kafka-test")
+	require.NoError(t, err)
+	assert.Nil(t, stats.Quorum)
+}
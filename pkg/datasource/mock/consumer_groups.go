@@ -2,7 +2,9 @@ package mock
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 )
@@ -303,19 +305,8 @@ func (kp *KafkaDataSourceMock) DeleteConsumerGroupOffsets(groupID string, topic
 
 // ResetConsumerGroupOffsets implements api.KafkaDataSource (CG-7, CG-8).
 func (kp *KafkaDataSourceMock) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
-	// Shared validation (mirrors kafds).
-	switch req.Mode {
-	case api.OffsetResetEarliest, api.OffsetResetLatest:
-	case api.OffsetResetTimestamp:
-		if req.Timestamp == nil {
-			return api.InvalidOffsetResetError{Reason: "timestamp mode requires a timestamp"}
-		}
-	case api.OffsetResetExplicit:
-		if len(req.PartitionOffsets) == 0 {
-			return api.InvalidOffsetResetError{Reason: "explicit mode requires per-partition offsets"}
-		}
-	default:
-		return api.InvalidOffsetResetError{Reason: "unrecognized reset mode"}
+	if err := validateMockOffsetReset(req); err != nil {
+		return err
 	}
 
 	kp.ensureGroupState()
@@ -330,36 +321,219 @@ func (kp *KafkaDataSourceMock) ResetConsumerGroupOffsets(ctx context.Context, re
 		return api.GroupNotEmptyError{GroupID: req.GroupID, State: g.state}
 	}
 
-	// Resolve target partitions (empty => all end-offset partitions of the topic).
+	targets := req.Planned
+	resolved, end, err := kp.resetInputs(g, req)
+	if err != nil {
+		return err
+	}
+	if targets != nil {
+		if err := checkMockPlannedOffsets(req, end); err != nil {
+			return err
+		}
+	} else {
+		targets = map[int32]int64{}
+		for _, p := range planMockOffsetReset(g, resolved, end) {
+			targets[p.Partition] = p.Target
+		}
+	}
+	if g.committed[req.Topic] == nil {
+		g.committed[req.Topic] = map[int32]int64{}
+	}
+	for p, off := range targets {
+		g.committed[req.Topic][p] = off
+	}
+	return nil
+}
+
+// PlanConsumerGroupOffsetReset implements api.KafkaDataSource. It resolves the
+// same targets as ResetConsumerGroupOffsets without committing.
+func (kp *KafkaDataSourceMock) PlanConsumerGroupOffsetReset(ctx context.Context, req api.OffsetResetRequest) ([]api.PartitionResetPlan, error) {
+	if err := validateMockOffsetReset(req); err != nil {
+		return nil, err
+	}
+
+	kp.ensureGroupState()
+	kp.groupMu.Lock()
+	defer kp.groupMu.Unlock()
+
+	g, ok := kp.groups[req.GroupID]
+	if !ok {
+		return nil, api.GroupNotFoundError{GroupID: req.GroupID}
+	}
+	req, end, err := kp.resetInputs(g, req)
+	if err != nil {
+		return nil, err
+	}
+	return planMockOffsetReset(g, req, end), nil
+}
+
+// resetInputs returns the topic's end offsets as known to g and, for
+// copy-from-group, the request with PartitionOffsets set to the source group's
+// committed offsets. The mock tracks end offsets per group, so a target group
+// that never consumed the topic borrows the source's. Callers hold groupMu.
+func (kp *KafkaDataSourceMock) resetInputs(g *mockGroup, req api.OffsetResetRequest) (api.OffsetResetRequest, map[int32]int64, error) {
+	end := g.end[req.Topic]
+	if req.Mode != api.OffsetResetFromGroup {
+		return req, end, nil
+	}
+	src, ok := kp.groups[req.SourceGroupID]
+	if !ok {
+		return req, nil, api.GroupNotFoundError{GroupID: req.SourceGroupID}
+	}
+	if len(src.committed[req.Topic]) == 0 {
+		return req, nil, api.InvalidOffsetResetError{Reason: fmt.Sprintf("group %q has no committed offsets for topic %q", req.SourceGroupID, req.Topic)}
+	}
+	if len(end) == 0 {
+		end = src.end[req.Topic]
+	}
+	req.PartitionOffsets = src.committed[req.Topic]
+	return req, end, nil
+}
+
+// checkMockPlannedOffsets mirrors kafds: planned targets must name a partition
+// of the reset and lie within [0, end].
+func checkMockPlannedOffsets(req api.OffsetResetRequest, end map[int32]int64) error {
 	partitions := req.Partitions
 	if len(partitions) == 0 {
-		for p := range g.end[req.Topic] {
+		for p := range end {
 			partitions = append(partitions, p)
 		}
 	}
+	known := make(map[int32]bool, len(partitions))
+	for _, p := range partitions {
+		known[p] = true
+	}
+	planned := make([]int32, 0, len(req.Planned))
+	for p := range req.Planned {
+		planned = append(planned, p)
+	}
+	sort.Slice(planned, func(i, j int) bool { return planned[i] < planned[j] })
+	for _, p := range planned {
+		off := req.Planned[p]
+		switch {
+		case !known[p]:
+			return api.InvalidOffsetResetError{Reason: fmt.Sprintf("planned partition %d is not part of the reset of %q", p, req.Topic)}
+		case off < 0:
+			return api.InvalidOffsetResetError{Reason: fmt.Sprintf("planned offset %d for partition %d is negative", off, p)}
+		case off > end[p]:
+			return api.InvalidOffsetResetError{Reason: fmt.Sprintf("planned offset %d for partition %d is outside its range [0, %d]; plan the reset again", off, p, end[p])}
+		}
+	}
+	return nil
+}
 
-	if g.committed[req.Topic] == nil {
-		g.committed[req.Topic] = map[int32]int64{}
+// validateMockOffsetReset is the shared request validation (mirrors kafds).
+func validateMockOffsetReset(req api.OffsetResetRequest) error {
+	switch req.Mode {
+	case api.OffsetResetEarliest, api.OffsetResetLatest:
+	case api.OffsetResetTimestamp:
+		if req.Timestamp == nil {
+			return api.InvalidOffsetResetError{Reason: "timestamp mode requires a timestamp"}
+		}
+	case api.OffsetResetExplicit:
+		if len(req.PartitionOffsets) == 0 {
+			return api.InvalidOffsetResetError{Reason: "explicit mode requires per-partition offsets"}
+		}
+	case api.OffsetResetShiftBy:
+		if req.ShiftBy == 0 {
+			return api.InvalidOffsetResetError{Reason: "shift-by mode requires a non-zero shift"}
+		}
+	case api.OffsetResetDuration:
+		if req.Duration <= 0 {
+			return api.InvalidOffsetResetError{Reason: "by-duration mode requires a positive duration"}
+		}
+	case api.OffsetResetCurrent:
+	case api.OffsetResetFromGroup:
+		if req.SourceGroupID == "" {
+			return api.InvalidOffsetResetError{Reason: "copy-from-group mode requires a source group"}
+		}
+		if req.SourceGroupID == req.GroupID {
+			return api.InvalidOffsetResetError{Reason: "source group must differ from the group being reset"}
+		}
+	default:
+		return api.InvalidOffsetResetError{Reason: "unrecognized reset mode"}
+	}
+	return nil
+}
+
+// planMockOffsetReset resolves targets against the group's fixture offsets
+// (log start 0, end = high-water mark). The mock has no record timestamps, so
+// timestamp and by-duration resets resolve to the end. copy-from-group requests
+// carry the source offsets (see resetInputs). Callers hold groupMu.
+func planMockOffsetReset(g *mockGroup, req api.OffsetResetRequest, end map[int32]int64) []api.PartitionResetPlan {
+	// Resolve target partitions (empty => all end-offset partitions of the topic).
+	partitions := req.Partitions
+	if len(partitions) == 0 {
+		for p := range end {
+			partitions = append(partitions, p)
+		}
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
 	}
+
+	plan := make([]api.PartitionResetPlan, 0, len(partitions))
 	for _, p := range partitions {
-		oldest := int64(0)
-		newest := g.end[req.Topic][p]
-		var target int64
+		if _, ok := req.PartitionOffsets[p]; req.Mode == api.OffsetResetFromGroup && !ok {
+			continue
+		}
+		entry := api.PartitionResetPlan{Topic: req.Topic, Partition: p, LogStart: 0, LogEnd: end[p]}
+		if c, ok := g.committed[req.Topic][p]; ok {
+			entry.Current = i64(c)
+		}
+		clamp := func(v int64) int64 {
+			if v < entry.LogStart {
+				return entry.LogStart
+			}
+			if v > entry.LogEnd {
+				return entry.LogEnd
+			}
+			return v
+		}
 		switch req.Mode {
 		case api.OffsetResetEarliest:
-			target = oldest
-		case api.OffsetResetLatest, api.OffsetResetTimestamp:
-			target = newest
-		case api.OffsetResetExplicit:
-			target = req.PartitionOffsets[p]
-			if target < oldest {
-				target = oldest
+			entry.Target = entry.LogStart
+		case api.OffsetResetLatest, api.OffsetResetTimestamp, api.OffsetResetDuration:
+			entry.Target = entry.LogEnd
+		case api.OffsetResetExplicit, api.OffsetResetFromGroup:
+			entry.Target = clamp(req.PartitionOffsets[p])
+		case api.OffsetResetCurrent:
+			entry.Target = entry.LogEnd
+			if entry.Current != nil {
+				entry.Target = clamp(*entry.Current)
 			}
-			if target > newest {
-				target = newest
+		case api.OffsetResetShiftBy:
+			base := entry.LogEnd
+			if entry.Current != nil {
+				base = *entry.Current
 			}
+			entry.Target = clamp(base + req.ShiftBy)
 		}
-		g.committed[req.Topic][p] = target
+		plan = append(plan, entry)
 	}
-	return nil
+	return plan
+}
+
+// mockRecordEpoch stamps the mock's fixture records: the record at offset o is
+// written o minutes after it, so timestamps grow with the offset.
+var mockRecordEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// GetRecordTimestamps implements api.KafkaDataSource. A partition's log end is
+// the highest end offset any fixture group knows for it.
+func (kp *KafkaDataSourceMock) GetRecordTimestamps(ctx context.Context, topic string, offsets map[int32]int64) (map[int32]time.Time, error) {
+	kp.ensureGroupState()
+	kp.groupMu.Lock()
+	defer kp.groupMu.Unlock()
+
+	out := make(map[int32]time.Time, len(offsets))
+	for p, off := range offsets {
+		var end int64
+		for _, g := range kp.groups {
+			if e := g.end[topic][p]; e > end {
+				end = e
+			}
+		}
+		if off >= 0 && off < end {
+			out[p] = mockRecordEpoch.Add(time.Duration(off) * time.Minute)
+		}
+	}
+	return out, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/stretchr/testify/assert"
@@ -146,3 +147,91 @@ func TestMockResetConsumerGroupOffsets(t *testing.T) {
 		assert.True(t, errors.As(err, &ivr))
 	})
 }
+
+func TestMockResetRejectsInvalidPlannedTargets(t *testing.T) {
+	tests := []struct {
+		name       string
+		partitions []int32
+		planned    map[int32]int64
+	}{
+		{"negative offset", []int32{0}, map[int32]int64{0: -1}},
+		{"past the high watermark", []int32{0}, map[int32]int64{0: 61}},
+		{"partition outside the request", []int32{0}, map[int32]int64{0: 5, 1: 5}},
+		{"partition outside the topic", nil, map[int32]int64{9: 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newGroupMock()
+			err := m.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+				GroupID: "inventory-sync", Topic: "inventory-events", Mode: api.OffsetResetEarliest,
+				Partitions: tt.partitions, Planned: tt.planned,
+			})
+			var ivr api.InvalidOffsetResetError
+			assert.True(t, errors.As(err, &ivr), "err = %v", err)
+			d, _ := m.GetConsumerGroupDetail("inventory-sync")
+			for _, po := range d.TopicOffsets {
+				assert.NotEqual(t, int64(5), *po.CommittedOffset, "partition %d was committed", po.Partition)
+			}
+		})
+	}
+}
+
+func TestMockPlanConsumerGroupOffsetReset(t *testing.T) {
+	m := newGroupMock()
+	// Planning works on active groups and never commits.
+	plan, err := m.PlanConsumerGroupOffsetReset(context.Background(), api.OffsetResetRequest{
+		GroupID: "order-processor", Topic: "order-events", Mode: api.OffsetResetShiftBy, ShiftBy: -120,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, plan, 3) {
+		assert.Equal(t, int32(0), plan[0].Partition)
+		assert.Equal(t, int64(100), *plan[0].Current)
+		assert.Equal(t, int64(0), plan[0].Target) // 100-120 clamped to log start
+		assert.Equal(t, int64(80), plan[1].Target)
+		assert.Equal(t, int64(170), plan[1].TargetLag())
+	}
+	d, _ := m.GetConsumerGroupDetail("order-processor")
+	for _, po := range d.TopicOffsets {
+		if po.Partition == 0 {
+			assert.Equal(t, int64(100), *po.CommittedOffset)
+		}
+	}
+}
+
+func TestMockResetToCurrentAndCopyFromGroup(t *testing.T) {
+	m := newGroupMock()
+	plan, err := m.PlanConsumerGroupOffsetReset(context.Background(), api.OffsetResetRequest{
+		GroupID: "analytics-consumer", Topic: "clickstream-events", Mode: api.OffsetResetCurrent,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, plan, 2) {
+		assert.Equal(t, int64(1000), plan[0].Target)
+		assert.Equal(t, int64(2000), plan[1].Target, "uncommitted partition resolves to the log end")
+	}
+
+	err = m.ResetConsumerGroupOffsets(context.Background(), api.OffsetResetRequest{
+		GroupID: "load-test-processor", Topic: "inventory-events", Mode: api.OffsetResetFromGroup, SourceGroupID: "inventory-sync",
+	})
+	assert.NoError(t, err)
+	d, _ := m.GetConsumerGroupDetail("load-test-processor")
+	got := map[int32]int64{}
+	for _, po := range d.TopicOffsets {
+		if po.CommittedOffset != nil {
+			got[po.Partition] = *po.CommittedOffset
+		}
+	}
+	assert.Equal(t, map[int32]int64{0: 10, 1: 20}, got)
+
+	_, err = m.PlanConsumerGroupOffsetReset(context.Background(), api.OffsetResetRequest{
+		GroupID: "inventory-sync", Topic: "inventory-events", Mode: api.OffsetResetFromGroup, SourceGroupID: "payment-service",
+	})
+	var ivr api.InvalidOffsetResetError
+	assert.True(t, errors.As(err, &ivr), "source without commits on the topic: %v", err)
+}
+
+func TestMockGetRecordTimestamps(t *testing.T) {
+	m := newGroupMock()
+	got, err := m.GetRecordTimestamps(context.Background(), "order-events", map[int32]int64{0: 100, 2: 150})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]time.Time{0: mockRecordEpoch.Add(100 * time.Minute)}, got, "p2 is at its log end")
+}
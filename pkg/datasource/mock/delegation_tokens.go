@@ -0,0 +1,153 @@
+package mock
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+)
+
+// Mock broker defaults, matching Kafka's delegation.token.expiry.time.ms and
+// delegation.token.max.lifetime.ms.
+const (
+	mockTokenRenewInterval = 24 * time.Hour
+	mockTokenMaxLifetime   = 7 * 24 * time.Hour
+	mockTokenOwner         = "User:kafui"
+)
+
+var errMockTokenNotFound = errors.New("delegation token not found")
+
+// seedTokens installs the initial sample tokens on first access.
+func (kp *KafkaDataSourceMock) seedTokens() {
+	if kp.tokens != nil {
+		return
+	}
+	kp.tokens = map[string]*api.DelegationToken{}
+	issued := mockStart.Add(-2 * time.Hour)
+	for i, renewers := range [][]string{{"User:batch-scheduler"}, nil} {
+		t := newMockToken(issued, mockTokenMaxLifetime, renewers)
+		t.ExpiryTime = issued.Add(time.Duration(i+1) * 6 * time.Hour)
+		kp.tokens[t.TokenID] = t
+	}
+}
+
+func newMockToken(now time.Time, maxLifetime time.Duration, renewers []string) *api.DelegationToken {
+	id := make([]byte, 8)
+	hmac := make([]byte, 32)
+	_, _ = rand.Read(id)
+	_, _ = rand.Read(hmac)
+	return &api.DelegationToken{
+		TokenID:    hex.EncodeToString(id),
+		Owner:      mockTokenOwner,
+		Renewers:   renewers,
+		IssueTime:  now,
+		ExpiryTime: minTime(now.Add(mockTokenRenewInterval), now.Add(maxLifetime)),
+		MaxTime:    now.Add(maxLifetime),
+		HMAC:       hmac,
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// DescribeDelegationTokens implements api.KafkaDataSource.
+func (kp *KafkaDataSourceMock) DescribeDelegationTokens(owners []string) ([]api.DelegationToken, error) {
+	kp.tokenMu.Lock()
+	defer kp.tokenMu.Unlock()
+	kp.seedTokens()
+
+	var out []api.DelegationToken
+	for _, t := range kp.tokens {
+		if len(owners) > 0 && !slices.Contains(owners, t.Owner) {
+			continue
+		}
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].ExpiryTime.Equal(out[j].ExpiryTime) {
+			return out[i].ExpiryTime.Before(out[j].ExpiryTime)
+		}
+		return out[i].TokenID < out[j].TokenID
+	})
+	return out, nil
+}
+
+// CreateDelegationToken implements api.KafkaDataSource. Tokens are owned by
+// the mock's connected principal, User:kafui.
+func (kp *KafkaDataSourceMock) CreateDelegationToken(req api.CreateDelegationTokenRequest) (api.DelegationToken, error) {
+	if err := api.ValidateCreateDelegationToken(req); err != nil {
+		return api.DelegationToken{}, err
+	}
+	maxLifetime := mockTokenMaxLifetime
+	if req.MaxLifetime > 0 && req.MaxLifetime < maxLifetime {
+		maxLifetime = req.MaxLifetime
+	}
+	kp.tokenMu.Lock()
+	defer kp.tokenMu.Unlock()
+	kp.seedTokens()
+
+	t := newMockToken(time.Now(), maxLifetime, req.Renewers)
+	kp.tokens[t.TokenID] = t
+	return *t, nil
+}
+
+// RenewDelegationToken implements api.KafkaDataSource; the new expiry never
+// passes the token's max time.
+func (kp *KafkaDataSourceMock) RenewDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	if err := api.ValidateDelegationTokenTarget(token); err != nil {
+		return time.Time{}, err
+	}
+	if period <= 0 {
+		period = mockTokenRenewInterval
+	}
+	kp.tokenMu.Lock()
+	defer kp.tokenMu.Unlock()
+	t, err := kp.tokenByHMAC(token.HMAC)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t.ExpiryTime = minTime(time.Now().Add(period), t.MaxTime)
+	return t.ExpiryTime, nil
+}
+
+// ExpireDelegationToken implements api.KafkaDataSource. Expiring immediately
+// removes the token, as the broker does.
+func (kp *KafkaDataSourceMock) ExpireDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	if err := api.ValidateDelegationTokenTarget(token); err != nil {
+		return time.Time{}, err
+	}
+	kp.tokenMu.Lock()
+	defer kp.tokenMu.Unlock()
+	t, err := kp.tokenByHMAC(token.HMAC)
+	if err != nil {
+		return time.Time{}, err
+	}
+	now := time.Now()
+	if period <= 0 {
+		delete(kp.tokens, t.TokenID)
+		return now, nil
+	}
+	t.ExpiryTime = minTime(now.Add(period), t.MaxTime)
+	return t.ExpiryTime, nil
+}
+
+// tokenByHMAC finds a token by its secret, like the broker; callers hold
+// tokenMu.
+func (kp *KafkaDataSourceMock) tokenByHMAC(hmac []byte) (*api.DelegationToken, error) {
+	kp.seedTokens()
+	for _, t := range kp.tokens {
+		if bytes.Equal(t.HMAC, hmac) {
+			return t, nil
+		}
+	}
+	return nil, errMockTokenNotFound
+}
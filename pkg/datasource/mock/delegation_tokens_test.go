@@ -0,0 +1,55 @@
+package mock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockDelegationTokens_Lifecycle(t *testing.T) {
+	m := &KafkaDataSourceMock{}
+
+	tok, err := m.CreateDelegationToken(api.CreateDelegationTokenRequest{Renewers: []string{"User:bob"}, MaxLifetime: 2 * time.Hour})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tok.TokenID)
+	assert.Len(t, tok.HMAC, 32)
+	assert.Equal(t, tok.MaxTime, tok.ExpiryTime, "expiry is capped by the requested max lifetime")
+
+	all, err := m.DescribeDelegationTokens(nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 3, "two seeded tokens plus the new one")
+
+	// Renewal never passes the max time.
+	expiry, err := m.RenewDelegationToken(tok, 48*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, tok.MaxTime, expiry)
+
+	expiry, err = m.ExpireDelegationToken(tok, time.Minute)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), expiry, 5*time.Second)
+
+	_, err = m.ExpireDelegationToken(tok, 0)
+	require.NoError(t, err)
+	all, _ = m.DescribeDelegationTokens(nil)
+	assert.Len(t, all, 2, "expiring now removes the token")
+
+	_, err = m.RenewDelegationToken(tok, time.Hour)
+	assert.ErrorIs(t, err, errMockTokenNotFound)
+}
+
+func TestMockDelegationTokens_Validation(t *testing.T) {
+	m := &KafkaDataSourceMock{}
+	_, err := m.CreateDelegationToken(api.CreateDelegationTokenRequest{Renewers: []string{"bob"}})
+	var ie api.InvalidDelegationTokenError
+	assert.ErrorAs(t, err, &ie)
+
+	_, err = m.RenewDelegationToken(api.DelegationToken{TokenID: "x"}, time.Hour)
+	assert.ErrorAs(t, err, &ie)
+
+	owned, err := m.DescribeDelegationTokens([]string{"User:nobody"})
+	require.NoError(t, err)
+	assert.Empty(t, owned)
+}
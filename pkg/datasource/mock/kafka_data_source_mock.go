@@ -76,9 +76,27 @@ type KafkaDataSourceMock struct {
 	quotaMu    sync.Mutex
 	quotas     []api.ClientQuotaEntry
 	quotasInit bool
+	// In-memory SCRAM users: user -> mechanism -> iterations (lazily
+	// initialised; see scram_users.go).
+	scramMu    sync.Mutex
+	scramUsers map[string]map[api.ScramMechanism]int32
+	// In-memory delegation tokens by token ID (lazily initialised; see
+	// delegation_tokens.go).
+	tokenMu sync.Mutex
+	tokens  map[string]*api.DelegationToken
 	// In-memory Kafka Connect state (lazily initialised; see connect.go).
 	connectMu    sync.Mutex
 	connectState *mockConnectState
+	// In-flight partition reassignments and the replication throttle (see
+	// reassignment.go). Guarded by topicMu, like the topics they rewrite.
+	reassignments map[api.TopicPartition]*mockReassignment
+	throttleRate  int64
+	// Partitions whose preferred leader was restored by ElectLeaders (see
+	// leader_election.go). Guarded by topicMu.
+	elected map[api.TopicPartition]bool
+	// Log-start offsets raised by DeleteRecordsBefore (see topic_admin.go).
+	// Guarded by topicMu.
+	logStart map[api.TopicPartition]int64
 }
 
 func (kp *KafkaDataSourceMock) Init(cfgOption string) {
@@ -448,6 +466,60 @@ func (kp *KafkaDataSourceMock) ProduceMessage(ctx context.Context, topic string,
 	return nil
 }
 
+// ProduceBatch produces recs one by one through ProduceMessage. A transactional
+// batch is all-or-nothing: every record is validated before any is appended.
+func (kp *KafkaDataSourceMock) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, nil
+	}
+	results := make([]api.ProduceResult, len(recs))
+
+	if opts.Transactional() {
+		if ctxData, ok := mockContexts[currentContext]; ok {
+			if t, exists := ctxData.topics[topic]; exists {
+				for _, rec := range recs {
+					if rec.Partition != nil && (*rec.Partition < 0 || *rec.Partition >= t.NumPartitions) {
+						err := api.NewPartitionError(
+							fmt.Sprintf("partition %d out of range (topic has %d partitions)", *rec.Partition, t.NumPartitions),
+							topic, *rec.Partition)
+						for i := range results {
+							results[i] = api.ProduceResult{Partition: -1, Offset: -1, Err: err}
+						}
+						return results, api.ProduceError{Topic: topic, Reason: "transaction aborted", Cause: err}
+					}
+				}
+			}
+		}
+	}
+
+	failed := 0
+	var firstErr error
+	for i, rec := range recs {
+		if err := kp.ProduceMessage(ctx, topic, rec); err != nil {
+			results[i] = api.ProduceResult{Partition: -1, Offset: -1, Err: err}
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		kp.producedMu.Lock()
+		last := kp.produced[topic][len(kp.produced[topic])-1]
+		kp.producedMu.Unlock()
+		results[i] = api.ProduceResult{Partition: last.Partition, Offset: last.Offset}
+		if opts.Acks == api.AcksNone {
+			results[i].Offset = -1
+		}
+	}
+	if failed > 0 {
+		return results, api.ProduceError{Topic: topic, Reason: fmt.Sprintf("%d of %d records failed", failed, len(recs)), Cause: firstErr}
+	}
+	return results, nil
+}
+
 // browseProduced returns the produced messages for a topic filtered by the
 // seek/partition/limit flags (MSG-2/3/4).
 func (kp *KafkaDataSourceMock) browseProduced(topic string, flags api.ConsumeFlags) []api.Message {
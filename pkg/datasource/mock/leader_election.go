@@ -0,0 +1,126 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Benny93/kafui/pkg/api"
+)
+
+// mockLeader is partition i's current leader. Every fourth partition (2, 6,
+// …) of a replicated topic starts out led by its second replica so preferred
+// elections have something to do; ElectLeaders restores the preferred one.
+// Callers hold kp.topicMu.
+func (kp *KafkaDataSourceMock) mockLeader(topic string, i int32, replicas []int32) int32 {
+	if len(replicas) > 1 && i%4 == 2 && !kp.elected[api.TopicPartition{Topic: topic, Partition: i}] {
+		return replicas[1]
+	}
+	return replicas[0]
+}
+
+// DescribeLeadership implements api.KafkaDataSource.
+func (kp *KafkaDataSourceMock) DescribeLeadership(_ context.Context, topics []string) ([]api.PartitionLeadership, error) {
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+	return kp.leadership(topics)
+}
+
+// leadership lists the partitions of topics (every topic when empty), sorted.
+// Callers hold kp.topicMu.
+func (kp *KafkaDataSourceMock) leadership(topics []string) ([]api.PartitionLeadership, error) {
+	all := currentTopics()
+	if len(topics) == 0 {
+		for name := range all {
+			topics = append(topics, name)
+		}
+	}
+	sort.Strings(topics)
+	var out []api.PartitionLeadership
+	for _, name := range topics {
+		topic, ok := all[name]
+		if !ok {
+			return nil, api.TopicNotFoundError{TopicName: name}
+		}
+		for i := int32(0); i < max(topic.NumPartitions, 1); i++ {
+			replicas := mockReplicas(topic, i)
+			isr := append([]int32{}, replicas...)
+			if i == 1 && len(isr) > 1 {
+				isr = isr[:len(isr)-1] // matches GetTopicDetails' under-replicated partition
+			}
+			out = append(out, api.PartitionLeadership{
+				Topic: name, Partition: i, Leader: kp.mockLeader(name, i, replicas), Replicas: replicas, ISR: isr,
+			})
+		}
+	}
+	return out, nil
+}
+
+// ElectLeaders implements api.KafkaDataSource. Preferred elections succeed
+// whenever the preferred replica is in the ISR; the fixtures have no
+// leaderless partitions, so unclean elections are never needed.
+func (kp *KafkaDataSourceMock) ElectLeaders(_ context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	if req.Type != api.ElectPreferred && req.Type != api.ElectUnclean {
+		return nil, api.ElectionError{Reason: fmt.Sprintf("unknown election type %q", req.Type)}
+	}
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+
+	var topics []string
+	for _, tp := range req.Partitions {
+		topics = append(topics, tp.Topic)
+	}
+	parts, err := kp.leadership(uniqueStrings(topics))
+	if err != nil {
+		return nil, err
+	}
+	byTP := make(map[api.TopicPartition]api.PartitionLeadership, len(parts))
+	for _, p := range parts {
+		byTP[api.TopicPartition{Topic: p.Topic, Partition: p.Partition}] = p
+	}
+	targets := req.Partitions
+	if len(targets) == 0 {
+		for _, p := range parts {
+			targets = append(targets, api.TopicPartition{Topic: p.Topic, Partition: p.Partition})
+		}
+	}
+
+	out := make([]api.ElectionResult, 0, len(targets))
+	for _, tp := range targets {
+		r := api.ElectionResult{Topic: tp.Topic, Partition: tp.Partition}
+		p, ok := byTP[tp]
+		switch {
+		case !ok:
+			r.Err = api.NewPartitionError("unknown partition", tp.Topic, tp.Partition)
+		case req.Type == api.ElectPreferred && p.NonPreferred():
+			if !p.PreferredInSync() {
+				r.Err = api.NewPartitionError("preferred leader not in sync", tp.Topic, tp.Partition)
+				break
+			}
+			if kp.elected == nil {
+				kp.elected = map[api.TopicPartition]bool{}
+			}
+			kp.elected[tp] = true
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Topic != out[j].Topic {
+			return out[i].Topic < out[j].Topic
+		}
+		return out[i].Partition < out[j].Partition
+	})
+	return out, nil
+}
+
+func uniqueStrings(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockPreferredElection(t *testing.T) {
+	ds := newTopicMock(t)
+	ctx := context.Background()
+	require.NoError(t, ds.CreateTopic("to-elect", 4, 2, nil))
+	t.Cleanup(func() { _ = ds.DeleteTopic("to-elect") })
+
+	parts, err := ds.DescribeLeadership(ctx, []string{"to-elect"})
+	require.NoError(t, err)
+	require.Len(t, parts, 4)
+	assert.True(t, parts[2].NonPreferred())
+	assert.False(t, parts[0].NonPreferred())
+
+	got, err := ds.ElectLeaders(ctx, api.ElectLeadersRequest{
+		Type:       api.ElectPreferred,
+		Partitions: []api.TopicPartition{{Topic: "to-elect", Partition: 2}, {Topic: "to-elect", Partition: 9}},
+	})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.NoError(t, got[0].Err)
+	assert.Error(t, got[1].Err, "unknown partition")
+
+	parts, err = ds.DescribeLeadership(ctx, []string{"to-elect"})
+	require.NoError(t, err)
+	assert.False(t, parts[2].NonPreferred())
+
+	details, err := ds.GetTopicDetails("to-elect")
+	require.NoError(t, err)
+	assert.Equal(t, details.Partitions[2].Replicas[0], details.Partitions[2].Leader)
+}
+
+func TestMockElectLeadersErrors(t *testing.T) {
+	ds := newTopicMock(t)
+	_, err := ds.ElectLeaders(context.Background(), api.ElectLeadersRequest{Type: "random"})
+	var ee api.ElectionError
+	assert.ErrorAs(t, err, &ee)
+
+	_, err = ds.DescribeLeadership(context.Background(), []string{"no-such-topic"})
+	var nf api.TopicNotFoundError
+	assert.ErrorAs(t, err, &nf)
+}
@@ -79,6 +79,38 @@ func TestMockProduceValidation(t *testing.T) {
 	})
 }
 
+func TestMockProduceBatch(t *testing.T) {
+	m := &KafkaDataSourceMock{}
+	m.Init("")
+	topic := firstTopic(t, m)
+
+	res, err := m.ProduceBatch(context.Background(), topic, []api.ProduceRecord{
+		{Value: []byte("a"), Partition: ptrI32(0)},
+		{Value: []byte("b"), Partition: ptrI32(0)},
+	}, api.ProducerOptions{})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, res[0].Offset+1, res[1].Offset)
+
+	t.Run("transactional batch is all or nothing", func(t *testing.T) {
+		before := len(m.browseProduced(topic, api.ConsumeFlags{}))
+		res, err := m.ProduceBatch(context.Background(), topic, []api.ProduceRecord{
+			{Value: []byte("ok"), Partition: ptrI32(0)},
+			{Value: []byte("bad"), Partition: ptrI32(9999)},
+		}, api.ProducerOptions{TransactionalID: "tx"})
+		var pe api.ProduceError
+		require.ErrorAs(t, err, &pe)
+		assert.Error(t, res[0].Err)
+		assert.Equal(t, before, len(m.browseProduced(topic, api.ConsumeFlags{})))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := m.ProduceBatch(context.Background(), topic, []api.ProduceRecord{{}}, api.ProducerOptions{Acks: "most"})
+		var ce api.InvalidConfigError
+		assert.ErrorAs(t, err, &ce)
+	})
+}
+
 // MSG-2/3/4: browseMessages seek/partition/limit filtering.
 func TestBrowseMessages(t *testing.T) {
 	t0 := time.Unix(100, 0)
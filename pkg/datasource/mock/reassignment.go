@@ -0,0 +1,231 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+)
+
+// mockReassignment is one in-flight move. Every ListReassignments poll
+// advances progress by mockReassignStep; at 1 the target is committed to the
+// topic's ReplicaAssignment and the entry disappears, like a finished
+// reassignment on a real controller.
+type mockReassignment struct {
+	current  []int32
+	target   []int32
+	progress float64
+}
+
+const mockReassignStep = 0.25
+
+// PlanReassignment implements api.KafkaDataSource over the fixture layout of
+// the current context's topics.
+func (kp *KafkaDataSourceMock) PlanReassignment(_ context.Context, req api.ReassignmentRequest) (api.ReassignmentPlan, error) {
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+
+	topics := currentTopics()
+	names := req.Topics
+	if len(names) == 0 {
+		for name := range topics {
+			if req.Goal == api.ReassignDecommission || !strings.HasPrefix(name, "__") {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	type part struct {
+		topic     string
+		partition int32
+		replicas  []int32
+	}
+	var parts []part
+	load := map[int32]int{}
+	for _, name := range names {
+		topic, ok := topics[name]
+		if !ok {
+			return api.ReassignmentPlan{}, api.TopicNotFoundError{TopicName: name}
+		}
+		for i := int32(0); i < max(topic.NumPartitions, 1); i++ {
+			rs := mockReplicas(topic, i)
+			parts = append(parts, part{name, i, rs})
+			for _, b := range rs {
+				load[b]++
+			}
+		}
+	}
+
+	var targets [][]int32
+	switch req.Goal {
+	case api.ReassignDecommission:
+		if len(req.Brokers) == 0 {
+			return api.ReassignmentPlan{}, api.ReassignmentError{Reason: "no brokers to decommission"}
+		}
+		for _, b := range req.Brokers {
+			if !slices.Contains(mockBrokerIDs, b) {
+				return api.ReassignmentPlan{}, api.BrokerNotFoundError{BrokerID: b}
+			}
+		}
+		var remaining []int32
+		for _, b := range mockBrokerIDs {
+			if !slices.Contains(req.Brokers, b) {
+				remaining = append(remaining, b)
+			}
+		}
+		for _, p := range parts {
+			target := append([]int32{}, p.replicas...)
+			for i, b := range target {
+				if !slices.Contains(req.Brokers, b) {
+					continue
+				}
+				next := int32(-1)
+				for _, c := range remaining {
+					if !slices.Contains(target, c) && (next < 0 || load[c] < load[next]) {
+						next = c
+					}
+				}
+				if next < 0 {
+					return api.ReassignmentPlan{}, api.ReassignmentError{
+						Reason: fmt.Sprintf("%s-%d needs %d replicas but only %d brokers would remain", p.topic, p.partition, len(target), len(remaining)),
+					}
+				}
+				target[i] = next
+				load[b]--
+				load[next]++
+			}
+			targets = append(targets, target)
+		}
+	case api.ReassignRebalance:
+		// Only preferred leaders are rebalanced here: the fixture round robin
+		// already spreads replicas evenly.
+		leaders := map[int32]int{}
+		for _, p := range parts {
+			target := append([]int32{}, p.replicas...)
+			best := 0
+			for i, b := range target {
+				if leaders[b] < leaders[target[best]] {
+					best = i
+				}
+			}
+			target[0], target[best] = target[best], target[0]
+			leaders[target[0]]++
+			targets = append(targets, target)
+		}
+	default:
+		return api.ReassignmentPlan{}, api.ReassignmentError{Reason: fmt.Sprintf("unknown goal %q", req.Goal)}
+	}
+
+	plan := api.ReassignmentPlan{Goal: req.Goal}
+	for i, p := range parts {
+		if !slices.Equal(p.replicas, targets[i]) {
+			plan.Moves = append(plan.Moves, api.PartitionMove{
+				Topic: p.topic, Partition: p.partition, Current: p.replicas, Target: targets[i],
+			})
+		}
+	}
+	return plan, nil
+}
+
+// ExecuteReassignment implements api.KafkaDataSource. The moves start
+// in-flight; see ListReassignments.
+func (kp *KafkaDataSourceMock) ExecuteReassignment(_ context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	if len(plan.Moves) == 0 {
+		return api.ReassignmentError{Reason: "the plan has no moves"}
+	}
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+
+	topics := currentTopics()
+	for _, m := range plan.Moves {
+		if _, ok := topics[m.Topic]; !ok {
+			return api.TopicNotFoundError{TopicName: m.Topic}
+		}
+	}
+	if kp.reassignments == nil {
+		kp.reassignments = map[api.TopicPartition]*mockReassignment{}
+	}
+	for _, m := range plan.Moves {
+		kp.reassignments[api.TopicPartition{Topic: m.Topic, Partition: m.Partition}] = &mockReassignment{
+			current: append([]int32{}, m.Current...),
+			target:  append([]int32{}, m.Target...),
+		}
+	}
+	if opts.ThrottleBytesPerSec > 0 {
+		kp.throttleRate = opts.ThrottleBytesPerSec
+	}
+	return nil
+}
+
+// ListReassignments implements api.KafkaDataSource. Each call advances every
+// in-flight move; completed ones are applied and dropped from the result.
+func (kp *KafkaDataSourceMock) ListReassignments(_ context.Context) ([]api.PartitionReassignment, error) {
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+
+	topics := currentTopics()
+	var out []api.PartitionReassignment
+	for tp, r := range kp.reassignments {
+		r.progress = min(r.progress+mockReassignStep, 1)
+		if r.progress < 1 {
+			m := api.PartitionMove{Current: r.current, Target: r.target}
+			out = append(out, api.PartitionReassignment{
+				Topic:     tp.Topic,
+				Partition: tp.Partition,
+				Replicas:  append(append([]int32{}, r.target...), m.Removing()...),
+				Adding:    m.Adding(),
+				Removing:  m.Removing(),
+				Progress:  r.progress,
+			})
+			continue
+		}
+		delete(kp.reassignments, tp)
+		topic, ok := topics[tp.Topic]
+		if !ok {
+			continue
+		}
+		assignment := make(map[int32][]int32, len(topic.ReplicaAssignment)+1)
+		for p, rs := range topic.ReplicaAssignment {
+			assignment[p] = rs
+		}
+		assignment[tp.Partition] = r.target
+		topic.ReplicaAssignment = assignment
+		topics[tp.Topic] = topic
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Topic != out[j].Topic {
+			return out[i].Topic < out[j].Topic
+		}
+		return out[i].Partition < out[j].Partition
+	})
+	return out, nil
+}
+
+// CancelReassignments implements api.KafkaDataSource. Cancelled partitions
+// keep their original replicas.
+func (kp *KafkaDataSourceMock) CancelReassignments(_ context.Context, partitions []api.TopicPartition) error {
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+
+	for _, tp := range partitions {
+		if _, ok := kp.reassignments[tp]; !ok {
+			return api.ReassignmentError{Reason: fmt.Sprintf("no reassignment in progress for %s-%d", tp.Topic, tp.Partition)}
+		}
+	}
+	for _, tp := range partitions {
+		delete(kp.reassignments, tp)
+	}
+	return nil
+}
+
+// ClearReplicationThrottles implements api.KafkaDataSource.
+func (kp *KafkaDataSourceMock) ClearReplicationThrottles(_ context.Context, _ []string) error {
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+	kp.throttleRate = 0
+	return nil
+}
@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockReassignmentLifecycle(t *testing.T) {
+	ds := newTopicMock(t)
+	ctx := context.Background()
+	require.NoError(t, ds.CreateTopic("to-move", 3, 2, nil))
+	t.Cleanup(func() { _ = ds.DeleteTopic("to-move") })
+
+	plan, err := ds.PlanReassignment(ctx, api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{3}, Topics: []string{"to-move"}})
+	require.NoError(t, err)
+	require.NotEmpty(t, plan.Moves)
+	for _, m := range plan.Moves {
+		assert.NotContains(t, m.Target, int32(3))
+		assert.Equal(t, []int32{3}, m.Removing())
+	}
+
+	require.NoError(t, ds.ExecuteReassignment(ctx, plan, api.ReassignmentOptions{ThrottleBytesPerSec: 1024}))
+	inFlight, err := ds.ListReassignments(ctx)
+	require.NoError(t, err)
+	require.Len(t, inFlight, len(plan.Moves))
+	assert.InDelta(t, 0.25, inFlight[0].Progress, 1e-9)
+	assert.NotEmpty(t, inFlight[0].Adding)
+
+	for len(inFlight) > 0 {
+		inFlight, err = ds.ListReassignments(ctx)
+		require.NoError(t, err)
+	}
+	details, err := ds.GetTopicDetails("to-move")
+	require.NoError(t, err)
+	for _, p := range details.Partitions {
+		assert.NotContains(t, p.Replicas, int32(3), "partition %d", p.ID)
+	}
+
+	// Nothing left to drain.
+	plan, err = ds.PlanReassignment(ctx, api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{3}, Topics: []string{"to-move"}})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Moves)
+	require.NoError(t, ds.ClearReplicationThrottles(ctx, []string{"to-move"}))
+}
+
+func TestMockCancelReassignment(t *testing.T) {
+	ds := newTopicMock(t)
+	ctx := context.Background()
+	require.NoError(t, ds.CreateTopic("to-cancel", 1, 2, nil))
+	t.Cleanup(func() { _ = ds.DeleteTopic("to-cancel") })
+
+	before, err := ds.GetTopicDetails("to-cancel")
+	require.NoError(t, err)
+	plan, err := ds.PlanReassignment(ctx, api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: before.Partitions[0].Replicas[:1], Topics: []string{"to-cancel"}})
+	require.NoError(t, err)
+	require.Len(t, plan.Moves, 1)
+	require.NoError(t, ds.ExecuteReassignment(ctx, plan, api.ReassignmentOptions{}))
+
+	tp := api.TopicPartition{Topic: "to-cancel", Partition: 0}
+	require.NoError(t, ds.CancelReassignments(ctx, []api.TopicPartition{tp}))
+	inFlight, err := ds.ListReassignments(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, inFlight)
+	after, err := ds.GetTopicDetails("to-cancel")
+	require.NoError(t, err)
+	assert.Equal(t, before.Partitions[0].Replicas, after.Partitions[0].Replicas)
+
+	var re api.ReassignmentError
+	assert.ErrorAs(t, ds.CancelReassignments(ctx, []api.TopicPartition{tp}), &re, "no longer in flight")
+}
+
+func TestMockPlanReassignmentErrors(t *testing.T) {
+	ds := newTopicMock(t)
+	ctx := context.Background()
+
+	_, err := ds.PlanReassignment(ctx, api.ReassignmentRequest{Goal: api.ReassignDecommission, Brokers: []int32{42}})
+	var nf api.BrokerNotFoundError
+	assert.ErrorAs(t, err, &nf)
+
+	var re api.ReassignmentError
+	_, err = ds.PlanReassignment(ctx, api.ReassignmentRequest{Goal: api.ReassignDecommission})
+	assert.ErrorAs(t, err, &re)
+	assert.ErrorAs(t, ds.ExecuteReassignment(ctx, api.ReassignmentPlan{}, api.ReassignmentOptions{}), &re)
+
+	_, err = ds.PlanReassignment(ctx, api.ReassignmentRequest{Goal: api.ReassignRebalance, Topics: []string{"missing"}})
+	var tnf api.TopicNotFoundError
+	assert.ErrorAs(t, err, &tnf)
+}
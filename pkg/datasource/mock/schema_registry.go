@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 
@@ -15,12 +16,19 @@ type mockRegistry struct {
 	subjects map[string]*mockSubject
 	nextID   int
 	global   api.CompatibilityLevel
+	mode     api.SchemaMode
 }
 
+// mockSubject holds a subject's versions. A subject whose versions are all
+// soft-deleted stays in the map (so it can be restored or hard-deleted) but is
+// invisible to everything except the deleted listings.
 type mockSubject struct {
 	versions []api.SchemaVersion // ascending by Version; Schema text populated
+	deleted  []api.SchemaVersion // soft-deleted versions, ascending
 	// compatibility is the subject-specific level, or "" to fall back to global.
 	compatibility api.CompatibilityLevel
+	// mode is the subject-specific mode, or "" to fall back to global.
+	mode api.SchemaMode
 }
 
 // registry lazily seeds and returns the in-memory registry. Callers must hold no
@@ -40,6 +48,7 @@ func seedMockRegistry() *mockRegistry {
 		subjects: map[string]*mockSubject{},
 		nextID:   200,
 		global:   api.CompatibilityBackward,
+		mode:     api.SchemaModeReadWrite,
 	}
 
 	// orders-value: three plausibly evolved AVRO versions (v3 references a named
@@ -76,13 +85,109 @@ func seedMockRegistry() *mockRegistry {
 			{Version: 1, ID: 130, SchemaType: "JSON", Schema: `{"type":"object","properties":{"warehouseId":{"type":"string"},"productId":{"type":"string"}}}`},
 		},
 	}
+	// customers-value imports the shared Address record from common-address.
+	r.subjects["common-address"] = &mockSubject{
+		versions: []api.SchemaVersion{
+			{Version: 1, ID: 140, SchemaType: "AVRO", Schema: `{"type":"record","name":"Address","namespace":"com.example.common","fields":[{"name":"street","type":"string"},{"name":"city","type":"string"}]}`},
+		},
+	}
+	r.subjects["customers-value"] = &mockSubject{
+		versions: []api.SchemaVersion{
+			{Version: 1, ID: 141, SchemaType: "AVRO", Schema: `{"type":"record","name":"Customer","namespace":"com.example.customers","fields":[{"name":"customerId","type":"string"},{"name":"address","type":"com.example.common.Address"}]}`,
+				References: []api.SchemaReference{{Name: "com.example.common.Address", Subject: "common-address", Version: 1}}},
+		},
+	}
+	// legacy-orders-value was soft-deleted and can be restored.
+	r.subjects["legacy-orders-value"] = &mockSubject{
+		deleted: []api.SchemaVersion{
+			{Version: 1, ID: 90, SchemaType: "AVRO", Schema: `{"type":"record","name":"LegacyOrder","namespace":"com.example.orders","fields":[{"name":"id","type":"string"}]}`},
+			{Version: 2, ID: 91, SchemaType: "AVRO", Schema: `{"type":"record","name":"LegacyOrder","namespace":"com.example.orders","fields":[{"name":"id","type":"string"},{"name":"total","type":"double","default":0}]}`},
+		},
+	}
 	return r
 }
 
+// active returns a subject that has at least one live version.
+func (r *mockRegistry) active(name string) (*mockSubject, bool) {
+	s, ok := r.subjects[name]
+	if !ok || len(s.versions) == 0 {
+		return nil, false
+	}
+	return s, true
+}
+
+// effectiveMode returns the subject's mode, falling back to global; s may be
+// nil for a subject that does not exist yet.
+func (r *mockRegistry) effectiveMode(s *mockSubject) (api.SchemaMode, bool) {
+	if s != nil && s.mode != "" {
+		return s.mode, true
+	}
+	return r.mode, false
+}
+
+// checkWritable rejects mutations while the subject (or registry) is READONLY.
+func (r *mockRegistry) checkWritable(subject string, s *mockSubject) error {
+	if mode, _ := r.effectiveMode(s); mode == api.SchemaModeReadOnly {
+		return api.SchemaModeError{Subject: subject, Message: "subject is in read-only mode"}
+	}
+	return nil
+}
+
+// nextVersion is one past the highest live or soft-deleted version number;
+// the registry never reuses a number.
+func (s *mockSubject) nextVersion() int {
+	next := 1
+	for _, vs := range [][]api.SchemaVersion{s.versions, s.deleted} {
+		for _, v := range vs {
+			if v.Version >= next {
+				next = v.Version + 1
+			}
+		}
+	}
+	return next
+}
+
+// softDelete moves live versions into the deleted list.
+func (s *mockSubject) softDelete(versions ...api.SchemaVersion) {
+	s.deleted = append(s.deleted, versions...)
+	sort.Slice(s.deleted, func(i, j int) bool { return s.deleted[i].Version < s.deleted[j].Version })
+}
+
 func (s *mockSubject) latest() api.SchemaVersion {
 	return s.versions[len(s.versions)-1]
 }
 
+// version returns the given version (latest when version <= 0).
+func (s *mockSubject) version(version int) (api.SchemaVersion, bool) {
+	if version <= 0 {
+		return s.latest(), true
+	}
+	for _, v := range s.versions {
+		if v.Version == version {
+			return v, true
+		}
+	}
+	return api.SchemaVersion{}, false
+}
+
+// checkReferences validates refs and resolves each against the registry, as
+// the registry does before registering or checking a schema.
+func (r *mockRegistry) checkReferences(refs []api.SchemaReference) error {
+	if err := api.ValidateSchemaReferences(refs); err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		s, ok := r.active(ref.Subject)
+		if !ok {
+			return api.SchemaValidationError{Message: "reference subject not found: " + ref.Subject}
+		}
+		if _, ok := s.version(ref.Version); !ok {
+			return api.SchemaValidationError{Message: fmt.Sprintf("reference version not found: %s/%d", ref.Subject, ref.Version)}
+		}
+	}
+	return nil
+}
+
 func (r *mockRegistry) effectiveCompat(s *mockSubject) (api.CompatibilityLevel, bool) {
 	if s.compatibility != "" {
 		return s.compatibility, true
@@ -96,8 +201,10 @@ func (kp *KafkaDataSourceMock) GetSchemas() ([]api.Schema, error) {
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
 	names := make([]string, 0, len(r.subjects))
-	for name := range r.subjects {
-		names = append(names, name)
+	for name, s := range r.subjects {
+		if len(s.versions) > 0 {
+			names = append(names, name)
+		}
 	}
 	sort.Strings(names)
 	out := make([]api.Schema, len(names))
@@ -137,19 +244,64 @@ func (kp *KafkaDataSourceMock) GetSchemaContent(subject string, version int) (st
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
-	s, ok := r.subjects[subject]
+	s, ok := r.active(subject)
 	if !ok {
 		return "", api.SubjectNotFoundError{Subject: subject}
 	}
-	if version <= 0 {
-		return s.latest().Schema, nil
+	v, ok := s.version(version)
+	if !ok {
+		return "", api.SchemaVersionNotFoundError{Subject: subject, Version: version}
 	}
-	for _, v := range s.versions {
-		if v.Version == version {
-			return v.Schema, nil
+	return v.Schema, nil
+}
+
+// GetSchemaVersion returns one version with its text and references.
+func (kp *KafkaDataSourceMock) GetSchemaVersion(subject string, version int) (api.SchemaVersion, error) {
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	s, ok := r.active(subject)
+	if !ok {
+		return api.SchemaVersion{}, api.SubjectNotFoundError{Subject: subject}
+	}
+	v, ok := s.version(version)
+	if !ok {
+		return api.SchemaVersion{}, api.SchemaVersionNotFoundError{Subject: subject, Version: version}
+	}
+	return v, nil
+}
+
+// GetSchemaReferencedBy scans every version for references to the target.
+func (kp *KafkaDataSourceMock) GetSchemaReferencedBy(subject string, version int) ([]api.SubjectVersion, error) {
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	s, ok := r.active(subject)
+	if !ok {
+		return nil, api.SubjectNotFoundError{Subject: subject}
+	}
+	target, ok := s.version(version)
+	if !ok {
+		return nil, api.SchemaVersionNotFoundError{Subject: subject, Version: version}
+	}
+	out := []api.SubjectVersion{}
+	for name, other := range r.subjects {
+		for _, v := range other.versions {
+			for _, ref := range v.References {
+				if ref.Subject == subject && ref.Version == target.Version {
+					out = append(out, api.SubjectVersion{Subject: name, Version: v.Version})
+					break
+				}
+			}
 		}
 	}
-	return "", api.SchemaVersionNotFoundError{Subject: subject, Version: version}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Subject != out[j].Subject {
+			return out[i].Subject < out[j].Subject
+		}
+		return out[i].Version < out[j].Version
+	})
+	return out, nil
 }
 
 // GetSchemaVersions lists all versions of a subject (ascending).
@@ -157,7 +309,7 @@ func (kp *KafkaDataSourceMock) GetSchemaVersions(subject string) ([]api.SchemaVe
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
-	s, ok := r.subjects[subject]
+	s, ok := r.active(subject)
 	if !ok {
 		return nil, api.SubjectNotFoundError{Subject: subject}
 	}
@@ -180,7 +332,7 @@ func (kp *KafkaDataSourceMock) GetSubjectCompatibility(subject string) (api.Comp
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
-	s, ok := r.subjects[subject]
+	s, ok := r.active(subject)
 	if !ok {
 		return "", false, api.SubjectNotFoundError{Subject: subject}
 	}
@@ -189,13 +341,16 @@ func (kp *KafkaDataSourceMock) GetSubjectCompatibility(subject string) (api.Comp
 }
 
 // RegisterSchema appends a new version (creating the subject when new).
-func (kp *KafkaDataSourceMock) RegisterSchema(subject, schemaText, schemaType string) (api.Schema, error) {
+func (kp *KafkaDataSourceMock) RegisterSchema(subject, schemaText, schemaType string, references []api.SchemaReference) (api.Schema, error) {
 	if strings.Contains(schemaText, "INVALID") {
 		return api.Schema{}, api.SchemaValidationError{Message: "schema contains INVALID marker"}
 	}
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
+	if err := r.checkReferences(references); err != nil {
+		return api.Schema{}, err
+	}
 
 	st := strings.ToUpper(strings.TrimSpace(schemaType))
 	if st == "" {
@@ -203,65 +358,155 @@ func (kp *KafkaDataSourceMock) RegisterSchema(subject, schemaText, schemaType st
 	}
 
 	s, ok := r.subjects[subject]
+	if err := r.checkWritable(subject, s); err != nil {
+		return api.Schema{}, err
+	}
 	if !ok {
 		s = &mockSubject{}
 		r.subjects[subject] = s
-	} else if strings.Contains(schemaText, "INCOMPATIBLE") {
+	} else if len(s.versions) > 0 && strings.Contains(schemaText, "INCOMPATIBLE") {
 		// Existing subject + magic marker → incompatible with prior versions.
 		return api.Schema{}, api.SchemaIncompatibleError{Subject: subject, Message: "reader schema incompatible with writer schema"}
 	}
 
-	nextVersion := 1
-	if len(s.versions) > 0 {
-		nextVersion = s.latest().Version + 1
-	}
 	r.nextID++
-	v := api.SchemaVersion{Version: nextVersion, ID: r.nextID, SchemaType: st, Schema: schemaText}
+	v := api.SchemaVersion{Version: s.nextVersion(), ID: r.nextID, SchemaType: st, Schema: schemaText, References: references}
 	s.versions = append(s.versions, v)
-	return api.Schema{Subject: subject, Version: v.Version, ID: v.ID, SchemaType: st}, nil
+	return api.Schema{Subject: subject, Version: v.Version, ID: v.ID, SchemaType: st, References: references}, nil
+}
+
+// ImportSchema inserts a version under its given number and ID. Like the
+// registry it requires IMPORT mode, refuses a number the subject already used
+// and refuses an ID that another schema text already holds.
+func (kp *KafkaDataSourceMock) ImportSchema(subject string, version api.SchemaVersion) (api.Schema, error) {
+	if version.Version < 1 || version.ID < 1 {
+		return api.Schema{}, api.SchemaValidationError{Message: "import needs a version and an id"}
+	}
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	s, ok := r.subjects[subject]
+	if mode, _ := r.effectiveMode(s); mode != api.SchemaModeImport {
+		return api.Schema{}, api.SchemaModeError{Subject: subject, Message: "subject is not in import mode"}
+	}
+	if err := r.checkReferences(version.References); err != nil {
+		return api.Schema{}, err
+	}
+	if ok && version.Version < s.nextVersion() {
+		for _, vs := range [][]api.SchemaVersion{s.versions, s.deleted} {
+			for _, v := range vs {
+				if v.Version == version.Version {
+					return api.Schema{}, api.SchemaValidationError{Message: fmt.Sprintf("version %d of %s already exists", version.Version, subject)}
+				}
+			}
+		}
+	}
+	for _, other := range r.subjects {
+		for _, v := range other.versions {
+			if v.ID == version.ID && v.Schema != version.Schema {
+				return api.Schema{}, api.SchemaValidationError{Message: fmt.Sprintf("schema id %d is already used by a different schema", version.ID)}
+			}
+		}
+	}
+
+	if !ok {
+		s = &mockSubject{}
+		r.subjects[subject] = s
+	}
+	v := version
+	if v.SchemaType = strings.ToUpper(strings.TrimSpace(v.SchemaType)); v.SchemaType == "" {
+		v.SchemaType = "AVRO"
+	}
+	s.versions = append(s.versions, v)
+	sort.Slice(s.versions, func(i, j int) bool { return s.versions[i].Version < s.versions[j].Version })
+	if v.ID > r.nextID {
+		r.nextID = v.ID
+	}
+	return api.Schema{Subject: subject, Version: v.Version, ID: v.ID, SchemaType: v.SchemaType, References: v.References}, nil
 }
 
 // CheckSchemaCompatibility returns incompatible when the candidate contains the
 // magic "INCOMPATIBLE" marker, compatible otherwise.
-func (kp *KafkaDataSourceMock) CheckSchemaCompatibility(subject, schemaText, schemaType string) (bool, []string, error) {
+func (kp *KafkaDataSourceMock) CheckSchemaCompatibility(subject, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
-	if _, ok := r.subjects[subject]; !ok {
+	if _, ok := r.active(subject); !ok {
 		return false, nil, api.SubjectNotFoundError{Subject: subject}
 	}
+	if err := r.checkReferences(references); err != nil {
+		return false, nil, err
+	}
 	if strings.Contains(schemaText, "INCOMPATIBLE") {
 		return false, []string{"reader field 'x' is missing a default value", "incompatible with version 1"}, nil
 	}
 	return true, nil, nil
 }
 
-// DeleteSubject removes all versions of a subject, returning the deleted numbers.
+// CheckSchemaCompatibilityWithVersion applies the same "INCOMPATIBLE" marker
+// rule against one live version of the subject.
+func (kp *KafkaDataSourceMock) CheckSchemaCompatibilityWithVersion(subject string, version int, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	s, ok := r.active(subject)
+	if !ok {
+		return false, nil, api.SubjectNotFoundError{Subject: subject}
+	}
+	if _, ok := s.version(version); !ok {
+		return false, nil, api.SchemaVersionNotFoundError{Subject: subject, Version: version}
+	}
+	if err := r.checkReferences(references); err != nil {
+		return false, nil, err
+	}
+	if strings.Contains(schemaText, "INCOMPATIBLE") {
+		return false, []string{"reader field 'x' is missing a default value", fmt.Sprintf("incompatible with version %d", version)}, nil
+	}
+	return true, nil, nil
+}
+
+// DeleteSubject soft-deletes all live versions of a subject, returning their
+// numbers. permanent=true purges the subject, soft-deleted versions included.
 func (kp *KafkaDataSourceMock) DeleteSubject(subject string, permanent bool) ([]int, error) {
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
 	s, ok := r.subjects[subject]
-	if !ok {
+	if !ok || (!permanent && len(s.versions) == 0) {
 		return nil, api.SubjectNotFoundError{Subject: subject}
 	}
-	deleted := make([]int, len(s.versions))
-	for i, v := range s.versions {
-		deleted[i] = v.Version
+	if err := r.checkWritable(subject, s); err != nil {
+		return nil, err
 	}
-	delete(r.subjects, subject)
+	deleted := make([]int, 0, len(s.versions)+len(s.deleted))
+	for _, v := range s.versions {
+		deleted = append(deleted, v.Version)
+	}
+	if permanent {
+		for _, v := range s.deleted {
+			deleted = append(deleted, v.Version)
+		}
+		delete(r.subjects, subject)
+		return deleted, nil
+	}
+	s.softDelete(s.versions...)
+	s.versions = nil
 	return deleted, nil
 }
 
-// DeleteSchemaVersion removes a single version (version=-1 targets the latest).
+// DeleteSchemaVersion soft-deletes a single version (version=-1 targets the
+// latest). permanent=true purges it, whether live or soft-deleted.
 func (kp *KafkaDataSourceMock) DeleteSchemaVersion(subject string, version int, permanent bool) error {
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
 	s, ok := r.subjects[subject]
-	if !ok {
+	if !ok || (version < 0 && len(s.versions) == 0) {
 		return api.SubjectNotFoundError{Subject: subject}
 	}
+	if err := r.checkWritable(subject, s); err != nil {
+		return err
+	}
 	target := version
 	if version < 0 {
 		target = s.latest().Version
@@ -269,12 +514,20 @@ func (kp *KafkaDataSourceMock) DeleteSchemaVersion(subject string, version int,
 	for i, v := range s.versions {
 		if v.Version == target {
 			s.versions = append(s.versions[:i], s.versions[i+1:]...)
-			if len(s.versions) == 0 {
-				delete(r.subjects, subject)
+			if !permanent {
+				s.softDelete(v)
 			}
 			return nil
 		}
 	}
+	if permanent {
+		for i, v := range s.deleted {
+			if v.Version == target {
+				s.deleted = append(s.deleted[:i], s.deleted[i+1:]...)
+				return nil
+			}
+		}
+	}
 	return api.SchemaVersionNotFoundError{Subject: subject, Version: target}
 }
 
@@ -298,10 +551,114 @@ func (kp *KafkaDataSourceMock) SetSubjectCompatibility(subject string, level api
 	r := kp.registry()
 	kp.schemaRegMu.Lock()
 	defer kp.schemaRegMu.Unlock()
-	s, ok := r.subjects[subject]
+	s, ok := r.active(subject)
 	if !ok {
 		return api.SubjectNotFoundError{Subject: subject}
 	}
 	s.compatibility = level
 	return nil
 }
+
+// GetDeletedSubjects lists subjects with soft-deleted but no live versions.
+func (kp *KafkaDataSourceMock) GetDeletedSubjects() ([]string, error) {
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	out := []string{}
+	for name, s := range r.subjects {
+		if len(s.versions) == 0 && len(s.deleted) > 0 {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// GetDeletedSchemaVersions lists a subject's soft-deleted versions.
+func (kp *KafkaDataSourceMock) GetDeletedSchemaVersions(subject string) ([]api.SchemaVersion, error) {
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	s, ok := r.subjects[subject]
+	if !ok {
+		return nil, api.SubjectNotFoundError{Subject: subject}
+	}
+	out := make([]api.SchemaVersion, len(s.deleted))
+	copy(out, s.deleted)
+	return out, nil
+}
+
+// RestoreSchemaVersion re-registers a soft-deleted version as the next
+// version. As in the registry, the soft-deleted original stays listed until it
+// is hard-deleted.
+func (kp *KafkaDataSourceMock) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	deleted, err := kp.GetDeletedSchemaVersions(subject)
+	if err != nil {
+		return api.Schema{}, err
+	}
+	for _, v := range deleted {
+		if v.Version == version {
+			return kp.RegisterSchema(subject, v.Schema, v.SchemaType, v.References)
+		}
+	}
+	return api.Schema{}, api.SchemaVersionNotFoundError{Subject: subject, Version: version}
+}
+
+// GetGlobalMode returns the mock registry mode.
+func (kp *KafkaDataSourceMock) GetGlobalMode() (api.SchemaMode, error) {
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	return r.mode, nil
+}
+
+// GetSubjectMode returns a subject's effective mode with a fallback flag.
+func (kp *KafkaDataSourceMock) GetSubjectMode(subject string) (api.SchemaMode, bool, error) {
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	mode, specific := r.effectiveMode(r.subjects[subject])
+	return mode, specific, nil
+}
+
+// SetGlobalMode validates and updates the registry mode. Like the registry it
+// refuses IMPORT while any subject has live versions.
+func (kp *KafkaDataSourceMock) SetGlobalMode(mode api.SchemaMode) error {
+	if !mode.Valid() {
+		return api.SchemaValidationError{Message: "invalid mode: " + string(mode)}
+	}
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	if mode == api.SchemaModeImport {
+		for _, s := range r.subjects {
+			if len(s.versions) > 0 {
+				return api.SchemaModeError{Message: "cannot import since found existing subjects"}
+			}
+		}
+	}
+	r.mode = mode
+	return nil
+}
+
+// SetSubjectMode validates and updates a subject's mode. As in the registry a
+// mode may be set before the subject has versions (to prepare an import);
+// IMPORT is refused while the subject has live versions.
+func (kp *KafkaDataSourceMock) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	if !mode.Valid() {
+		return api.SchemaValidationError{Message: "invalid mode: " + string(mode)}
+	}
+	r := kp.registry()
+	kp.schemaRegMu.Lock()
+	defer kp.schemaRegMu.Unlock()
+	s, ok := r.subjects[subject]
+	if !ok {
+		s = &mockSubject{}
+		r.subjects[subject] = s
+	}
+	if mode == api.SchemaModeImport && len(s.versions) > 0 {
+		return api.SchemaModeError{Subject: subject, Message: "cannot import since found existing subjects"}
+	}
+	s.mode = mode
+	return nil
+}
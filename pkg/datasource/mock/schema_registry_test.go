@@ -87,7 +87,7 @@ func TestMockRegistry_RegisterRoundTrip(t *testing.T) {
 	kp := &KafkaDataSourceMock{}
 
 	before, _ := kp.GetSchemaVersions("orders-value")
-	schema, err := kp.RegisterSchema("orders-value", `{"type":"record","name":"OrderCreatedEvent","fields":[]}`, "AVRO")
+	schema, err := kp.RegisterSchema("orders-value", `{"type":"record","name":"OrderCreatedEvent","fields":[]}`, "AVRO", nil)
 	require.NoError(t, err)
 	assert.Equal(t, len(before)+1, schema.Version)
 	assert.NotZero(t, schema.ID)
@@ -96,7 +96,7 @@ func TestMockRegistry_RegisterRoundTrip(t *testing.T) {
 	assert.Len(t, after, len(before)+1)
 
 	t.Run("new subject", func(t *testing.T) {
-		s, err := kp.RegisterSchema("brand-new-value", `{"type":"string"}`, "")
+		s, err := kp.RegisterSchema("brand-new-value", `{"type":"string"}`, "", nil)
 		require.NoError(t, err)
 		assert.Equal(t, 1, s.Version)
 		names, _ := kp.GetSchemas()
@@ -110,21 +110,49 @@ func TestMockRegistry_RegisterRoundTrip(t *testing.T) {
 	})
 
 	t.Run("incompatible marker on existing subject", func(t *testing.T) {
-		_, err := kp.RegisterSchema("orders-value", `{"INCOMPATIBLE":true}`, "")
+		_, err := kp.RegisterSchema("orders-value", `{"INCOMPATIBLE":true}`, "", nil)
 		var e api.SchemaIncompatibleError
 		assert.True(t, errors.As(err, &e))
 	})
 }
 
+func TestMockRegistry_References(t *testing.T) {
+	kp := &KafkaDataSourceMock{}
+
+	sv, err := kp.GetSchemaVersion("customers-value", 0)
+	require.NoError(t, err)
+	require.Len(t, sv.References, 1)
+	assert.Equal(t, "common-address", sv.References[0].Subject)
+
+	by, err := kp.GetSchemaReferencedBy("common-address", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []api.SubjectVersion{{Subject: "customers-value", Version: 1}}, by)
+
+	refs := []api.SchemaReference{{Name: "com.example.common.Address", Subject: "common-address", Version: 1}}
+	schema, err := kp.RegisterSchema("shipments-value", `{"type":"string"}`, "", refs)
+	require.NoError(t, err)
+	assert.Equal(t, refs, schema.References)
+	by, _ = kp.GetSchemaReferencedBy("common-address", 0)
+	assert.Len(t, by, 2)
+
+	t.Run("unresolvable reference is rejected", func(t *testing.T) {
+		var e api.SchemaValidationError
+		_, err := kp.RegisterSchema("shipments-value", `{"type":"int"}`, "", []api.SchemaReference{{Name: "x", Subject: "common-address", Version: 9}})
+		assert.True(t, errors.As(err, &e))
+		_, _, err = kp.CheckSchemaCompatibility("customers-value", "{}", "", []api.SchemaReference{{Name: "x", Subject: "nope", Version: 1}})
+		assert.True(t, errors.As(err, &e))
+	})
+}
+
 func TestMockRegistry_CompatibilityCheck(t *testing.T) {
 	kp := &KafkaDataSourceMock{}
 
-	ok, msgs, err := kp.CheckSchemaCompatibility("orders-value", `{"type":"string"}`, "")
+	ok, msgs, err := kp.CheckSchemaCompatibility("orders-value", `{"type":"string"}`, "", nil)
 	require.NoError(t, err)
 	assert.True(t, ok)
 	assert.Empty(t, msgs)
 
-	ok, msgs, err = kp.CheckSchemaCompatibility("orders-value", `{"INCOMPATIBLE":true}`, "")
+	ok, msgs, err = kp.CheckSchemaCompatibility("orders-value", `{"INCOMPATIBLE":true}`, "", nil)
 	require.NoError(t, err)
 	assert.False(t, ok)
 	assert.NotEmpty(t, msgs)
@@ -134,10 +162,21 @@ func TestMockRegistry_CompatibilityCheck(t *testing.T) {
 	assert.Len(t, versions, 3)
 
 	t.Run("unknown subject", func(t *testing.T) {
-		_, _, err := kp.CheckSchemaCompatibility("nope", "{}", "")
+		_, _, err := kp.CheckSchemaCompatibility("nope", "{}", "", nil)
 		var e api.SubjectNotFoundError
 		assert.True(t, errors.As(err, &e))
 	})
+
+	t.Run("against one version", func(t *testing.T) {
+		ok, msgs, err := kp.CheckSchemaCompatibilityWithVersion("orders-value", 2, `{"INCOMPATIBLE":true}`, "", nil)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Contains(t, msgs, "incompatible with version 2")
+
+		_, _, err = kp.CheckSchemaCompatibilityWithVersion("orders-value", 9, "{}", "", nil)
+		var e api.SchemaVersionNotFoundError
+		assert.True(t, errors.As(err, &e))
+	})
 }
 
 func TestMockRegistry_Delete(t *testing.T) {
@@ -166,3 +205,104 @@ func TestMockRegistry_Delete(t *testing.T) {
 		assert.True(t, errors.As(err, &e))
 	})
 }
+
+func TestMockRegistry_DeletedAndRestore(t *testing.T) {
+	kp := &KafkaDataSourceMock{}
+
+	subjects, err := kp.GetDeletedSubjects()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy-orders-value"}, subjects)
+
+	schemas, _ := kp.GetSchemas()
+	for _, s := range schemas {
+		assert.NotEqual(t, "legacy-orders-value", s.Subject, "soft-deleted subjects are hidden")
+	}
+
+	deleted, err := kp.GetDeletedSchemaVersions("legacy-orders-value")
+	require.NoError(t, err)
+	require.Len(t, deleted, 2)
+
+	schema, err := kp.RestoreSchemaVersion("legacy-orders-value", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, schema.Version, "a restore registers the next version")
+	versions, err := kp.GetSchemaVersions("legacy-orders-value")
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, deleted[0].Schema, versions[0].Schema)
+	subjects, _ = kp.GetDeletedSubjects()
+	assert.Empty(t, subjects)
+
+	t.Run("soft-deleted version becomes restorable", func(t *testing.T) {
+		require.NoError(t, kp.DeleteSchemaVersion("payments-value", 1, false))
+		deleted, _ := kp.GetDeletedSchemaVersions("payments-value")
+		require.Len(t, deleted, 1)
+		_, err := kp.RestoreSchemaVersion("payments-value", 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("live version is not restorable", func(t *testing.T) {
+		_, err := kp.RestoreSchemaVersion("orders-value", 1)
+		var e api.SchemaVersionNotFoundError
+		assert.True(t, errors.As(err, &e))
+	})
+}
+
+func TestMockRegistry_Mode(t *testing.T) {
+	kp := &KafkaDataSourceMock{}
+
+	mode, err := kp.GetGlobalMode()
+	require.NoError(t, err)
+	assert.Equal(t, api.SchemaModeReadWrite, mode)
+
+	require.NoError(t, kp.SetSubjectMode("orders-value", api.SchemaModeReadOnly))
+	mode, specific, err := kp.GetSubjectMode("orders-value")
+	require.NoError(t, err)
+	assert.Equal(t, api.SchemaModeReadOnly, mode)
+	assert.True(t, specific)
+
+	_, err = kp.RegisterSchema("orders-value", `{"type":"string"}`, "", nil)
+	var me api.SchemaModeError
+	require.True(t, errors.As(err, &me), "READONLY refuses registration")
+
+	_, specific, _ = kp.GetSubjectMode("payments-value")
+	assert.False(t, specific)
+
+	t.Run("import refused while subjects exist", func(t *testing.T) {
+		assert.True(t, errors.As(kp.SetGlobalMode(api.SchemaModeImport), &me))
+		assert.True(t, errors.As(kp.SetSubjectMode("payments-value", api.SchemaModeImport), &me))
+		assert.NoError(t, kp.SetSubjectMode("new-subject-value", api.SchemaModeImport))
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		var ve api.SchemaValidationError
+		assert.True(t, errors.As(kp.SetGlobalMode("NOPE"), &ve))
+	})
+}
+
+func TestMockRegistry_Import(t *testing.T) {
+	kp := &KafkaDataSourceMock{}
+	v := api.SchemaVersion{Version: 5, ID: 500, Schema: `{"type":"string"}`}
+
+	_, err := kp.ImportSchema("imported-value", v)
+	var me api.SchemaModeError
+	require.True(t, errors.As(err, &me), "import needs IMPORT mode")
+
+	require.NoError(t, kp.SetSubjectMode("imported-value", api.SchemaModeImport))
+	schema, err := kp.ImportSchema("imported-value", v)
+	require.NoError(t, err)
+	assert.Equal(t, 5, schema.Version)
+	assert.Equal(t, 500, schema.ID)
+
+	var ve api.SchemaValidationError
+	_, err = kp.ImportSchema("imported-value", v)
+	assert.True(t, errors.As(err, &ve), "a version number is used once")
+	_, err = kp.ImportSchema("imported-value", api.SchemaVersion{Version: 6, ID: 101, Schema: `{"type":"int"}`})
+	assert.True(t, errors.As(err, &ve), "an id belongs to one schema text")
+
+	// Later registrations never reuse an imported ID.
+	require.NoError(t, kp.SetSubjectMode("imported-value", api.SchemaModeReadWrite))
+	next, err := kp.RegisterSchema("imported-value", `{"type":"long"}`, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 6, next.Version)
+	assert.Greater(t, next.ID, 500)
+}
@@ -0,0 +1,100 @@
+package mock
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Benny93/kafui/pkg/api"
+)
+
+// seedScramUsers installs the initial sample SCRAM users on first access.
+func (kp *KafkaDataSourceMock) seedScramUsers() {
+	if kp.scramUsers != nil {
+		return
+	}
+	kp.scramUsers = map[string]map[api.ScramMechanism]int32{
+		"alice":           {api.ScramSHA256: 4096, api.ScramSHA512: 8192},
+		"bob":             {api.ScramSHA512: 4096},
+		"service-account": {api.ScramSHA256: 4096},
+	}
+}
+
+// DescribeUserScramCredentials implements api.KafkaDataSource. Unknown users
+// are omitted, like the broker's RESOURCE_NOT_FOUND per-user result.
+func (kp *KafkaDataSourceMock) DescribeUserScramCredentials(users []string) ([]api.ScramUser, error) {
+	kp.scramMu.Lock()
+	defer kp.scramMu.Unlock()
+	kp.seedScramUsers()
+
+	names := users
+	if len(names) == 0 {
+		for name := range kp.scramUsers {
+			names = append(names, name)
+		}
+	}
+	var out []api.ScramUser
+	for _, name := range names {
+		creds, ok := kp.scramUsers[name]
+		if !ok {
+			continue
+		}
+		user := api.ScramUser{Name: name}
+		for mech, iterations := range creds {
+			user.Credentials = append(user.Credentials, api.ScramCredentialInfo{Mechanism: mech, Iterations: iterations})
+		}
+		sort.Slice(user.Credentials, func(i, j int) bool {
+			return user.Credentials[i].Mechanism < user.Credentials[j].Mechanism
+		})
+		out = append(out, user)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// UpsertUserScramCredentials implements api.KafkaDataSource. The password is
+// validated and discarded; only the mechanism and iterations are kept.
+func (kp *KafkaDataSourceMock) UpsertUserScramCredentials(upserts []api.ScramCredentialUpsert) error {
+	for _, u := range upserts {
+		if err := api.ValidateScramUpsert(u); err != nil {
+			return err
+		}
+	}
+	kp.scramMu.Lock()
+	defer kp.scramMu.Unlock()
+	kp.seedScramUsers()
+
+	for _, u := range upserts {
+		if kp.scramUsers[u.User] == nil {
+			kp.scramUsers[u.User] = map[api.ScramMechanism]int32{}
+		}
+		kp.scramUsers[u.User][u.Mechanism] = u.Iterations
+	}
+	return nil
+}
+
+// DeleteUserScramCredentials implements api.KafkaDataSource. Deleting a
+// credential that does not exist fails without applying any deletion; a user
+// whose last credential is removed disappears.
+func (kp *KafkaDataSourceMock) DeleteUserScramCredentials(deletions []api.ScramCredentialDeletion) error {
+	for _, d := range deletions {
+		if err := api.ValidateScramDeletion(d); err != nil {
+			return err
+		}
+	}
+	kp.scramMu.Lock()
+	defer kp.scramMu.Unlock()
+	kp.seedScramUsers()
+
+	for _, d := range deletions {
+		if _, ok := kp.scramUsers[d.User][d.Mechanism]; !ok {
+			return fmt.Errorf("user %q has no %s credential", d.User, d.Mechanism)
+		}
+	}
+	for _, d := range deletions {
+		delete(kp.scramUsers[d.User], d.Mechanism)
+		if len(kp.scramUsers[d.User]) == 0 {
+			delete(kp.scramUsers, d.User)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package mock
+
+import (
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockScramUsers_RotateAndDelete(t *testing.T) {
+	m := &KafkaDataSourceMock{}
+
+	require.NoError(t, m.UpsertUserScramCredentials([]api.ScramCredentialUpsert{
+		{User: "carol", Mechanism: api.ScramSHA512, Iterations: 8192, Password: "pw"},
+		{User: "bob", Mechanism: api.ScramSHA512, Iterations: 16384, Password: "rotated"},
+	}))
+	got, err := m.DescribeUserScramCredentials([]string{"bob", "carol", "nobody"})
+	require.NoError(t, err)
+	assert.Equal(t, []api.ScramUser{
+		{Name: "bob", Credentials: []api.ScramCredentialInfo{{Mechanism: api.ScramSHA512, Iterations: 16384}}},
+		{Name: "carol", Credentials: []api.ScramCredentialInfo{{Mechanism: api.ScramSHA512, Iterations: 8192}}},
+	}, got)
+
+	// Removing carol's only credential removes the user.
+	require.NoError(t, m.DeleteUserScramCredentials([]api.ScramCredentialDeletion{{User: "carol", Mechanism: api.ScramSHA512}}))
+	all, err := m.DescribeUserScramCredentials(nil)
+	require.NoError(t, err)
+	for _, u := range all {
+		assert.NotEqual(t, "carol", u.Name)
+	}
+
+	assert.Error(t, m.DeleteUserScramCredentials([]api.ScramCredentialDeletion{{User: "bob", Mechanism: api.ScramSHA256}}),
+		"bob has no SCRAM-SHA-256 credential")
+}
+
+func TestMockScramUsers_Validation(t *testing.T) {
+	m := &KafkaDataSourceMock{}
+	err := m.UpsertUserScramCredentials([]api.ScramCredentialUpsert{{User: "dave", Mechanism: "MD5", Iterations: 4096, Password: "pw"}})
+	var ve api.ScramValidationError
+	require.ErrorAs(t, err, &ve)
+	assert.Equal(t, "mechanism", ve.Field)
+
+	users, _ := m.DescribeUserScramCredentials([]string{"dave"})
+	assert.Empty(t, users, "an invalid upsert must not create the user")
+}
@@ -3,6 +3,7 @@ package mock
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -90,14 +91,6 @@ func (kp *KafkaDataSourceMock) GetTopicDetails(topicName string) (api.TopicDetai
 	if n < 1 {
 		n = 1
 	}
-	rf := int(topic.ReplicationFactor)
-	if rf < 1 {
-		rf = 1
-	}
-	if rf > len(mockBrokerIDs) {
-		rf = len(mockBrokerIDs)
-	}
-
 	perPartition := topic.MessageCount / int64(n)
 	details := api.TopicDetails{
 		Name:              topicName,
@@ -105,11 +98,8 @@ func (kp *KafkaDataSourceMock) GetTopicDetails(topicName string) (api.TopicDetai
 		IsInternal:        strings.HasPrefix(topicName, "__"),
 	}
 	for i := int32(0); i < n; i++ {
-		leader := mockBrokerIDs[int(i)%len(mockBrokerIDs)]
-		replicas := make([]int32, 0, rf)
-		for r := 0; r < rf; r++ {
-			replicas = append(replicas, mockBrokerIDs[(int(i)+r)%len(mockBrokerIDs)])
-		}
+		replicas := mockReplicas(topic, i)
+		leader := kp.mockLeader(topicName, i, replicas)
 		isr := append([]int32{}, replicas...)
 		// Make partition 1 under-replicated when RF allows it.
 		if i == 1 && len(isr) > 1 {
@@ -120,7 +110,7 @@ func (kp *KafkaDataSourceMock) GetTopicDetails(topicName string) (api.TopicDetai
 			Leader:         leader,
 			Replicas:       replicas,
 			ISR:            isr,
-			EarliestOffset: 0,
+			EarliestOffset: min(kp.logStart[api.TopicPartition{Topic: topicName, Partition: i}], perPartition),
 			LatestOffset:   perPartition,
 		})
 		details.TotalReplicas += len(replicas)
@@ -132,6 +122,27 @@ func (kp *KafkaDataSourceMock) GetTopicDetails(topicName string) (api.TopicDetai
 	return details, nil
 }
 
+// mockReplicas is partition i's replica list: the topic's explicit assignment
+// (set by CreateTopic or an executed reassignment) or the fixture round robin
+// over mockBrokerIDs. The first replica leads.
+func mockReplicas(topic api.Topic, i int32) []int32 {
+	if rs := topic.ReplicaAssignment[i]; len(rs) > 0 {
+		return append([]int32{}, rs...)
+	}
+	rf := int(topic.ReplicationFactor)
+	if rf < 1 {
+		rf = 1
+	}
+	if rf > len(mockBrokerIDs) {
+		rf = len(mockBrokerIDs)
+	}
+	replicas := make([]int32, 0, rf)
+	for r := 0; r < rf; r++ {
+		replicas = append(replicas, mockBrokerIDs[(int(i)+r)%len(mockBrokerIDs)])
+	}
+	return replicas
+}
+
 // --- TP-4: GetTopicSizes ---
 
 // GetTopicSizes returns deterministic sizes (1 KiB per message) for known topics.
@@ -190,6 +201,7 @@ func (kp *KafkaDataSourceMock) DeleteTopic(name string) error {
 		return api.TopicNotFoundError{TopicName: name}
 	}
 	delete(topics, name)
+	kp.forgetLogStart(name)
 	return nil
 }
 
@@ -284,12 +296,98 @@ func (kp *KafkaDataSourceMock) PurgeTopicMessages(name string, partition int32)
 		}
 	}
 	topics[name] = topic
+	kp.forgetLogStart(name)
 	kp.counterMutex.Lock()
 	delete(kp.messageCounters, name)
 	kp.counterMutex.Unlock()
 	return nil
 }
 
+// DeleteRecordsBefore implements api.KafkaDataSource by raising the
+// partitions' log-start offsets. Times resolve against the fixture timeline,
+// on which the record at offset o was written o minutes after mockRecordEpoch.
+func (kp *KafkaDataSourceMock) DeleteRecordsBefore(_ context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	switch {
+	case req.Topic == "":
+		return nil, api.InvalidDeleteRecordsError{Reason: "a topic is required"}
+	case req.Before == nil && len(req.Offsets) == 0:
+		return nil, api.InvalidDeleteRecordsError{Reason: "either per-partition offsets or a time is required"}
+	case req.Before != nil && len(req.Offsets) > 0:
+		return nil, api.InvalidDeleteRecordsError{Reason: "offsets and a time are mutually exclusive"}
+	}
+	details, err := kp.GetTopicDetails(req.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	kp.topicMu.Lock()
+	defer kp.topicMu.Unlock()
+	policy := "delete"
+	if p, has := currentTopics()[req.Topic].ConfigEntries["cleanup.policy"]; has && p != nil {
+		policy = *p
+	}
+	if !cleanupAllowsDelete(policy) {
+		return nil, api.CleanupPolicyError{TopicName: req.Topic, Policy: policy}
+	}
+
+	targets := req.Offsets
+	if req.Before != nil {
+		// The first record at or after Before; none (-1) deletes everything.
+		first := int64(math.Ceil(float64(req.Before.Sub(mockRecordEpoch)) / float64(time.Minute)))
+		partitions := req.Partitions
+		if len(partitions) == 0 {
+			for _, p := range details.Partitions {
+				partitions = append(partitions, p.ID)
+			}
+		}
+		targets = make(map[int32]int64, len(partitions))
+		for _, id := range partitions {
+			targets[id] = max(first, 0)
+			if int(id) < len(details.Partitions) && first >= details.Partitions[id].LatestOffset {
+				targets[id] = -1
+			}
+		}
+	}
+
+	out := make([]api.RecordDeletion, 0, len(targets))
+	for id, target := range targets {
+		if id < 0 || int(id) >= len(details.Partitions) {
+			return nil, api.NewPartitionError("partition not found", req.Topic, id)
+		}
+		p := details.Partitions[id]
+		if target < 0 && req.Before == nil {
+			return nil, api.InvalidDeleteRecordsError{Reason: fmt.Sprintf("negative offset %d for partition %d", target, id)}
+		}
+		if target < 0 {
+			target = p.LatestOffset
+		}
+		if target > p.LatestOffset {
+			return nil, api.InvalidDeleteRecordsError{Reason: fmt.Sprintf("offset %d is past the end (%d) of partition %d", target, p.LatestOffset, id)}
+		}
+		d := api.RecordDeletion{Partition: id, Offset: max(target, p.EarliestOffset)}
+		if target > p.EarliestOffset {
+			d.Deleted = target - p.EarliestOffset
+			if kp.logStart == nil {
+				kp.logStart = map[api.TopicPartition]int64{}
+			}
+			kp.logStart[api.TopicPartition{Topic: req.Topic, Partition: id}] = target
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Partition < out[j].Partition })
+	return out, nil
+}
+
+// forgetLogStart drops the topic's raised log-start offsets once its records
+// are purged or the topic is deleted. Callers hold kp.topicMu.
+func (kp *KafkaDataSourceMock) forgetLogStart(topic string) {
+	for tp := range kp.logStart {
+		if tp.Topic == topic {
+			delete(kp.logStart, tp)
+		}
+	}
+}
+
 func cleanupAllowsDelete(policy string) bool {
 	for _, p := range strings.Split(policy, ",") {
 		if strings.TrimSpace(p) == "delete" {
@@ -342,6 +440,7 @@ func (kp *KafkaDataSourceMock) ChangeReplicationFactor(name string, newFactor in
 		return api.InvalidReplicationFactorError{TopicName: name, Reason: fmt.Sprintf("replication factor is already %d", newFactor)}
 	}
 	topic.ReplicationFactor = newFactor
+	topic.ReplicaAssignment = nil // back to the fixture layout at the new factor
 	topics[name] = topic
 	return nil
 }
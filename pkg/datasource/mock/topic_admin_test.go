@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/stretchr/testify/assert"
@@ -111,6 +112,44 @@ func TestMockPurgeMessages(t *testing.T) {
 	assert.True(t, errors.As(ds.PurgeTopicMessages("compacted", -1), &cp))
 }
 
+func TestMockDeleteRecordsBefore(t *testing.T) {
+	ds := newTopicMock(t)
+	ctx := context.Background()
+	require.NoError(t, ds.CreateTopic("truncate-me", 2, 1, nil))
+	t.Cleanup(func() { _ = ds.DeleteTopic("truncate-me") })
+	topics := currentTopics()
+	topic := topics["truncate-me"]
+	topic.MessageCount = 200 // 100 per partition
+	topics["truncate-me"] = topic
+
+	got, err := ds.DeleteRecordsBefore(ctx, api.DeleteRecordsRequest{Topic: "truncate-me", Offsets: map[int32]int64{1: 30}})
+	require.NoError(t, err)
+	assert.Equal(t, []api.RecordDeletion{{Partition: 1, Offset: 30, Deleted: 30}}, got)
+
+	// Record o was written o minutes after the epoch: 40 minutes in is offset 40.
+	before := mockRecordEpoch.Add(40 * time.Minute)
+	got, err = ds.DeleteRecordsBefore(ctx, api.DeleteRecordsRequest{Topic: "truncate-me", Before: &before})
+	require.NoError(t, err)
+	assert.Equal(t, []api.RecordDeletion{{Partition: 0, Offset: 40, Deleted: 40}, {Partition: 1, Offset: 40, Deleted: 10}}, got)
+
+	details, err := ds.GetTopicDetails("truncate-me")
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), details.Partitions[1].EarliestOffset)
+	assert.Equal(t, int64(120), details.MessageCount())
+
+	var inv api.InvalidDeleteRecordsError
+	_, err = ds.DeleteRecordsBefore(ctx, api.DeleteRecordsRequest{Topic: "truncate-me", Offsets: map[int32]int64{0: 101}})
+	assert.True(t, errors.As(err, &inv))
+
+	require.NoError(t, ds.PurgeTopicMessages("truncate-me", -1))
+	topic = topics["truncate-me"]
+	topic.MessageCount = 200
+	topics["truncate-me"] = topic
+	details, err = ds.GetTopicDetails("truncate-me")
+	require.NoError(t, err)
+	assert.Zero(t, details.Partitions[1].EarliestOffset, "a purge resets the log start")
+}
+
 func TestMockRecreateAndReplicationFactor(t *testing.T) {
 	ds := newTopicMock(t)
 	require.NoError(t, ds.CreateTopic("rc", 3, 2, nil))
@@ -43,6 +43,7 @@ type Field struct {
 	Options   []string  // choices for Select fields
 	Validator Validator // optional custom validation
 	Default   string    // initial value (text/numeric) or default option
+	Masked    bool      // echo text input as bullets (passwords)
 }
 
 // FormSubmitMsg is emitted when the form is submitted with all fields valid.
@@ -93,6 +94,10 @@ func New(fields []Field) *Form {
 			ti := textinput.New()
 			ti.SetValue(def.Default)
 			ti.Width = 40
+			if def.Masked {
+				ti.EchoMode = textinput.EchoPassword
+				ti.EchoCharacter = '•'
+			}
 			fs.input = ti
 		case Select:
 			for i, o := range def.Options {
@@ -197,3 +197,11 @@ func TestBoolToggle(t *testing.T) {
 	f.Update(key(" "))
 	assert.Equal(t, "false", f.Values()["b"])
 }
+
+func TestMaskedFieldHidesValue(t *testing.T) {
+	f := New([]Field{{Name: "password", Label: "Password", Type: Text, Masked: true}})
+	f.Focus()
+	typeInto(f, "s3cret")
+	assert.Equal(t, "s3cret", f.Values()["password"], "the submitted value is the clear text")
+	assert.NotContains(t, f.View(), "s3cret")
+}
@@ -52,12 +52,17 @@ func Init(opts InitOptions) {
 
 	dataSource = &mock.KafkaDataSourceMock{}
 	if !opts.Mock {
-		// Apply CLI overrides before the datasource reads config.
-		kafds.SetOverrides(opts.Brokers, opts.SchemaRegistry, opts.Cluster, opts.Verbose)
 		ds := kafds.NewKafkaDataSourceKaf()
+		// Apply CLI overrides before the datasource reads config.
+		ds.SetOverrides(kafds.Overrides{
+			Brokers:        opts.Brokers,
+			SchemaRegistry: opts.SchemaRegistry,
+			Cluster:        opts.Cluster,
+			Verbose:        opts.Verbose,
+		})
 		// Run the interactive OAuth2 device-code grant (if configured) while
 		// stdout is still the terminal — before InitTUIWriters redirects it (AA-13).
-		if err := kafds.PrepareOAuthDeviceFlow(opts.ConfigFile, os.Stdout); err != nil {
+		if err := ds.PrepareOAuthDeviceFlow(opts.ConfigFile, os.Stdout); err != nil {
 			log.Fatalf("OAuth device authentication failed: %v", err)
 		}
 		kafds.InitTUIWriters() // redirect stdout/stderr/sarama to log file before TUI starts
@@ -78,24 +83,34 @@ func Init(opts InitOptions) {
 	}
 	validateClusters(dataSource, &appCfg)
 
-	// Build the authorization gate + audit service and wrap the datasource with
-	// the enforcement guard before it reaches the UI (AA-8). A bad authz config
-	// is fatal (fail fast before the TUI starts).
+	// Wrap the datasource with the enforcement guard before it reaches the UI
+	// (AA-8). A bad authz config is fatal (fail fast before the TUI starts).
+	guard, gate, err := NewGuardedDataSource(dataSource, appCfg, opts.ReadOnly)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	dataSource = guard
+
+	openUIFunc(dataSource, appCfg, gate, audit.ResolveUser(), opts.Topic, opts.Resource, opts.MetricsListen)
+}
+
+// NewGuardedDataSource builds the authorization gate + audit service from the
+// kafui config and wraps ds with the enforcement guard. forceReadOnly is the
+// global --read-only flag. The TUI and the non-interactive CLI commands share
+// it so both enforce the same profiles and write the same audit records.
+func NewGuardedDataSource(ds api.KafkaDataSource, appCfg appconfig.Config, forceReadOnly bool) (*datasource.Guard, *authz.Gate, error) {
 	readOnlyForCluster := func(cluster string) bool {
 		ext, ok := appCfg.Clusters[cluster]
 		return ok && ext.ReadOnly
 	}
-	gate, err := authz.NewGate(appCfg.Authz, readOnlyForCluster, opts.ReadOnly)
+	gate, err := authz.NewGate(appCfg.Authz, readOnlyForCluster, forceReadOnly)
 	if err != nil {
-		log.Fatalf("invalid authz configuration: %v", err)
+		return nil, nil, fmt.Errorf("invalid authz configuration: %w", err)
 	}
-	if !gate.Enabled() && !opts.ReadOnly {
+	if !gate.Enabled() && !forceReadOnly {
 		shared.Log.Warn("authorization is disabled: access is unrestricted (no profiles configured)")
 	}
-	auditSvc := buildAuditService(appCfg.Audit)
-	dataSource = datasource.NewGuard(dataSource, gate, auditSvc)
-
-	openUIFunc(dataSource, appCfg, gate, audit.ResolveUser(), opts.Topic, opts.Resource, opts.MetricsListen)
+	return datasource.NewGuard(ds, gate, buildAuditService(appCfg.Audit)), gate, nil
 }
 
 // buildAuditService constructs the audit service from config, returning a
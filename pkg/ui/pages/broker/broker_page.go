@@ -1,15 +1,20 @@
 // Package broker implements the broker detail page (dynamic page ID
-// "broker:<id>"). It renders a summary strip plus three tabs — Log Dirs,
-// Configs and Metrics — over the shared template shell. The page is created by
-// the router; see NewModelWithCommon / NewModelWithInfo for the constructors the
-// router wires to the "broker:<id>" dynamic ID.
+// "broker:<id>"). It renders a summary strip plus four tabs — Log Dirs,
+// Configs, Metrics and the KRaft controller Quorum — over the shared template
+// shell. The page is created by the router; see NewModelWithCommon /
+// NewModelWithInfo for the constructors the router wires to the "broker:<id>"
+// dynamic ID.
 package broker
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/ui/components/editor"
@@ -69,6 +74,12 @@ type Model struct {
 	metricsLoaded bool
 	metricsErr    error
 
+	// Quorum tab
+	quorum       api.QuorumInfo
+	quorumLoaded bool
+	quorumErr    error
+	quorumTable  table.Model
+
 	// Reassignment form
 	moveForm *form.Form
 }
@@ -106,6 +117,7 @@ func newModel(common *core.Common, brokerID int32, info api.BrokerInfo, haveInfo
 	m.logTable = table.New(table.WithColumns(logDirColumns()), table.WithFocused(true), table.WithHeight(10))
 	m.partTable = table.New(table.WithColumns(partitionColumns()), table.WithFocused(true), table.WithHeight(8))
 	m.cfgTable = table.New(table.WithColumns(configColumns()), table.WithFocused(true), table.WithHeight(12))
+	m.quorumTable = table.New(table.WithColumns(quorumColumns()), table.WithFocused(true), table.WithHeight(8))
 	m.metricsViewer = editor.NewViewer("")
 	m.metricsViewer.SetHighlight(true)
 
@@ -146,6 +158,16 @@ func configColumns() []table.Column {
 	}
 }
 
+func quorumColumns() []table.Column {
+	return []table.Column{
+		{Title: "Replica", Width: 10},
+		{Title: "Role", Width: 10},
+		{Title: "Log End Offset", Width: 16},
+		{Title: "Lag", Width: 10},
+		{Title: "Last Fetch", Width: 16},
+	}
+}
+
 // --- core.Page ---
 
 func (m *Model) Init() tea.Cmd { return m.reusableApp.Init() }
@@ -172,6 +194,8 @@ func (m *Model) SetDimensions(width, height int) {
 	m.partTable.SetHeight(body / 2)
 	m.cfgTable.SetWidth(width)
 	m.cfgTable.SetHeight(body)
+	m.quorumTable.SetWidth(width)
+	m.quorumTable.SetHeight(body)
 	m.metricsViewer.SetDimensions(width, body)
 	m.reusableApp.Update(tea.WindowSizeMsg{Width: width, Height: height})
 }
@@ -180,7 +204,7 @@ func (m *Model) GetID() string    { return fmt.Sprintf("broker:%d", m.brokerID)
 func (m *Model) GetTitle() string { return fmt.Sprintf("Broker %d", m.brokerID) }
 
 func (m *Model) GetHelp() []key.Binding {
-	return []key.Binding{m.keys.NextTab, m.keys.Expand, m.keys.Edit, m.keys.Move, m.keys.Search, m.keys.Retry, m.keys.Back}
+	return []key.Binding{m.keys.NextTab, m.keys.Expand, m.keys.Edit, m.keys.Move, m.keys.Elect, m.keys.Unclean, m.keys.Search, m.keys.Retry, m.keys.Back}
 }
 
 func (m *Model) HandleNavigation(msg tea.Msg) (core.Page, tea.Cmd) { return m, nil }
@@ -236,6 +260,11 @@ func (m *Model) loadTab(t tab) tea.Cmd {
 			return nil
 		}
 		return m.loadMetrics()
+	case tabQuorum:
+		if m.quorumLoaded {
+			return nil
+		}
+		return m.loadQuorum()
 	default:
 		if m.logDirsLoaded {
 			return nil
@@ -274,6 +303,15 @@ func (m *Model) loadMetrics() tea.Cmd {
 	}
 }
 
+func (m *Model) loadQuorum() tea.Cmd {
+	ds := m.common.DataSource
+	id := m.brokerID
+	return func() tea.Msg {
+		q, err := ds.DescribeQuorum(context.Background())
+		return quorumLoadedMsg{brokerID: id, quorum: q, err: err}
+	}
+}
+
 // --- message handling (via the content provider) ---
 
 func (m *Model) handle(msg tea.Msg) tea.Cmd {
@@ -323,10 +361,20 @@ func (m *Model) handle(msg tea.Msg) tea.Cmd {
 			}
 		}
 		return nil
+	case quorumLoadedMsg:
+		if v.brokerID == m.brokerID {
+			m.quorum = v.quorum
+			m.quorumErr = v.err
+			m.quorumLoaded = true
+			m.rebuildQuorumTable()
+		}
+		return nil
 	case configAlteredMsg:
 		return m.handleConfigAltered(v)
 	case replicaMovedMsg:
 		return m.handleReplicaMoved(v)
+	case leadersElectedMsg:
+		return m.handleLeadersElected(v)
 	case form.FormSubmitMsg:
 		return m.handleMoveSubmit(v)
 	case form.FormCancelMsg:
@@ -348,6 +396,10 @@ func (m *Model) forwardToActive(msg tea.Msg) tea.Cmd {
 	case tabMetrics:
 		_, cmd := m.metricsViewer.Update(msg)
 		return cmd
+	case tabQuorum:
+		var cmd tea.Cmd
+		m.quorumTable, cmd = m.quorumTable.Update(msg)
+		return cmd
 	default:
 		var cmd tea.Cmd
 		if m.expanded >= 0 {
@@ -372,7 +424,7 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		return m.handleSearchKey(msg)
 	}
 
-	// Tab switching: tab key + number keys 1/2/3.
+	// Tab switching: tab key + number keys 1-4.
 	switch msg.String() {
 	case "tab":
 		return m.switchTab((m.active + 1) % tab(len(tabTitles)))
@@ -382,8 +434,14 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		return m.switchTab(tabConfigs)
 	case "3":
 		return m.switchTab(tabMetrics)
+	case "4":
+		return m.switchTab(tabQuorum)
 	case "r":
 		return m.retry()
+	case "L":
+		return m.confirmElection(api.ElectPreferred)
+	case "U":
+		return m.confirmElection(api.ElectUnclean)
 	}
 
 	switch m.active {
@@ -412,6 +470,9 @@ func (m *Model) retry() tea.Cmd {
 	case tabMetrics:
 		m.metricsLoaded = false
 		return m.loadMetrics()
+	case tabQuorum:
+		m.quorumLoaded = false
+		return m.loadQuorum()
 	default:
 		m.logDirsLoaded = false
 		return m.loadLogDirs()
@@ -555,6 +616,55 @@ func (m *Model) handleReplicaMoved(v replicaMovedMsg) tea.Cmd {
 	return tea.Batch(core.NewNotification(core.StatusSuccess, "Replica moved", fmt.Sprintf("%s-%d → %s", v.topic, v.partition, v.logDir)), m.loadLogDirs())
 }
 
+// --- Leader election ---
+
+// confirmElection asks to elect leaders for this broker's partitions: those
+// it should lead but does not (preferred), or its leaderless ones (unclean).
+// The confirmation lists the partitions concerned.
+func (m *Model) confirmElection(t api.ElectionType) tea.Cmd {
+	id := m.brokerID
+	ds := m.common.DataSource
+	keep := func(p api.PartitionLeadership) bool { return p.Preferred() == id }
+	if t == api.ElectUnclean {
+		keep = func(p api.PartitionLeadership) bool { return slices.Contains(p.Replicas, id) }
+	}
+	return func() tea.Msg {
+		parts, err := ds.DescribeLeadership(context.Background(), nil)
+		if err != nil {
+			return core.NotificationMsg{Severity: core.StatusError, Title: "Leader election", Message: err.Error()}
+		}
+		cands := shared.ElectionCandidates(parts, t, keep)
+		if len(cands) == 0 {
+			msg := fmt.Sprintf("Broker %d leads every partition it prefers", id)
+			if t == api.ElectUnclean {
+				msg = fmt.Sprintf("Every partition on broker %d has a leader", id)
+			}
+			return core.NotificationMsg{Severity: core.StatusInfo, Title: "Leader election", Message: msg}
+		}
+		return core.ShowConfirmMsg{
+			Title:        fmt.Sprintf("Elect %s leaders", t),
+			Message:      shared.ElectionConfirmText(t, cands),
+			Danger:       t == api.ElectUnclean,
+			ConfirmLabel: "Elect",
+			OnConfirm: func() tea.Msg {
+				results, err := ds.ElectLeaders(context.Background(), api.ElectLeadersRequest{Type: t, Partitions: shared.ElectionPartitions(cands)})
+				detail, failed := shared.ElectionOutcome(results)
+				if err == nil && failed {
+					err = errors.New(detail)
+				}
+				return leadersElectedMsg{brokerID: id, detail: detail, err: err}
+			},
+		}
+	}
+}
+
+func (m *Model) handleLeadersElected(v leadersElectedMsg) tea.Cmd {
+	if v.err != nil {
+		return func() tea.Msg { return shared.NewUIError("elect", "Leader election failed", v.err) }
+	}
+	return tea.Batch(core.NewNotification(core.StatusSuccess, "Leader election", v.detail), m.loadStats())
+}
+
 // --- Configs tab (BR-15/BR-16) ---
 
 func (m *Model) handleConfigsKey(msg tea.KeyMsg) tea.Cmd {
@@ -721,6 +831,8 @@ func (m *Model) render(width, height int) string {
 		m.logTable.SetHeight(th)
 	}
 	m.cfgTable.SetHeight(th)
+	m.quorumTable.SetWidth(innerW)
+	m.quorumTable.SetHeight(th - 2)
 
 	var b strings.Builder
 	b.WriteString(m.summaryStrip())
@@ -747,6 +859,8 @@ func (m *Model) render(width, height int) string {
 		b.WriteString(m.renderConfigs())
 	case tabMetrics:
 		b.WriteString(m.renderMetrics())
+	case tabQuorum:
+		b.WriteString(m.renderQuorum())
 	default:
 		b.WriteString(m.renderLogDirs())
 	}
@@ -865,6 +979,61 @@ func (m *Model) renderMetrics() string {
 	return m.metricsViewer.View()
 }
 
+// --- Quorum tab ---
+
+// rebuildQuorumTable lists voters then observers; this broker's row is starred.
+func (m *Model) rebuildQuorumTable() {
+	q := m.quorum
+	rows := make([]table.Row, 0, len(q.Voters)+len(q.Observers))
+	for _, rs := range [][]api.QuorumReplica{q.Voters, q.Observers} {
+		for _, r := range rs {
+			id := strconv.FormatInt(int64(r.ReplicaID), 10)
+			if r.ReplicaID == m.brokerID {
+				id = "* " + id
+			}
+			rows = append(rows, table.Row{
+				id,
+				q.Role(r.ReplicaID),
+				strconv.FormatInt(r.LogEndOffset, 10),
+				strconv.FormatInt(r.Lag, 10),
+				sinceLabel(r.LastFetch),
+			})
+		}
+	}
+	m.quorumTable.SetRows(rows)
+}
+
+func (m *Model) renderQuorum() string {
+	if !m.quorumLoaded {
+		return m.common.Styles.Muted.Render("Loading quorum…")
+	}
+	var ns api.NotSupportedError
+	if errors.As(m.quorumErr, &ns) {
+		return m.common.Styles.Muted.Render("ZooKeeper mode or pre-3.3 KRaft: quorum not available")
+	}
+	if m.quorumErr != nil {
+		return m.common.Styles.Error.Render("Error: "+m.quorumErr.Error()) + "\n" + m.common.Styles.Muted.Render("Press r to retry.")
+	}
+	q := m.quorum
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Leader: %d   Epoch: %d   High watermark: %d", q.LeaderID, q.LeaderEpoch, q.HighWatermark))
+	if role := q.Role(m.brokerID); role != "" {
+		b.WriteString("   ")
+		b.WriteString(m.common.Styles.StatusStyle.Success.Render(fmt.Sprintf("Broker %d is a %s", m.brokerID, role)))
+	}
+	b.WriteString("\n")
+	b.WriteString(stylesPkg.FrameTable(m.quorumTable.View()))
+	return b.String()
+}
+
+// sinceLabel renders a quorum fetch timestamp as an age; zero is unknown.
+func sinceLabel(t time.Time) string {
+	if t.IsZero() {
+		return "–"
+	}
+	return time.Since(t).Truncate(time.Millisecond).String() + " ago"
+}
+
 // --- ordering / filtering helpers (pure) ---
 
 // sourceRank orders config sources: dynamic* first, then static broker, default,
@@ -1,6 +1,8 @@
 package broker
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/Benny93/kafui/pkg/api"
@@ -173,3 +175,64 @@ func TestBrokerPage_MetricsLoaded(t *testing.T) {
 	assert.True(t, m.metricsLoaded)
 	assert.NoError(t, m.metricsErr)
 }
+
+func TestBrokerPage_QuorumTab(t *testing.T) {
+	m := newModel(testCommon(), 2, api.BrokerInfo{ID: 2}, true)
+	m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+	require.Equal(t, tabQuorum, m.active)
+	m.handle(m.loadQuorum()())
+	require.True(t, m.quorumLoaded)
+	require.NoError(t, m.quorumErr)
+
+	rows := m.quorumTable.Rows()
+	require.Len(t, rows, 4) // three voters + one observer
+	assert.Equal(t, []string{"1", "leader"}, []string(rows[0][:2]))
+	assert.Equal(t, []string{"* 2", "voter"}, []string(rows[1][:2]))
+	assert.Equal(t, "observer", rows[3][1])
+	assert.Contains(t, m.renderQuorum(), "Broker 2 is a voter")
+}
+
+func TestBrokerPage_QuorumNotSupported(t *testing.T) {
+	m := newModel(testCommon(), 1, api.BrokerInfo{ID: 1}, true)
+	m.active = tabQuorum
+	m.handle(quorumLoadedMsg{brokerID: 1, err: api.NotSupportedError{Operation: "DescribeQuorum"}})
+	assert.Contains(t, m.renderQuorum(), "quorum not available")
+}
+
+func TestBrokerPage_ElectPreferredLeaders(t *testing.T) {
+	common := testCommon()
+	parts, err := common.DataSource.DescribeLeadership(context.Background(), nil)
+	require.NoError(t, err)
+	var id int32 = -1
+	for _, p := range parts {
+		if p.NonPreferred() {
+			id = p.Preferred()
+			break
+		}
+	}
+	require.NotEqual(t, int32(-1), id, "fixtures should have a non-preferred leader")
+
+	m := newModel(common, id, api.BrokerInfo{ID: id}, true)
+	cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	require.NotNil(t, cmd)
+	confirm, ok := cmd().(core.ShowConfirmMsg)
+	require.True(t, ok, "non-preferred partitions ask for confirmation")
+	assert.Contains(t, confirm.Message, fmt.Sprintf("→ %d", id))
+	assert.False(t, confirm.Danger)
+
+	elected, ok := confirm.OnConfirm().(leadersElectedMsg)
+	require.True(t, ok)
+	require.NoError(t, elected.err)
+	assert.NotNil(t, m.handle(elected))
+
+	// Nothing left to elect.
+	_, ok = m.confirmElection(api.ElectPreferred)().(core.NotificationMsg)
+	assert.True(t, ok)
+}
+
+func TestBrokerPage_UncleanWithoutLeaderlessPartitions(t *testing.T) {
+	m := newModel(testCommon(), 1, api.BrokerInfo{ID: 1}, true)
+	msg, ok := m.confirmElection(api.ElectUnclean)().(core.NotificationMsg)
+	require.True(t, ok)
+	assert.Contains(t, msg.Message, "has a leader")
+}
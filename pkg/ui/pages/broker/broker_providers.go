@@ -33,6 +33,6 @@ func (p *contentProvider) GetContentSize(width int) int {
 type helpKeyMap struct{ keys pageKeys }
 
 func (h helpKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{h.keys.NextTab, h.keys.Expand, h.keys.Edit, h.keys.Move, h.keys.Search, h.keys.Back}
+	return []key.Binding{h.keys.NextTab, h.keys.Expand, h.keys.Edit, h.keys.Move, h.keys.Elect, h.keys.Search, h.keys.Back}
 }
 func (h helpKeyMap) FullHelp() [][]key.Binding { return [][]key.Binding{h.ShortHelp()} }
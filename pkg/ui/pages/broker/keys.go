@@ -8,6 +8,8 @@ type pageKeys struct {
 	Expand  key.Binding
 	Edit    key.Binding
 	Move    key.Binding
+	Elect   key.Binding
+	Unclean key.Binding
 	Retry   key.Binding
 	Search  key.Binding
 	Back    key.Binding
@@ -19,6 +21,8 @@ func defaultKeys() pageKeys {
 		Expand:  key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "expand")),
 		Edit:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit config")),
 		Move:    key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "move replica")),
+		Elect:   key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "elect preferred leaders")),
+		Unclean: key.NewBinding(key.WithKeys("U"), key.WithHelp("U", "unclean election")),
 		Retry:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry")),
 		Search:  key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
 		Back:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
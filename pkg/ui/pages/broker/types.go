@@ -9,6 +9,7 @@ const (
 	tabLogDirs tab = iota
 	tabConfigs
 	tabMetrics
+	tabQuorum
 )
 
 func (t tab) String() string {
@@ -17,13 +18,15 @@ func (t tab) String() string {
 		return "Configs"
 	case tabMetrics:
 		return "Metrics"
+	case tabQuorum:
+		return "Quorum"
 	default:
 		return "Log Dirs"
 	}
 }
 
 // tabTitles is the ordered tab bar.
-var tabTitles = []tab{tabLogDirs, tabConfigs, tabMetrics}
+var tabTitles = []tab{tabLogDirs, tabConfigs, tabMetrics, tabQuorum}
 
 // Async load result messages. Each carries the broker ID it was requested for so
 // stale responses (after navigation) can be discarded.
@@ -59,6 +62,12 @@ type (
 		err      error
 	}
 
+	quorumLoadedMsg struct {
+		brokerID int32
+		quorum   api.QuorumInfo
+		err      error
+	}
+
 	// configAlteredMsg is dispatched after a confirmed config change attempt.
 	configAlteredMsg struct {
 		brokerID int32
@@ -67,6 +76,13 @@ type (
 		err      error
 	}
 
+	// leadersElectedMsg is dispatched after a confirmed leader election.
+	leadersElectedMsg struct {
+		brokerID int32
+		detail   string
+		err      error
+	}
+
 	// replicaMovedMsg is dispatched after a confirmed replica log-dir move.
 	replicaMovedMsg struct {
 		brokerID  int32
@@ -66,6 +66,24 @@ func TestCandidateFromValues_SASLMappings(t *testing.T) {
 		assert.Empty(t, ext.SASL.Username)
 	})
 
+	t.Run("SCRAM delegation token", func(t *testing.T) {
+		_, ext, err := candidateFromValues(base(map[string]string{
+			fSaslMechanism: "SCRAM-SHA-256", fSaslUsername: "token-id", fSaslPassword: "aG1hYw==",
+			fSaslTokenAuth: "true",
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, ext.SASL)
+		assert.True(t, ext.SASL.TokenAuth)
+		assert.Equal(t, "token-id", ext.SASL.Username)
+	})
+
+	t.Run("delegation token needs SCRAM", func(t *testing.T) {
+		_, _, err := candidateFromValues(base(map[string]string{
+			fSaslMechanism: "PLAIN", fSaslUsername: "u", fSaslPassword: "p", fSaslTokenAuth: "true",
+		}))
+		assert.ErrorContains(t, err, "SCRAM")
+	})
+
 	t.Run("none omits SASL", func(t *testing.T) {
 		_, ext, err := candidateFromValues(base(map[string]string{fSaslMechanism: noneOption}))
 		require.NoError(t, err)
@@ -22,6 +22,7 @@ const (
 	fSaslClientID     = "saslClientID"
 	fSaslClientSecret = "saslClientSecret"
 	fSaslTokenURL     = "saslTokenURL"
+	fSaslTokenAuth    = "saslTokenAuth"
 	fTLSCa            = "tlsCaPath"
 	fTLSCert          = "tlsCertPath"
 	fTLSKey           = "tlsKeyPath"
@@ -96,6 +97,7 @@ func buildFields(name string, ext appconfig.ClusterExtension) []formpkg.Field {
 		{Name: fSaslMechanism, Label: "SASL Mechanism", Type: formpkg.Select, Options: saslMechanismOptions, Default: mech},
 		{Name: fSaslUsername, Label: "SASL Username", Type: formpkg.Text, Default: sasl.Username},
 		{Name: fSaslPassword, Label: "SASL Password", Type: formpkg.Text, Default: sasl.Password},
+		{Name: fSaslTokenAuth, Label: "SASL Delegation Token (SCRAM: username = token ID, password = HMAC)", Type: formpkg.Bool, Default: boolStr(sasl.TokenAuth)},
 		{Name: fSaslClientID, Label: "SASL Client ID (OAUTHBEARER)", Type: formpkg.Text, Default: sasl.ClientID},
 		{Name: fSaslClientSecret, Label: "SASL Client Secret (OAUTHBEARER)", Type: formpkg.Text, Default: sasl.ClientSecret},
 		{Name: fSaslTokenURL, Label: "SASL Token URL (OAUTHBEARER)", Type: formpkg.Text, Default: sasl.TokenURL},
@@ -142,6 +144,12 @@ func candidateFromValues(v map[string]string) (string, appconfig.ClusterExtensio
 		}
 		ext.SASL = s
 	}
+	if v[fSaslTokenAuth] == "true" {
+		if ext.SASL == nil || !strings.HasPrefix(ext.SASL.Mechanism, "SCRAM-") {
+			return "", ext, fmt.Errorf("delegation token login requires a SCRAM SASL mechanism")
+		}
+		ext.SASL.TokenAuth = true
+	}
 
 	if v[fTLSCa] != "" || v[fTLSCert] != "" || v[fTLSKey] != "" || v[fTLSInsecure] == "true" {
 		ext.TLS = &appconfig.TLSConfig{
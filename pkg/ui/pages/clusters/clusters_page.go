@@ -1,7 +1,7 @@
 // Package clusters implements the cluster overview dashboard page (page ID
 // "clusters"). It renders a full-width table of every configured cluster,
-// health-summary counters, an offline-only filter, and per-cluster actions
-// (open / refresh / validate). Data comes from the background collector at
+// health-summary counters, an offline-only filter, per-cluster actions
+// (open / refresh / validate), and the selected cluster's KRaft quorum. Data comes from the background collector at
 // common.Collector; the page re-renders on cluster.ClusterStatsUpdatedMsg.
 //
 // The page is NOT self-registering: the router registers it under the ID
@@ -99,6 +99,7 @@ func columns() []table.Column {
 		{Title: "Name", Width: 22},
 		{Title: "Status", Width: 12},
 		{Title: "Version", Width: 10},
+		{Title: "Mode", Width: 10},
 		{Title: "Brokers", Width: 8},
 		{Title: "Online Partitions", Width: 18},
 		{Title: "Topics", Width: 8},
@@ -303,6 +304,9 @@ func (m *Model) header() string {
 		"Offline: " + s.Error.Render(fmt.Sprintf("%d", offline)),
 		"Initializing: " + s.Warning.Render(fmt.Sprintf("%d", initializing)),
 	}
+	if kraft, zk := coordinationCounts(m.clusters); kraft+zk > 0 {
+		parts = append(parts, m.common.Styles.Muted.Render(fmt.Sprintf("KRaft: %d  ZooKeeper: %d", kraft, zk)))
+	}
 	line := strings.Join(parts, "  ")
 	if m.offlineOnly {
 		line += "  " + s.Warning.Render("[offline only]")
@@ -310,14 +314,19 @@ func (m *Model) header() string {
 	return line
 }
 
-// detail renders the footer area: the selected offline cluster's last error and
-// any validation results.
+// detail renders the footer area: the selected cluster's last error (offline)
+// or controller quorum (KRaft), and any validation results.
 func (m *Model) detail() string {
 	var b strings.Builder
 	s := m.common.Styles.StatusStyle
 	if c, ok := m.selected(); ok && c.Status == api.ClusterOffline && c.LastError != "" {
 		b.WriteString(s.Error.Render("Error: " + c.LastError))
 		b.WriteString("\n")
+	} else if ok {
+		if q := m.quorum(c.Name); q != nil {
+			b.WriteString(m.common.Styles.Muted.Render(quorumSummary(*q)))
+			b.WriteString("\n")
+		}
 	}
 	if m.validating {
 		b.WriteString(s.Info.Render("Validating " + m.validationTarget + "…"))
@@ -348,7 +357,7 @@ func (m *Model) detail() string {
 // rebuildRows repopulates the table from the visible cluster set.
 func (m *Model) rebuildRows() {
 	if !m.loaded {
-		m.table.SetRows([]table.Row{{"Loading…", "", "", "", "", "", "", "", "", ""}})
+		m.table.SetRows([]table.Row{{"Loading…", "", "", "", "", "", "", "", "", "", ""}})
 		return
 	}
 	vis := m.visibleClusters()
@@ -372,6 +381,7 @@ func (m *Model) row(c api.ClusterOverview) table.Row {
 		name,
 		string(c.Status),
 		versionOr(c.Version),
+		modeLabel(c.CoordinationType),
 		fmt.Sprintf("%d", c.BrokerCount),
 		fmt.Sprintf("%d", c.OnlinePartitionCount),
 		fmt.Sprintf("%d", c.TopicCount),
@@ -382,6 +392,18 @@ func (m *Model) row(c api.ClusterOverview) table.Row {
 	}
 }
 
+// quorum returns the cached controller quorum for a cluster, if collected.
+func (m *Model) quorum(name string) *api.QuorumInfo {
+	if m.common == nil || m.common.Collector == nil {
+		return nil
+	}
+	st, err := m.common.Collector.GetStatistics(name)
+	if err != nil {
+		return nil
+	}
+	return st.Quorum
+}
+
 func (m *Model) isCurrent(name string) bool {
 	if m.common == nil || m.common.DataSource == nil {
 		return false
@@ -432,6 +454,45 @@ func statusCounts(clusters []api.ClusterOverview) (online, offline, initializing
 	return
 }
 
+func coordinationCounts(clusters []api.ClusterOverview) (kraft, zookeeper int) {
+	for _, c := range clusters {
+		switch c.CoordinationType {
+		case "kraft":
+			kraft++
+		case "zookeeper":
+			zookeeper++
+		}
+	}
+	return
+}
+
+// modeLabel renders a ClusterOverview.CoordinationType for the Mode column.
+func modeLabel(coordination string) string {
+	switch coordination {
+	case "kraft":
+		return "KRaft"
+	case "zookeeper":
+		return "ZooKeeper"
+	default:
+		return dash
+	}
+}
+
+// quorumSummary is the one-line quorum footer: leader, epoch, high watermark
+// and each voter's lag behind the leader.
+func quorumSummary(q api.QuorumInfo) string {
+	voters := make([]string, 0, len(q.Voters))
+	for _, v := range q.Voters {
+		voters = append(voters, fmt.Sprintf("%d (lag %d)", v.ReplicaID, v.Lag))
+	}
+	line := fmt.Sprintf("Quorum: leader %d · epoch %d · HWM %d · voters %s",
+		q.LeaderID, q.LeaderEpoch, q.HighWatermark, strings.Join(voters, ", "))
+	if n := len(q.Observers); n > 0 {
+		line += fmt.Sprintf(" · %d observer(s)", n)
+	}
+	return line
+}
+
 func versionOr(v string) string {
 	if v == "" {
 		return dash
@@ -30,7 +30,7 @@ func feed(m *Model, clusters []api.ClusterOverview) string {
 
 func sample() []api.ClusterOverview {
 	return []api.ClusterOverview{
-		{Name: "kafka-dev", Status: api.ClusterOnline, Version: "3.7.0", BrokerCount: 3,
+		{Name: "kafka-dev", Status: api.ClusterOnline, Version: "3.7.0", CoordinationType: "kraft", BrokerCount: 3,
 			OnlinePartitionCount: 12, TopicCount: 5, MessagesInPerSec: 42.5,
 			BytesInPerSec: 1536, BytesOutPerSec: 1048576},
 		{Name: "kafka-prod", Status: api.ClusterOnline, Version: "3.6.0", BrokerCount: 5,
@@ -43,7 +43,7 @@ func TestRenderColumnsAndNames(t *testing.T) {
 	m := newModel(t)
 	out := feed(m, sample())
 
-	for _, col := range []string{"Name", "Status", "Version", "Brokers",
+	for _, col := range []string{"Name", "Status", "Version", "Mode", "Brokers",
 		"Online Partitions", "Topics", "Msgs/s", "Bytes In/s", "Bytes Out/s", "Access"} {
 		assert.Contains(t, out, col, "missing column header %q", col)
 	}
@@ -135,6 +135,22 @@ func TestHeaderShowsCounters(t *testing.T) {
 	assert.Contains(t, out, "Online:")
 	assert.Contains(t, out, "Offline:")
 	assert.Contains(t, out, "Initializing:")
+	assert.Contains(t, out, "KRaft: 1  ZooKeeper: 0")
+}
+
+func TestModeColumn(t *testing.T) {
+	m := newModel(t)
+	row := m.row(sample()[0])
+	assert.Equal(t, "KRaft", row[3])
+	assert.Equal(t, dash, modeLabel(""))
+	assert.Equal(t, "ZooKeeper", modeLabel("zookeeper"))
+}
+
+func TestQuorumSummary(t *testing.T) {
+	q := api.QuorumInfo{LeaderID: 1, LeaderEpoch: 7, HighWatermark: 500,
+		Voters:    []api.QuorumReplica{{ReplicaID: 1}, {ReplicaID: 2, Lag: 3}},
+		Observers: []api.QuorumReplica{{ReplicaID: 4}}}
+	assert.Equal(t, "Quorum: leader 1 · epoch 7 · HWM 500 · voters 1 (lag 0), 2 (lag 3) · 1 observer(s)", quorumSummary(q))
 }
 
 func TestOfflineFilterToggle(t *testing.T) {
@@ -200,4 +216,12 @@ func TestWithRealCollector(t *testing.T) {
 	require.NotEmpty(t, m.clusters)
 	assert.Contains(t, out, "Name")
 	assert.Contains(t, out, "kafka-")
+
+	// The mock's kafka-dev is KRaft: selecting it shows its quorum.
+	for i, c := range m.clusters {
+		if c.Name == "kafka-dev" {
+			m.table.SetCursor(i)
+		}
+	}
+	assert.Contains(t, m.renderContent(160, 40), "Quorum: leader 1")
 }
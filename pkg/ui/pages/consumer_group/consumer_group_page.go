@@ -145,7 +145,7 @@ func (m *Model) GetTitle() string { return "Group " + m.groupID }
 func (m *Model) GetHelp() []key.Binding {
 	return []key.Binding{
 		m.keys.Expand, m.keys.Filter, m.keys.Sort, m.keys.Refresh, m.keys.AutoRefresh,
-		m.keys.GotoTopic, m.keys.Reset, m.keys.DeleteOff, m.keys.Delete, m.keys.Export, m.keys.Back,
+		m.keys.GotoTopic, m.keys.Reset, m.keys.DeleteOff, m.keys.Delete, m.keys.Export, m.keys.ExportOffs, m.keys.Back,
 	}
 }
 
@@ -196,6 +196,8 @@ func (m *Model) handle(msg tea.Msg) tea.Cmd {
 		return m.handleOffsetsReset(v)
 	case resetFormSubmitMsg:
 		return m.handleResetSubmit(v)
+	case resetPlannedMsg:
+		return m.handleResetPlanned(v)
 	case resetFormCancelMsg:
 		m.resetForm = nil
 		return nil
@@ -280,6 +282,8 @@ func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
 		return m.deleteGroup()
 	case key.Matches(msg, m.keys.Export):
 		return m.exportCSV()
+	case key.Matches(msg, m.keys.ExportOffs):
+		return m.exportOffsets()
 	}
 	return m.forwardToActive(msg)
 }
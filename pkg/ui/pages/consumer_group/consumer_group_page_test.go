@@ -3,6 +3,7 @@ package consumergroup
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/datasource/mock"
@@ -18,6 +19,7 @@ type spyDS struct {
 	*mock.KafkaDataSourceMock
 	deleteCalls int
 	resetCalls  int
+	lastReset   api.OffsetResetRequest
 }
 
 func (s *spyDS) DeleteConsumerGroup(id string) error {
@@ -27,6 +29,7 @@ func (s *spyDS) DeleteConsumerGroup(id string) error {
 
 func (s *spyDS) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
 	s.resetCalls++
+	s.lastReset = req
 	return s.KafkaDataSourceMock.ResetConsumerGroupOffsets(ctx, req)
 }
 
@@ -142,7 +145,50 @@ func TestResetForm_TimestampRequiresValue(t *testing.T) {
 	assert.NotEmpty(t, f.errMsg)
 }
 
-func TestResetForm_SubmitEmitsConfirmThenResets(t *testing.T) {
+func TestResetForm_ValueModes(t *testing.T) {
+	m := newPage(t, newSpy(), "inventory-sync")
+	loadInto(m)
+	m.openResetForm()
+	f := m.resetForm
+	f.selected[0] = true
+
+	selectMode := func(mode api.OffsetResetMode) {
+		f.focus = focusMode
+		for f.currentMode() != mode {
+			f.adjust(1)
+		}
+	}
+	submitReq := func() api.OffsetResetRequest {
+		t.Helper()
+		cmd := f.submit()
+		require.NotNil(t, cmd, f.errMsg)
+		return cmd().(resetFormSubmitMsg).req
+	}
+
+	selectMode(api.OffsetResetShiftBy)
+	assert.True(t, f.hasConditional())
+	f.valueInput.SetValue("0")
+	assert.Nil(t, f.submit(), "zero shift is rejected")
+	f.valueInput.SetValue("-25")
+	assert.Equal(t, int64(-25), submitReq().ShiftBy)
+
+	selectMode(api.OffsetResetDuration)
+	assert.Empty(t, f.valueInput.Value(), "switching modes clears the value")
+	f.valueInput.SetValue("90m")
+	assert.Equal(t, 90*time.Minute, submitReq().Duration)
+
+	selectMode(api.OffsetResetFromGroup)
+	f.valueInput.SetValue("inventory-sync")
+	assert.Nil(t, f.submit(), "copying from itself is rejected")
+	f.valueInput.SetValue("order-processor")
+	assert.Equal(t, "order-processor", submitReq().SourceGroupID)
+
+	selectMode(api.OffsetResetCurrent)
+	assert.False(t, f.hasConditional())
+	assert.Equal(t, api.OffsetResetCurrent, submitReq().Mode)
+}
+
+func TestResetForm_SubmitPreviewsThenResets(t *testing.T) {
 	spy := newSpy()
 	m := newPage(t, spy, "inventory-sync")
 	loadInto(m)
@@ -157,20 +203,49 @@ func TestResetForm_SubmitEmitsConfirmThenResets(t *testing.T) {
 	assert.Equal(t, api.OffsetResetEarliest, sub.req.Mode)
 	assert.Equal(t, []int32{0}, sub.req.Partitions)
 
-	// The model turns it into a ShowConfirmMsg (no datasource call yet).
-	confirmCmd := m.handle(sub)
+	// The model plans the reset first...
+	planCmd := m.handle(sub)
+	require.NotNil(t, planCmd)
+	planned, ok := planCmd().(resetPlannedMsg)
+	require.True(t, ok)
+	require.NoError(t, planned.err)
+	require.Len(t, planned.plan, 1)
+
+	// ...and shows the preview as the confirmation (no commit yet).
+	confirmCmd := m.handle(planned)
 	require.NotNil(t, confirmCmd)
 	confirm, ok := confirmCmd().(core.ShowConfirmMsg)
 	require.True(t, ok)
 	assert.True(t, confirm.Danger)
+	assert.Contains(t, confirm.Message, "LAG DELTA")
+	assert.Contains(t, confirm.Message, "Total lag: 50 → 60 (+10 records)")
 	assert.Equal(t, 0, spy.resetCalls)
 
-	// Confirming runs the reset exactly once.
+	// Confirming commits the previewed targets exactly once.
 	resMsg := confirm.OnConfirm()
 	res, ok := resMsg.(offsetsResetMsg)
 	require.True(t, ok)
 	assert.NoError(t, res.err)
 	assert.Equal(t, 1, spy.resetCalls)
+	assert.Equal(t, api.OffsetResetEarliest, spy.lastReset.Mode, "the commit keeps the submitted mode")
+	assert.Equal(t, map[int32]int64{0: 0}, spy.lastReset.Planned)
+	d, err := spy.GetConsumerGroupDetail("inventory-sync")
+	require.NoError(t, err)
+	for _, po := range d.TopicOffsets {
+		if po.Partition == 0 {
+			assert.Equal(t, int64(0), *po.CommittedOffset)
+		}
+	}
+}
+
+func TestFormatResetPlan_CapsRows(t *testing.T) {
+	var plan []api.PartitionResetPlan
+	for p := int32(0); p < resetPreviewRows+3; p++ {
+		plan = append(plan, api.PartitionResetPlan{Partition: p, Target: 0, LogEnd: 10})
+	}
+	out := formatResetPlan(plan)
+	assert.Contains(t, out, "… 3 more partition(s)")
+	assert.Contains(t, out, "Total lag: 0 → 150 (+150 records)")
 }
 
 func TestDeleteGroup_ConfirmThenDeletesOnce(t *testing.T) {
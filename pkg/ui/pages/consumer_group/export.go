@@ -1,6 +1,7 @@
 package consumergroup
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"os"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Benny93/kafui/pkg/ui/core"
+	"github.com/Benny93/kafui/pkg/ui/shared/groupoffsets"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -55,6 +57,30 @@ func (m *Model) exportCSV() tea.Cmd {
 	}
 }
 
+// exportOffsets writes the group's committed offsets, with the timestamp of
+// the record at each, as JSON for `kafui groups import-offsets` on another
+// cluster.
+func (m *Model) exportOffsets() tea.Cmd {
+	ds := m.common.DataSource
+	group := m.groupID
+	return func() tea.Msg {
+		exp, err := groupoffsets.Collect(context.Background(), ds, group)
+		if err != nil {
+			return core.NotifyError("Offsets export failed", err)()
+		}
+		data, err := exp.JSON()
+		if err != nil {
+			return core.NotifyError("Offsets export failed", err)()
+		}
+		filename := fmt.Sprintf("consumer-group-offsets-%s-%s.json", sanitize(group), time.Now().Format("20060102-150405"))
+		if err := os.WriteFile(filename, append(data, '\n'), 0o644); err != nil {
+			return core.NotifyError("Offsets export failed", err)()
+		}
+		abs, _ := filepath.Abs(filename)
+		return core.NotificationMsg{Severity: core.StatusInfo, Title: "Offsets exported", Message: abs}
+	}
+}
+
 // sanitize replaces path-hostile characters in a group id for the filename.
 func sanitize(s string) string {
 	out := make([]rune, 0, len(s))
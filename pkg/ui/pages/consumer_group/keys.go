@@ -14,6 +14,7 @@ type pageKeys struct {
 	DeleteOff   key.Binding
 	Reset       key.Binding
 	Export      key.Binding
+	ExportOffs  key.Binding
 	Retry       key.Binding
 	Back        key.Binding
 }
@@ -30,6 +31,7 @@ func defaultKeys() pageKeys {
 		DeleteOff:   key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete offsets")),
 		Reset:       key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "reset offsets")),
 		Export:      key.NewBinding(key.WithKeys("ctrl+e"), key.WithHelp("ctrl+e", "export CSV")),
+		ExportOffs:  key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "export offsets")),
 		Retry:       key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "retry")),
 		Back:        key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
 	}
@@ -22,6 +22,14 @@ const resetTimestampLayout = "2006-01-02 15:04:05"
 
 var resetModes = []api.OffsetResetMode{
 	api.OffsetResetEarliest, api.OffsetResetLatest, api.OffsetResetTimestamp, api.OffsetResetExplicit,
+	api.OffsetResetShiftBy, api.OffsetResetDuration, api.OffsetResetCurrent, api.OffsetResetFromGroup,
+}
+
+// valueModePrompts labels the single-value input of the modes that take one.
+var valueModePrompts = map[api.OffsetResetMode]struct{ label, placeholder string }{
+	api.OffsetResetShiftBy:   {"Shift by (records, negative rewinds): ", "-100"},
+	api.OffsetResetDuration:  {"Go back by (duration): ", "2h30m"},
+	api.OffsetResetFromGroup: {"Copy from group: ", "source group id"},
 }
 
 // resetFocus enumerates the reset form's focus positions.
@@ -31,7 +39,7 @@ const (
 	focusTopic resetFocus = iota
 	focusMode
 	focusPartitions
-	focusConditional // timestamp input (Timestamp) or per-partition offsets (Explicit)
+	focusConditional // timestamp, per-partition offsets (Explicit) or the value input
 	focusSubmit
 )
 
@@ -52,6 +60,7 @@ type resetForm struct {
 	selected map[int32]bool // partition -> selected (for the current topic)
 
 	tsInput      textinput.Model
+	valueInput   textinput.Model // shift-by, by-duration, copy-from-group
 	offsetInputs map[int32]textinput.Model
 
 	focus      resetFocus
@@ -91,6 +100,9 @@ func newResetForm(groupID string, detail api.ConsumerGroupDetail, styles *styles
 	ts.Placeholder = resetTimestampLayout
 	ts.Width = 30
 
+	value := textinput.New()
+	value.Width = 30
+
 	f := &resetForm{
 		groupID:      groupID,
 		styles:       styles,
@@ -98,6 +110,7 @@ func newResetForm(groupID string, detail api.ConsumerGroupDetail, styles *styles
 		partsByTopic: byTopic,
 		selected:     map[int32]bool{},
 		tsInput:      ts,
+		valueInput:   value,
 		offsetInputs: map[int32]textinput.Model{},
 	}
 	return f
@@ -130,7 +143,8 @@ func (f *resetForm) clearTopicState() {
 
 func (f *resetForm) hasConditional() bool {
 	m := f.currentMode()
-	return m == api.OffsetResetTimestamp || m == api.OffsetResetExplicit
+	_, value := valueModePrompts[m]
+	return m == api.OffsetResetTimestamp || m == api.OffsetResetExplicit || value
 }
 
 // Update handles a key message; returns a command and whether it was consumed.
@@ -207,6 +221,11 @@ func (f *resetForm) syncInputFocus() {
 	} else {
 		f.tsInput.Blur()
 	}
+	if _, ok := valueModePrompts[f.currentMode()]; ok && f.focus == focusConditional {
+		f.valueInput.Focus()
+	} else {
+		f.valueInput.Blur()
+	}
 }
 
 // adjust changes the topic or mode selector with left/right.
@@ -220,6 +239,11 @@ func (f *resetForm) adjust(delta int) tea.Cmd {
 		n := len(resetModes)
 		f.modeIdx = (f.modeIdx + delta + n) % n
 		f.errMsg = ""
+		// The value input is shared between modes; its meaning changes.
+		f.valueInput.SetValue("")
+		if prompt, ok := valueModePrompts[f.currentMode()]; ok {
+			f.valueInput.Placeholder = prompt.placeholder
+		}
 	}
 	return nil
 }
@@ -263,6 +287,11 @@ func (f *resetForm) updateConditionalInput(msg tea.Msg) tea.Cmd {
 		f.tsInput, cmd = f.tsInput.Update(msg)
 		return cmd
 	}
+	if _, ok := valueModePrompts[f.currentMode()]; ok {
+		var cmd tea.Cmd
+		f.valueInput, cmd = f.valueInput.Update(msg)
+		return cmd
+	}
 	// Explicit: edit the offset for the partition under the cursor.
 	sel := f.selectedPartitions()
 	if len(sel) == 0 {
@@ -311,6 +340,31 @@ func (f *resetForm) submit() tea.Cmd {
 			return nil
 		}
 		req.Timestamp = &t
+	case api.OffsetResetShiftBy:
+		n, err := strconv.ParseInt(strings.TrimSpace(f.valueInput.Value()), 10, 64)
+		if err != nil || n == 0 {
+			f.errMsg = "shift must be a non-zero number of records"
+			return nil
+		}
+		req.ShiftBy = n
+	case api.OffsetResetDuration:
+		d, err := time.ParseDuration(strings.TrimSpace(f.valueInput.Value()))
+		if err != nil || d <= 0 {
+			f.errMsg = "duration must be positive (e.g. 30m, 2h)"
+			return nil
+		}
+		req.Duration = d
+	case api.OffsetResetFromGroup:
+		src := strings.TrimSpace(f.valueInput.Value())
+		if src == "" {
+			f.errMsg = "source group is required"
+			return nil
+		}
+		if src == f.groupID {
+			f.errMsg = "source group must differ from this group"
+			return nil
+		}
+		req.SourceGroupID = src
 	case api.OffsetResetExplicit:
 		offsets := map[int32]int64{}
 		for _, p := range sel {
@@ -370,7 +424,10 @@ func (f *resetForm) View() string {
 	b.WriteString("\n")
 
 	if f.hasConditional() {
-		if f.currentMode() == api.OffsetResetTimestamp {
+		if prompt, ok := valueModePrompts[f.currentMode()]; ok {
+			b.WriteString(sel(focusConditional, prompt.label))
+			b.WriteString(f.valueInput.View())
+		} else if f.currentMode() == api.OffsetResetTimestamp {
 			b.WriteString(sel(focusConditional, "Timestamp ("+resetTimestampLayout+"): "))
 			b.WriteString(f.tsInput.View())
 		} else {
@@ -384,9 +441,9 @@ func (f *resetForm) View() string {
 		b.WriteString("\n")
 	}
 
-	submitLabel := "Submit"
+	submitLabel := "Preview reset"
 	if len(f.selectedPartitions()) == 0 {
-		submitLabel = "Submit (select a partition)"
+		submitLabel = "Preview reset (select a partition)"
 	}
 	b.WriteString(sel(focusSubmit, submitLabel))
 	b.WriteString("\n")
@@ -411,27 +468,94 @@ func (m *Model) openResetForm() tea.Cmd {
 	return nil
 }
 
-// handleResetSubmit shows a confirmation modal summarizing the reset, then runs
-// ResetConsumerGroupOffsets on confirm.
+// resetPreviewRows caps the partition rows of the confirmation preview; the
+// totals line always covers every partition.
+const resetPreviewRows = 12
+
+// handleResetSubmit plans the submitted reset without committing anything;
+// the plan comes back as resetPlannedMsg.
 func (m *Model) handleResetSubmit(v resetFormSubmitMsg) tea.Cmd {
 	ds := m.common.DataSource
 	req := v.req
-	summary := fmt.Sprintf("Reset %s offsets of %s-%v for group %q?",
-		req.Mode, req.Topic, req.Partitions, req.GroupID)
+	return func() tea.Msg {
+		plan, err := ds.PlanConsumerGroupOffsetReset(context.Background(), req)
+		return resetPlannedMsg{req: req, plan: plan, err: err}
+	}
+}
+
+// handleResetPlanned shows the per-partition preview in a confirmation modal.
+// On confirm exactly the previewed targets are committed, so a timestamp or
+// relative reset cannot drift from what the user approved.
+func (m *Model) handleResetPlanned(v resetPlannedMsg) tea.Cmd {
+	if v.req.GroupID != m.groupID {
+		return nil
+	}
+	if v.err != nil {
+		return m.handleOffsetsReset(offsetsResetMsg{groupID: v.req.GroupID, topic: v.req.Topic, err: v.err})
+	}
+	if len(v.plan) == 0 {
+		if m.resetForm != nil {
+			m.resetForm.SetError("nothing to reset for the selected partitions")
+		}
+		return nil
+	}
+	ds := m.common.DataSource
+	commit := v.req
+	commit.Partitions, commit.Planned = nil, make(map[int32]int64, len(v.plan))
+	for _, p := range v.plan {
+		commit.Partitions = append(commit.Partitions, p.Partition)
+		commit.Planned[p.Partition] = p.Target
+	}
+	message := fmt.Sprintf("Reset %s offsets of %s for group %q:\n\n%s",
+		v.req.Mode, v.req.Topic, v.req.GroupID, formatResetPlan(v.plan))
 	return func() tea.Msg {
 		return core.ShowConfirmMsg{
 			Title:        "Reset offsets",
-			Message:      summary,
+			Message:      message,
 			Danger:       true,
 			ConfirmLabel: "Reset",
 			OnConfirm: func() tea.Msg {
-				err := ds.ResetConsumerGroupOffsets(context.Background(), req)
-				return offsetsResetMsg{groupID: req.GroupID, topic: req.Topic, err: err}
+				err := ds.ResetConsumerGroupOffsets(context.Background(), commit)
+				return offsetsResetMsg{groupID: commit.GroupID, topic: commit.Topic, err: err}
 			},
 		}
 	}
 }
 
+// formatResetPlan renders the preview table plus a totals line with the lag
+// change, the number that tells "skip a few records" from "re-read 2 TB".
+func formatResetPlan(plan []api.PartitionResetPlan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-4s %12s %12s %12s %12s %12s\n", "P", "CURRENT", "TARGET", "LOG START", "LOG END", "LAG DELTA")
+	var before, after int64
+	for i, p := range plan {
+		if cur := p.CurrentLag(); cur != nil {
+			before += *cur
+		}
+		after += p.TargetLag()
+		if i == resetPreviewRows {
+			fmt.Fprintf(&b, "… %d more partition(s)\n", len(plan)-resetPreviewRows)
+		}
+		if i >= resetPreviewRows {
+			continue
+		}
+		current := "-"
+		if p.Current != nil {
+			current = strconv.FormatInt(*p.Current, 10)
+		}
+		fmt.Fprintf(&b, "%-4d %12s %12d %12d %12d %12s\n", p.Partition, current, p.Target, p.LogStart, p.LogEnd, signed(p.LagDelta()))
+	}
+	fmt.Fprintf(&b, "\nTotal lag: %d → %d (%s records)", before, after, signed(after-before))
+	return b.String()
+}
+
+func signed(n int64) string {
+	if n > 0 {
+		return "+" + strconv.FormatInt(n, 10)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
 // handleOffsetsReset surfaces precondition/validation errors inline in the form;
 // on success it closes the form, refreshes the detail, and notifies.
 func (m *Model) handleOffsetsReset(v offsetsResetMsg) tea.Cmd {
@@ -48,6 +48,14 @@ type (
 		req api.OffsetResetRequest
 	}
 
+	// resetPlannedMsg carries the preview of a submitted reset; it is shown as
+	// the confirmation step before anything is committed.
+	resetPlannedMsg struct {
+		req  api.OffsetResetRequest
+		plan []api.PartitionResetPlan
+		err  error
+	}
+
 	// resetFormCancelMsg is emitted when the reset form is cancelled.
 	resetFormCancelMsg struct{}
 )
@@ -3,6 +3,7 @@ package mainpage
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/ui/shared"
@@ -59,6 +60,10 @@ func (m *mockKafkaDataSource) ProduceMessage(ctx context.Context, topic string,
 	return nil
 }
 
+func (m *mockKafkaDataSource) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	return nil, nil
+}
+
 func (m *mockKafkaDataSource) GetMessageSchemaInfo(keySchemaID, valueSchemaID string) (*api.MessageSchemaInfo, error) {
 	args := m.Called(keySchemaID, valueSchemaID)
 	if args.Get(0) == nil {
@@ -96,10 +101,10 @@ func (m *mockKafkaDataSource) GetGlobalCompatibility() (api.CompatibilityLevel,
 func (m *mockKafkaDataSource) GetSubjectCompatibility(subject string) (api.CompatibilityLevel, bool, error) {
 	return "", false, nil
 }
-func (m *mockKafkaDataSource) RegisterSchema(subject, schemaText, schemaType string) (api.Schema, error) {
+func (m *mockKafkaDataSource) RegisterSchema(subject, schemaText, schemaType string, references []api.SchemaReference) (api.Schema, error) {
 	return api.Schema{}, nil
 }
-func (m *mockKafkaDataSource) CheckSchemaCompatibility(subject, schemaText, schemaType string) (bool, []string, error) {
+func (m *mockKafkaDataSource) CheckSchemaCompatibility(subject, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
 	return true, nil, nil
 }
 func (m *mockKafkaDataSource) DeleteSubject(subject string, permanent bool) ([]int, error) {
@@ -179,6 +184,104 @@ func (m *mockKafkaDataSource) AlterReplicaLogDir(brokerID int32, topic string, p
 	return nil
 }
 func (m *mockKafkaDataSource) GetBrokerMetrics(brokerID int32) (string, error) { return "", nil }
+func (m *mockKafkaDataSource) DescribeQuorum(ctx context.Context) (api.QuorumInfo, error) {
+	return api.QuorumInfo{}, nil
+}
+func (m *mockKafkaDataSource) PlanReassignment(ctx context.Context, req api.ReassignmentRequest) (api.ReassignmentPlan, error) {
+	return api.ReassignmentPlan{}, nil
+}
+func (m *mockKafkaDataSource) ExecuteReassignment(ctx context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	return nil
+}
+func (m *mockKafkaDataSource) ListReassignments(ctx context.Context) ([]api.PartitionReassignment, error) {
+	return nil, nil
+}
+func (m *mockKafkaDataSource) CancelReassignments(ctx context.Context, partitions []api.TopicPartition) error {
+	return nil
+}
+func (m *mockKafkaDataSource) ClearReplicationThrottles(ctx context.Context, topics []string) error {
+	return nil
+}
+func (m *mockKafkaDataSource) DescribeLeadership(ctx context.Context, topics []string) ([]api.PartitionLeadership, error) {
+	return nil, nil
+}
+func (m *mockKafkaDataSource) ElectLeaders(ctx context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	return nil, nil
+}
+
+func (m *mockKafkaDataSource) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	return nil, nil
+}
+
+func (m *mockKafkaDataSource) DescribeUserScramCredentials(users []string) ([]api.ScramUser, error) {
+	return nil, nil
+}
+
+func (m *mockKafkaDataSource) UpsertUserScramCredentials(upserts []api.ScramCredentialUpsert) error {
+	return nil
+}
+
+func (m *mockKafkaDataSource) DeleteUserScramCredentials(deletions []api.ScramCredentialDeletion) error {
+	return nil
+}
+
+func (m *mockKafkaDataSource) DescribeDelegationTokens(owners []string) ([]api.DelegationToken, error) {
+	return nil, nil
+}
+
+func (m *mockKafkaDataSource) CreateDelegationToken(req api.CreateDelegationTokenRequest) (api.DelegationToken, error) {
+	return api.DelegationToken{}, nil
+}
+
+func (m *mockKafkaDataSource) RenewDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (m *mockKafkaDataSource) ExpireDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+func (m *mockKafkaDataSource) GetSchemaVersion(subject string, version int) (api.SchemaVersion, error) {
+	return api.SchemaVersion{}, nil
+}
+func (m *mockKafkaDataSource) GetSchemaReferencedBy(subject string, version int) ([]api.SubjectVersion, error) {
+	return nil, nil
+}
+
+func (m *mockKafkaDataSource) GetDeletedSubjects() ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockKafkaDataSource) GetDeletedSchemaVersions(subject string) ([]api.SchemaVersion, error) {
+	return nil, nil
+}
+
+func (m *mockKafkaDataSource) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (m *mockKafkaDataSource) GetGlobalMode() (api.SchemaMode, error) {
+	return "", nil
+}
+
+func (m *mockKafkaDataSource) GetSubjectMode(subject string) (api.SchemaMode, bool, error) {
+	return "", false, nil
+}
+
+func (m *mockKafkaDataSource) SetGlobalMode(mode api.SchemaMode) error {
+	return nil
+}
+
+func (m *mockKafkaDataSource) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	return nil
+}
+
+func (m *mockKafkaDataSource) ImportSchema(subject string, version api.SchemaVersion) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (m *mockKafkaDataSource) CheckSchemaCompatibilityWithVersion(subject string, version int, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
+	return true, nil, nil
+}
 
 // Topic-administration + analysis stubs (TP-1..TP-11, TP-29/TP-30).
 func (m *mockKafkaDataSource) GetTopicConfig(topicName string) ([]api.TopicConfigEntry, error) {
@@ -260,6 +363,12 @@ func (m *mockKafkaDataSource) DeleteConsumerGroupOffsets(groupID, topic string)
 func (m *mockKafkaDataSource) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
 	return nil
 }
+func (m *mockKafkaDataSource) PlanConsumerGroupOffsetReset(ctx context.Context, req api.OffsetResetRequest) ([]api.PartitionResetPlan, error) {
+	return nil, nil
+}
+func (m *mockKafkaDataSource) GetRecordTimestamps(ctx context.Context, topic string, offsets map[int32]int64) (map[int32]time.Time, error) {
+	return nil, nil
+}
 
 // newTestContentProvider creates a KafuiContentProvider wired to the given mock.
 func newTestContentProvider(ds *mockKafkaDataSource) *KafuiContentProvider {
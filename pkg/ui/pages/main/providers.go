@@ -120,6 +120,20 @@ func createResourceTableColumns(resourceType ResourceType) []table.Column {
 			table.NewColumn(colQuotaIP, "IP", 16),
 			table.NewColumn(colQuotaValues, "Quotas", 44),
 		}
+	case ScramUserResourceType:
+		return []table.Column{
+			table.NewColumn(colScramUser, "User", 30),
+			table.NewColumn(colScramMechanisms, "Mechanisms", 32),
+			table.NewColumn(colScramIterations, "Iterations", 16),
+		}
+	case DelegationTokenResourceType:
+		return []table.Column{
+			table.NewColumn(colTokenID, "Token ID", 24),
+			table.NewColumn(colTokenOwner, "Owner", 20),
+			table.NewColumn(colTokenRenewers, "Renewers", 24),
+			table.NewColumn(colTokenExpires, "Expires", 26),
+			table.NewColumn(colTokenMax, "Max", 18),
+		}
 	case BrokerResourceType:
 		return []table.Column{
 			table.NewColumn(colBrokerID, "ID", 8).WithStyle(right),
@@ -256,6 +270,18 @@ func convertItemsToRowsWithSpinner(items []interface{}, searchQuery string, name
 			continue
 		}
 
+		// SCRAM users use a dedicated three-column layout.
+		if si, ok := scramItemFrom(item); ok {
+			rows = append(rows, table.NewRow(scramRowData(si)))
+			continue
+		}
+
+		// Delegation tokens use a dedicated five-column layout.
+		if ti, ok := tokenItemFrom(item); ok {
+			rows = append(rows, table.NewRow(tokenRowData(ti)))
+			continue
+		}
+
 		// Connectors use a dedicated eight-column layout with a coloured state cell.
 		if ci, sq, ok := connectorItemFrom(item); ok {
 			effQuery := sq
@@ -690,6 +716,20 @@ type KafuiContentProvider struct {
 	showQuotaForm   bool
 	quotaEditEntity *api.ClientQuotaEntity
 
+	// SCRAM credential overlay form. scramRotateUser names the fixed user in
+	// rotate mode and is empty in create mode.
+	scramForm       *form.Form
+	showScramForm   bool
+	scramRotateUser string
+
+	// Delegation-token overlay form. tokenRenewTarget is the token being
+	// renewed and nil in create mode. issuedToken is the just-created token
+	// whose HMAC is shown once; it is cleared when its panel closes.
+	tokenForm        *form.Form
+	showTokenForm    bool
+	tokenRenewTarget *api.DelegationToken
+	issuedToken      *api.DelegationToken
+
 	// Connector create overlay form (KC-17): connect-cluster / name / plugin /
 	// JSON config. connectForm is nil unless showConnectForm is true.
 	connectForm     *form.Form
@@ -808,6 +848,10 @@ func (k *KafuiContentProvider) RenderContent(width, height int) string {
 		f.SetDimensions(tableWidth, tableHeight)
 		return f.View()
 	}
+	// A just-created delegation token shows its HMAC once.
+	if k.issuedToken != nil {
+		return k.renderIssuedToken()
+	}
 
 	// Resource picker overlay takes over the content area (UI-8).
 	if k.resourcePickerMode {
@@ -946,6 +990,10 @@ func (k *KafuiContentProvider) renderEmpty() string {
 			msg = "No schemas found in the registry."
 		case QuotaResourceType:
 			msg = "No client quotas configured — quotas may be unsupported on this cluster or none are set."
+		case ScramUserResourceType:
+			msg = "No SCRAM users found — SCRAM may be disabled on this cluster or no credentials are set."
+		case DelegationTokenResourceType:
+			msg = "No delegation tokens found — press n to create one."
 		case ConnectorResourceType:
 			msg = "No connectors found."
 		case ConnectClusterResourceType:
@@ -970,6 +1018,9 @@ func (k *KafuiContentProvider) HandleContentUpdate(msg tea.Msg) tea.Cmd {
 			cmd, _ := f.Update(msg)
 			return cmd
 		}
+		if k.issuedToken != nil {
+			return k.handleIssuedTokenKey(msg)
+		}
 
 		// Resource picker mode takes precedence over everything else (UI-8).
 		if k.resourcePickerMode {
@@ -1161,6 +1212,26 @@ func (k *KafuiContentProvider) HandleContentUpdate(msg tea.Msg) tea.Cmd {
 					return k.deleteSelectedQuota()
 				}
 			}
+			if k.isScramUserResource() {
+				switch msg.String() {
+				case "n":
+					return k.openScramForm(false)
+				case "e":
+					return k.openScramForm(true)
+				case "ctrl+d":
+					return k.deleteSelectedScramUser()
+				}
+			}
+			if k.isDelegationTokenResource() {
+				switch msg.String() {
+				case "n":
+					return k.openTokenCreateForm()
+				case "r":
+					return k.openTokenRenewForm()
+				case "ctrl+d":
+					return k.expireSelectedToken()
+				}
+			}
 			if k.isConnectorResource() {
 				switch msg.String() {
 				case "n":
@@ -1253,6 +1324,10 @@ func (k *KafuiContentProvider) HandleContentUpdate(msg tea.Msg) tea.Cmd {
 			return k.handleACLSyncSubmit(msg.Values["path"])
 		case k.showQuotaForm:
 			return k.handleQuotaFormSubmit(msg.Values)
+		case k.showScramForm:
+			return k.handleScramFormSubmit(msg.Values)
+		case k.showTokenForm:
+			return k.handleTokenFormSubmit(msg.Values)
 		case k.showConnectForm:
 			return k.handleConnectorFormSubmit(msg.Values)
 		default:
@@ -1268,6 +1343,11 @@ func (k *KafuiContentProvider) HandleContentUpdate(msg tea.Msg) tea.Cmd {
 		k.aclSyncForm = nil
 		k.showQuotaForm = false
 		k.quotaForm = nil
+		k.showScramForm = false
+		k.scramForm = nil
+		k.showTokenForm = false
+		k.tokenForm = nil
+		k.tokenRenewTarget = nil
 		k.showConnectForm = false
 		k.connectForm = nil
 
@@ -1388,6 +1468,48 @@ func (k *KafuiContentProvider) HandleContentUpdate(msg tea.Msg) tea.Cmd {
 			k.loadCurrentResource(),
 		)
 
+	case scramAlteredMsg:
+		if msg.err != nil {
+			// Validation or broker error — keep the form open to correct input.
+			return core.NotifyError("SCRAM credential update failed", msg.err)
+		}
+		k.showScramForm = false
+		k.scramForm = nil
+		k.scramRotateUser = ""
+		return tea.Batch(
+			core.NewNotification(core.StatusSuccess, "SCRAM user "+msg.action, msg.user),
+			k.loadCurrentResource(),
+		)
+
+	case delegationTokenCreatedMsg:
+		if msg.err != nil {
+			return core.NotifyError("Create delegation token failed", msg.err)
+		}
+		k.showTokenForm = false
+		k.tokenForm = nil
+		token := msg.token
+		k.issuedToken = &token
+		return tea.Batch(
+			core.NewNotification(core.StatusSuccess, "Delegation token created", token.TokenID),
+			k.loadCurrentResource(),
+		)
+
+	case delegationTokenAlteredMsg:
+		if msg.err != nil {
+			return core.NotifyError("Delegation token update failed", msg.err)
+		}
+		k.showTokenForm = false
+		k.tokenForm = nil
+		k.tokenRenewTarget = nil
+		detail := msg.tokenID
+		if msg.action == "renewed" {
+			detail += " until " + formatTokenTime(msg.expiry)
+		}
+		return tea.Batch(
+			core.NewNotification(core.StatusSuccess, "Delegation token "+msg.action, detail),
+			k.loadCurrentResource(),
+		)
+
 	case ClearClipboardFeedbackMsg:
 		k.clipboardMsg = ""
 
@@ -1473,6 +1595,10 @@ func (k *KafuiContentProvider) breadcrumbCmd() tea.Cmd {
 			label = "Brokers"
 		case QuotaResourceType:
 			label = "Quotas"
+		case ScramUserResourceType:
+			label = "Users"
+		case DelegationTokenResourceType:
+			label = "Tokens"
 		}
 	}
 	items := []string{"Kafka UI", label}
@@ -1490,6 +1616,10 @@ func (k *KafuiContentProvider) activeOverlayForm() *form.Form {
 		return k.aclSyncForm
 	case k.showQuotaForm && k.quotaForm != nil:
 		return k.quotaForm
+	case k.showScramForm && k.scramForm != nil:
+		return k.scramForm
+	case k.showTokenForm && k.tokenForm != nil:
+		return k.tokenForm
 	case k.showConnectForm && k.connectForm != nil:
 		return k.connectForm
 	}
@@ -1499,7 +1629,7 @@ func (k *KafuiContentProvider) activeOverlayForm() *form.Form {
 // IsInputMode returns true when the search bar is active so that
 // ReusableApp suppresses app-level hotkeys that would otherwise steal keystrokes.
 func (k *KafuiContentProvider) IsInputMode() bool {
-	return k.searchMode || k.resourcePickerMode || k.showTopicForm || k.activeOverlayForm() != nil
+	return k.searchMode || k.resourcePickerMode || k.showTopicForm || k.activeOverlayForm() != nil || k.issuedToken != nil
 }
 
 // GetContentSize returns the estimated content size for scrollbar calculation
@@ -2292,6 +2422,10 @@ func (k *KafuiContentProvider) parseResourceType(name string) ResourceType {
 		return BrokerResourceType
 	case "quotas", "quota":
 		return QuotaResourceType
+	case "users", "user", "scram-users":
+		return ScramUserResourceType
+	case "tokens", "token", "delegation-tokens":
+		return DelegationTokenResourceType
 	case "connectors", "connector":
 		return ConnectorResourceType
 	case "connect", "connect-clusters", "connect-cluster", "connects":
@@ -2364,6 +2498,8 @@ func (k *KafuiContentProvider) availableResourceChoices() []resourceChoice {
 		{"contexts", ContextResourceType},
 		{"brokers", BrokerResourceType},
 		{"quotas", QuotaResourceType},
+		{"users", ScramUserResourceType},
+		{"tokens", DelegationTokenResourceType},
 		{"schemas", SchemaResourceType},
 		{"acls", ACLResourceType},
 		{"connect-clusters", ConnectClusterResourceType},
@@ -4,6 +4,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/ui/shared"
@@ -30,6 +31,8 @@ func NewResourceManager(dataSource api.KafkaDataSource) *ResourceManager {
 	rm.resources[ACLResourceType] = NewACLResource(dataSource)
 	rm.resources[BrokerResourceType] = NewBrokerResource(dataSource)
 	rm.resources[QuotaResourceType] = NewQuotaResource(dataSource)
+	rm.resources[ScramUserResourceType] = NewScramUserResource(dataSource)
+	rm.resources[DelegationTokenResourceType] = NewDelegationTokenResource(dataSource)
 	// Connect resources are always registered; visibility (sidebar + resource
 	// cycle + :connectors) is gated on api.CapKafkaConnect, mirroring how the
 	// schema/ACL resources are registered here and gated in the sidebar.
@@ -402,6 +405,20 @@ func (sri *SchemaResourceItem) SchemaID() int         { return sri.schemaID }
 func (sri *SchemaResourceItem) SchemaType() string    { return sri.schemaType }
 func (sri *SchemaResourceItem) Compatibility() string { return sri.compatibility }
 
+// NewSchemaResourceItem builds an item for a known subject version, e.g. to
+// open a referenced subject from another page. Version 0 means latest.
+func NewSchemaResourceItem(s api.Schema) *SchemaResourceItem {
+	return &SchemaResourceItem{
+		id:            s.Subject,
+		subject:       s.Subject,
+		version:       s.Version,
+		schemaID:      s.ID,
+		schemaType:    s.SchemaType,
+		compatibility: s.Compatibility,
+		detailsLoaded: s.Version > 0,
+	}
+}
+
 // ContextResource represents Kafka contexts
 type ContextResource struct {
 	BaseResource
@@ -809,3 +826,154 @@ func (q *QuotaResourceItem) GetDetails() map[string]string {
 		"Quotas":   formatQuotaValues(q.entry.Quotas),
 	}
 }
+
+// ScramUserResource represents the cluster's SASL/SCRAM users.
+type ScramUserResource struct {
+	BaseResource
+}
+
+// NewScramUserResource creates a new SCRAM-user resource.
+func NewScramUserResource(dataSource api.KafkaDataSource) *ScramUserResource {
+	return &ScramUserResource{
+		BaseResource: BaseResource{
+			resourceType: ScramUserResourceType,
+			name:         "Users",
+			dataSource:   dataSource,
+		},
+	}
+}
+
+// GetData fetches every SCRAM user (ordered by name by the datasource).
+func (sr *ScramUserResource) GetData() ([]ResourceItem, error) {
+	users, err := sr.dataSource.DescribeUserScramCredentials(nil)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ResourceItem, 0, len(users))
+	for _, u := range users {
+		items = append(items, &ScramUserResourceItem{user: u})
+	}
+	return items, nil
+}
+
+// ScramUserResourceItem represents a single SCRAM user.
+type ScramUserResourceItem struct {
+	user api.ScramUser
+}
+
+// User returns the underlying SCRAM user.
+func (s *ScramUserResourceItem) User() api.ScramUser { return s.user }
+
+// GetID returns the user name.
+func (s *ScramUserResourceItem) GetID() string { return s.user.Name }
+
+// scramColumns renders the mechanisms and their iteration counts as parallel
+// comma-lists.
+func (s *ScramUserResourceItem) scramColumns() (mechanisms, iterations string) {
+	mechs := make([]string, 0, len(s.user.Credentials))
+	iters := make([]string, 0, len(s.user.Credentials))
+	for _, c := range s.user.Credentials {
+		mechs = append(mechs, string(c.Mechanism))
+		iters = append(iters, strconv.Itoa(int(c.Iterations)))
+	}
+	return strings.Join(mechs, ", "), strings.Join(iters, ", ")
+}
+
+// GetValues returns column values: user, mechanisms, iterations.
+func (s *ScramUserResourceItem) GetValues() []string {
+	mechs, iters := s.scramColumns()
+	return []string{s.user.Name, mechs, iters}
+}
+
+// GetDetails returns detail fields for this user.
+func (s *ScramUserResourceItem) GetDetails() map[string]string {
+	mechs, iters := s.scramColumns()
+	return map[string]string{
+		"User":       s.user.Name,
+		"Mechanisms": mechs,
+		"Iterations": iters,
+	}
+}
+
+// DelegationTokenResource represents the cluster's delegation tokens.
+type DelegationTokenResource struct {
+	BaseResource
+}
+
+// NewDelegationTokenResource creates a new delegation-token resource.
+func NewDelegationTokenResource(dataSource api.KafkaDataSource) *DelegationTokenResource {
+	return &DelegationTokenResource{
+		BaseResource: BaseResource{
+			resourceType: DelegationTokenResourceType,
+			name:         "Tokens",
+			dataSource:   dataSource,
+		},
+	}
+}
+
+// GetData fetches every token the connected principal may describe (ordered
+// by expiry by the datasource).
+func (dr *DelegationTokenResource) GetData() ([]ResourceItem, error) {
+	tokens, err := dr.dataSource.DescribeDelegationTokens(nil)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ResourceItem, 0, len(tokens))
+	for _, t := range tokens {
+		items = append(items, &DelegationTokenResourceItem{token: t})
+	}
+	return items, nil
+}
+
+// DelegationTokenResourceItem represents a single delegation token. The HMAC
+// is kept for renew/expire but never rendered.
+type DelegationTokenResourceItem struct {
+	token api.DelegationToken
+}
+
+// Token returns the underlying delegation token.
+func (d *DelegationTokenResourceItem) Token() api.DelegationToken { return d.token }
+
+// GetID returns the token ID.
+func (d *DelegationTokenResourceItem) GetID() string { return d.token.TokenID }
+
+// tokenTimeLayout renders token timestamps in local time.
+const tokenTimeLayout = "2006-01-02 15:04"
+
+func formatTokenTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(tokenTimeLayout)
+}
+
+// expiresColumn renders the expiry, marking tokens that have already expired.
+func (d *DelegationTokenResourceItem) expiresColumn() string {
+	if d.token.Expired(time.Now()) {
+		return formatTokenTime(d.token.ExpiryTime) + " (expired)"
+	}
+	return formatTokenTime(d.token.ExpiryTime)
+}
+
+// GetValues returns column values: token ID, owner, renewers, expires, max.
+func (d *DelegationTokenResourceItem) GetValues() []string {
+	return []string{
+		d.token.TokenID,
+		d.token.Owner,
+		strings.Join(d.token.Renewers, ", "),
+		d.expiresColumn(),
+		formatTokenTime(d.token.MaxTime),
+	}
+}
+
+// GetDetails returns detail fields for this token.
+func (d *DelegationTokenResourceItem) GetDetails() map[string]string {
+	return map[string]string{
+		"TokenID":  d.token.TokenID,
+		"Owner":    d.token.Owner,
+		"Renewers": strings.Join(d.token.Renewers, ", "),
+		"Issued":   formatTokenTime(d.token.IssueTime),
+		"Expires":  d.expiresColumn(),
+		"MaxTime":  formatTokenTime(d.token.MaxTime),
+	}
+}
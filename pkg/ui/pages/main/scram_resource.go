@@ -0,0 +1,153 @@
+package mainpage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/components/form"
+	"github.com/Benny93/kafui/pkg/ui/core"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/evertras/bubble-table/table"
+)
+
+// SCRAM-user column keys.
+const (
+	colScramUser       = "scram_user"
+	colScramMechanisms = "scram_mechanisms"
+	colScramIterations = "scram_iterations"
+)
+
+// isScramUserResource reports whether the users resource is currently active.
+func (k *KafuiContentProvider) isScramUserResource() bool {
+	return k.currentResource != nil && k.currentResource.GetType() == ScramUserResourceType
+}
+
+// scramItemFrom unwraps a *ScramUserResourceItem from the list-item wrappers.
+func scramItemFrom(item interface{}) (*ScramUserResourceItem, bool) {
+	switch v := item.(type) {
+	case shared.ResourceListItem:
+		si, ok := v.ResourceItem.(*ScramUserResourceItem)
+		return si, ok
+	case shared.HighlightedResourceListItem:
+		si, ok := v.ResourceItem.(*ScramUserResourceItem)
+		return si, ok
+	case *ScramUserResourceItem:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// scramRowData builds a bubble-table row for a SCRAM user.
+func scramRowData(s *ScramUserResourceItem) table.RowData {
+	vals := s.GetValues()
+	return table.RowData{
+		colScramUser:       vals[0],
+		colScramMechanisms: vals[1],
+		colScramIterations: vals[2],
+	}
+}
+
+// --- create / rotate / delete ---
+//
+// Create and rotate share one form and both upsert a single mechanism. In
+// rotate mode the user is fixed (scramRotateUser) and the form is pre-filled
+// with the user's first mechanism and its iteration count. Delete removes every
+// credential of the highlighted user, which removes the user itself.
+
+// openScramForm opens the credential form. With rotate set it targets the
+// highlighted user; otherwise it starts blank.
+func (k *KafuiContentProvider) openScramForm(rotate bool) tea.Cmd {
+	mechanisms := make([]string, 0, len(api.ScramMechanisms))
+	for _, m := range api.ScramMechanisms {
+		mechanisms = append(mechanisms, string(m))
+	}
+	iterations := strconv.Itoa(int(api.ScramDefaultIterations))
+	mechanism := string(api.ScramSHA512)
+	k.scramRotateUser = ""
+
+	fields := []form.Field{}
+	if rotate {
+		si, ok := scramItemFrom(k.GetSelectedResourceItem())
+		if !ok {
+			return nil
+		}
+		user := si.User()
+		k.scramRotateUser = user.Name
+		if len(user.Credentials) > 0 {
+			mechanism = string(user.Credentials[0].Mechanism)
+			iterations = strconv.Itoa(int(user.Credentials[0].Iterations))
+		}
+	} else {
+		fields = append(fields, form.Field{Name: "user", Label: "User", Type: form.Text, Required: true})
+	}
+	fields = append(fields,
+		form.Field{Name: "mechanism", Label: "Mechanism", Type: form.Select, Options: mechanisms, Default: mechanism},
+		form.Field{Name: "iterations", Label: "Iterations (4096-16384)", Type: form.Numeric, Required: true, Default: iterations},
+		form.Field{Name: "password", Label: "Password", Type: form.Text, Required: true, Masked: true},
+	)
+	k.scramForm = form.New(fields)
+	k.showScramForm = true
+	return k.scramForm.Focus()
+}
+
+// handleScramFormSubmit validates the credential and upserts it. Validation
+// errors come back as a scramAlteredMsg so the form stays open.
+func (k *KafuiContentProvider) handleScramFormSubmit(v map[string]string) tea.Cmd {
+	user := strings.TrimSpace(v["user"])
+	action := "created"
+	if k.scramRotateUser != "" {
+		user = k.scramRotateUser
+		action = "rotated"
+	}
+	iterations, err := strconv.ParseInt(strings.TrimSpace(v["iterations"]), 10, 32)
+	if err != nil {
+		err = api.ScramValidationError{Field: "iterations", Reason: "must be a whole number"}
+		return func() tea.Msg { return scramAlteredMsg{user: user, err: err} }
+	}
+	upsert := api.ScramCredentialUpsert{
+		User:       user,
+		Mechanism:  api.ScramMechanism(v["mechanism"]),
+		Iterations: int32(iterations),
+		Password:   v["password"],
+	}
+	if err := api.ValidateScramUpsert(upsert); err != nil {
+		return func() tea.Msg { return scramAlteredMsg{user: user, err: err} }
+	}
+
+	ds := k.dataSource
+	return func() tea.Msg {
+		err := ds.UpsertUserScramCredentials([]api.ScramCredentialUpsert{upsert})
+		return scramAlteredMsg{user: user, action: action, err: err}
+	}
+}
+
+// deleteSelectedScramUser deletes every credential of the highlighted user
+// behind a confirmation modal.
+func (k *KafuiContentProvider) deleteSelectedScramUser() tea.Cmd {
+	si, ok := scramItemFrom(k.GetSelectedResourceItem())
+	if !ok {
+		return nil
+	}
+	user := si.User()
+	deletions := make([]api.ScramCredentialDeletion, 0, len(user.Credentials))
+	for _, c := range user.Credentials {
+		deletions = append(deletions, api.ScramCredentialDeletion{User: user.Name, Mechanism: c.Mechanism})
+	}
+	mechs, _ := si.scramColumns()
+	ds := k.dataSource
+	return func() tea.Msg {
+		return core.ShowConfirmMsg{
+			Title:        "Delete SCRAM user",
+			Message:      fmt.Sprintf("Delete the %s credentials of %q? Clients using them can no longer authenticate.", mechs, user.Name),
+			Danger:       true,
+			ConfirmLabel: "Delete",
+			OnConfirm: func() tea.Msg {
+				return scramAlteredMsg{user: user.Name, action: "deleted", err: ds.DeleteUserScramCredentials(deletions)}
+			},
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package mainpage
+
+import (
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"github.com/Benny93/kafui/pkg/ui/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScramProvider(t *testing.T) (*KafuiContentProvider, *mock.KafkaDataSourceMock) {
+	t.Helper()
+	ds := &mock.KafkaDataSourceMock{}
+	ds.Init("")
+	k := NewKafuiContentProvider(ds)
+	k.switchResource(SwitchResourceMsg(ScramUserResourceType))
+	loadACLs(t, k) // loads whichever resource is current
+	return k, ds
+}
+
+func TestScramUserResourceItem_GetValues(t *testing.T) {
+	item := &ScramUserResourceItem{user: api.ScramUser{Name: "alice", Credentials: []api.ScramCredentialInfo{
+		{Mechanism: api.ScramSHA256, Iterations: 4096},
+		{Mechanism: api.ScramSHA512, Iterations: 8192},
+	}}}
+	assert.Equal(t, []string{"alice", "SCRAM-SHA-256, SCRAM-SHA-512", "4096, 8192"}, item.GetValues())
+}
+
+func TestScramForm_Create_CallsUpsert(t *testing.T) {
+	k, ds := newScramProvider(t)
+	k.openScramForm(false)
+	require.True(t, k.showScramForm)
+
+	res, ok := k.handleScramFormSubmit(map[string]string{
+		"user": "carol", "mechanism": "SCRAM-SHA-512", "iterations": "8192", "password": "pw",
+	})().(scramAlteredMsg)
+	require.True(t, ok)
+	require.NoError(t, res.err)
+	assert.Equal(t, "created", res.action)
+
+	users, _ := ds.DescribeUserScramCredentials([]string{"carol"})
+	require.Len(t, users, 1)
+	assert.Equal(t, []api.ScramCredentialInfo{{Mechanism: api.ScramSHA512, Iterations: 8192}}, users[0].Credentials)
+
+	k.HandleContentUpdate(res)
+	assert.False(t, k.showScramForm, "success closes the form")
+}
+
+func TestScramForm_RotateTargetsHighlightedUser(t *testing.T) {
+	k, ds := newScramProvider(t)
+	selected, ok := scramItemFrom(k.GetSelectedResourceItem())
+	require.True(t, ok)
+	k.openScramForm(true)
+	assert.Equal(t, selected.GetID(), k.scramRotateUser)
+
+	// A user typed into the values is ignored in rotate mode.
+	res := k.handleScramFormSubmit(map[string]string{
+		"user": "someone-else", "mechanism": "SCRAM-SHA-256", "iterations": "16384", "password": "new",
+	})().(scramAlteredMsg)
+	require.NoError(t, res.err)
+	assert.Equal(t, "rotated", res.action)
+	assert.Equal(t, selected.GetID(), res.user)
+
+	users, _ := ds.DescribeUserScramCredentials([]string{selected.GetID()})
+	require.Len(t, users, 1)
+	assert.Contains(t, users[0].Credentials, api.ScramCredentialInfo{Mechanism: api.ScramSHA256, Iterations: 16384})
+}
+
+func TestScramForm_InvalidIterationsKeepsFormOpen(t *testing.T) {
+	k, _ := newScramProvider(t)
+	k.openScramForm(false)
+	res := k.handleScramFormSubmit(map[string]string{
+		"user": "carol", "mechanism": "SCRAM-SHA-256", "iterations": "100", "password": "pw",
+	})().(scramAlteredMsg)
+	var ve api.ScramValidationError
+	require.ErrorAs(t, res.err, &ve)
+	assert.Equal(t, "iterations", ve.Field)
+
+	k.HandleContentUpdate(res)
+	assert.True(t, k.showScramForm)
+}
+
+func TestDeleteScramUser_ConfirmThenDelete(t *testing.T) {
+	k, ds := newScramProvider(t)
+	selected, ok := scramItemFrom(k.GetSelectedResourceItem())
+	require.True(t, ok)
+
+	confirm, ok := k.deleteSelectedScramUser()().(core.ShowConfirmMsg)
+	require.True(t, ok, "delete must go through a confirmation")
+	assert.True(t, confirm.Danger)
+
+	res, ok := confirm.OnConfirm().(scramAlteredMsg)
+	require.True(t, ok)
+	require.NoError(t, res.err)
+	users, _ := ds.DescribeUserScramCredentials([]string{selected.GetID()})
+	assert.Empty(t, users, "deleting every credential removes the user")
+}
+
+func TestParseResourceType_Users(t *testing.T) {
+	k, _ := newScramProvider(t)
+	assert.Equal(t, ScramUserResourceType, k.parseResourceType("users"))
+	assert.Equal(t, ScramUserResourceType, k.parseResourceType("scram-users"))
+}
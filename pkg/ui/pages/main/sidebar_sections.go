@@ -91,6 +91,8 @@ func (r *ResourcesSection) RenderItems(maxItems, width int) []providers.SidebarI
 		{"ACLs", ACLResourceType, "🔒"},
 		{"Brokers", BrokerResourceType, "🖥️"},
 		{"Quotas", QuotaResourceType, "📊"},
+		{"Users", ScramUserResourceType, "🔑"},
+		{"Tokens", DelegationTokenResourceType, "🎫"},
 		{"Connect Clusters", ConnectClusterResourceType, "🔌"},
 		{"Connectors", ConnectorResourceType, "🔗"},
 	}
@@ -131,7 +133,7 @@ func (r *ResourcesSection) HandleSectionUpdate(msg tea.Msg) tea.Cmd {
 		r.currentResource = msg.ResourceType
 	case tea.MouseMsg:
 		// Check if any resource sidebar item was clicked.
-		for _, rt := range []ResourceType{TopicResourceType, ConsumerGroupResourceType, SchemaResourceType, ContextResourceType, ACLResourceType, BrokerResourceType, QuotaResourceType, ConnectClusterResourceType, ConnectorResourceType} {
+		for _, rt := range []ResourceType{TopicResourceType, ConsumerGroupResourceType, SchemaResourceType, ContextResourceType, ACLResourceType, BrokerResourceType, QuotaResourceType, ScramUserResourceType, DelegationTokenResourceType, ConnectClusterResourceType, ConnectorResourceType} {
 			if r.enabled(rt) && zone.Get(sidebarZoneID(rt)).InBounds(msg) {
 				return func() tea.Msg { return SwitchResourceMsg(rt) }
 			}
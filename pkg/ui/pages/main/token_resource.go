@@ -0,0 +1,217 @@
+package mainpage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/components/form"
+	"github.com/Benny93/kafui/pkg/ui/core"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/evertras/bubble-table/table"
+)
+
+// Delegation-token column keys.
+const (
+	colTokenID       = "token_id"
+	colTokenOwner    = "token_owner"
+	colTokenRenewers = "token_renewers"
+	colTokenExpires  = "token_expires"
+	colTokenMax      = "token_max"
+)
+
+// defaultTokenRenewPeriod pre-fills the renew form.
+const defaultTokenRenewPeriod = "24h"
+
+// copyToClipboard writes the issued token's HMAC; tests replace it.
+var copyToClipboard = clipboard.WriteAll
+
+// isDelegationTokenResource reports whether the tokens resource is currently
+// active.
+func (k *KafuiContentProvider) isDelegationTokenResource() bool {
+	return k.currentResource != nil && k.currentResource.GetType() == DelegationTokenResourceType
+}
+
+// tokenItemFrom unwraps a *DelegationTokenResourceItem from the list-item
+// wrappers.
+func tokenItemFrom(item interface{}) (*DelegationTokenResourceItem, bool) {
+	switch v := item.(type) {
+	case shared.ResourceListItem:
+		ti, ok := v.ResourceItem.(*DelegationTokenResourceItem)
+		return ti, ok
+	case shared.HighlightedResourceListItem:
+		ti, ok := v.ResourceItem.(*DelegationTokenResourceItem)
+		return ti, ok
+	case *DelegationTokenResourceItem:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// tokenRowData builds a bubble-table row for a delegation token.
+func tokenRowData(d *DelegationTokenResourceItem) table.RowData {
+	vals := d.GetValues()
+	return table.RowData{
+		colTokenID:       vals[0],
+		colTokenOwner:    vals[1],
+		colTokenRenewers: vals[2],
+		colTokenExpires:  vals[3],
+		colTokenMax:      vals[4],
+	}
+}
+
+// --- create / renew / expire ---
+//
+// Create and renew share one form slot. In renew mode tokenRenewTarget holds
+// the highlighted token (its HMAC identifies it to the broker); in create mode
+// it is nil. A created token's HMAC is shown exactly once, in the issued-token
+// panel, and is never rendered from the list.
+
+// openTokenCreateForm opens a blank create form.
+func (k *KafuiContentProvider) openTokenCreateForm() tea.Cmd {
+	k.tokenRenewTarget = nil
+	k.tokenForm = form.New([]form.Field{
+		{Name: "renewers", Label: "Renewers (comma-separated, e.g. User:scheduler)", Type: form.Text},
+		{Name: "maxLifetime", Label: "Max Lifetime (e.g. 72h; blank = broker default)", Type: form.Text},
+	})
+	k.showTokenForm = true
+	return k.tokenForm.Focus()
+}
+
+// openTokenRenewForm opens the renew form for the highlighted token.
+func (k *KafuiContentProvider) openTokenRenewForm() tea.Cmd {
+	ti, ok := tokenItemFrom(k.GetSelectedResourceItem())
+	if !ok {
+		return nil
+	}
+	token := ti.Token()
+	k.tokenRenewTarget = &token
+	k.tokenForm = form.New([]form.Field{
+		{Name: "period", Label: "Renew For (e.g. 24h; capped at the token's max time)", Type: form.Text, Required: true, Default: defaultTokenRenewPeriod},
+	})
+	k.showTokenForm = true
+	return k.tokenForm.Focus()
+}
+
+// handleTokenFormSubmit dispatches to create or renew. Validation errors come
+// back as result messages so the form stays open.
+func (k *KafuiContentProvider) handleTokenFormSubmit(v map[string]string) tea.Cmd {
+	if k.tokenRenewTarget != nil {
+		return k.handleTokenRenewSubmit(*k.tokenRenewTarget, v)
+	}
+	maxLifetime, err := parseTokenDuration("max lifetime", v["maxLifetime"])
+	if err != nil {
+		return func() tea.Msg { return delegationTokenCreatedMsg{err: err} }
+	}
+	req := api.CreateDelegationTokenRequest{MaxLifetime: maxLifetime}
+	for _, r := range strings.Split(v["renewers"], ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			req.Renewers = append(req.Renewers, r)
+		}
+	}
+	if err := api.ValidateCreateDelegationToken(req); err != nil {
+		return func() tea.Msg { return delegationTokenCreatedMsg{err: err} }
+	}
+
+	ds := k.dataSource
+	return func() tea.Msg {
+		token, err := ds.CreateDelegationToken(req)
+		return delegationTokenCreatedMsg{token: token, err: err}
+	}
+}
+
+func (k *KafuiContentProvider) handleTokenRenewSubmit(token api.DelegationToken, v map[string]string) tea.Cmd {
+	period, err := parseTokenDuration("renew period", v["period"])
+	if err == nil && period == 0 {
+		err = api.InvalidDelegationTokenError{Reason: "renew period is required"}
+	}
+	if err != nil {
+		return func() tea.Msg { return delegationTokenAlteredMsg{tokenID: token.TokenID, err: err} }
+	}
+	ds := k.dataSource
+	return func() tea.Msg {
+		expiry, err := ds.RenewDelegationToken(token, period)
+		return delegationTokenAlteredMsg{tokenID: token.TokenID, action: "renewed", expiry: expiry, err: err}
+	}
+}
+
+// parseTokenDuration parses a Go duration; blank is zero.
+func parseTokenDuration(field, s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, api.InvalidDelegationTokenError{Reason: field + " must be a positive duration such as 24h"}
+	}
+	return d, nil
+}
+
+// expireSelectedToken expires the highlighted token immediately behind a
+// confirmation modal.
+func (k *KafuiContentProvider) expireSelectedToken() tea.Cmd {
+	ti, ok := tokenItemFrom(k.GetSelectedResourceItem())
+	if !ok {
+		return nil
+	}
+	token := ti.Token()
+	ds := k.dataSource
+	return func() tea.Msg {
+		return core.ShowConfirmMsg{
+			Title:        "Expire delegation token",
+			Message:      fmt.Sprintf("Expire token %q owned by %s now? Clients using it can no longer authenticate.", token.TokenID, token.Owner),
+			Danger:       true,
+			ConfirmLabel: "Expire",
+			OnConfirm: func() tea.Msg {
+				expiry, err := ds.ExpireDelegationToken(token, 0)
+				return delegationTokenAlteredMsg{tokenID: token.TokenID, action: "expired", expiry: expiry, err: err}
+			},
+		}
+	}
+}
+
+// --- issued-token panel ---
+
+// handleIssuedTokenKey handles keys while the issued-token panel is open: c
+// copies the HMAC, esc/enter closes the panel for good.
+func (k *KafuiContentProvider) handleIssuedTokenKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "c":
+		if err := copyToClipboard(k.issuedToken.HMACBase64()); err != nil {
+			shared.Log.Error("clipboard copy failed", "err", err)
+			k.clipboardMsg = "⚠ Copy failed"
+		} else {
+			k.clipboardMsg = "📋 HMAC copied!"
+		}
+		return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+			return ClearClipboardFeedbackMsg{}
+		})
+	case "esc", "escape", "enter":
+		k.issuedToken = nil
+		k.clipboardMsg = ""
+	}
+	return nil
+}
+
+// renderIssuedToken renders the one-time view of a created token's secret.
+func (k *KafuiContentProvider) renderIssuedToken() string {
+	t := k.issuedToken
+	var b strings.Builder
+	b.WriteString(k.styles.Header.Render("Delegation token created") + "\n\n")
+	fmt.Fprintf(&b, "Token ID (SCRAM username):  %s\n", t.TokenID)
+	fmt.Fprintf(&b, "HMAC (SCRAM password):      %s\n", t.HMACBase64())
+	fmt.Fprintf(&b, "Owner:                      %s\n", t.Owner)
+	fmt.Fprintf(&b, "Expires:                    %s\n", formatTokenTime(t.ExpiryTime))
+	fmt.Fprintf(&b, "Max lifetime until:         %s\n", formatTokenTime(t.MaxTime))
+	b.WriteString("\n" + k.styles.Error.Render("The HMAC is shown only once. Copy it now."))
+	if k.clipboardMsg != "" {
+		b.WriteString("  " + k.clipboardMsg)
+	}
+	b.WriteString("\n\n" + k.styles.SearchStyle.Help.Render("c copy HMAC • Enter/Esc close"))
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package mainpage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"github.com/Benny93/kafui/pkg/ui/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenProvider(t *testing.T) (*KafuiContentProvider, *mock.KafkaDataSourceMock) {
+	t.Helper()
+	ds := &mock.KafkaDataSourceMock{}
+	ds.Init("")
+	k := NewKafuiContentProvider(ds)
+	k.switchResource(SwitchResourceMsg(DelegationTokenResourceType))
+	loadACLs(t, k) // loads whichever resource is current
+	return k, ds
+}
+
+func TestDelegationTokenResourceItem_NeverRendersHMAC(t *testing.T) {
+	tok := api.DelegationToken{TokenID: "tk", Owner: "User:alice", Renewers: []string{"User:bob"}, HMAC: []byte("secret")}
+	item := &DelegationTokenResourceItem{token: tok}
+	vals := item.GetValues()
+	assert.Equal(t, []string{"tk", "User:alice", "User:bob"}, vals[:3])
+	for _, v := range append(vals, mapValues(item.GetDetails())...) {
+		assert.NotContains(t, v, tok.HMACBase64())
+	}
+}
+
+func mapValues(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestTokenCreate_ShowsHMACOnceAndCopies(t *testing.T) {
+	var copied string
+	orig := copyToClipboard
+	copyToClipboard = func(s string) error { copied = s; return nil }
+	defer func() { copyToClipboard = orig }()
+
+	k, ds := newTokenProvider(t)
+	k.openTokenCreateForm()
+	require.True(t, k.showTokenForm)
+
+	res, ok := k.handleTokenFormSubmit(map[string]string{"renewers": "User:bob, User:carol", "maxLifetime": "72h"})().(delegationTokenCreatedMsg)
+	require.True(t, ok)
+	require.NoError(t, res.err)
+	assert.Equal(t, []string{"User:bob", "User:carol"}, res.token.Renewers)
+
+	k.HandleContentUpdate(res)
+	assert.False(t, k.showTokenForm)
+	require.NotNil(t, k.issuedToken)
+	assert.True(t, k.IsInputMode(), "the panel swallows keys")
+	assert.Contains(t, k.RenderContent(120, 40), res.token.HMACBase64())
+
+	k.HandleContentUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	assert.Equal(t, res.token.HMACBase64(), copied)
+
+	k.HandleContentUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, k.issuedToken, "the HMAC is not shown again")
+	assert.False(t, k.IsInputMode())
+
+	tokens, _ := ds.DescribeDelegationTokens(nil)
+	assert.Len(t, tokens, 3)
+}
+
+func TestTokenCreate_InvalidInputKeepsFormOpen(t *testing.T) {
+	k, _ := newTokenProvider(t)
+	k.openTokenCreateForm()
+
+	for _, v := range []map[string]string{
+		{"renewers": "bob"},
+		{"maxLifetime": "soon"},
+	} {
+		res := k.handleTokenFormSubmit(v)().(delegationTokenCreatedMsg)
+		var ie api.InvalidDelegationTokenError
+		require.ErrorAs(t, res.err, &ie)
+		k.HandleContentUpdate(res)
+		assert.True(t, k.showTokenForm)
+		assert.Nil(t, k.issuedToken)
+	}
+}
+
+func TestTokenRenew_TargetsHighlightedToken(t *testing.T) {
+	k, _ := newTokenProvider(t)
+	selected, ok := tokenItemFrom(k.GetSelectedResourceItem())
+	require.True(t, ok)
+	k.openTokenRenewForm()
+	require.NotNil(t, k.tokenRenewTarget)
+	assert.Equal(t, selected.GetID(), k.tokenRenewTarget.TokenID)
+
+	res := k.handleTokenFormSubmit(map[string]string{"period": "48h"})().(delegationTokenAlteredMsg)
+	require.NoError(t, res.err)
+	assert.Equal(t, "renewed", res.action)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), res.expiry, time.Minute)
+
+	k.HandleContentUpdate(res)
+	assert.False(t, k.showTokenForm)
+	assert.Nil(t, k.tokenRenewTarget)
+}
+
+func TestTokenExpire_ConfirmThenExpire(t *testing.T) {
+	k, ds := newTokenProvider(t)
+	selected, ok := tokenItemFrom(k.GetSelectedResourceItem())
+	require.True(t, ok)
+
+	confirm, ok := k.expireSelectedToken()().(core.ShowConfirmMsg)
+	require.True(t, ok, "expire must go through a confirmation")
+	assert.True(t, confirm.Danger)
+
+	res, ok := confirm.OnConfirm().(delegationTokenAlteredMsg)
+	require.True(t, ok)
+	require.NoError(t, res.err)
+	tokens, _ := ds.DescribeDelegationTokens(nil)
+	for _, tok := range tokens {
+		assert.NotEqual(t, selected.GetID(), tok.TokenID)
+	}
+}
+
+func TestParseResourceType_Tokens(t *testing.T) {
+	k, _ := newTokenProvider(t)
+	assert.Equal(t, DelegationTokenResourceType, k.parseResourceType("tokens"))
+	assert.Equal(t, DelegationTokenResourceType, k.parseResourceType("delegation-tokens"))
+}
@@ -389,22 +389,7 @@ func (k *KafuiContentProvider) exportTopicsCSV() tea.Cmd {
 	ds := k.dataSource
 	ctx := ds.GetContext()
 	return func() tea.Msg {
-		sizes, _ := ds.GetTopicSizes(names)
-		rows := make([]shared.TopicCSVRow, 0, len(names))
-		for _, name := range names {
-			row := shared.TopicCSVRow{Name: name, MessageCount: -1, Size: -1}
-			if sz, ok := sizes[name]; ok {
-				row.Size = sz
-			}
-			if d, err := ds.GetTopicDetails(name); err == nil {
-				row.Partitions = int32(len(d.Partitions))
-				row.ReplicationFactor = d.ReplicationFactor
-				row.MessageCount = d.MessageCount()
-				row.OutOfSync = d.UnderReplicatedPartitions
-				row.Internal = d.IsInternal
-			}
-			rows = append(rows, row)
-		}
+		rows := shared.CollectTopicCSVRows(ds, names)
 		filename := fmt.Sprintf("kafui-topics-%s-%s.csv", ctx, time.Now().Format("20060102-150405"))
 		f, err := os.Create(filename)
 		if err != nil {
@@ -130,6 +130,29 @@ type (
 		err    error
 	}
 
+	// scramAlteredMsg reports the outcome of a SCRAM credential create/rotate/
+	// delete. action is one of "created", "rotated", "deleted".
+	scramAlteredMsg struct {
+		user   string
+		action string
+		err    error
+	}
+
+	// delegationTokenCreatedMsg carries a newly created token, HMAC included.
+	delegationTokenCreatedMsg struct {
+		token api.DelegationToken
+		err   error
+	}
+
+	// delegationTokenAlteredMsg reports the outcome of a token renew/expire.
+	// action is one of "renewed", "expired".
+	delegationTokenAlteredMsg struct {
+		tokenID string
+		action  string
+		expiry  time.Time
+		err     error
+	}
+
 	// connectClusterSelectedMsg drills from the Connect-clusters overview into
 	// the aggregated connectors view, pre-filtered to the chosen cluster (KC-11).
 	connectClusterSelectedMsg struct {
@@ -176,6 +199,8 @@ const (
 	QuotaResourceType
 	ConnectClusterResourceType
 	ConnectorResourceType
+	ScramUserResourceType
+	DelegationTokenResourceType
 )
 
 func (rt ResourceType) String() string {
@@ -198,6 +223,10 @@ func (rt ResourceType) String() string {
 		return "connect-clusters"
 	case ConnectorResourceType:
 		return "connectors"
+	case ScramUserResourceType:
+		return "users"
+	case DelegationTokenResourceType:
+		return "tokens"
 	default:
 		return "unknown"
 	}
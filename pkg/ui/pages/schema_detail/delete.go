@@ -63,3 +63,35 @@ func (p *SchemaDetailPageModel) handleDeleteResult(msg SchemaDeleteResultMsg) te
 		core.NewNotification(core.StatusSuccess, "Delete version", "Version deleted"),
 	)
 }
+
+// confirmRestoreVersionCmd asks for confirmation, then restores a soft-deleted
+// version by registering its content again.
+func (m *Model) confirmRestoreVersionCmd(version int) tea.Cmd {
+	subject, ds := m.subject, m.dataSource
+	return func() tea.Msg {
+		return core.ShowConfirmMsg{
+			Title:        "Restore version",
+			Message:      fmt.Sprintf("Restore deleted version %d of %q? It is registered again as a new version.", version, subject),
+			ConfirmLabel: "Restore",
+			OnConfirm: func() tea.Msg {
+				s, err := ds.RestoreSchemaVersion(subject, version)
+				return SchemaRestoreResultMsg{Version: version, Schema: s, Err: err}
+			},
+		}
+	}
+}
+
+// handleRestoreResult refreshes the version list after a restore.
+func (p *SchemaDetailPageModel) handleRestoreResult(msg SchemaRestoreResultMsg) tea.Cmd {
+	m := p.model
+	if msg.Err != nil {
+		return core.NotifyError("Restore version", msg.Err)
+	}
+	m.versionsLoaded = false
+	m.versionCursor = 0
+	return tea.Batch(
+		m.loadVersionsCmd(),
+		core.NewNotification(core.StatusSuccess, "Restore version",
+			fmt.Sprintf("Version %d restored as version %d", msg.Version, msg.Schema.Version)),
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Benny93/kafui/pkg/ui/components/editor"
+	"github.com/Benny93/kafui/pkg/ui/shared/schemadiff"
 	stylesPkg "github.com/Benny93/kafui/pkg/ui/styles"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -61,6 +62,19 @@ func (m *Model) refreshDiff() {
 	left := prettySchema(m.contentCache[m.diffLeft], m.GetSchemaType())
 	right := prettySchema(m.contentCache[m.diffRight], m.GetSchemaType())
 	m.diffView.SetContent(left, right)
+	m.refreshSemantic()
+}
+
+// refreshSemantic recomputes the structural changes between the two versions
+// once both texts are cached.
+func (m *Model) refreshSemantic() {
+	m.semantic, m.semanticErr, m.semanticOffset = schemadiff.Result{}, nil, 0
+	left, okLeft := m.contentCache[m.diffLeft]
+	right, okRight := m.contentCache[m.diffRight]
+	if !okLeft || !okRight {
+		return
+	}
+	m.semantic, m.semanticErr = schemadiff.Diff(m.GetSchemaType(), left, right)
 }
 
 // cycleDiffVersion moves the active pane's version by delta through the list.
@@ -102,6 +116,9 @@ func handleDiffKey(m *Model, msg tea.KeyMsg) tea.Cmd {
 	case "tab", "h", "l":
 		m.diffActive = 1 - m.diffActive
 		return nil
+	case "s":
+		m.diffSemantic = !m.diffSemantic
+		return nil
 	case "[":
 		return m.cycleDiffVersion(-1)
 	case "]":
@@ -109,13 +126,26 @@ func handleDiffKey(m *Model, msg tea.KeyMsg) tea.Cmd {
 	case "esc", "backspace":
 		m.mode = modeContent
 		return nil
-	default:
-		if m.diffView != nil {
-			_, cmd := m.diffView.Update(msg)
-			return cmd
+	case "j", "down":
+		if m.diffSemantic {
+			if m.semanticOffset < len(m.semantic.Changes)-1 {
+				m.semanticOffset++
+			}
+			return nil
 		}
-		return nil
+	case "k", "up":
+		if m.diffSemantic {
+			if m.semanticOffset > 0 {
+				m.semanticOffset--
+			}
+			return nil
+		}
+	}
+	if m.diffView != nil && !m.diffSemantic {
+		_, cmd := m.diffView.Update(msg)
+		return cmd
 	}
+	return nil
 }
 
 func renderDiff(m *Model, width, height int) string {
@@ -138,10 +168,65 @@ func renderDiff(m *Model, width, height int) string {
 		"   " + left + sideStyle.Render(" → ") + right
 
 	var body string
-	if m.diffView != nil {
+	hint := mutedStyle.Render("tab switch pane · [ / ] change version · s semantic · esc back")
+	switch {
+	case m.diffSemantic:
+		body = renderSemantic(m, height-3)
+		hint = mutedStyle.Render("↑/↓ scroll · tab switch pane · [ / ] change version · s text diff · esc back")
+	case m.diffView != nil:
 		m.diffView.SetDimensions(width, height-3)
 		body = m.diffView.View()
 	}
-	hint := mutedStyle.Render("tab switch pane · [ / ] change version · esc back")
 	return strings.Join([]string{header, body, hint}, "\n")
 }
+
+// renderSemantic lists the structural changes from the left to the right
+// version, each with its compatibility, under an overall summary line.
+func renderSemantic(m *Model, height int) string {
+	okStyle := lipgloss.NewStyle().Foreground(stylesPkg.Success)
+	badStyle := lipgloss.NewStyle().Foreground(stylesPkg.Error)
+	mutedStyle := lipgloss.NewStyle().Foreground(stylesPkg.FgMuted)
+	mark := func(ok bool) string {
+		if ok {
+			return okStyle.Render("✓")
+		}
+		return badStyle.Render("✗")
+	}
+
+	_, okLeft := m.contentCache[m.diffLeft]
+	_, okRight := m.contentCache[m.diffRight]
+	switch {
+	case !okLeft || !okRight:
+		return mutedStyle.Render("Loading versions…")
+	case m.semanticErr != nil:
+		return badStyle.Render(m.semanticErr.Error())
+	}
+
+	r := m.semantic
+	lines := []string{fmt.Sprintf("%s   backward %s   forward %s   %d changes",
+		lipgloss.NewStyle().Bold(true).Render(r.Compatibility()), mark(r.Backward()), mark(r.Forward()), len(r.Changes))}
+	if len(r.Changes) == 0 {
+		lines = append(lines, mutedStyle.Render("No structural changes."))
+	}
+	visible := height - 1
+	if visible < 1 {
+		visible = 1
+	}
+	for i := m.semanticOffset; i < len(r.Changes) && i < m.semanticOffset+visible; i++ {
+		c := r.Changes[i]
+		line := fmt.Sprintf("B%s F%s  %-20s %s", mark(c.Backward), mark(c.Forward), c.Kind, c.Path)
+		switch {
+		case c.From != "" && c.To != "":
+			line += fmt.Sprintf(": %s → %s", c.From, c.To)
+		case c.To != "":
+			line += ": " + c.To
+		case c.From != "":
+			line += ": " + c.From
+		}
+		if c.Note != "" {
+			line += mutedStyle.Render("  (" + c.Note + ")")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,200 @@
+package schemadetail
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/core"
+	stylesPkg "github.com/Benny93/kafui/pkg/ui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// enterMatrixForVersion checks the displayed version against every version
+// before it.
+func (m *Model) enterMatrixForVersion() tea.Cmd {
+	if m.version <= 0 || m.content == "" {
+		m.SetStatus("No version loaded")
+		return nil
+	}
+	return m.enterMatrix(fmt.Sprintf("v%d", m.version), m.content, m.references, m.version)
+}
+
+// enterMatrixForCandidate checks the register editor's text against every
+// version, i.e. what registering it would be checked against.
+func (m *Model) enterMatrixForCandidate() tea.Cmd {
+	if m.editor == nil {
+		return nil
+	}
+	refs, err := m.registerReferences()
+	if err != nil {
+		return core.NotifyError("Compatibility matrix", err)
+	}
+	return m.enterMatrix("candidate", m.editor.Value(), refs, 0)
+}
+
+// enterMatrix switches to the matrix view and starts the checks of text
+// against the versions below before (all versions when before is 0).
+func (m *Model) enterMatrix(candidate, text string, refs []api.SchemaReference, before int) tea.Cmd {
+	m.matrixReturn = m.mode
+	m.mode = modeMatrix
+	m.matrixCandidate = candidate
+	m.matrixLoading = true
+	m.matrix = SchemaMatrixResultMsg{}
+	m.matrixCursor = 0
+	m.matrixSeq++
+	subject, typ, ds, seq := m.subject, m.schemaType, m.dataSource, m.matrixSeq
+	return func() tea.Msg {
+		msg := checkMatrix(ds, subject, typ, text, refs, before)
+		msg.Seq = seq
+		return msg
+	}
+}
+
+// checkMatrix checks text against each live version below before, newest
+// first, one registry call per version so every result carries its own
+// verbose messages.
+func checkMatrix(ds api.KafkaDataSource, subject, typ, text string, refs []api.SchemaReference, before int) SchemaMatrixResultMsg {
+	level, _, err := ds.GetSubjectCompatibility(subject)
+	if err != nil {
+		return SchemaMatrixResultMsg{Err: err}
+	}
+	versions, err := ds.GetSchemaVersions(subject)
+	if err != nil {
+		return SchemaMatrixResultMsg{Err: err}
+	}
+	var prior []int
+	for _, v := range versions {
+		if before <= 0 || v.Version < before {
+			prior = append(prior, v.Version)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(prior)))
+
+	out := SchemaMatrixResultMsg{Level: level}
+	for i, v := range prior {
+		row := MatrixRow{Version: v, Enforced: enforced(level, i == 0)}
+		row.Compatible, row.Messages, row.Err = ds.CheckSchemaCompatibilityWithVersion(subject, v, text, typ, refs)
+		out.Rows = append(out.Rows, row)
+	}
+	return out
+}
+
+// enforced reports whether a registration under level is checked against a
+// prior version; newest marks the latest one.
+func enforced(level api.CompatibilityLevel, newest bool) bool {
+	switch {
+	case level == api.CompatibilityNone:
+		return false
+	case strings.HasSuffix(string(level), "_TRANSITIVE"):
+		return true
+	default:
+		return newest
+	}
+}
+
+// Failing returns the enforced versions the candidate is incompatible with (or
+// could not be checked against), i.e. why a registration would be rejected.
+func (r SchemaMatrixResultMsg) Failing() []int {
+	var out []int
+	for _, row := range r.Rows {
+		if row.Enforced && (row.Err != nil || !row.Compatible) {
+			out = append(out, row.Version)
+		}
+	}
+	return out
+}
+
+func handleMatrixKey(m *Model, msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "j", "down":
+		if m.matrixCursor < len(m.matrix.Rows)-1 {
+			m.matrixCursor++
+		}
+	case "k", "up":
+		if m.matrixCursor > 0 {
+			m.matrixCursor--
+		}
+	case "esc", "backspace":
+		m.mode = m.matrixReturn
+	}
+	return nil
+}
+
+func renderMatrix(m *Model, width, height int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(stylesPkg.Primary).Bold(true)
+	okStyle := lipgloss.NewStyle().Foreground(stylesPkg.Success)
+	badStyle := lipgloss.NewStyle().Foreground(stylesPkg.Error)
+	mutedStyle := lipgloss.NewStyle().Foreground(stylesPkg.FgMuted)
+	cursorStyle := lipgloss.NewStyle().Foreground(stylesPkg.Primary).Bold(true)
+
+	r := m.matrix
+	lines := []string{titleStyle.Render(fmt.Sprintf("Compatibility of %s against earlier versions of %s", m.matrixCandidate, m.subject))}
+	hint := mutedStyle.Render("↑/↓ select · esc back")
+	switch {
+	case m.matrixLoading:
+		return strings.Join(append(lines, mutedStyle.Render("Checking…"), hint), "\n")
+	case r.Err != nil:
+		return strings.Join(append(lines, badStyle.Render(r.Err.Error()), hint), "\n")
+	case len(r.Rows) == 0:
+		return strings.Join(append(lines, mutedStyle.Render("No earlier versions to check against."), hint), "\n")
+	}
+
+	if failing := r.Failing(); len(failing) > 0 {
+		names := make([]string, len(failing))
+		for i, v := range failing {
+			names[i] = fmt.Sprintf("v%d", v)
+		}
+		lines = append(lines, badStyle.Render(fmt.Sprintf("✗ fails %s: incompatible with %s, so registering it is rejected (409)", r.Level, strings.Join(names, ", "))))
+	} else {
+		lines = append(lines, okStyle.Render(fmt.Sprintf("✓ passes %s", r.Level)))
+	}
+	lines = append(lines, "")
+
+	// Each row is one line plus its messages; scroll so the cursor row shows.
+	var rows []string
+	cursorLine := 0
+	for i, row := range r.Rows {
+		mark, result := okStyle.Render("✓"), "compatible"
+		switch {
+		case row.Err != nil:
+			mark, result = badStyle.Render("!"), "error"
+		case !row.Compatible:
+			mark, result = badStyle.Render("✗"), "incompatible"
+		}
+		scope := mutedStyle.Render("not checked at " + string(r.Level))
+		if row.Enforced {
+			scope = "checked on register"
+		}
+		line := fmt.Sprintf("%s v%-4d %-12s  %s", mark, row.Version, result, scope)
+		if i == m.matrixCursor {
+			cursorLine = len(rows)
+			line = cursorStyle.Render("▸ ") + line
+		} else {
+			line = "  " + line
+		}
+		rows = append(rows, line)
+		if row.Err != nil {
+			rows = append(rows, badStyle.Render("      "+row.Err.Error()))
+		}
+		for _, msg := range row.Messages {
+			rows = append(rows, mutedStyle.Render("      "+msg))
+		}
+	}
+	visible := height - len(lines) - 1
+	if visible < 1 {
+		visible = 1
+	}
+	start := 0
+	if cursorLine >= visible {
+		start = cursorLine - visible + 1
+	}
+	end := start + visible
+	if end > len(rows) {
+		end = len(rows)
+	}
+	lines = append(lines, rows[start:end]...)
+	return strings.Join(append(lines, hint), "\n")
+}
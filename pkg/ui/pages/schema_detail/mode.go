@@ -0,0 +1,103 @@
+package schemadetail
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/core"
+	stylesPkg "github.com/Benny93/kafui/pkg/ui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// enterModePicker opens the registry mode selector, highlighting the current
+// effective mode.
+func (m *Model) enterModePicker() {
+	m.mode = modeModePicker
+	m.modePickerCursor = 0
+	for i, mode := range api.SchemaModes() {
+		if mode == m.registryMode {
+			m.modePickerCursor = i
+			break
+		}
+	}
+}
+
+// SelectedMode returns the registry mode under the picker cursor.
+func (m *Model) SelectedMode() api.SchemaMode {
+	modes := api.SchemaModes()
+	if m.modePickerCursor < 0 || m.modePickerCursor >= len(modes) {
+		return ""
+	}
+	return modes[m.modePickerCursor]
+}
+
+// confirmSetModeCmd asks for confirmation, then sets the subject's mode. The
+// datasource call runs only after confirmation.
+func (m *Model) confirmSetModeCmd(mode api.SchemaMode) tea.Cmd {
+	subject, ds := m.subject, m.dataSource
+	return func() tea.Msg {
+		return core.ShowConfirmMsg{
+			Title:        "Set mode",
+			Message:      fmt.Sprintf("Set mode of %q to %s?", subject, mode),
+			Danger:       mode != api.SchemaModeReadWrite,
+			ConfirmLabel: "Set",
+			OnConfirm: func() tea.Msg {
+				err := ds.SetSubjectMode(subject, mode)
+				return SchemaModeSetResultMsg{Mode: mode, Err: err}
+			},
+		}
+	}
+}
+
+func handleModePickerKey(m *Model, msg tea.KeyMsg) tea.Cmd {
+	modes := api.SchemaModes()
+	switch msg.String() {
+	case "up", "k":
+		if m.modePickerCursor > 0 {
+			m.modePickerCursor--
+		}
+	case "down", "j":
+		if m.modePickerCursor < len(modes)-1 {
+			m.modePickerCursor++
+		}
+	case "enter":
+		if mode := m.SelectedMode(); mode != "" {
+			m.mode = modeContent
+			return m.confirmSetModeCmd(mode)
+		}
+	case "esc", "backspace":
+		m.mode = modeContent
+	}
+	return nil
+}
+
+func renderModePicker(m *Model, width, height int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(stylesPkg.Primary).Bold(true)
+	cursorStyle := lipgloss.NewStyle().Foreground(stylesPkg.BgBase).Background(stylesPkg.Primary)
+	rowStyle := lipgloss.NewStyle().Foreground(stylesPkg.FgBase)
+	mutedStyle := lipgloss.NewStyle().Foreground(stylesPkg.FgMuted)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Registry mode for %s", m.subject)))
+	b.WriteString("\n\n")
+	for i, mode := range api.SchemaModes() {
+		marker := ""
+		if mode == m.registryMode {
+			marker = "  (current)"
+		}
+		line := string(mode) + marker
+		if i == m.modePickerCursor {
+			b.WriteString(cursorStyle.Render("▸ " + line))
+		} else {
+			b.WriteString(rowStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("READONLY blocks registrations · IMPORT keeps given IDs"))
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("↑/↓ select · enter set (confirm) · esc cancel"))
+	return b.String()
+}
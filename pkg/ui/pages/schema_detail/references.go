@@ -0,0 +1,163 @@
+package schemadetail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/core"
+	mainpage "github.com/Benny93/kafui/pkg/ui/pages/main"
+	stylesPkg "github.com/Benny93/kafui/pkg/ui/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// refTarget is one navigable row of the references view: either a subject the
+// current version imports or one that imports it.
+type refTarget struct {
+	subject string
+	version int
+}
+
+// enterReferences switches to the references view (imports + referenced by).
+func (m *Model) enterReferences() tea.Cmd {
+	m.mode = modeReferences
+	m.refCursor = 0
+	if m.referencedByLoaded {
+		return nil
+	}
+	return m.loadReferencedByCmd()
+}
+
+// refTargets lists the navigable rows: imports first, then referrers.
+func (m *Model) refTargets() []refTarget {
+	out := make([]refTarget, 0, len(m.references)+len(m.referencedBy))
+	for _, r := range m.references {
+		out = append(out, refTarget{subject: r.Subject, version: r.Version})
+	}
+	for _, r := range m.referencedBy {
+		out = append(out, refTarget{subject: r.Subject, version: r.Version})
+	}
+	return out
+}
+
+// openReferenceCmd navigates to another schema detail page for the target.
+func openReferenceCmd(t refTarget) tea.Cmd {
+	item := mainpage.NewSchemaResourceItem(api.Schema{Subject: t.subject, Version: t.version})
+	return core.NewPageChangeMsg("schema_detail:"+t.subject, map[string]interface{}{"schemaItem": item})
+}
+
+func handleReferencesKey(m *Model, msg tea.KeyMsg) tea.Cmd {
+	targets := m.refTargets()
+	switch msg.String() {
+	case "up", "k":
+		if m.refCursor > 0 {
+			m.refCursor--
+		}
+	case "down", "j":
+		if m.refCursor < len(targets)-1 {
+			m.refCursor++
+		}
+	case "enter":
+		if m.refCursor >= 0 && m.refCursor < len(targets) {
+			return openReferenceCmd(targets[m.refCursor])
+		}
+	case "esc", "backspace":
+		m.mode = modeContent
+	}
+	return nil
+}
+
+func renderReferences(m *Model, width, height int) string {
+	titleStyle := lipgloss.NewStyle().Foreground(stylesPkg.Primary).Bold(true)
+	headerStyle := lipgloss.NewStyle().Foreground(stylesPkg.FgBase).Bold(true)
+	cursorStyle := lipgloss.NewStyle().Foreground(stylesPkg.BgBase).Background(stylesPkg.Primary)
+	rowStyle := lipgloss.NewStyle().Foreground(stylesPkg.FgBase)
+	mutedStyle := lipgloss.NewStyle().Foreground(stylesPkg.FgMuted)
+
+	versionStr := "latest"
+	if m.version > 0 {
+		versionStr = "v" + strconv.Itoa(m.version)
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("References of %s %s", m.subject, versionStr)))
+	b.WriteString("\n\n")
+
+	row := func(idx int, line string) {
+		if idx == m.refCursor {
+			b.WriteString(cursorStyle.Render("▸ " + line))
+		} else {
+			b.WriteString(rowStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Imports (%d)", len(m.references))) + "\n")
+	if len(m.references) == 0 {
+		b.WriteString(mutedStyle.Render("  none") + "\n")
+	}
+	for i, r := range m.references {
+		row(i, fmt.Sprintf("%s → %s v%d", r.Name, r.Subject, r.Version))
+	}
+
+	b.WriteString("\n")
+	switch {
+	case !m.referencedByLoaded:
+		b.WriteString(headerStyle.Render("Referenced by") + "\n")
+		b.WriteString(mutedStyle.Render("  Loading…") + "\n")
+	case len(m.referencedBy) == 0:
+		b.WriteString(headerStyle.Render("Referenced by (0)") + "\n")
+		b.WriteString(mutedStyle.Render("  none") + "\n")
+	default:
+		b.WriteString(headerStyle.Render(fmt.Sprintf("Referenced by (%d)", len(m.referencedBy))) + "\n")
+		for i, r := range m.referencedBy {
+			row(len(m.references)+i, fmt.Sprintf("%s v%d", r.Subject, r.Version))
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("↑/↓ select · enter open subject · esc back"))
+	return b.String()
+}
+
+// parseSchemaReferences parses the register form's references field:
+// comma-separated name=subject:version entries, e.g.
+// "com.example.Address=address-value:2, common.proto=common:1".
+func parseSchemaReferences(s string) ([]api.SchemaReference, error) {
+	var refs []api.SchemaReference
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, target, ok := strings.Cut(entry, "=")
+		colon := strings.LastIndex(target, ":")
+		if !ok || colon < 0 {
+			return nil, api.SchemaValidationError{Message: fmt.Sprintf("reference %q: expected name=subject:version", entry)}
+		}
+		version, err := strconv.Atoi(strings.TrimSpace(target[colon+1:]))
+		if err != nil {
+			return nil, api.SchemaValidationError{Message: fmt.Sprintf("reference %q: version must be a number", entry)}
+		}
+		refs = append(refs, api.SchemaReference{
+			Name:    strings.TrimSpace(name),
+			Subject: strings.TrimSpace(target[:colon]),
+			Version: version,
+		})
+	}
+	if err := api.ValidateSchemaReferences(refs); err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+// formatSchemaReferences is the inverse of parseSchemaReferences.
+func formatSchemaReferences(refs []api.SchemaReference) string {
+	parts := make([]string, len(refs))
+	for i, r := range refs {
+		parts[i] = fmt.Sprintf("%s=%s:%d", r.Name, r.Subject, r.Version)
+	}
+	return strings.Join(parts, ", ")
+}
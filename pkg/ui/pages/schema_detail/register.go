@@ -5,18 +5,44 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/ui/components/editor"
 	"github.com/Benny93/kafui/pkg/ui/core"
 	stylesPkg "github.com/Benny93/kafui/pkg/ui/styles"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// enterRegister opens the editor seeded with the current schema text (SR-16).
+// enterRegister opens the editor seeded with the current schema text and its
+// references (SR-16).
 func (m *Model) enterRegister() {
 	m.mode = modeRegister
 	m.registerSeed = m.content
 	m.editor = editor.NewEditor(m.content)
+	m.registerRefsSeed = formatSchemaReferences(m.references)
+	m.refsInput = textinput.New()
+	m.refsInput.Placeholder = "name=subject:version, …"
+	m.refsInput.Prompt = "References: "
+	m.refsInput.SetValue(m.registerRefsSeed)
+	m.refsFocused = false
+}
+
+// registerReferences parses the references field of the register view.
+func (m *Model) registerReferences() ([]api.SchemaReference, error) {
+	return parseSchemaReferences(m.refsInput.Value())
+}
+
+// toggleRefsFocus moves focus between the schema editor and the references
+// field.
+func (m *Model) toggleRefsFocus() tea.Cmd {
+	m.refsFocused = !m.refsFocused
+	if m.refsFocused {
+		m.editor.Blur()
+		return m.refsInput.Focus()
+	}
+	m.refsInput.Blur()
+	return m.editor.Focus()
 }
 
 // validateRegister performs client-side pre-validation. Returns "" when valid.
@@ -24,7 +50,7 @@ func (m *Model) validateRegister(text string) string {
 	if strings.TrimSpace(text) == "" {
 		return "Schema text is empty"
 	}
-	if text == m.registerSeed {
+	if text == m.registerSeed && m.refsInput.Value() == m.registerRefsSeed {
 		return "Schema unchanged from the current version"
 	}
 	if isJSONType(m.GetSchemaType()) {
@@ -39,30 +65,34 @@ func (m *Model) validateRegister(text string) string {
 // checkThenRegisterCmd runs a compatibility check and only registers when the
 // candidate is compatible (SR-16/SR-17). Guarantees Check is called before
 // Register.
-func (m *Model) checkThenRegisterCmd(text string) tea.Cmd {
+func (m *Model) checkThenRegisterCmd(text string, refs []api.SchemaReference) tea.Cmd {
 	subject, typ, ds := m.subject, m.schemaType, m.dataSource
 	return func() tea.Msg {
-		compatible, messages, err := ds.CheckSchemaCompatibility(subject, text, typ)
+		compatible, messages, err := ds.CheckSchemaCompatibility(subject, text, typ, refs)
 		if err != nil {
 			return SchemaRegisterResultMsg{Err: err}
 		}
 		if !compatible {
 			return SchemaRegisterResultMsg{Incompatible: true, Messages: messages}
 		}
-		schema, err := ds.RegisterSchema(subject, text, typ)
+		schema, err := ds.RegisterSchema(subject, text, typ, refs)
 		return SchemaRegisterResultMsg{Schema: schema, Err: err}
 	}
 }
 
 // checkOnlyCmd runs a standalone compatibility check without registering (SR-17).
 func (m *Model) checkOnlyCmd() tea.Cmd {
-	text := m.content
+	text, refs := m.content, m.references
 	if m.editor != nil {
 		text = m.editor.Value()
+		var err error
+		if refs, err = m.registerReferences(); err != nil {
+			return func() tea.Msg { return SchemaCheckResultMsg{Err: err} }
+		}
 	}
 	subject, typ, ds := m.subject, m.schemaType, m.dataSource
 	return func() tea.Msg {
-		compatible, messages, err := ds.CheckSchemaCompatibility(subject, text, typ)
+		compatible, messages, err := ds.CheckSchemaCompatibility(subject, text, typ, refs)
 		return SchemaCheckResultMsg{Compatible: compatible, Messages: messages, Err: err}
 	}
 }
@@ -90,10 +120,23 @@ func handleRegisterKey(m *Model, msg tea.KeyMsg) tea.Cmd {
 		if reason := m.validateRegister(text); reason != "" {
 			return core.NewNotification(core.StatusWarning, "Register schema", reason)
 		}
-		return m.checkThenRegisterCmd(text)
+		refs, err := m.registerReferences()
+		if err != nil {
+			return core.NotifyError("Register schema", err)
+		}
+		return m.checkThenRegisterCmd(text, refs)
 	case "ctrl+k":
 		return m.checkOnlyCmd()
+	case "ctrl+t":
+		return m.enterMatrixForCandidate()
+	case "ctrl+r":
+		return m.toggleRefsFocus()
 	default:
+		if m.refsFocused {
+			var cmd tea.Cmd
+			m.refsInput, cmd = m.refsInput.Update(msg)
+			return cmd
+		}
 		if m.editor != nil {
 			_, cmd := m.editor.Update(msg)
 			return cmd
@@ -139,9 +182,11 @@ func renderRegister(m *Model, width, height int) string {
 	header := titleStyle.Render(fmt.Sprintf("Register new version of %s (%s)", m.subject, m.GetSchemaType()))
 	var body string
 	if m.editor != nil {
-		m.editor.SetDimensions(width, height-3)
+		m.editor.SetDimensions(width, height-4)
 		body = m.editor.View()
 	}
-	hint := mutedStyle.Render("ctrl+s check & register · ctrl+k check only · esc cancel")
-	return strings.Join([]string{header, body, hint}, "\n")
+	m.refsInput.Width = width - lipgloss.Width(m.refsInput.Prompt) - 1
+	refs := m.refsInput.View()
+	hint := mutedStyle.Render("ctrl+s check & register · ctrl+k check only · ctrl+t check all versions · ctrl+r edit references · esc cancel")
+	return strings.Join([]string{header, body, refs, hint}, "\n")
 }
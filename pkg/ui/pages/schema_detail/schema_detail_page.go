@@ -8,11 +8,13 @@ import (
 	"github.com/Benny93/kafui/pkg/ui/components/editor"
 	"github.com/Benny93/kafui/pkg/ui/core"
 	mainpage "github.com/Benny93/kafui/pkg/ui/pages/main"
+	"github.com/Benny93/kafui/pkg/ui/shared/schemadiff"
 	templateui "github.com/Benny93/kafui/pkg/ui/template/ui"
 	"github.com/Benny93/kafui/pkg/ui/template/ui/providers"
 	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -30,34 +32,50 @@ import (
 type viewMode int
 
 const (
-	modeContent  viewMode = iota // read-only syntax-highlighted schema (default)
-	modeVersions                 // version list (SR-11)
-	modeDiff                     // side-by-side version diff (SR-12)
-	modeRegister                 // editable new-version editor (SR-16)
-	modePicker                   // compatibility-level picker (SR-19)
+	modeContent    viewMode = iota // read-only syntax-highlighted schema (default)
+	modeVersions                   // version list (SR-11)
+	modeDiff                       // side-by-side version diff (SR-12)
+	modeRegister                   // editable new-version editor (SR-16)
+	modePicker                     // compatibility-level picker (SR-19)
+	modeReferences                 // imports + referenced-by lists
+	modeModePicker                 // registry mode picker
+	modeMatrix                     // candidate checked against every prior version
 )
 
 // ─── Async messages ──────────────────────────────────────────────────────────
 
 // SchemaContentLoadedMsg is sent when the async schema fetch completes.
 type SchemaContentLoadedMsg struct {
-	Version int
-	Content string
-	Err     error
+	Version    int
+	Content    string
+	References []api.SchemaReference
+	Err        error
 }
 
-// SchemaVersionsLoadedMsg carries the version list of the current subject (SR-11).
+// SchemaReferencedByLoadedMsg carries the subject versions that reference the
+// given version of the current subject.
+type SchemaReferencedByLoadedMsg struct {
+	Version  int
+	Subjects []api.SubjectVersion
+	Err      error
+}
+
+// SchemaVersionsLoadedMsg carries the version list of the current subject
+// (SR-11) and its soft-deleted versions.
 type SchemaVersionsLoadedMsg struct {
 	Versions []api.SchemaVersion
+	Deleted  []api.SchemaVersion
 	Err      error
 }
 
-// SchemaMetaLoadedMsg carries the effective compatibility level and associated
-// topic resolved for the sidebar (SR-13/SR-14).
+// SchemaMetaLoadedMsg carries the effective compatibility level, registry mode
+// and associated topic resolved for the sidebar (SR-13/SR-14).
 type SchemaMetaLoadedMsg struct {
-	Level    api.CompatibilityLevel
-	Specific bool
-	Topic    string
+	Level        api.CompatibilityLevel
+	Specific     bool
+	Mode         api.SchemaMode
+	ModeSpecific bool
+	Topic        string
 }
 
 // schemaVersionContentMsg carries one version's text for the diff view (SR-12).
@@ -74,6 +92,28 @@ type SchemaCheckResultMsg struct {
 	Err        error
 }
 
+// MatrixRow is the outcome of checking a candidate against one prior version.
+type MatrixRow struct {
+	Version    int
+	Compatible bool
+	Messages   []string
+	// Enforced marks the versions a registration is checked against under the
+	// subject's level: all of them for *_TRANSITIVE, the newest otherwise and
+	// none for NONE.
+	Enforced bool
+	Err      error
+}
+
+// SchemaMatrixResultMsg carries a candidate's compatibility with every prior
+// version of the subject, newest first. Seq identifies the check it answers;
+// results of an earlier check are dropped.
+type SchemaMatrixResultMsg struct {
+	Seq   int
+	Level api.CompatibilityLevel
+	Rows  []MatrixRow
+	Err   error
+}
+
 // SchemaRegisterResultMsg is the result of a register-schema attempt (SR-16).
 type SchemaRegisterResultMsg struct {
 	Schema       api.Schema
@@ -94,6 +134,19 @@ type SchemaCompatSetResultMsg struct {
 	Err   error
 }
 
+// SchemaModeSetResultMsg is the result of a subject mode update.
+type SchemaModeSetResultMsg struct {
+	Mode api.SchemaMode
+	Err  error
+}
+
+// SchemaRestoreResultMsg is the result of restoring a soft-deleted version.
+type SchemaRestoreResultMsg struct {
+	Version int
+	Schema  api.Schema
+	Err     error
+}
+
 // Model holds the state for the schema detail page.
 type Model struct {
 	common     *core.Common
@@ -119,9 +172,10 @@ type Model struct {
 	viewer        *editor.Viewer
 
 	// Versions (SR-11)
-	versions       []api.SchemaVersion
-	versionsLoaded bool
-	versionCursor  int
+	versions        []api.SchemaVersion
+	deletedVersions []api.SchemaVersion
+	versionsLoaded  bool
+	versionCursor   int
 
 	// Diff (SR-12)
 	diffView     *editor.DiffView
@@ -130,9 +184,34 @@ type Model struct {
 	diffActive   int // 0 = left, 1 = right
 	contentCache map[int]string
 
+	// Semantic diff: the structural changes between the diff's two versions.
+	diffSemantic   bool
+	semantic       schemadiff.Result
+	semanticErr    error
+	semanticOffset int
+
+	// Compatibility matrix: the candidate's label (e.g. "v3"), the view to
+	// return to, the sequence number of the running check and the result
+	// once loaded.
+	matrixCandidate string
+	matrixSeq       int
+	matrixReturn    viewMode
+	matrixLoading   bool
+	matrix          SchemaMatrixResultMsg
+	matrixCursor    int
+
 	// Register (SR-16)
-	editor       *editor.Editor
-	registerSeed string
+	editor           *editor.Editor
+	registerSeed     string
+	refsInput        textinput.Model
+	refsFocused      bool
+	registerRefsSeed string
+
+	// References of the displayed version and the versions that import it.
+	references         []api.SchemaReference
+	referencedBy       []api.SubjectVersion
+	referencedByLoaded bool
+	refCursor          int
 
 	// Compatibility (SR-13/SR-19)
 	compat         api.CompatibilityLevel
@@ -140,6 +219,12 @@ type Model struct {
 	compatLoaded   bool
 	pickerCursor   int
 
+	// Registry mode (READWRITE / READONLY / IMPORT)
+	registryMode     api.SchemaMode
+	modeSpecific     bool
+	modeLoaded       bool
+	modePickerCursor int
+
 	// Associated topic (SR-14)
 	topic string
 }
@@ -164,12 +249,21 @@ func NewModel(ds api.KafkaDataSource, item *mainpage.SchemaResourceItem) *Model
 
 // ─── Async command constructors ───────────────────────────────────────────────
 
-// LoadContentAsync fetches the current version's schema content.
+// LoadContentAsync fetches the current version's schema content and references.
 func (m *Model) LoadContentAsync() tea.Cmd {
 	subject, version, ds := m.subject, m.version, m.dataSource
 	return func() tea.Msg {
-		content, err := ds.GetSchemaContent(subject, version)
-		return SchemaContentLoadedMsg{Version: version, Content: content, Err: err}
+		sv, err := ds.GetSchemaVersion(subject, version)
+		return SchemaContentLoadedMsg{Version: version, Content: sv.Schema, References: sv.References, Err: err}
+	}
+}
+
+// loadReferencedByCmd lists the subject versions that import the current one.
+func (m *Model) loadReferencedByCmd() tea.Cmd {
+	subject, version, ds := m.subject, m.version, m.dataSource
+	return func() tea.Msg {
+		subjects, err := ds.GetSchemaReferencedBy(subject, version)
+		return SchemaReferencedByLoadedMsg{Version: version, Subjects: subjects, Err: err}
 	}
 }
 
@@ -178,11 +272,21 @@ func (m *Model) loadVersionsCmd() tea.Cmd {
 	subject, ds := m.subject, m.dataSource
 	return func() tea.Msg {
 		versions, err := ds.GetSchemaVersions(subject)
-		return SchemaVersionsLoadedMsg{Versions: versions, Err: err}
+		if err != nil {
+			return SchemaVersionsLoadedMsg{Err: err}
+		}
+		// Soft-deleted versions are extra; a registry that cannot list them
+		// still shows the live versions.
+		deleted, derr := ds.GetDeletedSchemaVersions(subject)
+		if derr != nil {
+			deleted = nil
+		}
+		return SchemaVersionsLoadedMsg{Versions: versions, Deleted: deleted}
 	}
 }
 
-// loadMetaCmd resolves effective compatibility + associated topic (SR-13/SR-14).
+// loadMetaCmd resolves effective compatibility, registry mode and associated
+// topic (SR-13/SR-14).
 func (m *Model) loadMetaCmd() tea.Cmd {
 	subject, ds := m.subject, m.dataSource
 	return func() tea.Msg {
@@ -190,7 +294,15 @@ func (m *Model) loadMetaCmd() tea.Cmd {
 		if err != nil {
 			level, specific = "", false
 		}
-		return SchemaMetaLoadedMsg{Level: level, Specific: specific, Topic: resolveTopic(subject, ds)}
+		mode, modeSpecific, err := ds.GetSubjectMode(subject)
+		if err != nil {
+			mode, modeSpecific = "", false
+		}
+		return SchemaMetaLoadedMsg{
+			Level: level, Specific: specific,
+			Mode: mode, ModeSpecific: modeSpecific,
+			Topic: resolveTopic(subject, ds),
+		}
 	}
 }
 
@@ -224,12 +336,30 @@ func (m *Model) EffectiveCompatibility() (api.CompatibilityLevel, bool, bool) {
 	return m.compat, m.compatSpecific, m.compatLoaded
 }
 
+// RegistryMode returns the resolved mode and whether it is subject-specific
+// (false → inherited from the global mode).
+func (m *Model) RegistryMode() (api.SchemaMode, bool, bool) {
+	return m.registryMode, m.modeSpecific, m.modeLoaded
+}
+
 // AssociatedTopic returns the matched topic name ("" when none).
 func (m *Model) AssociatedTopic() string { return m.topic }
 
 // Versions returns the loaded version list (may be empty until loaded).
 func (m *Model) Versions() []api.SchemaVersion { return m.versions }
 
+// DeletedVersions returns the loaded soft-deleted versions.
+func (m *Model) DeletedVersions() []api.SchemaVersion { return m.deletedVersions }
+
+// References returns the displayed version's references.
+func (m *Model) References() []api.SchemaReference { return m.references }
+
+// ReferencedBy returns the versions importing the displayed one and whether
+// they have been loaded.
+func (m *Model) ReferencedBy() ([]api.SubjectVersion, bool) {
+	return m.referencedBy, m.referencedByLoaded
+}
+
 func (m *Model) SetStatus(msg string) {
 	m.statusMsg = msg
 	m.statusAt = time.Now()
@@ -310,14 +440,29 @@ func (p *SchemaDetailPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.loading = false
 		if msg.Err != nil {
 			m.setContent(friendlySchemaError(msg.Err))
-		} else {
-			m.version = msg.Version
-			m.setContent(msg.Content)
-			m.loadedAt = time.Now()
-			if msg.Version > 0 {
-				m.contentCache[msg.Version] = msg.Content
-			}
+			return p, nil
+		}
+		m.version = msg.Version
+		m.setContent(msg.Content)
+		m.references = msg.References
+		m.loadedAt = time.Now()
+		if msg.Version > 0 {
+			m.contentCache[msg.Version] = msg.Content
+		}
+		m.referencedBy, m.referencedByLoaded = nil, false
+		return p, m.loadReferencedByCmd()
+
+	case SchemaReferencedByLoadedMsg:
+		// Drop results for a version that is no longer displayed.
+		if msg.Version != m.version {
+			return p, nil
 		}
+		m.referencedByLoaded = true
+		if msg.Err != nil {
+			m.referencedBy = nil
+			return p, core.NotifyError("Referenced by", msg.Err)
+		}
+		m.referencedBy = msg.Subjects
 		return p, nil
 
 	case SchemaVersionsLoadedMsg:
@@ -325,6 +470,7 @@ func (p *SchemaDetailPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return p, core.NotifyError("Schema versions", msg.Err)
 		}
 		m.versions = msg.Versions
+		m.deletedVersions = msg.Deleted
 		m.versionsLoaded = true
 		// Default the cursor to the newest version.
 		m.versionCursor = 0
@@ -334,6 +480,9 @@ func (p *SchemaDetailPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.compat = msg.Level
 		m.compatSpecific = msg.Specific
 		m.compatLoaded = msg.Level != ""
+		m.registryMode = msg.Mode
+		m.modeSpecific = msg.ModeSpecific
+		m.modeLoaded = msg.Mode != ""
 		m.topic = msg.Topic
 		return p, nil
 
@@ -363,6 +512,30 @@ func (p *SchemaDetailPageModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			core.NewNotification(core.StatusSuccess, "Compatibility", "Level set to "+string(msg.Level)),
 		)
 
+	case SchemaModeSetResultMsg:
+		if msg.Err != nil {
+			return p, core.NotifyError("Set mode", msg.Err)
+		}
+		m.mode = modeContent
+		return p, tea.Batch(
+			m.loadMetaCmd(),
+			core.NewNotification(core.StatusSuccess, "Mode", "Mode set to "+string(msg.Mode)),
+		)
+
+	case SchemaRestoreResultMsg:
+		return p, p.handleRestoreResult(msg)
+
+	case SchemaMatrixResultMsg:
+		if msg.Seq != m.matrixSeq {
+			return p, nil
+		}
+		m.matrixLoading = false
+		m.matrix = msg
+		if msg.Err != nil {
+			return p, core.NotifyError("Compatibility matrix", msg.Err)
+		}
+		return p, nil
+
 	case tea.KeyMsg:
 		// 't' navigates to the associated topic (SR-14). Handle it at the page
 		// level so it overrides the framework's sidebar-toggle default, but only
@@ -395,8 +568,8 @@ func (p *SchemaDetailPageModel) GetTitle() string {
 func (p *SchemaDetailPageModel) GetHelp() []key.Binding {
 	km := NewSchemaDetailKeyMap()
 	return []key.Binding{
-		km.Versions, km.Diff, km.Register, km.CheckCompat,
-		km.Compatibility, km.DeleteSubject, km.DeleteVersion,
+		km.Versions, km.Diff, km.References, km.Register, km.CheckCompat,
+		km.Matrix, km.Compatibility, km.Mode, km.DeleteSubject, km.DeleteVersion,
 		km.Topic, km.Copy, km.Back, km.Quit,
 	}
 }
@@ -23,9 +23,12 @@ import (
 type SchemaDetailKeyMap struct {
 	Versions      key.Binding
 	Diff          key.Binding
+	References    key.Binding
 	Register      key.Binding
 	CheckCompat   key.Binding
+	Matrix        key.Binding
 	Compatibility key.Binding
+	Mode          key.Binding
 	DeleteSubject key.Binding
 	DeleteVersion key.Binding
 	Topic         key.Binding
@@ -38,9 +41,12 @@ func NewSchemaDetailKeyMap() SchemaDetailKeyMap {
 	return SchemaDetailKeyMap{
 		Versions:      key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "versions")),
 		Diff:          key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "diff")),
+		References:    key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "references")),
 		Register:      key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "register version")),
 		CheckCompat:   key.NewBinding(key.WithKeys("ctrl+k"), key.WithHelp("ctrl+k", "check compat")),
+		Matrix:        key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "compat matrix")),
 		Compatibility: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "compatibility")),
+		Mode:          key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "mode")),
 		DeleteSubject: key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "delete subject")),
 		DeleteVersion: key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete version")),
 		Topic:         key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "open topic")),
@@ -56,8 +62,8 @@ func (k SchemaDetailKeyMap) ShortHelp() []key.Binding {
 
 func (k SchemaDetailKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Versions, k.Diff, k.Register, k.CheckCompat},
-		{k.Compatibility, k.DeleteSubject, k.DeleteVersion, k.Topic},
+		{k.Versions, k.Diff, k.References, k.Register, k.CheckCompat, k.Matrix},
+		{k.Compatibility, k.Mode, k.DeleteSubject, k.DeleteVersion, k.Topic},
 		{k.Copy, k.Back, k.Quit},
 	}
 }
@@ -102,6 +108,12 @@ func (p *SchemaDetailContentProvider) RenderContent(width, height int) string {
 		body = renderRegister(m, width, height)
 	case modePicker:
 		body = renderPicker(m, width, height)
+	case modeReferences:
+		body = renderReferences(m, width, height)
+	case modeModePicker:
+		body = renderModePicker(m, width, height)
+	case modeMatrix:
+		body = renderMatrix(m, width, height)
 	default:
 		m.viewer.SetDimensions(width, height-1)
 		body = zone.Mark("schema-content", m.viewer.View())
@@ -137,6 +149,12 @@ func (p *SchemaDetailContentProvider) HandleContentUpdate(msg tea.Msg) tea.Cmd {
 			return handleRegisterKey(m, msg)
 		case modePicker:
 			return handlePickerKey(m, msg)
+		case modeReferences:
+			return handleReferencesKey(m, msg)
+		case modeModePicker:
+			return handleModePickerKey(m, msg)
+		case modeMatrix:
+			return handleMatrixKey(m, msg)
 		default:
 			return handleContentKey(m, msg)
 		}
@@ -158,6 +176,8 @@ func handleContentKey(m *Model, msg tea.KeyMsg) tea.Cmd {
 		return nil
 	case "v":
 		return m.enterVersions()
+	case "R":
+		return m.enterReferences()
 	case "d":
 		return m.enterDiffFromContent()
 	case "r":
@@ -166,9 +186,14 @@ func handleContentKey(m *Model, msg tea.KeyMsg) tea.Cmd {
 	case "c":
 		m.enterPicker()
 		return nil
+	case "M":
+		m.enterModePicker()
+		return nil
 	case "ctrl+k":
 		m.enterRegister()
 		return m.checkOnlyCmd()
+	case "T":
+		return m.enterMatrixForVersion()
 	case "D":
 		return m.confirmDeleteSubjectCmd()
 	default:
@@ -186,7 +211,9 @@ func (p *SchemaDetailContentProvider) InitContent() tea.Cmd {
 // framework must not intercept keystrokes as app-level hotkeys.
 func (p *SchemaDetailContentProvider) IsInputMode() bool {
 	return p.model.mode == modeRegister || p.model.mode == modePicker ||
-		p.model.mode == modeVersions || p.model.mode == modeDiff
+		p.model.mode == modeVersions || p.model.mode == modeDiff ||
+		p.model.mode == modeReferences || p.model.mode == modeModePicker ||
+		p.model.mode == modeMatrix
 }
 
 func (p *SchemaDetailContentProvider) GetContentSize(width int) int {
@@ -262,6 +289,19 @@ func (s *SchemaMetadataSidebarSection) RenderItems(maxItems, width int) []provid
 		items = append(items, providers.SidebarItem{Text: "Compatibility", Value: val})
 	}
 
+	// Registry mode: annotate "(global)" when inherited.
+	if mode, specific, loaded := m.RegistryMode(); loaded {
+		val := string(mode)
+		if !specific {
+			val += " (global)"
+		}
+		item := providers.SidebarItem{Text: "Mode", Value: val}
+		if mode != api.SchemaModeReadWrite {
+			item.Status = "warning"
+		}
+		items = append(items, item)
+	}
+
 	// Associated topic (SR-14): show only when a topic matched.
 	if m.AssociatedTopic() != "" {
 		items = append(items, providers.SidebarItem{
@@ -269,6 +309,14 @@ func (s *SchemaMetadataSidebarSection) RenderItems(maxItems, width int) []provid
 		})
 	}
 
+	// References: imports of this version and the versions importing it.
+	if refs := m.References(); len(refs) > 0 {
+		items = append(items, providers.SidebarItem{Text: "References", Value: fmt.Sprintf("%d (R)", len(refs))})
+	}
+	if by, loaded := m.ReferencedBy(); loaded && len(by) > 0 {
+		items = append(items, providers.SidebarItem{Text: "Referenced by", Value: fmt.Sprintf("%d (R)", len(by))})
+	}
+
 	if !m.IsLoading() && m.GetContent() != "" {
 		items = append(items, providers.SidebarItem{
 			Text:  "Size",
@@ -1,12 +1,16 @@
 package schemadetail
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/datasource/mock"
 	"github.com/Benny93/kafui/pkg/ui/components/editor"
 	"github.com/Benny93/kafui/pkg/ui/core"
+	mainpage "github.com/Benny93/kafui/pkg/ui/pages/main"
+	"github.com/Benny93/kafui/pkg/ui/shared/schemadiff"
+	"github.com/Benny93/kafui/pkg/ui/template/ui/providers"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
@@ -16,13 +20,16 @@ import (
 // spyDS wraps the full mock datasource and records the schema mutation calls.
 type spyDS struct {
 	*mock.KafkaDataSourceMock
-	callLog       []string
-	checkCalls    int
-	registerCalls int
-	delSubject    []string
-	delVersion    [][2]int // {version, permanentFlag(0/1)}
-	setSubject    []api.CompatibilityLevel
-	topicNames    []string
+	callLog        []string
+	checkCalls     int
+	registerCalls  int
+	registeredRefs []api.SchemaReference
+	delSubject     []string
+	delVersion     [][2]int // {version, permanentFlag(0/1)}
+	setSubject     []api.CompatibilityLevel
+	setMode        []api.SchemaMode
+	restored       []int
+	topicNames     []string
 }
 
 func newSpy() *spyDS {
@@ -31,16 +38,17 @@ func newSpy() *spyDS {
 	return &spyDS{KafkaDataSourceMock: m}
 }
 
-func (s *spyDS) CheckSchemaCompatibility(subject, text, typ string) (bool, []string, error) {
+func (s *spyDS) CheckSchemaCompatibility(subject, text, typ string, refs []api.SchemaReference) (bool, []string, error) {
 	s.callLog = append(s.callLog, "check")
 	s.checkCalls++
-	return s.KafkaDataSourceMock.CheckSchemaCompatibility(subject, text, typ)
+	return s.KafkaDataSourceMock.CheckSchemaCompatibility(subject, text, typ, refs)
 }
 
-func (s *spyDS) RegisterSchema(subject, text, typ string) (api.Schema, error) {
+func (s *spyDS) RegisterSchema(subject, text, typ string, refs []api.SchemaReference) (api.Schema, error) {
 	s.callLog = append(s.callLog, "register")
 	s.registerCalls++
-	return s.KafkaDataSourceMock.RegisterSchema(subject, text, typ)
+	s.registeredRefs = refs
+	return s.KafkaDataSourceMock.RegisterSchema(subject, text, typ, refs)
 }
 
 func (s *spyDS) DeleteSubject(subject string, permanent bool) ([]int, error) {
@@ -62,6 +70,16 @@ func (s *spyDS) SetSubjectCompatibility(subject string, level api.CompatibilityL
 	return s.KafkaDataSourceMock.SetSubjectCompatibility(subject, level)
 }
 
+func (s *spyDS) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	s.setMode = append(s.setMode, mode)
+	return s.KafkaDataSourceMock.SetSubjectMode(subject, mode)
+}
+
+func (s *spyDS) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	s.restored = append(s.restored, version)
+	return s.KafkaDataSourceMock.RestoreSchemaVersion(subject, version)
+}
+
 func (s *spyDS) GetTopicNames() ([]string, error) {
 	if s.topicNames != nil {
 		return s.topicNames, nil
@@ -183,6 +201,43 @@ func TestDiffVersionCycling(t *testing.T) {
 	assert.Equal(t, 2, m.diffRight)
 }
 
+func TestSemanticDiffClassifiesChanges(t *testing.T) {
+	m := newTestModel(newSpy(), "orders-value", "AVRO")
+	m.versions, _ = m.dataSource.GetSchemaVersions("orders-value")
+	m.versionsLoaded = true
+	page := &SchemaDetailPageModel{model: m}
+	for _, msg := range drainBatch(m.enterDiff(2, 3)) {
+		page.Update(msg)
+	}
+
+	handleDiffKey(m, keyRunes("s"))
+	require.True(t, m.diffSemantic)
+	require.NoError(t, m.semanticErr)
+	assert.Equal(t, "NONE", m.semantic.Compatibility())
+	var added bool
+	for _, c := range m.semantic.Changes {
+		if c.Kind == schemadiff.FieldAdded && c.Path == "OrderCreatedEvent.createdAt" {
+			added = true
+			assert.False(t, c.Backward, "no default: v3 readers cannot read v2 data")
+			assert.True(t, c.Forward)
+		}
+	}
+	assert.True(t, added)
+	out := renderDiff(m, 120, 20)
+	assert.Contains(t, out, "createdAt")
+	assert.Contains(t, out, "s text diff")
+
+	// Changing a version recomputes the changes; v2 → v2 has none.
+	handleDiffKey(m, keyRunes("l"))
+	handleDiffKey(m, keyRunes("["))
+	assert.Equal(t, 2, m.diffRight)
+	assert.Empty(t, m.semantic.Changes)
+	assert.Contains(t, renderDiff(m, 120, 20), "No structural changes.")
+
+	handleDiffKey(m, keyRunes("s"))
+	assert.False(t, m.diffSemantic)
+}
+
 // ─── SR-16/17: register + compatibility check ───────────────────────────────
 
 func TestRegisterChecksThenRegistersWhenCompatible(t *testing.T) {
@@ -254,6 +309,75 @@ func TestCheckOnlyNeverRegisters(t *testing.T) {
 	assert.Equal(t, 1, spy.checkCalls)
 }
 
+// ─── compatibility matrix ────────────────────────────────────────────────────
+
+func TestMatrixChecksCandidateAgainstEveryVersion(t *testing.T) {
+	spy := newSpy()
+	require.NoError(t, spy.SetSubjectCompatibility("orders-value", api.CompatibilityBackwardTransitive))
+	m := newTestModel(spy, "orders-value", "AVRO")
+	m.enterRegister()
+	m.editor.SetValue(`{"INCOMPATIBLE":true}`)
+
+	msg, ok := run(handleRegisterKey(m, tea.KeyMsg{Type: tea.KeyCtrlT})).(SchemaMatrixResultMsg)
+	require.True(t, ok)
+	assert.Equal(t, modeMatrix, m.mode)
+	page := &SchemaDetailPageModel{model: m}
+	page.Update(msg)
+	require.NoError(t, m.matrix.Err)
+	require.Len(t, m.matrix.Rows, 3)
+	for i, row := range m.matrix.Rows {
+		assert.Equal(t, 3-i, row.Version, "newest first")
+		assert.True(t, row.Enforced, "transitive levels check every version")
+		assert.False(t, row.Compatible)
+		assert.Contains(t, row.Messages, fmt.Sprintf("incompatible with version %d", row.Version))
+	}
+	assert.Equal(t, []int{3, 2, 1}, m.matrix.Failing())
+	out := renderMatrix(m, 120, 30)
+	assert.Contains(t, out, "fails BACKWARD_TRANSITIVE")
+	assert.Contains(t, out, "incompatible with version 1")
+
+	handleMatrixKey(m, keyRunes("esc"))
+	assert.Equal(t, modeRegister, m.mode, "esc returns to the editor")
+}
+
+func TestMatrixOfDisplayedVersionHonoursLevel(t *testing.T) {
+	m := newTestModel(newSpy(), "orders-value", "AVRO")
+	m.version, m.content = 3, `{"INCOMPATIBLE":true}`
+
+	msg := run(handleContentKey(m, keyRunes("T"))).(SchemaMatrixResultMsg)
+	require.NoError(t, msg.Err)
+	assert.Equal(t, api.CompatibilityBackward, msg.Level, "inherited from the global level")
+	require.Len(t, msg.Rows, 2, "only versions before v3")
+	assert.True(t, msg.Rows[0].Enforced)
+	assert.Equal(t, 2, msg.Rows[0].Version)
+	assert.False(t, msg.Rows[1].Enforced, "BACKWARD only checks the latest version")
+	assert.Equal(t, []int{2}, msg.Failing())
+
+	require.NoError(t, m.dataSource.SetSubjectCompatibility("orders-value", api.CompatibilityNone))
+	msg = run(m.enterMatrixForVersion()).(SchemaMatrixResultMsg)
+	assert.Empty(t, msg.Failing(), "NONE enforces no version")
+	m.matrixLoading, m.matrix = false, msg
+	assert.Contains(t, renderMatrix(m, 120, 30), "passes NONE")
+}
+
+func TestMatrixDropsResultOfEarlierCheck(t *testing.T) {
+	m := newTestModel(newSpy(), "orders-value", "AVRO")
+	m.version, m.content = 3, `{"type":"string"}`
+	page := &SchemaDetailPageModel{model: m}
+
+	first := handleContentKey(m, keyRunes("T"))
+	handleMatrixKey(m, keyRunes("esc"))
+	m.enterRegister()
+	m.editor.SetValue(`{"INCOMPATIBLE":true}`)
+	second := handleRegisterKey(m, tea.KeyMsg{Type: tea.KeyCtrlT})
+
+	page.Update(run(second))
+	page.Update(run(first))
+	assert.Equal(t, "candidate", m.matrixCandidate)
+	assert.False(t, m.matrixLoading)
+	require.Len(t, m.matrix.Rows, 3, "the candidate is checked against every version, not only those before v3")
+}
+
 // ─── SR-18: delete subject/version ───────────────────────────────────────────
 
 func TestDeleteSubjectConfirmationAndCall(t *testing.T) {
@@ -316,6 +440,75 @@ func TestCompatibilityPickerSetsSubjectLevel(t *testing.T) {
 	assert.Equal(t, []api.CompatibilityLevel{target}, spy.setSubject)
 }
 
+// ─── Registry mode and soft-deleted versions ─────────────────────────────────
+
+func TestModePickerSetsSubjectMode(t *testing.T) {
+	spy := newSpy()
+	m := newTestModel(spy, "orders-value", "AVRO")
+	meta, ok := run(m.loadMetaCmd()).(SchemaMetaLoadedMsg)
+	require.True(t, ok)
+	assert.Equal(t, api.SchemaModeReadWrite, meta.Mode)
+	assert.False(t, meta.ModeSpecific)
+	m.registryMode = meta.Mode
+
+	m.enterModePicker()
+	assert.Equal(t, modeModePicker, m.mode)
+	assert.Equal(t, api.SchemaModeReadWrite, m.SelectedMode(), "cursor starts on the current mode")
+	for m.SelectedMode() != api.SchemaModeReadOnly {
+		handleModePickerKey(m, tea.KeyMsg{Type: tea.KeyDown})
+	}
+	confirm, ok := run(handleModePickerKey(m, tea.KeyMsg{Type: tea.KeyEnter})).(core.ShowConfirmMsg)
+	require.True(t, ok)
+	assert.True(t, confirm.Danger)
+	assert.Empty(t, spy.setMode)
+
+	res, ok := confirm.OnConfirm().(SchemaModeSetResultMsg)
+	require.True(t, ok)
+	assert.NoError(t, res.Err)
+	assert.Equal(t, []api.SchemaMode{api.SchemaModeReadOnly}, spy.setMode)
+
+	m.registryMode, m.modeSpecific, m.modeLoaded = api.SchemaModeReadOnly, true, true
+	var mode providers.SidebarItem
+	for _, it := range NewSchemaMetadataSidebarSection(m).RenderItems(10, 30) {
+		if it.Text == "Mode" {
+			mode = it
+		}
+	}
+	assert.Equal(t, "READONLY", mode.Value)
+	assert.Equal(t, "warning", mode.Status)
+}
+
+func TestVersionListRestoresDeletedVersion(t *testing.T) {
+	spy := newSpy()
+	require.NoError(t, spy.KafkaDataSourceMock.DeleteSchemaVersion("payments-value", 1, false))
+	m := newTestModel(spy, "payments-value", "AVRO")
+
+	msg, ok := run(m.enterVersions()).(SchemaVersionsLoadedMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.Err)
+	require.Len(t, msg.Versions, 1)
+	require.Len(t, msg.Deleted, 1)
+	m.versions, m.deletedVersions, m.versionsLoaded = msg.Versions, msg.Deleted, true
+
+	out := renderVersionList(m, 100, 20)
+	assert.Contains(t, out, "(deleted)")
+	assert.Contains(t, out, "u restore deleted")
+
+	// Deleted versions follow the live ones; enter/x do nothing on them.
+	handleVersionsKey(m, tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 0, m.SelectedVersion())
+	assert.Nil(t, handleVersionsKey(m, keyRunes("x")))
+
+	confirm, ok := run(handleVersionsKey(m, keyRunes("u"))).(core.ShowConfirmMsg)
+	require.True(t, ok)
+	assert.Empty(t, spy.restored)
+	res, ok := confirm.OnConfirm().(SchemaRestoreResultMsg)
+	require.True(t, ok)
+	require.NoError(t, res.Err)
+	assert.Equal(t, []int{1}, spy.restored)
+	assert.Equal(t, 3, res.Schema.Version)
+}
+
 // ─── SR-13/14: sidebar metadata ──────────────────────────────────────────────
 
 func TestSidebarShowsCompatibilityAndTopic(t *testing.T) {
@@ -361,6 +554,104 @@ func TestResolveTopic(t *testing.T) {
 	}
 }
 
+// ─── schema references ─────────────────────────────────────────────────────
+
+func TestContentLoadCarriesReferencesAndLoadsReferencedBy(t *testing.T) {
+	m := newTestModel(newSpy(), "customers-value", "AVRO")
+	page := &SchemaDetailPageModel{model: m}
+
+	_, cmd := page.Update(run(m.LoadContentAsync()))
+	require.Len(t, m.References(), 1)
+	assert.Equal(t, "common-address", m.References()[0].Subject)
+
+	page.Update(run(cmd))
+	by, loaded := m.ReferencedBy()
+	assert.True(t, loaded)
+	assert.Empty(t, by)
+
+	// Stale referenced-by results for another version are ignored.
+	page.Update(SchemaReferencedByLoadedMsg{Version: 7, Subjects: []api.SubjectVersion{{Subject: "x", Version: 1}}})
+	by, _ = m.ReferencedBy()
+	assert.Empty(t, by)
+}
+
+func TestReferencesViewNavigates(t *testing.T) {
+	m := newTestModel(newSpy(), "common-address", "AVRO")
+	m.version = 1
+	m.references = []api.SchemaReference{{Name: "base.avsc", Subject: "base", Version: 2}}
+
+	msg, ok := run(m.enterReferences()).(SchemaReferencedByLoadedMsg)
+	require.True(t, ok)
+	page := &SchemaDetailPageModel{model: m}
+	page.Update(msg)
+	assert.Equal(t, modeReferences, m.mode)
+
+	out := renderReferences(m, 80, 20)
+	assert.Contains(t, out, "Imports (1)")
+	assert.Contains(t, out, "base.avsc → base v2")
+	assert.Contains(t, out, "Referenced by (1)")
+	assert.Contains(t, out, "customers-value v1")
+
+	// Row 0 is the import, row 1 the referrer.
+	handleReferencesKey(m, tea.KeyMsg{Type: tea.KeyDown})
+	change, ok := run(handleReferencesKey(m, tea.KeyMsg{Type: tea.KeyEnter})).(core.PageChangeMsg)
+	require.True(t, ok)
+	assert.Equal(t, "schema_detail:customers-value", change.PageID)
+	item := change.Data.(map[string]interface{})["schemaItem"].(*mainpage.SchemaResourceItem)
+	assert.Equal(t, "customers-value", item.Subject())
+	assert.Equal(t, 1, item.Version())
+
+	handleReferencesKey(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, modeContent, m.mode)
+}
+
+func TestRegisterWithReferences(t *testing.T) {
+	spy := newSpy()
+	m := newTestModel(spy, "customers-value", "AVRO")
+	m.content = `{"old":true}`
+	m.references = []api.SchemaReference{{Name: "com.example.common.Address", Subject: "common-address", Version: 1}}
+	m.enterRegister()
+	assert.Equal(t, "com.example.common.Address=common-address:1", m.refsInput.Value(), "seeded from the current version")
+
+	// Changing only the references is a real change.
+	m.refsInput.SetValue("")
+	assert.Empty(t, m.validateRegister(`{"old":true}`))
+
+	m.toggleRefsFocus()
+	assert.True(t, m.refsFocused)
+	for _, r := range "com.example.common.Address=common-address:1" {
+		handleRegisterKey(m, keyRunes(string(r)))
+	}
+	m.editor.SetValue(`{"new":true}`)
+	msg, ok := run(handleRegisterKey(m, tea.KeyMsg{Type: tea.KeyCtrlS})).(SchemaRegisterResultMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.Err)
+	assert.Equal(t, m.references, spy.registeredRefs)
+
+	t.Run("malformed references never reach the registry", func(t *testing.T) {
+		spy.callLog = nil
+		m.refsInput.SetValue("Address=common-address")
+		assert.IsType(t, core.NotificationMsg{}, run(handleRegisterKey(m, tea.KeyMsg{Type: tea.KeyCtrlS})))
+		assert.Empty(t, spy.callLog)
+	})
+}
+
+func TestParseSchemaReferences(t *testing.T) {
+	refs, err := parseSchemaReferences(" a.proto=common:2, com.x.Y = ns:sub:1 ,")
+	require.NoError(t, err)
+	assert.Equal(t, []api.SchemaReference{
+		{Name: "a.proto", Subject: "common", Version: 2},
+		{Name: "com.x.Y", Subject: "ns:sub", Version: 1},
+	}, refs)
+	assert.Equal(t, "a.proto=common:2, com.x.Y=ns:sub:1", formatSchemaReferences(refs))
+
+	for _, bad := range []string{"noequals", "a=sub", "a=sub:latest", "a=sub:1, a=other:1"} {
+		_, err := parseSchemaReferences(bad)
+		var ve api.SchemaValidationError
+		assert.ErrorAs(t, err, &ve, bad)
+	}
+}
+
 // ─── SR-22: not-configured empty state ───────────────────────────────────────
 
 func TestNotConfiguredEmptyState(t *testing.T) {
@@ -37,7 +37,18 @@ func (m *Model) latestVersion() int {
 	return m.versions[len(m.versions)-1].Version
 }
 
-// SelectedVersion returns the version number highlighted in the list, or 0.
+// selectedDeleted returns the soft-deleted version highlighted in the list.
+// Deleted versions are listed after the live ones.
+func (m *Model) selectedDeleted() (api.SchemaVersion, bool) {
+	i := m.versionCursor - len(m.versions)
+	if i < 0 || i >= len(m.deletedVersions) {
+		return api.SchemaVersion{}, false
+	}
+	return m.deletedVersions[len(m.deletedVersions)-1-i], true
+}
+
+// SelectedVersion returns the live version number highlighted in the list, or
+// 0 (also when a soft-deleted version is highlighted).
 func (m *Model) SelectedVersion() int {
 	dv := m.displayVersions()
 	if m.versionCursor < 0 || m.versionCursor >= len(dv) {
@@ -54,7 +65,7 @@ func handleVersionsKey(m *Model, msg tea.KeyMsg) tea.Cmd {
 			m.versionCursor--
 		}
 	case "down", "j":
-		if m.versionCursor < len(dv)-1 {
+		if m.versionCursor < len(dv)+len(m.deletedVersions)-1 {
 			m.versionCursor++
 		}
 	case "enter":
@@ -62,13 +73,17 @@ func handleVersionsKey(m *Model, msg tea.KeyMsg) tea.Cmd {
 			return m.selectVersion(dv[m.versionCursor])
 		}
 	case "d":
-		if len(m.versions) >= 2 {
-			return m.enterDiff(m.SelectedVersion(), m.latestVersion())
+		if v := m.SelectedVersion(); v > 0 && len(m.versions) >= 2 {
+			return m.enterDiff(v, m.latestVersion())
 		}
 	case "x":
 		if v := m.SelectedVersion(); v > 0 {
 			return m.confirmDeleteVersionCmd(v)
 		}
+	case "u":
+		if v, ok := m.selectedDeleted(); ok {
+			return m.confirmRestoreVersionCmd(v.Version)
+		}
 	case "esc", "backspace":
 		m.mode = modeContent
 	}
@@ -87,6 +102,8 @@ func (m *Model) selectVersion(v api.SchemaVersion) tea.Cmd {
 	return m.LoadContentAsync()
 }
 
+const deletedMarker = "  (deleted)"
+
 func renderVersionList(m *Model, width, height int) string {
 	titleStyle := lipgloss.NewStyle().Foreground(stylesPkg.Primary).Bold(true)
 	cursorStyle := lipgloss.NewStyle().Foreground(stylesPkg.BgBase).Background(stylesPkg.Primary)
@@ -97,7 +114,7 @@ func renderVersionList(m *Model, width, height int) string {
 		return mutedStyle.Render("Loading versions…")
 	}
 	dv := m.displayVersions()
-	if len(dv) == 0 {
+	if len(dv) == 0 && len(m.deletedVersions) == 0 {
 		return mutedStyle.Render("No versions found for this subject.")
 	}
 
@@ -105,24 +122,37 @@ func renderVersionList(m *Model, width, height int) string {
 	b.WriteString(titleStyle.Render(fmt.Sprintf("Versions of %s (%d)", m.subject, len(dv))))
 	b.WriteString("\n\n")
 	latest := m.latestVersion()
-	for i, v := range dv {
+	row := func(i int, v api.SchemaVersion, marker string) {
 		typ := v.SchemaType
 		if typ == "" {
 			typ = "AVRO"
 		}
-		marker := ""
-		if v.Version == latest {
-			marker = "  (latest)"
-		}
 		line := fmt.Sprintf("v%-4d  id:%-6d  %-8s%s", v.Version, v.ID, typ, marker)
-		if i == m.versionCursor {
+		switch {
+		case i == m.versionCursor:
 			b.WriteString(cursorStyle.Render("▸ " + line))
-		} else {
+		case marker == deletedMarker:
+			b.WriteString(mutedStyle.Render("  " + line))
+		default:
 			b.WriteString(rowStyle.Render("  " + line))
 		}
 		b.WriteString("\n")
 	}
+	for i, v := range dv {
+		marker := ""
+		if v.Version == latest {
+			marker = "  (latest)"
+		}
+		row(i, v, marker)
+	}
+	for i := range m.deletedVersions {
+		row(len(dv)+i, m.deletedVersions[len(m.deletedVersions)-1-i], deletedMarker)
+	}
 	b.WriteString("\n")
-	b.WriteString(mutedStyle.Render("↑/↓ select · enter view · d diff vs latest · x delete version · esc back"))
+	hint := "↑/↓ select · enter view · d diff vs latest · x delete version · esc back"
+	if len(m.deletedVersions) > 0 {
+		hint = "↑/↓ select · enter view · d diff vs latest · x delete version · u restore deleted · esc back"
+	}
+	b.WriteString(mutedStyle.Render(hint))
 	return b.String()
 }
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/masking"
@@ -31,6 +30,22 @@ var seekModeOptions = []string{
 	string(api.SeekToTimestamp),
 }
 
+// isolationOptions lists the consumer isolation levels offered in the seek
+// dialog; read_uncommitted (Kafka's default) first.
+var isolationOptions = []string{
+	string(api.IsolationReadUncommitted),
+	string(api.IsolationReadCommitted),
+}
+
+// isolationLabel renders the active isolation level, defaulting an unset one
+// to read_uncommitted.
+func isolationLabel(level api.IsolationLevel) string {
+	if level == "" {
+		return string(api.IsolationReadUncommitted)
+	}
+	return string(level)
+}
+
 // serdeOptions returns the serde choices for the selector: "auto" plus the
 // datasource's registry names (MSG-18/22).
 func (m *Model) serdeOptions() []string {
@@ -66,70 +81,6 @@ func (m *Model) applySerdeConfig(common *core.Common) {
 	}
 }
 
-// parseSeekTime parses an absolute RFC3339 timestamp or a relative duration such
-// as "-1h" / "-30m" (relative to now).
-func parseSeekTime(s string) (time.Time, error) {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return time.Time{}, fmt.Errorf("timestamp is required")
-	}
-	if t, err := time.Parse(time.RFC3339, s); err == nil {
-		return t, nil
-	}
-	if d, err := time.ParseDuration(s); err == nil {
-		return time.Now().Add(d), nil
-	}
-	return time.Time{}, fmt.Errorf("invalid timestamp %q (use RFC3339 like 2006-01-02T15:04:05Z or a relative offset like -1h)", s)
-}
-
-// buildSeekFlags builds ConsumeFlags for the chosen seek mode and value.
-// value carries the offset (integer) or timestamp (RFC3339/relative) for the
-// modes that need one. It returns a validated set of flags (MSG-21).
-func buildSeekFlags(mode, value string, count int, partitions []int32) (api.ConsumeFlags, error) {
-	f := api.ConsumeFlags{
-		LimitMessages: int64(count),
-		Partitions:    partitions,
-	}
-	switch api.SeekMode(mode) {
-	case api.SeekNewest, "":
-		f.Seek = api.SeekNewest
-		f.Follow = false
-		f.Tail = int32(count)
-		f.OffsetFlag = "latest"
-	case api.SeekOldest:
-		f.Seek = api.SeekOldest
-		f.Follow = false
-		f.OffsetFlag = "oldest"
-	case api.SeekLive:
-		f.Seek = api.SeekLive
-		f.Follow = true
-		f.OffsetFlag = "latest"
-		f.LimitMessages = 0 // tailing applies no page limit
-	case api.SeekFromOffset, api.SeekToOffset:
-		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
-		if err != nil {
-			return f, fmt.Errorf("offset must be an integer: %q", value)
-		}
-		f.Seek = api.SeekMode(mode)
-		f.SeekOffset = &n
-		f.OffsetFlag = strconv.FormatInt(n, 10) // legacy numeric offset path
-	case api.SeekFromTimestamp, api.SeekToTimestamp:
-		t, err := parseSeekTime(value)
-		if err != nil {
-			return f, err
-		}
-		f.Seek = api.SeekMode(mode)
-		f.SeekTimestamp = &t
-		f.OffsetFlag = "latest"
-	default:
-		return f, fmt.Errorf("unknown seek mode %q", mode)
-	}
-	if err := f.Validate(); err != nil {
-		return f, err
-	}
-	return f, nil
-}
-
 // buildPartitionFilter parses a comma-separated partition list into a validated
 // []int32. Empty input (or "all") returns nil meaning "all partitions" (MSG-22).
 func buildPartitionFilter(input string, numPartitions int32) ([]int32, error) {
@@ -188,6 +139,7 @@ func (k *Keys) handleShowSeek(model *Model) tea.Cmd {
 	model.seekForm = formpkg.New([]formpkg.Field{
 		{Name: "mode", Label: "Seek mode", Type: formpkg.Select, Options: seekModeOptions, Default: string(model.consumeFlags.Seek)},
 		{Name: "value", Label: "Offset or timestamp (RFC3339 / -1h)", Type: formpkg.Text},
+		{Name: "isolation", Label: "Isolation", Type: formpkg.Select, Options: isolationOptions, Default: isolationLabel(model.consumeFlags.Isolation)},
 	})
 	model.showSeek = true
 	if model.dimensions.Width > 0 {
@@ -213,17 +165,21 @@ func (h *Handlers) handleSeekFormSubmit(model *Model, values map[string]string)
 	model.seekForm = nil
 	model.markRenderDirty()
 
-	flags, err := buildSeekFlags(values["mode"], values["value"], seekPageSize, model.consumeFlags.Partitions)
+	flags, err := api.BuildSeekFlags(values["mode"], values["value"], seekPageSize, model.consumeFlags.Partitions)
 	if err != nil {
 		return model, core.NotifyError("Invalid seek", err)
 	}
-	model.statusMessage = fmt.Sprintf("Seek: %s", flags.Seek)
+	flags.Isolation = api.IsolationLevel(values["isolation"])
+	if flags.Isolation == "" {
+		flags.Isolation = model.consumeFlags.Isolation
+	}
+	model.statusMessage = fmt.Sprintf("Seek: %s • %s", flags.Seek, isolationLabel(flags.Isolation))
 	return model, model.startForFlags(flags)
 }
 
 func (m *Model) renderSeekOverlay(width int) string {
 	return renderFormOverlay("Seek — "+m.topicName,
-		"pick a mode; from/to-offset need an integer, from/to-timestamp an RFC3339 or relative time",
+		"pick a mode; from/to-offset need an integer, from/to-timestamp an RFC3339 or relative time; read_committed hides aborted transactions",
 		m.seekForm)
 }
 
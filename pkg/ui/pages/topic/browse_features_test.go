@@ -2,11 +2,13 @@ package topic
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/serde"
+	"github.com/Benny93/kafui/pkg/ui/core"
 	"github.com/Benny93/kafui/pkg/ui/shared"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,6 +51,98 @@ func TestProduceFormSubmitCallsProduceMessage(t *testing.T) {
 	assert.False(t, m.showProduce, "form closes when keep is false")
 }
 
+// batchCaptureDS captures ProduceBatch arguments.
+type batchCaptureDS struct {
+	*MockDataSource
+	gotRecs []api.ProduceRecord
+	gotOpts api.ProducerOptions
+	single  bool
+}
+
+func (c *batchCaptureDS) ProduceMessage(ctx context.Context, topic string, rec api.ProduceRecord) error {
+	c.single = true
+	return nil
+}
+
+func (c *batchCaptureDS) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	c.gotRecs = recs
+	c.gotOpts = opts
+	return make([]api.ProduceResult, len(recs)), nil
+}
+
+func TestProduceFormSubmitSplitUsesProduceBatch(t *testing.T) {
+	ds := &batchCaptureDS{MockDataSource: &MockDataSource{}}
+	m := NewModel(ds, "orders", api.Topic{NumPartitions: 4})
+	m.showProduce = true
+
+	_, cmd := m.handlers.handleProduceFormSubmit(m, map[string]string{
+		"key": "k", "value": "a|b||c", "split": "|", "partition": "2",
+		"acks": "all", "compression": "zstd", "idempotent": "false", "txnID": "tx-1", "keep": "false",
+	})
+	require.NotNil(t, cmd)
+	msg := cmd()
+
+	assert.False(t, ds.single)
+	require.Len(t, ds.gotRecs, 4)
+	assert.Equal(t, []byte("a"), ds.gotRecs[0].Value)
+	assert.Nil(t, ds.gotRecs[2].Value, "an empty piece is a null value")
+	for _, r := range ds.gotRecs {
+		assert.Equal(t, []byte("k"), r.Key)
+		require.NotNil(t, r.Partition)
+		assert.Equal(t, int32(2), *r.Partition)
+	}
+	assert.Equal(t, api.CompressionZstd, ds.gotOpts.Compression)
+	assert.Equal(t, "tx-1", ds.gotOpts.TransactionalID)
+	n, ok := msg.(core.NotificationMsg)
+	require.True(t, ok)
+	assert.Contains(t, n.Message, "transaction committed")
+}
+
+func TestProduceFormKeepRetainsOptions(t *testing.T) {
+	ds := &batchCaptureDS{MockDataSource: &MockDataSource{}}
+	m := NewModel(ds, "orders", api.Topic{NumPartitions: 4})
+	m.showProduce = true
+
+	submitted := map[string]string{
+		"key": "k", "value": "a|b", "headers": "h=1", "split": "|", "partition": "2",
+		"acks": "leader", "compression": "zstd", "idempotent": "false", "txnID": "", "keep": "true",
+	}
+	_, cmd := m.handlers.handleProduceFormSubmit(m, submitted)
+	require.NotNil(t, cmd)
+	require.True(t, m.showProduce)
+	require.NotNil(t, m.produceForm)
+	assert.Equal(t, submitted, m.produceForm.Values())
+}
+
+// failingBatchDS fails some records of a batch.
+type failingBatchDS struct {
+	*MockDataSource
+}
+
+func (f *failingBatchDS) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	tooLarge := errors.New("message too large")
+	results := []api.ProduceResult{{Offset: 1}, {Err: tooLarge}, {Offset: 2}, {Err: tooLarge}, {Err: errors.New("not leader")}}
+	return results, api.ProduceError{Topic: topic, Reason: "3 of 5 records failed"}
+}
+
+func TestProduceBatchReportsFailedRecords(t *testing.T) {
+	msg := produceBatchCmd(&failingBatchDS{&MockDataSource{}}, "orders", make([]api.ProduceRecord, 5), api.ProducerOptions{})()
+	n, ok := msg.(core.NotificationMsg)
+	require.True(t, ok)
+	assert.Equal(t, core.StatusError, n.Severity)
+	assert.Equal(t, "3 of 5 record(s) failed: #2,#4: message too large; #5: not leader", n.Message)
+}
+
+func TestBuildProduceBatchRejectsBadOptions(t *testing.T) {
+	_, _, err := buildProduceBatch(map[string]string{"value": "v", "acks": "leader", "idempotent": "true"}, 1)
+	assert.Error(t, err)
+
+	recs, opts, err := buildProduceBatch(map[string]string{"value": "v", "acks": "all", "compression": "none"}, 1)
+	require.NoError(t, err)
+	assert.Len(t, recs, 1)
+	assert.True(t, defaultProducerOptions(opts))
+}
+
 func TestSavedFilterRoundTrip(t *testing.T) {
 	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 
@@ -139,7 +233,7 @@ func TestBuildSeekFlags(t *testing.T) {
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			f, err := buildSeekFlags(tc.mode, tc.value, 100, nil)
+			f, err := api.BuildSeekFlags(tc.mode, tc.value, 100, nil)
 			if tc.wantErr {
 				assert.Error(t, err)
 				return
@@ -153,6 +247,28 @@ func TestBuildSeekFlags(t *testing.T) {
 	}
 }
 
+func TestSeekFormIsolationLevel(t *testing.T) {
+	m := NewModel(&MockDataSource{}, "payments", api.Topic{NumPartitions: 1})
+	assert.False(t, m.consumeFlags.ReadCommitted(), "browsing defaults to read_uncommitted")
+
+	m.handlers.handleSeekFormSubmit(m, map[string]string{
+		"mode": string(api.SeekNewest), "isolation": string(api.IsolationReadCommitted),
+	})
+	assert.Equal(t, api.IsolationReadCommitted, m.consumeFlags.Isolation)
+	assert.Contains(t, m.statusMessage, "read_committed")
+
+	// Switching the consume mode keeps the chosen isolation level...
+	m.consumeMode = ModeOldest
+	m.startForMode()
+	assert.Equal(t, api.IsolationReadCommitted, m.consumeFlags.Isolation)
+
+	// ...and so does paging to the next batch.
+	m.messages = []api.Message{{Offset: 10}}
+	next := m.nextBatchFlags()
+	require.NotNil(t, next)
+	assert.Equal(t, api.IsolationReadCommitted, next.Isolation)
+}
+
 func TestBuildPartitionFilter(t *testing.T) {
 	tests := []struct {
 		name    string
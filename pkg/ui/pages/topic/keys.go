@@ -35,6 +35,10 @@ func (k *Keys) HandleKey(model *Model, msg tea.KeyMsg) tea.Cmd {
 	if model.showGroups {
 		return k.handleGroupsOverlayKey(model, msg)
 	}
+	// The mutation form may open on top of the overview (delete records).
+	if model.showMutationForm {
+		return k.handleMutationFormKey(model, msg)
+	}
 	if model.showOverview {
 		return k.handleOverviewKey(model, msg)
 	}
@@ -47,9 +51,6 @@ func (k *Keys) HandleKey(model *Model, msg tea.KeyMsg) tea.Cmd {
 	if model.showSettingsEdit {
 		return k.handleEditFormKey(model, msg)
 	}
-	if model.showMutationForm {
-		return k.handleMutationFormKey(model, msg)
-	}
 	if model.showSeek {
 		return k.handleSeekFormKey(model, msg)
 	}
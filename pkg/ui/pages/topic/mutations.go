@@ -1,12 +1,16 @@
 package topic
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/Benny93/kafui/pkg/api"
 	formpkg "github.com/Benny93/kafui/pkg/ui/components/form"
 	"github.com/Benny93/kafui/pkg/ui/core"
+	"github.com/Benny93/kafui/pkg/ui/shared"
 	stylesPkg "github.com/Benny93/kafui/pkg/ui/styles"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -18,6 +22,7 @@ type mutationKind int
 const (
 	mutIncreasePartitions mutationKind = iota
 	mutReplicationFactor
+	mutDeleteRecords
 )
 
 // topicMutationMsg reports the outcome of a topic mutation (TP-26/TP-27).
@@ -54,6 +59,20 @@ func (k *Keys) handleReplicationFactorDialog(model *Model) tea.Cmd {
 	return k.openMutationForm(model)
 }
 
+// handleDeleteRecordsDialog asks for an offset or time to delete records
+// before, on the given partition by default.
+func (k *Keys) handleDeleteRecordsDialog(model *Model, partition int32) tea.Cmd {
+	if isInternalTopic(model.topicName) {
+		return core.NewNotification(core.StatusWarning, "Not allowed", "Cannot delete records of an internal topic")
+	}
+	model.mutationKind = mutDeleteRecords
+	model.mutationForm = formpkg.New([]formpkg.Field{
+		{Name: "before", Label: "Delete records before (offset, RFC3339 time or relative like -24h)", Type: formpkg.Text, Required: true},
+		{Name: "partitions", Label: "Partitions (comma-separated, empty=all)", Type: formpkg.Text, Default: strconv.Itoa(int(partition))},
+	})
+	return k.openMutationForm(model)
+}
+
 func (k *Keys) openMutationForm(model *Model) tea.Cmd {
 	model.showMutationForm = true
 	if model.dimensions.Width > 0 {
@@ -83,6 +102,9 @@ func (h *Handlers) handleMutationFormSubmit(model *Model, values map[string]stri
 	model.mutationForm = nil
 	model.markRenderDirty()
 
+	if kind == mutDeleteRecords {
+		return model, deleteRecordsConfirm(model, values)
+	}
 	n, err := strconv.Atoi(strings.TrimSpace(values["value"]))
 	if err != nil {
 		return model, core.NotifyError("Invalid input", fmt.Errorf("%q is not a number", values["value"]))
@@ -119,6 +141,57 @@ func (h *Handlers) handleMutationFormSubmit(model *Model, values map[string]stri
 	return model, nil
 }
 
+// deleteRecordsConfirm builds the DeleteRecordsBefore request from the dialog
+// values and asks for confirmation. An integer is an offset applied to every
+// selected partition; anything else must parse as a time.
+func deleteRecordsConfirm(model *Model, values map[string]string) tea.Cmd {
+	parts, err := buildPartitionFilter(values["partitions"], model.topicDetails.NumPartitions)
+	if err != nil {
+		return core.NotifyError("Invalid partitions", err)
+	}
+	req := api.DeleteRecordsRequest{Topic: model.topicName}
+	var what string
+	before := strings.TrimSpace(values["before"])
+	if off, perr := strconv.ParseInt(before, 10, 64); perr == nil {
+		if len(parts) == 0 {
+			for p := int32(0); p < model.topicDetails.NumPartitions; p++ {
+				parts = append(parts, p)
+			}
+		}
+		req.Offsets = make(map[int32]int64, len(parts))
+		for _, p := range parts {
+			req.Offsets[p] = off
+		}
+		what = fmt.Sprintf("below offset %d", off)
+	} else {
+		ts, terr := api.ParseSeekTime(before)
+		if terr != nil {
+			return core.NotifyError("Invalid input", terr)
+		}
+		req.Before, req.Partitions = &ts, parts
+		what = "older than " + ts.Local().Format("2006-01-02 15:04:05")
+	}
+
+	ds := model.dataSource
+	topic := model.topicName
+	return func() tea.Msg {
+		return core.ShowConfirmMsg{
+			Title:        "Delete records",
+			Message:      fmt.Sprintf("Delete the records of %q %s on partition(s) %s? This cannot be undone.", topic, what, partitionLabel(parts)),
+			Danger:       true,
+			ConfirmLabel: "Delete",
+			OnConfirm: func() tea.Msg {
+				res, e := ds.DeleteRecordsBefore(context.Background(), req)
+				var deleted int64
+				for _, r := range res {
+					deleted += r.Deleted
+				}
+				return topicMutationMsg{Action: "Delete records", Detail: fmt.Sprintf("%d record(s) deleted from %s", deleted, topic), Err: e, Refresh: true}
+			},
+		}
+	}
+}
+
 // --- TP-27: header actions ---
 
 // handleClearAllMessages purges all messages after confirmation (ctrl+p).
@@ -163,6 +236,41 @@ func (k *Keys) confirmPurgePartition(model *Model, partition int32) tea.Cmd {
 	}
 }
 
+// confirmLeaderElection looks up the topic's partitions an election of type t
+// would change and asks for confirmation listing them before electing.
+func (k *Keys) confirmLeaderElection(model *Model, t api.ElectionType) tea.Cmd {
+	ds := model.dataSource
+	topic := model.topicName
+	return func() tea.Msg {
+		parts, err := ds.DescribeLeadership(context.Background(), []string{topic})
+		if err != nil {
+			return core.NotificationMsg{Severity: core.StatusError, Title: "Leader election", Message: err.Error()}
+		}
+		cands := shared.ElectionCandidates(parts, t, nil)
+		if len(cands) == 0 {
+			msg := "Every partition is led by its preferred replica"
+			if t == api.ElectUnclean {
+				msg = "Every partition has a leader"
+			}
+			return core.NotificationMsg{Severity: core.StatusInfo, Title: "Leader election", Message: msg}
+		}
+		return core.ShowConfirmMsg{
+			Title:        fmt.Sprintf("Elect %s leaders", t),
+			Message:      shared.ElectionConfirmText(t, cands),
+			Danger:       t == api.ElectUnclean,
+			ConfirmLabel: "Elect",
+			OnConfirm: func() tea.Msg {
+				results, e := ds.ElectLeaders(context.Background(), api.ElectLeadersRequest{Type: t, Partitions: shared.ElectionPartitions(cands)})
+				detail, failed := shared.ElectionOutcome(results)
+				if e == nil && failed {
+					e = errors.New(detail)
+				}
+				return topicMutationMsg{Action: "Leader election", Detail: detail, Err: e, Refresh: true}
+			},
+		}
+	}
+}
+
 // handleRecreateTopic recreates the topic after confirmation (ctrl+r).
 func (k *Keys) handleRecreateTopic(model *Model) tea.Cmd {
 	if isInternalTopic(model.topicName) {
@@ -230,9 +338,13 @@ func (m *Model) renderMutationOverlay(width int) string {
 	header := lipgloss.NewStyle().Foreground(stylesPkg.Primary).Bold(true)
 	title := "Increase partitions"
 	current := fmt.Sprintf("current: %d partitions", m.topicDetails.NumPartitions)
-	if m.mutationKind == mutReplicationFactor {
+	switch m.mutationKind {
+	case mutReplicationFactor:
 		title = "Change replication factor"
 		current = fmt.Sprintf("current: RF %d", m.topicDetails.ReplicationFactor)
+	case mutDeleteRecords:
+		title = "Delete records"
+		current = "records below the offset, or written before the time, are deleted"
 	}
 	var b strings.Builder
 	b.WriteString(header.Render(title + ": " + m.topicName))
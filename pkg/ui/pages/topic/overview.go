@@ -74,6 +74,16 @@ func (k *Keys) handleOverviewKey(model *Model, msg tea.KeyMsg) tea.Cmd {
 		}
 		pid := model.overview.Partitions[model.partitionCursor].ID
 		return k.confirmPurgePartition(model, pid)
+	case "d":
+		// Delete records before an offset or time, from the highlighted partition.
+		if model.overview == nil || model.partitionCursor >= len(model.overview.Partitions) {
+			return nil
+		}
+		return k.handleDeleteRecordsDialog(model, model.overview.Partitions[model.partitionCursor].ID)
+	case "L":
+		return k.confirmLeaderElection(model, api.ElectPreferred)
+	case "U":
+		return k.confirmLeaderElection(model, api.ElectUnclean)
 	}
 	return nil
 }
@@ -153,6 +163,7 @@ func (m *Model) renderOverviewOverlay(width int) string {
 	b.WriteString(fmt.Sprintf("  Replication factor:  %d\n", d.ReplicationFactor))
 	b.WriteString("  Under-replicated:    " + hs.Render(strconv.Itoa(d.UnderReplicatedPartitions)) + "\n")
 	b.WriteString("  ISR / replicas:      " + hs.Render(fmt.Sprintf("%d/%d", d.InSyncReplicas, d.TotalReplicas)) + "\n")
+	b.WriteString("  Leader imbalance:    " + renderNonPreferred(d.Partitions) + "\n")
 	b.WriteString(fmt.Sprintf("  Type:                %s\n", label))
 	b.WriteString(fmt.Sprintf("  Total size:          %s\n", shared.FormatBytes2dp(m.overviewSize)))
 	b.WriteString(fmt.Sprintf("  Cleanup policy:      %s\n", m.cleanupPolicy()))
@@ -172,10 +183,25 @@ func (m *Model) renderOverviewOverlay(width int) string {
 		b.WriteString("\n")
 	}
 	b.WriteString("\n")
-	b.WriteString(muted.Render("↑/↓: select partition • x: clear partition • r: refresh • esc: close"))
+	b.WriteString(muted.Render("↑/↓: select partition • x: clear partition • d: delete records before • L: elect preferred leaders • U: unclean election • r: refresh • esc: close"))
 	return b.String()
 }
 
+// renderNonPreferred counts the partitions whose leader is not their preferred
+// (first) replica, in Warning style when there are any.
+func renderNonPreferred(parts []api.PartitionInfo) string {
+	n := 0
+	for _, p := range parts {
+		if len(p.Replicas) > 0 && p.Leader >= 0 && p.Leader != p.Replicas[0] {
+			n++
+		}
+	}
+	if n == 0 {
+		return "0"
+	}
+	return lipgloss.NewStyle().Foreground(stylesPkg.Warning).Render(strconv.Itoa(n))
+}
+
 // renderReplicas renders the replica list, marking the leader with * and
 // highlighting out-of-sync replicas (in Replicas but not ISR) in Error style.
 func renderReplicas(p api.PartitionInfo) string {
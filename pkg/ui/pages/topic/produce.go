@@ -84,6 +84,39 @@ func buildProduceRecord(values map[string]string, numPartitions int32) (api.Prod
 	return rec, nil
 }
 
+// buildProduceBatch expands the form into the records to send plus the
+// producer options. A non-blank "split" separator turns every piece of the
+// value into its own record sharing key, headers and partition.
+func buildProduceBatch(values map[string]string, numPartitions int32) ([]api.ProduceRecord, api.ProducerOptions, error) {
+	opts := api.ProducerOptions{
+		Acks:            api.ProducerAcks(values["acks"]),
+		Compression:     api.CompressionCodec(values["compression"]),
+		Idempotent:      values["idempotent"] == "true",
+		TransactionalID: strings.TrimSpace(values["txnID"]),
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, opts, err
+	}
+	rec, err := buildProduceRecord(values, numPartitions)
+	if err != nil {
+		return nil, opts, err
+	}
+	sep := values["split"]
+	if sep == "" || rec.Value == nil {
+		return []api.ProduceRecord{rec}, opts, nil
+	}
+	var recs []api.ProduceRecord
+	for _, part := range strings.Split(values["value"], sep) {
+		r := rec
+		r.Value = nil
+		if part != "" {
+			r.Value = []byte(part)
+		}
+		recs = append(recs, r)
+	}
+	return recs, opts, nil
+}
+
 // produceFields builds the produce form fields, pre-filled from prefill (nil for
 // a blank form). Used by both produce (MSG-31) and reproduce (MSG-32).
 func produceFields(prefill *api.Message) []formpkg.Field {
@@ -98,10 +131,37 @@ func produceFields(prefill *api.Message) []formpkg.Field {
 		{Name: "value", Label: "Value (blank = null)", Type: formpkg.Text, Default: value},
 		{Name: "headers", Label: "Headers (k=v,k=v)", Type: formpkg.Text, Default: headers},
 		{Name: "partition", Label: "Partition (auto or index)", Type: formpkg.Text, Default: "auto"},
+		{Name: "split", Label: "Split value on (blank = one record)", Type: formpkg.Text},
+		{Name: "acks", Label: "Acks", Type: formpkg.Select, Options: acksOptions, Default: string(api.AcksAll)},
+		{Name: "compression", Label: "Compression", Type: formpkg.Select, Options: compressionOptions(), Default: string(api.CompressionNone)},
+		{Name: "idempotent", Label: "Idempotent", Type: formpkg.Bool, Default: "false"},
+		{Name: "txnID", Label: "Transactional id (blank = none)", Type: formpkg.Text},
 		{Name: "keep", Label: "Keep contents after send", Type: formpkg.Bool, Default: "false"},
 	}
 }
 
+// keptProduceFields rebuilds the produce form with every submitted value,
+// producer options and split separator included, for "keep contents".
+func keptProduceFields(values map[string]string) []formpkg.Field {
+	fields := produceFields(nil)
+	for i := range fields {
+		if v, ok := values[fields[i].Name]; ok {
+			fields[i].Default = v
+		}
+	}
+	return fields
+}
+
+var acksOptions = []string{string(api.AcksAll), string(api.AcksLeader), string(api.AcksNone)}
+
+func compressionOptions() []string {
+	out := make([]string, 0, len(api.CompressionCodecs))
+	for _, c := range api.CompressionCodecs {
+		out = append(out, string(c))
+	}
+	return out
+}
+
 func (k *Keys) openProduceForm(model *Model, prefill *api.Message) tea.Cmd {
 	model.produceForm = formpkg.New(produceFields(prefill))
 	model.showProduce = true
@@ -144,19 +204,17 @@ func (k *Keys) handleProduceFormKey(model *Model, msg tea.KeyMsg) tea.Cmd {
 }
 
 func (h *Handlers) handleProduceFormSubmit(model *Model, values map[string]string) (tea.Model, tea.Cmd) {
-	rec, err := buildProduceRecord(values, model.topicDetails.NumPartitions)
+	recs, opts, err := buildProduceBatch(values, model.topicDetails.NumPartitions)
 	if err != nil {
 		return model, core.NotifyError("Invalid message", err)
 	}
 	keep := values["keep"] == "true"
 
-	// Close (or reopen blank-preserving) the form.
+	// Close (or reopen with the submitted contents) the form.
 	model.showProduce = false
 	model.produceForm = nil
 	if keep {
-		model.produceForm = formpkg.New(produceFields(&api.Message{
-			Key: values["key"], Value: values["value"], Headers: parseHeaderField(values["headers"]),
-		}))
+		model.produceForm = formpkg.New(keptProduceFields(values))
 		model.showProduce = true
 		if model.dimensions.Width > 0 {
 			model.produceForm.SetDimensions(model.dimensions.Width-4, model.dimensions.Height-6)
@@ -166,6 +224,10 @@ func (h *Handlers) handleProduceFormSubmit(model *Model, values map[string]strin
 
 	ds := model.dataSource
 	topic := model.topicName
+	if len(recs) > 1 || !defaultProducerOptions(opts) {
+		return model, produceBatchCmd(ds, topic, recs, opts)
+	}
+	rec := recs[0]
 	return model, func() tea.Msg {
 		if err := ds.ProduceMessage(context.Background(), topic, rec); err != nil {
 			return core.NotificationMsg{Severity: core.StatusError, Title: "Produce failed", Message: err.Error()}
@@ -174,7 +236,66 @@ func (h *Handlers) handleProduceFormSubmit(model *Model, values map[string]strin
 	}
 }
 
+// defaultProducerOptions reports whether opts match what ProduceMessage uses,
+// so a single record can keep going through it.
+func defaultProducerOptions(opts api.ProducerOptions) bool {
+	return (opts.Acks == "" || opts.Acks == api.AcksAll) &&
+		(opts.Compression == "" || opts.Compression == api.CompressionNone) &&
+		!opts.Idempotent && !opts.Transactional()
+}
+
+// produceBatchCmd sends recs in one ProduceBatch call and summarises the
+// per-record results, naming the failed records when some did not go through.
+func produceBatchCmd(ds api.KafkaDataSource, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) tea.Cmd {
+	return func() tea.Msg {
+		results, err := ds.ProduceBatch(context.Background(), topic, recs, opts)
+		if err != nil {
+			msg := err.Error()
+			if failed := failedRecords(results); failed != "" {
+				msg = failed
+			}
+			return core.NotificationMsg{Severity: core.StatusError, Title: "Produce failed", Message: msg}
+		}
+		msg := fmt.Sprintf("%d record(s) to %s", len(recs), topic)
+		if opts.Transactional() {
+			msg += " (transaction committed)"
+		}
+		if n := len(results); n > 0 && results[n-1].Offset >= 0 {
+			last := results[n-1]
+			msg += fmt.Sprintf(", last at %d/%d", last.Partition, last.Offset)
+		}
+		return core.NotificationMsg{Severity: core.StatusSuccess, Title: "Messages produced", Message: msg}
+	}
+}
+
+// failedRecords describes the records of a batch that were not written, as
+// 1-based record numbers grouped by error, or "" when every record succeeded.
+func failedRecords(results []api.ProduceResult) string {
+	var order []string
+	byErr := map[string][]string{}
+	failed := 0
+	for i, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		failed++
+		e := r.Err.Error()
+		if _, ok := byErr[e]; !ok {
+			order = append(order, e)
+		}
+		byErr[e] = append(byErr[e], "#"+strconv.Itoa(i+1))
+	}
+	if failed == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(order))
+	for _, e := range order {
+		parts = append(parts, strings.Join(byErr[e], ",")+": "+e)
+	}
+	return fmt.Sprintf("%d of %d record(s) failed: %s", failed, len(results), strings.Join(parts, "; "))
+}
+
 func (m *Model) renderProduceOverlay(width int) string {
 	return renderFormOverlay("Produce to "+m.topicName,
-		"blank key/value = null; headers as k=v,k=v; partition 'auto' or index", m.produceForm)
+		"blank key/value = null; headers as k=v,k=v; partition 'auto' or index; split sends one record per piece", m.produceForm)
 }
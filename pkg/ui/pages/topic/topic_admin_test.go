@@ -25,6 +25,8 @@ type spyDataSource struct {
 	purgeCalls       [][2]interface{} // {name, partition}
 	updateCalls      []map[string]*string
 	replicationCalls []int16
+	electCalls       []api.ElectLeadersRequest
+	truncateCalls    []api.DeleteRecordsRequest
 }
 
 func newSpy() *spyDataSource {
@@ -54,6 +56,16 @@ func (s *spyDataSource) ChangeReplicationFactor(name string, f int16) error {
 	return s.KafkaDataSourceMock.ChangeReplicationFactor(name, f)
 }
 
+func (s *spyDataSource) ElectLeaders(ctx context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	s.electCalls = append(s.electCalls, req)
+	return s.KafkaDataSourceMock.ElectLeaders(ctx, req)
+}
+
+func (s *spyDataSource) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	s.truncateCalls = append(s.truncateCalls, req)
+	return s.KafkaDataSourceMock.DeleteRecordsBefore(ctx, req)
+}
+
 // run executes a tea.Cmd to completion and returns its message (nil-safe).
 func run(cmd tea.Cmd) tea.Msg {
 	if cmd == nil {
@@ -255,6 +267,64 @@ func TestPerPartitionPurgePassesHighlightedID(t *testing.T) {
 	assert.Equal(t, int32(2), spy.purgeCalls[0][1], "purge targets the highlighted partition")
 }
 
+func TestOverviewPreferredElectionListsNonPreferredPartitions(t *testing.T) {
+	spy := newSpy()
+	m := NewModel(spy, "user-events", api.Topic{})
+	m.showOverview = true
+
+	cm, ok := run(m.keys.handleOverviewKey(m, keyMsg("L"))).(core.ShowConfirmMsg)
+	require.True(t, ok)
+	assert.Contains(t, cm.Message, "user-events-2")
+	assert.NotContains(t, cm.Message, "user-events-1 ")
+	res := run(cm.OnConfirm).(topicMutationMsg)
+	require.NoError(t, res.Err)
+	assert.True(t, res.Refresh)
+	require.Len(t, spy.electCalls, 1)
+	assert.Equal(t, api.ElectPreferred, spy.electCalls[0].Type)
+	assert.Contains(t, spy.electCalls[0].Partitions, api.TopicPartition{Topic: "user-events", Partition: 2})
+
+	// Balanced now: a hint instead of a modal.
+	_, isNote := run(m.keys.handleOverviewKey(m, keyMsg("L"))).(core.NotificationMsg)
+	assert.True(t, isNote)
+	assert.Len(t, spy.electCalls, 1)
+}
+
+func TestOverviewDeleteRecordsBefore(t *testing.T) {
+	spy := newSpy()
+	m := NewModel(spy, "user-events", api.Topic{NumPartitions: 3})
+	details, err := spy.GetTopicDetails("user-events")
+	require.NoError(t, err)
+	m.showOverview, m.overview, m.partitionCursor = true, &details, 1
+
+	m.keys.handleOverviewKey(m, keyMsg("d"))
+	require.True(t, m.showMutationForm, "the dialog opens on top of the overview")
+
+	_, cmd := m.handlers.handleMutationFormSubmit(m, map[string]string{"before": "0", "partitions": "1"})
+	cm := run(cmd).(core.ShowConfirmMsg)
+	assert.True(t, cm.Danger)
+	assert.Contains(t, cm.Message, "below offset 0 on partition(s) 1")
+	assert.Empty(t, spy.truncateCalls, "no call before confirmation")
+	res := run(cm.OnConfirm).(topicMutationMsg)
+	require.NoError(t, res.Err)
+	assert.True(t, res.Refresh)
+	require.Len(t, spy.truncateCalls, 1)
+	assert.Equal(t, map[int32]int64{1: 0}, spy.truncateCalls[0].Offsets)
+
+	m.keys.handleOverviewKey(m, keyMsg("d"))
+	_, cmd = m.handlers.handleMutationFormSubmit(m, map[string]string{"before": "-24h", "partitions": ""})
+	cm = run(cmd).(core.ShowConfirmMsg)
+	assert.Contains(t, cm.Message, "on partition(s) all")
+	run(cm.OnConfirm)
+	require.Len(t, spy.truncateCalls, 2)
+	assert.NotNil(t, spy.truncateCalls[1].Before)
+	assert.Empty(t, spy.truncateCalls[1].Partitions)
+
+	m.keys.handleOverviewKey(m, keyMsg("d"))
+	_, cmd = m.handlers.handleMutationFormSubmit(m, map[string]string{"before": "yesterday"})
+	_, isConfirm := run(cmd).(core.ShowConfirmMsg)
+	assert.False(t, isConfirm, "an unparseable time is reported, not confirmed")
+}
+
 // --- TP-31: analysis states ---
 
 func TestAnalysisNeverAnalyzedRendersStart(t *testing.T) {
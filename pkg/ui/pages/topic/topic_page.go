@@ -462,6 +462,7 @@ func (m *Model) nextBatchFlags() *api.ConsumeFlags {
 			Tail:          0, // must be 0 so OffsetFlag numeric value is used
 			OffsetFlag:    fmt.Sprintf("%d", start),
 			LimitMessages: batchSize,
+			Isolation:     m.consumeFlags.Isolation,
 		}
 		return &f
 	case ModeOldest:
@@ -475,6 +476,7 @@ func (m *Model) nextBatchFlags() *api.ConsumeFlags {
 			Tail:          0,
 			OffsetFlag:    fmt.Sprintf("%d", max+1),
 			LimitMessages: batchSize,
+			Isolation:     m.consumeFlags.Isolation,
 		}
 		return &f
 	}
@@ -495,7 +497,10 @@ func (m *Model) startForMode() tea.Cmd {
 	m.pendingReset = true
 	m.markRenderDirty()
 
+	// The isolation level is a browse preference, not part of the mode.
+	isolation := m.consumeFlags.Isolation
 	m.consumeFlags = consumeFlagsForMode(m.consumeMode)
+	m.consumeFlags.Isolation = isolation
 
 	// Sort order: Oldest mode shows lowest offset first; all others show newest first.
 	if m.consumeMode == ModeOldest {
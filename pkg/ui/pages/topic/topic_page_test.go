@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	tea "github.com/charmbracelet/bubbletea"
@@ -58,6 +59,10 @@ func (m *MockDataSource) ProduceMessage(ctx context.Context, topic string, rec a
 	return nil
 }
 
+func (m *MockDataSource) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	return nil, nil
+}
+
 func (m *MockDataSource) GetMessageSchemaInfo(keySchemaID, valueSchemaID string) (*api.MessageSchemaInfo, error) {
 	args := m.Called(keySchemaID, valueSchemaID)
 	if args.Get(0) == nil {
@@ -89,10 +94,10 @@ func (m *MockDataSource) GetGlobalCompatibility() (api.CompatibilityLevel, error
 func (m *MockDataSource) GetSubjectCompatibility(subject string) (api.CompatibilityLevel, bool, error) {
 	return "", false, nil
 }
-func (m *MockDataSource) RegisterSchema(subject, schemaText, schemaType string) (api.Schema, error) {
+func (m *MockDataSource) RegisterSchema(subject, schemaText, schemaType string, references []api.SchemaReference) (api.Schema, error) {
 	return api.Schema{}, nil
 }
-func (m *MockDataSource) CheckSchemaCompatibility(subject, schemaText, schemaType string) (bool, []string, error) {
+func (m *MockDataSource) CheckSchemaCompatibility(subject, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
 	return true, nil, nil
 }
 func (m *MockDataSource) DeleteSubject(subject string, permanent bool) ([]int, error) {
@@ -170,6 +175,96 @@ func (m *MockDataSource) AlterReplicaLogDir(brokerID int32, topic string, partit
 	return nil
 }
 func (m *MockDataSource) GetBrokerMetrics(brokerID int32) (string, error) { return "", nil }
+func (m *MockDataSource) DescribeQuorum(ctx context.Context) (api.QuorumInfo, error) { return api.QuorumInfo{}, nil }
+func (m *MockDataSource) PlanReassignment(ctx context.Context, req api.ReassignmentRequest) (api.ReassignmentPlan, error) {
+	return api.ReassignmentPlan{}, nil
+}
+func (m *MockDataSource) ExecuteReassignment(ctx context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	return nil
+}
+func (m *MockDataSource) ListReassignments(ctx context.Context) ([]api.PartitionReassignment, error) { return nil, nil }
+func (m *MockDataSource) CancelReassignments(ctx context.Context, partitions []api.TopicPartition) error { return nil }
+func (m *MockDataSource) ClearReplicationThrottles(ctx context.Context, topics []string) error { return nil }
+func (m *MockDataSource) DescribeLeadership(ctx context.Context, topics []string) ([]api.PartitionLeadership, error) {
+	return nil, nil
+}
+func (m *MockDataSource) ElectLeaders(ctx context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	return nil, nil
+}
+
+func (m *MockDataSource) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	return nil, nil
+}
+
+func (m *MockDataSource) DescribeUserScramCredentials(users []string) ([]api.ScramUser, error) {
+	return nil, nil
+}
+
+func (m *MockDataSource) UpsertUserScramCredentials(upserts []api.ScramCredentialUpsert) error {
+	return nil
+}
+
+func (m *MockDataSource) DeleteUserScramCredentials(deletions []api.ScramCredentialDeletion) error {
+	return nil
+}
+
+func (m *MockDataSource) DescribeDelegationTokens(owners []string) ([]api.DelegationToken, error) {
+	return nil, nil
+}
+
+func (m *MockDataSource) CreateDelegationToken(req api.CreateDelegationTokenRequest) (api.DelegationToken, error) {
+	return api.DelegationToken{}, nil
+}
+
+func (m *MockDataSource) RenewDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (m *MockDataSource) ExpireDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+func (m *MockDataSource) GetSchemaVersion(subject string, version int) (api.SchemaVersion, error) {
+	return api.SchemaVersion{}, nil
+}
+func (m *MockDataSource) GetSchemaReferencedBy(subject string, version int) ([]api.SubjectVersion, error) {
+	return nil, nil
+}
+
+func (m *MockDataSource) GetDeletedSubjects() ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockDataSource) GetDeletedSchemaVersions(subject string) ([]api.SchemaVersion, error) {
+	return nil, nil
+}
+
+func (m *MockDataSource) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (m *MockDataSource) GetGlobalMode() (api.SchemaMode, error) {
+	return "", nil
+}
+
+func (m *MockDataSource) GetSubjectMode(subject string) (api.SchemaMode, bool, error) {
+	return "", false, nil
+}
+
+func (m *MockDataSource) SetGlobalMode(mode api.SchemaMode) error {
+	return nil
+}
+
+func (m *MockDataSource) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	return nil
+}
+
+func (m *MockDataSource) ImportSchema(subject string, version api.SchemaVersion) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (m *MockDataSource) CheckSchemaCompatibilityWithVersion(subject string, version int, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
+	return true, nil, nil
+}
 
 // Topic-administration + analysis stubs (TP-1..TP-11, TP-29/TP-30).
 func (m *MockDataSource) GetTopicConfig(topicName string) ([]api.TopicConfigEntry, error) {
@@ -251,6 +346,12 @@ func (m *MockDataSource) DeleteConsumerGroupOffsets(groupID, topic string) error
 func (m *MockDataSource) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
 	return nil
 }
+func (m *MockDataSource) PlanConsumerGroupOffsetReset(ctx context.Context, req api.OffsetResetRequest) ([]api.PartitionResetPlan, error) {
+	return nil, nil
+}
+func (m *MockDataSource) GetRecordTimestamps(ctx context.Context, topic string, offsets map[int32]int64) (map[int32]time.Time, error) {
+	return nil, nil
+}
 
 func TestNewModel(t *testing.T) {
 	// Create mock data source
@@ -58,6 +58,9 @@ func (t *TopicContentProvider) RenderContent(width, height int) (result string)
 	if t.model.showGroups {
 		return t.model.renderGroupsOverlay(width)
 	}
+	if t.model.showMutationForm {
+		return t.model.renderMutationOverlay(width)
+	}
 	if t.model.showOverview {
 		return t.model.renderOverviewOverlay(width)
 	}
@@ -67,9 +70,6 @@ func (t *TopicContentProvider) RenderContent(width, height int) (result string)
 	if t.model.showSettingsEdit {
 		return t.model.renderEditOverlay(width)
 	}
-	if t.model.showMutationForm {
-		return t.model.renderMutationOverlay(width)
-	}
 	if t.model.showAnalysis {
 		return t.model.renderAnalysisOverlay(width)
 	}
@@ -553,6 +553,18 @@ func (t *ConsumptionControlSection) RenderItems(maxItems, width int) []providers
 		Status: modeStatus,
 	})
 
+	// Isolation: read_committed hides records of aborted transactions.
+	isolationIcon, isolationStatus := "🔓", "muted"
+	if t.model.consumeFlags.ReadCommitted() {
+		isolationIcon, isolationStatus = "🔒", "success"
+	}
+	items = append(items, providers.SidebarItem{
+		Icon:   isolationIcon,
+		Text:   "Isolation",
+		Value:  isolationLabel(t.model.consumeFlags.Isolation),
+		Status: isolationStatus,
+	})
+
 	return items
 }
 
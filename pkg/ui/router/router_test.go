@@ -3,6 +3,7 @@ package router
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Benny93/kafui/pkg/api"
 	"github.com/Benny93/kafui/pkg/ui/core"
@@ -50,6 +51,10 @@ func (m *mockDataSource) ProduceMessage(ctx context.Context, topic string, rec a
 	return nil
 }
 
+func (m *mockDataSource) ProduceBatch(ctx context.Context, topic string, recs []api.ProduceRecord, opts api.ProducerOptions) ([]api.ProduceResult, error) {
+	return nil, nil
+}
+
 func (m *mockDataSource) GetConsumerGroups() ([]api.ConsumerGroup, error) {
 	return []api.ConsumerGroup{}, nil
 }
@@ -99,10 +104,10 @@ func (m *mockDataSource) GetGlobalCompatibility() (api.CompatibilityLevel, error
 func (m *mockDataSource) GetSubjectCompatibility(subject string) (api.CompatibilityLevel, bool, error) {
 	return "", false, nil
 }
-func (m *mockDataSource) RegisterSchema(subject, schemaText, schemaType string) (api.Schema, error) {
+func (m *mockDataSource) RegisterSchema(subject, schemaText, schemaType string, references []api.SchemaReference) (api.Schema, error) {
 	return api.Schema{}, nil
 }
-func (m *mockDataSource) CheckSchemaCompatibility(subject, schemaText, schemaType string) (bool, []string, error) {
+func (m *mockDataSource) CheckSchemaCompatibility(subject, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
 	return true, nil, nil
 }
 func (m *mockDataSource) DeleteSubject(subject string, permanent bool) ([]int, error) {
@@ -180,6 +185,96 @@ func (m *mockDataSource) AlterReplicaLogDir(brokerID int32, topic string, partit
 	return nil
 }
 func (m *mockDataSource) GetBrokerMetrics(brokerID int32) (string, error) { return "", nil }
+func (m *mockDataSource) DescribeQuorum(ctx context.Context) (api.QuorumInfo, error) { return api.QuorumInfo{}, nil }
+func (m *mockDataSource) PlanReassignment(ctx context.Context, req api.ReassignmentRequest) (api.ReassignmentPlan, error) {
+	return api.ReassignmentPlan{}, nil
+}
+func (m *mockDataSource) ExecuteReassignment(ctx context.Context, plan api.ReassignmentPlan, opts api.ReassignmentOptions) error {
+	return nil
+}
+func (m *mockDataSource) ListReassignments(ctx context.Context) ([]api.PartitionReassignment, error) { return nil, nil }
+func (m *mockDataSource) CancelReassignments(ctx context.Context, partitions []api.TopicPartition) error { return nil }
+func (m *mockDataSource) ClearReplicationThrottles(ctx context.Context, topics []string) error { return nil }
+func (m *mockDataSource) DescribeLeadership(ctx context.Context, topics []string) ([]api.PartitionLeadership, error) {
+	return nil, nil
+}
+func (m *mockDataSource) ElectLeaders(ctx context.Context, req api.ElectLeadersRequest) ([]api.ElectionResult, error) {
+	return nil, nil
+}
+
+func (m *mockDataSource) DeleteRecordsBefore(ctx context.Context, req api.DeleteRecordsRequest) ([]api.RecordDeletion, error) {
+	return nil, nil
+}
+
+func (m *mockDataSource) DescribeUserScramCredentials(users []string) ([]api.ScramUser, error) {
+	return nil, nil
+}
+
+func (m *mockDataSource) UpsertUserScramCredentials(upserts []api.ScramCredentialUpsert) error {
+	return nil
+}
+
+func (m *mockDataSource) DeleteUserScramCredentials(deletions []api.ScramCredentialDeletion) error {
+	return nil
+}
+
+func (m *mockDataSource) DescribeDelegationTokens(owners []string) ([]api.DelegationToken, error) {
+	return nil, nil
+}
+
+func (m *mockDataSource) CreateDelegationToken(req api.CreateDelegationTokenRequest) (api.DelegationToken, error) {
+	return api.DelegationToken{}, nil
+}
+
+func (m *mockDataSource) RenewDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (m *mockDataSource) ExpireDelegationToken(token api.DelegationToken, period time.Duration) (time.Time, error) {
+	return time.Time{}, nil
+}
+func (m *mockDataSource) GetSchemaVersion(subject string, version int) (api.SchemaVersion, error) {
+	return api.SchemaVersion{}, nil
+}
+func (m *mockDataSource) GetSchemaReferencedBy(subject string, version int) ([]api.SubjectVersion, error) {
+	return nil, nil
+}
+
+func (m *mockDataSource) GetDeletedSubjects() ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockDataSource) GetDeletedSchemaVersions(subject string) ([]api.SchemaVersion, error) {
+	return nil, nil
+}
+
+func (m *mockDataSource) RestoreSchemaVersion(subject string, version int) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (m *mockDataSource) GetGlobalMode() (api.SchemaMode, error) {
+	return "", nil
+}
+
+func (m *mockDataSource) GetSubjectMode(subject string) (api.SchemaMode, bool, error) {
+	return "", false, nil
+}
+
+func (m *mockDataSource) SetGlobalMode(mode api.SchemaMode) error {
+	return nil
+}
+
+func (m *mockDataSource) SetSubjectMode(subject string, mode api.SchemaMode) error {
+	return nil
+}
+
+func (m *mockDataSource) ImportSchema(subject string, version api.SchemaVersion) (api.Schema, error) {
+	return api.Schema{}, nil
+}
+
+func (m *mockDataSource) CheckSchemaCompatibilityWithVersion(subject string, version int, schemaText, schemaType string, references []api.SchemaReference) (bool, []string, error) {
+	return true, nil, nil
+}
 
 // Topic-administration + analysis stubs (TP-1..TP-11, TP-29/TP-30).
 func (m *mockDataSource) GetTopicConfig(topicName string) ([]api.TopicConfigEntry, error) {
@@ -261,6 +356,12 @@ func (m *mockDataSource) DeleteConsumerGroupOffsets(groupID, topic string) error
 func (m *mockDataSource) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
 	return nil
 }
+func (m *mockDataSource) PlanConsumerGroupOffsetReset(ctx context.Context, req api.OffsetResetRequest) ([]api.PartitionResetPlan, error) {
+	return nil, nil
+}
+func (m *mockDataSource) GetRecordTimestamps(ctx context.Context, topic string, offsets map[int32]int64) (map[int32]time.Time, error) {
+	return nil, nil
+}
 
 type mockResourceItem struct {
 	id      string
@@ -0,0 +1,316 @@
+// Package groupoffsets exports a consumer group's committed offsets (with the
+// timestamp of the record at each offset) as JSON or CSV and imports such an
+// export into a group, possibly on another cluster.
+package groupoffsets
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+)
+
+// Header is the CSV column order written by CSV and required by Parse.
+var Header = []string{"Group", "Topic", "Partition", "Offset", "Timestamp"}
+
+// Entry is one committed partition offset. Timestamp is the time of the record
+// at Offset; nil when the group is caught up (Offset is the log end).
+type Entry struct {
+	Topic     string     `json:"topic"`
+	Partition int32      `json:"partition"`
+	Offset    int64      `json:"offset"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// Export is a group's committed offsets, ordered by topic and partition.
+type Export struct {
+	Group   string  `json:"group"`
+	Offsets []Entry `json:"offsets"`
+}
+
+// ParseError describes a malformed export. Line is 0 for JSON input.
+type ParseError struct {
+	Line   int
+	Reason string
+}
+
+func (e ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("offsets export line %d: %s", e.Line, e.Reason)
+	}
+	return "offsets export: " + e.Reason
+}
+
+// Collect reads the group's committed offsets and looks up the timestamp of
+// the record at each one.
+func Collect(ctx context.Context, ds api.KafkaDataSource, group string) (Export, error) {
+	detail, err := ds.GetConsumerGroupDetail(group)
+	if err != nil {
+		return Export{}, err
+	}
+	byTopic := map[string]map[int32]int64{}
+	for _, po := range detail.TopicOffsets {
+		if po.CommittedOffset == nil {
+			continue
+		}
+		if byTopic[po.Topic] == nil {
+			byTopic[po.Topic] = map[int32]int64{}
+		}
+		byTopic[po.Topic][po.Partition] = *po.CommittedOffset
+	}
+
+	exp := Export{Group: group}
+	for topic, offsets := range byTopic {
+		stamps, err := ds.GetRecordTimestamps(ctx, topic, offsets)
+		if err != nil {
+			return Export{}, fmt.Errorf("reading record timestamps of %q: %w", topic, err)
+		}
+		for p, off := range offsets {
+			e := Entry{Topic: topic, Partition: p, Offset: off}
+			if ts, ok := stamps[p]; ok {
+				ts := ts.UTC()
+				e.Timestamp = &ts
+			}
+			exp.Offsets = append(exp.Offsets, e)
+		}
+	}
+	sortEntries(exp.Offsets)
+	return exp, nil
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Topic != entries[j].Topic {
+			return entries[i].Topic < entries[j].Topic
+		}
+		return entries[i].Partition < entries[j].Partition
+	})
+}
+
+// JSON renders the export as indented JSON.
+func (e Export) JSON() ([]byte, error) {
+	if e.Offsets == nil {
+		e.Offsets = []Entry{}
+	}
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// CSV renders the export as CSV, one row per partition. Timestamps are
+// RFC 3339 and blank for caught-up partitions.
+func (e Export) CSV() string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.Write(Header)
+	for _, o := range e.Offsets {
+		ts := ""
+		if o.Timestamp != nil {
+			ts = o.Timestamp.Format(time.RFC3339Nano)
+		}
+		_ = w.Write([]string{e.Group, o.Topic, strconv.FormatInt(int64(o.Partition), 10), strconv.FormatInt(o.Offset, 10), ts})
+	}
+	w.Flush()
+	return b.String()
+}
+
+// Parse reads an export written by JSON or CSV; the format is
+// detected from the first non-blank character.
+func Parse(data []byte) (Export, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseJSON(trimmed)
+	}
+	return parseCSV(string(data))
+}
+
+func parseJSON(data []byte) (Export, error) {
+	var exp Export
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&exp); err != nil {
+		return Export{}, ParseError{Reason: err.Error()}
+	}
+	for _, o := range exp.Offsets {
+		if err := validateEntry(o); err != nil {
+			return Export{}, ParseError{Reason: err.Error()}
+		}
+	}
+	sortEntries(exp.Offsets)
+	return exp, nil
+}
+
+func parseCSV(s string) (Export, error) {
+	r := csv.NewReader(strings.NewReader(s))
+	r.FieldsPerRecord = -1 // validated manually so the error carries the line
+	var exp Export
+	first := true
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if pe, ok := err.(*csv.ParseError); ok {
+				return Export{}, ParseError{Line: pe.Line, Reason: pe.Err.Error()}
+			}
+			return Export{}, err
+		}
+		line, _ := r.FieldPos(0)
+		if first {
+			first = false
+			if strings.EqualFold(strings.TrimSpace(rec[0]), Header[0]) {
+				continue
+			}
+		}
+		if len(rec) != len(Header) {
+			return Export{}, ParseError{Line: line, Reason: fmt.Sprintf("expected %d columns, got %d", len(Header), len(rec))}
+		}
+		group := strings.TrimSpace(rec[0])
+		if exp.Group == "" {
+			exp.Group = group
+		} else if group != exp.Group {
+			return Export{}, ParseError{Line: line, Reason: fmt.Sprintf("mixed groups %q and %q", exp.Group, group)}
+		}
+		p, err := strconv.ParseInt(strings.TrimSpace(rec[2]), 10, 32)
+		if err != nil {
+			return Export{}, ParseError{Line: line, Reason: fmt.Sprintf("invalid partition %q", rec[2])}
+		}
+		off, err := strconv.ParseInt(strings.TrimSpace(rec[3]), 10, 64)
+		if err != nil {
+			return Export{}, ParseError{Line: line, Reason: fmt.Sprintf("invalid offset %q", rec[3])}
+		}
+		e := Entry{Topic: strings.TrimSpace(rec[1]), Partition: int32(p), Offset: off}
+		if raw := strings.TrimSpace(rec[4]); raw != "" {
+			ts, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return Export{}, ParseError{Line: line, Reason: fmt.Sprintf("invalid timestamp %q (want RFC 3339)", raw)}
+			}
+			e.Timestamp = &ts
+		}
+		if err := validateEntry(e); err != nil {
+			return Export{}, ParseError{Line: line, Reason: err.Error()}
+		}
+		exp.Offsets = append(exp.Offsets, e)
+	}
+	sortEntries(exp.Offsets)
+	return exp, nil
+}
+
+func validateEntry(e Entry) error {
+	switch {
+	case e.Topic == "":
+		return fmt.Errorf("topic must not be blank")
+	case e.Partition < 0:
+		return fmt.Errorf("%s: partition must not be negative", e.Topic)
+	case e.Offset < 0:
+		return fmt.Errorf("%s/%d: offset must not be negative", e.Topic, e.Partition)
+	}
+	return nil
+}
+
+// ImportOptions controls how an export is mapped onto the target group.
+type ImportOptions struct {
+	// Group is the group to commit to; empty uses the exported group.
+	Group string
+	// TranslateByTimestamp resolves each partition to the first offset at or
+	// after the exported record timestamp instead of reusing the offset, for
+	// clusters whose offsets differ (e.g. mirrored topics). Caught-up
+	// partitions (no timestamp) translate to the log end.
+	TranslateByTimestamp bool
+}
+
+// PlanImport resolves the target offsets of an import without committing. The
+// plan is ordered like the export.
+func PlanImport(ctx context.Context, ds api.KafkaDataSource, exp Export, opts ImportOptions) ([]api.PartitionResetPlan, error) {
+	group := opts.Group
+	if group == "" {
+		group = exp.Group
+	}
+	if group == "" {
+		return nil, fmt.Errorf("no target group: the export names none")
+	}
+
+	var plan []api.PartitionResetPlan
+	for _, req := range importRequests(group, exp.Offsets, opts.TranslateByTimestamp) {
+		entries, err := ds.PlanConsumerGroupOffsetReset(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("planning %s: %w", req.Topic, err)
+		}
+		plan = append(plan, entries...)
+	}
+	sort.SliceStable(plan, func(i, j int) bool {
+		if plan[i].Topic != plan[j].Topic {
+			return plan[i].Topic < plan[j].Topic
+		}
+		return plan[i].Partition < plan[j].Partition
+	})
+	return plan, nil
+}
+
+// importRequests groups entries into reset requests: one explicit request per
+// topic, or with translation one timestamp request per partition (each has its
+// own timestamp) and one latest request per topic for caught-up partitions.
+func importRequests(group string, entries []Entry, translate bool) []api.OffsetResetRequest {
+	var reqs []api.OffsetResetRequest
+	explicit := map[string]int{} // topic -> index into reqs
+	latest := map[string]int{}
+	for _, e := range entries {
+		switch {
+		case translate && e.Timestamp != nil:
+			ts := *e.Timestamp
+			reqs = append(reqs, api.OffsetResetRequest{GroupID: group, Topic: e.Topic, Mode: api.OffsetResetTimestamp, Partitions: []int32{e.Partition}, Timestamp: &ts})
+		case translate:
+			i, ok := latest[e.Topic]
+			if !ok {
+				i = len(reqs)
+				latest[e.Topic] = i
+				reqs = append(reqs, api.OffsetResetRequest{GroupID: group, Topic: e.Topic, Mode: api.OffsetResetLatest})
+			}
+			reqs[i].Partitions = append(reqs[i].Partitions, e.Partition)
+		default:
+			i, ok := explicit[e.Topic]
+			if !ok {
+				i = len(reqs)
+				explicit[e.Topic] = i
+				reqs = append(reqs, api.OffsetResetRequest{GroupID: group, Topic: e.Topic, Mode: api.OffsetResetExplicit, PartitionOffsets: map[int32]int64{}})
+			}
+			reqs[i].Partitions = append(reqs[i].Partitions, e.Partition)
+			reqs[i].PartitionOffsets[e.Partition] = e.Offset
+		}
+	}
+	return reqs
+}
+
+// Apply commits the planned targets to group, one reset per topic carrying
+// them as Planned, so exactly the printed plan is committed and audited even
+// if the logs moved since. The group must be inactive on the target cluster.
+func Apply(ctx context.Context, ds api.KafkaDataSource, group string, plan []api.PartitionResetPlan) error {
+	byTopic := map[string]*api.OffsetResetRequest{}
+	var topics []string
+	for _, p := range plan {
+		req, ok := byTopic[p.Topic]
+		if !ok {
+			req = &api.OffsetResetRequest{GroupID: group, Topic: p.Topic, Mode: api.OffsetResetExplicit, PartitionOffsets: map[int32]int64{}, Planned: map[int32]int64{}}
+			byTopic[p.Topic] = req
+			topics = append(topics, p.Topic)
+		}
+		req.Partitions = append(req.Partitions, p.Partition)
+		req.PartitionOffsets[p.Partition] = p.Target
+		req.Planned[p.Partition] = p.Target
+	}
+	for _, topic := range topics {
+		if err := ds.ResetConsumerGroupOffsets(ctx, *byTopic[topic]); err != nil {
+			return fmt.Errorf("committing %s: %w", topic, err)
+		}
+		shared.Log.Info("group offsets import: committed", "group", group, "topic", topic, "partitions", len(byTopic[topic].Partitions))
+	}
+	return nil
+}
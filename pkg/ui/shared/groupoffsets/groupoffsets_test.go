@@ -0,0 +1,118 @@
+package groupoffsets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetRecorder records the reset requests passed to the mock.
+type resetRecorder struct {
+	*mock.KafkaDataSourceMock
+	reqs []api.OffsetResetRequest
+}
+
+func (r *resetRecorder) ResetConsumerGroupOffsets(ctx context.Context, req api.OffsetResetRequest) error {
+	r.reqs = append(r.reqs, req)
+	return r.KafkaDataSourceMock.ResetConsumerGroupOffsets(ctx, req)
+}
+
+func newMockDS() *mock.KafkaDataSourceMock {
+	m := &mock.KafkaDataSourceMock{}
+	m.Init("")
+	return m
+}
+
+func TestCollectAndRoundTrip(t *testing.T) {
+	exp, err := Collect(context.Background(), newMockDS(), "order-processor")
+	require.NoError(t, err)
+	require.Len(t, exp.Offsets, 3)
+	assert.Equal(t, Entry{Topic: "order-events", Partition: 1, Offset: 200, Timestamp: exp.Offsets[1].Timestamp}, exp.Offsets[1])
+	assert.NotNil(t, exp.Offsets[0].Timestamp)
+	assert.Nil(t, exp.Offsets[2].Timestamp, "p2 is caught up: no record at its offset")
+
+	data, err := exp.JSON()
+	require.NoError(t, err)
+	fromJSON, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, exp, fromJSON)
+
+	fromCSV, err := Parse([]byte(exp.CSV()))
+	require.NoError(t, err)
+	assert.Equal(t, exp.Group, fromCSV.Group)
+	require.Len(t, fromCSV.Offsets, 3)
+	assert.True(t, exp.Offsets[0].Timestamp.Equal(*fromCSV.Offsets[0].Timestamp))
+	assert.Nil(t, fromCSV.Offsets[2].Timestamp)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown json field": `{"group":"g","offsets":[{"topic":"t","partition":0,"offset":1,"extra":1}]}`,
+		"negative offset":    `{"group":"g","offsets":[{"topic":"t","partition":0,"offset":-1}]}`,
+		"csv columns":        "g,t,0\n",
+		"csv bad partition":  "g,t,x,1,\n",
+		"csv bad timestamp":  "g,t,0,1,yesterday\n",
+		"csv mixed groups":   "g,t,0,1,\nh,t,1,1,\n",
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(input))
+			var pe ParseError
+			assert.True(t, errors.As(err, &pe), "got %v", err)
+		})
+	}
+}
+
+func TestImportRequests(t *testing.T) {
+	ts := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Topic: "a", Partition: 0, Offset: 5, Timestamp: &ts},
+		{Topic: "a", Partition: 1, Offset: 7},
+		{Topic: "a", Partition: 2, Offset: 9},
+	}
+
+	reqs := importRequests("g", entries, false)
+	require.Len(t, reqs, 1)
+	assert.Equal(t, api.OffsetResetExplicit, reqs[0].Mode)
+	assert.Equal(t, map[int32]int64{0: 5, 1: 7, 2: 9}, reqs[0].PartitionOffsets)
+
+	reqs = importRequests("g", entries, true)
+	require.Len(t, reqs, 2)
+	assert.Equal(t, api.OffsetResetTimestamp, reqs[0].Mode)
+	assert.Equal(t, []int32{0}, reqs[0].Partitions)
+	assert.Equal(t, ts, *reqs[0].Timestamp)
+	assert.Equal(t, api.OffsetResetLatest, reqs[1].Mode)
+	assert.Equal(t, []int32{1, 2}, reqs[1].Partitions)
+}
+
+func TestPlanImportAndApply(t *testing.T) {
+	ds := newMockDS()
+	exp := Export{Group: "order-processor", Offsets: []Entry{
+		{Topic: "inventory-events", Partition: 0, Offset: 42},
+		{Topic: "inventory-events", Partition: 1, Offset: 999},
+	}}
+
+	plan, err := PlanImport(context.Background(), ds, exp, ImportOptions{Group: "inventory-sync"})
+	require.NoError(t, err)
+	require.Len(t, plan, 2)
+	assert.Equal(t, int64(42), plan[0].Target)
+	assert.Equal(t, int64(20), plan[1].Target, "clamped to the target's log end")
+
+	rec := &resetRecorder{KafkaDataSourceMock: ds}
+	require.NoError(t, Apply(context.Background(), rec, "inventory-sync", plan))
+	require.Len(t, rec.reqs, 1)
+	assert.Equal(t, map[int32]int64{0: 42, 1: 20}, rec.reqs[0].Planned, "the printed plan is committed as is")
+	d, err := ds.GetConsumerGroupDetail("inventory-sync")
+	require.NoError(t, err)
+	got := map[int32]int64{}
+	for _, po := range d.TopicOffsets {
+		got[po.Partition] = *po.CommittedOffset
+	}
+	assert.Equal(t, map[int32]int64{0: 42, 1: 20}, got)
+}
@@ -0,0 +1,92 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Benny93/kafui/pkg/api"
+)
+
+// maxElectionLines caps the partitions listed in an election confirmation.
+const maxElectionLines = 10
+
+// ElectionCandidates returns the partitions an election of type t would change:
+// for a preferred election those led by a replica other than the preferred
+// one, for an unclean election the leaderless ones. keep, when non-nil,
+// narrows the set further (e.g. to one broker's partitions).
+func ElectionCandidates(parts []api.PartitionLeadership, t api.ElectionType, keep func(api.PartitionLeadership) bool) []api.PartitionLeadership {
+	var out []api.PartitionLeadership
+	for _, p := range parts {
+		if keep != nil && !keep(p) {
+			continue
+		}
+		if (t == api.ElectUnclean && p.Leaderless()) || (t == api.ElectPreferred && p.NonPreferred()) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ElectionPartitions converts candidates into an ElectLeaders partition list.
+func ElectionPartitions(cands []api.PartitionLeadership) []api.TopicPartition {
+	out := make([]api.TopicPartition, 0, len(cands))
+	for _, p := range cands {
+		out = append(out, api.TopicPartition{Topic: p.Topic, Partition: p.Partition})
+	}
+	return out
+}
+
+// ElectionConfirmText renders the confirmation body for an election over
+// cands, one line per partition with its current and preferred leader.
+func ElectionConfirmText(t api.ElectionType, cands []api.PartitionLeadership) string {
+	var b strings.Builder
+	if t == api.ElectUnclean {
+		fmt.Fprintf(&b, "Elect a leader for %d leaderless partition(s), even from out-of-sync replicas? Records not yet replicated will be lost.\n", len(cands))
+	} else {
+		fmt.Fprintf(&b, "Move leadership back to the preferred replica of %d partition(s)?\n", len(cands))
+	}
+	for i, p := range cands {
+		if i == maxElectionLines {
+			fmt.Fprintf(&b, "\n  … and %d more", len(cands)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s-%d  ", p.Topic, p.Partition)
+		switch {
+		case p.Leaderless():
+			fmt.Fprintf(&b, "no leader, replicas %s", JoinInt32s(p.Replicas))
+		case !p.PreferredInSync():
+			fmt.Fprintf(&b, "leader %d, preferred %d (not in sync, will fail)", p.Leader, p.Preferred())
+		default:
+			fmt.Fprintf(&b, "leader %d → %d", p.Leader, p.Preferred())
+		}
+	}
+	return b.String()
+}
+
+// ElectionOutcome summarizes ElectLeaders results for a notification and
+// reports whether any partition failed.
+func ElectionOutcome(results []api.ElectionResult) (string, bool) {
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s-%d: %v", r.Topic, r.Partition, r.Err))
+		}
+	}
+	n := len(failed)
+	if n == 0 {
+		return fmt.Sprintf("%d partition(s) elected", len(results)), false
+	}
+	if n > 3 {
+		failed = append(failed[:3:3], fmt.Sprintf("… and %d more", n-3))
+	}
+	return fmt.Sprintf("%d of %d partition(s) failed: %s", n, len(results), strings.Join(failed, "; ")), true
+}
+
+// JoinInt32s renders ids as a comma-separated list.
+func JoinInt32s(ids []int32) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,50 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElectionCandidates(t *testing.T) {
+	parts := []api.PartitionLeadership{
+		{Topic: "t", Partition: 0, Leader: 1, Replicas: []int32{1, 2}, ISR: []int32{1, 2}},
+		{Topic: "t", Partition: 1, Leader: 2, Replicas: []int32{1, 2}, ISR: []int32{2}},
+		{Topic: "t", Partition: 2, Leader: -1, Replicas: []int32{3, 1}},
+	}
+	assert.Len(t, ElectionCandidates(parts, api.ElectPreferred, nil), 1)
+	assert.Equal(t, int32(2), ElectionCandidates(parts, api.ElectUnclean, nil)[0].Partition)
+	assert.Empty(t, ElectionCandidates(parts, api.ElectPreferred, func(p api.PartitionLeadership) bool { return p.Preferred() == 3 }))
+
+	text := ElectionConfirmText(api.ElectPreferred, parts[1:2])
+	assert.Contains(t, text, "t-1  leader 2, preferred 1 (not in sync, will fail)")
+	assert.Contains(t, ElectionConfirmText(api.ElectUnclean, parts[2:]), "t-2  no leader, replicas 3,1")
+
+	var many []api.PartitionLeadership
+	for i := int32(0); i < 12; i++ {
+		many = append(many, api.PartitionLeadership{Topic: "t", Partition: i, Leader: 2, Replicas: []int32{1, 2}, ISR: []int32{1, 2}})
+	}
+	text = ElectionConfirmText(api.ElectPreferred, many)
+	assert.Equal(t, maxElectionLines, strings.Count(text, "leader 2 → 1"))
+	assert.Contains(t, text, "… and 2 more")
+}
+
+func TestElectionOutcome(t *testing.T) {
+	msg, failed := ElectionOutcome([]api.ElectionResult{{Topic: "t"}, {Topic: "t", Partition: 1}})
+	assert.False(t, failed)
+	assert.Equal(t, "2 partition(s) elected", msg)
+
+	var results []api.ElectionResult
+	for i := int32(0); i < 5; i++ {
+		results = append(results, api.ElectionResult{Topic: "t", Partition: i, Err: errors.New(fmt.Sprint("boom ", i))})
+	}
+	msg, failed = ElectionOutcome(results)
+	assert.True(t, failed)
+	assert.True(t, strings.HasPrefix(msg, "5 of 5 partition(s) failed: t-0: boom 0"))
+	assert.Contains(t, msg, "… and 2 more")
+	assert.NotContains(t, msg, "boom 3")
+}
@@ -48,7 +48,7 @@ func WriteMessagesCSV(w io.Writer, msgs []api.Message, f CSVFormat) error {
 	rows := make([][]string, 0, len(msgs)+1)
 	rows = append(rows, MessageCSVHeader)
 	for _, m := range msgs {
-		rows = append(rows, messageRow(m))
+		rows = append(rows, MessageCSVRow(m))
 	}
 
 	// Fast path: standard double quotes, no forced quoting, and a line
@@ -71,8 +71,9 @@ func WriteMessagesCSV(w io.Writer, msgs []api.Message, f CSVFormat) error {
 	return writeManualCSV(w, rows, f)
 }
 
-// messageRow builds the CSV cells for a single message.
-func messageRow(m api.Message) []string {
+// MessageCSVRow builds the CSV cells for a single message, aligned with
+// MessageCSVHeader. Streaming writers use it to emit rows one at a time.
+func MessageCSVRow(m api.Message) []string {
 	ts := ""
 	if !m.Timestamp.IsZero() {
 		ts = m.Timestamp.Format(time.RFC3339)
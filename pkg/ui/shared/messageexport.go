@@ -25,10 +25,14 @@ type exportedMessage struct {
 	Timestamp string            `json:"timestamp"`
 }
 
-// ExportMessageJSON writes the message to path as pretty JSON containing
-// key, value, offset, partition, headers (as an object/map name->value), and
-// timestamp (RFC3339). Creates parent dirs as needed.
-func ExportMessageJSON(path string, topic string, m api.Message) error {
+// MarshalMessageJSON renders the message in the export shape: key, value,
+// offset, partition, headers (as an object/map name->value), and timestamp
+// (RFC3339). The output is compact (one line), suitable for JSONL streams.
+func MarshalMessageJSON(m api.Message) ([]byte, error) {
+	return json.Marshal(newExportedMessage(m))
+}
+
+func newExportedMessage(m api.Message) exportedMessage {
 	headers := make(map[string]string, len(m.Headers))
 	for _, h := range m.Headers {
 		headers[h.Key] = h.Value
@@ -37,7 +41,7 @@ func ExportMessageJSON(path string, topic string, m api.Message) error {
 	if !m.Timestamp.IsZero() {
 		ts = m.Timestamp.Format(time.RFC3339)
 	}
-	out := exportedMessage{
+	return exportedMessage{
 		Key:       m.Key,
 		Value:     m.Value,
 		Offset:    m.Offset,
@@ -45,7 +49,12 @@ func ExportMessageJSON(path string, topic string, m api.Message) error {
 		Headers:   headers,
 		Timestamp: ts,
 	}
-	data, err := json.MarshalIndent(out, "", "  ")
+}
+
+// ExportMessageJSON writes the message to path as pretty JSON in the
+// MarshalMessageJSON shape. Creates parent dirs as needed.
+func ExportMessageJSON(path string, topic string, m api.Message) error {
+	data, err := json.MarshalIndent(newExportedMessage(m), "", "  ")
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,247 @@
+package schemabackup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A backup is a registry.json index plus one subjects/<name>.json file per
+// subject, the name query-escaped so any subject is a valid file name:
+//
+//	registry.json            {"formatVersion":1,"globalCompatibility":"BACKWARD"}
+//	subjects/orders-value.json
+//	subjects/common%3Aaddress.json
+//
+// Write lays the files out in a directory, or in a gzipped tar when the path
+// ends in .tar.gz or .tgz. Read accepts either.
+const (
+	indexFile   = "registry.json"
+	subjectsDir = "subjects"
+)
+
+// IsArchive reports whether path names a .tar.gz archive rather than a
+// directory.
+func IsArchive(p string) bool {
+	lower := strings.ToLower(p)
+	return strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// Write stores the backup at p. A directory must be new or empty, so an
+// old backup's subjects never mix into a new one.
+func Write(p string, b Backup) error {
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+	if IsArchive(p) {
+		return writeArchive(p, files)
+	}
+	return writeDir(p, files)
+}
+
+// Read loads a backup written by Write.
+func Read(p string) (Backup, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return Backup{}, err
+	}
+	var files map[string][]byte
+	if info.IsDir() {
+		files, err = readDir(p)
+	} else {
+		files, err = readArchive(p)
+	}
+	if err != nil {
+		return Backup{}, err
+	}
+	return fromFiles(files)
+}
+
+// files renders the backup as slash-separated relative paths.
+func (b Backup) files() (map[string][]byte, error) {
+	files := make(map[string][]byte, len(b.Subjects)+1)
+	index, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files[indexFile] = append(index, '\n')
+	for _, s := range b.Subjects {
+		data, err := json.MarshalIndent(s, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join(subjectsDir, url.QueryEscape(s.Name)+".json")] = append(data, '\n')
+	}
+	return files, nil
+}
+
+// fromFiles parses and validates the files of a backup.
+func fromFiles(files map[string][]byte) (Backup, error) {
+	index, ok := files[indexFile]
+	if !ok {
+		return Backup{}, FormatError{Reason: indexFile + " is missing"}
+	}
+	var b Backup
+	if err := json.Unmarshal(index, &b); err != nil {
+		return Backup{}, FormatError{File: indexFile, Reason: err.Error()}
+	}
+	if b.FormatVersion < 1 || b.FormatVersion > FormatVersion {
+		return Backup{}, FormatError{File: indexFile, Reason: fmt.Sprintf("unsupported format version %d", b.FormatVersion)}
+	}
+
+	seen := map[string]bool{}
+	for name, data := range files {
+		if path.Dir(name) != subjectsDir || path.Ext(name) != ".json" {
+			continue
+		}
+		var s Subject
+		if err := json.Unmarshal(data, &s); err != nil {
+			return Backup{}, FormatError{File: name, Reason: err.Error()}
+		}
+		if err := validateSubject(s, seen); err != nil {
+			return Backup{}, FormatError{File: name, Reason: err.Error()}
+		}
+		b.Subjects = append(b.Subjects, s)
+	}
+	sort.Slice(b.Subjects, func(i, j int) bool { return b.Subjects[i].Name < b.Subjects[j].Name })
+	return b, nil
+}
+
+// validateSubject checks what an import relies on: a unique name and strictly
+// ascending versions that each carry text and an ID.
+func validateSubject(s Subject, seen map[string]bool) error {
+	switch {
+	case strings.TrimSpace(s.Name) == "":
+		return errors.New("subject without a name")
+	case seen[s.Name]:
+		return fmt.Errorf("subject %q is listed more than once", s.Name)
+	case len(s.Versions) == 0:
+		return fmt.Errorf("subject %q has no versions", s.Name)
+	}
+	seen[s.Name] = true
+	last := 0
+	for _, v := range s.Versions {
+		switch {
+		case v.Version <= last:
+			return fmt.Errorf("subject %q: versions must be ascending (version %d after %d)", s.Name, v.Version, last)
+		case v.ID < 1:
+			return fmt.Errorf("subject %q version %d has no schema id", s.Name, v.Version)
+		case v.Schema == "":
+			return fmt.Errorf("subject %q version %d has no schema text", s.Name, v.Version)
+		}
+		last = v.Version
+	}
+	return nil
+}
+
+func writeDir(dir string, files map[string][]byte) error {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("directory %s is not empty", dir)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, subjectsDir), 0o755); err != nil {
+		return err
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readDir(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	index, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if err != nil {
+		return nil, FormatError{Reason: fmt.Sprintf("%s: %v", indexFile, err)}
+	}
+	files[indexFile] = index
+	entries, err := os.ReadDir(filepath.Join(dir, subjectsDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, subjectsDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[path.Join(subjectsDir, e.Name())] = data
+	}
+	return files, nil
+}
+
+func writeArchive(p string, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	now := time.Now()
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name])), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(p, buf.Bytes(), 0o644)
+}
+
+func readArchive(p string) (map[string][]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, FormatError{Reason: fmt.Sprintf("%s is not a .tar.gz archive: %v", p, err)}
+	}
+	defer zr.Close()
+	tr := tar.NewReader(zr)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, FormatError{Reason: err.Error()}
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[path.Clean(hdr.Name)] = data
+	}
+}
@@ -0,0 +1,390 @@
+// Package schemabackup exports a schema registry — every subject with all its
+// live versions, types, references, IDs and compatibility levels — and imports
+// such a backup into another registry. The import keeps each subject's version
+// order and resolves references first; when the target is in IMPORT mode it
+// also keeps the original version numbers and schema IDs.
+package schemabackup
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+)
+
+// FormatVersion is the backup layout written by Write. Read refuses newer
+// layouts.
+const FormatVersion = 1
+
+// Backup is the exported state of a registry.
+type Backup struct {
+	FormatVersion int `json:"formatVersion"`
+	// GlobalCompatibility is the registry-wide level; empty when unknown.
+	GlobalCompatibility api.CompatibilityLevel `json:"globalCompatibility,omitempty"`
+	// Subjects are sorted by name.
+	Subjects []Subject `json:"-"`
+}
+
+// Subject is one exported subject.
+type Subject struct {
+	Name string `json:"subject"`
+	// Compatibility is the subject-specific level; empty when the subject
+	// inherits the global level.
+	Compatibility api.CompatibilityLevel `json:"compatibility,omitempty"`
+	// Versions are ascending and carry text, type, references and ID.
+	Versions []api.SchemaVersion `json:"versions"`
+}
+
+// FormatError describes an unreadable or inconsistent backup. File is empty
+// when the error applies to the whole backup.
+type FormatError struct {
+	File   string
+	Reason string
+}
+
+func (e FormatError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("invalid schema backup: %s", e.Reason)
+	}
+	return fmt.Sprintf("invalid schema backup %s: %s", e.File, e.Reason)
+}
+
+// Export reads every subject of the registry behind ds. Soft-deleted versions
+// are not exported.
+func Export(ds api.KafkaDataSource) (Backup, error) {
+	schemas, err := ds.GetSchemas()
+	if err != nil {
+		return Backup{}, err
+	}
+	global, err := ds.GetGlobalCompatibility()
+	if err != nil {
+		return Backup{}, fmt.Errorf("global compatibility: %w", err)
+	}
+	b := Backup{FormatVersion: FormatVersion, GlobalCompatibility: global}
+	for _, s := range schemas {
+		sub, err := exportSubject(ds, s.Subject)
+		if err != nil {
+			return Backup{}, err
+		}
+		b.Subjects = append(b.Subjects, sub)
+	}
+	sort.Slice(b.Subjects, func(i, j int) bool { return b.Subjects[i].Name < b.Subjects[j].Name })
+	shared.Log.Info("schema backup: exported registry", "subjects", len(b.Subjects))
+	return b, nil
+}
+
+func exportSubject(ds api.KafkaDataSource, name string) (Subject, error) {
+	versions, err := ds.GetSchemaVersions(name)
+	if err != nil {
+		return Subject{}, fmt.Errorf("versions of subject %q: %w", name, err)
+	}
+	sub := Subject{Name: name}
+	for _, v := range versions {
+		sv, err := ds.GetSchemaVersion(name, v.Version)
+		if err != nil {
+			return Subject{}, fmt.Errorf("version %d of subject %q: %w", v.Version, name, err)
+		}
+		sub.Versions = append(sub.Versions, sv)
+	}
+	sort.Slice(sub.Versions, func(i, j int) bool { return sub.Versions[i].Version < sub.Versions[j].Version })
+	level, specific, err := ds.GetSubjectCompatibility(name)
+	if err != nil {
+		return Subject{}, fmt.Errorf("compatibility of subject %q: %w", name, err)
+	}
+	if specific {
+		sub.Compatibility = level
+	}
+	return sub, nil
+}
+
+// Step registers one backed-up version in the target.
+type Step struct {
+	Subject string
+	Version api.SchemaVersion
+	// PreserveID imports the version with its original number and ID; set when
+	// the target subject is in IMPORT mode.
+	PreserveID bool
+	// Existing is the target version already holding the same schema; the step
+	// is then skipped.
+	Existing int
+}
+
+// CompatChange sets a compatibility level. Subject is empty for the global
+// level.
+type CompatChange struct {
+	Subject  string
+	From, To api.CompatibilityLevel
+}
+
+// Plan is what an import changes in the target registry.
+type Plan struct {
+	// Registers are in import order: each subject's versions ascending, and
+	// every referenced version before the versions that import it.
+	Registers []Step
+	// Skipped lists versions whose schema the target subject already holds.
+	Skipped []Step
+	// CompatChanges are applied after all registrations, so the historical
+	// versions are not checked against a level set for the latest one.
+	CompatChanges []CompatChange
+}
+
+// Empty reports whether the target already matches the backup.
+func (p Plan) Empty() bool {
+	return len(p.Registers) == 0 && len(p.CompatChanges) == 0
+}
+
+// PreservesIDs reports whether every registration keeps its version number
+// and schema ID.
+func (p Plan) PreservesIDs() bool {
+	for _, s := range p.Registers {
+		if !s.PreserveID {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanImport compares the backup with the target registry behind ds. It does
+// not mutate the registry — call Plan.Apply for that.
+func PlanImport(ds api.KafkaDataSource, b Backup) (Plan, error) {
+	ordered, err := importOrder(b)
+	if err != nil {
+		return Plan{}, err
+	}
+	type target struct {
+		preserve bool
+		texts    map[string]int // schema type + text → target version
+	}
+	targets := make(map[string]target, len(b.Subjects))
+	for _, sub := range b.Subjects {
+		mode, _, err := ds.GetSubjectMode(sub.Name)
+		if err != nil {
+			return Plan{}, fmt.Errorf("mode of subject %q: %w", sub.Name, err)
+		}
+		texts, err := targetTexts(ds, sub.Name)
+		if err != nil {
+			return Plan{}, err
+		}
+		targets[sub.Name] = target{preserve: mode == api.SchemaModeImport, texts: texts}
+	}
+
+	var plan Plan
+	for _, step := range ordered {
+		t := targets[step.Subject]
+		step.PreserveID = t.preserve
+		if existing, ok := t.texts[textKey(step.Version)]; ok {
+			step.Existing = existing
+			plan.Skipped = append(plan.Skipped, step)
+			continue
+		}
+		plan.Registers = append(plan.Registers, step)
+	}
+
+	if b.GlobalCompatibility != "" {
+		global, err := ds.GetGlobalCompatibility()
+		if err != nil {
+			return Plan{}, fmt.Errorf("global compatibility: %w", err)
+		}
+		if global != b.GlobalCompatibility {
+			plan.CompatChanges = append(plan.CompatChanges, CompatChange{From: global, To: b.GlobalCompatibility})
+		}
+	}
+	for _, sub := range b.Subjects {
+		if sub.Compatibility == "" {
+			continue
+		}
+		// A subject that does not exist yet has no level to compare.
+		level, specific, err := ds.GetSubjectCompatibility(sub.Name)
+		if err != nil || !specific {
+			level = ""
+		}
+		if level != sub.Compatibility {
+			plan.CompatChanges = append(plan.CompatChanges, CompatChange{Subject: sub.Name, From: level, To: sub.Compatibility})
+		}
+	}
+	return plan, nil
+}
+
+// targetTexts indexes the target subject's versions by type and text. A
+// missing subject has none.
+func targetTexts(ds api.KafkaDataSource, subject string) (map[string]int, error) {
+	texts := map[string]int{}
+	versions, err := ds.GetSchemaVersions(subject)
+	var notFound api.SubjectNotFoundError
+	if errors.As(err, &notFound) {
+		return texts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("versions of target subject %q: %w", subject, err)
+	}
+	for _, v := range versions {
+		sv, err := ds.GetSchemaVersion(subject, v.Version)
+		if err != nil {
+			return nil, fmt.Errorf("version %d of target subject %q: %w", v.Version, subject, err)
+		}
+		if _, seen := texts[textKey(sv)]; !seen {
+			texts[textKey(sv)] = sv.Version
+		}
+	}
+	return texts, nil
+}
+
+func textKey(v api.SchemaVersion) string {
+	t := v.SchemaType
+	if t == "" {
+		t = "AVRO"
+	}
+	return t + "\x00" + v.Schema
+}
+
+type versionKey struct {
+	subject string
+	version int
+}
+
+// importOrder sorts all versions so that each subject's versions stay
+// ascending and every referenced version comes before the versions that import
+// it. Among versions that are ready at the same time the lower schema ID goes
+// first, which replays the source registry's registration order. References
+// to versions outside the backup must already exist in the target.
+func importOrder(b Backup) ([]Step, error) {
+	steps := map[versionKey]Step{}
+	deps := map[versionKey][]versionKey{}
+	for _, sub := range b.Subjects {
+		for i, v := range sub.Versions {
+			k := versionKey{sub.Name, v.Version}
+			steps[k] = Step{Subject: sub.Name, Version: v}
+			if i > 0 {
+				deps[k] = append(deps[k], versionKey{sub.Name, sub.Versions[i-1].Version})
+			}
+		}
+	}
+	for k, s := range steps {
+		for _, ref := range s.Version.References {
+			if rk := (versionKey{ref.Subject, ref.Version}); steps[rk].Subject != "" {
+				deps[k] = append(deps[k], rk)
+			}
+		}
+	}
+
+	pending := make(map[versionKey]int, len(steps))
+	dependents := map[versionKey][]versionKey{}
+	for k := range steps {
+		pending[k] = len(deps[k])
+		for _, d := range deps[k] {
+			dependents[d] = append(dependents[d], k)
+		}
+	}
+	ready := &readyQueue{steps: steps}
+	for k, n := range pending {
+		if n == 0 {
+			heap.Push(ready, k)
+		}
+	}
+	out := make([]Step, 0, len(steps))
+	for ready.Len() > 0 {
+		k := heap.Pop(ready).(versionKey)
+		out = append(out, steps[k])
+		for _, d := range dependents[k] {
+			if pending[d]--; pending[d] == 0 {
+				heap.Push(ready, d)
+			}
+		}
+	}
+	if len(out) < len(steps) {
+		return nil, FormatError{Reason: "schema references form a cycle"}
+	}
+	return out, nil
+}
+
+// readyQueue orders versions whose dependencies are imported by schema ID,
+// then subject and version.
+type readyQueue struct {
+	keys  []versionKey
+	steps map[versionKey]Step
+}
+
+func (q readyQueue) Len() int      { return len(q.keys) }
+func (q readyQueue) Swap(i, j int) { q.keys[i], q.keys[j] = q.keys[j], q.keys[i] }
+func (q readyQueue) Less(i, j int) bool {
+	a, b := q.keys[i], q.keys[j]
+	if ia, ib := q.steps[a].Version.ID, q.steps[b].Version.ID; ia != ib {
+		return ia < ib
+	}
+	if a.subject != b.subject {
+		return a.subject < b.subject
+	}
+	return a.version < b.version
+}
+func (q *readyQueue) Push(x any) { q.keys = append(q.keys, x.(versionKey)) }
+func (q *readyQueue) Pop() any {
+	k := q.keys[len(q.keys)-1]
+	q.keys = q.keys[:len(q.keys)-1]
+	return k
+}
+
+// Apply registers the plan's versions in order, then sets the compatibility
+// levels. It stops at the first failing call. Without IMPORT mode the target
+// assigns new IDs and version numbers; references, also those of imported
+// versions, are rewritten to the numbers the referenced versions received.
+func (p Plan) Apply(ds api.KafkaDataSource) error {
+	if p.Empty() {
+		shared.Log.Info("schema import: target already matches the backup, nothing to do")
+		return nil
+	}
+	shared.Log.Info("schema import: applying plan",
+		"register", len(p.Registers), "skipped", len(p.Skipped),
+		"compat", len(p.CompatChanges), "preserveIDs", p.PreservesIDs())
+
+	numbers := make(map[versionKey]int, len(p.Registers)+len(p.Skipped))
+	for _, s := range p.Skipped {
+		numbers[versionKey{s.Subject, s.Version.Version}] = s.Existing
+	}
+	for _, s := range p.Registers {
+		v := s.Version
+		var (
+			got api.Schema
+			err error
+		)
+		if s.PreserveID {
+			v.References = remapReferences(v.References, numbers)
+			got, err = ds.ImportSchema(s.Subject, v)
+		} else {
+			got, err = ds.RegisterSchema(s.Subject, v.Schema, v.SchemaType, remapReferences(v.References, numbers))
+		}
+		if err != nil {
+			return fmt.Errorf("register version %d of subject %q: %w", v.Version, s.Subject, err)
+		}
+		numbers[versionKey{s.Subject, v.Version}] = got.Version
+	}
+	for _, c := range p.CompatChanges {
+		if c.Subject == "" {
+			if err := ds.SetGlobalCompatibility(c.To); err != nil {
+				return fmt.Errorf("set global compatibility: %w", err)
+			}
+			continue
+		}
+		if err := ds.SetSubjectCompatibility(c.Subject, c.To); err != nil {
+			return fmt.Errorf("set compatibility of subject %q: %w", c.Subject, err)
+		}
+	}
+	return nil
+}
+
+// remapReferences points references at the target's version numbers.
+func remapReferences(refs []api.SchemaReference, numbers map[versionKey]int) []api.SchemaReference {
+	if len(refs) == 0 {
+		return refs
+	}
+	out := make([]api.SchemaReference, len(refs))
+	for i, r := range refs {
+		if n, ok := numbers[versionKey{r.Subject, r.Version}]; ok && n > 0 {
+			r.Version = n
+		}
+		out[i] = r
+	}
+	return out
+}
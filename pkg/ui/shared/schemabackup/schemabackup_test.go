@@ -0,0 +1,223 @@
+package schemabackup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emptyRegistry returns a mock whose seeded subjects are all hard-deleted.
+func emptyRegistry(t *testing.T) *mock.KafkaDataSourceMock {
+	t.Helper()
+	ds := &mock.KafkaDataSourceMock{}
+	schemas, err := ds.GetSchemas()
+	require.NoError(t, err)
+	deleted, _ := ds.GetDeletedSubjects()
+	for _, s := range schemas {
+		deleted = append(deleted, s.Subject)
+	}
+	for _, name := range deleted {
+		_, err := ds.DeleteSubject(name, true)
+		require.NoError(t, err)
+	}
+	return ds
+}
+
+func subjectNamed(t *testing.T, b Backup, name string) Subject {
+	t.Helper()
+	for _, s := range b.Subjects {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("subject %q not in backup", name)
+	return Subject{}
+}
+
+func TestExportWriteReadRoundTrip(t *testing.T) {
+	src := &mock.KafkaDataSourceMock{}
+	require.NoError(t, src.SetSubjectCompatibility("payments-value", api.CompatibilityFull))
+	b, err := Export(src)
+	require.NoError(t, err)
+
+	orders := subjectNamed(t, b, "orders-value")
+	require.Len(t, orders.Versions, 3)
+	assert.NotEmpty(t, orders.Versions[0].Schema)
+	assert.Equal(t, 101, orders.Versions[0].ID)
+	assert.Equal(t, api.CompatibilityFull, subjectNamed(t, b, "payments-value").Compatibility)
+	assert.Empty(t, orders.Compatibility, "inherited levels are not exported")
+	require.Len(t, subjectNamed(t, b, "customers-value").Versions[0].References, 1)
+	for _, s := range b.Subjects {
+		assert.NotEqual(t, "legacy-orders-value", s.Name, "soft-deleted subjects are not exported")
+	}
+
+	for _, name := range []string{"backup", "backup.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), name)
+			require.NoError(t, Write(p, b))
+			got, err := Read(p)
+			require.NoError(t, err)
+			assert.Equal(t, b, got)
+		})
+	}
+
+	t.Run("non-empty directory is refused", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "old.json"), []byte("{}"), 0o644))
+		assert.Error(t, Write(dir, b))
+	})
+}
+
+func TestReadRejectsInvalidBackup(t *testing.T) {
+	write := func(t *testing.T, files map[string]string) string {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, subjectsDir), 0o755))
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0o644))
+		}
+		return dir
+	}
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"missing index", map[string]string{}},
+		{"newer format", map[string]string{indexFile: `{"formatVersion":2}`}},
+		{"descending versions", map[string]string{
+			indexFile:         `{"formatVersion":1}`,
+			"subjects/a.json": `{"subject":"a","versions":[{"version":2,"id":1,"schema":"{}"},{"version":1,"id":2,"schema":"{}"}]}`,
+		}},
+		{"version without id", map[string]string{
+			indexFile:         `{"formatVersion":1}`,
+			"subjects/a.json": `{"subject":"a","versions":[{"version":1,"schema":"{}"}]}`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(write(t, tt.files))
+			var fe FormatError
+			assert.True(t, errors.As(err, &fe), "err = %v", err)
+		})
+	}
+}
+
+func TestImportOrderPutsReferencesFirst(t *testing.T) {
+	ref := []api.SchemaReference{{Name: "Address", Subject: "z-address", Version: 1}}
+	b := Backup{FormatVersion: FormatVersion, Subjects: []Subject{
+		{Name: "a-customer", Versions: []api.SchemaVersion{
+			{Version: 1, ID: 1, Schema: "c1", References: ref},
+			{Version: 2, ID: 3, Schema: "c2", References: ref},
+		}},
+		// The referenced subject got a higher ID than a-customer's first version,
+		// e.g. because it was re-registered later in the source registry.
+		{Name: "z-address", Versions: []api.SchemaVersion{{Version: 1, ID: 2, Schema: "addr"}}},
+	}}
+	steps, err := importOrder(b)
+	require.NoError(t, err)
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.Version.Schema)
+	}
+	assert.Equal(t, []string{"addr", "c1", "c2"}, got)
+
+	t.Run("cycle", func(t *testing.T) {
+		b.Subjects[1].Versions[0].References = []api.SchemaReference{{Name: "C", Subject: "a-customer", Version: 1}}
+		_, err := importOrder(b)
+		var fe FormatError
+		assert.True(t, errors.As(err, &fe))
+	})
+}
+
+func TestImportIntoReadWriteRegistry(t *testing.T) {
+	b, err := Export(&mock.KafkaDataSourceMock{})
+	require.NoError(t, err)
+	dst := emptyRegistry(t)
+	// Leave a version of common-address behind so it is skipped and the
+	// reference of customers-value must be rewritten to its new number.
+	addr := subjectNamed(t, b, "common-address").Versions[0]
+	_, err = dst.RegisterSchema("common-address", `{"type":"string"}`, "", nil)
+	require.NoError(t, err)
+	_, err = dst.RegisterSchema("common-address", addr.Schema, addr.SchemaType, nil)
+	require.NoError(t, err)
+
+	plan, err := PlanImport(dst, b)
+	require.NoError(t, err)
+	require.Len(t, plan.Skipped, 1)
+	assert.Equal(t, 2, plan.Skipped[0].Existing)
+	assert.False(t, plan.PreservesIDs())
+	require.NoError(t, plan.Apply(dst))
+
+	versions, err := dst.GetSchemaVersions("orders-value")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	for i, v := range versions {
+		assert.Equal(t, subjectNamed(t, b, "orders-value").Versions[i].Schema, v.Schema, "version order is kept")
+	}
+	customers, err := dst.GetSchemaVersion("customers-value", 0)
+	require.NoError(t, err)
+	require.Len(t, customers.References, 1)
+	assert.Equal(t, 2, customers.References[0].Version, "reference points at the target's version")
+
+	again, err := PlanImport(dst, b)
+	require.NoError(t, err)
+	assert.True(t, again.Empty(), "a second import is a no-op")
+}
+
+func TestImportIntoImportModeKeepsIDs(t *testing.T) {
+	src := &mock.KafkaDataSourceMock{}
+	require.NoError(t, src.SetSubjectCompatibility("orders-value", api.CompatibilityNone))
+	b, err := Export(src)
+	require.NoError(t, err)
+	dst := emptyRegistry(t)
+	require.NoError(t, dst.SetGlobalCompatibility(api.CompatibilityFull))
+	require.NoError(t, dst.SetGlobalMode(api.SchemaModeImport))
+
+	plan, err := PlanImport(dst, b)
+	require.NoError(t, err)
+	assert.True(t, plan.PreservesIDs())
+	assert.Contains(t, plan.CompatChanges, CompatChange{From: api.CompatibilityFull, To: api.CompatibilityBackward})
+	require.NoError(t, plan.Apply(dst))
+
+	for _, sub := range b.Subjects {
+		versions, err := dst.GetSchemaVersions(sub.Name)
+		require.NoError(t, err)
+		require.Len(t, versions, len(sub.Versions))
+		for i, v := range versions {
+			assert.Equal(t, sub.Versions[i].Version, v.Version)
+			assert.Equal(t, sub.Versions[i].ID, v.ID)
+		}
+	}
+	level, specific, err := dst.GetSubjectCompatibility("orders-value")
+	require.NoError(t, err)
+	assert.True(t, specific)
+	assert.Equal(t, api.CompatibilityNone, level)
+	global, _ := dst.GetGlobalCompatibility()
+	assert.Equal(t, api.CompatibilityBackward, global)
+}
+
+func TestImportIntoMixedModesRemapsReferences(t *testing.T) {
+	b, err := Export(&mock.KafkaDataSourceMock{})
+	require.NoError(t, err)
+	dst := emptyRegistry(t)
+	// common-address stays READWRITE and already has a version, so the
+	// backed-up one lands on 2; customers-value imports with its IDs.
+	_, err = dst.RegisterSchema("common-address", `{"type":"string"}`, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, dst.SetSubjectMode("customers-value", api.SchemaModeImport))
+
+	plan, err := PlanImport(dst, b)
+	require.NoError(t, err)
+	require.NoError(t, plan.Apply(dst))
+
+	customers, err := dst.GetSchemaVersion("customers-value", 0)
+	require.NoError(t, err)
+	assert.Equal(t, subjectNamed(t, b, "customers-value").Versions[0].ID, customers.ID, "the ID is kept")
+	require.Len(t, customers.References, 1)
+	assert.Equal(t, 2, customers.References[0].Version, "reference points at the target's version")
+}
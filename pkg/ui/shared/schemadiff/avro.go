@@ -0,0 +1,486 @@
+package schemadiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// avroType is a parsed Avro schema node. Named types (record, enum, fixed)
+// are shared by pointer, so recursive records parse to a cycle.
+type avroType struct {
+	kind        string // primitive name, record, enum, fixed, array, map, union or named
+	name        string // full name of a named type
+	logical     string // logicalType of a primitive or fixed
+	aliases     []string
+	fields      []*avroField
+	symbols     []string
+	enumDefault *string
+	size        int
+	items       *avroType // array
+	values      *avroType // map
+	branches    []*avroType
+}
+
+type avroField struct {
+	name       string
+	typ        *avroType
+	aliases    []string
+	hasDefault bool
+	def        string // canonical JSON of the default
+}
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true,
+	"float": true, "double": true, "bytes": true, "string": true,
+}
+
+// avroParser resolves named-type references against the types defined so far.
+// A name defined elsewhere, typically in a schema reference, parses to an
+// opaque "named" type that compares by name only.
+type avroParser struct {
+	named map[string]*avroType
+}
+
+func parseAvro(side, text string) (*avroType, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, ParseError{Side: side, Reason: err.Error()}
+	}
+	p := &avroParser{named: map[string]*avroType{}}
+	t, err := p.parse(raw, "")
+	if err != nil {
+		return nil, ParseError{Side: side, Reason: err.Error()}
+	}
+	return t, nil
+}
+
+func (p *avroParser) parse(raw interface{}, namespace string) (*avroType, error) {
+	switch v := raw.(type) {
+	case string:
+		if avroPrimitives[v] {
+			return &avroType{kind: v}, nil
+		}
+		if t, ok := p.named[fullName(v, namespace)]; ok {
+			return t, nil
+		}
+		if t, ok := p.named[v]; ok {
+			return t, nil
+		}
+		t := &avroType{kind: "named", name: fullName(v, namespace)}
+		p.named[t.name] = t
+		return t, nil
+	case []interface{}:
+		u := &avroType{kind: "union"}
+		for _, b := range v {
+			bt, err := p.parse(b, namespace)
+			if err != nil {
+				return nil, err
+			}
+			u.branches = append(u.branches, bt)
+		}
+		return u, nil
+	case map[string]interface{}:
+		return p.parseObject(v, namespace)
+	default:
+		return nil, fmt.Errorf("unexpected schema value %v", raw)
+	}
+}
+
+func (p *avroParser) parseObject(v map[string]interface{}, namespace string) (*avroType, error) {
+	typ, _ := v["type"].(string)
+	switch typ {
+	case "record", "error", "enum", "fixed":
+		name, _ := v["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("%s without a name", typ)
+		}
+		if ns, ok := v["namespace"].(string); ok && !strings.Contains(name, ".") {
+			namespace = ns
+		}
+		t := &avroType{kind: typ, name: fullName(name, namespace), aliases: stringList(v["aliases"])}
+		if typ == "error" {
+			t.kind = "record"
+		}
+		if i := strings.LastIndex(t.name, "."); i >= 0 {
+			namespace = t.name[:i]
+		}
+		p.named[t.name] = t
+		switch typ {
+		case "enum":
+			t.symbols = stringList(v["symbols"])
+			if d, ok := v["default"].(string); ok {
+				t.enumDefault = &d
+			}
+		case "fixed":
+			size, _ := v["size"].(float64)
+			t.size = int(size)
+			t.logical, _ = v["logicalType"].(string)
+		default:
+			fields, _ := v["fields"].([]interface{})
+			for _, rf := range fields {
+				fm, ok := rf.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("record %s: field is not an object", t.name)
+				}
+				f, err := p.parseField(fm, namespace)
+				if err != nil {
+					return nil, fmt.Errorf("record %s: %w", t.name, err)
+				}
+				t.fields = append(t.fields, f)
+			}
+		}
+		return t, nil
+	case "array":
+		items, err := p.parse(v["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroType{kind: "array", items: items}, nil
+	case "map":
+		values, err := p.parse(v["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &avroType{kind: "map", values: values}, nil
+	default:
+		// {"type": "int", "logicalType": "date"} or a nested type definition.
+		t, err := p.parse(v["type"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		if logical, ok := v["logicalType"].(string); ok && avroPrimitives[t.kind] {
+			t = &avroType{kind: t.kind, logical: logical}
+		}
+		return t, nil
+	}
+}
+
+func (p *avroParser) parseField(fm map[string]interface{}, namespace string) (*avroField, error) {
+	name, _ := fm["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("field without a name")
+	}
+	t, err := p.parse(fm["type"], namespace)
+	if err != nil {
+		return nil, fmt.Errorf("field %s: %w", name, err)
+	}
+	f := &avroField{name: name, typ: t, aliases: stringList(fm["aliases"])}
+	if d, ok := fm["default"]; ok {
+		b, _ := json.Marshal(d)
+		f.hasDefault, f.def = true, string(b)
+	}
+	return f, nil
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+func shortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func stringList(raw interface{}) []string {
+	list, _ := raw.([]interface{})
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// describe renders a type compactly, e.g. "array<string>" or "null|Address".
+func (t *avroType) describe() string {
+	switch t.kind {
+	case "record", "enum", "named":
+		return shortName(t.name)
+	case "fixed":
+		return fmt.Sprintf("%s(fixed %d)", shortName(t.name), t.size)
+	case "array":
+		return "array<" + t.items.describe() + ">"
+	case "map":
+		return "map<" + t.values.describe() + ">"
+	case "union":
+		parts := make([]string, len(t.branches))
+		for i, b := range t.branches {
+			parts[i] = b.describe()
+		}
+		return strings.Join(parts, "|")
+	default:
+		if t.logical != "" {
+			return t.kind + "(" + t.logical + ")"
+		}
+		return t.kind
+	}
+}
+
+// avroPromotions lists the writer→reader promotions of Avro schema resolution.
+var avroPromotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// avroCanRead reports whether data written with writer resolves against
+// reader. Named types match by unqualified name or alias; their fields and
+// symbols are classified separately.
+func avroCanRead(reader, writer *avroType) bool {
+	if writer.kind == "union" {
+		for _, b := range writer.branches {
+			if !avroCanRead(reader, b) {
+				return false
+			}
+		}
+		return true
+	}
+	if reader.kind == "union" {
+		for _, b := range reader.branches {
+			if avroCanRead(b, writer) {
+				return true
+			}
+		}
+		return false
+	}
+	if reader.kind == "named" || writer.kind == "named" {
+		// Only the name of a referenced type is known.
+		return reader.name != "" && writer.name != "" && avroNamesMatch(reader, writer)
+	}
+	if reader.kind == writer.kind {
+		switch reader.kind {
+		case "record", "enum":
+			return avroNamesMatch(reader, writer)
+		case "fixed":
+			return avroNamesMatch(reader, writer) && reader.size == writer.size
+		case "array":
+			return avroCanRead(reader.items, writer.items)
+		case "map":
+			return avroCanRead(reader.values, writer.values)
+		default:
+			return true
+		}
+	}
+	for _, to := range avroPromotions[writer.kind] {
+		if to == reader.kind {
+			return true
+		}
+	}
+	return false
+}
+
+func avroNamesMatch(reader, writer *avroType) bool {
+	if shortName(reader.name) == shortName(writer.name) {
+		return true
+	}
+	for _, a := range reader.aliases {
+		if shortName(a) == shortName(writer.name) {
+			return true
+		}
+	}
+	return false
+}
+
+func diffAvro(oldText, newText string) ([]Change, error) {
+	o, err := parseAvro("old", oldText)
+	if err != nil {
+		return nil, err
+	}
+	n, err := parseAvro("new", newText)
+	if err != nil {
+		return nil, err
+	}
+	d := &avroDiff{seen: map[[2]*avroType]bool{}}
+	root := "(root)"
+	if n.name != "" {
+		root = shortName(n.name)
+	}
+	d.types(root, o, n)
+	return d.changes, nil
+}
+
+type avroDiff struct {
+	changes []Change
+	seen    map[[2]*avroType]bool
+}
+
+func (d *avroDiff) add(c Change) { d.changes = append(d.changes, c) }
+
+// types compares two types at path, recursing into matching named types.
+func (d *avroDiff) types(path string, o, n *avroType) {
+	if o.kind == n.kind {
+		switch o.kind {
+		case "record":
+			if avroNamesMatch(n, o) {
+				d.record(path, o, n)
+				return
+			}
+		case "enum":
+			if avroNamesMatch(n, o) {
+				d.enum(path, o, n)
+				return
+			}
+		case "array":
+			d.types(path+"[]", o.items, n.items)
+			return
+		case "map":
+			d.types(path+"{}", o.values, n.values)
+			return
+		case "union":
+			// Recurse into branches present on both sides, e.g. the record of
+			// ["null", Address].
+			for _, nb := range n.branches {
+				for _, ob := range o.branches {
+					if ob.kind == nb.kind && (ob.name == "" || avroNamesMatch(nb, ob)) && ob.describe() == nb.describe() {
+						d.types(path, ob, nb)
+					}
+				}
+			}
+		}
+	}
+	if o.describe() == n.describe() {
+		return
+	}
+	c := Change{
+		Kind: TypeChanged, Path: path, From: o.describe(), To: n.describe(),
+		Backward: avroCanRead(n, o), Forward: avroCanRead(o, n),
+	}
+	switch {
+	case c.Backward && !c.Forward:
+		c.Note = "widened: old readers cannot read the new values"
+	case !c.Backward && c.Forward:
+		c.Note = "narrowed: new readers cannot read the old values"
+	}
+	d.add(c)
+}
+
+func (d *avroDiff) record(path string, o, n *avroType) {
+	key := [2]*avroType{o, n}
+	if d.seen[key] {
+		return
+	}
+	d.seen[key] = true
+
+	oldByName := make(map[string]*avroField, len(o.fields))
+	for _, f := range o.fields {
+		oldByName[f.name] = f
+	}
+	newNames := make(map[string]bool, len(n.fields))
+	for _, f := range n.fields {
+		newNames[f.name] = true
+	}
+	matched := map[string]bool{}
+
+	for _, nf := range n.fields {
+		fp := joinPath(path, nf.name)
+		if of, ok := oldByName[nf.name]; ok {
+			matched[of.name] = true
+			d.field(fp, of, nf)
+			continue
+		}
+		if of := renamedFrom(nf, oldByName, newNames); of != nil {
+			matched[of.name] = true
+			c := Change{Kind: FieldRenamed, Path: fp, From: of.name, To: nf.name, Backward: true, Forward: of.hasDefault}
+			if !of.hasDefault {
+				c.Note = "old readers find no " + of.name + " in new data and it has no default"
+			}
+			d.add(c)
+			d.field(fp, of, nf)
+			continue
+		}
+		c := Change{Kind: FieldAdded, Path: fp, To: nf.typ.describe(), Backward: nf.hasDefault, Forward: true}
+		if !nf.hasDefault {
+			c.Note = "no default: new readers cannot read old data"
+		}
+		d.add(c)
+	}
+	for _, of := range o.fields {
+		if matched[of.name] {
+			continue
+		}
+		c := Change{Kind: FieldRemoved, Path: joinPath(path, of.name), From: of.typ.describe(), Backward: true, Forward: of.hasDefault}
+		if !of.hasDefault {
+			c.Note = "the old field has no default: old readers cannot read new data"
+		}
+		d.add(c)
+	}
+}
+
+// renamedFrom returns the old field nf aliases, if that old name is gone.
+func renamedFrom(nf *avroField, oldByName map[string]*avroField, newNames map[string]bool) *avroField {
+	for _, a := range nf.aliases {
+		if of, ok := oldByName[a]; ok && !newNames[a] {
+			return of
+		}
+	}
+	return nil
+}
+
+func (d *avroDiff) field(path string, o, n *avroField) {
+	d.types(path, o.typ, n.typ)
+	if o.hasDefault == n.hasDefault && o.def == n.def {
+		return
+	}
+	c := Change{Kind: DefaultChanged, Path: path, From: avroDefault(o), To: avroDefault(n), Backward: true, Forward: true}
+	switch {
+	case !n.hasDefault:
+		c.Note = "without a default the field can no longer be removed compatibly"
+	default:
+		c.Note = "only applies when a reader fills a missing field"
+	}
+	d.add(c)
+}
+
+func avroDefault(f *avroField) string {
+	if !f.hasDefault {
+		return "(none)"
+	}
+	return f.def
+}
+
+func (d *avroDiff) enum(path string, o, n *avroType) {
+	oldSyms := make(map[string]bool, len(o.symbols))
+	for _, s := range o.symbols {
+		oldSyms[s] = true
+	}
+	newSyms := make(map[string]bool, len(n.symbols))
+	for _, s := range n.symbols {
+		newSyms[s] = true
+	}
+	for _, s := range n.symbols {
+		if oldSyms[s] {
+			continue
+		}
+		c := Change{Kind: EnumSymbolAdded, Path: path, To: s, Backward: true, Forward: o.enumDefault != nil}
+		if o.enumDefault == nil {
+			c.Note = "the old enum has no default for unknown symbols"
+		}
+		d.add(c)
+	}
+	for _, s := range o.symbols {
+		if newSyms[s] {
+			continue
+		}
+		c := Change{Kind: EnumSymbolRemoved, Path: path, From: s, Backward: n.enumDefault != nil, Forward: true}
+		if n.enumDefault == nil {
+			c.Note = "the new enum has no default for unknown symbols"
+		}
+		d.add(c)
+	}
+	if od, nd := derefOr(o.enumDefault), derefOr(n.enumDefault); od != nd {
+		d.add(Change{Kind: DefaultChanged, Path: path, From: od, To: nd, Backward: true, Forward: true})
+	}
+}
+
+func derefOr(s *string) string {
+	if s == nil {
+		return "(none)"
+	}
+	return *s
+}
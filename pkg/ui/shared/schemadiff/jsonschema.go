@@ -0,0 +1,232 @@
+package schemadiff
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// JSON Schema describes what a document may contain rather than how it is
+// encoded, so a change is backward compatible when the new schema accepts
+// every document the old one did, and forward compatible the other way round.
+
+func diffJSONSchema(oldText, newText string) ([]Change, error) {
+	o, err := parseJSONSchema("old", oldText)
+	if err != nil {
+		return nil, err
+	}
+	n, err := parseJSONSchema("new", newText)
+	if err != nil {
+		return nil, err
+	}
+	d := &jsonDiff{}
+	d.schema("", o, n)
+	return d.changes, nil
+}
+
+func parseJSONSchema(side, text string) (map[string]interface{}, error) {
+	var s map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &s); err != nil {
+		return nil, ParseError{Side: side, Reason: err.Error()}
+	}
+	return s, nil
+}
+
+type jsonDiff struct {
+	changes []Change
+}
+
+func (d *jsonDiff) add(c Change) {
+	if c.Path == "" {
+		c.Path = "(root)"
+	}
+	d.changes = append(d.changes, c)
+}
+
+func (d *jsonDiff) schema(path string, o, n map[string]interface{}) {
+	d.types(path, jsonTypes(o), jsonTypes(n))
+	d.enum(path, o["enum"], n["enum"])
+	if od, nd := jsonValue(o, "default"), jsonValue(n, "default"); od != nd {
+		d.add(Change{Kind: DefaultChanged, Path: path, From: od, To: nd, Backward: true, Forward: true,
+			Note: "defaults are annotations and do not affect validation"})
+	}
+	d.properties(path, o, n)
+	if oi, ok := o["items"].(map[string]interface{}); ok {
+		if ni, ok := n["items"].(map[string]interface{}); ok {
+			d.schema(path+"[]", oi, ni)
+		}
+	}
+}
+
+func (d *jsonDiff) types(path string, o, n []string) {
+	if len(o) == 0 || len(n) == 0 || strings.Join(o, "|") == strings.Join(n, "|") {
+		return
+	}
+	c := Change{Kind: TypeChanged, Path: path, From: strings.Join(o, "|"), To: strings.Join(n, "|")}
+	c.Backward = jsonTypesCover(n, o)
+	c.Forward = jsonTypesCover(o, n)
+	switch {
+	case c.Backward && !c.Forward:
+		c.Note = "widened: old readers reject some new documents"
+	case !c.Backward && c.Forward:
+		c.Note = "narrowed: new readers reject some old documents"
+	}
+	d.add(c)
+}
+
+// jsonTypesCover reports whether every type in inner is accepted by outer;
+// "number" accepts integers.
+func jsonTypesCover(outer, inner []string) bool {
+	accepts := map[string]bool{}
+	for _, t := range outer {
+		accepts[t] = true
+	}
+	for _, t := range inner {
+		if !accepts[t] && !(t == "integer" && accepts["number"]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *jsonDiff) enum(path string, o, n interface{}) {
+	ol, oOK := o.([]interface{})
+	nl, nOK := n.([]interface{})
+	if !oOK || !nOK {
+		return
+	}
+	oldVals, newVals := jsonSet(ol), jsonSet(nl)
+	for _, v := range sortedKeys(newVals) {
+		if !oldVals[v] {
+			d.add(Change{Kind: EnumSymbolAdded, Path: path, To: v, Backward: true, Forward: false,
+				Note: "old readers reject documents using it"})
+		}
+	}
+	for _, v := range sortedKeys(oldVals) {
+		if !newVals[v] {
+			d.add(Change{Kind: EnumSymbolRemoved, Path: path, From: v, Backward: false, Forward: true,
+				Note: "new readers reject old documents using it"})
+		}
+	}
+}
+
+func (d *jsonDiff) properties(path string, o, n map[string]interface{}) {
+	op, _ := o["properties"].(map[string]interface{})
+	np, _ := n["properties"].(map[string]interface{})
+	if op == nil && np == nil {
+		return
+	}
+	oldReq, newReq := jsonSet(asList(o["required"])), jsonSet(asList(n["required"]))
+	oldClosed, newClosed := o["additionalProperties"] == false, n["additionalProperties"] == false
+
+	for _, name := range sortedKeys(keysOf(np)) {
+		fp := joinPath(path, name)
+		required := newReq[jsonString(name)]
+		oldProp, ok := op[name].(map[string]interface{})
+		if _, present := op[name]; !present {
+			c := Change{Kind: FieldAdded, Path: fp, To: strings.Join(jsonTypes(asMap(np[name])), "|"), Backward: !required, Forward: !oldClosed}
+			switch {
+			case required:
+				c.Note = "required: new readers reject old documents without it"
+			case oldClosed:
+				c.Note = "the old schema forbids additional properties"
+			}
+			d.add(c)
+			continue
+		}
+		wasRequired := oldReq[jsonString(name)]
+		switch {
+		case required && !wasRequired:
+			d.add(Change{Kind: RequiredChanged, Path: fp, From: "optional", To: "required", Backward: false, Forward: true,
+				Note: "new readers reject old documents without it"})
+		case !required && wasRequired:
+			d.add(Change{Kind: RequiredChanged, Path: fp, From: "required", To: "optional", Backward: true, Forward: false,
+				Note: "old readers reject new documents without it"})
+		}
+		if newProp, nOK := np[name].(map[string]interface{}); ok && nOK {
+			d.schema(fp, oldProp, newProp)
+		}
+	}
+	for _, name := range sortedKeys(keysOf(op)) {
+		if _, present := np[name]; present {
+			continue
+		}
+		c := Change{Kind: FieldRemoved, Path: joinPath(path, name), From: strings.Join(jsonTypes(asMap(op[name])), "|"),
+			Backward: !newClosed, Forward: !oldReq[jsonString(name)]}
+		switch {
+		case !c.Forward:
+			c.Note = "required: old readers reject new documents without it"
+		case newClosed:
+			c.Note = "the new schema forbids additional properties"
+		}
+		d.add(c)
+	}
+}
+
+// jsonTypes returns the sorted "type" keyword of a schema.
+func jsonTypes(s map[string]interface{}) []string {
+	var types []string
+	switch t := s["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			if str, ok := v.(string); ok {
+				types = append(types, str)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// jsonValue renders s[key] as canonical JSON, or "(none)" when absent.
+func jsonValue(s map[string]interface{}, key string) string {
+	v, ok := s[key]
+	if !ok {
+		return "(none)"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func jsonString(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// jsonSet keys a list of JSON values by their canonical encoding.
+func jsonSet(list []interface{}) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, v := range list {
+		set[jsonString(v)] = true
+	}
+	return set
+}
+
+func asList(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func keysOf(m map[string]interface{}) map[string]bool {
+	set := make(map[string]bool, len(m))
+	for k := range m {
+		set[k] = true
+	}
+	return set
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,619 @@
+package schemadiff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// protoFile is the part of a .proto file the diff needs: messages and enums
+// keyed by their name relative to the package, e.g. "Order.Item".
+type protoFile struct {
+	pkg      string
+	messages map[string]*protoMessage
+	enums    map[string]*protoEnum
+	order    []string // message names in declaration order
+	enumSeq  []string // enum names in declaration order
+}
+
+type protoMessage struct {
+	name   string
+	fields []*protoField
+}
+
+type protoField struct {
+	name   string
+	number int
+	label  string // "", optional, required or repeated
+	typ    string
+	def    string
+}
+
+type protoEnum struct {
+	name   string
+	values []protoEnumValue
+}
+
+type protoEnumValue struct {
+	name   string
+	number int
+}
+
+// protoParser is a small recursive-descent parser over the tokens of a .proto
+// file. Services, options and extensions are skipped.
+type protoParser struct {
+	toks []string
+	pos  int
+	file *protoFile
+}
+
+func parseProto(side, text string) (*protoFile, error) {
+	p := &protoParser{toks: protoTokens(text), file: &protoFile{messages: map[string]*protoMessage{}, enums: map[string]*protoEnum{}}}
+	if err := p.parseFile(); err != nil {
+		return nil, ParseError{Side: side, Reason: err.Error()}
+	}
+	return p.file, nil
+}
+
+// protoTokens splits text into identifiers, numbers, string literals and
+// single-character punctuation, dropping comments.
+func protoTokens(text string) []string {
+	var toks []string
+	rs := []rune(text)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			i += 2
+			for i+1 < len(rs) && !(rs[i] == '*' && rs[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				if rs[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(rs) {
+				j = len(rs) - 1
+			}
+			toks = append(toks, string(rs[i:j+1]))
+			i = j + 1
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-' || r == '+':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '.' || rs[j] == '-' || rs[j] == '+') {
+				j++
+			}
+			toks = append(toks, string(rs[i:j]))
+			i = j
+		default:
+			toks = append(toks, string(r))
+			i++
+		}
+	}
+	return toks
+}
+
+func (p *protoParser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *protoParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *protoParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		if got == "" {
+			got = "end of file"
+		}
+		return fmt.Errorf("expected %q, got %q", tok, got)
+	}
+	return nil
+}
+
+// skipStatement skips to the end of the current statement or block.
+func (p *protoParser) skipStatement() {
+	depth := 0
+	for p.pos < len(p.toks) {
+		switch p.next() {
+		case "{":
+			depth++
+		case "}":
+			depth--
+			if depth <= 0 {
+				return
+			}
+		case ";":
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *protoParser) parseFile() error {
+	for p.pos < len(p.toks) {
+		switch p.peek() {
+		case "package":
+			p.next()
+			p.file.pkg = p.next()
+			p.skipStatement()
+		case "message":
+			p.next()
+			if err := p.parseMessage(""); err != nil {
+				return err
+			}
+		case "enum":
+			p.next()
+			if err := p.parseEnum(""); err != nil {
+				return err
+			}
+		case ";":
+			p.next()
+		case "syntax", "edition", "import", "option", "service", "extend":
+			p.skipStatement()
+		default:
+			return fmt.Errorf("unexpected %q", p.peek())
+		}
+	}
+	return nil
+}
+
+func (p *protoParser) parseMessage(parent string) error {
+	name := joinPath(parent, p.next())
+	if err := p.expect("{"); err != nil {
+		return fmt.Errorf("message %s: %w", name, err)
+	}
+	m := &protoMessage{name: name}
+	p.file.messages[name] = m
+	p.file.order = append(p.file.order, name)
+	for {
+		switch tok := p.peek(); tok {
+		case "":
+			return fmt.Errorf("message %s: missing }", name)
+		case "}":
+			p.next()
+			return nil
+		case ";":
+			p.next()
+		case "message":
+			p.next()
+			if err := p.parseMessage(name); err != nil {
+				return err
+			}
+		case "enum":
+			p.next()
+			if err := p.parseEnum(name); err != nil {
+				return err
+			}
+		case "oneof":
+			p.next()
+			p.next() // oneof name
+			if err := p.expect("{"); err != nil {
+				return fmt.Errorf("message %s: %w", name, err)
+			}
+			for p.peek() != "}" && p.peek() != "" {
+				if p.peek() == "option" {
+					p.skipStatement()
+					continue
+				}
+				if err := p.parseField(m, ""); err != nil {
+					return err
+				}
+			}
+			p.next()
+		case "option", "reserved", "extensions", "extend":
+			p.skipStatement()
+		case "optional", "required", "repeated":
+			p.next()
+			if err := p.parseField(m, tok); err != nil {
+				return err
+			}
+		default:
+			if err := p.parseField(m, ""); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *protoParser) parseField(m *protoMessage, label string) error {
+	typ := p.next()
+	if typ == "map" {
+		// map<K, V>
+		var b strings.Builder
+		b.WriteString("map")
+		for {
+			tok := p.next()
+			if tok == "" {
+				return fmt.Errorf("message %s: unterminated map type", m.name)
+			}
+			b.WriteString(tok)
+			if tok == ">" {
+				break
+			}
+			if tok == "," {
+				b.WriteString(" ")
+			}
+		}
+		typ = b.String()
+	}
+	name := p.next()
+	if err := p.expect("="); err != nil {
+		return fmt.Errorf("message %s field %s: %w", m.name, name, err)
+	}
+	number, err := strconv.Atoi(p.next())
+	if err != nil {
+		return fmt.Errorf("message %s field %s: invalid field number", m.name, name)
+	}
+	f := &protoField{name: name, number: number, label: label, typ: typ}
+	if p.peek() == "[" {
+		p.next()
+		for p.peek() != "]" && p.peek() != "" {
+			if p.next() == "default" && p.peek() == "=" {
+				p.next()
+				f.def = p.next()
+			}
+		}
+		p.next()
+	}
+	if err := p.expect(";"); err != nil {
+		return fmt.Errorf("message %s field %s: %w", m.name, name, err)
+	}
+	m.fields = append(m.fields, f)
+	return nil
+}
+
+func (p *protoParser) parseEnum(parent string) error {
+	name := joinPath(parent, p.next())
+	if err := p.expect("{"); err != nil {
+		return fmt.Errorf("enum %s: %w", name, err)
+	}
+	e := &protoEnum{name: name}
+	p.file.enums[name] = e
+	p.file.enumSeq = append(p.file.enumSeq, name)
+	for {
+		switch tok := p.peek(); tok {
+		case "":
+			return fmt.Errorf("enum %s: missing }", name)
+		case "}":
+			p.next()
+			return nil
+		case ";":
+			p.next()
+		case "option", "reserved":
+			p.skipStatement()
+		default:
+			p.next()
+			if err := p.expect("="); err != nil {
+				return fmt.Errorf("enum %s value %s: %w", name, tok, err)
+			}
+			number, err := strconv.Atoi(p.next())
+			if err != nil {
+				return fmt.Errorf("enum %s value %s: invalid number", name, tok)
+			}
+			p.skipStatement()
+			e.values = append(e.values, protoEnumValue{name: tok, number: number})
+		}
+	}
+}
+
+// resolve strips the package qualifier from a type reference, so "Item",
+// ".shop.Item" and "shop.Item" compare equal.
+func (f *protoFile) resolve(typ string) string {
+	typ = strings.TrimPrefix(typ, ".")
+	if f.pkg != "" {
+		typ = strings.TrimPrefix(typ, f.pkg+".")
+	}
+	return typ
+}
+
+// wireGroup names the wire encoding a scalar (or enum) type shares with the
+// types it can be changed to without breaking parsers.
+func (f *protoFile) wireGroup(typ string) string {
+	switch typ {
+	case "int32", "int64", "uint32", "uint64", "bool":
+		return "varint"
+	case "sint32", "sint64":
+		return "zigzag"
+	case "fixed32", "sfixed32":
+		return "fixed32"
+	case "fixed64", "sfixed64":
+		return "fixed64"
+	case "string", "bytes":
+		return "bytes"
+	}
+	if _, ok := f.enums[f.resolveEnum(typ)]; ok {
+		return "varint"
+	}
+	return ""
+}
+
+func (f *protoFile) resolveEnum(typ string) string {
+	typ = f.resolve(typ)
+	if _, ok := f.enums[typ]; ok {
+		return typ
+	}
+	// Nested enums may be referenced by their short name.
+	for name := range f.enums {
+		if shortName(name) == typ {
+			return name
+		}
+	}
+	return typ
+}
+
+func diffProtobuf(oldText, newText string) ([]Change, error) {
+	o, err := parseProto("old", oldText)
+	if err != nil {
+		return nil, err
+	}
+	n, err := parseProto("new", newText)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for _, name := range n.order {
+		if om, ok := o.messages[name]; ok {
+			changes = append(changes, diffProtoMessage(o, n, om, n.messages[name])...)
+		}
+	}
+	for _, name := range n.enumSeq {
+		if oe, ok := o.enums[name]; ok {
+			changes = append(changes, diffProtoEnum(oe, n.enums[name])...)
+		}
+	}
+	changes = append(changes, diffProtoTypes(o, n)...)
+	return changes, nil
+}
+
+// diffProtoTypes reports the messages and enums only one version declares.
+// Types nested in an added or removed message are covered by their parent.
+// Adding a type changes no encoding. Removing one is breaking when a field of
+// the new version still refers to it (the schema no longer resolves) or when
+// it was the first message, which serializers use as the record type.
+func diffProtoTypes(o, n *protoFile) []Change {
+	var changes []Change
+	for _, name := range n.order {
+		if _, ok := o.messages[name]; !ok && declaredParent(o, name) {
+			changes = append(changes, Change{Kind: MessageAdded, Path: name, Backward: true, Forward: true})
+		}
+	}
+	for _, name := range n.enumSeq {
+		if _, ok := o.enums[name]; !ok && declaredParent(o, name) {
+			changes = append(changes, Change{Kind: EnumAdded, Path: name, Backward: true, Forward: true})
+		}
+	}
+	for i, name := range o.order {
+		if _, ok := n.messages[name]; ok || !declaredParent(n, name) {
+			continue
+		}
+		c := Change{Kind: MessageRemoved, Path: name, Backward: true, Forward: true}
+		if i == 0 {
+			c.Backward, c.Forward = false, false
+			c.Note = "it was the first message, the record type serializers use"
+		}
+		removedProtoType(&c, n, name)
+		changes = append(changes, c)
+	}
+	for _, name := range o.enumSeq {
+		if _, ok := n.enums[name]; ok || !declaredParent(n, name) {
+			continue
+		}
+		c := Change{Kind: EnumRemoved, Path: name, Backward: true, Forward: true}
+		removedProtoType(&c, n, name)
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+// declaredParent reports whether the message enclosing the type name (if any)
+// is declared in f.
+func declaredParent(f *protoFile, name string) bool {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return true
+	}
+	_, ok := f.messages[name[:i]]
+	return ok
+}
+
+// removedProtoType marks c, the removal of type name, as breaking when a
+// field of f still refers to it.
+func removedProtoType(c *Change, f *protoFile, name string) {
+	users := f.typeUsers(name)
+	if len(users) == 0 {
+		return
+	}
+	c.Backward, c.Forward = false, false
+	c.Note = "still used by " + strings.Join(users, ", ")
+}
+
+// typeUsers returns the paths of the fields whose type (or map value type)
+// resolves to the type name under protobuf scoping: a relative name is looked
+// up from the field's message outwards and the first declared match wins.
+func (f *protoFile) typeUsers(name string) []string {
+	var out []string
+	for _, mname := range f.order {
+		for _, fld := range f.messages[mname].fields {
+			typ := fld.typ
+			if strings.HasPrefix(typ, "map<") {
+				typ = strings.TrimSuffix(typ[strings.LastIndex(typ, " ")+1:], ">")
+			}
+			if f.refersTo(mname, typ, name) {
+				out = append(out, joinPath(mname, fld.name))
+			}
+		}
+	}
+	return out
+}
+
+func (f *protoFile) refersTo(scope, typ, name string) bool {
+	qualified := strings.HasPrefix(typ, ".")
+	typ = f.resolve(typ)
+	if qualified {
+		return typ == name
+	}
+	for {
+		cand := joinPath(scope, typ)
+		if cand == name {
+			return true
+		}
+		if _, ok := f.messages[cand]; ok {
+			return false
+		}
+		if _, ok := f.enums[cand]; ok {
+			return false
+		}
+		if scope == "" {
+			return false
+		}
+		scope = scope[:max(strings.LastIndex(scope, "."), 0)]
+	}
+}
+
+func diffProtoMessage(of, nf *protoFile, o, n *protoMessage) []Change {
+	var changes []Change
+	oldByNum := make(map[int]*protoField, len(o.fields))
+	for _, f := range o.fields {
+		oldByNum[f.number] = f
+	}
+	newNums := make(map[int]bool, len(n.fields))
+	for _, nfld := range n.fields {
+		newNums[nfld.number] = true
+		path := joinPath(n.name, nfld.name)
+		ofld, ok := oldByNum[nfld.number]
+		if !ok {
+			c := Change{Kind: FieldAdded, Path: path, To: protoFieldType(nfld), Backward: true, Forward: true}
+			if nfld.label == "required" {
+				c.Backward = false
+				c.Note = "required: new readers reject old data without it"
+			}
+			changes = append(changes, c)
+			continue
+		}
+		if ofld.name != nfld.name {
+			changes = append(changes, Change{Kind: FieldRenamed, Path: path, From: ofld.name, To: nfld.name, Backward: true, Forward: true,
+				Note: "binary compatible; the JSON encoding uses the field name"})
+		}
+		changes = append(changes, diffProtoField(of, nf, path, ofld, nfld)...)
+	}
+	for _, ofld := range o.fields {
+		if newNums[ofld.number] {
+			continue
+		}
+		c := Change{Kind: FieldRemoved, Path: joinPath(o.name, ofld.name), From: protoFieldType(ofld), Backward: true, Forward: true,
+			Note: fmt.Sprintf("reserve field number %d so it is not reused", ofld.number)}
+		if ofld.label == "required" {
+			c.Forward = false
+			c.Note = "required: old readers reject new data without it"
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func diffProtoField(of, nf *protoFile, path string, o, n *protoField) []Change {
+	var changes []Change
+	oldReq, newReq := o.label == "required", n.label == "required"
+	switch {
+	case !oldReq && newReq:
+		changes = append(changes, Change{Kind: RequiredChanged, Path: path, From: "optional", To: "required", Backward: false, Forward: true,
+			Note: "new readers reject old data without it"})
+	case oldReq && !newReq:
+		changes = append(changes, Change{Kind: RequiredChanged, Path: path, From: "required", To: "optional", Backward: true, Forward: false,
+			Note: "old readers reject new data without it"})
+	}
+
+	ot, nt := of.resolve(o.typ), nf.resolve(n.typ)
+	oldRep, newRep := o.label == "repeated", n.label == "repeated"
+	if ot != nt || oldRep != newRep {
+		c := Change{Kind: TypeChanged, Path: path, From: protoFieldType(o), To: protoFieldType(n)}
+		og, ng := of.wireGroup(ot), nf.wireGroup(nt)
+		switch {
+		case oldRep != newRep:
+			// Singular and repeated share an encoding only for length-delimited
+			// values; readers of the singular form keep the last element.
+			if ot == nt && (og == "bytes" || og == "") && !strings.HasPrefix(ot, "map<") {
+				c.Backward, c.Forward = true, true
+				c.Note = "singular readers keep only the last element"
+			}
+		case og != "" && og == ng:
+			c.Backward, c.Forward = true, true
+			c.Note = "same wire type; values may be truncated or reinterpreted"
+		}
+		changes = append(changes, c)
+	}
+
+	if o.def != n.def {
+		changes = append(changes, Change{Kind: DefaultChanged, Path: path, From: orNone(o.def), To: orNone(n.def), Backward: true, Forward: true,
+			Note: "readers of each version fill missing values with their own default"})
+	}
+	return changes
+}
+
+func protoFieldType(f *protoField) string {
+	if f.label == "repeated" || f.label == "required" {
+		return f.label + " " + f.typ
+	}
+	return f.typ
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func diffProtoEnum(o, n *protoEnum) []Change {
+	var changes []Change
+	oldByNum := make(map[int]string, len(o.values))
+	for _, v := range o.values {
+		oldByNum[v.number] = v.name
+	}
+	newByNum := make(map[int]string, len(n.values))
+	for _, v := range n.values {
+		newByNum[v.number] = v.name
+	}
+	for _, v := range n.values {
+		old, ok := oldByNum[v.number]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Kind: EnumSymbolAdded, Path: n.name, To: fmt.Sprintf("%s = %d", v.name, v.number), Backward: true, Forward: true,
+				Note: "old readers see the number as an unknown value"})
+		case old != v.name:
+			changes = append(changes, Change{Kind: EnumSymbolRenamed, Path: n.name, From: old, To: v.name, Backward: true, Forward: true,
+				Note: "binary compatible; the JSON encoding uses the value name"})
+		}
+	}
+	var removed []int
+	for num := range oldByNum {
+		if _, ok := newByNum[num]; !ok {
+			removed = append(removed, num)
+		}
+	}
+	sort.Ints(removed)
+	for _, num := range removed {
+		changes = append(changes, Change{Kind: EnumSymbolRemoved, Path: o.name, From: fmt.Sprintf("%s = %d", oldByNum[num], num), Backward: false, Forward: true,
+			Note: "new readers see old data's value as unknown"})
+	}
+	return changes
+}
@@ -0,0 +1,144 @@
+// Package schemadiff compares two versions of an Avro, Protobuf or JSON Schema
+// structurally: added, removed and renamed fields, type, default and
+// required-ness changes, enum symbol changes and added or removed Protobuf
+// messages and enums, each classified as backward and/or forward compatible.
+//
+// Backward compatible means consumers using the new schema can read data
+// written with the old one; forward compatible means consumers still using
+// the old schema can read data written with the new one. The rules follow the
+// schema registry's compatibility checks closely but not exhaustively, so the
+// registry's own check stays authoritative.
+package schemadiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind classifies a change.
+type Kind string
+
+const (
+	FieldAdded        Kind = "field added"
+	FieldRemoved      Kind = "field removed"
+	FieldRenamed      Kind = "field renamed"
+	TypeChanged       Kind = "type changed"
+	DefaultChanged    Kind = "default changed"
+	RequiredChanged   Kind = "required changed"
+	EnumSymbolAdded   Kind = "enum symbol added"
+	EnumSymbolRemoved Kind = "enum symbol removed"
+	EnumSymbolRenamed Kind = "enum symbol renamed"
+	MessageAdded      Kind = "message added"
+	MessageRemoved    Kind = "message removed"
+	EnumAdded         Kind = "enum added"
+	EnumRemoved       Kind = "enum removed"
+)
+
+// Change is one structural difference between the old and new schema.
+type Change struct {
+	Kind Kind
+	// Path locates the change, e.g. "Order.items" or "Order.Status".
+	Path string
+	// From and To describe the old and new value; empty when not applicable
+	// (e.g. From of an added field).
+	From, To string
+	Backward bool
+	Forward  bool
+	// Note explains the classification when it is not obvious.
+	Note string
+}
+
+// Compatibility names the change's classification as the registry's levels
+// do: FULL, BACKWARD, FORWARD or NONE.
+func (c Change) Compatibility() string {
+	switch {
+	case c.Backward && c.Forward:
+		return "FULL"
+	case c.Backward:
+		return "BACKWARD"
+	case c.Forward:
+		return "FORWARD"
+	default:
+		return "NONE"
+	}
+}
+
+// Result is the outcome of Diff.
+type Result struct {
+	Changes []Change
+}
+
+// Backward reports whether every change is backward compatible.
+func (r Result) Backward() bool {
+	for _, c := range r.Changes {
+		if !c.Backward {
+			return false
+		}
+	}
+	return true
+}
+
+// Forward reports whether every change is forward compatible.
+func (r Result) Forward() bool {
+	for _, c := range r.Changes {
+		if !c.Forward {
+			return false
+		}
+	}
+	return true
+}
+
+// Compatibility is the classification of the change set as a whole.
+func (r Result) Compatibility() string {
+	return Change{Backward: r.Backward(), Forward: r.Forward()}.Compatibility()
+}
+
+// UnsupportedTypeError is returned for a schema type Diff cannot compare.
+type UnsupportedTypeError struct {
+	SchemaType string
+}
+
+func (e UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("structural diff is not supported for schema type %q", e.SchemaType)
+}
+
+// ParseError is returned when the old or new schema text cannot be parsed.
+type ParseError struct {
+	Side   string // "old" or "new"
+	Reason string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("cannot parse %s schema: %s", e.Side, e.Reason)
+}
+
+// Diff compares oldText with newText. schemaType is AVRO (or empty), PROTOBUF
+// or JSON.
+func Diff(schemaType, oldText, newText string) (Result, error) {
+	var (
+		changes []Change
+		err     error
+	)
+	switch strings.ToUpper(strings.TrimSpace(schemaType)) {
+	case "", "AVRO":
+		changes, err = diffAvro(oldText, newText)
+	case "PROTOBUF":
+		changes, err = diffProtobuf(oldText, newText)
+	case "JSON":
+		changes, err = diffJSONSchema(oldText, newText)
+	default:
+		return Result{}, UnsupportedTypeError{SchemaType: schemaType}
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Changes: changes}, nil
+}
+
+// joinPath appends a segment to a dotted path.
+func joinPath(base, name string) string {
+	if base == "" {
+		return name
+	}
+	return base + "." + name
+}
@@ -0,0 +1,307 @@
+package schemadiff
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// find returns the change of kind at path.
+func find(t *testing.T, r Result, kind Kind, path string) Change {
+	t.Helper()
+	for _, c := range r.Changes {
+		if c.Kind == kind && c.Path == path {
+			return c
+		}
+	}
+	t.Fatalf("no %s change at %s in %+v", kind, path, r.Changes)
+	return Change{}
+}
+
+func TestDiffAvro(t *testing.T) {
+	oldSchema := `{"type":"record","name":"Order","namespace":"shop","fields":[
+		{"name":"id","type":"string"},
+		{"name":"qty","type":"int"},
+		{"name":"note","type":["null","string"],"default":null},
+		{"name":"legacy","type":"string"},
+		{"name":"customer","type":{"type":"record","name":"Customer","fields":[{"name":"name","type":"string"}]}},
+		{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID","VOID"]}},
+		{"name":"currency","type":"string","default":"EUR"}
+	]}`
+	newSchema := `{"type":"record","name":"Order","namespace":"shop","fields":[
+		{"name":"order_id","type":"string","aliases":["id"]},
+		{"name":"qty","type":"long"},
+		{"name":"customer","type":{"type":"record","name":"Customer","fields":[{"name":"name","type":"string"},{"name":"email","type":"string"}]}},
+		{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID","SHIPPED"],"default":"NEW"}},
+		{"name":"currency","type":"string","default":"USD"},
+		{"name":"channel","type":"string","default":"web"}
+	]}`
+	r, err := Diff("AVRO", oldSchema, newSchema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		kind              Kind
+		path              string
+		backward, forward bool
+	}{
+		{FieldRenamed, "Order.order_id", true, false},
+		{TypeChanged, "Order.qty", true, false},
+		{FieldRemoved, "Order.note", true, true},
+		{FieldRemoved, "Order.legacy", true, false},
+		{FieldAdded, "Order.customer.email", false, true},
+		{EnumSymbolAdded, "Order.status", true, false},
+		{EnumSymbolRemoved, "Order.status", true, true},
+		{DefaultChanged, "Order.currency", true, true},
+		{FieldAdded, "Order.channel", true, true},
+	}
+	for _, tt := range tests {
+		c := find(t, r, tt.kind, tt.path)
+		assert.Equal(t, tt.backward, c.Backward, "%s %s backward", tt.kind, tt.path)
+		assert.Equal(t, tt.forward, c.Forward, "%s %s forward", tt.kind, tt.path)
+	}
+	assert.Equal(t, "int", find(t, r, TypeChanged, "Order.qty").From)
+	assert.Equal(t, "NONE", r.Compatibility())
+}
+
+func TestDiffAvroUnionsAndRecursion(t *testing.T) {
+	oldSchema := `{"type":"record","name":"Node","fields":[
+		{"name":"value","type":"int"},
+		{"name":"next","type":["null","Node"],"default":null}
+	]}`
+	newSchema := `{"type":"record","name":"Node","fields":[
+		{"name":"value","type":["null","int","string"]},
+		{"name":"next","type":["null","Node"],"default":null}
+	]}`
+	r, err := Diff("", oldSchema, newSchema)
+	require.NoError(t, err)
+	require.Len(t, r.Changes, 1)
+	c := r.Changes[0]
+	assert.Equal(t, TypeChanged, c.Kind)
+	assert.Equal(t, "null|int|string", c.To)
+	assert.Equal(t, "BACKWARD", c.Compatibility())
+
+	same, err := Diff("AVRO", oldSchema, oldSchema)
+	require.NoError(t, err)
+	assert.Empty(t, same.Changes)
+	assert.Equal(t, "FULL", same.Compatibility())
+}
+
+func TestDiffAvroReferencedRecord(t *testing.T) {
+	// shop.Address is defined in a referenced subject, not in the text.
+	oldSchema := `{"type":"record","name":"Order","namespace":"shop","fields":[
+		{"name":"id","type":"string"},
+		{"name":"billing","type":"shop.Address"}
+	]}`
+	newSchema := `{"type":"record","name":"Order","namespace":"shop","fields":[
+		{"name":"id","type":"string"},
+		{"name":"billing","type":"shop.Address"},
+		{"name":"shipping","type":["null","Address"],"default":null},
+		{"name":"contact","type":"shop.Contact","default":{}}
+	]}`
+	r, err := Diff("AVRO", oldSchema, newSchema)
+	require.NoError(t, err)
+	require.Len(t, r.Changes, 2, "the unchanged reference compares equal: %+v", r.Changes)
+	assert.Equal(t, "null|Address", find(t, r, FieldAdded, "Order.shipping").To)
+	assert.Equal(t, "Contact", find(t, r, FieldAdded, "Order.contact").To)
+
+	retyped, err := Diff("AVRO", oldSchema, strings.Replace(oldSchema, `"shop.Address"`, `"shop.Location"`, 1))
+	require.NoError(t, err)
+	c := find(t, retyped, TypeChanged, "Order.billing")
+	assert.Equal(t, "Address", c.From)
+	assert.Equal(t, "Location", c.To)
+	assert.False(t, c.Backward)
+	assert.False(t, c.Forward)
+}
+
+func TestDiffProtobuf(t *testing.T) {
+	oldSchema := `syntax = "proto3";
+package shop;
+// An order.
+message Order {
+  string id = 1;
+  int32 qty = 2;
+  string note = 3;
+  Status status = 4;
+  message Item { string sku = 1; }
+  repeated Item items = 5;
+  double total = 6;
+}
+enum Status {
+  NEW = 0;
+  PAID = 1;
+  VOID = 2;
+}
+`
+	newSchema := `syntax = "proto3";
+package shop;
+message Order {
+  string order_id = 1;
+  int64 qty = 2;
+  Status status = 4;
+  message Item { string sku = 1; int32 count = 2; }
+  repeated Item items = 5;
+  string total = 6;
+  map<string, string> labels = 7;
+}
+enum Status {
+  NEW = 0;
+  PAYED = 1;
+  SHIPPED = 3;
+}
+`
+	r, err := Diff("PROTOBUF", oldSchema, newSchema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		kind              Kind
+		path              string
+		backward, forward bool
+	}{
+		{FieldRenamed, "Order.order_id", true, true},
+		{TypeChanged, "Order.qty", true, true},
+		{FieldRemoved, "Order.note", true, true},
+		{FieldAdded, "Order.Item.count", true, true},
+		{TypeChanged, "Order.total", false, false},
+		{FieldAdded, "Order.labels", true, true},
+		{EnumSymbolRenamed, "Status", true, true},
+		{EnumSymbolAdded, "Status", true, true},
+		{EnumSymbolRemoved, "Status", false, true},
+	}
+	for _, tt := range tests {
+		c := find(t, r, tt.kind, tt.path)
+		assert.Equal(t, tt.backward, c.Backward, "%s %s backward", tt.kind, tt.path)
+		assert.Equal(t, tt.forward, c.Forward, "%s %s forward", tt.kind, tt.path)
+	}
+	assert.Equal(t, "map<string, string>", find(t, r, FieldAdded, "Order.labels").To)
+	assert.Contains(t, find(t, r, FieldRemoved, "Order.note").Note, "reserve field number 3")
+}
+
+func TestDiffProtobufTypes(t *testing.T) {
+	oldSchema := `syntax = "proto3";
+package shop;
+message Order {
+  string id = 1;
+  Address ship = 2;
+  message Gift { string note = 1; }
+}
+message Address { string city = 1; }
+message Legacy { string x = 1; }
+enum Color { RED = 0; }
+enum Size { S = 0; }
+`
+	newSchema := `syntax = "proto3";
+package shop;
+message Order {
+  string id = 1;
+  .shop.Address ship = 2;
+  Size size = 3;
+}
+message Customer {
+  string name = 1;
+  message Tier { int32 level = 1; }
+}
+enum Channel { WEB = 0; }
+enum Size { S = 0; }
+`
+	r, err := Diff("PROTOBUF", oldSchema, newSchema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		kind              Kind
+		path              string
+		backward, forward bool
+	}{
+		{MessageAdded, "Customer", true, true},
+		{EnumAdded, "Channel", true, true},
+		{MessageRemoved, "Address", false, false},
+		{MessageRemoved, "Legacy", true, true},
+		{MessageRemoved, "Order.Gift", true, true},
+		{EnumRemoved, "Color", true, true},
+	}
+	for _, tt := range tests {
+		c := find(t, r, tt.kind, tt.path)
+		assert.Equal(t, tt.backward, c.Backward, "%s %s backward", tt.kind, tt.path)
+		assert.Equal(t, tt.forward, c.Forward, "%s %s forward", tt.kind, tt.path)
+	}
+	assert.Equal(t, "still used by Order.ship", find(t, r, MessageRemoved, "Address").Note)
+	for _, c := range r.Changes {
+		assert.NotEqual(t, "Customer.Tier", c.Path, "nested types of an added message are covered by it")
+	}
+	assert.Equal(t, "NONE", r.Compatibility())
+
+	t.Run("removing the first message breaks", func(t *testing.T) {
+		r, err := Diff("PROTOBUF", `message A { string a = 1; } message B { string b = 1; }`, `message B { string b = 1; }`)
+		require.NoError(t, err)
+		assert.Equal(t, "NONE", find(t, r, MessageRemoved, "A").Compatibility())
+	})
+}
+
+func TestDiffProtobufRequired(t *testing.T) {
+	oldSchema := `syntax = "proto2"; message M { optional string a = 1; required int32 b = 2 [default = 5]; }`
+	newSchema := `syntax = "proto2"; message M { required string a = 1; optional int32 b = 2 [default = 7]; required string c = 3; }`
+	r, err := Diff("PROTOBUF", oldSchema, newSchema)
+	require.NoError(t, err)
+	assert.Equal(t, "FORWARD", find(t, r, RequiredChanged, "M.a").Compatibility())
+	assert.Equal(t, "BACKWARD", find(t, r, RequiredChanged, "M.b").Compatibility())
+	assert.Equal(t, "7", find(t, r, DefaultChanged, "M.b").To)
+	assert.Equal(t, "FORWARD", find(t, r, FieldAdded, "M.c").Compatibility())
+}
+
+func TestDiffJSONSchema(t *testing.T) {
+	oldSchema := `{"type":"object","properties":{
+		"id":{"type":"string"},
+		"qty":{"type":"integer"},
+		"color":{"type":"string","enum":["red","green"]},
+		"note":{"type":"string"},
+		"tags":{"type":"array","items":{"type":"object","properties":{"k":{"type":"string"}}}}
+	},"required":["id","note"]}`
+	newSchema := `{"type":"object","properties":{
+		"id":{"type":"string"},
+		"qty":{"type":"number"},
+		"color":{"type":"string","enum":["red","blue"]},
+		"sku":{"type":"string"},
+		"tags":{"type":"array","items":{"type":"object","properties":{"k":{"type":"string"}},"required":["k"]}}
+	},"required":["id","sku"],"additionalProperties":false}`
+	r, err := Diff("JSON", oldSchema, newSchema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		kind              Kind
+		path              string
+		backward, forward bool
+	}{
+		{TypeChanged, "qty", true, false},
+		{EnumSymbolAdded, "color", true, false},
+		{EnumSymbolRemoved, "color", false, true},
+		{FieldAdded, "sku", false, true},
+		{FieldRemoved, "note", false, false},
+		{RequiredChanged, "tags[].k", false, true},
+	}
+	for _, tt := range tests {
+		c := find(t, r, tt.kind, tt.path)
+		assert.Equal(t, tt.backward, c.Backward, "%s %s backward", tt.kind, tt.path)
+		assert.Equal(t, tt.forward, c.Forward, "%s %s forward", tt.kind, tt.path)
+	}
+	assert.Equal(t, `"blue"`, find(t, r, EnumSymbolAdded, "color").To)
+}
+
+func TestDiffErrors(t *testing.T) {
+	_, err := Diff("XML", "a", "b")
+	var ute UnsupportedTypeError
+	assert.True(t, errors.As(err, &ute))
+
+	for _, schemaType := range []string{"AVRO", "PROTOBUF", "JSON"} {
+		_, err := Diff(schemaType, "{", `{"type":"string"}`)
+		var pe ParseError
+		if assert.True(t, errors.As(err, &pe), "%s: err = %v", schemaType, err) {
+			assert.Equal(t, "old", pe.Side)
+		}
+	}
+	_, err = Diff("AVRO", `"string"`, `{"type":"record","fields":[]}`)
+	var pe ParseError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, "new", pe.Side)
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"sort"
 	"strconv"
+
+	"github.com/Benny93/kafui/pkg/api"
 )
 
 // TopicCSVRow is one topic's exported row. MessageCount/Size are negative when
@@ -24,6 +26,29 @@ var TopicCSVHeader = []string{
 	"Name", "Partitions", "Replication Factor", "Message Count", "Out Of Sync", "Size", "Internal",
 }
 
+// CollectTopicCSVRows builds export rows for the named topics, fetching sizes
+// and per-topic details. It is a full fan-out, so call it only on an explicit
+// export/listing action. Topics whose details fail keep "N/A" placeholders.
+func CollectTopicCSVRows(ds api.KafkaDataSource, names []string) []TopicCSVRow {
+	sizes, _ := ds.GetTopicSizes(names)
+	rows := make([]TopicCSVRow, 0, len(names))
+	for _, name := range names {
+		row := TopicCSVRow{Name: name, MessageCount: -1, Size: -1}
+		if sz, ok := sizes[name]; ok {
+			row.Size = sz
+		}
+		if d, err := ds.GetTopicDetails(name); err == nil {
+			row.Partitions = int32(len(d.Partitions))
+			row.ReplicationFactor = d.ReplicationFactor
+			row.MessageCount = d.MessageCount()
+			row.OutOfSync = d.UnderReplicatedPartitions
+			row.Internal = d.IsInternal
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
 // WriteTopicCSV writes topics as CSV to w. Rows are ordered by name for
 // deterministic output. Unknown message counts/sizes (negative) render "N/A";
 // sizes are formatted human-readably via FormatBytes2dp.
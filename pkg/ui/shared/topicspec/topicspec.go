@@ -0,0 +1,375 @@
+// Package topicspec provides a declarative YAML topic specification and a
+// plan/apply reconciliation (creates, config changes, partition and
+// replication-factor changes) between the spec and the cluster.
+package topicspec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/ui/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the desired topic layout of a cluster.
+//
+//	prune: false            # also plan deletion of topics missing from the spec
+//	topics:
+//	  - name: orders
+//	    partitions: 6
+//	    replicationFactor: 3  # optional; 0/omitted = cluster default, never changed
+//	    configs:
+//	      retention.ms: "86400000"
+type Spec struct {
+	// Prune plans the deletion of every non-internal topic that the spec does
+	// not list. Deletions are unsafe and only applied with AllowUnsafe.
+	Prune  bool        `yaml:"prune,omitempty"`
+	Topics []TopicSpec `yaml:"topics"`
+}
+
+// TopicSpec is the desired state of one topic. Configs is the complete set of
+// topic-level overrides: overrides present on the cluster but missing here are
+// reset to their default.
+type TopicSpec struct {
+	Name              string            `yaml:"name"`
+	Partitions        int32             `yaml:"partitions"`
+	ReplicationFactor int16             `yaml:"replicationFactor,omitempty"`
+	Configs           map[string]string `yaml:"configs,omitempty"`
+}
+
+// SpecError describes an invalid topic spec. Topic is empty when the error
+// applies to the whole file.
+type SpecError struct {
+	Topic  string
+	Reason string
+}
+
+func (e SpecError) Error() string {
+	if e.Topic == "" {
+		return fmt.Sprintf("invalid topic spec: %s", e.Reason)
+	}
+	return fmt.Sprintf("invalid topic spec for %q: %s", e.Topic, e.Reason)
+}
+
+// Parse reads a YAML topic spec and validates it: unique non-empty names,
+// at least one partition, a non-negative replication factor and non-empty
+// config values. Unknown fields are rejected so typos do not silently become
+// no-ops.
+func Parse(data []byte) (Spec, error) {
+	var spec Spec
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return Spec{}, SpecError{Reason: err.Error()}
+	}
+	seen := make(map[string]bool, len(spec.Topics))
+	for _, t := range spec.Topics {
+		switch {
+		case strings.TrimSpace(t.Name) == "":
+			return Spec{}, SpecError{Reason: "topic without a name"}
+		case seen[t.Name]:
+			return Spec{}, SpecError{Topic: t.Name, Reason: "listed more than once"}
+		case t.Partitions < 1:
+			return Spec{}, SpecError{Topic: t.Name, Reason: "partitions must be at least 1"}
+		case t.ReplicationFactor < 0:
+			return Spec{}, SpecError{Topic: t.Name, Reason: "replicationFactor must not be negative"}
+		}
+		for k, v := range t.Configs {
+			if strings.TrimSpace(v) == "" {
+				return Spec{}, SpecError{Topic: t.Name, Reason: fmt.Sprintf("config %q has an empty value (omit it to use the default)", k)}
+			}
+		}
+		seen[t.Name] = true
+	}
+	return spec, nil
+}
+
+// ConfigChange sets (To != nil) or resets to default (To == nil) one topic
+// config entry. From is nil when the entry had no value.
+type ConfigChange struct {
+	Topic     string
+	Key       string
+	From      *string
+	To        *string
+	Sensitive bool
+}
+
+// PartitionChange moves a topic's partition count from From to To.
+type PartitionChange struct {
+	Topic    string
+	From, To int32
+}
+
+// ReplicationChange moves a topic's replication factor from From to To.
+type ReplicationChange struct {
+	Topic    string
+	From, To int16
+}
+
+// Plan is the difference between a Spec and the cluster. PartitionDecreases
+// and Deletes are unsafe: Kafka cannot shrink a topic in place, so a decrease
+// is applied by re-creating the topic, and both lose data.
+type Plan struct {
+	Creates            []TopicSpec
+	ConfigChanges      []ConfigChange
+	PartitionIncreases []PartitionChange
+	ReplicationChanges []ReplicationChange
+
+	PartitionDecreases []PartitionChange
+	Deletes            []string
+
+	// specs indexes the desired topics for re-creation on a decrease.
+	specs map[string]TopicSpec
+}
+
+// Empty reports whether the plan is a no-op (cluster already in sync).
+func (p Plan) Empty() bool {
+	return !p.HasSafeChanges() && !p.HasUnsafeChanges()
+}
+
+// HasSafeChanges reports whether the plan contains changes applied by default.
+func (p Plan) HasSafeChanges() bool {
+	return len(p.Creates) > 0 || len(p.ConfigChanges) > 0 || len(p.PartitionIncreases) > 0 || len(p.ReplicationChanges) > 0
+}
+
+// HasUnsafeChanges reports whether the plan contains changes that need
+// ApplyOptions.AllowUnsafe.
+func (p Plan) HasUnsafeChanges() bool {
+	return len(p.PartitionDecreases) > 0 || len(p.Deletes) > 0
+}
+
+// UnsafeErrors returns one typed error per unsafe change: PartitionDecreaseError
+// for a partition decrease and TopicDeletionRequiredError for a deletion.
+func (p Plan) UnsafeErrors() []error {
+	var errs []error
+	for _, c := range p.PartitionDecreases {
+		errs = append(errs, api.PartitionDecreaseError{TopicName: c.Topic, Current: c.From, Requested: c.To})
+	}
+	for _, name := range p.Deletes {
+		errs = append(errs, TopicDeletionRequiredError{TopicName: name})
+	}
+	return errs
+}
+
+// TopicDeletionRequiredError reports a topic that exists on the cluster but not
+// in a pruning spec.
+type TopicDeletionRequiredError struct {
+	TopicName string
+}
+
+func (e TopicDeletionRequiredError) Error() string {
+	return fmt.Sprintf("topic %q is not in the spec and would be deleted", e.TopicName)
+}
+
+// PlanTopics fetches the cluster's topics and the configs of the topics named in
+// spec, and computes the plan. It does not mutate the cluster — call Plan.Apply
+// for that.
+func PlanTopics(ds api.KafkaDataSource, spec Spec) (Plan, error) {
+	current, err := ds.GetTopics()
+	if err != nil {
+		return Plan{}, err
+	}
+	configs := make(map[string][]api.TopicConfigEntry)
+	for _, t := range spec.Topics {
+		if _, ok := current[t.Name]; !ok {
+			continue
+		}
+		entries, err := ds.GetTopicConfig(t.Name)
+		if err != nil {
+			return Plan{}, fmt.Errorf("config of topic %q: %w", t.Name, err)
+		}
+		configs[t.Name] = entries
+	}
+	return Diff(spec, current, configs), nil
+}
+
+// Diff is the pure comparison behind PlanTopics. configs holds the described
+// config entries of the existing topics named in spec.
+func Diff(spec Spec, current map[string]api.Topic, configs map[string][]api.TopicConfigEntry) Plan {
+	plan := Plan{specs: make(map[string]TopicSpec, len(spec.Topics))}
+	for _, want := range spec.Topics {
+		plan.specs[want.Name] = want
+		have, exists := current[want.Name]
+		if !exists {
+			plan.Creates = append(plan.Creates, want)
+			continue
+		}
+		switch {
+		case want.Partitions > have.NumPartitions:
+			plan.PartitionIncreases = append(plan.PartitionIncreases, PartitionChange{Topic: want.Name, From: have.NumPartitions, To: want.Partitions})
+		case want.Partitions < have.NumPartitions:
+			plan.PartitionDecreases = append(plan.PartitionDecreases, PartitionChange{Topic: want.Name, From: have.NumPartitions, To: want.Partitions})
+		}
+		if want.ReplicationFactor > 0 && want.ReplicationFactor != have.ReplicationFactor {
+			plan.ReplicationChanges = append(plan.ReplicationChanges, ReplicationChange{Topic: want.Name, From: have.ReplicationFactor, To: want.ReplicationFactor})
+		}
+		plan.ConfigChanges = append(plan.ConfigChanges, diffConfigs(want, configs[want.Name])...)
+	}
+	if spec.Prune {
+		for name := range current {
+			if _, wanted := plan.specs[name]; !wanted && !isInternal(name) {
+				plan.Deletes = append(plan.Deletes, name)
+			}
+		}
+		sort.Strings(plan.Deletes)
+	}
+	return plan
+}
+
+// diffConfigs sets every spec entry whose value differs from the cluster and
+// resets topic-level overrides the spec does not mention.
+func diffConfigs(want TopicSpec, entries []api.TopicConfigEntry) []ConfigChange {
+	byName := make(map[string]api.TopicConfigEntry, len(entries))
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+	keys := make([]string, 0, len(want.Configs))
+	for k := range want.Configs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var changes []ConfigChange
+	for _, k := range keys {
+		v := want.Configs[k]
+		e, ok := byName[k]
+		if ok && e.Value == v {
+			continue
+		}
+		c := ConfigChange{Topic: want.Name, Key: k, To: &v, Sensitive: e.Sensitive}
+		if ok {
+			from := e.Value
+			c.From = &from
+		}
+		changes = append(changes, c)
+	}
+	for _, e := range entries {
+		if _, wanted := want.Configs[e.Name]; wanted || e.Source != "Topic" || e.ReadOnly {
+			continue
+		}
+		from := e.Value
+		changes = append(changes, ConfigChange{Topic: want.Name, Key: e.Name, From: &from, Sensitive: e.Sensitive})
+	}
+	return changes
+}
+
+// isInternal reports Kafka-internal topics (__consumer_offsets,
+// __transaction_state, ...), which a prune never deletes.
+func isInternal(name string) bool {
+	return strings.HasPrefix(name, "__")
+}
+
+// ApplyOptions controls Plan.Apply.
+type ApplyOptions struct {
+	// AllowUnsafe applies partition decreases (by re-creating the topic) and
+	// deletions. Without it those changes are skipped and reported.
+	AllowUnsafe bool
+}
+
+// Apply executes the plan: creates, config changes, partition increases and
+// replication-factor changes, then — with AllowUnsafe — re-creations and
+// deletions. It stops at the first failing call. Skipped unsafe changes are
+// returned joined (see UnsafeErrors) after the safe changes were applied.
+func (p Plan) Apply(ds api.KafkaDataSource, opts ApplyOptions) error {
+	if p.Empty() {
+		shared.Log.Info("topic sync: already in sync, nothing to do")
+		return nil
+	}
+	shared.Log.Info("topic sync: applying plan",
+		"create", len(p.Creates), "config", len(p.ConfigChanges),
+		"partitions", len(p.PartitionIncreases), "replication", len(p.ReplicationChanges),
+		"unsafe", len(p.PartitionDecreases)+len(p.Deletes), "allowUnsafe", opts.AllowUnsafe)
+
+	for _, t := range p.Creates {
+		if err := createTopic(ds, t); err != nil {
+			return err
+		}
+	}
+	byTopic := make(map[string]map[string]*string)
+	var order []string
+	for _, c := range p.ConfigChanges {
+		if byTopic[c.Topic] == nil {
+			byTopic[c.Topic] = make(map[string]*string)
+			order = append(order, c.Topic)
+		}
+		byTopic[c.Topic][c.Key] = c.To
+	}
+	for _, topic := range order {
+		if err := ds.UpdateTopicConfig(topic, byTopic[topic]); err != nil {
+			return fmt.Errorf("update config of topic %q: %w", topic, err)
+		}
+	}
+	for _, c := range p.PartitionIncreases {
+		if err := ds.IncreasePartitions(c.Topic, c.To); err != nil {
+			return fmt.Errorf("increase partitions of topic %q: %w", c.Topic, err)
+		}
+	}
+	for _, c := range p.ReplicationChanges {
+		if err := ds.ChangeReplicationFactor(c.Topic, c.To); err != nil {
+			return fmt.Errorf("change replication factor of topic %q: %w", c.Topic, err)
+		}
+	}
+
+	if !opts.AllowUnsafe {
+		return errors.Join(p.UnsafeErrors()...)
+	}
+	for _, c := range p.PartitionDecreases {
+		if err := ds.DeleteTopic(c.Topic); err != nil {
+			return fmt.Errorf("delete topic %q for re-creation: %w", c.Topic, err)
+		}
+		if err := recreateTopic(ds, p.specs[c.Topic]); err != nil {
+			return err
+		}
+	}
+	for _, name := range p.Deletes {
+		if err := ds.DeleteTopic(name); err != nil {
+			return fmt.Errorf("delete topic %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// recreateRetries/Delay bound the create after a delete, which fails with
+// TopicAlreadyExistsError until the deletion has propagated (vars so tests can
+// shrink them). They match kafds' RecreateTopic.
+var (
+	recreateRetries = 20
+	recreateDelay   = 500 * time.Millisecond
+)
+
+// recreateTopic creates t after its previous instance was deleted, retrying
+// while the broker still reports the topic as existing.
+func recreateTopic(ds api.KafkaDataSource, t TopicSpec) error {
+	var lastErr error
+	for i := 0; i < recreateRetries; i++ {
+		lastErr = createTopic(ds, t)
+		var exists api.TopicAlreadyExistsError
+		if lastErr == nil || !errors.As(lastErr, &exists) {
+			return lastErr
+		}
+		time.Sleep(recreateDelay)
+	}
+	return api.RecreateTimeoutError{TopicName: t.Name, Cause: lastErr}
+}
+
+// createTopic creates t with its configs; an omitted replication factor
+// requests the cluster default.
+func createTopic(ds api.KafkaDataSource, t TopicSpec) error {
+	rf := t.ReplicationFactor
+	if rf == 0 {
+		rf = -1
+	}
+	configs := make(map[string]*string, len(t.Configs))
+	for k, v := range t.Configs {
+		v := v
+		configs[k] = &v
+	}
+	if err := ds.CreateTopic(t.Name, t.Partitions, rf, configs); err != nil {
+		return fmt.Errorf("create topic %q: %w", t.Name, err)
+	}
+	return nil
+}
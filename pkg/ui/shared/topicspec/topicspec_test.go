@@ -0,0 +1,212 @@
+package topicspec
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Benny93/kafui/pkg/api"
+	"github.com/Benny93/kafui/pkg/datasource/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strp(s string) *string { return &s }
+
+func TestParse(t *testing.T) {
+	spec, err := Parse([]byte(`
+prune: true
+topics:
+  - name: orders
+    partitions: 6
+    replicationFactor: 3
+    configs:
+      retention.ms: 86400000
+      cleanup.policy: compact
+  - name: audit
+    partitions: 1
+`))
+	require.NoError(t, err)
+	assert.True(t, spec.Prune)
+	require.Len(t, spec.Topics, 2)
+	assert.Equal(t, TopicSpec{Name: "orders", Partitions: 6, ReplicationFactor: 3,
+		Configs: map[string]string{"retention.ms": "86400000", "cleanup.policy": "compact"}}, spec.Topics[0])
+	assert.Equal(t, int16(0), spec.Topics[1].ReplicationFactor)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown field":  "topics:\n  - name: a\n    partitons: 1\n",
+		"missing name":   "topics:\n  - partitions: 1\n",
+		"duplicate":      "topics:\n  - {name: a, partitions: 1}\n  - {name: a, partitions: 2}\n",
+		"zero partition": "topics:\n  - {name: a, partitions: 0}\n",
+		"negative rf":    "topics:\n  - {name: a, partitions: 1, replicationFactor: -1}\n",
+		"empty config":   "topics:\n  - name: a\n    partitions: 1\n    configs: {retention.ms: \"\"}\n",
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(input))
+			var specErr SpecError
+			assert.True(t, errors.As(err, &specErr), "got %v", err)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	spec := Spec{Prune: true, Topics: []TopicSpec{
+		{Name: "new", Partitions: 3},
+		{Name: "grow", Partitions: 6, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "1000", "cleanup.policy": "delete"}},
+		{Name: "shrink", Partitions: 2},
+	}}
+	current := map[string]api.Topic{
+		"grow":               {NumPartitions: 3, ReplicationFactor: 2},
+		"shrink":             {NumPartitions: 4, ReplicationFactor: 1},
+		"unmanaged":          {NumPartitions: 1, ReplicationFactor: 1},
+		"__consumer_offsets": {NumPartitions: 50, ReplicationFactor: 3},
+	}
+	configs := map[string][]api.TopicConfigEntry{
+		"grow": {
+			{Name: "cleanup.policy", Value: "delete", Source: "Default config"},
+			{Name: "retention.ms", Value: "604800000", Source: "Default config"},
+			{Name: "segment.ms", Value: "10", Source: "Topic"},
+			{Name: "max.message.bytes", Value: "1048576", Source: "Default config"},
+		},
+	}
+
+	plan := Diff(spec, current, configs)
+	assert.Equal(t, []TopicSpec{spec.Topics[0]}, plan.Creates)
+	assert.Equal(t, []PartitionChange{{Topic: "grow", From: 3, To: 6}}, plan.PartitionIncreases)
+	assert.Equal(t, []ReplicationChange{{Topic: "grow", From: 2, To: 3}}, plan.ReplicationChanges)
+	assert.Equal(t, []ConfigChange{
+		{Topic: "grow", Key: "retention.ms", From: strp("604800000"), To: strp("1000")},
+		{Topic: "grow", Key: "segment.ms", From: strp("10")},
+	}, plan.ConfigChanges)
+	assert.Equal(t, []PartitionChange{{Topic: "shrink", From: 4, To: 2}}, plan.PartitionDecreases)
+	assert.Equal(t, []string{"unmanaged"}, plan.Deletes, "internal topics are never pruned")
+
+	spec.Prune = false
+	assert.Empty(t, Diff(spec, current, configs).Deletes)
+}
+
+// fakeTopicsDS keeps topics in memory and records admin calls.
+type fakeTopicsDS struct {
+	*mock.KafkaDataSourceMock
+	topics  map[string]api.Topic
+	calls   []string
+	updated map[string]map[string]*string
+	// deleting counts the creates of a deleted topic that still fail because
+	// its deletion has not propagated yet.
+	deleting int
+}
+
+func newFakeTopicsDS(topics map[string]api.Topic) *fakeTopicsDS {
+	return &fakeTopicsDS{KafkaDataSourceMock: &mock.KafkaDataSourceMock{}, topics: topics, updated: map[string]map[string]*string{}}
+}
+
+func (f *fakeTopicsDS) GetTopics() (map[string]api.Topic, error) { return f.topics, nil }
+func (f *fakeTopicsDS) GetTopicConfig(name string) ([]api.TopicConfigEntry, error) {
+	var out []api.TopicConfigEntry
+	for k, v := range f.topics[name].ConfigEntries {
+		out = append(out, api.TopicConfigEntry{Name: k, Value: *v, Source: "Topic"})
+	}
+	return out, nil
+}
+func (f *fakeTopicsDS) CreateTopic(name string, partitions int32, rf int16, configs map[string]*string) error {
+	f.calls = append(f.calls, "create "+name)
+	if f.deleting > 0 {
+		f.deleting--
+		return api.TopicAlreadyExistsError{TopicName: name}
+	}
+	f.topics[name] = api.Topic{NumPartitions: partitions, ReplicationFactor: rf, ConfigEntries: configs}
+	return nil
+}
+func (f *fakeTopicsDS) DeleteTopic(name string) error {
+	f.calls = append(f.calls, "delete "+name)
+	delete(f.topics, name)
+	return nil
+}
+func (f *fakeTopicsDS) UpdateTopicConfig(name string, entries map[string]*string) error {
+	f.calls = append(f.calls, "config "+name)
+	f.updated[name] = entries
+	return nil
+}
+func (f *fakeTopicsDS) IncreasePartitions(name string, total int32) error {
+	f.calls = append(f.calls, "partitions "+name)
+	return nil
+}
+func (f *fakeTopicsDS) ChangeReplicationFactor(name string, rf int16) error {
+	f.calls = append(f.calls, "replication "+name)
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	spec := Spec{Prune: true, Topics: []TopicSpec{
+		{Name: "new", Partitions: 3, Configs: map[string]string{"retention.ms": "5"}},
+		{Name: "grow", Partitions: 6, ReplicationFactor: 3},
+		{Name: "shrink", Partitions: 2},
+	}}
+	current := func() map[string]api.Topic {
+		return map[string]api.Topic{
+			"grow":      {NumPartitions: 3, ReplicationFactor: 2, ConfigEntries: map[string]*string{"segment.ms": strp("10")}},
+			"shrink":    {NumPartitions: 4, ReplicationFactor: 1},
+			"unmanaged": {NumPartitions: 1},
+		}
+	}
+
+	t.Run("safe only", func(t *testing.T) {
+		ds := newFakeTopicsDS(current())
+		plan, err := PlanTopics(ds, spec)
+		require.NoError(t, err)
+		err = plan.Apply(ds, ApplyOptions{})
+
+		var decrease api.PartitionDecreaseError
+		require.True(t, errors.As(err, &decrease), "got %v", err)
+		assert.Equal(t, api.PartitionDecreaseError{TopicName: "shrink", Current: 4, Requested: 2}, decrease)
+		var deletion TopicDeletionRequiredError
+		require.True(t, errors.As(err, &deletion))
+		assert.Equal(t, "unmanaged", deletion.TopicName)
+
+		assert.Equal(t, []string{"create new", "config grow", "partitions grow", "replication grow"}, ds.calls)
+		assert.Equal(t, map[string]*string{"segment.ms": nil}, ds.updated["grow"])
+		assert.Equal(t, int16(-1), ds.topics["new"].ReplicationFactor, "omitted replication factor requests the cluster default")
+		assert.Equal(t, "5", *ds.topics["new"].ConfigEntries["retention.ms"])
+	})
+
+	t.Run("allow unsafe", func(t *testing.T) {
+		ds := newFakeTopicsDS(current())
+		plan, err := PlanTopics(ds, spec)
+		require.NoError(t, err)
+		require.NoError(t, plan.Apply(ds, ApplyOptions{AllowUnsafe: true}))
+		assert.Equal(t, []string{"create new", "config grow", "partitions grow", "replication grow",
+			"delete shrink", "create shrink", "delete unmanaged"}, ds.calls)
+		assert.Equal(t, int32(2), ds.topics["shrink"].NumPartitions)
+	})
+
+	t.Run("re-creation waits for the deletion", func(t *testing.T) {
+		recreateDelay = time.Millisecond
+		defer func() { recreateDelay = 500 * time.Millisecond }()
+		ds := newFakeTopicsDS(current())
+		plan, err := PlanTopics(ds, Spec{Topics: []TopicSpec{{Name: "shrink", Partitions: 2}}})
+		require.NoError(t, err)
+		ds.deleting = 2
+		require.NoError(t, plan.Apply(ds, ApplyOptions{AllowUnsafe: true}))
+		assert.Equal(t, []string{"delete shrink", "create shrink", "create shrink", "create shrink"}, ds.calls)
+		assert.Equal(t, int32(2), ds.topics["shrink"].NumPartitions)
+
+		ds = newFakeTopicsDS(current())
+		ds.deleting = recreateRetries
+		err = plan.Apply(ds, ApplyOptions{AllowUnsafe: true})
+		var timeout api.RecreateTimeoutError
+		require.True(t, errors.As(err, &timeout), "got %v", err)
+		assert.Equal(t, "shrink", timeout.TopicName)
+	})
+
+	t.Run("in sync", func(t *testing.T) {
+		ds := newFakeTopicsDS(map[string]api.Topic{"a": {NumPartitions: 1, ReplicationFactor: 1}})
+		plan, err := PlanTopics(ds, Spec{Topics: []TopicSpec{{Name: "a", Partitions: 1}}})
+		require.NoError(t, err)
+		assert.True(t, plan.Empty())
+		require.NoError(t, plan.Apply(ds, ApplyOptions{}))
+		assert.Empty(t, ds.calls)
+	})
+}